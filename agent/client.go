@@ -28,6 +28,7 @@ import (
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/internal/buildinfo"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/logger"
 	"github.com/superfly/flyctl/internal/sentry"
@@ -47,13 +48,19 @@ func Establish(ctx context.Context, apiClient *api.Client) (*Client, error) {
 		return StartDaemon(ctx)
 	}
 
-	if buildinfo.Version().EQ(res.Version) {
+	var msg, stopMessage string
+	switch name, ok := sessionContext(ctx); {
+	case !buildinfo.Version().EQ(res.Version):
+		// TOOD: log this instead
+		msg = fmt.Sprintf("The running flyctl agent (v%s) is older than the current flyctl (v%s).", res.Version, buildinfo.Version())
+		stopMessage = "The out-of-date agent will be shut down along with existing wireguard connections. The new agent will start automatically as needed."
+	case ok && name != res.Context:
+		msg = fmt.Sprintf("The running flyctl agent uses the credentials of %s, rather than those of %s.", contextDescription(res.Context), contextDescription(name))
+		stopMessage = "The agent will be shut down along with existing wireguard connections, and started again with the credentials in use."
+	default:
 		return c, nil
 	}
 
-	// TOOD: log this instead
-	msg := fmt.Sprintf("The running flyctl agent (v%s) is older than the current flyctl (v%s).", res.Version, buildinfo.Version())
-
 	logger := logger.MaybeFromContext(ctx)
 	if logger != nil {
 		logger.Warn(msg)
@@ -65,7 +72,6 @@ func Establish(ctx context.Context, apiClient *api.Client) (*Client, error) {
 		return c, nil
 	}
 
-	const stopMessage = "The out-of-date agent will be shut down along with existing wireguard connections. The new agent will start automatically as needed."
 	if logger != nil {
 		logger.Warn(stopMessage)
 	} else {
@@ -90,6 +96,25 @@ func Establish(ctx context.Context, apiClient *api.Client) (*Client, error) {
 	return StartDaemon(ctx)
 }
 
+// sessionContext returns the name of the config context the credentials of
+// the command come from, empty when they come from outside of contexts, and
+// whether it's known.
+func sessionContext(ctx context.Context) (string, bool) {
+	if cfg := config.MaybeFromContext(ctx); cfg != nil {
+		return cfg.Context, true
+	}
+
+	return "", false
+}
+
+func contextDescription(name string) string {
+	if name == "" {
+		return "no context"
+	}
+
+	return fmt.Sprintf("context %s", name)
+}
+
 func newClient(network, addr string) *Client {
 	return &Client{
 		network: network,
@@ -178,6 +203,7 @@ type PingResponse struct {
 	PID        int
 	Version    semver.Version
 	Background bool
+	Context    string `json:",omitempty"`
 }
 
 type errInvalidResponse []byte
@@ -209,6 +235,47 @@ func (c *Client) Ping(ctx context.Context) (res PingResponse, err error) {
 	return
 }
 
+// TunnelStatus is the state of the tunnel of an organization.
+type TunnelStatus struct {
+	Org           string
+	Endpoint      string
+	DNS           net.IP
+	LastHandshake time.Time
+	RxBytes       uint64
+	TxBytes       uint64
+}
+
+type StatusResponse struct {
+	Tunnels []TunnelStatus
+}
+
+// Status returns the state of the tunnels the agent maintains.
+func (c *Client) Status(ctx context.Context) (res StatusResponse, err error) {
+	err = c.do(ctx, func(conn net.Conn) (err error) {
+		if err = proto.Write(conn, "status"); err != nil {
+			return
+		}
+
+		var data []byte
+		if data, err = proto.Read(conn); err != nil {
+			return
+		}
+
+		switch {
+		default:
+			err = errInvalidResponse(data)
+		case isOK(data):
+			err = unmarshal(&res, data)
+		case isError(data):
+			err = extractError(data)
+		}
+
+		return
+	})
+
+	return
+}
+
 const okPrefix = "ok "
 
 func isOK(data []byte) bool {
@@ -622,8 +689,13 @@ func (d *dialer) DialContext(ctx context.Context, network, addr string) (conn ne
 		}
 	}()
 
+	command := "connect"
+	if strings.HasPrefix(network, "udp") {
+		command = "connect-udp"
+	}
+
 	timeout := strconv.FormatInt(int64(d.timeout), 10)
-	if err = proto.Write(conn, "connect", d.slug, addr, timeout); err != nil {
+	if err = proto.Write(conn, command, d.slug, addr, timeout); err != nil {
 		return
 	}
 
@@ -636,6 +708,9 @@ func (d *dialer) DialContext(ctx context.Context, network, addr string) (conn ne
 	default:
 		err = errInvalidResponse(data)
 	case string(data) == "ok":
+		if command == "connect-udp" {
+			conn = NewDatagramConn(conn)
+		}
 	case isError(data):
 		err = extractError(data)
 	}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+)
+
+// datagramConn carries datagrams over a stream connection to the agent. Each
+// datagram is framed by its length as an NBO u16, so that every Read returns
+// exactly one datagram and every Write sends exactly one.
+type datagramConn struct {
+	net.Conn
+}
+
+// NewDatagramConn wraps an agent connection which carries datagrams, such as
+// one upgraded by the "connect-udp" command.
+func NewDatagramConn(conn net.Conn) net.Conn {
+	return &datagramConn{Conn: conn}
+}
+
+func (c *datagramConn) Read(p []byte) (int, error) {
+	var lbuf [2]byte
+	if _, err := io.ReadFull(c.Conn, lbuf[:]); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, binary.BigEndian.Uint16(lbuf[:]))
+	if _, err := io.ReadFull(c.Conn, buf); err != nil {
+		return 0, err
+	}
+
+	if len(buf) > len(p) {
+		return 0, io.ErrShortBuffer
+	}
+
+	return copy(p, buf), nil
+}
+
+func (c *datagramConn) Write(p []byte) (int, error) {
+	if len(p) > 0xffff {
+		return 0, fmt.Errorf("datagram of %d bytes is too large", len(p))
+	}
+
+	buf := make([]byte, 2+len(p))
+	binary.BigEndian.PutUint16(buf, uint16(len(p)))
+	copy(buf[2:], p)
+
+	if _, err := c.Conn.Write(buf); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
@@ -29,9 +29,16 @@ import (
 type Options struct {
 	Socket     string
 	Logger     *log.Logger
-	Client     *api.Client
 	Background bool
 	ConfigFile string
+
+	// Context is the name of the config context the credentials of the agent
+	// come from, if any.
+	Context string
+
+	// Client returns the API client of the session the agent runs in, with
+	// its access token refreshed when it's about to expire.
+	Client func(context.Context) (*api.Client, error)
 }
 
 func Run(ctx context.Context, opt Options) (err error) {
@@ -204,7 +211,7 @@ func (s *server) checkForConfigChange() (err error) {
 	return
 }
 
-func (s *server) buildTunnel(org *api.Organization, recycle bool) (tunnel *wg.Tunnel, err error) {
+func (s *server) buildTunnel(ctx context.Context, org *api.Organization, recycle bool) (tunnel *wg.Tunnel, err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -213,8 +220,13 @@ func (s *server) buildTunnel(org *api.Organization, recycle bool) (tunnel *wg.Tu
 		return
 	}
 
+	var client *api.Client
+	if client, err = s.Client(ctx); err != nil {
+		return
+	}
+
 	var state *wg.WireGuardState
-	if state, err = wireguard.StateForOrg(s.Client, org, "", "", recycle); err != nil {
+	if state, err = wireguard.StateForOrg(client, org, "", "", recycle); err != nil {
 		return
 	}
 
@@ -285,6 +297,20 @@ func (s *server) tunnelFor(slug string) *wg.Tunnel {
 	return s.tunnels[slug]
 }
 
+// currentTunnels returns a copy of the tunnels the server maintains, keyed by
+// organization slug.
+func (s *server) currentTunnels() map[string]*wg.Tunnel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tunnels := make(map[string]*wg.Tunnel, len(s.tunnels))
+	for slug, tunnel := range s.tunnels {
+		tunnels[slug] = tunnel
+	}
+
+	return tunnels
+}
+
 func (s *server) probeTunnel(ctx context.Context, slug string) (err error) {
 	tunnel := s.tunnelFor(slug)
 	if tunnel == nil {
@@ -346,7 +372,9 @@ func (s *server) clean(ctx context.Context) {
 			break
 		}
 
-		if err := wireguard.PruneInvalidPeers(ctx, s.Client); err != nil {
+		if client, err := s.Client(ctx); err != nil {
+			s.printf("failed refreshing the session: %v", err)
+		} else if err := wireguard.PruneInvalidPeers(ctx, client); err != nil {
 			s.printf("failed pruning invalid peers: %v", err)
 		}
 
@@ -12,6 +12,7 @@ import (
 	"net"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -110,9 +111,11 @@ type handlerFunc func(*session, context.Context, ...string)
 var handlers = map[string]handlerFunc{
 	"kill":        (*session).kill,
 	"ping":        (*session).ping,
+	"status":      (*session).status,
 	"establish":   (*session).establish,
 	"reestablish": (*session).reestablish,
 	"connect":     (*session).connect,
+	"connect-udp": (*session).connectUDP,
 	"probe":       (*session).probe,
 	"instances":   (*session).instances,
 	"resolve":     (*session).resolve,
@@ -142,9 +145,44 @@ func (s *session) ping(_ context.Context, args ...string) {
 		Version:    buildinfo.Version(),
 		PID:        os.Getpid(),
 		Background: s.srv.Options.Background,
+		Context:    s.srv.Options.Context,
 	})
 }
 
+var errMalformedStatus = errors.New("malformed status command")
+
+func (s *session) status(_ context.Context, args ...string) {
+	if !s.noArgs(args, errMalformedStatus) {
+		return
+	}
+
+	var res agent.StatusResponse
+
+	for slug, tunnel := range s.srv.currentTunnels() {
+		stats, err := tunnel.Stats()
+		if err != nil {
+			s.logger.Printf("failed reading stats of %q tunnel: %v", slug, err)
+
+			continue
+		}
+
+		res.Tunnels = append(res.Tunnels, agent.TunnelStatus{
+			Org:           slug,
+			Endpoint:      stats.Endpoint,
+			DNS:           tunnel.Config.DNS,
+			LastHandshake: stats.LastHandshake,
+			RxBytes:       stats.RxBytes,
+			TxBytes:       stats.TxBytes,
+		})
+	}
+
+	sort.Slice(res.Tunnels, func(i, j int) bool {
+		return res.Tunnels[i].Org < res.Tunnels[j].Org
+	})
+
+	_ = s.marshal(res)
+}
+
 var errMalformedEstablish = errors.New("malformed establish command")
 
 func (s *session) doEstablish(ctx context.Context, recycle bool, args ...string) {
@@ -159,7 +197,7 @@ func (s *session) doEstablish(ctx context.Context, recycle bool, args ...string)
 		return
 	}
 
-	tunnel, err := s.srv.buildTunnel(org, recycle)
+	tunnel, err := s.srv.buildTunnel(ctx, org, recycle)
 	if err != nil {
 		s.error(err)
 
@@ -183,7 +221,12 @@ func (s *session) reestablish(ctx context.Context, args ...string) {
 var errNoSuchOrg = errors.New("no such organization")
 
 func (s *session) fetchOrg(ctx context.Context, slug string) (*api.Organization, error) {
-	orgs, err := s.srv.Client.GetOrganizations(ctx)
+	client, err := s.srv.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orgs, err := client.GetOrganizations(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -313,6 +356,16 @@ var (
 )
 
 func (s *session) connect(ctx context.Context, args ...string) {
+	s.proxy(ctx, "tcp", args...)
+}
+
+// connectUDP is like connect, but relays UDP datagrams framed as by
+// agent.NewDatagramConn.
+func (s *session) connectUDP(ctx context.Context, args ...string) {
+	s.proxy(ctx, "udp", args...)
+}
+
+func (s *session) proxy(ctx context.Context, network string, args ...string) {
 	if !s.exactArgs(3, args, errMalformedConnect) {
 		return
 	}
@@ -340,7 +393,7 @@ func (s *session) connect(ctx context.Context, args ...string) {
 	}
 	defer cancel()
 
-	outconn, err := tunnel.DialContext(dialContext, "tcp", args[1])
+	outconn, err := tunnel.DialContext(dialContext, network, args[1])
 	if err != nil {
 		s.error(err)
 
@@ -356,6 +409,11 @@ func (s *session) connect(ctx context.Context, args ...string) {
 		return
 	}
 
+	conn := s.conn
+	if network == "udp" {
+		conn = agent.NewDatagramConn(s.conn)
+	}
+
 	var eg *errgroup.Group
 	eg, ctx = errgroup.WithContext(ctx)
 
@@ -368,7 +426,7 @@ func (s *session) connect(ctx context.Context, args ...string) {
 	})
 
 	eg.Go(func() (err error) {
-		if _, err = io.Copy(s.conn, outconn); err == nil {
+		if _, err = io.Copy(conn, outconn); err == nil {
 			err = io.EOF
 		}
 
@@ -376,7 +434,7 @@ func (s *session) connect(ctx context.Context, args ...string) {
 	})
 
 	eg.Go(func() (err error) {
-		if _, err = io.Copy(outconn, s.conn); err == nil {
+		if _, err = io.Copy(outconn, conn); err == nil {
 			err = io.EOF
 		}
 
@@ -36,6 +36,11 @@ func StartDaemon(ctx context.Context) (*Client, error) {
 
 	cmd := exec.Command(os.Args[0], "agent", "run", logFile)
 	cmd.Env = append(os.Environ(), "FLY_NO_UPDATE_CHECK=1")
+	if name, _ := sessionContext(ctx); name != "" {
+		// the agent uses the credentials of the context in use, even when it
+		// was chosen by flag or isn't the one pinned to where it runs
+		cmd.Env = append(cmd.Env, "FLY_CONTEXT="+name)
+	}
 	setSysProcAttributes(cmd)
 
 	if err := cmd.Start(); err != nil {
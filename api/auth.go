@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 )
 
 // CLISessionAuth holds access information
@@ -13,16 +14,42 @@ type CLISessionAuth struct {
 	ID          string `json:"id"`
 	AuthURL     string `json:"auth_url"`
 	AccessToken string `json:"access_token"`
+
+	// UserCode and VerificationURL are set for device code sessions, which
+	// are authorized by entering the code at the URL on another device.
+	UserCode        string `json:"user_code,omitempty"`
+	VerificationURL string `json:"verification_url,omitempty"`
+
+	// RefreshToken and ExpiresAt are set for the short-lived access tokens
+	// of SSO sessions.
+	RefreshToken string     `json:"refresh_token,omitempty"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"`
 }
 
 // StartCLISessionWebAuth starts a session with the platform via web auth
 func StartCLISessionWebAuth(machineName string, signup bool) (CLISessionAuth, error) {
-	var result CLISessionAuth
-
-	postData, _ := json.Marshal(map[string]interface{}{
+	return startCLISession(map[string]interface{}{
 		"name":   machineName,
 		"signup": signup,
 	})
+}
+
+// StartCLISessionSSO starts a session with the platform via the SSO provider
+// of the organization with the given slug. With deviceCode, the session is
+// authorized by entering its user code on another device rather than by
+// opening its auth URL.
+func StartCLISessionSSO(machineName, orgSlug string, deviceCode bool) (CLISessionAuth, error) {
+	return startCLISession(map[string]interface{}{
+		"name":             machineName,
+		"sso_organization": orgSlug,
+		"device_code":      deviceCode,
+	})
+}
+
+func startCLISession(params map[string]interface{}) (CLISessionAuth, error) {
+	var result CLISessionAuth
+
+	postData, _ := json.Marshal(params)
 
 	url := fmt.Sprintf("%s/api/v1/cli_sessions", baseURL)
 
@@ -44,6 +71,13 @@ func StartCLISessionWebAuth(machineName string, signup bool) (CLISessionAuth, er
 
 // GetAccessTokenForCLISession Obtains the access token for the session
 func GetAccessTokenForCLISession(ctx context.Context, id string) (token string, err error) {
+	auth, err := GetCLISession(ctx, id)
+
+	return auth.AccessToken, err
+}
+
+// GetCLISession obtains the tokens of an authorized session.
+func GetCLISession(ctx context.Context, id string) (auth CLISessionAuth, err error) {
 	url := fmt.Sprintf("%s/api/v1/cli_sessions/%s", baseURL, id)
 
 	var req *http.Request
@@ -63,12 +97,90 @@ func GetAccessTokenForCLISession(ctx context.Context, id string) (token string,
 	case http.StatusNotFound:
 		err = ErrNotFound
 	case http.StatusOK:
-		var auth CLISessionAuth
+		err = json.NewDecoder(res.Body).Decode(&auth)
+	}
+
+	return
+}
+
+// RefreshCLISession exchanges the refresh token of an SSO session for a new
+// access token, and the refresh token that replaces the one given.
+func RefreshCLISession(ctx context.Context, refreshToken string) (auth CLISessionAuth, err error) {
+	postData, _ := json.Marshal(map[string]string{
+		"refresh_token": refreshToken,
+	})
+
+	url := fmt.Sprintf("%s/api/v1/cli_sessions/refresh", baseURL)
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(postData)); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var res *http.Response
+	if res, err = http.DefaultClient.Do(req); err != nil {
+		return
+	}
+	defer res.Body.Close()
 
-		if err = json.NewDecoder(res.Body).Decode(&auth); err == nil {
-			token = auth.AccessToken
-		}
+	if res.StatusCode != http.StatusOK {
+		err = ErrorFromResp(res)
+
+		return
 	}
 
+	err = json.NewDecoder(res.Body).Decode(&auth)
+
+	return
+}
+
+// OIDCExchange is an OIDC identity token, like those CI providers issue jobs,
+// to exchange for a Fly token, optionally narrowed down to an organization or
+// an app when the trust rules matching it allow several.
+type OIDCExchange struct {
+	Token        string `json:"token"`
+	Organization string `json:"organization,omitempty"`
+	App          string `json:"app,omitempty"`
+}
+
+// OIDCExchangeResult is the short-lived scoped token an OIDC identity token
+// was exchanged for.
+type OIDCExchangeResult struct {
+	AccessToken  string    `json:"access_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Scope        string    `json:"scope"`
+	Organization string    `json:"organization"`
+	App          string    `json:"app,omitempty"`
+	Rule         string    `json:"rule"`
+}
+
+// ExchangeOIDCToken exchanges an OIDC identity token for a short-lived token
+// scoped by the trust rule of its organization that it matches.
+func ExchangeOIDCToken(ctx context.Context, exchange OIDCExchange) (result OIDCExchangeResult, err error) {
+	postData, _ := json.Marshal(exchange)
+
+	url := fmt.Sprintf("%s/api/v1/oidc/exchange", baseURL)
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(postData)); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var res *http.Response
+	if res, err = http.DefaultClient.Do(req); err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err = ErrorFromResp(res)
+
+		return
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&result)
+
 	return
 }
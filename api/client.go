@@ -84,6 +84,15 @@ func (c *Client) RunWithContext(ctx context.Context, req *graphql.Request) (Quer
 	return resp, err
 }
 
+// Do sends req, which is meant for a service other than the API and so
+// carries no credentials, with the client's user agent. It's sent over the
+// client's HTTP client, so it's retried and logged like API requests.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("User-Agent", c.userAgent)
+
+	return c.httpClient.Do(req)
+}
+
 var compactPattern = regexp.MustCompile(`\s+`)
 
 func compactQueryString(q string) string {
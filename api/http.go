@@ -6,22 +6,55 @@ import (
 	"io/ioutil"
 	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/PuerkitoBio/rehttp"
 )
 
+// DefaultMaxRetries is how many times requests are retried unless
+// SetMaxRetries says otherwise.
+const DefaultMaxRetries = 3
+
+var maxRetries = DefaultMaxRetries
+
+// SetMaxRetries sets how many times the requests of HTTP clients, including
+// those created before, are retried after transient failures.
+func SetMaxRetries(n int) {
+	maxRetries = n
+}
+
+const (
+	// minRetryDelay and maxRetryDelay bound the exponential backoff between
+	// retries when the response says nothing about when to retry.
+	minRetryDelay = 100 * time.Millisecond
+	maxRetryDelay = 10 * time.Second
+
+	// maxRetryAfter caps how long a Retry-After header may make us wait.
+	maxRetryAfter = time.Minute
+)
+
 func NewHTTPClient(logger Logger, transport http.RoundTripper) (*http.Client, error) {
 	retryTransport := rehttp.NewTransport(
 		transport,
 		rehttp.RetryAll(
-			rehttp.RetryMaxRetries(3),
+			func(attempt rehttp.Attempt) bool {
+				return attempt.Index < maxRetries
+			},
 			rehttp.RetryAny(
 				rehttp.RetryTemporaryErr(),
-				rehttp.RetryStatuses(502, 503),
+				rehttp.RetryStatuses(http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable),
+				// the request may have had effects before failing with other
+				// server errors, or timing out, so only requests without any
+				// are retried after them
+				rehttp.RetryAll(
+					rehttp.RetryStatuses(http.StatusInternalServerError, http.StatusGatewayTimeout),
+					rehttp.RetryHTTPMethods(http.MethodGet, http.MethodHead),
+				),
 			),
 		),
-		rehttp.ExpJitterDelay(100*time.Millisecond, 1*time.Second),
+		retryDelay(logger, rehttp.ExpJitterDelay(minRetryDelay, maxRetryDelay)),
 	)
 
 	loggingTransport := &LoggingTransport{
@@ -36,6 +69,57 @@ func NewHTTPClient(logger Logger, transport http.RoundTripper) (*http.Client, er
 	return httpClient, nil
 }
 
+// retryDelay returns the DelayFn which waits for as long as the Retry-After
+// header of responses asks, when they have one, or for backoff otherwise.
+func retryDelay(logger Logger, backoff rehttp.DelayFn) rehttp.DelayFn {
+	return func(attempt rehttp.Attempt) time.Duration {
+		delay, ok := retryAfter(attempt.Response, time.Now())
+		if !ok {
+			delay = backoff(attempt)
+		}
+
+		status := "error"
+		if attempt.Response != nil {
+			status = attempt.Response.Status
+		}
+		logger.Debugf("%s %s failed (%s), retrying in %s (attempt %d of %d)\n",
+			attempt.Request.Method, attempt.Request.URL, status, delay, attempt.Index+1, maxRetries)
+
+		return delay
+	}
+}
+
+// retryAfter returns how long the Retry-After header of res asks to wait
+// before retrying, as of now, capped at maxRetryAfter.
+func retryAfter(res *http.Response, now time.Time) (time.Duration, bool) {
+	if res == nil {
+		return 0, false
+	}
+
+	header := strings.TrimSpace(res.Header.Get("Retry-After"))
+	if header == "" {
+		return 0, false
+	}
+
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(header); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(header); err == nil {
+		delay = at.Sub(now)
+	} else {
+		return 0, false
+	}
+
+	switch {
+	case delay < 0:
+		delay = 0
+	case delay > maxRetryAfter:
+		delay = maxRetryAfter
+	}
+
+	return delay, true
+}
+
 type LoggingTransport struct {
 	InnerTransport http.RoundTripper
 	Logger         Logger
@@ -89,7 +173,6 @@ func (t *LoggingTransport) logRequest(req *http.Request) {
 
 func (t *LoggingTransport) logResponse(resp *http.Response) {
 	ctx := resp.Request.Context()
-	defer resp.Body.Close()
 
 	if start, ok := ctx.Value(contextKeyRequestStart).(time.Time); ok {
 		t.Logger.Debugf("<-- %d %s (%s)\n", resp.StatusCode, resp.Request.URL, shiftedDuration(time.Since(start), 2))
@@ -97,6 +180,14 @@ func (t *LoggingTransport) logResponse(resp *http.Response) {
 		t.Logger.Debugf("<-- %d %s %s\n", resp.StatusCode, resp.Request.URL)
 	}
 
+	// binary bodies, like snapshot contents, are streamed rather than read
+	// into memory for logging
+	if resp.Header.Get("Content-Type") == "application/octet-stream" {
+		return
+	}
+
+	defer resp.Body.Close()
+
 	data, err := ioutil.ReadAll(resp.Body)
 
 	if err != nil {
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name   string
+		header string
+		delay  time.Duration
+		ok     bool
+	}{
+		{"missing", "", 0, false},
+		{"seconds", "5", 5 * time.Second, true},
+		{"padded seconds", " 5 ", 5 * time.Second, true},
+		{"date", now.Add(30 * time.Second).Format(http.TimeFormat), 30 * time.Second, true},
+		{"past date", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"negative seconds", "-3", 0, true},
+		{"capped seconds", "3600", maxRetryAfter, true},
+		{"capped date", now.Add(time.Hour).Format(http.TimeFormat), maxRetryAfter, true},
+		{"garbage", "soon", 0, false},
+	}
+
+	for _, c := range cases {
+		res := &http.Response{Header: http.Header{}}
+		if c.header != "" {
+			res.Header.Set("Retry-After", c.header)
+		}
+
+		delay, ok := retryAfter(res, now)
+		if delay != c.delay || ok != c.ok {
+			t.Errorf("%s: retryAfter(%q) = %s, %t; want %s, %t", c.name, c.header, delay, ok, c.delay, c.ok)
+		}
+	}
+
+	if _, ok := retryAfter(nil, now); ok {
+		t.Error("retryAfter(nil) has a delay")
+	}
+}
@@ -108,6 +108,10 @@ type RemoveMachineInput struct {
 	Kill bool `json:"kill"`
 }
 
+// MachineProcessGroupKey is the metadata key of the process group a machine
+// belongs to.
+const MachineProcessGroupKey = "process_group"
+
 type MachineRestartPolicy string
 
 var (
@@ -133,6 +137,7 @@ type MachineGuest struct {
 	CPUKind  string `json:"cpu_kind"`
 	CPUs     int    `json:"cpus"`
 	MemoryMB int    `json:"memory_mb"`
+	GPUKind  string `json:"gpu_kind,omitempty"`
 
 	KernelArgs []string `json:"kernel_args,omitempty"`
 }
@@ -151,6 +156,11 @@ var MachinePresets map[string]*MachineGuest = map[string]*MachineGuest{
 	"dedicated-cpu-2x": {CPUKind: "dedicated", CPUs: 2, MemoryMB: 2 * MEMORY_MB_PER_CPU},
 	"dedicated-cpu-4x": {CPUKind: "dedicated", CPUs: 4, MemoryMB: 4 * MEMORY_MB_PER_CPU},
 	"dedicated-cpu-8x": {CPUKind: "dedicated", CPUs: 8, MemoryMB: 8 * MEMORY_MB_PER_CPU},
+	"performance-1x":   {CPUKind: "performance", CPUs: 1, MemoryMB: 1 * MEMORY_MB_PER_CPU},
+	"performance-2x":   {CPUKind: "performance", CPUs: 2, MemoryMB: 2 * MEMORY_MB_PER_CPU},
+	"performance-4x":   {CPUKind: "performance", CPUs: 4, MemoryMB: 4 * MEMORY_MB_PER_CPU},
+	"performance-8x":   {CPUKind: "performance", CPUs: 8, MemoryMB: 8 * MEMORY_MB_PER_CPU},
+	"performance-16x":  {CPUKind: "performance", CPUs: 16, MemoryMB: 16 * MEMORY_MB_PER_CPU},
 }
 
 type MachineMetrics struct {
@@ -185,6 +195,12 @@ type MachineService struct {
 	InternalPort int                        `json:"internal_port" toml:"internal_port"`
 	Ports        []MachinePort              `json:"ports" toml:"ports"`
 	Concurrency  *MachineServiceConcurrency `json:"concurrency,omitempty" toml:"concurrency"`
+
+	// Autostop and Autostart let the proxy stop machines which are idle and
+	// start them again on requests, keeping MinMachinesRunning of them running.
+	Autostop           *bool `json:"autostop,omitempty" toml:"auto_stop_machines,omitempty"`
+	Autostart          *bool `json:"autostart,omitempty" toml:"auto_start_machines,omitempty"`
+	MinMachinesRunning *int  `json:"min_machines_running,omitempty" toml:"min_machines_running,omitempty"`
 }
 
 type MachineServiceConcurrency struct {
@@ -207,6 +223,29 @@ type MachineConfig struct {
 	Metrics   *MachineMetrics         `json:"metrics"`
 	Schedule  string                  `json:"schedule,omitempty"`
 	Checks    map[string]MachineCheck `json:"checks,omitempty"`
+	Files     []MachineFile           `json:"files,omitempty"`
+
+	// Standbys are the IDs of the machines this one stands by for: it's kept
+	// stopped, and started when any of them becomes unhealthy.
+	Standbys []string `json:"standbys,omitempty"`
+}
+
+// MachineFile is a file written to the machine's filesystem on boot, with
+// its contents taken from the base64 encoded value of the named secret.
+type MachineFile struct {
+	GuestPath  string `json:"guest_path"`
+	SecretName string `json:"secret_name,omitempty"`
+}
+
+type MachineExecRequest struct {
+	Cmd     string `json:"cmd,omitempty"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+type MachineExecResponse struct {
+	ExitCode int32  `json:"exit_code,omitempty"`
+	StdOut   string `json:"stdout,omitempty"`
+	StdErr   string `json:"stderr,omitempty"`
 }
 
 type MachineLease struct {
@@ -231,6 +270,9 @@ type LaunchMachineInput struct {
 	OrgSlug string         `json:"organizationId,omitempty"`
 	Region  string         `json:"region,omitempty"`
 	Config  *MachineConfig `json:"config"`
+
+	// SkipLaunch creates the machine without starting it.
+	SkipLaunch bool `json:"skip_launch,omitempty"`
 }
 
 type MachineProcess struct {
@@ -37,3 +37,95 @@ func (c *Client) GetAppChanges(ctx context.Context, appName string) ([]AppChange
 
 	return data.App.Changes.Nodes, nil
 }
+
+// GetAuditEvents returns the latest audit events of an organization matching
+// filter, newest first.
+func (c *Client) GetAuditEvents(ctx context.Context, orgSlug string, filter AuditEventsFilter, limit int) ([]AuditEvent, error) {
+	query := `
+		query($slug: String!, $filter: AuditEventsFilter, $limit: Int!) {
+			organization(slug: $slug) {
+				auditEvents(filter: $filter, first: $limit) {
+					nodes {
+						id
+						type
+						description
+						app {
+							id
+							name
+						}
+						user {
+							id
+							email
+						}
+						ipAddress
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("slug", orgSlug)
+	req.Var("filter", filter)
+	req.Var("limit", limit)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Organization.AuditEvents.Nodes, nil
+}
+
+// GetAuditEventsPage returns a page of up to first audit events of an
+// organization matching filter, newest first, after the cursor given, if
+// any.
+func (c *Client) GetAuditEventsPage(ctx context.Context, orgSlug string, filter AuditEventsFilter, first int, after string) ([]AuditEvent, PageInfo, error) {
+	query := `
+		query($slug: String!, $filter: AuditEventsFilter, $first: Int!, $after: String) {
+			organization(slug: $slug) {
+				auditEvents(filter: $filter, first: $first, after: $after) {
+					nodes {
+						id
+						type
+						description
+						app {
+							id
+							name
+						}
+						user {
+							id
+							email
+						}
+						ipAddress
+						createdAt
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("slug", orgSlug)
+	req.Var("filter", filter)
+	req.Var("first", first)
+	if after != "" {
+		req.Var("after", after)
+	}
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, PageInfo{}, err
+	}
+
+	events := data.Organization.AuditEvents
+
+	return events.Nodes, events.PageInfo, nil
+}
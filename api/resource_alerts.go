@@ -0,0 +1,96 @@
+package api
+
+import "context"
+
+func (client *Client) GetAlertRules(ctx context.Context, organizationSlug string) ([]AlertRule, error) {
+	q := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				alertRules {
+					nodes {
+						id
+						name
+						condition
+						threshold
+						minutes
+						checkName
+						app {
+							id
+							name
+						}
+						targets {
+							type
+							address
+						}
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("slug", organizationSlug)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Organization.AlertRules.Nodes, nil
+}
+
+func (client *Client) CreateAlertRule(ctx context.Context, input CreateAlertRuleInput) (*AlertRule, error) {
+	q := `
+		mutation($input: CreateAlertRuleInput!) {
+			createAlertRule(input: $input) {
+				alertRule {
+					id
+					name
+					condition
+					threshold
+					minutes
+					checkName
+					app {
+						id
+						name
+					}
+					targets {
+						type
+						address
+					}
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateAlertRule.AlertRule, nil
+}
+
+func (client *Client) DeleteAlertRule(ctx context.Context, id string) error {
+	q := `
+		mutation($input: DeleteAlertRuleInput!) {
+			deleteAlertRule(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("input", DeleteAlertRuleInput{AlertRuleID: id})
+
+	_, err := client.RunWithContext(ctx, req)
+
+	return err
+}
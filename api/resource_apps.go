@@ -1,6 +1,9 @@
 package api
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 func (client *Client) GetApps(ctx context.Context, role *string) ([]App, error) {
 	query := `
@@ -39,6 +42,58 @@ func (client *Client) GetApps(ctx context.Context, role *string) ([]App, error)
 	return data.Apps.Nodes, nil
 }
 
+// GetOrganizationApps returns all the apps of the organization, fetching
+// them page by page.
+func (client *Client) GetOrganizationApps(ctx context.Context, orgSlug string) ([]App, error) {
+	query := `
+		query($slug: String!, $after: String) {
+			organization(slug: $slug) {
+				apps(first: 200, after: $after) {
+					nodes {
+						id
+						name
+						platformVersion
+					}
+					pageInfo {
+						hasNextPage
+						endCursor
+					}
+				}
+			}
+		}
+	`
+
+	var (
+		apps  []App
+		after string
+	)
+
+	for {
+		req := client.NewRequest(query)
+		req.Var("slug", orgSlug)
+		if after != "" {
+			req.Var("after", after)
+		}
+
+		data, err := client.RunWithContext(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+
+		if data.Organization == nil || data.Organization.Apps == nil {
+			return nil, fmt.Errorf("organization %s not found", orgSlug)
+		}
+
+		page := data.Organization.Apps
+		apps = append(apps, page.Nodes...)
+
+		if !page.PageInfo.HasNextPage {
+			return apps, nil
+		}
+		after = page.PageInfo.EndCursor
+	}
+}
+
 func (client *Client) GetAppID(ctx context.Context, appName string) (string, error) {
 	query := `
 		query ($appName: String!) {
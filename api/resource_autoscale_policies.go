@@ -0,0 +1,93 @@
+package api
+
+import "context"
+
+// GetAutoscalePolicies returns the autoscaling policies of the process groups
+// of the app.
+func (client *Client) GetAutoscalePolicies(ctx context.Context, appName string) ([]AutoscalePolicy, error) {
+	const query = `
+		query($appName: String!) {
+			app(name: $appName) {
+				autoscalePolicies {
+					nodes {
+						processGroup
+						minCount
+						maxCount
+						metric
+						target
+						query
+						currentCount
+						lastValue
+						lastScaledAt
+						updatedAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.AutoscalePolicies.Nodes, nil
+}
+
+// SetAutoscalePolicy sets the autoscaling policy of a process group of an
+// app, replacing its previous one.
+func (client *Client) SetAutoscalePolicy(ctx context.Context, input SetAutoscalePolicyInput) (*AutoscalePolicy, error) {
+	const query = `
+		mutation($input: SetAutoscalePolicyInput!) {
+			setAutoscalePolicy(input: $input) {
+				policy {
+					processGroup
+					minCount
+					maxCount
+					metric
+					target
+					query
+					currentCount
+					lastValue
+					lastScaledAt
+					updatedAt
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.SetAutoscalePolicy.Policy, nil
+}
+
+// DeleteAutoscalePolicy deletes the autoscaling policy of a process group of
+// an app, which keeps the machines it has.
+func (client *Client) DeleteAutoscalePolicy(ctx context.Context, appName, processGroup string) error {
+	const query = `
+		mutation($appName: ID!, $processGroup: String!) {
+			deleteAutoscalePolicy(input: {appId: $appName, processGroup: $processGroup}) {
+				app {
+					id
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("processGroup", processGroup)
+
+	_, err := client.RunWithContext(ctx, req)
+
+	return err
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"time"
+)
+
+// GetOrganizationUsage returns the usage of an organization from start until
+// end.
+func (client *Client) GetOrganizationUsage(ctx context.Context, organizationSlug string, start, end time.Time) (*OrganizationUsage, error) {
+	q := `
+		query($slug: String!, $start: ISO8601DateTime!, $end: ISO8601DateTime!) {
+			organization(slug: $slug) {
+				usage(start: $start, end: $end) {
+					currency
+					totalCents
+					items {
+						appName
+						category
+						resourceId
+						description
+						quantity
+						unit
+						amountCents
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("slug", organizationSlug)
+	req.Var("start", start.UTC().Format(time.RFC3339))
+	req.Var("end", end.UTC().Format(time.RFC3339))
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Organization.Usage == nil {
+		return &OrganizationUsage{}, nil
+	}
+
+	return data.Organization.Usage, nil
+}
@@ -11,6 +11,17 @@ func (c *Client) GetAppCertificates(ctx context.Context, appName string) ([]AppC
 						createdAt
 						hostname
 						clientStatus
+						certificateAuthority
+						issued {
+							nodes {
+								type
+								expiresAt
+							}
+						}
+						validationErrors {
+							message
+							timestamp
+						}
 					}
 				}
 			}
@@ -55,6 +66,10 @@ func (c *Client) CheckAppCertificate(ctx context.Context, appName, hostname stri
 							expiresAt
 						}
 					}
+					validationErrors {
+						message
+						timestamp
+					}
 				}
 				check {
 					aRecords
@@ -110,6 +125,10 @@ func (c *Client) AddCertificate(ctx context.Context, appName, hostname string) (
 							expiresAt
 						}
 					}
+					validationErrors {
+						message
+						timestamp
+					}
 				}
 				check {
 					aRecords
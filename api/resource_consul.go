@@ -0,0 +1,50 @@
+package api
+
+import "context"
+
+// GetAppConsul returns the managed Consul cluster the app is attached to, or
+// nil when it isn't attached to any.
+func (client *Client) GetAppConsul(ctx context.Context, appName string) (*ConsulCluster, error) {
+	const query = `
+		query($appName: String!) {
+			app(name: $appName) {
+				consul {
+					url
+					region
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.Consul, nil
+}
+
+// RotateConsulToken replaces the token of the app on its managed Consul
+// cluster, revoking the old one, and returns the URL embedding the new one.
+func (client *Client) RotateConsulToken(ctx context.Context, appName string) (*PostgresEnableConsulPayload, error) {
+	const query = `
+		mutation($appName: ID!) {
+			rotateConsulToken(input: {appId: $appName}) {
+				consulUrl
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.RotateConsulToken, nil
+}
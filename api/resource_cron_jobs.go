@@ -0,0 +1,89 @@
+package api
+
+import "context"
+
+// GetCronJobs returns the cron jobs of the app.
+func (client *Client) GetCronJobs(ctx context.Context, appName string) ([]CronJob, error) {
+	const query = `
+		query($appName: String!) {
+			app(name: $appName) {
+				cronJobs {
+					nodes {
+						id
+						name
+						schedule
+						command
+						size
+						image
+						region
+						machineId
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.CronJobs.Nodes, nil
+}
+
+// SetCronJob records a cron job of an app, replacing the job of the same
+// name.
+func (client *Client) SetCronJob(ctx context.Context, input SetCronJobInput) (*CronJob, error) {
+	const query = `
+		mutation($input: SetCronJobInput!) {
+			setCronJob(input: $input) {
+				cronJob {
+					id
+					name
+					schedule
+					command
+					size
+					image
+					region
+					machineId
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.SetCronJob.CronJob, nil
+}
+
+// DeleteCronJob deletes the record of a cron job of an app.
+func (client *Client) DeleteCronJob(ctx context.Context, appName, name string) error {
+	const query = `
+		mutation($appName: ID!, $name: String!) {
+			deleteCronJob(input: {appId: $appName, name: $name}) {
+				app {
+					id
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("name", name)
+
+	_, err := client.RunWithContext(ctx, req)
+
+	return err
+}
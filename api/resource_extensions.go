@@ -0,0 +1,149 @@
+package api
+
+import "context"
+
+// CreateSentryRelease creates a release of the Sentry project of the app,
+// along with the commit it was built from. It returns nil when the app has
+// no Sentry project tracking releases.
+func (client *Client) CreateSentryRelease(ctx context.Context, appName, version, commitSHA string) (*SentryRelease, error) {
+	const query = `
+		mutation($appId: ID!, $version: String!, $commitSha: String) {
+			createSentryRelease(input: {appId: $appId, version: $version, commitSha: $commitSha}) {
+				release {
+					version
+					url
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appId", appName)
+	req.Var("version", version)
+	if commitSHA != "" {
+		req.Var("commitSha", commitSHA)
+	}
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.CreateSentryRelease == nil {
+		return nil, nil
+	}
+
+	return data.CreateSentryRelease.Release, nil
+}
+
+// GetEmailExtension returns the email extension called name, along with the
+// DNS records its sending domain must have.
+func (client *Client) GetEmailExtension(ctx context.Context, name string) (*AddOn, error) {
+	const query = `
+		query($name: String) {
+			addOn(name: $name) {
+				id
+				name
+				dnsRecords {
+					name
+					type
+					value
+				}
+				organization {
+					slug
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("name", name)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AddOn, nil
+}
+
+// GetAddOnProviders returns the providers extensions can be provisioned from.
+func (client *Client) GetAddOnProviders(ctx context.Context) ([]AddOnProvider, error) {
+	const query = `
+		query {
+			addOnProviders {
+				name
+				displayName
+				description
+				type
+			}
+		}
+	`
+
+	data, err := client.RunWithContext(ctx, client.NewRequest(query))
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AddOnProviders, nil
+}
+
+// GetExtensions returns the extensions of the organizations of the user,
+// along with their provider and status.
+func (client *Client) GetExtensions(ctx context.Context) ([]AddOn, error) {
+	const query = `
+		query {
+			addOns {
+				nodes {
+					id
+					name
+					status
+					addOnProvider {
+						name
+					}
+					organization {
+						slug
+					}
+				}
+			}
+		}
+	`
+
+	data, err := client.RunWithContext(ctx, client.NewRequest(query))
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AddOns.Nodes, nil
+}
+
+// ProvisionExtension provisions an extension, returning it along with the
+// environment apps use to reach it.
+func (client *Client) ProvisionExtension(ctx context.Context, input CreateAddOnInput) (*AddOn, error) {
+	const query = `
+		mutation($input: CreateAddOnInput!) {
+			createAddOn(input: $input) {
+				addOn {
+					id
+					name
+					status
+					environment
+				}
+			}
+		}
+	`
+
+	if input.Options == nil {
+		input.Options = map[string]interface{}{}
+	}
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.CreateAddOn.AddOn, nil
+}
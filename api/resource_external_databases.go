@@ -0,0 +1,69 @@
+package api
+
+import "context"
+
+// GetExternalDatabases returns the databases hosted outside of Fly.io the app
+// is attached to.
+func (client *Client) GetExternalDatabases(ctx context.Context, appName string) ([]ExternalDatabase, error) {
+	const query = `
+		query($appName: String!) {
+			app(name: $appName) {
+				externalDatabases {
+					nodes {
+						id
+						name
+						provider
+						host
+						port
+						secretName
+						path
+						region
+						checkedAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.ExternalDatabases.Nodes, nil
+}
+
+// AttachExternalDatabase records that an app is attached to a database hosted
+// outside of Fly.io, replacing the attachment of the same name.
+func (client *Client) AttachExternalDatabase(ctx context.Context, input AttachExternalDatabaseInput) (*ExternalDatabase, error) {
+	const query = `
+		mutation($input: AttachExternalDatabaseInput!) {
+			attachExternalDatabase(input: $input) {
+				externalDatabase {
+					id
+					name
+					provider
+					host
+					port
+					secretName
+					path
+					region
+					checkedAt
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.AttachExternalDatabase.ExternalDatabase, nil
+}
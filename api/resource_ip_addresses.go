@@ -105,3 +105,82 @@ func (c *Client) ReleaseIPAddress(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (c *Client) GetEgressIPAddresses(ctx context.Context, appName string) ([]EgressIPAddress, error) {
+	query := `
+		query ($appName: String!) {
+			app(name: $appName) {
+				egressIpAddresses {
+					nodes {
+						id
+						ip
+						version
+						region
+						machineId
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.EgressIPAddresses.Nodes, nil
+}
+
+// AllocateEgressIPAddress allocates a static IPv4 and IPv6 egress address pair
+// to the machine, returning both.
+func (c *Client) AllocateEgressIPAddress(ctx context.Context, appName, machineID string) ([]EgressIPAddress, error) {
+	query := `
+		mutation($input: AllocateEgressIPAddressInput!) {
+			allocateEgressIpAddress(input: $input) {
+				egressIpAddresses {
+					id
+					ip
+					version
+					region
+					machineId
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", AllocateEgressIPAddressInput{AppID: appName, MachineID: machineID})
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AllocateEgressIPAddress.EgressIPAddresses, nil
+}
+
+// ReleaseEgressIPAddress releases the egress addresses of the machine, which
+// then sends outbound traffic from the shared addresses of its host.
+func (c *Client) ReleaseEgressIPAddress(ctx context.Context, appName, machineID string) error {
+	query := `
+		mutation($input: ReleaseEgressIPAddressInput!) {
+			releaseEgressIpAddress(input: $input) {
+				clientMutationId
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", ReleaseEgressIPAddressInput{AppID: appName, MachineID: machineID})
+
+	_, err := c.RunWithContext(ctx, req)
+
+	return err
+}
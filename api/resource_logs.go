@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 type getLogsResponse struct {
@@ -30,6 +32,60 @@ func (c *Client) GetAppLogs(ctx context.Context, appName, token, region, instanc
 
 	url := fmt.Sprintf("%s/api/v1/apps/%s/logs?%s", baseURL, appName, data.Encode())
 
+	return c.getLogs(ctx, url)
+}
+
+// LogSearch narrows down a search of the stored logs of an app. Query is
+// matched against the full text of messages, and Fields against the values
+// of their fields, like http.response.status_code.
+type LogSearch struct {
+	Query    string
+	Since    time.Time
+	Until    time.Time
+	Region   string
+	Instance string
+	Level    string
+	Fields   map[string]string
+	Limit    int
+}
+
+// SearchAppLogs searches the stored logs of an app, oldest first, rather than
+// only those of the last few minutes GetAppLogs returns. It's only available
+// to apps with log retention configured.
+func (c *Client) SearchAppLogs(ctx context.Context, appName string, search LogSearch, token string) (entries []LogEntry, nextToken string, err error) {
+	data := url.Values{}
+	data.Set("next_token", token)
+	if search.Query != "" {
+		data.Set("query", search.Query)
+	}
+	if !search.Since.IsZero() {
+		data.Set("since", search.Since.UTC().Format(time.RFC3339))
+	}
+	if !search.Until.IsZero() {
+		data.Set("until", search.Until.UTC().Format(time.RFC3339))
+	}
+	if search.Region != "" {
+		data.Set("region", search.Region)
+	}
+	if search.Instance != "" {
+		data.Set("instance", search.Instance)
+	}
+	if search.Level != "" {
+		data.Set("level", search.Level)
+	}
+	for field, value := range search.Fields {
+		data.Set("field["+field+"]", value)
+	}
+	if search.Limit > 0 {
+		data.Set("limit", strconv.Itoa(search.Limit))
+	}
+
+	url := fmt.Sprintf("%s/api/v1/apps/%s/logs/search?%s", baseURL, appName, data.Encode())
+
+	return c.getLogs(ctx, url)
+}
+
+func (c *Client) getLogs(ctx context.Context, url string) (entries []LogEntry, nextToken string, err error) {
 	var req *http.Request
 	if req, err = http.NewRequestWithContext(ctx, "GET", url, nil); err != nil {
 		return
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type MetricSample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// MetricSeries is a series of samples resulting from a PromQL query, along
+// with the labels identifying it.
+type MetricSeries struct {
+	Labels  map[string]string `json:"labels"`
+	Samples []MetricSample    `json:"samples"`
+}
+
+type queryResponse struct {
+	Status    string
+	ErrorType string
+	Error     string
+	Data      struct {
+		ResultType string
+		Result     json.RawMessage
+	}
+}
+
+// QueryMetricsRange evaluates a PromQL query over the metrics store of the
+// named organization and returns the samples of the first resulting series.
+func (c *Client) QueryMetricsRange(ctx context.Context, orgSlug, query string, start, end time.Time, step time.Duration) (samples []MetricSample, err error) {
+	series, err := c.QueryMetricsSeries(ctx, orgSlug, query, start, end, step)
+	if err != nil || len(series) == 0 {
+		return
+	}
+
+	return series[0].Samples, nil
+}
+
+// QueryMetricsSeries evaluates a PromQL query over the metrics store of the
+// named organization between start and end, and returns every resulting
+// series.
+func (c *Client) QueryMetricsSeries(ctx context.Context, orgSlug, query string, start, end time.Time, step time.Duration) ([]MetricSeries, error) {
+	data := url.Values{}
+	data.Set("query", query)
+	data.Set("start", strconv.FormatInt(start.Unix(), 10))
+	data.Set("end", strconv.FormatInt(end.Unix(), 10))
+	data.Set("step", strconv.Itoa(int(step.Seconds())))
+
+	return c.queryMetrics(ctx, orgSlug, "query_range", data)
+}
+
+// QueryMetrics evaluates a PromQL query over the metrics store of the named
+// organization at the given time, and returns every resulting series with
+// its single sample. Scalar results are returned as a series without labels.
+func (c *Client) QueryMetrics(ctx context.Context, orgSlug, query string, at time.Time) ([]MetricSeries, error) {
+	data := url.Values{}
+	data.Set("query", query)
+	data.Set("time", strconv.FormatInt(at.Unix(), 10))
+
+	return c.queryMetrics(ctx, orgSlug, "query", data)
+}
+
+func (c *Client) queryMetrics(ctx context.Context, orgSlug, endpoint string, data url.Values) ([]MetricSeries, error) {
+	url := fmt.Sprintf("%s/prometheus/%s/api/v1/%s?%s", baseURL, orgSlug, endpoint, data.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("User-Agent", c.userAgent)
+	if c.trace != "" {
+		req.Header.Set("Fly-Force-Trace", c.trace)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var result queryResponse
+	decodeErr := json.NewDecoder(res.Body).Decode(&result)
+
+	// Prometheus explains rejected queries, such as ones that don't parse, in
+	// the body of its error responses
+	if result.Status == "error" && result.Error != "" {
+		return nil, &ApiError{
+			Message: fmt.Sprintf("%s: %s", result.ErrorType, result.Error),
+			Status:  res.StatusCode,
+		}
+	}
+
+	if res.StatusCode != 200 {
+		return nil, ErrorFromResp(res)
+	}
+
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+
+	return parseMetricsResult(result.Data.ResultType, result.Data.Result)
+}
+
+func parseMetricsResult(resultType string, raw json.RawMessage) ([]MetricSeries, error) {
+	switch resultType {
+	case "scalar":
+		var value [2]interface{}
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+
+		series := MetricSeries{Labels: map[string]string{}}
+		if sample, ok := parseMetricSample(value); ok {
+			series.Samples = append(series.Samples, sample)
+		}
+
+		return []MetricSeries{series}, nil
+	case "vector", "matrix":
+		var result []struct {
+			Metric map[string]string
+			Value  *[2]interface{}
+			Values [][2]interface{}
+		}
+		if err := json.Unmarshal(raw, &result); err != nil {
+			return nil, err
+		}
+
+		series := make([]MetricSeries, 0, len(result))
+		for _, r := range result {
+			values := r.Values
+			if r.Value != nil {
+				values = append(values, *r.Value)
+			}
+
+			s := MetricSeries{Labels: r.Metric}
+			if s.Labels == nil {
+				s.Labels = map[string]string{}
+			}
+
+			for _, v := range values {
+				if sample, ok := parseMetricSample(v); ok {
+					s.Samples = append(s.Samples, sample)
+				}
+			}
+
+			series = append(series, s)
+		}
+
+		return series, nil
+	case "":
+		return nil, nil
+	default:
+		return nil, errors.New("unsupported result type " + resultType)
+	}
+}
+
+func parseMetricSample(v [2]interface{}) (sample MetricSample, ok bool) {
+	ts, ok := v[0].(float64)
+	if !ok {
+		return
+	}
+
+	str, ok := v[1].(string)
+	if !ok {
+		return
+	}
+
+	value, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return sample, false
+	}
+
+	return MetricSample{
+		Time:  time.Unix(int64(ts), 0),
+		Value: value,
+	}, true
+}
@@ -129,6 +129,32 @@ func (client *Client) GetDetailedOrganizationBySlug(ctx context.Context, slug st
 	return &data.OrganizationDetails, nil
 }
 
+// GetOrganizationLimits returns the machine quota of the organization
+// identified by slug, along with its current usage.
+func (client *Client) GetOrganizationLimits(ctx context.Context, slug string) (*OrganizationLimits, error) {
+	q := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				limits {
+					maxMachines
+					machines
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+
+	req.Var("slug", slug)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Organization.Limits, nil
+}
+
 func (c *Client) CreateOrganization(ctx context.Context, organizationname string) (*Organization, error) {
 	query := `
 		mutation($input: CreateOrganizationInput!) {
@@ -182,7 +208,10 @@ func (c *Client) DeleteOrganization(ctx context.Context, id string) (deletedid s
 	return data.DeleteOrganization.DeletedOrganizationId, nil
 }
 
-func (c *Client) CreateOrganizationInvite(ctx context.Context, id, email string) (*Invitation, error) {
+// CreateOrganizationInvite invites email to the organization with the given
+// id, as a member with the given role, or the default one when it's empty.
+// Only APIs taking the role of invitations accept a role.
+func (c *Client) CreateOrganizationInvite(ctx context.Context, id, email, role string) (*Invitation, error) {
 	query := `
 	mutation($input: CreateOrganizationInvitationInput!){
 		createOrganizationInvitation(input: $input){
@@ -201,10 +230,15 @@ func (c *Client) CreateOrganizationInvite(ctx context.Context, id, email string)
 
 	req := c.NewRequest(query)
 
-	req.Var("input", map[string]string{
+	input := map[string]string{
 		"organizationId": id,
 		"email":          email,
-	})
+	}
+	if role != "" {
+		input["role"] = role
+	}
+
+	req.Var("input", input)
 
 	data, err := c.RunWithContext(ctx, req)
 	if err != nil {
@@ -244,6 +278,89 @@ func (c *Client) DeleteOrganizationMembership(ctx context.Context, orgId, userId
 	return data.DeleteOrganizationMembership.Organization.Name, data.DeleteOrganizationMembership.User.Email, nil
 }
 
+// GetOrganizationInvitations returns the invitations to an organization that
+// haven't been redeemed yet.
+func (c *Client) GetOrganizationInvitations(ctx context.Context, slug string) ([]Invitation, error) {
+	query := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				invitations {
+					nodes {
+						id
+						email
+						createdAt
+						redeemed
+						inviter {
+							id
+							name
+							email
+						}
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("slug", slug)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Organization.Invitations.Nodes, nil
+}
+
+func (c *Client) UpdateOrganizationMembership(ctx context.Context, orgId, userId, role string) error {
+	query := `
+	mutation($input: UpdateOrganizationMembershipInput!){
+		updateOrganizationMembership(input: $input){
+			organization{
+				slug
+			}
+			user{
+				email
+			}
+		}
+	}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{
+		"userId":         userId,
+		"organizationId": orgId,
+		"role":           role,
+	})
+
+	_, err := c.RunWithContext(ctx, req)
+
+	return err
+}
+
+func (c *Client) DeleteOrganizationInvitation(ctx context.Context, invitationId string) error {
+	query := `
+	mutation($input: DeleteOrganizationInvitationInput!){
+		deleteOrganizationInvitation(input: $input){
+			organization{
+				slug
+			}
+		}
+	}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", map[string]string{
+		"invitationId": invitationId,
+	})
+
+	_, err := c.RunWithContext(ctx, req)
+
+	return err
+}
+
 func (c *Client) UpdateRemoteBuilder(ctx context.Context, orgName string, image string) (*Organization, error) {
 
 	org, err := c.GetOrganizationBySlug(ctx, orgName)
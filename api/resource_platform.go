@@ -87,3 +87,93 @@ func (c *Client) PlatformVMSizes(ctx context.Context) ([]VMSize, error) {
 
 	return data.Platform.VMSizes, nil
 }
+
+// PlatformGPUKinds returns the kinds of GPUs machines may be given.
+func (c *Client) PlatformGPUKinds(ctx context.Context) ([]GPUKind, error) {
+	query := `
+		query {
+			platform {
+				gpuKinds {
+					name
+					memoryGb
+					regions
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Platform.GPUKinds, nil
+}
+
+// PlatformRegionCapacity reports whether region has room for machines of the
+// given guest size.
+func (c *Client) PlatformRegionCapacity(ctx context.Context, region string, guest *MachineGuest) (bool, error) {
+	query := `
+		query($region: String!, $cpuKind: String!, $cpus: Int!, $memoryMb: Int!, $gpuKind: String) {
+			platform {
+				regionCapacity(region: $region, cpuKind: $cpuKind, cpus: $cpus, memoryMb: $memoryMb, gpuKind: $gpuKind) {
+					region
+					available
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("region", region)
+	req.Var("cpuKind", guest.CPUKind)
+	req.Var("cpus", guest.CPUs)
+	req.Var("memoryMb", guest.MemoryMB)
+	if guest.GPUKind != "" {
+		req.Var("gpuKind", guest.GPUKind)
+	}
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	return data.Platform.RegionCapacity.Available, nil
+}
+
+// PlatformRegionPlacement returns how many machines of the given guest size
+// region can place now, and how much volume space it has left.
+func (c *Client) PlatformRegionPlacement(ctx context.Context, region string, guest *MachineGuest) (*RegionCapacity, error) {
+	query := `
+		query($region: String!, $cpuKind: String!, $cpus: Int!, $memoryMb: Int!, $gpuKind: String) {
+			platform {
+				regionCapacity(region: $region, cpuKind: $cpuKind, cpus: $cpus, memoryMb: $memoryMb, gpuKind: $gpuKind) {
+					region
+					available
+					machines
+					volumeGb
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("region", region)
+	req.Var("cpuKind", guest.CPUKind)
+	req.Var("cpus", guest.CPUs)
+	req.Var("memoryMb", guest.MemoryMB)
+	if guest.GPUKind != "" {
+		req.Var("gpuKind", guest.GPUKind)
+	}
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.Platform.RegionCapacity, nil
+}
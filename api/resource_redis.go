@@ -0,0 +1,59 @@
+package api
+
+import "context"
+
+// GetAddOnMetrics returns the metrics of the Redis database called name, as
+// last reported by its provider.
+func (client *Client) GetAddOnMetrics(ctx context.Context, name string) (*AddOnMetrics, error) {
+	const query = `
+		query($name: String) {
+			addOn(name: $name) {
+				metrics {
+					usedMemoryBytes
+					maxMemoryBytes
+					evictionEnabled
+					evictedKeys
+					keys
+					hitRate
+					connectedClients
+					commandsPerSecond
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("name", name)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AddOn.Metrics, nil
+}
+
+// ResetAddOnPassword resets the password of the Redis database of id, and
+// returns it along with its new URL.
+func (client *Client) ResetAddOnPassword(ctx context.Context, id string) (*AddOn, error) {
+	const query = `
+		mutation($addOnId: ID!) {
+			resetAddOnPassword(input: {addOnId: $addOnId}) {
+				addOn {
+					id
+					publicUrl
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("addOnId", id)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.ResetAddOnPassword.AddOn, nil
+}
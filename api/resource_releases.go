@@ -1,6 +1,9 @@
 package api
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 func (c *Client) GetAppReleases(ctx context.Context, appName string, limit int) ([]Release, error) {
 	query := `
@@ -67,3 +70,67 @@ func (c *Client) GetAppRelease(ctx context.Context, appName string, id string) (
 
 	return data.App.Release, nil
 }
+
+// RecordReleasePromotion records in the release histories of both apps that
+// the image of the source release was promoted to the target app.
+func (c *Client) RecordReleasePromotion(ctx context.Context, input RecordReleasePromotionInput) (*Release, error) {
+	query := `
+		mutation ($input: RecordReleasePromotionInput!) {
+			recordReleasePromotion(input: $input) {
+				release {
+					id
+					version
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", input)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.RecordReleasePromotion.Release, nil
+}
+
+// GetAppReleaseByVersion returns the release of an app with the given
+// version, including the machine configs recorded for it, if any.
+func (c *Client) GetAppReleaseByVersion(ctx context.Context, appName string, version int) (*Release, error) {
+	query := `
+		query ($appName: String!, $version: Int!) {
+			app(name: $appName) {
+				release(version: $version) {
+					id
+					version
+					description
+					reason
+					status
+					imageRef
+					imageDigest
+					configs
+					createdAt
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+	req.Var("version", version)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.App.Release == nil {
+		return nil, fmt.Errorf("app %s has no release v%d", appName, version)
+	}
+
+	return data.App.Release, nil
+}
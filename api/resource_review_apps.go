@@ -0,0 +1,90 @@
+package api
+
+import "context"
+
+// GetReviewApps returns the review apps of the app, along with the template
+// their names follow.
+func (client *Client) GetReviewApps(ctx context.Context, appName string) (*ReviewApps, error) {
+	const query = `
+		query($appName: String!) {
+			app(name: $appName) {
+				reviewApps {
+					nameTemplate
+					nodes {
+						name
+						pullRequest
+						branch
+						postgresApp
+						createdAt
+						updatedAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.App.ReviewApps == nil {
+		return &ReviewApps{}, nil
+	}
+
+	return data.App.ReviewApps, nil
+}
+
+// RecordReviewApp records a review app of an app, replacing the record of the
+// same pull request, and stores the name template of the input when it's set.
+func (client *Client) RecordReviewApp(ctx context.Context, input RecordReviewAppInput) (*ReviewApp, error) {
+	const query = `
+		mutation($input: RecordReviewAppInput!) {
+			recordReviewApp(input: $input) {
+				reviewApp {
+					name
+					pullRequest
+					branch
+					postgresApp
+					createdAt
+					updatedAt
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.RecordReviewApp.ReviewApp, nil
+}
+
+// DeleteReviewApp deletes the record of the review app of a pull request of
+// an app.
+func (client *Client) DeleteReviewApp(ctx context.Context, appName string, pullRequest int) error {
+	const query = `
+		mutation($appName: ID!, $pullRequest: Int!) {
+			deleteReviewApp(input: {appId: $appName, pullRequest: $pullRequest}) {
+				app {
+					id
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("appName", appName)
+	req.Var("pullRequest", pullRequest)
+
+	_, err := client.RunWithContext(ctx, req)
+
+	return err
+}
@@ -0,0 +1,88 @@
+package api
+
+import "context"
+
+func (c *Client) GetRoutingPolicies(ctx context.Context, appName string) ([]RoutingPolicy, error) {
+	query := `
+		query ($appName: String!) {
+			app(name: $appName) {
+				routingPolicies {
+					nodes {
+						id
+						hostname
+						ipAddress {
+							id
+							address
+							type
+						}
+						regions
+						fallbackRegions
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("appName", appName)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.RoutingPolicies.Nodes, nil
+}
+
+// SetRoutingPolicy creates or replaces the routing policy of an address or a
+// hostname of an app. With neither, the policy applies to all of its traffic.
+func (c *Client) SetRoutingPolicy(ctx context.Context, input SetRoutingPolicyInput) (*RoutingPolicy, error) {
+	query := `
+		mutation($input: SetRoutingPolicyInput!) {
+			setRoutingPolicy(input: $input) {
+				routingPolicy {
+					id
+					hostname
+					ipAddress {
+						id
+						address
+						type
+					}
+					regions
+					fallbackRegions
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", input)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.SetRoutingPolicy.RoutingPolicy, nil
+}
+
+func (c *Client) DeleteRoutingPolicy(ctx context.Context, id string) error {
+	query := `
+		mutation($input: DeleteRoutingPolicyInput!) {
+			deleteRoutingPolicy(input: $input) {
+				app {
+					name
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", DeleteRoutingPolicyInput{RoutingPolicyID: id})
+
+	_, err := c.RunWithContext(ctx, req)
+
+	return err
+}
@@ -0,0 +1,51 @@
+package api
+
+import "context"
+
+// SchemaType is the introspected shape of a type of the GraphQL schema.
+type SchemaType struct {
+	Fields      []SchemaTypeMember
+	InputFields []SchemaTypeMember
+	EnumValues  []SchemaTypeMember
+}
+
+type SchemaTypeMember struct {
+	Name string
+}
+
+// SchemaHas reports whether the type named typeName of the API's schema has
+// a field, input field or enum value called name. Commands use it to check
+// the API serves what they need before relying on it.
+func (c *Client) SchemaHas(ctx context.Context, typeName, name string) (bool, error) {
+	query := `
+		query ($name: String!) {
+			__type(name: $name) {
+				fields { name }
+				inputFields { name }
+				enumValues { name }
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+	req.Var("name", typeName)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	if data.SchemaType == nil {
+		return false, nil
+	}
+
+	for _, members := range [][]SchemaTypeMember{data.SchemaType.Fields, data.SchemaType.InputFields, data.SchemaType.EnumValues} {
+		for _, m := range members {
+			if m.Name == name {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
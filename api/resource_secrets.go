@@ -3,6 +3,22 @@ package api
 import "context"
 
 func (c *Client) SetSecrets(ctx context.Context, appName string, secrets map[string]string) (*Release, error) {
+	return c.SetProcessGroupSecrets(ctx, appName, nil, secrets)
+}
+
+// SetProcessGroupSecrets sets secrets which are only exposed to the machines
+// of the given process groups. A nil or empty groups slice exposes the
+// secrets to every process group of the app.
+func (c *Client) SetProcessGroupSecrets(ctx context.Context, appName string, groups []string, secrets map[string]string) (*Release, error) {
+	input := SetSecretsInput{AppID: appName}
+	for k, v := range secrets {
+		input.Secrets = append(input.Secrets, SetSecretsInputSecret{Key: k, Value: v, ProcessGroups: groups})
+	}
+
+	return c.setSecrets(ctx, input)
+}
+
+func (c *Client) setSecrets(ctx context.Context, input SetSecretsInput) (*Release, error) {
 	query := `
 		mutation($input: SetSecretsInput!) {
 			setSecrets(input: $input) {
@@ -23,11 +39,6 @@ func (c *Client) SetSecrets(ctx context.Context, appName string, secrets map[str
 		}
 	`
 
-	input := SetSecretsInput{AppID: appName}
-	for k, v := range secrets {
-		input.Secrets = append(input.Secrets, SetSecretsInputSecret{Key: k, Value: v})
-	}
-
 	req := c.NewRequest(query)
 
 	req.Var("input", input)
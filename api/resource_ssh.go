@@ -47,6 +47,21 @@ mutation($input: EstablishSSHKeyInput!) {
 }
 
 func (c *Client) IssueSSHCertificate(ctx context.Context, org OrganizationImpl, email string, username *string, valid_hours *int) (*IssuedCertificate, error) {
+	input := IssueCertificateInput{
+		OrganizationID: org.GetID(),
+		Email:          email,
+		Username:       username,
+		ValidHours:     valid_hours,
+	}
+
+	return c.IssueRestrictedSSHCertificate(ctx, input)
+}
+
+// IssueRestrictedSSHCertificate issues an SSH certificate limited to the
+// principals, validity and source addresses of input. ValidMinutes and
+// SourceAddresses need the API to serve them, which it doesn't everywhere
+// yet; check with SchemaHas before setting them.
+func (c *Client) IssueRestrictedSSHCertificate(ctx context.Context, input IssueCertificateInput) (*IssuedCertificate, error) {
 	req := c.NewRequest(`
 mutation($input: IssueCertificateInput!) {
   issueCertificate(input: $input) {
@@ -54,20 +69,7 @@ mutation($input: IssueCertificateInput!) {
   }
 }
 `)
-	inputs := map[string]interface{}{
-		"organizationId": org.GetID(),
-		"email":          email,
-	}
-
-	if username != nil {
-		inputs["username"] = *username
-	}
-
-	if valid_hours != nil {
-		inputs["validHours"] = *valid_hours
-	}
-
-	req.Var("input", inputs)
+	req.Var("input", input)
 
 	data, err := c.RunWithContext(ctx, req)
 	if err != nil {
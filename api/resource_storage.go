@@ -0,0 +1,55 @@
+package api
+
+import "context"
+
+// GetStorageBucket returns the Tigris bucket called name, along with the
+// environment apps use to reach it.
+func (client *Client) GetStorageBucket(ctx context.Context, name string) (*AddOn, error) {
+	const query = `
+		query($name: String) {
+			addOn(name: $name) {
+				id
+				name
+				environment
+				organization {
+					slug
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("name", name)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.AddOn, nil
+}
+
+// RotateAddOnCredentials issues new credentials for the add-on of id, revoking
+// the old ones, and returns it along with its new environment.
+func (client *Client) RotateAddOnCredentials(ctx context.Context, id string) (*AddOn, error) {
+	const query = `
+		mutation($addOnId: ID!) {
+			rotateAddOnCredentials(input: {addOnId: $addOnId}) {
+				addOn {
+					id
+					environment
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(query)
+	req.Var("addOnId", id)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.RotateAddOnCredentials.AddOn, nil
+}
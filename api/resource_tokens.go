@@ -0,0 +1,131 @@
+package api
+
+import "context"
+
+func (client *Client) GetAccessTokens(ctx context.Context, organizationSlug string) ([]AccessToken, error) {
+	q := `
+		query($slug: String!) {
+			organization(slug: $slug) {
+				accessTokens {
+					nodes {
+						id
+						name
+						scope
+						app {
+							id
+							name
+						}
+						machineIds
+						allowedIps
+						createdBy {
+							id
+							email
+						}
+						expiresAt
+						lastUsedAt
+						createdAt
+					}
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("slug", organizationSlug)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Organization.AccessTokens.Nodes, nil
+}
+
+// CreateAccessToken creates a scoped token and returns it along with its
+// secret, which can't be retrieved again.
+func (client *Client) CreateAccessToken(ctx context.Context, input CreateAccessTokenInput) (*AccessToken, string, error) {
+	q := `
+		mutation($input: CreateAccessTokenInput!) {
+			createAccessToken(input: $input) {
+				accessToken {
+					id
+					name
+					scope
+					app {
+						id
+						name
+					}
+					machineIds
+					allowedIps
+					expiresAt
+					createdAt
+				}
+				token
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("input", input)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &data.CreateAccessToken.AccessToken, data.CreateAccessToken.Token, nil
+}
+
+func (client *Client) RevokeAccessToken(ctx context.Context, id string) error {
+	q := `
+		mutation($input: RevokeAccessTokenInput!) {
+			revokeAccessToken(input: $input) {
+				organization {
+					id
+				}
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+	req.Var("input", RevokeAccessTokenInput{AccessTokenID: id})
+
+	_, err := client.RunWithContext(ctx, req)
+
+	return err
+}
+
+// GetCurrentAccessToken returns the scoped token the client authenticates
+// with, or nil when it authenticates with the token of a user.
+func (client *Client) GetCurrentAccessToken(ctx context.Context) (*AccessToken, error) {
+	q := `
+		query {
+			currentAccessToken {
+				id
+				name
+				scope
+				app {
+					id
+					name
+				}
+				machineIds
+				organization {
+					id
+					slug
+				}
+				allowedIps
+				expiresAt
+				createdAt
+			}
+		}
+	`
+
+	req := client.NewRequest(q)
+
+	data, err := client.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.CurrentAccessToken, nil
+}
@@ -0,0 +1,43 @@
+package api
+
+import "context"
+
+// GetRequestTrace returns how the proxy handled the request of an app with
+// the given fly-request-id.
+func (c *Client) GetRequestTrace(ctx context.Context, appName, requestID string) (*RequestTrace, error) {
+	query := `
+		query($appName: String!, $requestId: String!) {
+			app(name: $appName) {
+				requestTrace(requestId: $requestId) {
+					requestId
+					method
+					url
+					edgeRegion
+					status
+					durationMs
+					startedAt
+					spans {
+						name
+						region
+						machineId
+						description
+						offsetMs
+						durationMs
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("appName", appName)
+	req.Var("requestId", requestID)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.App.RequestTrace, nil
+}
@@ -2,6 +2,9 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"net/http"
 )
 
 func (c *Client) GetVolumes(ctx context.Context, appName string) ([]Volume, error) {
@@ -86,6 +89,42 @@ func (c *Client) CreateVolume(ctx context.Context, input CreateVolumeInput) (*Vo
 	return &data.CreateVolume.Volume, nil
 }
 
+// ForkVolume creates a copy-on-write duplicate of a volume under the given
+// app, which must belong to the organization of the volume's app.
+func (c *Client) ForkVolume(ctx context.Context, input ForkVolumeInput) (*Volume, error) {
+	query := `
+		mutation($input: ForkVolumeInput!) {
+			forkVolume(input: $input) {
+				volume {
+					id
+					name
+					app{
+						name
+					}
+					region
+					sizeGb
+					encrypted
+					createdAt
+					host {
+						id
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", input)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data.ForkVolume.Volume, nil
+}
+
 func (c *Client) ExtendVolume(ctx context.Context, input ExtendVolumeInput) (*Volume, error) {
 	query := `
 		mutation($input: ExtendVolumeInput!) {
@@ -166,6 +205,9 @@ func (c *Client) GetVolume(ctx context.Context, volID string) (Volume *Volume, e
 				host {
 					id
 				}
+				attachedMachine {
+					id
+				}
 			}
 		}
 	}`
@@ -212,3 +254,125 @@ func (c *Client) GetVolumeSnapshots(ctx context.Context, volID string) ([]Snapsh
 
 	return data.Volume.Snapshots.Nodes, nil
 }
+
+// CreateVolumeSnapshot requests an on-demand snapshot of the given volume.
+// The snapshot is taken asynchronously; use GetVolumeSnapshots to find it.
+func (c *Client) CreateVolumeSnapshot(ctx context.Context, volID string) error {
+	query := `
+		mutation($input: CreateVolumeSnapshotInput!) {
+			createVolumeSnapshot(input: $input) {
+				volume {
+					id
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", CreateVolumeSnapshotInput{VolumeID: volID})
+
+	_, err := c.RunWithContext(ctx, req)
+
+	return err
+}
+
+func (c *Client) GetVolumeSnapshotSchedule(ctx context.Context, volID string) ([]SnapshotScheduleRule, error) {
+	query := `
+	query($id: ID!) {
+		volume: node(id: $id) {
+			... on Volume {
+				snapshotSchedule {
+					frequency
+					retention
+				}
+			}
+		}
+	}`
+
+	req := c.NewRequest(query)
+
+	req.Var("id", volID)
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.Volume.SnapshotSchedule, nil
+}
+
+// SetVolumeSnapshotSchedule replaces the snapshot schedule of the given
+// volume. An empty set of rules restores the default daily snapshot.
+func (c *Client) SetVolumeSnapshotSchedule(ctx context.Context, volID string, rules []SnapshotScheduleRule) ([]SnapshotScheduleRule, error) {
+	query := `
+		mutation($input: SetVolumeSnapshotScheduleInput!) {
+			setVolumeSnapshotSchedule(input: $input) {
+				volume {
+					snapshotSchedule {
+						frequency
+						retention
+					}
+				}
+			}
+		}
+	`
+
+	req := c.NewRequest(query)
+
+	req.Var("input", SetVolumeSnapshotScheduleInput{VolumeID: volID, Rules: rules})
+
+	data, err := c.RunWithContext(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return data.SetVolumeSnapshotSchedule.Volume.SnapshotSchedule, nil
+}
+
+// GetVolumeSnapshotContent streams the contents of the given snapshot from
+// offset onwards. It also returns the total size of the snapshot.
+func (c *Client) GetVolumeSnapshotContent(ctx context.Context, snapshotID string, offset int64) (body io.ReadCloser, size int64, err error) {
+	url := fmt.Sprintf("%s/api/v1/volume_snapshots/%s/content", baseURL, snapshotID)
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, "GET", url, nil); err != nil {
+		return
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.accessToken))
+	req.Header.Set("User-Agent", c.userAgent)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	if c.trace != "" {
+		req.Header.Set("Fly-Force-Trace", c.trace)
+	}
+
+	var res *http.Response
+	if res, err = c.httpClient.Do(req); err != nil {
+		return
+	}
+
+	switch {
+	case res.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// the range starts at the end of the content when an earlier download
+		// already fetched all of it
+		res.Body.Close()
+		if res.Header.Get("Content-Range") == fmt.Sprintf("bytes */%d", offset) {
+			body, size = http.NoBody, offset
+		} else {
+			err = fmt.Errorf("snapshot content can't be resumed from byte %d", offset)
+		}
+	case res.StatusCode == http.StatusOK && offset > 0:
+		res.Body.Close()
+		err = fmt.Errorf("snapshot content can't be resumed from byte %d", offset)
+	case res.StatusCode == http.StatusOK, res.StatusCode == http.StatusPartialContent:
+		body, size = res.Body, offset+res.ContentLength
+	default:
+		res.Body.Close()
+		err = ErrorFromResp(res)
+	}
+
+	return
+}
@@ -23,6 +23,7 @@ type Query struct {
 	AppCertsCompact      AppCertsCompact
 	CurrentUser          User
 	PersonalOrganization Organization
+	SchemaType           *SchemaType `json:"__type"`
 	GqlMachine           GqlMachine
 	Organizations        struct {
 		Nodes []Organization
@@ -34,6 +35,20 @@ type Query struct {
 
 	AddOn *AddOn
 
+	AddOnProviders []AddOnProvider
+
+	CreateAddOn struct {
+		AddOn AddOn
+	}
+
+	RotateAddOnCredentials struct {
+		AddOn AddOn
+	}
+
+	ResetAddOnPassword struct {
+		AddOn AddOn
+	}
+
 	Organization *Organization
 	// PersonalOrganizations PersonalOrganizations
 	OrganizationDetails OrganizationDetails
@@ -45,9 +60,11 @@ type Query struct {
 	Nodes []interface{}
 
 	Platform struct {
-		RequestRegion string
-		Regions       []Region
-		VMSizes       []VMSize
+		RequestRegion  string
+		Regions        []Region
+		VMSizes        []VMSize
+		GPUKinds       []GPUKind
+		RegionCapacity RegionCapacity
 	}
 
 	NearestRegion *Region
@@ -115,6 +132,20 @@ type Query struct {
 	ReleaseIPAddress struct {
 		App App
 	}
+
+	SetRoutingPolicy struct {
+		RoutingPolicy RoutingPolicy
+	}
+	DeleteRoutingPolicy struct {
+		App App
+	}
+
+	AllocateEgressIPAddress struct {
+		EgressIPAddresses []EgressIPAddress
+	}
+	ReleaseEgressIPAddress struct {
+		App App
+	}
 	ScaleApp struct {
 		App       App
 		Placement []RegionPlacement
@@ -178,6 +209,13 @@ type Query struct {
 	CreateVolume CreateVolumePayload
 	DeleteVolume DeleteVolumePayload
 	ExtendVolume ExtendVolumePayload
+	ForkVolume   ForkVolumePayload
+
+	CreateVolumeSnapshot CreateVolumeSnapshotPayload
+
+	SetVolumeSnapshotSchedule SetVolumeSnapshotSchedulePayload
+
+	RecordReleasePromotion RecordReleasePromotionPayload
 
 	AddWireGuardPeer              CreatedWireGuardPeer
 	EstablishSSHKey               SSHCertificate
@@ -197,11 +235,48 @@ type Query struct {
 		Handler *HealthCheckHandler
 	}
 
+	CreateAlertRule struct {
+		AlertRule AlertRule
+	}
+	DeleteAlertRule struct {
+		Organization Organization
+	}
+
+	CreateAccessToken struct {
+		AccessToken AccessToken
+		Token       string
+	}
+	RevokeAccessToken struct {
+		Organization Organization
+	}
+	CurrentAccessToken *AccessToken
+
 	CreatePostgresCluster *CreatePostgresClusterPayload
 
 	AttachPostgresCluster *AttachPostgresClusterPayload
 
 	EnablePostgresConsul *PostgresEnableConsulPayload
+	RotateConsulToken    *PostgresEnableConsulPayload
+
+	CreateSentryRelease *struct {
+		Release *SentryRelease
+	}
+
+	AttachExternalDatabase *struct {
+		ExternalDatabase ExternalDatabase
+	}
+
+	SetCronJob *struct {
+		CronJob CronJob
+	}
+
+	RecordReviewApp *struct {
+		ReviewApp ReviewApp
+	}
+
+	SetAutoscalePolicy *struct {
+		Policy AutoscalePolicy
+	}
 
 	CreateOrganizationInvitation CreateOrganizationInvitation
 
@@ -215,6 +290,15 @@ type Query struct {
 
 	DeleteOrganizationMembership *DeleteOrganizationMembershipPayload
 
+	UpdateOrganizationMembership struct {
+		Organization Organization
+		User         User
+	}
+
+	DeleteOrganizationInvitation struct {
+		Organization Organization
+	}
+
 	UpdateRemoteBuilder struct {
 		Organization Organization
 	}
@@ -250,6 +334,16 @@ type IssuedCertificate struct {
 	Key         string
 }
 
+type IssueCertificateInput struct {
+	OrganizationID  string   `json:"organizationId"`
+	Email           string   `json:"email"`
+	Username        *string  `json:"username,omitempty"`
+	Principals      *string  `json:"principals,omitempty"`
+	ValidHours      *int     `json:"validHours,omitempty"`
+	ValidMinutes    *int     `json:"validMinutes,omitempty"`
+	SourceAddresses []string `json:"sourceAddresses,omitempty"`
+}
+
 type Definition map[string]interface{}
 
 type MachineInit struct {
@@ -295,8 +389,14 @@ type App struct {
 	IPAddresses struct {
 		Nodes []IPAddress
 	}
-	IPAddress *IPAddress
-	Builds    struct {
+	IPAddress         *IPAddress
+	EgressIPAddresses struct {
+		Nodes []EgressIPAddress
+	}
+	RoutingPolicies struct {
+		Nodes []RoutingPolicy
+	}
+	Builds struct {
 		Nodes []Build
 	}
 	SourceBuilds struct {
@@ -331,7 +431,23 @@ type App struct {
 		Databases *[]PostgresClusterDatabase
 		Users     *[]PostgresClusterUser
 	}
-	Image *Image
+	Image        *Image
+	RequestTrace *RequestTrace
+	Consul       *ConsulCluster
+
+	ExternalDatabases struct {
+		Nodes []ExternalDatabase
+	}
+
+	CronJobs struct {
+		Nodes []CronJob
+	}
+
+	ReviewApps *ReviewApps
+
+	AutoscalePolicies struct {
+		Nodes []AutoscalePolicy
+	}
 
 	ImageUpgradeAvailable       bool
 	ImageVersionTrackingEnabled bool
@@ -353,6 +469,11 @@ type Snapshot struct {
 	CreatedAt time.Time
 }
 
+type SnapshotScheduleRule struct {
+	Frequency string `json:"frequency"`
+	Retention string `json:"retention"`
+}
+
 type Volume struct {
 	ID  string `json:"id"`
 	App struct {
@@ -364,6 +485,7 @@ type Volume struct {
 	Snapshots struct {
 		Nodes []Snapshot
 	}
+	SnapshotSchedule   []SnapshotScheduleRule
 	State              string
 	Region             string
 	Encrypted          bool
@@ -395,6 +517,16 @@ type CreateVolumeInput struct {
 	RequireUniqueZone bool    `json:"requireUniqueZone"`
 }
 
+type ForkVolumeInput struct {
+	AppID       string `json:"appId"`
+	SourceVolID string `json:"sourceVolId"`
+	Name        string `json:"name,omitempty"`
+}
+
+type ForkVolumePayload struct {
+	Volume Volume
+}
+
 type ExtendVolumeInput struct {
 	VolumeID string `json:"volumeId"`
 	SizeGb   int    `json:"sizeGb"`
@@ -410,6 +542,23 @@ type ExtendVolumePayload struct {
 	Volume Volume
 }
 
+type CreateVolumeSnapshotInput struct {
+	VolumeID string `json:"volumeId"`
+}
+
+type CreateVolumeSnapshotPayload struct {
+	Volume Volume
+}
+
+type SetVolumeSnapshotScheduleInput struct {
+	VolumeID string                 `json:"volumeId"`
+	Rules    []SnapshotScheduleRule `json:"rules"`
+}
+
+type SetVolumeSnapshotSchedulePayload struct {
+	Volume Volume
+}
+
 type DeleteVolumeInput struct {
 	VolumeID string `json:"volumeId"`
 }
@@ -425,9 +574,14 @@ type AppCertsCompact struct {
 }
 
 type AppCertificateCompact struct {
-	CreatedAt    time.Time
-	Hostname     string
-	ClientStatus string
+	CreatedAt            time.Time
+	Hostname             string
+	ClientStatus         string
+	CertificateAuthority string
+	Issued               struct {
+		Nodes []AppCertificateIssued
+	}
+	ValidationErrors []AppCertificateValidationError
 }
 
 type AppCompact struct {
@@ -552,6 +706,28 @@ type Organization struct {
 		Nodes []HealthCheckHandler
 	}
 
+	Apps *struct {
+		Nodes    []App
+		PageInfo PageInfo
+	}
+
+	AlertRules *struct {
+		Nodes []AlertRule
+	}
+
+	AuditEvents *struct {
+		Nodes    []AuditEvent
+		PageInfo PageInfo
+	}
+
+	AccessTokens *struct {
+		Nodes []AccessToken
+	}
+
+	Invitations *struct {
+		Nodes []Invitation
+	}
+
 	HealthChecks *struct {
 		Nodes []HealthCheck
 	}
@@ -559,6 +735,17 @@ type Organization struct {
 	LoggedCertificates *struct {
 		Nodes []LoggedCertificate
 	}
+
+	Limits *OrganizationLimits
+
+	Usage *OrganizationUsage
+}
+
+// OrganizationLimits holds the quotas of an organization along with its
+// current usage of each.
+type OrganizationLimits struct {
+	MaxMachines int
+	Machines    int
 }
 
 func (o *Organization) GetID() string {
@@ -641,6 +828,17 @@ type IPAddress struct {
 	CreatedAt time.Time
 }
 
+// EgressIPAddress is a static address outbound traffic from a machine is
+// sent from.
+type EgressIPAddress struct {
+	ID        string
+	IP        string
+	Version   int
+	Region    string
+	MachineID string
+	CreatedAt time.Time
+}
+
 type User struct {
 	ID    string
 	Name  string
@@ -659,8 +857,9 @@ type SetSecretsInput struct {
 }
 
 type SetSecretsInputSecret struct {
-	Key   string `json:"key"`
-	Value string `json:"value"`
+	Key           string   `json:"key"`
+	Value         string   `json:"value"`
+	ProcessGroups []string `json:"processGroups,omitempty"`
 }
 
 type UnsetSecretsInput struct {
@@ -722,6 +921,11 @@ type Release struct {
 	EvaluationID       string
 	CreatedAt          time.Time
 	ImageRef           string
+	// ImageDigest and Configs are recorded for the releases of machines apps,
+	// Configs being the machine config the release rendered for each of its
+	// process groups.
+	ImageDigest string
+	Configs     map[string]*MachineConfig
 }
 
 type Build struct {
@@ -751,6 +955,61 @@ type SignedUrl struct {
 	PutUrl string
 }
 
+// AuditEvent is an event of the audit log of an organization, such as a
+// deployment, a change to secrets, a machine being mutated or a member
+// logging in. App is nil for events that aren't about an app.
+type AuditEvent struct {
+	ID          string
+	Type        string
+	Description string
+	App         *AppBasic
+	User        *User
+	IPAddress   string
+	CreatedAt   time.Time
+}
+
+// PageInfo tells whether a connection has more nodes after the page
+// retrieved, and the cursor to retrieve them after.
+type PageInfo struct {
+	HasNextPage bool
+	EndCursor   string
+}
+
+// AuditEventsFilter narrows down the audit events of an organization. Types
+// are categories of events, like deploy, secrets, machine or login.
+type AuditEventsFilter struct {
+	Since   *time.Time `json:"since,omitempty"`
+	Until   *time.Time `json:"until,omitempty"`
+	Types   []string   `json:"types,omitempty"`
+	AppName string     `json:"appName,omitempty"`
+	Email   string     `json:"email,omitempty"`
+}
+
+// RequestTrace is how the proxy handled a request to an app, identified by
+// the fly-request-id header of its response.
+type RequestTrace struct {
+	RequestID  string
+	Method     string
+	URL        string
+	EdgeRegion string
+	Status     int
+	DurationMs float64
+	StartedAt  time.Time
+	Spans      []RequestTraceSpan
+}
+
+// RequestTraceSpan is a step of a traced request, like accepting it at the
+// edge, picking a machine to route it to, or waiting on the machine's
+// response. OffsetMs is when the step started, relative to the request.
+type RequestTraceSpan struct {
+	Name        string
+	Region      string
+	MachineID   string
+	Description string
+	OffsetMs    float64
+	DurationMs  float64
+}
+
 type AppChange struct {
 	ID        string
 	CreatedAt time.Time
@@ -796,11 +1055,23 @@ type AppCertificate struct {
 	IsApex                    bool
 	IsWildcard                bool
 	Issued                    struct {
-		Nodes []struct {
-			ExpiresAt time.Time
-			Type      string
-		}
+		Nodes []AppCertificateIssued
 	}
+	ValidationErrors []AppCertificateValidationError
+}
+
+// AppCertificateIssued is a certificate issued for a hostname, of which there
+// is one per key type.
+type AppCertificateIssued struct {
+	ExpiresAt time.Time
+	Type      string
+}
+
+// AppCertificateValidationError is an error validating the ownership of a
+// hostname, which holds up issuing its certificate.
+type AppCertificateValidationError struct {
+	Message   string
+	Timestamp time.Time
 }
 
 type CreateOrganizationPayload struct {
@@ -830,6 +1101,17 @@ type DeleteAddOnPayload struct {
 	ID string
 }
 
+type RecordReleasePromotionInput struct {
+	SourceAppID     string `json:"sourceAppId"`
+	SourceReleaseID string `json:"sourceReleaseId"`
+	TargetAppID     string `json:"targetAppId"`
+	Image           string `json:"image"`
+}
+
+type RecordReleasePromotionPayload struct {
+	Release Release
+}
+
 type DeployImageInput struct {
 	AppID      string      `json:"appId"`
 	Image      string      `json:"image"`
@@ -883,6 +1165,139 @@ type ReleaseIPAddressInput struct {
 	IPAddressID string `json:"ipAddressId"`
 }
 
+// RoutingPolicy restricts the regions the proxy routes the traffic of an
+// address, or of a hostname, to. Traffic goes to the nearest of Regions, and
+// to FallbackRegions in order when none of them can take it.
+type RoutingPolicy struct {
+	ID              string
+	Hostname        string
+	IPAddress       *IPAddress
+	Regions         []string
+	FallbackRegions []string
+}
+
+type SetRoutingPolicyInput struct {
+	AppID           string   `json:"appId"`
+	IPAddressID     string   `json:"ipAddressId,omitempty"`
+	Hostname        string   `json:"hostname,omitempty"`
+	Regions         []string `json:"regions"`
+	FallbackRegions []string `json:"fallbackRegions"`
+}
+
+// OrganizationUsage is the usage of an organization over a period, in line
+// items of the resources it's billed for.
+type OrganizationUsage struct {
+	Currency   string
+	TotalCents int
+	Items      []UsageItem
+}
+
+// UsageItem is the usage of a resource: a MACHINE, a VOLUME, the BANDWIDTH
+// of an app, or OTHER resources like IP addresses, and what it costs.
+// Quantity is measured in Unit, like seconds or GB.
+type UsageItem struct {
+	AppName     string
+	Category    string
+	ResourceID  string
+	Description string
+	Quantity    float64
+	Unit        string
+	AmountCents int
+}
+
+type DeleteRoutingPolicyInput struct {
+	RoutingPolicyID string `json:"routingPolicyId"`
+}
+
+// AlertRule notifies its Targets once its Condition has held for Minutes on
+// the apps of an organization, or on App alone when set. Threshold is the
+// percentage of responses that are errors for ERROR_RATE conditions, and
+// CheckName narrows CHECK_FAILING conditions down to a single check.
+type AlertRule struct {
+	ID        string
+	Name      string
+	Condition string
+	Threshold float64
+	Minutes   int
+	CheckName string
+	App       *AppBasic
+	Targets   []AlertTarget
+	CreatedAt time.Time
+}
+
+// AlertTarget is where alerts are sent: an EMAIL address, a SLACK webhook, or
+// a PAGERDUTY service. Address is the email address, the Slack channel, or
+// the name of the PagerDuty service, since webhooks and tokens are secret.
+type AlertTarget struct {
+	Type    string
+	Address string
+}
+
+type CreateAlertRuleInput struct {
+	OrganizationID string             `json:"organizationId"`
+	AppID          string             `json:"appId,omitempty"`
+	Name           string             `json:"name"`
+	Condition      string             `json:"condition"`
+	Threshold      float64            `json:"threshold,omitempty"`
+	Minutes        int                `json:"minutes,omitempty"`
+	CheckName      string             `json:"checkName,omitempty"`
+	Targets        []AlertTargetInput `json:"targets"`
+}
+
+type AlertTargetInput struct {
+	Type            string `json:"type"`
+	Email           string `json:"email,omitempty"`
+	SlackWebhookURL string `json:"slackWebhookUrl,omitempty"`
+	SlackChannel    string `json:"slackChannel,omitempty"`
+	PagerdutyToken  string `json:"pagerdutyToken,omitempty"`
+}
+
+type DeleteAlertRuleInput struct {
+	AlertRuleID string `json:"alertRuleId"`
+}
+
+// AccessToken is a token scoped to DEPLOY, READ_ONLY or MACHINES operations
+// on the apps of an organization, or on a single app. MACHINE_EXEC tokens
+// may only start, stop and exec into the MachineIDs of their app. Tokens with
+// AllowedIPs are only accepted from those addresses or networks.
+type AccessToken struct {
+	ID           string
+	Name         string
+	Scope        string
+	App          *AppBasic
+	MachineIDs   []string
+	AllowedIPs   []string
+	CreatedBy    *User
+	ExpiresAt    *time.Time
+	LastUsedAt   *time.Time
+	CreatedAt    time.Time
+	Organization *OrganizationBasic
+}
+
+type CreateAccessTokenInput struct {
+	OrganizationID string     `json:"organizationId"`
+	AppID          string     `json:"appId,omitempty"`
+	MachineIDs     []string   `json:"machineIds,omitempty"`
+	Name           string     `json:"name"`
+	Scope          string     `json:"scope"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	AllowedIPs     []string   `json:"allowedIps,omitempty"`
+}
+
+type RevokeAccessTokenInput struct {
+	AccessTokenID string `json:"accessTokenId"`
+}
+
+type AllocateEgressIPAddressInput struct {
+	AppID     string `json:"appId"`
+	MachineID string `json:"machineId"`
+}
+
+type ReleaseEgressIPAddressInput struct {
+	AppID     string `json:"appId"`
+	MachineID string `json:"machineId"`
+}
+
 type ScaleAppInput struct {
 	AppID   string             `json:"appId"`
 	Regions []ScaleRegionInput `json:"regions"`
@@ -956,6 +1371,23 @@ type Region struct {
 	GatewayAvailable bool
 }
 
+// GPUKind is a kind of GPU machines may be given, along with the regions
+// it's available in.
+type GPUKind struct {
+	Name     string
+	MemoryGB int
+	Regions  []string
+}
+
+type RegionCapacity struct {
+	Region    string
+	Available bool
+	// Machines is the count of machines of the size asked about the region
+	// can place now, and VolumeGb the space it has left for volumes.
+	Machines int
+	VolumeGb int
+}
+
 type AutoscalingConfig struct {
 	BalanceRegions bool
 	Enabled        bool
@@ -998,6 +1430,11 @@ type VMSize struct {
 	// MemoryIncrementsMB []int
 }
 
+// PriceHour returns the price of running a VM of this size for an hour.
+func (s *VMSize) PriceHour() float32 {
+	return s.PriceSecond * 3600
+}
+
 type ProcessGroup struct {
 	Name         string
 	Regions      []string
@@ -1219,6 +1656,120 @@ type PostgresEnableConsulPayload struct {
 	ConsulURL string `json:"consulUrl"`
 }
 
+// SentryRelease is a release of the Sentry project of an app.
+type SentryRelease struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// ExternalDatabase is a database hosted outside of Fly.io an app is attached
+// to.
+type ExternalDatabase struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Provider   string    `json:"provider"`
+	Host       string    `json:"host"`
+	Port       int       `json:"port"`
+	SecretName string    `json:"secretName"`
+	Path       string    `json:"path"`
+	Region     string    `json:"region"`
+	CheckedAt  time.Time `json:"checkedAt"`
+}
+
+type AttachExternalDatabaseInput struct {
+	AppID      string     `json:"appId"`
+	Name       string     `json:"name"`
+	Provider   string     `json:"provider"`
+	Host       string     `json:"host"`
+	Port       int        `json:"port"`
+	SecretName string     `json:"secretName"`
+	Path       string     `json:"path"`
+	Region     string     `json:"region,omitempty"`
+	CheckedAt  *time.Time `json:"checkedAt,omitempty"`
+}
+
+// CronJob is a job of an app run on a cron schedule by a scheduled machine.
+type CronJob struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Schedule  string    `json:"schedule"`
+	Command   string    `json:"command"`
+	Size      string    `json:"size"`
+	Image     string    `json:"image"`
+	Region    string    `json:"region"`
+	MachineID string    `json:"machineId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SetCronJobInput struct {
+	AppID     string `json:"appId"`
+	Name      string `json:"name"`
+	Schedule  string `json:"schedule"`
+	Command   string `json:"command"`
+	Size      string `json:"size"`
+	Image     string `json:"image"`
+	Region    string `json:"region"`
+	MachineID string `json:"machineId"`
+}
+
+// AutoscalePolicy scales the machines of a process group of an app between
+// a minimum and a maximum count, keeping a metric near a target. The platform
+// evaluates it, and reports the last value of the metric it saw.
+type AutoscalePolicy struct {
+	ProcessGroup string     `json:"processGroup"`
+	MinCount     int        `json:"minCount"`
+	MaxCount     int        `json:"maxCount"`
+	Metric       string     `json:"metric"`
+	Target       float64    `json:"target"`
+	Query        string     `json:"query,omitempty"`
+	CurrentCount int        `json:"currentCount"`
+	LastValue    *float64   `json:"lastValue,omitempty"`
+	LastScaledAt *time.Time `json:"lastScaledAt,omitempty"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type SetAutoscalePolicyInput struct {
+	AppID        string  `json:"appId"`
+	ProcessGroup string  `json:"processGroup"`
+	MinCount     int     `json:"minCount"`
+	MaxCount     int     `json:"maxCount"`
+	Metric       string  `json:"metric"`
+	Target       float64 `json:"target"`
+	Query        string  `json:"query,omitempty"`
+}
+
+// ReviewApps are the ephemeral apps deploying the pull requests of an app,
+// along with the template their names follow.
+type ReviewApps struct {
+	NameTemplate string      `json:"nameTemplate"`
+	Nodes        []ReviewApp `json:"nodes"`
+}
+
+// ReviewApp is an ephemeral app deploying a pull request of an app.
+type ReviewApp struct {
+	Name        string    `json:"name"`
+	PullRequest int       `json:"pullRequest"`
+	Branch      string    `json:"branch"`
+	PostgresApp string    `json:"postgresApp,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type RecordReviewAppInput struct {
+	AppID        string `json:"appId"`
+	Name         string `json:"name"`
+	PullRequest  int    `json:"pullRequest"`
+	Branch       string `json:"branch,omitempty"`
+	PostgresApp  string `json:"postgresApp,omitempty"`
+	NameTemplate string `json:"nameTemplate,omitempty"`
+}
+
+// ConsulCluster is the managed Consul cluster an app is attached to.
+type ConsulCluster struct {
+	URL    string `json:"url"`
+	Region string `json:"region"`
+}
+
 type EnsureRemoteBuilderInput struct {
 	AppName        *string `json:"appName"`
 	OrganizationID *string `json:"organizationId"`
@@ -1309,4 +1860,46 @@ type AddOn struct {
 	ID            string
 	PrimaryRegion string
 	Organization  *OrganizationBasic
+	Status        string
+	Environment   map[string]interface{}
+	AddOnProvider *AddOnProvider
+	DNSRecords    []AddOnDNSRecord
+	Metrics       *AddOnMetrics
+}
+
+// AddOnMetrics are the metrics of a Redis database, as last reported by its
+// provider.
+type AddOnMetrics struct {
+	UsedMemoryBytes   int64   `json:"usedMemoryBytes"`
+	MaxMemoryBytes    int64   `json:"maxMemoryBytes"`
+	EvictionEnabled   bool    `json:"evictionEnabled"`
+	EvictedKeys       int64   `json:"evictedKeys"`
+	Keys              int64   `json:"keys"`
+	HitRate           float64 `json:"hitRate"`
+	ConnectedClients  int     `json:"connectedClients"`
+	CommandsPerSecond float64 `json:"commandsPerSecond"`
+}
+
+// AddOnProvider is a provider extensions can be provisioned from.
+type AddOnProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+	Type        string `json:"type"`
+}
+
+type CreateAddOnInput struct {
+	Type           string                 `json:"type"`
+	OrganizationID string                 `json:"organizationId"`
+	AppID          string                 `json:"appId,omitempty"`
+	Name           string                 `json:"name,omitempty"`
+	Options        map[string]interface{} `json:"options"`
+}
+
+// AddOnDNSRecord is a DNS record the domain of an add-on must have, like
+// those verifying the sending domain of email.
+type AddOnDNSRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
 }
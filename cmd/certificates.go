@@ -1,14 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/samber/lo"
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/internal/dnsprovider"
 
 	"github.com/superfly/flyctl/docstrings"
 
@@ -31,12 +35,25 @@ func newCertificatesCommand(client *client.Client) *Command {
 	cmd := BuildCommandKS(nil, nil, certsStrings, client, requireAppName, requireSession)
 
 	certsListStrings := docstrings.Get("certs.list")
-	BuildCommandKS(cmd, runCertsList, certsListStrings, client, requireSession, requireAppName)
+	list := BuildCommandKS(cmd, runCertsList, certsListStrings, client, requireSession, requireAppName)
+	list.AddIntFlag(IntFlagOpts{Name: "warn-days", Description: "Fail if a certificate expires within this many days"})
 
 	certsCreateStrings := docstrings.Get("certs.add")
 	createCmd := BuildCommandKS(cmd, runCertAdd, certsCreateStrings, client, requireSession, requireAppName)
 	createCmd.Aliases = []string{"create"}
-	createCmd.Command.Args = cobra.ExactArgs(1)
+	createCmd.Command.Args = cobra.MinimumNArgs(1)
+	createCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "pair",
+		Description: "Also add certificates for the apex domain of wildcard hostnames, and the wildcard of other hostnames",
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "dns-provider",
+		Description: fmt.Sprintf("Create the DNS validation record with this provider's API and wait for issuance. One of %s", strings.Join(dnsprovider.Names, ", ")),
+	})
+	createCmd.AddStringFlag(StringFlagOpts{
+		Name:        "dns-credentials",
+		Description: "Credentials for the DNS provider. Defaults to the provider's usual environment variables",
+	})
 
 	certsDeleteStrings := docstrings.Get("certs.remove")
 	deleteCmd := BuildCommandKS(cmd, runCertDelete, certsDeleteStrings, client, requireSession, requireAppName)
@@ -51,6 +68,7 @@ func newCertificatesCommand(client *client.Client) *Command {
 	certsCheckStrings := docstrings.Get("certs.check")
 	check := BuildCommandKS(cmd, runCertCheck, certsCheckStrings, client, requireSession, requireAppName)
 	check.Command.Args = cobra.ExactArgs(1)
+	check.AddIntFlag(IntFlagOpts{Name: "warn-days", Description: "Fail if the certificate expires within this many days"})
 
 	return cmd
 }
@@ -63,7 +81,50 @@ func runCertsList(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	return printCertificates(commandContext, certs)
+	if err := printCertificates(commandContext, certs); err != nil {
+		return err
+	}
+
+	var expiring []string
+	for _, cert := range certs {
+		if expiresWithin(cert.Issued.Nodes, commandContext.Config.GetInt("warn-days")) {
+			expiring = append(expiring, cert.Hostname)
+		}
+	}
+
+	if len(expiring) > 0 {
+		return fmt.Errorf("certificates expiring within %d days: %s", commandContext.Config.GetInt("warn-days"), strings.Join(expiring, ", "))
+	}
+
+	return nil
+}
+
+// expiresWithin reports whether any of the issued certificates expires within
+// the given number of days. It's always false for 0 days.
+func expiresWithin(issued []api.AppCertificateIssued, days int) bool {
+	if days <= 0 {
+		return false
+	}
+
+	deadline := time.Now().AddDate(0, 0, days)
+	for _, cert := range issued {
+		if cert.ExpiresAt.Before(deadline) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// earliestExpiry returns the time the first of the issued certificates expires.
+func earliestExpiry(issued []api.AppCertificateIssued) (expiry time.Time) {
+	for _, cert := range issued {
+		if expiry.IsZero() || cert.ExpiresAt.Before(expiry) {
+			expiry = cert.ExpiresAt
+		}
+	}
+
+	return
 }
 
 func runCertShow(commandContext *cmdctx.CmdContext) error {
@@ -96,30 +157,124 @@ func runCertCheck(commandContext *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if cert.ClientStatus == "Ready" {
+	if commandContext.OutputJSON() {
+		printCertificate(commandContext, cert)
+	} else if cert.ClientStatus == "Ready" {
 		// A certificate has been issued
 		commandContext.Statusf("certs", cmdctx.SINFO, "The certificate for %s has been issued.\n", hostname)
 		printCertificate(commandContext, cert)
-		// Details should go here
-		return nil
+	} else {
+		commandContext.Statusf("certs", cmdctx.SINFO, "The certificate for %s has not been issued yet.\n", hostname)
+
+		return reportNextStepCert(commandContext, hostname, cert, hostcheck)
 	}
 
-	commandContext.Statusf("certs", cmdctx.SINFO, "The certificate for %s has not been issued yet.\n", hostname)
+	if days := commandContext.Config.GetInt("warn-days"); expiresWithin(cert.Issued.Nodes, days) {
+		return fmt.Errorf("certificate for %s expires within %d days", hostname, days)
+	}
 
-	return reportNextStepCert(commandContext, hostname, cert, hostcheck)
+	return nil
 }
 
 func runCertAdd(commandContext *cmdctx.CmdContext) error {
 	ctx := commandContext.Command.Context()
 
-	hostname := commandContext.Args[0]
+	hostnames := commandContext.Args
+	if commandContext.Config.GetBool("pair") {
+		hostnames = pairHostnames(hostnames)
+	}
 
-	cert, hostcheck, err := commandContext.Client.API().AddCertificate(ctx, commandContext.AppName, hostname)
-	if err != nil {
+	var provider dnsprovider.Provider
+	if name := commandContext.Config.GetString("dns-provider"); name != "" {
+		var err error
+		if provider, err = dnsprovider.New(name, commandContext.Config.GetString("dns-credentials")); err != nil {
+			return err
+		}
+	}
+
+	// each hostname gets a certificate, and DNS configuration, of its own
+	for _, hostname := range hostnames {
+		cert, hostcheck, err := commandContext.Client.API().AddCertificate(ctx, commandContext.AppName, hostname)
+		if err != nil {
+			return err
+		}
+
+		if provider != nil {
+			err = completeDNSValidation(commandContext, provider, cert)
+		} else {
+			err = reportNextStepCert(commandContext, hostname, cert, hostcheck)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pairHostnames adds the apex domain of each wildcard hostname, and the
+// wildcard of every other hostname, keeping the order they were given in.
+func pairHostnames(hostnames []string) []string {
+	var paired []string
+	for _, hostname := range hostnames {
+		if strings.HasPrefix(hostname, "*.") {
+			paired = append(paired, hostname, strings.TrimPrefix(hostname, "*."))
+		} else {
+			paired = append(paired, hostname, "*."+hostname)
+		}
+	}
+
+	return lo.Uniq(paired)
+}
+
+// completeDNSValidation creates the DNS-01 validation record of the certificate
+// with the provider, then waits for it to propagate and for the certificate to
+// be issued.
+func completeDNSValidation(commandContext *cmdctx.CmdContext, provider dnsprovider.Provider, cert *api.AppCertificate) error {
+	ctx := commandContext.Command.Context()
+
+	if cert.DNSValidationHostname == "" || cert.DNSValidationTarget == "" {
+		return fmt.Errorf("no DNS validation record was issued for %s", cert.Hostname)
+	}
+
+	commandContext.Statusf("certs", cmdctx.SINFO, "Creating CNAME record %s pointing at %s\n", cert.DNSValidationHostname, cert.DNSValidationTarget)
+
+	if err := provider.SetCNAME(ctx, cert.DNSValidationHostname, cert.DNSValidationTarget); err != nil {
+		return fmt.Errorf("failed creating validation record: %w", err)
+	}
+
+	commandContext.Statusf("certs", cmdctx.SINFO, "Waiting for the record to propagate\n")
+
+	propagationCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	if err := dnsprovider.WaitForCNAME(propagationCtx, cert.DNSValidationHostname, cert.DNSValidationTarget); err != nil {
 		return err
 	}
 
-	return reportNextStepCert(commandContext, hostname, cert, hostcheck)
+	commandContext.Statusf("certs", cmdctx.SINFO, "Waiting for the certificate to be issued\n")
+
+	issuanceCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	for {
+		checked, _, err := commandContext.Client.API().CheckAppCertificate(issuanceCtx, commandContext.AppName, cert.Hostname)
+		if err != nil {
+			return err
+		}
+
+		if checked.ClientStatus == "Ready" {
+			commandContext.Statusf("certs", cmdctx.STITLE, "The certificate for %s has been issued.\n\n", cert.Hostname)
+			printCertificate(commandContext, checked)
+			return nil
+		}
+
+		select {
+		case <-issuanceCtx.Done():
+			return fmt.Errorf("certificate for %s wasn't issued in time, status is %s; check on it with flyctl certs check", cert.Hostname, checked.ClientStatus)
+		case <-time.After(10 * time.Second):
+		}
+	}
 }
 
 func runCertDelete(commandContext *cmdctx.CmdContext) error {
@@ -128,6 +283,10 @@ func runCertDelete(commandContext *cmdctx.CmdContext) error {
 	hostname := commandContext.Args[0]
 
 	if !commandContext.Config.GetBool("yes") {
+		if err := ensurePrompt("", "--yes"); err != nil {
+			return err
+		}
+
 		confirm := false
 		prompt := &survey.Confirm{
 			Message: fmt.Sprintf("Remove certificate %s from app %s?", hostname, commandContext.AppName),
@@ -312,8 +471,15 @@ func printCertificate(commandContext *cmdctx.CmdContext, cert *api.AppCertificat
 	myprnt("DNS Provider", cert.DNSProvider)
 	myprnt("Certificate Authority", readableCertAuthority(cert.CertificateAuthority))
 	myprnt("Issued", strings.Join(certtypes, ","))
+	if expiry := earliestExpiry(cert.Issued.Nodes); !expiry.IsZero() {
+		myprnt("Expires", humanize.Time(expiry))
+	}
 	myprnt("Added to App", humanize.Time(cert.CreatedAt))
 	myprnt("Source", cert.Source)
+	if n := len(cert.ValidationErrors); n > 0 {
+		last := cert.ValidationErrors[n-1]
+		myprnt("Last Error", fmt.Sprintf("%s (%s)", last.Message, humanize.Time(last.Timestamp)))
+	}
 }
 
 func readableCertAuthority(ca string) string {
@@ -329,12 +495,18 @@ func printCertificates(commandContext *cmdctx.CmdContext, certs []api.AppCertifi
 		return nil
 	}
 
-	commandContext.Statusf("certs", cmdctx.STITLE, "%-25s %-20s %s\n", "Host Name", "Added", "Status")
+	commandContext.Statusf("certs", cmdctx.STITLE, "%-25s %-20s %-20s %s\n", "Host Name", "Added", "Expires", "Status")
 
 	for _, v := range certs {
-		commandContext.Statusf("certs", cmdctx.SINFO, "%-25s %-20s %s\n",
+		expires := "-"
+		if expiry := earliestExpiry(v.Issued.Nodes); !expiry.IsZero() {
+			expires = humanize.Time(expiry)
+		}
+
+		commandContext.Statusf("certs", cmdctx.SINFO, "%-25s %-20s %-20s %s\n",
 			v.Hostname,
 			humanize.Time(v.CreatedAt),
+			expires,
 			v.ClientStatus)
 	}
 
@@ -182,6 +182,8 @@ func BuildCommandCobra(parent *Command, fn RunFn, cmd *cobra.Command, client *cl
 
 	if fn != nil {
 		flycmd.RunE = func(cmd *cobra.Command, args []string) error {
+			flyctl.ApplyMaxRetries()
+
 			ctx, err := cmdctx.NewCmdContext(client, namespace(cmd), cmd, args)
 			if err != nil {
 				return err
@@ -312,7 +314,9 @@ func requireAppName(cmd *Command) Initializer {
 			if ctx.AppConfig.AppName != "" && ctx.AppConfig.AppName != ctx.AppName {
 				terminal.Warnf("app flag '%s' does not match app name in config file '%s'\n", ctx.AppName, ctx.AppConfig.AppName)
 
-				if !confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName)) {
+				if confirmed, err := confirm(fmt.Sprintf("Continue using '%s'", ctx.AppName)); err != nil {
+					return err
+				} else if !confirmed {
 					return flyerr.ErrAbort
 				}
 			}
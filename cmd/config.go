@@ -61,9 +61,9 @@ func runSaveConfig(cmdCtx *cmdctx.CmdContext) error {
 
 	if helpers.FileExists(configfilename) {
 		cmdCtx.Status("create", cmdctx.SERROR, "An existing configuration file has been found.")
-		confirmation := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename))
-		if !confirmation {
-			return nil
+		confirmation, err := confirm(fmt.Sprintf("Overwrite file '%s'", configfilename))
+		if err != nil || !confirmation {
+			return err
 		}
 	}
 
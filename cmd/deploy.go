@@ -39,7 +39,7 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 	if cmdCtx.AppConfig == nil {
 		cfg, err := cmdCtx.Client.API().GetConfig(ctx, cmdCtx.AppName)
 		if err != nil {
-			return fmt.Errorf("unable to fetch existing configuration file: %s", err)
+			return fmt.Errorf("unable to fetch existing configuration file: %w", err)
 		}
 
 		cmdCtx.AppConfig.Definition = cfg.Definition
@@ -57,7 +57,7 @@ func runDeploy(cmdCtx *cmdctx.CmdContext) error {
 	if err != nil {
 		if parsedCfg == nil {
 			// No error data has been returned
-			return fmt.Errorf("not possible to validate configuration: server returned %s", err)
+			return fmt.Errorf("not possible to validate configuration: server returned %w", err)
 		}
 		for _, error := range parsedCfg.Errors {
 			//	fmt.Println("   ", aurora.Red("✘").String(), error)
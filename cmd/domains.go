@@ -33,6 +33,14 @@ func newDomainsCommand(client *client.Client) *Command {
 	registerCmd := BuildCommandKS(cmd, runDomainsRegister, docstrings.Get("domains.register"), client, requireSession)
 	registerCmd.Args = cobra.MaximumNArgs(2)
 
+	attachCmd := BuildCommandKS(cmd, runDomainsAttach, docstrings.Get("domains.attach"), client, requireSession, requireAppName)
+	attachCmd.Args = cobra.ExactArgs(1)
+	attachCmd.AddBoolFlag(BoolFlagOpts{Name: "watch", Description: "Keep checking until the DNS records resolve and the certificate is issued"})
+
+	checkCmd := BuildCommandKS(cmd, runDomainsCheck, docstrings.Get("domains.check"), client, requireSession, requireAppName)
+	checkCmd.Args = cobra.ExactArgs(1)
+	checkCmd.AddBoolFlag(BoolFlagOpts{Name: "watch", Description: "Keep checking until the DNS records resolve and the certificate is issued"})
+
 	return cmd
 }
 
@@ -41,7 +49,7 @@ func runDomainsList(cmdCtx *cmdctx.CmdContext) error {
 
 	var orgSlug string
 	if len(cmdCtx.Args) == 0 {
-		org, err := selectOrganization(ctx, cmdCtx.Client.API(), "")
+		org, err := selectOrganization(ctx, cmdCtx.Client.API(), "", "organization argument")
 		if err != nil {
 			return err
 		}
@@ -125,14 +133,19 @@ func runDomainsCreate(cmdCtx *cmdctx.CmdContext) error {
 	var err error
 
 	if len(cmdCtx.Args) == 0 {
-		org, err = selectOrganization(ctx, cmdCtx.Client.API(), "")
+		org, err = selectOrganization(ctx, cmdCtx.Client.API(), "", "organization argument")
 		if err != nil {
 			return err
 		}
 
+		if err := ensurePrompt("Domain name to add", "domain name argument"); err != nil {
+			return err
+		}
+
 		prompt := &survey.Input{Message: "Domain name to add"}
-		err := survey.AskOne(prompt, &name)
-		checkErr(err)
+		if err := survey.AskOne(prompt, &name); err != nil {
+			return err
+		}
 
 		// TODO: Add some domain validation here
 	} else if len(cmdCtx.Args) == 2 {
@@ -165,14 +178,19 @@ func runDomainsRegister(cmdCtx *cmdctx.CmdContext) error {
 	var err error
 
 	if len(cmdCtx.Args) == 0 {
-		org, err = selectOrganization(ctx, cmdCtx.Client.API(), "")
+		org, err = selectOrganization(ctx, cmdCtx.Client.API(), "", "organization argument")
 		if err != nil {
 			return err
 		}
 
+		if err := ensurePrompt("Domain name to add", "domain name argument"); err != nil {
+			return err
+		}
+
 		prompt := &survey.Input{Message: "Domain name to add"}
-		err := survey.AskOne(prompt, &name)
-		checkErr(err)
+		if err := survey.AskOne(prompt, &name); err != nil {
+			return err
+		}
 		// TODO: Add some domain validation here
 	} else if len(cmdCtx.Args) == 2 {
 		org, err = cmdCtx.Client.API().GetOrganizationBySlug(ctx, cmdCtx.Args[0])
@@ -207,8 +225,8 @@ func runDomainsRegister(cmdCtx *cmdctx.CmdContext) error {
 	fmt.Printf("Registration costs $%s per year and will renew automatically after the first year.\n", formattedCost)
 	fmt.Println("Your account will be charged once the domain is registered. This transaction is non-refundable.")
 
-	if !confirm(fmt.Sprintf("Register %s for $%s?", name, formattedCost)) {
-		return nil
+	if confirmed, err := confirm(fmt.Sprintf("Register %s for $%s?", name, formattedCost)); err != nil || !confirmed {
+		return err
 	}
 
 	fmt.Printf("Registering domain %s in organization %s\n", name, org.Slug)
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/cmdctx"
+)
+
+// dnsRecord is a DNS record a hostname needs to be served by an app, along
+// with what currently resolves for it.
+type dnsRecord struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Required bool   `json:"required"`
+	Current  string `json:"current"`
+	OK       bool   `json:"ok"`
+}
+
+type hostnameStatus struct {
+	Hostname             string      `json:"hostname"`
+	CertificateStatus    string      `json:"certificate_status"`
+	CertificateAuthority string      `json:"certificate_authority"`
+	Records              []dnsRecord `json:"records"`
+}
+
+// ready reports whether the certificate is issued and all required records
+// resolve as expected.
+func (s *hostnameStatus) ready() bool {
+	if s.CertificateStatus != "Ready" {
+		return false
+	}
+
+	for _, record := range s.Records {
+		if record.Required && !record.OK {
+			return false
+		}
+	}
+
+	return true
+}
+
+func runDomainsAttach(cmdCtx *cmdctx.CmdContext) error {
+	ctx := cmdCtx.Command.Context()
+
+	hostname := cmdCtx.Args[0]
+
+	cert, _, err := cmdCtx.Client.API().AddCertificate(ctx, cmdCtx.AppName, hostname)
+	if err != nil {
+		return err
+	}
+
+	if !cmdCtx.OutputJSON() {
+		cmdCtx.Statusf("domains", cmdctx.SINFO, "Attached %s to %s, using %s for its certificate\n\n", hostname, cmdCtx.AppName, readableCertAuthority(cert.CertificateAuthority))
+	}
+
+	return reportHostname(cmdCtx, cert)
+}
+
+func runDomainsCheck(cmdCtx *cmdctx.CmdContext) error {
+	ctx := cmdCtx.Command.Context()
+
+	hostname := cmdCtx.Args[0]
+
+	cert, _, err := cmdCtx.Client.API().CheckAppCertificate(ctx, cmdCtx.AppName, hostname)
+	if err != nil {
+		return err
+	}
+
+	return reportHostname(cmdCtx, cert)
+}
+
+// reportHostname prints the DNS records and certificate status of a hostname,
+// and keeps doing so until everything is in place with --watch.
+func reportHostname(cmdCtx *cmdctx.CmdContext, cert *api.AppCertificate) error {
+	ctx := cmdCtx.Command.Context()
+
+	for {
+		status, err := checkHostname(ctx, cmdCtx.Client.API(), cmdCtx.AppName, cert)
+		if err != nil {
+			return err
+		}
+
+		printHostnameStatus(cmdCtx, status)
+
+		if !cmdCtx.Config.GetBool("watch") || status.ready() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(10 * time.Second):
+		}
+
+		if cert, _, err = cmdCtx.Client.API().CheckAppCertificate(ctx, cmdCtx.AppName, cert.Hostname); err != nil {
+			return err
+		}
+	}
+}
+
+func checkHostname(ctx context.Context, client *api.Client, appName string, cert *api.AppCertificate) (*hostnameStatus, error) {
+	ips, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return nil, err
+	}
+
+	var ipV4, ipV6 string
+	for _, ip := range ips {
+		switch ip.Type {
+		case "v4", "shared_v4":
+			ipV4 = ip.Address
+		case "v6":
+			ipV6 = ip.Address
+		}
+	}
+
+	var records []dnsRecord
+
+	switch {
+	case cert.IsApex || cert.IsWildcard:
+		if ipV4 != "" {
+			records = append(records, dnsRecord{Type: "A", Name: cert.Hostname, Value: ipV4, Required: true})
+		}
+		if ipV6 != "" {
+			records = append(records, dnsRecord{Type: "AAAA", Name: cert.Hostname, Value: ipV6, Required: true})
+		}
+	default:
+		records = append(records, dnsRecord{Type: "CNAME", Name: cert.Hostname, Value: appName + ".fly.dev", Required: true})
+	}
+
+	// the DNS-01 validation record is only needed for wildcards, but lets the
+	// certificate be issued before traffic is pointed at the app otherwise
+	if cert.DNSValidationHostname != "" {
+		records = append(records, dnsRecord{
+			Type:     "CNAME",
+			Name:     cert.DNSValidationHostname,
+			Value:    cert.DNSValidationTarget,
+			Required: cert.IsWildcard,
+		})
+	}
+
+	for i := range records {
+		resolveRecord(ctx, &records[i])
+	}
+
+	return &hostnameStatus{
+		Hostname:             cert.Hostname,
+		CertificateStatus:    cert.ClientStatus,
+		CertificateAuthority: readableCertAuthority(cert.CertificateAuthority),
+		Records:              records,
+	}, nil
+}
+
+// resolveRecord looks up what the record's name currently resolves to.
+func resolveRecord(ctx context.Context, record *dnsRecord) {
+	// wildcard records are checked through a name they cover
+	name := strings.Replace(record.Name, "*", "flyctl-check", 1)
+
+	switch record.Type {
+	case "CNAME":
+		cname, err := net.DefaultResolver.LookupCNAME(ctx, name)
+		if err != nil || strings.TrimSuffix(cname, ".") == strings.TrimSuffix(name, ".") {
+			return
+		}
+
+		record.Current = strings.TrimSuffix(cname, ".")
+		record.OK = strings.EqualFold(record.Current, strings.TrimSuffix(record.Value, "."))
+	case "A", "AAAA":
+		network := "ip4"
+		if record.Type == "AAAA" {
+			network = "ip6"
+		}
+
+		addrs, err := net.DefaultResolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return
+		}
+
+		var current []string
+		for _, addr := range addrs {
+			current = append(current, addr.String())
+			record.OK = record.OK || addr.Equal(net.ParseIP(record.Value))
+		}
+		record.Current = strings.Join(current, ", ")
+	}
+}
+
+func printHostnameStatus(cmdCtx *cmdctx.CmdContext, status *hostnameStatus) {
+	if cmdCtx.OutputJSON() {
+		cmdCtx.WriteJSON(status)
+		return
+	}
+
+	table := tablewriter.NewWriter(cmdCtx.Out)
+	table.SetHeader([]string{"Type", "Name", "Value", "Currently Resolves To", "Status"})
+
+	for _, record := range status.Records {
+		result := "ok"
+		switch {
+		case record.OK:
+		case record.Current == "":
+			result = "missing"
+		default:
+			result = "mismatch"
+		}
+		if !record.Required {
+			result += " (optional)"
+		}
+
+		current := record.Current
+		if current == "" {
+			current = "-"
+		}
+
+		table.Append([]string{record.Type, record.Name, record.Value, current, result})
+	}
+
+	table.Render()
+
+	cmdCtx.Statusf("domains", cmdctx.SINFO, "\nCertificate for %s: %s\n", status.Hostname, status.CertificateStatus)
+
+	if status.ready() {
+		cmdCtx.Statusf("domains", cmdctx.SDONE, "%s is ready\n", status.Hostname)
+	}
+
+	fmt.Fprintln(cmdCtx.Out)
+}
@@ -8,32 +8,49 @@ import (
 	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/scanner"
 )
 
-func confirm(message string) bool {
-	confirm := false
-	prompt := &survey.Confirm{
-		Message: message,
+// ensurePrompt fails with --non-interactive, rather than prompting message.
+// The error names the input the answer may be given with instead, like
+// "--org" or "name argument", if there's one.
+func ensurePrompt(message, input string) error {
+	switch {
+	case !viper.GetBool(flyctl.ConfigNonInteractive):
+		return nil
+	case input != "":
+		return prompt.NonInteractiveError(fmt.Sprintf("%s must be specified with --non-interactive", input))
+	default:
+		return prompt.NonInteractiveError(fmt.Sprintf("can't prompt %q with --non-interactive", strings.TrimSpace(message)))
 	}
-	err := survey.AskOne(prompt, &confirm)
-	checkErr(err)
-
-	return confirm
 }
 
-func confirmOverwrite(filename string) bool {
+func confirm(message string) (bool, error) {
+	if err := ensurePrompt(message, ""); err != nil {
+		return false, err
+	}
+
 	confirm := false
 	prompt := &survey.Confirm{
-		Message: fmt.Sprintf(`Overwrite "%s"?`, filename),
+		Message: message,
 	}
 	err := survey.AskOne(prompt, &confirm)
-	checkErr(err)
 
-	return confirm
+	return confirm, err
+}
+
+func confirmOverwrite(filename string) (bool, error) {
+	return confirm(fmt.Sprintf(`Overwrite "%s"?`, filename))
 }
 
-func selectOrganization(ctx context.Context, client *api.Client, slug string) (*api.Organization, error) {
+// selectOrganization returns the organization of the given slug, or prompts
+// for one when slug is empty. input names what the slug may be given with
+// instead, for --non-interactive to report.
+func selectOrganization(ctx context.Context, client *api.Client, slug, input string) (*api.Organization, error) {
 	orgs, err := client.GetOrganizations(ctx)
 	if err != nil {
 		return nil, err
@@ -62,6 +79,10 @@ func selectOrganization(ctx context.Context, client *api.Client, slug string) (*
 		options = append(options, fmt.Sprintf("%s (%s)", org.Name, org.Slug))
 	}
 
+	if err := ensurePrompt("Select organization:", input); err != nil {
+		return nil, err
+	}
+
 	selectedOrg := 0
 	prompt := &survey.Select{
 		Message:  "Select organization:",
@@ -90,6 +111,10 @@ func selectWireGuardPeer(ctx context.Context, client *api.Client, slug string) (
 		options = append(options, peer.Name)
 	}
 
+	if err := ensurePrompt("Select peer:", "peer name argument"); err != nil {
+		return "", err
+	}
+
 	selectedPeer := 0
 	prompt := &survey.Select{
 		Message:  "Select peer:",
@@ -125,6 +150,10 @@ func selectRegion(ctx context.Context, client *api.Client, regionCode string) (*
 		options = append(options, fmt.Sprintf("%s (%s)", region.Code, region.Name))
 	}
 
+	if err := ensurePrompt("Select region:", "--region"); err != nil {
+		return nil, err
+	}
+
 	selectedRegion := 0
 	prompt := &survey.Select{
 		Message:  "Select region:",
@@ -162,7 +191,11 @@ func selectVMSize(ctx context.Context, client *api.Client, vmSizeName string) (*
 	options := []string{}
 
 	for _, vmSize := range vmSizes {
-		options = append(options, fmt.Sprintf("%s - %d", vmSize.Name, vmSize.MemoryMB))
+		options = append(options, fmt.Sprintf("%s - %d MB - $%.2f/month", vmSize.Name, vmSize.MemoryMB, vmSize.PriceMonth))
+	}
+
+	if err := ensurePrompt("Select VM size:", "--vm-size"); err != nil {
+		return nil, err
 	}
 
 	selectedVMSize := 0
@@ -187,6 +220,10 @@ func inputAppName(defaultName string, autoGenerate bool) (name string, err error
 
 	message += ":"
 
+	if err := ensurePrompt(message, "--name"); err != nil {
+		return name, err
+	}
+
 	prompt := &survey.Input{
 		Message: message,
 		Default: defaultName,
@@ -200,6 +237,10 @@ func inputAppName(defaultName string, autoGenerate bool) (name string, err error
 }
 
 func initialClusterSizeInput(defaultVal int) (int, error) {
+	if err := ensurePrompt("Specify the initial cluster size:", "--initial-cluster-size"); err != nil {
+		return 0, err
+	}
+
 	var count int
 	prompt := &survey.Input{
 		Message: "Specify the initial cluster size:",
@@ -213,6 +254,10 @@ func initialClusterSizeInput(defaultVal int) (int, error) {
 }
 
 func volumeSizeInput(defaultVal int) (int, error) {
+	if err := ensurePrompt("Volume size (GB):", "--volume-size"); err != nil {
+		return 0, err
+	}
+
 	var volumeSize int
 	prompt := &survey.Input{
 		Message: "Volume size (GB):",
@@ -224,3 +269,39 @@ func volumeSizeInput(defaultVal int) (int, error) {
 
 	return volumeSize, nil
 }
+
+func inputDockerfileOptions(options []scanner.DockerfileOption) (map[string]string, error) {
+	values := make(map[string]string, len(options))
+
+	for _, opt := range options {
+		if err := ensurePrompt(opt.Prompt, ""); err != nil {
+			return nil, err
+		}
+
+		var (
+			value  string
+			prompt survey.Prompt
+		)
+
+		if len(opt.Choices) > 0 {
+			prompt = &survey.Select{
+				Message: opt.Prompt,
+				Options: opt.Choices,
+				Default: opt.Default,
+			}
+		} else {
+			prompt = &survey.Input{
+				Message: opt.Prompt,
+				Default: opt.Default,
+			}
+		}
+
+		if err := survey.AskOne(prompt, &value); err != nil {
+			return nil, err
+		}
+
+		values[opt.Name] = value
+	}
+
+	return values, nil
+}
@@ -22,7 +22,10 @@ import (
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/build/imgsrc"
+	"github.com/superfly/flyctl/internal/command/consul"
+	"github.com/superfly/flyctl/internal/command/litefs"
 	"github.com/superfly/flyctl/internal/filemu"
+	"github.com/superfly/flyctl/internal/gpu"
 	"github.com/superfly/flyctl/scanner"
 	"github.com/superfly/flyctl/terminal"
 	"github.com/superfly/graphql"
@@ -83,11 +86,29 @@ func newLaunchCommand(client *client.Client) *Command {
 		Description: "Perform builds remotely without using the local docker daemon",
 		Default:     false,
 	})
+	launchCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "generate-dockerfile",
+		Description: "Generate a Dockerfile with default settings, instead of building with buildpacks, when none exists",
+		Default:     false,
+	})
 	launchCmd.AddBoolFlag(BoolFlagOpts{
 		Name:        "dockerignore-from-gitignore",
 		Description: "If a .dockerignore does not exist create one from .gitignore files",
 		Default:     false,
 	})
+	launchCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "litefs",
+		Description: "Replicate the SQLite databases of the app with LiteFS",
+		Default:     false,
+	})
+	launchCmd.AddStringFlag(StringFlagOpts{
+		Name:        "vm-size",
+		Description: "VM size of the machines of the app, like performance-8x",
+	})
+	launchCmd.AddStringFlag(StringFlagOpts{
+		Name:        "vm-gpu-kind",
+		Description: "Kind of GPU to give the machines of the app, like a100-40gb",
+	})
 
 	return launchCmd
 }
@@ -138,7 +159,16 @@ func runLaunch(cmdCtx *cmdctx.CmdContext) error {
 			cmdCtx.AppName = cfg.AppName
 			cmdCtx.AppConfig = cfg
 			return runDeploy(cmdCtx)
-		} else if cmdCtx.Config.GetBool("copy-config") || confirm("Would you like to copy its configuration to the new app?") {
+		}
+
+		copyConfig := cmdCtx.Config.GetBool("copy-config")
+		if !copyConfig {
+			if copyConfig, err = confirm("Would you like to copy its configuration to the new app?"); err != nil {
+				return err
+			}
+		}
+
+		if copyConfig {
 			appConfig.Definition = cfg.Definition
 			importedConfig = true
 		}
@@ -186,6 +216,36 @@ func runLaunch(cmdCtx *cmdctx.CmdContext) error {
 
 			fmt.Printf("Detected %s %s app\n", article, aurora.Green(appType))
 
+			if srcInfo.Builder != "" && srcInfo.DockerfileGenerator != nil && !helpers.FileExists(filepath.Join(dir, "Dockerfile")) {
+				generate := cmdCtx.Config.GetBool("generate-dockerfile")
+
+				useGenerator := generate
+				if !useGenerator {
+					var err error
+					if useGenerator, err = confirm("Generate a Dockerfile instead of building with buildpacks?"); err != nil {
+						return err
+					}
+				}
+
+				if useGenerator {
+					values := srcInfo.DockerfileGenerator.Defaults()
+
+					if !generate {
+						input, err := inputDockerfileOptions(srcInfo.DockerfileGenerator.Options)
+						if err != nil {
+							return err
+						}
+						values = input
+					}
+
+					fmt.Println("Generating a Dockerfile and .dockerignore")
+
+					srcInfo.Files = append(srcInfo.Files, srcInfo.DockerfileGenerator.Generate(values)...)
+					srcInfo.Builder = ""
+					srcInfo.Buildpacks = nil
+				}
+			}
+
 			if srcInfo.Builder != "" {
 				fmt.Println("Using the following build configuration:")
 				fmt.Println("\tBuilder:", srcInfo.Builder)
@@ -201,12 +261,27 @@ func runLaunch(cmdCtx *cmdctx.CmdContext) error {
 		}
 	}
 
+	if cmdCtx.Config.GetBool("litefs") {
+		if srcInfo == nil {
+			srcInfo = &scanner.SourceInfo{SkipDeploy: true}
+		}
+
+		srcInfo.Files = append(srcInfo.Files, scanner.SourceFile{Path: litefs.ConfigFileName, Contents: litefs.DefaultConfig()})
+		srcInfo.Volumes = append(srcInfo.Volumes, litefs.Volume)
+		srcInfo.Notice += litefs.Notice
+		srcInfo.SkipDatabase = true
+	}
+
 	if srcInfo != nil {
 		for _, f := range srcInfo.Files {
 			path := filepath.Join(dir, f.Path)
 
-			if helpers.FileExists(path) && !confirmOverwrite(path) {
-				continue
+			if helpers.FileExists(path) {
+				if overwrite, err := confirmOverwrite(path); err != nil {
+					return err
+				} else if !overwrite {
+					continue
+				}
 			}
 
 			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
@@ -228,9 +303,17 @@ func runLaunch(cmdCtx *cmdctx.CmdContext) error {
 	dockerIgnore := ".dockerignore"
 	gitIgnore := ".gitignore"
 	allGitIgnores := scanner.FindGitignores(dir)
+	createDockerIgnore := cmdCtx.Config.GetBool("dockerignore-from-gitignore")
+	if !helpers.FileExists(dockerIgnore) && len(allGitIgnores) > 0 && !createDockerIgnore {
+		var err error
+		if createDockerIgnore, err = confirm(fmt.Sprintf("Create %s from %d %s files?", dockerIgnore, len(allGitIgnores), gitIgnore)); err != nil {
+			return err
+		}
+	}
+
 	if helpers.FileExists(dockerIgnore) {
 		terminal.Debugf("Found %s file. Will use when deploying to Fly.\n", dockerIgnore)
-	} else if len(allGitIgnores) > 0 && (cmdCtx.Config.GetBool("dockerignore-from-gitignore") || confirm(fmt.Sprintf("Create %s from %d %s files?", dockerIgnore, len(allGitIgnores), gitIgnore))) {
+	} else if len(allGitIgnores) > 0 && createDockerIgnore {
 		createdDockerIgnore, err := createDockerignoreFromGitignores(dir, allGitIgnores)
 		if err != nil {
 			terminal.Warnf("Error creating %s from %d %s files: %v\n", dockerIgnore, len(allGitIgnores), gitIgnore, err)
@@ -262,7 +345,7 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 		}
 	}
 
-	org, err := selectOrganization(ctx, cmdCtx.Client.API(), orgSlug)
+	org, err := selectOrganization(ctx, cmdCtx.Client.API(), orgSlug, "--org")
 	if err != nil {
 		return err
 	}
@@ -278,6 +361,33 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 		return err
 	}
 
+	vmSize, gpuKind := cmdCtx.Config.GetString("vm-size"), cmdCtx.Config.GetString("vm-gpu-kind")
+	if vmSize != "" {
+		if _, ok := api.MachinePresets[vmSize]; !ok {
+			return fmt.Errorf("unknown VM size %s; fly platform vm-sizes lists them", vmSize)
+		}
+	}
+
+	if gpuKind != "" {
+		guest := *api.MachinePresets["shared-cpu-1x"]
+		if vmSize != "" {
+			guest = *api.MachinePresets[vmSize]
+		}
+		guest.GPUKind = gpuKind
+
+		if err := gpu.Check(ctx, cmdCtx.Client.API(), &guest, region.Code); err != nil {
+			return err
+		}
+
+		image := cmdCtx.Config.GetString("image")
+		if image == "" {
+			image = "the image built from the Dockerfile"
+		}
+		if notice := gpu.Notice(image); notice != "" {
+			fmt.Println(notice)
+		}
+	}
+
 	input := api.CreateAppInput{
 		Name:            appName,
 		OrganizationID:  org.ID,
@@ -296,6 +406,10 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 	appConfig.AppName = app.Name
 	cmdCtx.AppConfig = appConfig
 
+	if vmSize != "" || gpuKind != "" {
+		appConfig.SetVM(vmSize, gpuKind)
+	}
+
 	if srcInfo != nil {
 		if srcInfo.Port > 0 {
 			appConfig.SetInternalPort(srcInfo.Port)
@@ -359,6 +473,9 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 				val = secret.Value
 			} else {
 				prompt := fmt.Sprintf("Set secret %s:", secret.Key)
+				if err := ensurePrompt(prompt, ""); err != nil {
+					return err
+				}
 
 				surveyInput := &survey.Input{
 					Message: prompt,
@@ -383,6 +500,13 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 		}
 	}
 
+	if cmdCtx.Config.GetBool("litefs") {
+		if _, err := consul.Attach(ctx, cmdCtx.Client.API(), cmdCtx.AppName, consul.DefaultVariableName); err != nil {
+			return err
+		}
+		fmt.Printf("Attached %s to Consul for LiteFS to elect its primary\n", cmdCtx.AppName)
+	}
+
 	// If volumes are requested by the launch scanner, create them
 	if srcInfo != nil && len(srcInfo.Volumes) > 0 {
 		for _, vol := range srcInfo.Volumes {
@@ -439,7 +563,15 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 		return nil
 	}
 
-	if !cmdCtx.Config.GetBool("no-deploy") && !cmdCtx.Config.GetBool("now") && !srcInfo.SkipDatabase && confirm("Would you like to set up a Postgresql database now?") {
+	setupDatabase := false
+	if !cmdCtx.Config.GetBool("no-deploy") && !cmdCtx.Config.GetBool("now") && !srcInfo.SkipDatabase {
+		var err error
+		if setupDatabase, err = confirm("Would you like to set up a Postgresql database now?"); err != nil {
+			return err
+		}
+	}
+
+	if setupDatabase {
 
 		appID, err := cmdCtx.Client.API().GetAppID(ctx, cmdCtx.AppName)
 		if err != nil {
@@ -494,10 +626,18 @@ More info at: https://docs.docker.com/engine/reference/builder/#dockerignore-fil
 		fmt.Println(srcInfo.Notice)
 	}
 
-	if !cmdCtx.Config.GetBool("no-deploy") &&
-		!srcInfo.SkipDeploy &&
-		(cmdCtx.Config.GetBool("now") || confirm("Would you like to deploy now?")) {
-		return runDeploy(cmdCtx)
+	if !cmdCtx.Config.GetBool("no-deploy") && !srcInfo.SkipDeploy {
+		deployNow := cmdCtx.Config.GetBool("now")
+		if !deployNow {
+			var err error
+			if deployNow, err = confirm("Would you like to deploy now?"); err != nil {
+				return err
+			}
+		}
+
+		if deployNow {
+			return runDeploy(cmdCtx)
+		}
 	}
 
 	// Alternative deploy documentation if our standard deploy method is not correct
@@ -88,7 +88,7 @@ func runCreatePostgresCluster(cmdCtx *cmdctx.CmdContext) error {
 	}
 
 	orgSlug := cmdCtx.Config.GetString("organization")
-	org, err := selectOrganization(ctx, cmdCtx.Client.API(), orgSlug)
+	org, err := selectOrganization(ctx, cmdCtx.Client.API(), orgSlug, "--org")
 	if err != nil {
 		return err
 	}
@@ -121,6 +121,10 @@ func runCreatePostgresCluster(cmdCtx *cmdctx.CmdContext) error {
 	// configuration.
 	if !customConfig {
 		fmt.Println(aurora.Yellow("For pricing information visit: https://fly.io/docs/about/pricing/#postgresql-clusters"))
+		if err := ensurePrompt("Select configuration:", ""); err != nil {
+			return err
+		}
+
 		selectedCfg := 0
 		options := []string{}
 		for _, cfg := range postgresConfigurations() {
@@ -305,7 +309,7 @@ func runAttachPostgresCluster(cmdCtx *cmdctx.CmdContext) error {
 
 	dialer, err := agentclient.Dialer(ctx, pgApp.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("ssh: can't build tunnel for %s: %s", pgApp.Organization.Slug, err)
+		return fmt.Errorf("ssh: can't build tunnel for %s: %w", pgApp.Organization.Slug, err)
 	}
 
 	instances, err := agentclient.Instances(ctx, pgApp.Organization.Slug, pgApp.Name)
@@ -333,6 +337,10 @@ func runAttachPostgresCluster(cmdCtx *cmdctx.CmdContext) error {
 		return err
 	}
 	if dbExists && !flag.GetBool(ctx, "force") {
+		if err := ensurePrompt("", "--force"); err != nil {
+			return err
+		}
+
 		confirm := false
 		prompt := &survey.Confirm{
 			Message: fmt.Sprintf("Database %q already exists. Continue with the attachment process?", *input.DatabaseName),
@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/cmdctx"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/docstrings"
+	"github.com/superfly/flyctl/internal/command"
 
 	"github.com/spf13/cobra"
 )
@@ -23,6 +26,14 @@ func newRegionsCommand(client *client.Client) *Command {
 		Description: "The process group to add the region to",
 		Default:     "",
 	})
+	addCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "with-volumes",
+		Description: "Create volumes in the regions for the process groups mounting them (machines apps only)",
+	})
+	addCmd.AddStringFlag(StringFlagOpts{
+		Name:        "snapshot-region",
+		Description: "Restore the new volumes from the newest snapshot of the volumes in this region (machines apps only)",
+	})
 
 	removeStrings := docstrings.Get("regions.remove")
 	removeCmd := BuildCommandKS(cmd, runRegionsRemove, removeStrings, client, requireSession, requireAppName)
@@ -55,7 +66,27 @@ func newRegionsCommand(client *client.Client) *Command {
 func runRegionsAdd(cmdCtx *cmdctx.CmdContext) error {
 	ctx := cmdCtx.Command.Context()
 
+	isMachine, err := command.CheckPlatform(cmdCtx.Client.API(), ctx, cmdCtx.AppName)
+	if err != nil {
+		return fmt.Errorf("failed to check platform version %w", err)
+	}
+
 	group := cmdCtx.Config.GetString("group")
+	withVolumes := cmdCtx.Config.GetBool("with-volumes")
+	snapshotRegion := cmdCtx.Config.GetString("snapshot-region")
+
+	if isMachine {
+		if snapshotRegion != "" && !withVolumes {
+			return fmt.Errorf("--snapshot-region requires --with-volumes")
+		}
+
+		return addMachineRegions(cmdCtx, cmdCtx.Args, group, withVolumes, snapshotRegion)
+	}
+
+	if withVolumes || snapshotRegion != "" {
+		return fmt.Errorf("--with-volumes and --snapshot-region are only supported by apps running on machines")
+	}
+
 	input := api.ConfigureRegionsInput{
 		AppID:        cmdCtx.AppName,
 		Group:        group,
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flaps"
+)
+
+// addMachineRegions launches a machine of each process group, or only of
+// group when given, in each of regions it has none in. Groups mounting
+// volumes get volumes in those regions when withVolumes is set, restored from
+// the newest snapshot of their volume in snapshotRegion when it's given.
+func addMachineRegions(cmdCtx *cmdctx.CmdContext, regions []string, group string, withVolumes bool, snapshotRegion string) error {
+	ctx := client.NewContext(cmdCtx.Command.Context(), cmdCtx.Client)
+
+	app, err := cmdCtx.Client.API().GetAppCompact(ctx, cmdCtx.AppName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	byGroup := map[string][]*api.Machine{}
+	for _, machine := range machines {
+		if machine.Config == nil {
+			continue
+		}
+
+		name := "app"
+		if machine.Config.Metadata[api.MachineProcessGroupKey] != "" {
+			name = machine.Config.Metadata[api.MachineProcessGroupKey]
+		}
+		if group != "" && name != group {
+			continue
+		}
+
+		byGroup[name] = append(byGroup[name], machine)
+	}
+
+	if len(byGroup) == 0 {
+		if group != "" {
+			return fmt.Errorf("process group %s has no machines to clone; deploy it first", group)
+		}
+
+		return fmt.Errorf("app %s has no machines to clone; deploy it first", app.Name)
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for name, members := range byGroup {
+		groups = append(groups, name)
+
+		// check every group before changing any
+		if !mountsVolumes(members) {
+			continue
+		}
+		if !withVolumes {
+			return fmt.Errorf("machines of %s mount volumes; pass --with-volumes to create volumes for them in the new regions", name)
+		}
+		if snapshotRegion == "" {
+			continue
+		}
+
+		source := -1
+		for i, machine := range members {
+			if machine.Region == snapshotRegion && len(machine.Config.Mounts) > 0 {
+				source = i
+				break
+			}
+		}
+		if source < 0 {
+			return fmt.Errorf("%s has no machine with a volume in %s to restore snapshots from", name, snapshotRegion)
+		}
+
+		// cloneMachine clones the first machine of a group unless the region
+		// has one, so the machine whose volume is restored goes first
+		members[0], members[source] = members[source], members[0]
+	}
+	sort.Strings(groups)
+
+	volumes := &volumePool{apiClient: cmdCtx.Client.API(), app: app, restore: snapshotRegion != ""}
+
+	for _, name := range groups {
+		current := countByRegion(byGroup[name])
+
+		for _, region := range regions {
+			if current[region] > 0 {
+				fmt.Fprintf(cmdCtx.Out, "Skipped %s in %s, it already runs there\n", name, region)
+
+				continue
+			}
+
+			machine, err := cloneMachine(ctx, flapsClient, volumes, app.Name, byGroup[name], region, true)
+			if err != nil {
+				return fmt.Errorf("failed creating a machine of %s in %s: %w", name, region, err)
+			}
+
+			fmt.Fprintf(cmdCtx.Out, "Created machine %s of %s in %s\n", machine.ID, name, region)
+		}
+	}
+
+	return nil
+}
+
+func mountsVolumes(machines []*api.Machine) bool {
+	for _, machine := range machines {
+		if len(machine.Config.Mounts) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
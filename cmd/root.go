@@ -9,6 +9,7 @@ import (
 	"github.com/logrusorgru/aurora"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/flyctl"
@@ -36,6 +37,18 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	err := viper.BindPFlag(flyctl.ConfigAPIToken, rootCmd.PersistentFlags().Lookup("access-token"))
 	checkErr(err)
 
+	rootCmd.PersistentFlags().String("context", "", "Name of the config context to use")
+
+	rootCmd.PersistentFlags().Int("max-retries", api.DefaultMaxRetries, "How many times API requests are retried after rate limiting and transient errors")
+	err = viper.BindPFlag(flyctl.ConfigMaxRetries, rootCmd.PersistentFlags().Lookup("max-retries"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("non-interactive", false, "Fail, rather than prompt, when input is missing")
+	err = viper.BindPFlag(flyctl.ConfigNonInteractive, rootCmd.PersistentFlags().Lookup("non-interactive"))
+	checkErr(err)
+
+	rootCmd.PersistentFlags().Bool("json-errors", false, "Report failures as JSON on stderr, with a stable error code")
+
 	rootCmd.PersistentFlags().Bool("verbose", false, "verbose output")
 	err = viper.BindPFlag(flyctl.ConfigVerboseOutput, rootCmd.PersistentFlags().Lookup("verbose"))
 	checkErr(err)
@@ -59,7 +72,6 @@ func NewRootCmd(client *client.Client) *cobra.Command {
 	rootCmd.AddCommand(
 		newCertificatesCommand(client),
 		newConfigCommand(client),
-		newDashboardCommand(client),
 		newInfoCommand(client),
 		newListCommand(client),
 		newRegionsCommand(client),
@@ -1,10 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/cmdctx"
@@ -13,6 +13,7 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/docstrings"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 )
 
@@ -34,6 +35,14 @@ func newScaleCommand(client *client.Client) *Command {
 		Description: "The process group to apply the VM size to",
 		Default:     "",
 	})
+	vmCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "region",
+		Description: "Only resize the machines in these regions, for apps running on machines",
+	})
+	vmCmd.AddStringFlag(StringFlagOpts{
+		Name:        "vm-gpu-kind",
+		Description: "Kind of GPU to give the machines, like a100-40gb, for apps running on machines",
+	})
 
 	memoryCmdStrings := docstrings.Get("scale.memory")
 	memoryCmd := BuildCommandKS(cmd, runScaleMemory, memoryCmdStrings, client, requireSession, requireAppName)
@@ -43,6 +52,10 @@ func newScaleCommand(client *client.Client) *Command {
 		Description: "The process group to apply the memory size to",
 		Default:     "",
 	})
+	memoryCmd.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "region",
+		Description: "Only resize the machines in these regions, for apps running on machines",
+	})
 
 	countCmdStrings := docstrings.Get("scale.count")
 	countCmd := BuildCommand(cmd, runScaleCount, countCmdStrings.Usage, countCmdStrings.Short, countCmdStrings.Long, client, requireSession, requireAppName)
@@ -52,6 +65,16 @@ func newScaleCommand(client *client.Client) *Command {
 		Description: "Max number of VMs per region",
 		Default:     -1,
 	}))
+	countCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "no-create-volumes",
+		Description: "Do not create the volumes missing for the mounts of the scaled process groups",
+		Default:     false,
+	})
+	countCmd.AddBoolFlag(BoolFlagOpts{
+		Name:        "yes",
+		Shorthand:   "y",
+		Description: "Accept the changes without confirming them",
+	})
 
 	showCmdStrings := docstrings.Get("scale.show")
 	BuildCommand(cmd, runScaleShow, showCmdStrings.Usage, showCmdStrings.Short, showCmdStrings.Long, client, requireSession, requireAppName)
@@ -68,16 +91,28 @@ func runScaleVM(cmdCtx *cmdctx.CmdContext) error {
 		return fmt.Errorf("failed to check platform version %w", err)
 	}
 
-	if isMachine {
-		return fmt.Errorf("it looks like your app is running on v2 of our platform, and does not support this legacy command: try running fly machine update instead")
-	}
-
 	sizeName := cmdCtx.Args[0]
 
 	memoryMB := int64(cmdCtx.Config.GetInt("memory"))
 
 	group := cmdCtx.Config.GetString("group")
 
+	regions := cmdCtx.Config.GetStringSlice("region")
+
+	gpuKind := cmdCtx.Config.GetString("vm-gpu-kind")
+
+	if isMachine {
+		return resizeMachines(cmdCtx, sizeName, int(memoryMB), gpuKind, group, regions)
+	}
+
+	if len(regions) > 0 {
+		return fmt.Errorf("--region is only supported by apps running on machines")
+	}
+
+	if gpuKind != "" {
+		return fmt.Errorf("--vm-gpu-kind is only supported by apps running on machines")
+	}
+
 	size, err := cmdCtx.Client.API().SetAppVMSize(ctx, cmdCtx.AppName, group, sizeName, memoryMB)
 	if err != nil {
 		return err
@@ -102,46 +137,48 @@ func runScaleCount(cmdCtx *cmdctx.CmdContext) error {
 		return fmt.Errorf("failed to check platform version %w", err)
 	}
 
-	if isMachine {
-		return fmt.Errorf("it looks like your app is running on v2 of our platform, and does not support this legacy command: try running fly machine clone instead")
+	targets, err := parseScaleCounts(cmdCtx.Args)
+	if err != nil {
+		return err
 	}
 
-	groups := map[string]int{}
+	// THIS IS AN OPTION TYPE CAN YOU TELL?
+	maxPerRegionRaw := cmdCtx.Config.GetInt("max-per-region")
 
-	// single numeric arg: fly scale count 3
-	if len(cmdCtx.Args) == 1 {
-		count, err := strconv.Atoi(cmdCtx.Args[0])
-		if err == nil {
-			groups["app"] = count
+	if isMachine {
+		if maxPerRegionRaw == -1 {
+			maxPerRegionRaw = 0
 		}
-	}
 
-	// group labels: fly scale web=X worker=Y
-	if len(groups) < 1 {
-		for _, arg := range cmdCtx.Args {
-			parts := strings.Split(arg, "=")
-			if len(parts) != 2 {
-				return fmt.Errorf("%s is not a valid process=count option", arg)
-			}
-			count, err := strconv.Atoi(parts[1])
-			if err != nil {
-				return err
-			}
+		return scaleMachines(cmdCtx, targets, maxPerRegionRaw, !cmdCtx.Config.GetBool("no-create-volumes"))
+	}
 
-			groups[parts[0]] = count
+	groups := map[string]int{}
+	for group, counts := range targets {
+		count, ok := counts[""]
+		if !ok || len(counts) > 1 {
+			return fmt.Errorf("counts per region, like %s=2:fra, are only supported by apps running on machines", group)
 		}
+
+		groups[group] = count
 	}
 
-	// THIS IS AN OPTION TYPE CAN YOU TELL?
-	maxPerRegionRaw := cmdCtx.Config.GetInt("max-per-region")
 	maxPerRegion := &maxPerRegionRaw
 
 	if maxPerRegionRaw == -1 {
 		maxPerRegion = nil
 	}
 
+	var created []*api.Volume
+	if !cmdCtx.Config.GetBool("no-create-volumes") {
+		if created, err = createMissingVolumes(cmdCtx, groups); err != nil {
+			return err
+		}
+	}
+
 	counts, warnings, err := cmdCtx.Client.API().SetAppVMCount(ctx, cmdCtx.AppName, groups, maxPerRegion)
 	if err != nil {
+		deleteVolumes(cmdCtx, created)
 		return err
 	}
 
@@ -185,6 +222,161 @@ func runScaleShow(cmdCtx *cmdctx.CmdContext) error {
 	return nil
 }
 
+type configMount struct {
+	Source    string
+	Processes []string
+}
+
+// createMissingVolumes creates the volumes the process groups being scaled
+// need for the mounts they declare, spreading them over the app's regions
+// and sizing them like the existing volumes of each mount. The volumes it
+// created are deleted should it fail.
+func createMissingVolumes(cmdCtx *cmdctx.CmdContext, groups map[string]int) (created []*api.Volume, err error) {
+	ctx := cmdCtx.Command.Context()
+	apiClient := cmdCtx.Client.API()
+
+	defer func() {
+		if err != nil {
+			deleteVolumes(cmdCtx, created)
+			created = nil
+		}
+	}()
+
+	cfg, err := apiClient.GetConfig(ctx, cmdCtx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts := parseConfigMounts(cfg.Definition["mounts"])
+	if len(mounts) == 0 {
+		return nil, nil
+	}
+
+	volumes, err := apiClient.GetVolumes(ctx, cmdCtx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	regions, _, err := apiClient.ListAppRegions(ctx, cmdCtx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(regions) == 0 {
+		return nil, nil
+	}
+
+	appID, err := apiClient.GetAppID(ctx, cmdCtx.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mount := range mounts {
+		var required int
+		for group, count := range groups {
+			if len(mount.Processes) == 0 || lo.Contains(mount.Processes, group) {
+				required += count
+			}
+		}
+
+		sizeGb := 3
+		perRegion := map[string]int{}
+		var existing int
+
+		for _, volume := range volumes {
+			if volume.Name != mount.Source {
+				continue
+			}
+
+			existing++
+			perRegion[volume.Region]++
+			if volume.SizeGb > sizeGb {
+				sizeGb = volume.SizeGb
+			}
+		}
+
+		for ; existing < required; existing++ {
+			// place each volume in the region with the fewest so far
+			region := regions[0].Code
+			for _, r := range regions {
+				if perRegion[r.Code] < perRegion[region] {
+					region = r.Code
+				}
+			}
+
+			volume, err := apiClient.CreateVolume(ctx, api.CreateVolumeInput{
+				AppID:             appID,
+				Name:              mount.Source,
+				Region:            region,
+				SizeGb:            sizeGb,
+				Encrypted:         true,
+				RequireUniqueZone: true,
+			})
+			if err != nil {
+				return created, fmt.Errorf("failed creating volume %s in region %s: %w", mount.Source, region, err)
+			}
+
+			created = append(created, volume)
+			perRegion[region]++
+
+			fmt.Printf("Created %dGB volume %s (%s) in region %s\n", volume.SizeGb, volume.Name, volume.ID, region)
+		}
+	}
+
+	return created, nil
+}
+
+// deleteVolumes deletes the volumes createMissingVolumes created, reporting
+// those which couldn't be.
+func deleteVolumes(cmdCtx *cmdctx.CmdContext, volumes []*api.Volume) {
+	for _, volume := range volumes {
+		if _, err := cmdCtx.Client.API().DeleteVolume(context.Background(), volume.ID); err != nil {
+			fmt.Fprintf(cmdCtx.IO.ErrOut, "failed deleting volume %s (%s): %v\n", volume.Name, volume.ID, err)
+			continue
+		}
+
+		fmt.Printf("Deleted volume %s (%s)\n", volume.Name, volume.ID)
+	}
+}
+
+// parseConfigMounts reads the mounts section of an app config definition,
+// which may be a single table or an array of them.
+func parseConfigMounts(raw interface{}) (mounts []configMount) {
+	var tables []interface{}
+
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		tables = append(tables, raw)
+	case []interface{}:
+		tables = raw
+	}
+
+	for _, table := range tables {
+		table, ok := table.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		source, _ := table["source"].(string)
+		if source == "" {
+			continue
+		}
+
+		mount := configMount{Source: source}
+		if processes, ok := table["processes"].([]interface{}); ok {
+			for _, p := range processes {
+				if p, ok := p.(string); ok {
+					mount.Processes = append(mount.Processes, p)
+				}
+			}
+		}
+
+		mounts = append(mounts, mount)
+	}
+
+	return
+}
+
 func countMessage(counts []api.TaskGroupCount) string {
 	msg := ""
 
@@ -277,15 +469,21 @@ func runScaleMemory(cmdCtx *cmdctx.CmdContext) error {
 		return fmt.Errorf("failed to check platform version %w", err)
 	}
 
-	if isMachine {
-		return fmt.Errorf("it looks like your app is running on v2 of our platform, and does not support this legacy command: try running fly machine update instead")
-	}
-
 	memoryMB, err := strconv.ParseInt(cmdCtx.Args[0], 10, 64)
 	if err != nil {
 		return err
 	}
 
+	regions := cmdCtx.Config.GetStringSlice("region")
+
+	if isMachine {
+		return resizeMachines(cmdCtx, "", int(memoryMB), "", cmdCtx.Config.GetString("group"), regions)
+	}
+
+	if len(regions) > 0 {
+		return fmt.Errorf("--region is only supported by apps running on machines")
+	}
+
 	// API doesn't allow memory setting on own yet, so get get the current size for the mutation
 	currentsize, _, _, err := cmdCtx.Client.API().AppVMResources(ctx, cmdCtx.AppName)
 	if err != nil {
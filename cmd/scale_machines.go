@@ -0,0 +1,678 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/cmdctx"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/gpu"
+	"github.com/superfly/flyctl/terminal"
+)
+
+// parseScaleCounts parses the arguments of scale count, like 3, web=3 or
+// web=3:fra,2:ams, into the counts of each process group by region. Counts
+// without a region are keyed by an empty one.
+func parseScaleCounts(args []string) (map[string]map[string]int, error) {
+	targets := map[string]map[string]int{}
+
+	// single numeric arg: fly scale count 3
+	if len(args) == 1 {
+		if count, err := strconv.Atoi(args[0]); err == nil {
+			targets["app"] = map[string]int{"": count}
+
+			return targets, nil
+		}
+	}
+
+	// group labels: fly scale count web=3:fra,2:ams worker=1
+	for _, arg := range args {
+		group, spec, ok := strings.Cut(arg, "=")
+		if !ok || group == "" {
+			return nil, fmt.Errorf("%s is not a valid process=count option", arg)
+		}
+
+		counts := map[string]int{}
+		for _, item := range strings.Split(spec, ",") {
+			value, region, _ := strings.Cut(item, ":")
+
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 0 {
+				return nil, fmt.Errorf("%s is not a valid count of %s", value, group)
+			}
+
+			if _, dup := counts[region]; dup {
+				return nil, fmt.Errorf("%s has more than one count for region %s", group, region)
+			}
+			counts[region] = count
+		}
+
+		if _, ok := counts[""]; ok && len(counts) > 1 {
+			return nil, fmt.Errorf("counts of %s must all have a region, or be a single one without", group)
+		}
+
+		targets[group] = counts
+	}
+
+	return targets, nil
+}
+
+// scaleMachines creates and destroys the machines of the process groups of
+// targets to match their counts by region, capped at maxPerRegion in any
+// region unless it's 0. The changes are printed, and confirmed unless --yes
+// is set, before any is made. Machines are cloned from those of their group, with
+// volumes for their mounts, which are created unless createVolumes is unset.
+func scaleMachines(cmdCtx *cmdctx.CmdContext, targets map[string]map[string]int, maxPerRegion int, createVolumes bool) error {
+	ctx := client.NewContext(cmdCtx.Command.Context(), cmdCtx.Client)
+	apiClient := cmdCtx.Client.API()
+
+	app, err := apiClient.GetAppCompact(ctx, cmdCtx.AppName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	byGroup := map[string][]*api.Machine{}
+	for _, machine := range machines {
+		group := "app"
+		if machine.Config != nil && machine.Config.Metadata[api.MachineProcessGroupKey] != "" {
+			group = machine.Config.Metadata[api.MachineProcessGroupKey]
+		}
+		byGroup[group] = append(byGroup[group], machine)
+	}
+
+	// plan every group before changing any, so invalid counts change nothing
+	plans := map[string]map[string]int{}
+	for group, counts := range targets {
+		if len(byGroup[group]) == 0 {
+			return fmt.Errorf("process group %s has no machines to clone; deploy it first", group)
+		}
+
+		plan, err := planRegionCounts(byGroup[group], counts, maxPerRegion)
+		if err != nil {
+			return fmt.Errorf("can't scale %s: %w", group, err)
+		}
+		plans[group] = plan
+	}
+
+	groups := make([]string, 0, len(plans))
+	for group := range plans {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	var changes []string
+	for _, group := range groups {
+		current := countByRegion(byGroup[group])
+
+		for _, region := range sortedRegions(plans[group], current) {
+			if want, have := plans[group][region], current[region]; want != have {
+				changes = append(changes, fmt.Sprintf("  %s in %s: %d -> %d machines", group, region, have, want))
+			}
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Fprintf(cmdCtx.Out, "App %s already has these counts; nothing to change\n", app.Name)
+
+		return nil
+	}
+
+	fmt.Fprintf(cmdCtx.Out, "Scaling app %s:\n%s\n", app.Name, strings.Join(changes, "\n"))
+
+	if !cmdCtx.Config.GetBool("yes") {
+		if err := ensurePrompt("", "--yes"); err != nil {
+			return err
+		}
+
+		confirm := false
+		if err := survey.AskOne(&survey.Confirm{Message: "Apply these changes?"}, &confirm); err != nil {
+			return err
+		}
+
+		if !confirm {
+			return nil
+		}
+	}
+
+	volumes := &volumePool{apiClient: apiClient, app: app}
+
+	var (
+		summary     []string
+		keptVolumes bool
+	)
+	for _, group := range groups {
+		current := countByRegion(byGroup[group])
+
+		for _, region := range sortedRegions(plans[group], current) {
+			want, have := plans[group][region], current[region]
+
+			for ; have < want; have++ {
+				machine, err := cloneMachine(ctx, flapsClient, volumes, app.Name, byGroup[group], region, createVolumes)
+				if err != nil {
+					return fmt.Errorf("failed creating a machine of %s in %s: %w", group, region, err)
+				}
+
+				fmt.Fprintf(cmdCtx.Out, "Created machine %s of %s in %s\n", machine.ID, group, region)
+			}
+
+			for _, machine := range surplus(byGroup[group], region, have-want) {
+				if err := stopAndDestroy(ctx, flapsClient, app.Name, machine); err != nil {
+					return fmt.Errorf("failed destroying machine %s of %s in %s: %w", machine.ID, group, region, err)
+				}
+
+				fmt.Fprintf(cmdCtx.Out, "Destroyed machine %s of %s in %s\n", machine.ID, group, region)
+
+				keptVolumes = keptVolumes || (machine.Config != nil && len(machine.Config.Mounts) > 0)
+			}
+
+			if want > 0 {
+				summary = append(summary, fmt.Sprintf("%s=%d:%s", group, want, region))
+			}
+		}
+	}
+
+	if keptVolumes {
+		fmt.Fprintf(cmdCtx.Out, "Volumes of destroyed machines are kept; fly volumes list shows them\n")
+	}
+
+	if len(summary) == 0 {
+		summary = append(summary, "0")
+	}
+
+	fmt.Fprintf(cmdCtx.Out, "Count changed to %s\n", strings.Join(summary, " "))
+
+	return nil
+}
+
+// stopAndDestroy stops machine, when it's started, waiting for it to exit
+// before destroying it, so its processes get to shut down cleanly.
+func stopAndDestroy(ctx context.Context, flapsClient *flaps.Client, appName string, machine *api.Machine) error {
+	if machine.State == "started" {
+		if err := flapsClient.Stop(ctx, api.StopMachineInput{ID: machine.ID, Filters: &api.Filters{}}); err != nil {
+			return err
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		err := flapsClient.Wait(waitCtx, machine, "stopped")
+		cancel()
+
+		if err != nil {
+			return fmt.Errorf("machine didn't stop: %w", err)
+		}
+	}
+
+	return flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: appName, ID: machine.ID})
+}
+
+// planRegionCounts returns the count of machines of a group in each region
+// that matches counts. Regions counts don't give keep the machines they have.
+// A count without a region is spread over the regions the group runs in,
+// keeping its machines where they are as far as it can.
+func planRegionCounts(machines []*api.Machine, counts map[string]int, maxPerRegion int) (map[string]int, error) {
+	plan := countByRegion(machines)
+
+	total, spread := counts[""]
+	if !spread {
+		for region, count := range counts {
+			if maxPerRegion > 0 && count > maxPerRegion {
+				return nil, fmt.Errorf("%d machines in %s exceed --max-per-region %d", count, region, maxPerRegion)
+			}
+
+			plan[region] = count
+		}
+
+		return plan, nil
+	}
+
+	regions := make([]string, 0, len(plan))
+	for region := range plan {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	have := 0
+	for _, count := range plan {
+		have += count
+	}
+
+	// trim regions above the cap first, then add to the emptiest regions and
+	// remove from the fullest
+	if maxPerRegion > 0 {
+		for _, region := range regions {
+			if plan[region] > maxPerRegion {
+				have -= plan[region] - maxPerRegion
+				plan[region] = maxPerRegion
+			}
+		}
+	}
+
+	for ; have < total; have++ {
+		emptiest := ""
+		for _, region := range regions {
+			if (maxPerRegion == 0 || plan[region] < maxPerRegion) && (emptiest == "" || plan[region] < plan[emptiest]) {
+				emptiest = region
+			}
+		}
+
+		if emptiest == "" {
+			return nil, fmt.Errorf("%d machines don't fit %d regions with --max-per-region %d; give counts per region, like 2:fra", total, len(regions), maxPerRegion)
+		}
+
+		plan[emptiest]++
+	}
+
+	for ; have > total; have-- {
+		fullest := regions[0]
+		for _, region := range regions {
+			if plan[region] > plan[fullest] {
+				fullest = region
+			}
+		}
+
+		plan[fullest]--
+	}
+
+	return plan, nil
+}
+
+func countByRegion(machines []*api.Machine) map[string]int {
+	counts := map[string]int{}
+	for _, machine := range machines {
+		counts[machine.Region]++
+	}
+
+	return counts
+}
+
+// sortedRegions returns the regions of plan and current, sorted.
+func sortedRegions(plan, current map[string]int) []string {
+	var regions []string
+	for region := range plan {
+		regions = append(regions, region)
+	}
+	for region := range current {
+		if _, ok := plan[region]; !ok {
+			regions = append(regions, region)
+		}
+	}
+	sort.Strings(regions)
+
+	return regions
+}
+
+// surplus returns n machines of region to destroy, preferring those which
+// aren't started, then the most recently created.
+func surplus(machines []*api.Machine, region string, n int) []*api.Machine {
+	if n <= 0 {
+		return nil
+	}
+
+	var candidates []*api.Machine
+	for _, machine := range machines {
+		if machine.Region == region {
+			candidates = append(candidates, machine)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if started := candidates[i].State == "started"; started != (candidates[j].State == "started") {
+			return !started
+		}
+
+		return candidates[i].CreatedAt > candidates[j].CreatedAt
+	})
+
+	return candidates[:n]
+}
+
+// cloneMachine launches a machine in region with the config of a machine of
+// the group, preferably one of the same region, and volumes for its mounts.
+func cloneMachine(ctx context.Context, flapsClient *flaps.Client, volumes *volumePool, appName string, group []*api.Machine, region string, createVolumes bool) (*api.Machine, error) {
+	source := group[0]
+	for _, machine := range group {
+		if machine.Region == region {
+			source = machine
+			break
+		}
+	}
+
+	source, err := flapsClient.Get(ctx, source.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	config := *source.Config
+	config.Mounts = nil
+
+	for _, mount := range source.Config.Mounts {
+		volume, err := volumes.take(ctx, mount, region, createVolumes)
+		if err != nil {
+			volumes.giveBack(config.Mounts)
+			return nil, err
+		}
+
+		mount.Volume = volume.ID
+		mount.SizeGb = volume.SizeGb
+		config.Mounts = append(config.Mounts, mount)
+	}
+
+	machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:  appName,
+		Region: region,
+		Config: &config,
+	})
+	if err != nil {
+		volumes.giveBack(config.Mounts)
+		return nil, err
+	}
+
+	return machine, nil
+}
+
+// volumePool hands out the volumes of an app to the machines being created,
+// reusing unattached volumes before creating new ones. Volumes are created
+// from the newest snapshot of the volume being cloned when restore is set.
+type volumePool struct {
+	apiClient *api.Client
+	app       *api.AppCompact
+	restore   bool
+	volumes   []api.Volume
+	loaded    bool
+	taken     map[string]bool
+	created   map[string]bool
+}
+
+// take returns a volume in region like the one of mount.
+func (p *volumePool) take(ctx context.Context, mount api.MachineMount, region string, create bool) (*api.Volume, error) {
+	if !p.loaded {
+		volumes, err := p.apiClient.GetVolumes(ctx, p.app.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving volumes: %w", err)
+		}
+
+		p.volumes, p.loaded, p.taken, p.created = volumes, true, map[string]bool{}, map[string]bool{}
+	}
+
+	name := ""
+	for _, volume := range p.volumes {
+		if volume.ID == mount.Volume {
+			name = volume.Name
+		}
+	}
+	if name == "" {
+		return nil, fmt.Errorf("volume %s of the machine being cloned wasn't found", mount.Volume)
+	}
+
+	for i := range p.volumes {
+		volume := &p.volumes[i]
+
+		if volume.Name == name && volume.Region == region && volume.AttachedMachine == nil && volume.AttachedAllocation == nil && !p.taken[volume.ID] {
+			p.taken[volume.ID] = true
+
+			return volume, nil
+		}
+	}
+
+	if !create {
+		return nil, fmt.Errorf("no unattached volume %s is in %s, and --no-create-volumes is set", name, region)
+	}
+
+	input := api.CreateVolumeInput{
+		AppID:             p.app.ID,
+		Name:              name,
+		Region:            region,
+		SizeGb:            mount.SizeGb,
+		Encrypted:         mount.Encrypted,
+		RequireUniqueZone: true,
+	}
+
+	if p.restore {
+		snapshot, err := p.newestSnapshot(ctx, mount.Volume)
+		if err != nil {
+			return nil, err
+		}
+
+		input.SnapshotID = &snapshot.ID
+	}
+
+	volume, err := p.apiClient.CreateVolume(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating volume %s in %s: %w", name, region, err)
+	}
+
+	p.taken[volume.ID] = true
+	p.created[volume.ID] = true
+
+	return volume, nil
+}
+
+// giveBack returns the volumes of mounts to the pool, once the machine they
+// were taken for couldn't be created. Those created for it are deleted, so
+// they're not left behind unattached.
+func (p *volumePool) giveBack(mounts []api.MachineMount) {
+	for _, mount := range mounts {
+		delete(p.taken, mount.Volume)
+
+		if !p.created[mount.Volume] {
+			continue
+		}
+
+		if _, err := p.apiClient.DeleteVolume(context.Background(), mount.Volume); err != nil {
+			terminal.Warnf("failed deleting volume %s: %v\n", mount.Volume, err)
+			continue
+		}
+
+		delete(p.created, mount.Volume)
+	}
+}
+
+// newestSnapshot returns the newest snapshot of the volume volumeID.
+func (p *volumePool) newestSnapshot(ctx context.Context, volumeID string) (*api.Snapshot, error) {
+	snapshots, err := p.apiClient.GetVolumeSnapshots(ctx, volumeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving snapshots of volume %s: %w", volumeID, err)
+	}
+
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("volume %s has no snapshots to restore from", volumeID)
+	}
+
+	newest := &snapshots[0]
+	for i := range snapshots {
+		if snapshots[i].CreatedAt.After(newest.CreatedAt) {
+			newest = &snapshots[i]
+		}
+	}
+
+	return newest, nil
+}
+
+// resizeMachines updates the machines of the app, only those of group and
+// regions when given, to the VM size sizeName with memoryMB of memory and a
+// GPU of gpuKind. Any may be left empty to keep what each machine has.
+// Machines are updated one at a time, and those which were started are waited
+// on to start again before the next is updated.
+func resizeMachines(cmdCtx *cmdctx.CmdContext, sizeName string, memoryMB int, gpuKind string, group string, regions []string) error {
+	ctx := client.NewContext(cmdCtx.Command.Context(), cmdCtx.Client)
+
+	var preset *api.MachineGuest
+	if sizeName != "" {
+		var ok bool
+		if preset, ok = api.MachinePresets[sizeName]; !ok {
+			sizes := make([]string, 0, len(api.MachinePresets))
+			for size := range api.MachinePresets {
+				sizes = append(sizes, size)
+			}
+			sort.Strings(sizes)
+
+			return fmt.Errorf("unknown VM size %s, must be one of %s", sizeName, strings.Join(sizes, ", "))
+		}
+	}
+
+	app, err := cmdCtx.Client.API().GetAppCompact(ctx, cmdCtx.AppName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	machines = selectMachines(machines, group, regions)
+	if len(machines) == 0 {
+		return fmt.Errorf("no machines match the process group and regions given")
+	}
+
+	if gpuKind != "" {
+		checked := map[string]bool{}
+		for _, machine := range machines {
+			if checked[machine.Region] {
+				continue
+			}
+			checked[machine.Region] = true
+
+			guest, _ := resizedGuest(machine, preset, memoryMB, gpuKind)
+			if err := gpu.Check(ctx, cmdCtx.Client.API(), &guest, machine.Region); err != nil {
+				return err
+			}
+		}
+
+		if notice := gpu.Notice(machines[0].Config.Image); notice != "" {
+			fmt.Fprintln(cmdCtx.Out, notice)
+		}
+	}
+
+	var resized int
+	for _, machine := range machines {
+		guest, current := resizedGuest(machine, preset, memoryMB, gpuKind)
+		if guest.CPUKind == current.CPUKind && guest.CPUs == current.CPUs && guest.MemoryMB == current.MemoryMB && guest.GPUKind == current.GPUKind {
+			continue
+		}
+
+		config := *machine.Config
+		config.Guest = &guest
+
+		updated, err := flapsClient.Update(ctx, api.LaunchMachineInput{
+			ID:     machine.ID,
+			AppID:  app.Name,
+			Name:   machine.Name,
+			Region: machine.Region,
+			Config: &config,
+		}, "")
+		if err != nil {
+			return fmt.Errorf("failed updating machine %s, after %d of %d were resized: %w", machine.ID, resized, len(machines), err)
+		}
+
+		if machine.State == "started" {
+			waitCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			err := flapsClient.Wait(waitCtx, updated, "started")
+			cancel()
+
+			if err != nil {
+				return fmt.Errorf("machine %s didn't start again, after %d of %d were resized: %w", machine.ID, resized, len(machines), err)
+			}
+		}
+
+		resized++
+
+		fmt.Fprintf(cmdCtx.Out, "Resized machine %s in %s to %s\n", machine.ID, machine.Region, formatGuest(guest))
+	}
+
+	if resized == 0 {
+		fmt.Fprintf(cmdCtx.Out, "All %d machines already have this size\n", len(machines))
+
+		return nil
+	}
+
+	fmt.Fprintf(cmdCtx.Out, "Resized %d of %d machines\n", resized, len(machines))
+
+	return nil
+}
+
+// resizedGuest returns the guest machine is resized to, along with the one it
+// has.
+func resizedGuest(machine *api.Machine, preset *api.MachineGuest, memoryMB int, gpuKind string) (guest, current api.MachineGuest) {
+	current = api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: api.MEMORY_MB_PER_SHARED_CPU}
+	if machine.Config.Guest != nil {
+		current = *machine.Config.Guest
+	}
+
+	guest = current
+	if preset != nil {
+		guest.CPUKind, guest.CPUs, guest.MemoryMB = preset.CPUKind, preset.CPUs, preset.MemoryMB
+	}
+	if memoryMB > 0 {
+		guest.MemoryMB = memoryMB
+	}
+	if gpuKind != "" {
+		guest.GPUKind = gpuKind
+	}
+
+	return guest, current
+}
+
+// selectMachines returns the machines of group and regions, or any group or
+// region when they're empty, sorted by region.
+func selectMachines(machines []*api.Machine, group string, regions []string) []*api.Machine {
+	var selected []*api.Machine
+	for _, machine := range machines {
+		if machine.Config == nil {
+			continue
+		}
+
+		machineGroup := machine.Config.Metadata[api.MachineProcessGroupKey]
+		if machineGroup == "" {
+			machineGroup = "app"
+		}
+
+		if group != "" && machineGroup != group {
+			continue
+		}
+
+		if len(regions) > 0 && !lo.Contains(regions, machine.Region) {
+			continue
+		}
+
+		selected = append(selected, machine)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		return selected[i].Region < selected[j].Region
+	})
+
+	return selected
+}
+
+func formatGuest(guest api.MachineGuest) string {
+	s := fmt.Sprintf("%d %s CPU, %d MB", guest.CPUs, guest.CPUKind, guest.MemoryMB)
+	if guest.GPUKind != "" {
+		s += ", " + guest.GPUKind + " GPU"
+	}
+
+	return s
+}
@@ -131,7 +131,7 @@ func runTurboku(cmdCtx *cmdctx.CmdContext) error {
 
 	orgSlug := cmdCtx.Config.GetString("org")
 
-	org, err := selectOrganization(ctx, fly, orgSlug)
+	org, err := selectOrganization(ctx, fly, orgSlug, "--org")
 	if err != nil {
 		return err
 	}
@@ -254,7 +254,14 @@ func runTurboku(cmdCtx *cmdctx.CmdContext) error {
 		return err
 	}
 
-	if !cmdCtx.Config.GetBool("no-deploy") && (cmdCtx.Config.GetBool("now") || confirm("Would you like to deploy now?")) {
+	deployNow := cmdCtx.Config.GetBool("now")
+	if !cmdCtx.Config.GetBool("no-deploy") && !deployNow {
+		if deployNow, err = confirm("Would you like to deploy now?"); err != nil {
+			return err
+		}
+	}
+
+	if !cmdCtx.Config.GetBool("no-deploy") && deployNow {
 		// change working directory(cmdCtx.WorkingDir) to the app directory
 		cmdCtx.WorkingDir = filepath.Join(cmdCtx.WorkingDir, app.Name)
 
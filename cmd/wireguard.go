@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 	"text/template"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/superfly/flyctl/cmdctx"
 	"github.com/superfly/flyctl/docstrings"
 	"github.com/superfly/flyctl/flyctl"
+	"github.com/superfly/flyctl/internal/qrcode"
 	"github.com/superfly/flyctl/internal/wireguard"
 	"github.com/superfly/flyctl/terminal"
 )
@@ -36,12 +38,56 @@ func newWireGuardCommand(client *client.Client) *Command {
 	}
 
 	child(cmd, runWireGuardList, "wireguard.list").Args = cobra.MaximumNArgs(1)
-	child(cmd, runWireGuardCreate, "wireguard.create").Args = cobra.MaximumNArgs(4)
+	create := child(cmd, runWireGuardCreate, "wireguard.create")
+	create.Args = cobra.MaximumNArgs(4)
+	create.AddStringFlag(StringFlagOpts{
+		Name:        "output",
+		Shorthand:   "o",
+		Description: "Format of the configuration: conf, to write it to a file, or qr, to show it as a QR code for the WireGuard mobile app",
+		Default:     "conf",
+	})
+	// every configuration routes nothing but the private network, so
+	// --split-tunnel was dropped; it's still accepted to say so
+	create.AddBoolFlag(BoolFlagOpts{
+		Name:        "split-tunnel",
+		Description: "No longer needed: configurations only route the organization's private network",
+		Hidden:      true,
+	})
 	child(cmd, runWireGuardRemove, "wireguard.remove").Args = cobra.MaximumNArgs(2)
 	child(cmd, runWireGuardStat, "wireguard.status").Args = cobra.MaximumNArgs(2)
 	child(cmd, runWireGuardResetPeer, "wireguard.reset").Args = cobra.MaximumNArgs(1)
 	child(cmd, runWireGuardWebSockets, "wireguard.websockets").Args = cobra.ExactArgs(1)
 
+	teamCreate := child(cmd, runWireGuardTeamCreate, "wireguard.team-create")
+	teamCreate.Args = cobra.MaximumNArgs(1)
+	teamCreate.AddStringSliceFlag(StringSliceFlagOpts{
+		Name:        "members",
+		Description: "Comma separated list of team members to create peers for",
+	})
+	teamCreate.AddStringFlag(StringFlagOpts{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "Region to create the peers in; defaults to the closest gateway",
+	})
+	teamCreate.AddStringFlag(StringFlagOpts{
+		Name:        "output",
+		Shorthand:   "o",
+		Description: "Format of the configurations: conf, to write them to files, or qr, to show them as QR codes for the WireGuard mobile app",
+		Default:     "conf",
+	})
+	teamCreate.AddStringFlag(StringFlagOpts{
+		Name:        "output-dir",
+		Description: "Directory to write the per-member WireGuard configurations to",
+		Default:     ".",
+	})
+
+	prune := child(cmd, runWireGuardPrune, "wireguard.prune")
+	prune.Args = cobra.MaximumNArgs(1)
+	prune.AddStringFlag(StringFlagOpts{
+		Name:        "member",
+		Description: "Team member whose peers should be removed",
+	})
+
 	tokens := child(cmd, nil, "wireguard.token")
 
 	child(tokens, runWireGuardTokenList, "wireguard.token.list").Args = cobra.MaximumNArgs(1)
@@ -59,6 +105,10 @@ func argOrPromptImpl(ctx *cmdctx.CmdContext, nth int, prompt string, first bool)
 		return ctx.Args[nth], nil
 	}
 
+	if err := ensurePrompt(prompt, ""); err != nil {
+		return "", err
+	}
+
 	val := ""
 	err := survey.AskOne(&survey.Input{
 		Message: prompt,
@@ -80,7 +130,7 @@ func orgByArg(cmdCtx *cmdctx.CmdContext) (*api.Organization, error) {
 	client := cmdCtx.Client.API()
 
 	if len(cmdCtx.Args) == 0 {
-		org, err := selectOrganization(ctx, client, "")
+		org, err := selectOrganization(ctx, client, "", "organization argument")
 		if err != nil {
 			return nil, err
 		}
@@ -111,16 +161,22 @@ func runWireGuardList(cmdCtx *cmdctx.CmdContext) error {
 		return nil
 	}
 
+	teamPeers, err := wireguard.GetTeamPeers()
+	if err != nil {
+		return err
+	}
+
 	table := tablewriter.NewWriter(cmdCtx.Out)
 
 	table.SetHeader([]string{
 		"Name",
 		"Region",
 		"Peer IP",
+		"Owner",
 	})
 
 	for _, peer := range peers {
-		table.Append([]string{peer.Name, peer.Region, peer.Peerip})
+		table.Append([]string{peer.Name, peer.Region, peer.Peerip, wireguard.TeamPeerOwner(teamPeers[org.Slug], peer)})
 	}
 
 	table.Render()
@@ -259,6 +315,15 @@ func runWireGuardResetPeer(ctx *cmdctx.CmdContext) error {
 }
 
 func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
+	output := ctx.Config.GetString("output")
+	if output != "conf" && output != "qr" {
+		return fmt.Errorf("unsupported output %q; use conf or qr", output)
+	}
+
+	if ctx.Config.GetBool("split-tunnel") {
+		fmt.Fprintln(ctx.IO.ErrOut, "--split-tunnel is no longer supported, nor needed: every configuration only routes the organization's private network through the tunnel")
+	}
+
 	org, err := orgByArg(ctx)
 	if err != nil {
 		return err
@@ -288,6 +353,21 @@ func runWireGuardCreate(ctx *cmdctx.CmdContext) error {
 !!!! and re-add the peering connection.                                     !!!!
 `)
 
+	if output == "qr" {
+		var conf bytes.Buffer
+		generateWgConf(data, state.LocalPrivate, &conf)
+
+		code, err := qrcode.Encode(bytes.TrimSpace(conf.Bytes()))
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("\nScan this code with the WireGuard app, using \"Create from QR code\":")
+		fmt.Println()
+
+		return code.Render(ctx.Out)
+	}
+
 	w, shouldClose, err := resolveOutputWriter(ctx, 3, "Filename to store WireGuard configuration in, or 'stdout': ")
 	if err != nil {
 		return err
@@ -338,6 +418,150 @@ func runWireGuardRemove(cmdCtx *cmdctx.CmdContext) error {
 	return wireguard.PruneInvalidPeers(ctx, cmdCtx.Client.API())
 }
 
+func runWireGuardTeamCreate(cmdCtx *cmdctx.CmdContext) error {
+	ctx := cmdCtx.Command.Context()
+
+	client := cmdCtx.Client.API()
+
+	members := cmdCtx.Config.GetStringSlice("members")
+	if len(members) == 0 {
+		return fmt.Errorf("at least one member must be provided with --members")
+	}
+
+	output := cmdCtx.Config.GetString("output")
+	if output != "conf" && output != "qr" {
+		return fmt.Errorf("unsupported output %q; use conf or qr", output)
+	}
+
+	org, err := orgByArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	// resolve the region upfront, as it's part of every peer name
+	region := cmdCtx.Config.GetString("region")
+	if region == "" {
+		gateway, err := client.ClosestWireguardGatewayRegion(ctx)
+		if err != nil {
+			return err
+		}
+		region = gateway.Code
+	}
+
+	dir := cmdCtx.Config.GetString("output-dir")
+	if output == "conf" {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf(`
+!!!! WARNING: Output includes private keys. Private keys cannot be recovered !!!!
+!!!! after creating the peers; hand each to its owner and delete it.        !!!!
+`)
+
+	for _, member := range members {
+		name := wireguard.TeamPeerName(member, region)
+
+		state, err := wireguard.Create(client, org, region, name)
+		if err != nil {
+			return fmt.Errorf("failed creating peer for %s: %w", member, err)
+		}
+
+		if err := wireguard.RecordTeamPeer(org.Slug, name, state.LocalPublic, member); err != nil {
+			return fmt.Errorf("failed recording peer of %s: %w", member, err)
+		}
+
+		if output == "qr" {
+			var conf bytes.Buffer
+			generateWgConf(&state.Peer, state.LocalPrivate, &conf)
+
+			code, err := qrcode.Encode(bytes.TrimSpace(conf.Bytes()))
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nWireGuard configuration for %s; scan it with the WireGuard app, using \"Create from QR code\":\n\n", member)
+
+			if err := code.Render(cmdCtx.Out); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		filename := filepath.Join(dir, name+".conf")
+
+		f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if err != nil {
+			return fmt.Errorf("can't create '%s': %w", filename, err)
+		}
+
+		generateWgConf(&state.Peer, state.LocalPrivate, f)
+
+		if err := f.Close(); err != nil {
+			return err
+		}
+
+		fmt.Printf("Wrote WireGuard configuration for %s to %s\n", member, filename)
+	}
+
+	return nil
+}
+
+func runWireGuardPrune(cmdCtx *cmdctx.CmdContext) error {
+	ctx := cmdCtx.Command.Context()
+
+	client := cmdCtx.Client.API()
+
+	member := cmdCtx.Config.GetString("member")
+	if member == "" {
+		return fmt.Errorf("a member must be provided with --member")
+	}
+
+	org, err := orgByArg(cmdCtx)
+	if err != nil {
+		return err
+	}
+
+	peers, err := client.GetWireGuardPeers(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	teamPeers, err := wireguard.GetTeamPeers()
+	if err != nil {
+		return err
+	}
+
+	var removed int
+	for _, peer := range peers {
+		if !wireguard.IsTeamPeerOf(teamPeers[org.Slug], peer, member) {
+			continue
+		}
+
+		fmt.Printf("Removing WireGuard peer \"%s\" for organization %s\n", peer.Name, org.Slug)
+
+		if err := client.RemoveWireGuardPeer(ctx, org, peer.Name); err != nil {
+			return err
+		}
+		removed++
+
+		if err := wireguard.ForgetTeamPeer(org.Slug, peer.Name); err != nil {
+			return err
+		}
+	}
+
+	if removed == 0 {
+		fmt.Printf("No WireGuard peers found for %s\n", member)
+		return nil
+	}
+
+	fmt.Printf("Removed %d peer(s).\n", removed)
+
+	return wireguard.PruneInvalidPeers(ctx, client)
+}
+
 func runWireGuardStat(cmdCtx *cmdctx.CmdContext) error {
 	ctx := cmdCtx.Command.Context()
 
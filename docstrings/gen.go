@@ -117,7 +117,8 @@ authenticated and in use.`,
 		}
 	case "autoscale":
 		return KeyStrings{"autoscale", "Autoscaling app resources",
-			`Autoscaling application resources`,
+			`Autoscaling application resources. Apps running on machines are
+autoscaled with the metrics-based policies of fly autoscale policy.`,
 		}
 	case "autoscale.balanced":
 		return KeyStrings{"balanced", "Configure a traffic balanced app with params (min=int max=int)",
@@ -188,18 +189,27 @@ When Fly is then able to validate that hostname/domain, the platform gets
 certificates issued for the hostname/domain by Let's Encrypt.`,
 		}
 	case "certs.add":
-		return KeyStrings{"add <hostname>", "Add a certificate for an app.",
+		return KeyStrings{"add <hostname> [<hostname>...]", "Add a certificate for an app.",
 			`Add a certificate for an application. Takes a hostname
-as a parameter for the certificate.`,
+as a parameter for the certificate. Given several hostnames, a certificate
+is added for each of them. With --pair, certificates are also added for the
+apex domain of wildcard hostnames, and the wildcard of the others.
+
+With --dns-provider, the DNS validation record is created through the
+provider's API (cloudflare, route53 or google), and the command waits for it
+to propagate and for the certificate to be issued.`,
 		}
 	case "certs.check":
 		return KeyStrings{"check <hostname>", "Checks DNS configuration",
 			`Checks the DNS configuration for the specified hostname.
-Displays results in the same format as the SHOW command.`,
+Displays results in the same format as the SHOW command. With --warn-days,
+exits with an error when the certificate expires within that many days.`,
 		}
 	case "certs.list":
 		return KeyStrings{"list", "List certificates for an app.",
-			`List the certificates associated with a deployed application.`,
+			`List the certificates associated with a deployed application.
+With --warn-days, exits with an error when a certificate expires within
+that many days.`,
 		}
 	case "certs.remove":
 		return KeyStrings{"remove <hostname>", "Removes a certificate from an app",
@@ -326,12 +336,25 @@ browser to view the content.`,
 		}
 	case "domains":
 		return KeyStrings{"domains", "Manage domains",
-			`Manage domains`,
+			`Manage domains of organizations, and the hostnames apps are served on`,
 		}
 	case "domains.add":
 		return KeyStrings{"add [org] [name]", "Add a domain",
 			`Add a domain to an organization`,
 		}
+	case "domains.attach":
+		return KeyStrings{"attach <hostname>", "Attach a hostname to an app",
+			`Attach a hostname to an application. A certificate is requested for the
+hostname, and the DNS records it needs are listed along with what they currently
+resolve to. With --watch, the records and certificate are checked until the
+hostname is ready.`,
+		}
+	case "domains.check":
+		return KeyStrings{"check <hostname>", "Check the DNS records and certificate of a hostname",
+			`Check a hostname attached to an application. Lists the DNS records it needs
+along with what they currently resolve to, and the status of its certificate.
+With --watch, they're checked until the hostname is ready.`,
+		}
 	case "domains.list":
 		return KeyStrings{"list [<org>]", "List domains",
 			`List domains for an organization`,
@@ -619,7 +642,13 @@ about the Fly platform.`,
 		}
 	case "regions.add":
 		return KeyStrings{"add REGION ...", "Allow the app to run in the provided regions",
-			`Allow the app to run in one or more regions`,
+			`Allow the app to run in one or more regions.
+
+For apps running on machines, a machine of each process group, or only of
+--group, is created in the regions from the config of its existing ones.
+Groups whose machines mount volumes need --with-volumes to get volumes in the
+new regions, which --snapshot-region restores from the newest snapshot of the
+group's volume in that region.`,
 		}
 	case "regions.backup":
 		return KeyStrings{"backup REGION ...", "Sets the backup region pool with provided regions",
@@ -658,14 +687,26 @@ the number of configured instances.`,
 			`Scale application resources`,
 		}
 	case "scale.count":
-		return KeyStrings{"count <count>", "Change an app's VM count to the given value",
+		return KeyStrings{"count <count>|<group>=<count>[:<region>,...]...", "Change an app's VM count to the given value",
 			`Change an app's VM count to the given value.
 
+Counts of process groups are given like web=3 worker=1. For apps running on
+machines, counts may be given per region, like web=3:fra,2:ams; regions not
+given keep the machines they have, and web=0:fra removes those of a region.
+Machines are created, cloned from those of the group, or stopped and destroyed
+to match, along with the volumes their mounts need. Counts without regions keep
+the machines balanced over the regions the group runs in, and --max-per-region
+caps how many run in any region. The changes are shown, and confirmed unless
+--yes is given, before any is made.
+
 For pricing, see https://fly.io/docs/about/pricing/`,
 		}
 	case "scale.memory":
 		return KeyStrings{"memory <memoryMB>", "Set VM memory",
-			`Set VM memory to a number of megabytes`,
+			`Set VM memory to a number of megabytes
+
+For apps running on machines, --group and --region limit the machines resized
+to those of a process group and regions. Machines are updated one at a time.`,
 		}
 	case "scale.show":
 		return KeyStrings{"show", "Show current resources",
@@ -675,7 +716,8 @@ For pricing, see https://fly.io/docs/about/pricing/`,
 		return KeyStrings{"vm [SIZENAME] [flags]", "Change an app's VM to a named size (eg. shared-cpu-1x, dedicated-cpu-1x, dedicated-cpu-2x...)",
 			`Change an application's VM size to one of the named VM sizes.
 
-Size names include shared-cpu-1x, dedicated-cpu-1x, dedicated-cpu-2x.
+Size names include shared-cpu-1x, dedicated-cpu-1x, dedicated-cpu-2x and, for
+apps running on machines, performance-1x to performance-16x.
 
 For a full list of supported sizes use the command flyctl platform vm-sizes
 
@@ -687,7 +729,16 @@ For dedicated vms, this should be a multiple of 1024MB.
 
 For shared vms, this can be 256MB or a a multiple of 1024MB.
 
-For pricing, see https://fly.io/docs/about/pricing/`,
+For pricing, see https://fly.io/docs/about/pricing/
+
+For apps running on machines, --group and --region limit the machines resized
+to those of a process group and regions, so each can have its own size, like
+flyctl scale vm performance-2x --group worker --region fra. Machines are
+updated one at a time, waiting for each to start again before the next.
+
+GPUs are given to the machines of apps running on machines with
+--vm-gpu-kind, like flyctl scale vm performance-8x --vm-gpu-kind a100-40gb.
+The kind is checked to be available in the regions of the machines.`,
 		}
 	case "secrets":
 		return KeyStrings{"secrets", "Manage app secrets",
@@ -831,12 +882,23 @@ number to operate. This can be found through the volumes list command`,
 		}
 	case "wireguard.create":
 		return KeyStrings{"create [org] [region] [name]", "Add a WireGuard peer connection",
-			`Add a WireGuard peer connection to an organization`,
+			`Add a WireGuard peer connection to an organization.
+
+Use --output qr to show the configuration as a QR code to scan with the
+WireGuard mobile app rather than writing it to a file. Every configuration
+is split tunnel: only the organization's private network is routed through
+the tunnel, so there's no --split-tunnel flag anymore. Names are resolved
+with the organization's DNS server, so that .internal names resolve.`,
 		}
 	case "wireguard.list":
 		return KeyStrings{"list [<org>]", "List all WireGuard peer connections",
 			`List all WireGuard peer connections`,
 		}
+	case "wireguard.prune":
+		return KeyStrings{"prune [org]", "Remove a team member's WireGuard peers",
+			`Remove every WireGuard peer created for a team member with
+'wireguard team-create' on this computer, for example when they leave the team`,
+		}
 	case "wireguard.remove":
 		return KeyStrings{"remove [org] [name]", "Remove a WireGuard peer connection",
 			`Remove a WireGuard peer connection from an organization`,
@@ -849,6 +911,14 @@ number to operate. This can be found through the volumes list command`,
 		return KeyStrings{"status [org] [name]", "Get status a WireGuard peer connection",
 			`Get status for a WireGuard peer connection`,
 		}
+	case "wireguard.team-create":
+		return KeyStrings{"team-create [org]", "Create WireGuard peers for a team",
+			`Create individually named WireGuard peers for each member of a team,
+writing one configuration file per member, or showing one QR code per member
+with --output qr. The member each peer is created for is recorded in the
+flyctl config file, so that 'wireguard list' shows their owner and 'wireguard
+prune' finds them on this computer.`,
+		}
 	case "wireguard.token":
 		return KeyStrings{"token <command>", "Commands that managed WireGuard delegated access tokens",
 			`Commands that managed WireGuard delegated access tokens`,
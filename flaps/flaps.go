@@ -182,6 +182,18 @@ func (f *Client) Get(ctx context.Context, machineID string) (*api.Machine, error
 	return out, nil
 }
 
+// ListEvents returns the events of the machine, which lists of machines only
+// include the latest few of.
+func (f *Client) ListEvents(ctx context.Context, machineID string) ([]*api.MachineEvent, error) {
+	out := make([]*api.MachineEvent, 0)
+
+	err := f.sendRequest(ctx, http.MethodGet, fmt.Sprintf("/%s/events", machineID), nil, &out, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events of VM %s: %w", machineID, err)
+	}
+	return out, nil
+}
+
 func (f *Client) GetMany(ctx context.Context, machineIDs []string) ([]*api.Machine, error) {
 	machines := make([]*api.Machine, 0, len(machineIDs))
 	for _, id := range machineIDs {
@@ -222,7 +234,7 @@ func (f *Client) ListActive(ctx context.Context) ([]*api.Machine, error) {
 	}
 
 	machines = lo.Filter(machines, func(m *api.Machine, _ int) bool {
-		return m.Config != nil && m.Config.Metadata["process_group"] != "release_command" && m.State != "destroyed"
+		return m.Config != nil && m.Config.Metadata[api.MachineProcessGroupKey] != "release_command" && m.State != "destroyed"
 	})
 
 	return machines, nil
@@ -250,6 +262,47 @@ func (f *Client) Kill(ctx context.Context, machineID string) (err error) {
 	return
 }
 
+func (f *Client) Exec(ctx context.Context, machineID string, in *api.MachineExecRequest) (*api.MachineExecResponse, error) {
+	endpoint := fmt.Sprintf("/%s/exec", machineID)
+
+	out := new(api.MachineExecResponse)
+
+	if err := f.sendRequest(ctx, http.MethodPost, endpoint, in, out, nil); err != nil {
+		return nil, fmt.Errorf("failed to exec on VM %s: %w", machineID, err)
+	}
+	return out, nil
+}
+
+// Console returns the output of the serial console of a machine since it last
+// booted: its kernel and init messages as well as what its process wrote
+// before its logs started shipping. With follow the output is streamed until
+// the machine stops or ctx is done. Callers must close the returned reader.
+func (f *Client) Console(ctx context.Context, machineID string, follow bool) (io.ReadCloser, error) {
+	endpoint := fmt.Sprintf("/%s/console", machineID)
+
+	if follow {
+		endpoint += "?follow=true"
+	}
+
+	req, err := f.NewRequest(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get console of VM %s: %w", machineID, err)
+	}
+
+	if resp.StatusCode > 299 {
+		defer resp.Body.Close()
+
+		return nil, fmt.Errorf("failed to get console of VM %s: %w", machineID, handleAPIError(resp))
+	}
+
+	return resp.Body, nil
+}
+
 func (f *Client) GetLease(ctx context.Context, machineID string, ttl *int) (*api.MachineLease, error) {
 	endpoint := fmt.Sprintf("/%s/lease", machineID)
 
@@ -333,6 +386,16 @@ func (f *Client) NewRequest(ctx context.Context, method, path string, in interfa
 	return req, nil
 }
 
+// Error is an error the machines API responded with.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
 func handleAPIError(resp *http.Response) error {
 	switch resp.StatusCode / 100 {
 	case 1, 3:
@@ -343,12 +406,15 @@ func handleAPIError(resp *http.Response) error {
 			Message string `json:"message,omitempty"`
 		}{}
 		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return fmt.Errorf("request returned non-2xx status, %d", resp.StatusCode)
+			return &Error{
+				StatusCode: resp.StatusCode,
+				Message:    fmt.Sprintf("request returned non-2xx status, %d", resp.StatusCode),
+			}
 		}
 		if apiErr.Message != "" {
-			return fmt.Errorf("%s", apiErr.Message)
+			return &Error{StatusCode: resp.StatusCode, Message: apiErr.Message}
 		}
-		return errors.New(apiErr.Error)
+		return &Error{StatusCode: resp.StatusCode, Message: apiErr.Error}
 	default:
 		return errors.New("something went terribly wrong")
 	}
@@ -443,6 +443,21 @@ func (ac *AppConfig) SetProcess(name, value string) {
 	ac.Definition["processes"] = processes
 }
 
+// SetVM sets the VM size and kind of GPU of the machines of the app, leaving
+// out those which are empty.
+func (ac *AppConfig) SetVM(size, gpuKind string) {
+	vm := map[string]string{}
+
+	if size != "" {
+		vm["size"] = size
+	}
+	if gpuKind != "" {
+		vm["gpu_kind"] = gpuKind
+	}
+
+	ac.Definition["vm"] = vm
+}
+
 func (ac *AppConfig) SetStatics(statics []scanner.Static) {
 	ac.Definition["statics"] = statics
 }
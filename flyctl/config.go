@@ -6,10 +6,14 @@ import (
 
 const (
 	ConfigAPIToken        = "access_token"
+	ConfigRefreshToken    = "refresh_token"
+	ConfigTokenExpiresAt  = "access_token_expires_at"
 	ConfigAPIBaseURL      = "api_base_url"
 	ConfigAppName         = "app"
 	ConfigVerboseOutput   = "verbose"
 	ConfigJSONOutput      = "json"
+	ConfigNonInteractive  = "non_interactive"
+	ConfigMaxRetries      = "max_retries"
 	ConfigBuiltinsfile    = "builtins_file"
 	ConfigGQLErrorLogging = "gqlerrorlogging"
 	ConfigInstaller       = "installer"
@@ -17,6 +21,7 @@ const (
 
 	ConfigWireGuardState      = "wire_guard_state"
 	ConfigWireGuardWebsockets = "wire_guard_websockets"
+	ConfigWireGuardTeamPeers  = "wire_guard_team_peers"
 
 	ConfigRegistryHost = "registry_host"
 )
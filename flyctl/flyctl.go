@@ -6,6 +6,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/superfly/flyctl/api"
@@ -73,6 +74,14 @@ func initViper() {
 	api.SetErrorLog(viper.GetBool(ConfigGQLErrorLogging))
 }
 
+// ApplyMaxRetries sets how many times API requests are retried to what the
+// --max-retries flag, or FLY_MAX_RETRIES, asks for, once flags are parsed.
+func ApplyMaxRetries() {
+	if n := viper.GetInt(ConfigMaxRetries); n >= 0 {
+		api.SetMaxRetries(n)
+	}
+}
+
 func loadConfig() error {
 	if configDir == "" {
 		return nil
@@ -116,12 +125,34 @@ func GetAPIToken() string {
 		return apiToken
 	}
 
+	if contextToken != "" {
+		return contextToken
+	}
+
 	viperAuth := viper.GetString(ConfigAPIToken)
 
 	return viperAuth
 }
 
-var writeableConfigKeys = []string{ConfigAPIToken, ConfigInstaller, ConfigWireGuardState, ConfigWireGuardWebsockets, BuildKitNodeID}
+// contextToken is the access token of the named context in use, if any.
+var contextToken string
+
+// UseContextToken makes GetAPIToken return the access token of the named
+// context in use rather than the one of the config file.
+func UseContextToken(token string) {
+	contextToken = token
+}
+
+// SetSessionTokens updates the access token of an SSO session, the time it
+// expires at and its refresh token once they've been refreshed, so that saving
+// the config doesn't write back the previous ones.
+func SetSessionTokens(accessToken, refreshToken string, expiresAt time.Time) {
+	viper.Set(ConfigAPIToken, accessToken)
+	viper.Set(ConfigRefreshToken, refreshToken)
+	viper.Set(ConfigTokenExpiresAt, expiresAt.UTC())
+}
+
+var writeableConfigKeys = []string{ConfigAPIToken, ConfigRefreshToken, ConfigTokenExpiresAt, ConfigInstaller, ConfigWireGuardState, ConfigWireGuardWebsockets, ConfigWireGuardTeamPeers, BuildKitNodeID}
 
 func SaveConfig() error {
 	out := map[string]interface{}{}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -84,7 +86,13 @@ type Config struct {
 	Deploy          *Deploy                     `toml:"deploy, omitempty"`
 	PrimaryRegion   string                      `toml:"primary_region,omitempty"`
 	Checks          map[string]api.MachineCheck `toml:"checks,omitempty"`
+	VM              *VM                         `toml:"vm,omitempty"`
 	platformVersion string
+
+	// RequiredSecrets maps the names of the secrets which must be set before
+	// the app may be deployed to an optional description. It's read from the
+	// [env.required_secrets] table.
+	RequiredSecrets map[string]string `toml:"-" json:"required_secrets,omitempty"`
 }
 
 type Deploy struct {
@@ -96,14 +104,42 @@ type Static struct {
 	UrlPrefix string `toml:"url_prefix" json:"url_prefix" validate:"required"`
 }
 type HttpService struct {
-	InternalPort int                            `json:"internal_port" toml:"internal_port" validate:"required,numeric"`
-	ForceHttps   bool                           `toml:"force_https"`
-	Concurrency  *api.MachineServiceConcurrency `toml:"concurrency,omitempty"`
+	InternalPort       int                            `json:"internal_port" toml:"internal_port" validate:"required,numeric"`
+	ForceHttps         bool                           `toml:"force_https"`
+	Concurrency        *api.MachineServiceConcurrency `toml:"concurrency,omitempty"`
+	AutoStopMachines   *bool                          `json:"auto_stop_machines,omitempty" toml:"auto_stop_machines,omitempty"`
+	AutoStartMachines  *bool                          `json:"auto_start_machines,omitempty" toml:"auto_start_machines,omitempty"`
+	MinMachinesRunning *int                           `json:"min_machines_running,omitempty" toml:"min_machines_running,omitempty"`
 }
 
 type VM struct {
-	CpuCount int `toml:"cpu_count,omitempty"`
-	Memory   int `toml:"memory,omitempty"`
+	Size     string `toml:"size,omitempty"`
+	CpuCount int    `toml:"cpu_count,omitempty"`
+	Memory   int    `toml:"memory,omitempty"`
+	GPUKind  string `toml:"gpu_kind,omitempty"`
+}
+
+// Guest returns the guest of the machines deployments launch, as set by the
+// [vm] table, or nil when it isn't.
+func (c *Config) Guest() *api.MachineGuest {
+	if c.VM == nil {
+		return nil
+	}
+
+	guest := *api.MachinePresets["shared-cpu-1x"]
+	if preset, ok := api.MachinePresets[c.VM.Size]; ok {
+		guest = *preset
+	}
+
+	if c.VM.CpuCount > 0 {
+		guest.CPUs = c.VM.CpuCount
+	}
+	if c.VM.Memory > 0 {
+		guest.MemoryMB = c.VM.Memory
+	}
+	guest.GPUKind = c.VM.GPUKind
+
+	return &guest
 }
 
 type Build struct {
@@ -211,7 +247,20 @@ func (c *Config) unmarshalTOML(r io.ReadSeeker) (err error) {
 		// Rewind TOML in preparation for parsing the full config
 		r.Seek(0, io.SeekStart)
 		if c.ForMachines() {
-			_, err = toml.NewDecoder(r).Decode(&c)
+			if _, err = toml.NewDecoder(r).Decode(&data); err != nil {
+				return err
+			}
+
+			// [env.required_secrets] doesn't fit the env map, so take it out
+			// and decode what remains
+			c.RequiredSecrets = takeRequiredSecrets(data)
+
+			var buf bytes.Buffer
+			if err = toml.NewEncoder(&buf).Encode(data); err != nil {
+				return err
+			}
+
+			_, err = toml.NewDecoder(&buf).Decode(&c)
 
 			if err != nil {
 				return err
@@ -240,6 +289,8 @@ func (c *Config) unmarshalNativeMap(data map[string]interface{}) error {
 	c.Build = unmarshalBuild(data)
 	delete(data, "build")
 
+	c.RequiredSecrets = takeRequiredSecrets(data)
+
 	for k := range c.Definition {
 		delete(c.Definition, k)
 	}
@@ -248,6 +299,58 @@ func (c *Config) unmarshalNativeMap(data map[string]interface{}) error {
 	return nil
 }
 
+const requiredSecretsKey = "required_secrets"
+
+// takeRequiredSecrets removes the required_secrets table from the env section
+// of data, returning its entries. Required secrets may be given as a table of
+// names to descriptions, or as an array of names.
+func takeRequiredSecrets(data map[string]interface{}) map[string]string {
+	env, ok := data["env"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	raw, ok := env[requiredSecretsKey]
+	if !ok {
+		return nil
+	}
+	delete(env, requiredSecretsKey)
+
+	secrets := map[string]string{}
+
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		for name, desc := range raw {
+			secrets[name] = fmt.Sprint(desc)
+		}
+	case []interface{}:
+		for _, name := range raw {
+			secrets[fmt.Sprint(name)] = ""
+		}
+	}
+
+	return secrets
+}
+
+// MissingSecrets returns the sorted names of the required secrets which
+// aren't part of secrets.
+func (c *Config) MissingSecrets(secrets []api.Secret) (missing []string) {
+	set := make(map[string]struct{}, len(secrets))
+	for _, secret := range secrets {
+		set[secret.Name] = struct{}{}
+	}
+
+	for name := range c.RequiredSecrets {
+		if _, ok := set[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	sort.Strings(missing)
+
+	return
+}
+
 func unmarshalBuild(data map[string]interface{}) *Build {
 	buildConfig, ok := (data["build"]).(map[string]interface{})
 	if !ok {
@@ -317,6 +420,7 @@ func (c *Config) marshalTOML(w io.Writer) error {
 	// For machines apps, encode and write directly, bypassing custom marshalling
 	if c.platformVersion == MachinesPlatform {
 		encoder.Encode(&c)
+		c.marshalRequiredSecrets(&b)
 		_, err := b.WriteTo(w)
 		return err
 	}
@@ -375,10 +479,32 @@ func (c *Config) marshalTOML(w io.Writer) error {
 		}
 	}
 
+	c.marshalRequiredSecrets(&b)
+
 	_, err := b.WriteTo(w)
 	return err
 }
 
+// marshalRequiredSecrets writes the [env.required_secrets] table. It's written
+// by hand, after everything else, since encoding it as part of env would
+// clash with the env table already written.
+func (c *Config) marshalRequiredSecrets(w io.Writer) {
+	if len(c.RequiredSecrets) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(c.RequiredSecrets))
+	for name := range c.RequiredSecrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "\n[env.%s]\n", requiredSecretsKey)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s = %s\n", strconv.Quote(name), strconv.Quote(c.RequiredSecrets[name]))
+	}
+}
+
 func (c *Config) WriteToFile(filename string) (err error) {
 	if err = helpers.MkdirAll(filename); err != nil {
 		return
@@ -6,6 +6,7 @@ import (
 
 	"github.com/BurntSushi/toml"
 	"github.com/stretchr/testify/assert"
+	"github.com/superfly/flyctl/api"
 )
 
 func TestLoadTOMLAppConfigWithAppName(t *testing.T) {
@@ -60,3 +61,24 @@ func TestLoadTOMLAppConfigWithServices(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, p.Definition, rawData)
 }
+
+func TestLoadTOMLAppConfigWithRequiredSecrets(t *testing.T) {
+	const path = "./testdata/required-secrets.toml"
+
+	p, err := LoadConfig(context.Background(), path, NomadPlatform)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"DATABASE_URL": "Postgres connection string", "API_KEY": ""}, p.RequiredSecrets)
+	assert.Equal(t, map[string]interface{}{"LOG_LEVEL": "info"}, p.Definition["env"])
+
+	missing := p.MissingSecrets([]api.Secret{{Name: "API_KEY"}})
+	assert.Equal(t, []string{"DATABASE_URL"}, missing)
+}
+
+func TestLoadTOMLAppConfigWithVM(t *testing.T) {
+	const path = "./testdata/vm.toml"
+
+	p, err := LoadConfig(context.Background(), path, MachinesPlatform)
+	assert.NoError(t, err)
+	assert.Equal(t, &api.MachineGuest{CPUKind: "performance", CPUs: 8, MemoryMB: 16384, GPUKind: "a100-40gb"}, p.Guest())
+	assert.Equal(t, "", api.MachinePresets["performance-8x"].GPUKind)
+}
@@ -4,19 +4,29 @@ package cli
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"regexp"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2/terminal"
 	"github.com/spf13/cobra"
 
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/graphql"
 
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/flyerr"
 	"github.com/superfly/flyctl/internal/logger"
+	"github.com/superfly/flyctl/internal/prompt"
 
+	"github.com/superfly/flyctl/internal/command/plugin"
 	"github.com/superfly/flyctl/internal/command/root"
 )
 
@@ -27,31 +37,145 @@ func Run(ctx context.Context, io *iostreams.IOStreams, args ...string) int {
 	ctx = logger.NewContext(ctx, logger.FromEnv(io.ErrOut))
 
 	cmd := root.New()
+	plugin.Attach(cmd, args)
 	cmd.SetOut(io.Out)
 	cmd.SetErr(io.ErrOut)
 	cmd.SetArgs(args)
 
 	cs := io.ColorScheme()
 
-	switch _, err := cmd.ExecuteContextC(ctx); {
+	var exitCode flyerr.ExitCodeError
+
+	_, err := cmd.ExecuteContextC(ctx)
+	jsonErrors, _ := cmd.PersistentFlags().GetBool(flag.JSONErrorsName)
+
+	switch {
 	case err == nil:
 		return 0
-	case errors.Is(err, context.Canceled), errors.Is(err, terminal.InterruptErr):
-		return 127
-	case errors.Is(err, context.DeadlineExceeded):
-		printError(io.ErrOut, cs, err)
+	case errors.As(err, &exitCode):
+		// the command already reported why it failed, like the remote
+		// commands of ssh console do, so only scripts are told
+		if jsonErrors {
+			printJSONError(io.ErrOut, classify(err), int(exitCode), err)
+		}
 
-		return 126
+		return int(exitCode)
 	case isUnchangedError(err):
 		// This means the deployment was a noop, which is noteworthy but not something we should
 		// fail CI on. Print a warning and exit 0. Remove this once we're fully on Machines!
 		printError(io.ErrOut, cs, err)
 		return 0
 	default:
-		printError(io.ErrOut, cs, err)
+		code := classify(err)
+
+		if jsonErrors {
+			printJSONError(io.ErrOut, code, code.ExitCode(), err)
+		} else if code != flyerr.CodeCancelled {
+			printError(io.ErrOut, cs, err)
+		}
+
+		return code.ExitCode()
+	}
+}
+
+// classify returns the code of err: the one it was given, if any, or else the
+// one the errors of the APIs it wraps amount to.
+func classify(err error) flyerr.Code {
+	if code := flyerr.GetErrorCode(err); code != flyerr.CodeUnknown {
+		return code
+	}
+
+	var (
+		apiErr   *api.ApiError
+		flapsErr *flaps.Error
+		gqlErr   *graphql.GraphQLError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, terminal.InterruptErr):
+		return flyerr.CodeCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return flyerr.CodeTimeout
+	case prompt.IsNonInteractive(err):
+		return flyerr.CodeInputRequired
+	case errors.Is(err, client.ErrNoAuthToken):
+		return flyerr.CodeAuth
+	case errors.Is(err, api.ErrNotFound):
+		return flyerr.CodeNotFound
+	case errors.As(err, &apiErr):
+		return statusCode(apiErr.Status)
+	case errors.As(err, &flapsErr):
+		switch {
+		case flapsErr.StatusCode == http.StatusConflict && isLeaseError(flapsErr.Message):
+			return flyerr.CodeLeaseConflict
+		case isCapacityError(flapsErr.Message):
+			return flyerr.CodeCapacity
+		}
 
-		return 1
+		return statusCode(flapsErr.StatusCode)
+	case errors.As(err, &gqlErr):
+		switch gqlErr.Extensions.Code {
+		case "UNAUTHORIZED", "UNAUTHENTICATED":
+			return flyerr.CodeAuth
+		case "NOT_FOUND":
+			return flyerr.CodeNotFound
+		}
 	}
+
+	return flyerr.CodeUnknown
+}
+
+// statusCode returns the code of API errors of the given HTTP status.
+func statusCode(status int) flyerr.Code {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return flyerr.CodeAuth
+	case http.StatusNotFound:
+		return flyerr.CodeNotFound
+	default:
+		return flyerr.CodeUnknown
+	}
+}
+
+// capacityError matches the messages the machines API fails with when the
+// hosts of a region have no room for a machine, like "insufficient memory
+// available to fulfill request".
+var capacityError = regexp.MustCompile(`(?i)\binsufficient (memory|cpus?|gpus?|resources|capacity) available to fulfill request\b`)
+
+// isCapacityError reports whether the machines API failed to place a machine
+// for lack of room on the hosts of its region.
+func isCapacityError(msg string) bool {
+	return capacityError.MatchString(msg)
+}
+
+// isLeaseError reports whether the machines API refused a change because the
+// machine is leased by another deployment or command. The API conflicts over
+// other things too, like the state machines are in.
+func isLeaseError(msg string) bool {
+	return strings.Contains(strings.ToLower(msg), "lease")
+}
+
+// printJSONError prints err in the envelope --json-errors asks for.
+func printJSONError(w io.Writer, code flyerr.Code, exitCode int, err error) {
+	type envelope struct {
+		Code        flyerr.Code `json:"code"`
+		ExitCode    int         `json:"exit_code"`
+		Message     string      `json:"message"`
+		Description string      `json:"description,omitempty"`
+		Suggestion  string      `json:"suggestion,omitempty"`
+	}
+
+	_ = json.NewEncoder(w).Encode(struct {
+		Error envelope `json:"error"`
+	}{
+		Error: envelope{
+			Code:        code,
+			ExitCode:    exitCode,
+			Message:     err.Error(),
+			Description: flyerr.GetErrorDescription(err),
+			Suggestion:  flyerr.GetErrorSuggestion(err),
+		},
+	})
 }
 
 // isUnchangedError returns true if the error returned is an UNCHANGED GraphQL error.
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/superfly/graphql"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+
+	"github.com/superfly/flyctl/internal/flyerr"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+func TestClassify(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		code flyerr.Code
+	}{
+		{"unknown", errors.New("boom"), flyerr.CodeUnknown},
+		{"cancelled", context.Canceled, flyerr.CodeCancelled},
+		{"timeout", context.DeadlineExceeded, flyerr.CodeTimeout},
+		{"no token", client.ErrNoAuthToken, flyerr.CodeAuth},
+		{"not found", api.ErrNotFound, flyerr.CodeNotFound},
+		{"api unauthorized", &api.ApiError{Status: 401}, flyerr.CodeAuth},
+		{"api server error", &api.ApiError{Status: 500}, flyerr.CodeUnknown},
+		{"lease", &flaps.Error{StatusCode: 409, Message: "machine lease currently held by another client"}, flyerr.CodeLeaseConflict},
+		{"conflict", &flaps.Error{StatusCode: 409, Message: "machine is not in a stopped state"}, flyerr.CodeUnknown},
+		{"capacity", &flaps.Error{StatusCode: 412, Message: "insufficient memory available to fulfill request"}, flyerr.CodeCapacity},
+		{"not capacity", &flaps.Error{StatusCode: 422, Message: "volume capacity must be at least 1GB"}, flyerr.CodeUnknown},
+		{"graphql", &graphql.GraphQLError{Extensions: graphql.GraphQLErrorExtensions{Code: "NOT_FOUND"}}, flyerr.CodeNotFound},
+		{"input required", prompt.NonInteractiveError("--org flag must be specified when not running interactively"), flyerr.CodeInputRequired},
+		{"coded", flyerr.WithCode(flyerr.CodeCheckTimeout, errors.New("checks")), flyerr.CodeCheckTimeout},
+		{"wrapped", fmt.Errorf("failed to get app: %w", api.ErrNotFound), flyerr.CodeNotFound},
+		{"wrapped flaps", fmt.Errorf("failed to update machine: %w", &flaps.Error{StatusCode: 404}), flyerr.CodeNotFound},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.code, classify(c.err))
+		})
+	}
+}
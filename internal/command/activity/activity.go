@@ -0,0 +1,238 @@
+// Package activity implements the activity command.
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/azazeal/pause"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Show the audit log of an organization: deployments, changes to secrets,
+machines being created, updated, restarted or destroyed, members logging in,
+and more, each with the user behind it.
+
+Events are narrowed down with --app, --user, --type, and --since and --until,
+which take either a time like 2022-09-01T15:04:05Z or a duration ago like 1h.
+With --follow, new events are shown as they happen, until interrupted.
+
+The audit log needs the API to serve it, which it doesn't everywhere yet.
+`
+		short = "Show the audit log of an organization"
+	)
+
+	cmd := command.New("activity", short, long, run,
+		command.RequireAPI("Organization", "auditEvents"),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.App(),
+		flag.String{
+			Name:        "user",
+			Description: "Only show the events of the user with this email address",
+		},
+		flag.StringSlice{
+			Name:        "type",
+			Description: "Only show events of these types, like deploy, secrets, machine or login, comma separated or repeated",
+		},
+		flag.String{
+			Name:        "since",
+			Description: "Only show events since this time or duration ago",
+			Default:     "24h",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "Only show events until this time or duration ago",
+		},
+		flag.Int{
+			Name:        "limit",
+			Description: "How many of the latest events to show",
+			Default:     50,
+		},
+		flag.Bool{
+			Name:        "follow",
+			Shorthand:   "f",
+			Description: "Keep showing new events as they happen",
+		},
+		flag.Duration{
+			Name:        "interval",
+			Description: "How often --follow checks for new events",
+			Default:     10 * time.Second,
+		},
+	)
+
+	return cmd
+}
+
+func run(ctx context.Context) error {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		apiClient = client.FromContext(ctx).API()
+		limit     = flag.GetInt(ctx, "limit")
+		follow    = flag.GetBool(ctx, "follow")
+	)
+
+	if limit < 1 {
+		return errors.New("--limit must be at least 1")
+	}
+
+	filter := api.AuditEventsFilter{
+		AppName: flag.GetApp(ctx),
+		Email:   flag.GetString(ctx, "user"),
+		Types:   flag.GetStringSlice(ctx, "type"),
+	}
+
+	since, err := flag.GetTime(ctx, "since")
+	if err != nil {
+		return err
+	}
+	if !since.IsZero() {
+		filter.Since = &since
+	}
+
+	until, err := flag.GetTime(ctx, "until")
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		if follow {
+			return errors.New("--follow and --until can't be used together")
+		}
+		filter.Until = &until
+	}
+
+	org, err := orgSlug(ctx, apiClient, filter.AppName)
+	if err != nil {
+		return err
+	}
+
+	events, err := apiClient.GetAuditEvents(ctx, org, filter, limit)
+	if err != nil {
+		return fmt.Errorf("failed retrieving audit events: %w", err)
+	}
+
+	jsonOutput := config.FromContext(ctx).JSONOutput
+
+	if !follow {
+		if jsonOutput {
+			return render.JSON(out, events)
+		}
+
+		rows := make([][]string, 0, len(events))
+		for _, event := range events {
+			rows = append(rows, eventRow(event))
+		}
+
+		return render.Table(out, "", rows, "Time", "Type", "App", "User", "IP Address", "Description")
+	}
+
+	interval := flag.GetDuration(ctx, "interval")
+	if interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	seen := map[string]bool{}
+
+	for {
+		// events come newest first, so print them the other way around
+		for i := len(events) - 1; i >= 0; i-- {
+			event := events[i]
+			if seen[event.ID] {
+				continue
+			}
+			seen[event.ID] = true
+
+			if err := printEvent(out, event, jsonOutput); err != nil {
+				return err
+			}
+
+			if filter.Since == nil || event.CreatedAt.After(*filter.Since) {
+				createdAt := event.CreatedAt
+				filter.Since = &createdAt
+			}
+		}
+
+		if pause.For(ctx, interval); ctx.Err() != nil {
+			return nil
+		}
+
+		if events, err = apiClient.GetAuditEvents(ctx, org, filter, limit); err != nil {
+			return fmt.Errorf("failed retrieving audit events: %w", err)
+		}
+	}
+}
+
+// orgSlug returns the slug of the organization given with --org, or of the
+// app given with --app, or of the one the user picks.
+func orgSlug(ctx context.Context, apiClient *api.Client, appName string) (string, error) {
+	if slug := flag.GetOrg(ctx); slug != "" || appName == "" {
+		org, err := prompt.Org(ctx)
+		if err != nil {
+			return "", err
+		}
+
+		return org.Slug, nil
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	return app.Organization.Slug, nil
+}
+
+func eventRow(event api.AuditEvent) []string {
+	var appName, email string
+	if event.App != nil {
+		appName = event.App.Name
+	}
+	if event.User != nil {
+		email = event.User.Email
+	}
+
+	return []string{
+		event.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+		event.Type,
+		appName,
+		email,
+		event.IPAddress,
+		event.Description,
+	}
+}
+
+func printEvent(w io.Writer, event api.AuditEvent, jsonOutput bool) error {
+	if jsonOutput {
+		return json.NewEncoder(w).Encode(event)
+	}
+
+	row := eventRow(event)
+	for i, field := range row {
+		if field == "" {
+			row[i] = "-"
+		}
+	}
+
+	_, err := fmt.Fprintf(w, "%s %s %s %s %s %s\n", row[0], row[1], row[2], row[3], row[4], row[5])
+
+	return err
+}
@@ -29,9 +29,12 @@ func New() (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newRun(),
 		newPing(),
+		newStatus(),
 		newStart(),
 		newStop(),
 		newRestart(),
+		newInstall(),
+		newUninstall(),
 	)
 
 	if env.IsTruthy("DEV") {
@@ -65,6 +68,7 @@ func dial(ctx context.Context) (client *agent.Client, err error) {
 	return
 }
 
-func socketPath(ctx context.Context) string {
+// SocketPath returns the path of the socket the agent listens on.
+func SocketPath(ctx context.Context) string {
 	return filepath.Join(state.ConfigDirectory(ctx), "fly-agent.sock")
 }
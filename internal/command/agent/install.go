@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azazeal/pause"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/state"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newInstall() (cmd *cobra.Command) {
+	const (
+		short = "Install the Fly agent as a system service"
+		long  = `Install the Fly agent as a service of the current user, so that it starts
+automatically at login. The agent is installed as a systemd user unit on Linux
+and a launchd agent on macOS, which restart it when it fails, backing off when
+it keeps failing and giving up after 5 failures in 10 minutes. On Windows it's
+installed as a scheduled task run at logon, which isn't restarted when it fails;
+the agent is then started on demand again. Its log is written to agent.log in
+the flyctl configuration directory, which is rotated once it grows past 10MB.
+`
+	)
+
+	cmd = command.New("install", short, long, runInstall,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return
+}
+
+func newUninstall() (cmd *cobra.Command) {
+	const (
+		short = "Uninstall the Fly agent system service"
+		long  = short + "\n"
+	)
+
+	cmd = command.New("uninstall", short, long, runUninstall)
+
+	cmd.Args = cobra.NoArgs
+
+	return
+}
+
+func runInstall(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed locating flyctl: %w", err)
+	}
+
+	// the agent started on demand holds the lock the service's one needs
+	if client, err := dial(ctx); err == nil {
+		if err := client.Kill(ctx); err != nil {
+			return fmt.Errorf("failed stopping running agent: %w", err)
+		}
+
+		pause.For(ctx, time.Second)
+	}
+
+	svc := service{
+		exe:     exe,
+		logPath: serviceLogPath(ctx),
+	}
+
+	location, err := svc.install(ctx)
+	if err != nil {
+		return fmt.Errorf("failed installing agent service: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Installed the Fly agent service at %s\n", location)
+	fmt.Fprintf(io.Out, "Its log is written to %s\n", svc.logPath)
+
+	return nil
+}
+
+func runUninstall(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	svc := service{
+		logPath: serviceLogPath(ctx),
+	}
+
+	if err := svc.uninstall(ctx); err != nil {
+		return fmt.Errorf("failed uninstalling agent service: %w", err)
+	}
+
+	fmt.Fprintln(io.Out, "Uninstalled the Fly agent service; the agent will be started on demand again")
+
+	return nil
+}
+
+var errServiceNotInstalled = errors.New("the agent isn't installed as a service")
+
+// service describes the agent service to install.
+type service struct {
+	exe     string
+	logPath string
+}
+
+// serviceEnv is set in the environment of the agent run by the service, which
+// bounds how often it's restarted.
+const serviceEnv = "FLY_AGENT_SERVICE"
+
+// args returns the command line the service runs.
+func (s *service) args() []string {
+	return []string{s.exe, "agent", "run", s.logPath}
+}
+
+func serviceLogPath(ctx context.Context) string {
+	return filepath.Join(state.ConfigDirectory(ctx), "agent.log")
+}
+
+// execService runs a command of the service manager, including its output in
+// the error it fails with.
+func execService(ctx context.Context, name string, args ...string) error {
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
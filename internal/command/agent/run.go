@@ -7,13 +7,19 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/azazeal/pause"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/agent/server"
+	"github.com/superfly/flyctl/api"
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/filemu"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/state"
@@ -33,7 +39,7 @@ func newRun() (cmd *cobra.Command) {
 	return
 }
 
-func run(ctx context.Context) error {
+func run(ctx context.Context) (err error) {
 	logPath := flag.FirstArg(ctx)
 	logger, closeLogger, err := setupLogger(logPath)
 	if err != nil {
@@ -44,6 +50,27 @@ func run(ctx context.Context) error {
 	}
 	defer closeLogger()
 
+	if os.Getenv(serviceEnv) != "" {
+		path, start := filepath.Join(state.ConfigDirectory(ctx), "agent-starts"), time.Now()
+
+		delay, giveUp := serviceBackoff(path, start)
+		if giveUp {
+			// exiting successfully stops the service from restarting it
+			logger.Printf("the agent failed more than %d times in %s, giving up until the service is restarted", maxServiceStarts, serviceStartsWindow)
+
+			return nil
+		}
+
+		pause.For(ctx, delay)
+
+		stable := time.AfterFunc(serviceStableAfter, func() { serviceStarted(path, start) })
+		defer func() {
+			if stable.Stop() && err == nil {
+				serviceStarted(path, start)
+			}
+		}()
+	}
+
 	apiClient := client.FromContext(ctx)
 	if !apiClient.Authenticated() {
 		logger.Println(client.ErrNoAuthToken)
@@ -58,27 +85,58 @@ func run(ctx context.Context) error {
 	defer unlock()
 
 	opt := server.Options{
-		Socket:     socketPath(ctx),
+		Socket:     SocketPath(ctx),
 		Logger:     logger,
-		Client:     apiClient.API(),
 		Background: logPath != "",
 		ConfigFile: state.ConfigFile(ctx),
+		Context:    config.FromContext(ctx).Context,
+		Client:     sessionClient(ctx, apiClient.API()),
 	}
 
-	return server.Run(ctx, opt)
+	err = server.Run(ctx, opt)
+
+	return
+}
+
+// sessionClient returns the function the agent gets its API client with. The
+// agent outlives the short-lived access tokens of SSO sessions, so the client
+// is replaced once the token is refreshed, or taken from the config file after
+// a command refreshed it first.
+func sessionClient(ctx context.Context, apiClient *api.Client) func(context.Context) (*api.Client, error) {
+	cfg := config.FromContext(ctx)
+	token := cfg.AccessToken
+
+	var mu sync.Mutex
+
+	return func(context.Context) (*api.Client, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// refreshes outlive the sessions asking for them, so they run in the
+		// context of the agent
+		if err := command.RefreshSession(ctx, cfg); err != nil && time.Now().After(cfg.AccessTokenExpiresAt) {
+			return nil, fmt.Errorf("failed refreshing your SSO session, run fly auth login --sso to log in again: %w", err)
+		}
+
+		if cfg.AccessToken != token {
+			token = cfg.AccessToken
+			apiClient = client.FromToken(token).API()
+		}
+
+		return apiClient, nil
+	}
 }
 
 func setupLogger(path string) (logger *log.Logger, close func(), err error) {
 	var out io.Writer
 	if path != "" {
-		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+		f, err := openRotatingFile(path, maxLogSize)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		out = io.MultiWriter(os.Stdout, f)
 		close = func() {
-			_ = f.Sync()
 			_ = f.Close()
 		}
 	} else {
@@ -123,3 +181,133 @@ func lock(ctx context.Context, logger *log.Logger) (unlock filemu.UnlockFunc, er
 
 	return
 }
+
+const (
+	maxServiceStarts    = 5
+	serviceStartsWindow = 10 * time.Minute
+	maxServiceBackoff   = time.Minute
+	serviceStableAfter  = time.Minute
+)
+
+// serviceBackoff records a start of the agent service in the file at path,
+// which holds the times of its recent failed starts, and returns how long to
+// wait before running the agent, doubling with each recent failure, and
+// whether to give up as it keeps failing. The start counts as failed until
+// serviceStarted forgets it.
+func serviceBackoff(path string, now time.Time) (delay time.Duration, giveUp bool) {
+	data, _ := os.ReadFile(path)
+
+	var starts []string
+	for _, field := range strings.Fields(string(data)) {
+		if at, err := time.Parse(time.RFC3339, field); err == nil && now.Sub(at) < serviceStartsWindow {
+			starts = append(starts, field)
+		}
+	}
+	starts = append(starts, now.Format(time.RFC3339))
+
+	_ = os.WriteFile(path, []byte(strings.Join(starts, "\n")+"\n"), 0o600)
+
+	recent := len(starts) - 1
+	if recent > maxServiceStarts {
+		return 0, true
+	}
+
+	if recent > 0 {
+		delay = time.Second << recent
+		if delay > maxServiceBackoff {
+			delay = maxServiceBackoff
+		}
+	}
+
+	return delay, false
+}
+
+// serviceStarted forgets the start of the agent service at the given time,
+// once the agent ran for serviceStableAfter or exited cleanly, so that only
+// failed starts count towards giving up.
+func serviceStarted(path string, at time.Time) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	var starts []string
+	for _, field := range strings.Fields(string(data)) {
+		if field != at.Format(time.RFC3339) {
+			starts = append(starts, field)
+		}
+	}
+
+	_ = os.WriteFile(path, []byte(strings.Join(starts, "\n")+"\n"), 0o600)
+}
+
+// maxLogSize is the size past which the log of a long running agent, such as
+// one installed as a service, is rotated.
+const maxLogSize = 10 << 20
+
+// rotatingFile is a log file which, once it grows past its maximum size, is
+// moved aside to path.1 and started over.
+type rotatingFile struct {
+	mu   sync.Mutex
+	path string
+	max  int64
+	f    *os.File
+	size int64
+}
+
+func openRotatingFile(path string, max int64) (*rotatingFile, error) {
+	rf := &rotatingFile{path: path, max: max}
+	if err := rf.open(); err != nil {
+		return nil, err
+	}
+
+	return rf, nil
+}
+
+func (rf *rotatingFile) open() (err error) {
+	if rf.f, err = os.OpenFile(rf.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600); err != nil {
+		return
+	}
+
+	var info os.FileInfo
+	if info, err = rf.f.Stat(); err != nil {
+		_ = rf.f.Close()
+
+		return
+	}
+
+	rf.size = info.Size()
+
+	return
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	if rf.size > 0 && rf.size+int64(len(p)) > rf.max {
+		_ = rf.f.Close()
+
+		if err := os.Rename(rf.path, rf.path+".1"); err != nil {
+			return 0, err
+		}
+
+		if err := rf.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := rf.f.Write(p)
+	rf.size += int64(n)
+
+	return n, err
+}
+
+func (rf *rotatingFile) Close() error {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	_ = rf.f.Sync()
+
+	return rf.f.Close()
+}
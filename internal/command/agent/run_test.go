@@ -0,0 +1,51 @@
+package agent
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceBackoff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-starts")
+	now := time.Date(2022, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	delays := []time.Duration{0, 2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second, 32 * time.Second}
+	for i, want := range delays {
+		delay, giveUp := serviceBackoff(path, now.Add(time.Duration(i)*time.Second))
+		assert.False(t, giveUp, "start %d", i)
+		assert.Equal(t, want, delay, "start %d", i)
+	}
+
+	_, giveUp := serviceBackoff(path, now.Add(time.Minute))
+	assert.True(t, giveUp)
+
+	// starts older than the window are forgotten
+	delay, giveUp := serviceBackoff(path, now.Add(serviceStartsWindow+2*time.Minute))
+	assert.False(t, giveUp)
+	assert.Equal(t, time.Duration(0), delay)
+}
+
+func TestServiceStarted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agent-starts")
+	now := time.Date(2022, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	// starts that turned out fine don't count towards backing off
+	for i := 0; i < 2*maxServiceStarts; i++ {
+		start := now.Add(time.Duration(i) * time.Second)
+
+		delay, giveUp := serviceBackoff(path, start)
+		assert.False(t, giveUp, "start %d", i)
+		assert.Equal(t, time.Duration(0), delay, "start %d", i)
+
+		serviceStarted(path, start)
+	}
+
+	delay, _ := serviceBackoff(path, now.Add(time.Minute))
+	assert.Equal(t, time.Duration(0), delay)
+
+	delay, _ = serviceBackoff(path, now.Add(2*time.Minute))
+	assert.Equal(t, 2*time.Second, delay)
+}
@@ -0,0 +1,108 @@
+//go:build darwin
+// +build darwin
+
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const launchdLabel = "io.fly.agent"
+
+func launchdPlistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, "Library", "LaunchAgents", launchdLabel+".plist"), nil
+}
+
+func (s *service) install(ctx context.Context) (string, error) {
+	path, err := launchdPlistPath()
+	if err != nil {
+		return "", err
+	}
+
+	var args bytes.Buffer
+	for _, arg := range s.args() {
+		args.WriteString("\n\t\t<string>")
+		if err := xml.EscapeText(&args, []byte(arg)); err != nil {
+			return "", err
+		}
+		args.WriteString("</string>")
+	}
+
+	plist := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>%s</string>
+	<key>ProgramArguments</key>
+	<array>%s
+	</array>
+	<key>EnvironmentVariables</key>
+	<dict>
+		<key>FLY_NO_UPDATE_CHECK</key>
+		<string>1</string>
+		<key>%s</key>
+		<string>1</string>
+	</dict>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>ThrottleInterval</key>
+	<integer>5</integer>
+	<key>StandardOutPath</key>
+	<string>/dev/null</string>
+</dict>
+</plist>
+`, launchdLabel, args.String(), serviceEnv)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	// reinstalling replaces the loaded service
+	if _, err := os.Stat(path); err == nil {
+		_ = execService(ctx, "launchctl", "unload", "-w", path)
+	}
+
+	if err := os.WriteFile(path, []byte(plist), 0o644); err != nil {
+		return "", err
+	}
+
+	if err := execService(ctx, "launchctl", "load", "-w", path); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (s *service) uninstall(ctx context.Context) error {
+	path, err := launchdPlistPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return errServiceNotInstalled
+	}
+
+	if err := execService(ctx, "launchctl", "unload", "-w", path); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
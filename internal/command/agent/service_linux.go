@@ -0,0 +1,90 @@
+//go:build linux
+// +build linux
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const systemdUnit = "fly-agent.service"
+
+func systemdUnitPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "systemd", "user", systemdUnit), nil
+}
+
+func (s *service) install(ctx context.Context) (string, error) {
+	path, err := systemdUnitPath()
+	if err != nil {
+		return "", err
+	}
+
+	quoted := make([]string, 0, len(s.args()))
+	for _, arg := range s.args() {
+		quoted = append(quoted, strconv.Quote(arg))
+	}
+
+	unit := fmt.Sprintf(`[Unit]
+Description=Fly agent, managing the WireGuard connections of flyctl
+
+[Service]
+ExecStart=%s
+Environment=FLY_NO_UPDATE_CHECK=1 %s=1
+Restart=on-failure
+RestartSec=5
+StandardOutput=null
+
+[Install]
+WantedBy=default.target
+`, strings.Join(quoted, " "), serviceEnv)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(path, []byte(unit), 0o644); err != nil {
+		return "", err
+	}
+
+	if err := execService(ctx, "systemctl", "--user", "daemon-reload"); err != nil {
+		return "", err
+	}
+
+	if err := execService(ctx, "systemctl", "--user", "enable", "--now", systemdUnit); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (s *service) uninstall(ctx context.Context) error {
+	path, err := systemdUnitPath()
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return errServiceNotInstalled
+	}
+
+	if err := execService(ctx, "systemctl", "--user", "disable", "--now", systemdUnit); err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	return execService(ctx, "systemctl", "--user", "daemon-reload")
+}
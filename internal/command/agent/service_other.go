@@ -0,0 +1,18 @@
+//go:build !linux && !darwin && !windows
+// +build !linux,!darwin,!windows
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"runtime"
+)
+
+func (s *service) install(context.Context) (string, error) {
+	return "", errors.New("installing the agent as a service isn't supported on " + runtime.GOOS)
+}
+
+func (s *service) uninstall(context.Context) error {
+	return errServiceNotInstalled
+}
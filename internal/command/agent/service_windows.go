@@ -0,0 +1,47 @@
+//go:build windows
+// +build windows
+
+package agent
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+)
+
+// the agent needs the credentials of the user, so it runs as a task started at
+// their logon rather than as a Windows service
+const scheduledTask = "Fly Agent"
+
+func (s *service) install(ctx context.Context) (string, error) {
+	quoted := make([]string, 0, len(s.args()))
+	for _, arg := range s.args() {
+		quoted = append(quoted, `"`+arg+`"`)
+	}
+
+	if err := execService(ctx, "schtasks", "/Create", "/F",
+		"/TN", scheduledTask,
+		"/TR", strings.Join(quoted, " "),
+		"/SC", "ONLOGON",
+		"/RL", "LIMITED",
+	); err != nil {
+		return "", err
+	}
+
+	if err := execService(ctx, "schtasks", "/Run", "/TN", scheduledTask); err != nil {
+		return "", err
+	}
+
+	return `Task Scheduler\` + scheduledTask, nil
+}
+
+func (s *service) uninstall(ctx context.Context) error {
+	if err := exec.CommandContext(ctx, "schtasks", "/Query", "/TN", scheduledTask).Run(); err != nil {
+		return errServiceNotInstalled
+	}
+
+	// the task isn't running when the agent exited on its own
+	_ = execService(ctx, "schtasks", "/End", "/TN", scheduledTask)
+
+	return execService(ctx, "schtasks", "/Delete", "/F", "/TN", scheduledTask)
+}
@@ -0,0 +1,298 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/net/icmp"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/internal/buildinfo"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/ping"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStatus() (cmd *cobra.Command) {
+	const (
+		short = "Show the status of the Fly agent and its tunnels"
+		long  = `Show the status of the Fly agent and of the WireGuard tunnel it maintains for
+each organization. With --verbose, the agent's socket is checked and each tunnel
+is tested for packet loss and resolving .internal names, with suggested fixes
+for the problems found.
+`
+	)
+
+	cmd = command.New("status", short, long, runStatus,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return
+}
+
+// staleHandshake is the age past which the handshake of a tunnel means its
+// peer stopped responding; WireGuard handshakes every 2 minutes otherwise.
+const staleHandshake = 3 * time.Minute
+
+type tunnelReport struct {
+	agent.TunnelStatus
+
+	PacketLoss *float64      `json:",omitempty"`
+	DNSLatency time.Duration `json:",omitempty"`
+	DNSError   string        `json:",omitempty"`
+}
+
+type finding struct {
+	Problem string
+	Fix     string
+}
+
+type statusReport struct {
+	Socket   string
+	Agent    *agent.PingResponse `json:",omitempty"`
+	Tunnels  []tunnelReport
+	Findings []finding
+}
+
+func runStatus(ctx context.Context) error {
+	verbose := config.FromContext(ctx).VerboseOutput
+
+	report := &statusReport{
+		Socket: agent.PathToSocket(),
+	}
+
+	if verbose {
+		report.Findings = append(report.Findings, checkSocket(report.Socket)...)
+	}
+
+	client, err := agent.DefaultClient(ctx)
+	if err == nil {
+		var pong agent.PingResponse
+		if pong, err = client.Ping(ctx); err == nil {
+			report.Agent = &pong
+		}
+	}
+
+	if err != nil {
+		report.Findings = append(report.Findings, finding{
+			Problem: fmt.Sprintf("The agent isn't reachable: %v", err),
+			Fix:     "Start it with fly agent start, or install it as a service with fly agent install",
+		})
+
+		return printStatus(ctx, report)
+	}
+
+	if !buildinfo.Version().EQ(report.Agent.Version) {
+		report.Findings = append(report.Findings, finding{
+			Problem: fmt.Sprintf("The agent (v%s) doesn't match flyctl (v%s)", report.Agent.Version, buildinfo.Version()),
+			Fix:     "Restart it with fly agent restart",
+		})
+	}
+
+	// agents older than flyctl may not know the status command, so failing to
+	// fetch it still reports the mismatch found above
+	status, err := client.Status(ctx)
+	if err != nil {
+		report.Findings = append(report.Findings, finding{
+			Problem: fmt.Sprintf("The agent's tunnels couldn't be fetched: %v", err),
+			Fix:     "Restart it with fly agent restart",
+		})
+
+		return printStatus(ctx, report)
+	}
+
+	for _, tunnel := range status.Tunnels {
+		tr := tunnelReport{TunnelStatus: tunnel}
+
+		switch age := time.Since(tunnel.LastHandshake); {
+		case tunnel.LastHandshake.IsZero():
+			report.Findings = append(report.Findings, finding{
+				Problem: fmt.Sprintf("The %s tunnel hasn't completed a handshake with %s", tunnel.Org, tunnel.Endpoint),
+				Fix:     "UDP port 51820 may be blocked on your network; run fly wireguard websockets enable and fly agent restart",
+			})
+		case age > staleHandshake:
+			report.Findings = append(report.Findings, finding{
+				Problem: fmt.Sprintf("The last handshake of the %s tunnel was %s ago", tunnel.Org, age.Round(time.Second)),
+				Fix:     "Restart the agent with fly agent restart; if that doesn't help, run fly wireguard reset " + tunnel.Org,
+			})
+		}
+
+		if verbose {
+			if loss, err := packetLoss(ctx, client, tunnel); err == nil {
+				tr.PacketLoss = &loss
+
+				if loss > 0 {
+					report.Findings = append(report.Findings, finding{
+						Problem: fmt.Sprintf("%.0f%% of packets were lost over the %s tunnel", loss*100, tunnel.Org),
+						Fix:     "Check your network connection; on unreliable networks, fly wireguard websockets enable may help",
+					})
+				}
+			}
+
+			start := time.Now()
+			if _, err := client.Resolve(ctx, tunnel.Org, "_api.internal"); err != nil {
+				tr.DNSError = err.Error()
+
+				report.Findings = append(report.Findings, finding{
+					Problem: fmt.Sprintf("_api.internal doesn't resolve over the %s tunnel: %v", tunnel.Org, err),
+					Fix:     "Reset the tunnel with fly wireguard reset " + tunnel.Org,
+				})
+			} else {
+				tr.DNSLatency = time.Since(start)
+			}
+		}
+
+		report.Tunnels = append(report.Tunnels, tr)
+	}
+
+	return printStatus(ctx, report)
+}
+
+// checkSocket looks for problems with the agent's socket file.
+func checkSocket(path string) (findings []finding) {
+	info, err := os.Stat(path)
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return
+	case err != nil:
+		return append(findings, finding{
+			Problem: fmt.Sprintf("The agent socket at %s can't be read: %v", path, err),
+			Fix:     "Make sure you own the directory it's in",
+		})
+	case runtime.GOOS == "windows":
+		return
+	case info.Mode()&os.ModeSocket == 0:
+		return append(findings, finding{
+			Problem: fmt.Sprintf("%s isn't a socket, so the agent can't listen on it", path),
+			Fix:     "Remove it and run fly agent restart",
+		})
+	case info.Mode().Perm()&0o022 != 0:
+		findings = append(findings, finding{
+			Problem: fmt.Sprintf("Other users can connect to the agent socket at %s, and use your tunnels", path),
+			Fix:     fmt.Sprintf("Run chmod 600 %s", path),
+		})
+	}
+
+	if owner, ok := fileOwner(info); ok && owner != os.Getuid() {
+		findings = append(findings, finding{
+			Problem: fmt.Sprintf("The agent socket at %s belongs to another user, likely from running flyctl with sudo", path),
+			Fix:     "Stop that agent, remove the socket and run fly agent start as yourself",
+		})
+	}
+
+	return
+}
+
+// packetLoss pings the DNS server of the tunnel a few times and returns the
+// ratio of echo requests that went unanswered.
+func packetLoss(ctx context.Context, client *agent.Client, tunnel agent.TunnelStatus) (float64, error) {
+	const count = 5
+
+	pinger, err := client.Pinger(ctx, tunnel.Org)
+	if err != nil {
+		return 0, err
+	}
+	defer pinger.Close()
+
+	for i := 0; i < count; i++ {
+		if _, err := pinger.WriteTo(ping.EchoRequest(0, i, time.Now(), 0), &net.IPAddr{IP: tunnel.DNS}); err != nil {
+			return 0, err
+		}
+	}
+
+	received := map[int]bool{}
+	deadline := time.Now().Add(2 * time.Second)
+
+	for len(received) < count && time.Now().Before(deadline) {
+		if err := pinger.SetReadDeadline(deadline); err != nil {
+			return 0, err
+		}
+
+		buf := make([]byte, 1500)
+		n, _, err := pinger.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+
+		if msg, err := icmp.ParseMessage(58, buf[:n]); err == nil {
+			if echo, ok := msg.Body.(*icmp.Echo); ok {
+				received[echo.Seq] = true
+			}
+		}
+	}
+
+	return float64(count-len(received)) / count, nil
+}
+
+func printStatus(ctx context.Context, report *statusReport) error {
+	out := iostreams.FromContext(ctx).Out
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, report)
+	}
+
+	fmt.Fprintf(out, "%-10s: %s\n", "Socket", report.Socket)
+	if report.Agent != nil {
+		fmt.Fprintf(out, "%-10s: %d\n", "PID", report.Agent.PID)
+		fmt.Fprintf(out, "%-10s: %s\n", "Version", report.Agent.Version)
+		fmt.Fprintf(out, "%-10s: %t\n", "Background", report.Agent.Background)
+	}
+
+	if len(report.Tunnels) > 0 {
+		rows := make([][]string, 0, len(report.Tunnels))
+		for _, tunnel := range report.Tunnels {
+			handshake := "never"
+			if !tunnel.LastHandshake.IsZero() {
+				handshake = time.Since(tunnel.LastHandshake).Round(time.Second).String() + " ago"
+			}
+
+			loss, dns := "-", "-"
+			if tunnel.PacketLoss != nil {
+				loss = fmt.Sprintf("%.0f%%", *tunnel.PacketLoss*100)
+			}
+			switch {
+			case tunnel.DNSError != "":
+				dns = "failed"
+			case tunnel.DNSLatency > 0:
+				dns = tunnel.DNSLatency.Round(time.Millisecond).String()
+			}
+
+			rows = append(rows, []string{
+				tunnel.Org,
+				tunnel.Endpoint,
+				handshake,
+				fmt.Sprintf("%d/%d", tunnel.RxBytes, tunnel.TxBytes),
+				loss,
+				dns,
+			})
+		}
+
+		fmt.Fprintln(out)
+		if err := render.Table(out, "Tunnels", rows, "Org", "Endpoint", "Last Handshake", "Bytes In/Out", "Packet Loss", "DNS"); err != nil {
+			return err
+		}
+	} else if report.Agent != nil {
+		fmt.Fprintln(out, "\nThe agent has no tunnels open")
+	}
+
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(out, "\nProblems found:")
+		for _, f := range report.Findings {
+			fmt.Fprintf(out, "  * %s\n    %s\n", f.Problem, f.Fix)
+		}
+	}
+
+	return nil
+}
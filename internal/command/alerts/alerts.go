@@ -0,0 +1,99 @@
+// Package alerts implements the alerts command chain.
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing alert rules, which notify email addresses, Slack
+channels or PagerDuty services when health checks keep failing, when apps
+respond with too many errors, or when machines run out of memory. Rules apply
+to all the apps of an organization, or to a single app with --app.
+
+Alert rules need the API to serve them, which it doesn't everywhere yet.`
+		short = "Manage alert rules"
+	)
+
+	cmd := command.New("alerts", short, long, nil)
+
+	cmd.AddCommand(
+		newList(),
+		newCreate(),
+		newDelete(),
+	)
+
+	return cmd
+}
+
+// requireAlerts makes sure the API serves alert rules.
+var requireAlerts = command.RequireAPI("Organization", "alertRules")
+
+// scopeFlags pick the organization, or the app, rules apply to.
+var scopeFlags = flag.Set{
+	flag.Org(),
+	flag.String{
+		Name:        flag.AppName,
+		Shorthand:   "a",
+		Description: "Application the rules apply to, rather than all the apps of the organization",
+	},
+}
+
+// scope returns the organization rules are managed for, and the app they're
+// narrowed down to with --app, if any.
+func scope(ctx context.Context) (*api.OrganizationBasic, *api.AppCompact, error) {
+	if appName := flag.GetApp(ctx); appName != "" {
+		app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		}
+
+		return app.Organization, app, nil
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &api.OrganizationBasic{ID: org.ID, Slug: org.Slug}, nil, nil
+}
+
+// describeCondition spells out when rule notifies its targets.
+func describeCondition(rule api.AlertRule) string {
+	switch rule.Condition {
+	case "CHECK_FAILING":
+		check := "a health check"
+		if rule.CheckName != "" {
+			check = "health check " + rule.CheckName
+		}
+
+		return fmt.Sprintf("%s failing for %dm", check, rule.Minutes)
+	case "ERROR_RATE":
+		return fmt.Sprintf("over %g%% of responses errors for %dm", rule.Threshold, rule.Minutes)
+	case "MACHINE_OOM":
+		return "a machine running out of memory"
+	default:
+		return strings.ToLower(rule.Condition)
+	}
+}
+
+func describeTargets(targets []api.AlertTarget) string {
+	described := make([]string, 0, len(targets))
+	for _, target := range targets {
+		described = append(described, fmt.Sprintf("%s %s", strings.ToLower(target.Type), target.Address))
+	}
+
+	return strings.Join(described, ", ")
+}
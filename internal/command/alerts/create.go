@@ -0,0 +1,194 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// conditions maps the conditions create takes to those of the API.
+var conditions = map[string]string{
+	"check-failing": "CHECK_FAILING",
+	"error-rate":    "ERROR_RATE",
+	"oom":           "MACHINE_OOM",
+}
+
+func newCreate() *cobra.Command {
+	const (
+		long = `Creates an alert rule, which notifies its targets when its condition holds:
+
+  check-failing  a health check, or the one given with --check, has been
+                 failing on a machine for --for
+  error-rate     more than --threshold percent of the responses of an app have
+                 been 5xx errors for --for
+  oom            a machine was killed for running out of memory
+
+Targets are given with --email, --slack-webhook-url and --pagerduty-token, at
+least one of which is required.`
+		short = "Create an alert rule"
+		usage = "create <name>"
+	)
+
+	cmd := command.New(usage, short, long, runCreate,
+		requireAlerts,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		scopeFlags,
+		flag.String{
+			Name:        "condition",
+			Description: "What to alert on: check-failing, error-rate or oom",
+		},
+		flag.Duration{
+			Name:        "for",
+			Description: "How long the condition must hold before alerting, in whole minutes",
+			Default:     5 * time.Minute,
+		},
+		flag.String{
+			Name:        "check",
+			Description: "Name of the health check check-failing rules apply to, defaults to all",
+		},
+		flag.String{
+			Name:        "threshold",
+			Description: "Percentage of responses that must be errors for error-rate rules, like 5",
+		},
+		flag.StringSlice{
+			Name:        "email",
+			Description: "Email address to notify, comma separated or repeated",
+		},
+		flag.String{
+			Name:        "slack-webhook-url",
+			Description: "Slack webhook URL to notify",
+		},
+		flag.String{
+			Name:        "slack-channel",
+			Description: "Slack channel to notify, defaults to the webhook's configured channel",
+		},
+		flag.String{
+			Name:        "pagerduty-token",
+			Description: "PagerDuty integration token to notify",
+		},
+	)
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+		name   = flag.FirstArg(ctx)
+	)
+
+	input := api.CreateAlertRuleInput{
+		Name:      name,
+		CheckName: flag.GetString(ctx, "check"),
+	}
+
+	condition := flag.GetString(ctx, "condition")
+	if input.Condition = conditions[condition]; input.Condition == "" {
+		return fmt.Errorf("unsupported condition %q; use check-failing, error-rate or oom", condition)
+	}
+
+	if input.CheckName != "" && input.Condition != "CHECK_FAILING" {
+		return errors.New("--check only applies to check-failing rules")
+	}
+
+	if input.Condition != "MACHINE_OOM" {
+		d := flag.GetDuration(ctx, "for")
+		if d < time.Minute || d%time.Minute != 0 {
+			return fmt.Errorf("--for must be a whole number of minutes, got %s", d)
+		}
+
+		input.Minutes = int(d / time.Minute)
+	}
+
+	threshold := flag.GetString(ctx, "threshold")
+	switch {
+	case input.Condition == "ERROR_RATE":
+		v, err := strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		if err != nil || v <= 0 || v > 100 {
+			return fmt.Errorf("--threshold must be a percentage above 0 and up to 100, got %q", threshold)
+		}
+
+		input.Threshold = v
+	case threshold != "":
+		return errors.New("--threshold only applies to error-rate rules")
+	}
+
+	targets, err := targetInputs(ctx)
+	if err != nil {
+		return err
+	}
+	input.Targets = targets
+
+	org, app, err := scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	input.OrganizationID = org.ID
+	if app != nil {
+		input.AppID = app.ID
+	}
+
+	rule, err := client.CreateAlertRule(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed creating alert rule: %w", err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, rule)
+	}
+
+	fmt.Fprintf(out, "Created alert rule %s (%s), alerting %s on %s\n", rule.Name, rule.ID, describeTargets(rule.Targets), describeCondition(*rule))
+
+	return nil
+}
+
+func targetInputs(ctx context.Context) ([]api.AlertTargetInput, error) {
+	var targets []api.AlertTargetInput
+
+	for _, email := range flag.GetStringSlice(ctx, "email") {
+		if !strings.Contains(email, "@") {
+			return nil, fmt.Errorf("%q is not an email address", email)
+		}
+
+		targets = append(targets, api.AlertTargetInput{Type: "EMAIL", Email: email})
+	}
+
+	webhookURL := flag.GetString(ctx, "slack-webhook-url")
+	channel := flag.GetString(ctx, "slack-channel")
+
+	switch {
+	case webhookURL != "":
+		targets = append(targets, api.AlertTargetInput{Type: "SLACK", SlackWebhookURL: webhookURL, SlackChannel: channel})
+	case channel != "":
+		return nil, errors.New("--slack-channel requires --slack-webhook-url")
+	}
+
+	if token := flag.GetString(ctx, "pagerduty-token"); token != "" {
+		targets = append(targets, api.AlertTargetInput{Type: "PAGERDUTY", PagerdutyToken: token})
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("at least one of --email, --slack-webhook-url or --pagerduty-token is required")
+	}
+
+	return targets, nil
+}
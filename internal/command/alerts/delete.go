@@ -0,0 +1,65 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDelete() *cobra.Command {
+	const (
+		long  = `Deletes the alert rule with the given ID, as shown by the list command.`
+		short = "Delete an alert rule"
+		usage = "delete <id>"
+	)
+
+	cmd := command.New(usage, short, long, runDelete,
+		requireAlerts,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+	cmd.Aliases = []string{"rm"}
+
+	flag.Add(cmd,
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runDelete(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		id     = flag.FirstArg(ctx)
+	)
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Delete alert rule %s?", id); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return errors.New("--yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := client.DeleteAlertRule(ctx, id); err != nil {
+		return fmt.Errorf("failed deleting alert rule %s: %w", id, err)
+	}
+
+	fmt.Fprintf(io.Out, "Deleted alert rule %s\n", id)
+
+	return nil
+}
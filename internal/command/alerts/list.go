@@ -0,0 +1,87 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `Lists the alert rules of an organization, or those that apply to the app
+given with --app, which include the rules of its whole organization.`
+		short = "List alert rules"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		requireAlerts,
+	)
+
+	cmd.Args = cobra.NoArgs
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		scopeFlags,
+		flag.Format(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+	)
+
+	org, app, err := scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules, err := client.GetAlertRules(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if app != nil {
+		var applicable []api.AlertRule
+		for _, rule := range rules {
+			if rule.App == nil || rule.App.ID == app.ID {
+				applicable = append(applicable, rule)
+			}
+		}
+		rules = applicable
+	}
+
+	if ok, err := render.Structured(ctx, out, rules); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		appName := "all apps"
+		if rule.App != nil {
+			appName = rule.App.Name
+		}
+
+		rows = append(rows, []string{
+			rule.ID,
+			rule.Name,
+			appName,
+			describeCondition(rule),
+			describeTargets(rule.Targets),
+			format.RelativeTime(rule.CreatedAt),
+		})
+	}
+
+	return render.Table(out, "", rows, "ID", "Name", "App", "Alerts on", "Notifies", "Created")
+}
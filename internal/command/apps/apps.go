@@ -32,6 +32,7 @@ The LIST command will list all currently registered applications.
 		newRestart(),
 		NewOpen(),
 		NewReleases(),
+		newCrashes(),
 	)
 
 	return apps
@@ -0,0 +1,371 @@
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/parallel"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// signalNames are the names of the signals machines commonly exit with.
+var signalNames = map[int16]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	9:  "SIGKILL",
+	11: "SIGSEGV",
+	15: "SIGTERM",
+}
+
+func newCrashes() *cobra.Command {
+	const (
+		long = `Aggregates the exit events of the machines of an application, destroyed
+ones included, over the --since window into a report grouped by process group,
+region and reason: an OOM kill, a signal or a non-zero exit code. Clean exits
+and requested stops aren't counted. The platform keeps a bounded history of
+events per machine, so the oldest crashes of machines which crash often may be
+missing from long windows.
+
+The report ends with the rate of crashes since the latest release in the
+window, compared to the rate before it, when there are enough crashes on both
+sides of the release to tell them apart.
+`
+		short = "Report the crashes and OOM kills of an app's machines"
+	)
+
+	cmd := command.New("crashes", short, long, runCrashes,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Duration{
+			Name:        "since",
+			Description: "How far back to report crashes from",
+			Default:     7 * 24 * time.Hour,
+		},
+		flag.String{
+			Name:        "process-group",
+			Description: "Only report the crashes of machines in this process group",
+		},
+		flag.String{
+			Name:        "region",
+			Shorthand:   "r",
+			Description: "Only report the crashes of machines in this region",
+		},
+	)
+
+	return cmd
+}
+
+// crash is an exit event of a machine that wasn't a clean exit or a requested
+// stop.
+type crash struct {
+	Machine      string    `json:"machine"`
+	ProcessGroup string    `json:"process_group"`
+	Region       string    `json:"region"`
+	Reason       string    `json:"reason"`
+	Time         time.Time `json:"time"`
+}
+
+// crashGroup counts the crashes of a process group in a region for a reason.
+type crashGroup struct {
+	ProcessGroup string    `json:"process_group"`
+	Region       string    `json:"region"`
+	Reason       string    `json:"reason"`
+	Count        int       `json:"count"`
+	Last         time.Time `json:"last"`
+	Daily        []int     `json:"daily"`
+}
+
+func runCrashes(ctx context.Context) error {
+	var (
+		appName   = app.NameFromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		io        = iostreams.FromContext(ctx)
+		since     = flag.GetDuration(ctx, "since")
+	)
+
+	if since < time.Hour {
+		return errors.New("--since must be at least 1h")
+	}
+
+	appCompact, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	if appCompact.PlatformVersion != "machines" {
+		return fmt.Errorf("crash reports are only available for apps running on machines, %s runs on %s", appName, appCompact.PlatformVersion)
+	}
+
+	flapsClient, err := flaps.New(ctx, appCompact)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	// list destroyed machines as well, so that their crashes are reported
+	machines, err := flapsClient.List(ctx, "include_deleted=true")
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	// machines are listed along with their latest few events only
+	var (
+		mu     sync.Mutex
+		failed int
+	)
+	parallel.Each(len(machines), parallel.Limit, func(i int) {
+		events, err := flapsClient.ListEvents(ctx, machines[i].ID)
+		if err != nil {
+			mu.Lock()
+			failed++
+			mu.Unlock()
+			return
+		}
+
+		machines[i].Events = events
+	})
+
+	if failed > 0 {
+		fmt.Fprintf(io.ErrOut, "WARNING: failed retrieving the events of %d machines, only their latest ones are reported\n", failed)
+	}
+
+	end := time.Now()
+	start := end.Add(-since)
+
+	crashes := collectCrashes(machines, start, flag.GetString(ctx, "process-group"), flag.GetString(ctx, "region"))
+	groups := groupCrashes(crashes, start, end)
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, groups)
+	}
+
+	if len(groups) == 0 {
+		fmt.Fprintf(io.Out, "No crashes in the last %s\n", humanize.RelTime(start, end, "", ""))
+		return nil
+	}
+
+	rows := make([][]string, 0, len(groups))
+	for _, g := range groups {
+		daily := make([]float64, len(g.Daily))
+		for i, count := range g.Daily {
+			daily[i] = float64(count)
+		}
+
+		rows = append(rows, []string{
+			g.ProcessGroup,
+			g.Region,
+			g.Reason,
+			strconv.Itoa(g.Count),
+			humanize.Time(g.Last),
+			render.Sparkline(daily),
+		})
+	}
+
+	if err := render.Table(io.Out, "Crashes", rows, "Process Group", "Region", "Reason", "Count", "Last", "Daily"); err != nil {
+		return err
+	}
+
+	// comparing against the latest release is best effort
+	releases, err := apiClient.GetAppReleases(ctx, appName, 25)
+	if err != nil {
+		return nil
+	}
+
+	for _, release := range releases {
+		if release.CreatedAt.After(start) && release.CreatedAt.Before(end) {
+			if rates, ok := describeCrashRates(crashes, release, start, end); ok {
+				fmt.Fprintln(io.Out, rates)
+			} else {
+				fmt.Fprintf(io.Out, "Too few crashes, or too short a window, around v%d to compare crash rates\n", release.Version)
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// collectCrashes returns the crashes of machines from start on, optionally
+// narrowed down to a process group and a region.
+func collectCrashes(machines []*api.Machine, start time.Time, processGroup, region string) (crashes []crash) {
+	for _, machine := range machines {
+		group := ""
+		if machine.Config != nil {
+			group = machine.Config.Metadata[api.MachineProcessGroupKey]
+		}
+
+		if processGroup != "" && group != processGroup {
+			continue
+		}
+
+		if region != "" && machine.Region != region {
+			continue
+		}
+
+		for _, event := range machine.Events {
+			if event.Type != "exit" || event.Request == nil || event.Request.ExitEvent == nil {
+				continue
+			}
+
+			at := time.UnixMilli(event.Timestamp)
+			if at.Before(start) {
+				continue
+			}
+
+			reason := crashReason(event.Request.ExitEvent)
+			if reason == "" {
+				continue
+			}
+
+			crashes = append(crashes, crash{
+				Machine:      machine.ID,
+				ProcessGroup: group,
+				Region:       machine.Region,
+				Reason:       reason,
+				Time:         at,
+			})
+		}
+	}
+
+	return
+}
+
+// crashReason describes why a machine exited, or returns an empty string for
+// clean exits and requested stops.
+func crashReason(exit *api.MachineExitEvent) string {
+	switch {
+	case exit.OOMKilled:
+		return "oom killed"
+	case exit.RequestedStop:
+		return ""
+	case exit.GuestSignal > 0:
+		return describeSignal(exit.GuestSignal)
+	case exit.Signal > 0:
+		return describeSignal(exit.Signal)
+	case exit.ExitCode != 0:
+		return fmt.Sprintf("exit code %d", exit.ExitCode)
+	default:
+		return ""
+	}
+}
+
+func describeSignal(signal int16) string {
+	if name, ok := signalNames[signal]; ok {
+		return fmt.Sprintf("signal %d (%s)", signal, name)
+	}
+
+	return fmt.Sprintf("signal %d", signal)
+}
+
+// groupCrashes groups crashes by process group, region and reason, most
+// frequent first, counting them per day of the window from start to end.
+func groupCrashes(crashes []crash, start, end time.Time) []*crashGroup {
+	days := int(end.Sub(start)/(24*time.Hour)) + 1
+
+	byKey := map[string]*crashGroup{}
+
+	var groups []*crashGroup
+	for _, c := range crashes {
+		key := c.ProcessGroup + "/" + c.Region + "/" + c.Reason
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &crashGroup{
+				ProcessGroup: c.ProcessGroup,
+				Region:       c.Region,
+				Reason:       c.Reason,
+				Daily:        make([]int, days),
+			}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+
+		g.Count++
+		if c.Time.After(g.Last) {
+			g.Last = c.Time
+		}
+
+		if day := int(c.Time.Sub(start) / (24 * time.Hour)); day >= 0 && day < days {
+			g.Daily[day]++
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+
+		return groups[i].Last.After(groups[j].Last)
+	})
+
+	return groups
+}
+
+// Crash rates are compared around a release only when there's at least a day
+// of the window on either side of it, and a few crashes in all; rates of
+// fewer are noise.
+const (
+	minCrashRateDays    = 1
+	minCrashRateCrashes = 5
+)
+
+// describeCrashRates compares the daily rates of crashes and OOM kills since
+// release to those before it. It reports false when there are too few
+// crashes, or too short a window on either side of release, to compare them.
+func describeCrashRates(crashes []crash, release api.Release, start, end time.Time) (string, bool) {
+	var before, after, oomBefore, oomAfter float64
+	for _, c := range crashes {
+		oom := c.Reason == "oom killed"
+
+		if c.Time.Before(release.CreatedAt) {
+			before++
+			if oom {
+				oomBefore++
+			}
+		} else {
+			after++
+			if oom {
+				oomAfter++
+			}
+		}
+	}
+
+	daysBefore := release.CreatedAt.Sub(start).Hours() / 24
+	daysAfter := end.Sub(release.CreatedAt).Hours() / 24
+
+	if daysBefore < minCrashRateDays || daysAfter < minCrashRateDays ||
+		before+after < minCrashRateCrashes {
+		return "", false
+	}
+
+	return fmt.Sprintf("Since v%d (%s): %.1f crashes/day, %.1f OOM kills/day; before: %.1f crashes/day, %.1f OOM kills/day",
+		release.Version,
+		humanize.Time(release.CreatedAt),
+		after/daysAfter, oomAfter/daysAfter,
+		before/daysBefore, oomBefore/daysBefore,
+	), true
+}
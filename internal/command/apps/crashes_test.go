@@ -0,0 +1,38 @@
+package apps
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestDescribeCrashRates(t *testing.T) {
+	var (
+		end     = time.Date(2022, 10, 8, 0, 0, 0, 0, time.UTC)
+		start   = end.Add(-4 * 24 * time.Hour)
+		release = api.Release{Version: 7, CreatedAt: end.Add(-2 * 24 * time.Hour)}
+	)
+
+	crashesAt := func(n int, at time.Time, reason string) (crashes []crash) {
+		for i := 0; i < n; i++ {
+			crashes = append(crashes, crash{Reason: reason, Time: at})
+		}
+		return
+	}
+
+	crashes := append(crashesAt(4, start.Add(time.Hour), "exit code 1"), crashesAt(2, end.Add(-time.Hour), "oom killed")...)
+
+	rates, ok := describeCrashRates(crashes, release, start, end)
+	assert.True(t, ok)
+	assert.Contains(t, rates, "Since v7")
+	assert.Contains(t, rates, "1.0 crashes/day, 1.0 OOM kills/day; before: 2.0 crashes/day, 0.0 OOM kills/day")
+
+	_, ok = describeCrashRates(crashes[:3], release, start, end)
+	assert.False(t, ok, "too few crashes")
+
+	_, ok = describeCrashRates(crashes, api.Release{CreatedAt: end.Add(-time.Hour)}, start, end)
+	assert.False(t, ok, "too short a window since the release")
+}
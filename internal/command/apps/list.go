@@ -2,16 +2,23 @@ package apps
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/parallel"
 	"github.com/superfly/flyctl/internal/render"
 )
 
@@ -19,19 +26,35 @@ func newList() *cobra.Command {
 	const (
 		long = `The APPS LIST command will show the applications currently
 registered and available to this user. The list will include applications
-from all the organizations the user is a member of. Each application will
-be shown with its name, owner and when it was last deployed.
+from all the organizations the user is a member of, unless --org narrows it
+down to one. Each application will be shown with its name, owner and when it
+was last deployed.
+
+With --full, the machines or instances of each application are shown too,
+counted by state, along with the regions they run in. Their details are
+fetched for several applications at once, and shown as they arrive when
+running interactively.
 `
 		short = "List applications"
 	)
 
-	return command.New("list", short, long, runList,
+	cmd := command.New("list", short, long, runList,
 		command.RequireSession,
 	)
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Bool{
+			Name:        "full",
+			Description: "Also show the machines or instances of each app, and their regions",
+		},
+		flag.Format(),
+	)
+
+	return cmd
 }
 
 func runList(ctx context.Context) (err error) {
-	cfg := config.FromContext(ctx)
 	client := client.FromContext(ctx)
 
 	var apps []api.App
@@ -39,26 +62,34 @@ func runList(ctx context.Context) (err error) {
 		return
 	}
 
-	out := iostreams.FromContext(ctx).Out
-	if cfg.JSONOutput {
-		_ = render.JSON(out, apps)
+	// only the flag narrows apps down, not the default organization
+	if org := flag.GetString(ctx, flag.OrgName); org != "" {
+		var filtered []api.App
+		for _, app := range apps {
+			if app.Organization.Slug == org {
+				filtered = append(filtered, app)
+			}
+		}
+		apps = filtered
+	}
 
-		return
+	if flag.GetBool(ctx, "full") {
+		return listFull(ctx, apps)
+	}
+
+	out := iostreams.FromContext(ctx).Out
+	if ok, err := render.Structured(ctx, out, apps); ok {
+		return err
 	}
 
 	rows := make([][]string, 0, len(apps))
 	for _, app := range apps {
-		latestDeploy := ""
-		if app.Deployed && app.CurrentRelease != nil {
-			latestDeploy = format.RelativeTime(app.CurrentRelease.CreatedAt)
-		}
-
 		rows = append(rows, []string{
 			app.Name,
 			app.Organization.Slug,
 			app.Status,
 			app.PlatformVersion,
-			latestDeploy,
+			latestDeploy(app),
 		})
 	}
 
@@ -66,3 +97,220 @@ func runList(ctx context.Context) (err error) {
 
 	return
 }
+
+func latestDeploy(app api.App) string {
+	if app.Deployed && app.CurrentRelease != nil {
+		return format.RelativeTime(app.CurrentRelease.CreatedAt)
+	}
+
+	return ""
+}
+
+// appDetails is an app along with what --full shows of it.
+type appDetails struct {
+	api.App
+
+	// Machines counts the machines, or instances, of the app by state.
+	Machines map[string]int
+	Regions  []string
+	Error    string `json:",omitempty"`
+}
+
+var fullColumns = []string{"Name", "Owner", "Status", "Platform", "Latest Deploy", "Machines", "Regions"}
+
+func (d *appDetails) row() []string {
+	machines := describeStates(d.Machines)
+	if d.Error != "" {
+		machines = "error: " + d.Error
+	}
+
+	return []string{
+		d.Name,
+		d.Organization.Slug,
+		d.Status,
+		d.PlatformVersion,
+		latestDeploy(d.App),
+		machines,
+		strings.Join(d.Regions, ","),
+	}
+}
+
+// listFull lists apps along with their machines or instances, fetching those
+// of parallel.Limit apps at once. Interactively, rows are printed as their
+// details arrive; otherwise, they're printed once all have, sorted by name.
+func listFull(ctx context.Context, apps []api.App) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		scripted  = config.FromContext(ctx).JSONOutput || flag.GetString(ctx, flag.FormatName) != ""
+		streaming = io.IsInteractive() && !scripted
+		details   = make([]*appDetails, len(apps))
+		arrived   = make(chan *appDetails)
+	)
+
+	var stream *streamedTable
+	if streaming {
+		stream = newStreamedTable(io.Out, apps)
+	}
+
+	go func() {
+		defer close(arrived)
+
+		parallel.Each(len(apps), parallel.Limit, func(i int) {
+			details[i] = fetchDetails(ctx, apps[i])
+			arrived <- details[i]
+		})
+	}()
+
+	for d := range arrived {
+		if stream != nil {
+			stream.print(d.row())
+		}
+	}
+
+	if streaming {
+		fmt.Fprintln(io.Out)
+
+		return nil
+	}
+
+	sort.Slice(details, func(i, j int) bool {
+		return details[i].Name < details[j].Name
+	})
+
+	if ok, err := render.Structured(ctx, io.Out, details); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(details))
+	for _, d := range details {
+		rows = append(rows, d.row())
+	}
+
+	return render.Table(io.Out, "", rows, fullColumns...)
+}
+
+// fetchDetails fetches the machines of machines apps, and the instances of
+// others. Failing to is recorded on the details, so that other apps are still
+// listed.
+func fetchDetails(ctx context.Context, app api.App) *appDetails {
+	d := &appDetails{
+		App:      app,
+		Machines: map[string]int{},
+	}
+
+	regions := map[string]bool{}
+
+	if app.PlatformVersion == "machines" {
+		flapsClient, err := flaps.New(ctx, &api.AppCompact{
+			Name:            app.Name,
+			PlatformVersion: app.PlatformVersion,
+			Organization: &api.OrganizationBasic{
+				Slug: app.Organization.Slug,
+			},
+		})
+		if err != nil {
+			d.Error = err.Error()
+
+			return d
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			d.Error = err.Error()
+
+			return d
+		}
+
+		for _, machine := range machines {
+			d.Machines[machine.State]++
+			regions[machine.Region] = true
+		}
+	} else if app.Deployed {
+		status, err := client.FromContext(ctx).API().GetAppStatus(ctx, app.Name, false)
+		if err != nil {
+			d.Error = err.Error()
+
+			return d
+		}
+
+		for _, alloc := range status.Allocations {
+			d.Machines[alloc.Status]++
+			regions[alloc.Region] = true
+		}
+	}
+
+	for region := range regions {
+		d.Regions = append(d.Regions, region)
+	}
+	sort.Strings(d.Regions)
+
+	return d
+}
+
+// describeStates spells out counts by state, like 3 started, 1 stopped.
+func describeStates(states map[string]int) string {
+	if len(states) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(states))
+	for state := range states {
+		names = append(names, state)
+	}
+	sort.Strings(names)
+
+	described := make([]string, 0, len(names))
+	for _, state := range names {
+		described = append(described, fmt.Sprintf("%d %s", states[state], state))
+	}
+
+	return strings.Join(described, ", ")
+}
+
+// streamedTable prints the rows of a table one at a time, aligning the
+// columns whose values are known up front like render.Table would. The last
+// two, which depend on the details being fetched, are left unaligned.
+type streamedTable struct {
+	w      io.Writer
+	widths []int
+}
+
+func newStreamedTable(w io.Writer, apps []api.App) *streamedTable {
+	t := &streamedTable{w: w}
+
+	header := make([]string, len(fullColumns))
+	for i, col := range fullColumns {
+		header[i] = strings.ToUpper(col)
+	}
+
+	t.widths = make([]int, len(fullColumns)-2)
+	for i := range t.widths {
+		t.widths[i] = len(header[i])
+	}
+
+	for _, app := range apps {
+		for i, value := range (&appDetails{App: app}).row()[:len(t.widths)] {
+			if len(value) > t.widths[i] {
+				t.widths[i] = len(value)
+			}
+		}
+	}
+
+	t.print(header)
+
+	return t
+}
+
+func (t *streamedTable) print(row []string) {
+	var b strings.Builder
+
+	for i, value := range row {
+		if i < len(t.widths) {
+			fmt.Fprintf(&b, "%-*s\t", t.widths[i], value)
+		} else {
+			fmt.Fprintf(&b, "%s\t", value)
+		}
+	}
+
+	fmt.Fprintln(t.w, strings.TrimRight(b.String(), "\t"))
+}
@@ -0,0 +1,190 @@
+package apps
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newPromote() (cmd *cobra.Command) {
+	const (
+		long = `Promote a release of one app to another, such as from staging to production.
+The exact image of the release, pinned by digest, is deployed to the target app
+using the config of the source app. Secrets aren't copied; the target app keeps
+its own. The region and environment can be overridden for the target app.
+Where the API supports it, the promotion is recorded in the release history of
+both apps.
+`
+		short = "Promote a release of one app to another"
+	)
+
+	cmd = command.New("promote", short, long, runPromote,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "from",
+			Description: "App to promote the release from",
+		},
+		flag.String{
+			Name:        "to",
+			Description: "App to promote the release to",
+		},
+		flag.Int{
+			Name:        "version",
+			Description: "Version of the release to promote. Defaults to the latest stable release",
+		},
+		flag.String{
+			Name:   flag.ImageName,
+			Hidden: true,
+		},
+		deploy.CommonFlags,
+	)
+
+	return
+}
+
+func runPromote(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+		from   = flag.GetString(ctx, "from")
+		to     = flag.GetString(ctx, "to")
+	)
+
+	if from == "" || to == "" {
+		return fmt.Errorf("both --from and --to must be specified")
+	}
+	if from == to {
+		return fmt.Errorf("can't promote a release of %s to itself", from)
+	}
+
+	source, err := client.GetAppCompact(ctx, from)
+	if err != nil {
+		return err
+	}
+
+	target, err := client.GetAppCompact(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	if source.PlatformVersion != target.PlatformVersion {
+		return fmt.Errorf("can't promote a release of %s app %s to %s app %s", source.PlatformVersion, from, target.PlatformVersion, to)
+	}
+
+	release, err := findPromotableRelease(ctx, from, flag.GetInt(ctx, "version"))
+	if err != nil {
+		return err
+	}
+
+	image, err := client.ResolveImageForApp(ctx, from, release.ImageRef)
+	if err != nil {
+		return fmt.Errorf("failed resolving image of release v%d: %w", release.Version, err)
+	}
+	if image == nil {
+		return fmt.Errorf("image %s of release v%d no longer exists", release.ImageRef, release.Version)
+	}
+
+	ref := pinnedImageRef(image)
+
+	apiConfig, err := client.GetConfig(ctx, from)
+	if err != nil {
+		return fmt.Errorf("failed fetching app config of %s: %w", from, err)
+	}
+
+	cfg := &app.Config{
+		AppName:    to,
+		Definition: apiConfig.Definition,
+	}
+	cfg.SetPlatformVersion(target.PlatformVersion)
+
+	fmt.Fprintf(out, "Promoting release v%d of %s (%s) to %s\n", release.Version, from, ref, to)
+
+	if err := flag.FromContext(ctx).Set(flag.ImageName, ref); err != nil {
+		return err
+	}
+
+	ctx = app.WithName(ctx, to)
+	ctx = app.WithConfig(ctx, cfg)
+
+	if err := deploy.Run(ctx); err != nil {
+		return err
+	}
+
+	switch served, err := client.SchemaHas(ctx, "Mutations", "recordReleasePromotion"); {
+	case err != nil:
+		return fmt.Errorf("release was promoted but checking the API records promotions failed: %w", err)
+	case !served:
+		fmt.Fprintf(out, "Promoted release v%d of %s to %s\n", release.Version, from, to)
+
+		return nil
+	}
+
+	promoted, err := client.RecordReleasePromotion(ctx, api.RecordReleasePromotionInput{
+		SourceAppID:     source.ID,
+		SourceReleaseID: release.ID,
+		TargetAppID:     target.ID,
+		Image:           ref,
+	})
+	if err != nil {
+		return fmt.Errorf("release was promoted but recording its provenance failed: %w", err)
+	}
+
+	fmt.Fprintf(out, "Promoted release v%d of %s to release v%d of %s\n", release.Version, from, promoted.Version, to)
+
+	return nil
+}
+
+// findPromotableRelease returns the given release of the app or, when version
+// is 0, its latest stable one.
+func findPromotableRelease(ctx context.Context, appName string, version int) (*api.Release, error) {
+	releases, err := client.FromContext(ctx).API().GetAppReleases(ctx, appName, 25)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving app releases %s: %w", appName, err)
+	}
+
+	for _, release := range releases {
+		if release.ImageRef == "" {
+			continue
+		}
+
+		if (version == 0 && release.Stable) || release.Version == version {
+			return &release, nil
+		}
+	}
+
+	if version == 0 {
+		return nil, fmt.Errorf("app %s has no stable release to promote", appName)
+	}
+
+	return nil, fmt.Errorf("release v%d of app %s not found among its recent releases", version, appName)
+}
+
+// pinnedImageRef returns the reference to the image by digest rather than
+// by tag, so the exact image is promoted.
+func pinnedImageRef(image *api.Image) string {
+	if image.Digest == "" {
+		return image.Ref
+	}
+
+	repo := strings.SplitN(image.Ref, "@", 2)[0]
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+
+	return repo + "@" + image.Digest
+}
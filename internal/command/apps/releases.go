@@ -3,6 +3,7 @@ package apps
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -12,7 +13,6 @@ import (
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
-	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -23,6 +23,14 @@ func NewReleases() (cmd *cobra.Command) {
 	const (
 		long = `List all the releases of the application onto the Fly platform,
 including type, when, success/fail and which user triggered the release.
+
+--diff shows the changes to the image, environment, services, checks and
+guest of each process group between two releases, like --diff v3,v5, or
+between a release and the one before it, like --diff v5. It needs the API to
+record the machine config of releases, which it doesn't everywhere yet.
+
+With --watch, the list is refreshed every --interval, highlighting what
+changed, such as the status of a release being deployed.
 `
 		short = "List app releases"
 	)
@@ -41,6 +49,16 @@ including type, when, success/fail and which user triggered the release.
 			Name:        "image",
 			Description: "Display the Docker image reference of the release",
 		},
+		flag.StringSlice{
+			Name:        "diff",
+			Description: "Show the changes between two releases, or a release and the previous one",
+		},
+		flag.Watch(),
+		flag.Format(),
+	)
+
+	cmd.AddCommand(
+		newPromote(),
 	)
 
 	return
@@ -49,14 +67,27 @@ including type, when, success/fail and which user triggered the release.
 func runReleases(ctx context.Context) error {
 	appName := app.NameFromContext(ctx)
 
+	if versions := flag.GetStringSlice(ctx, "diff"); len(versions) > 0 {
+		return runReleasesDiff(ctx, appName, versions)
+	}
+
+	if flag.GetBool(ctx, "watch") {
+		return render.Watch(ctx, flag.GetDuration(ctx, "interval"), "Releases of "+appName, listReleases)
+	}
+
+	return listReleases(ctx, iostreams.FromContext(ctx).Out)
+}
+
+func listReleases(ctx context.Context, out io.Writer) error {
+	appName := app.NameFromContext(ctx)
+
 	releases, err := client.FromContext(ctx).API().GetAppReleases(ctx, appName, 25)
 	if err != nil {
 		return fmt.Errorf("failed retrieving app releases %s: %w", appName, err)
 	}
 
-	out := iostreams.FromContext(ctx).Out
-	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, releases)
+	if ok, err := render.Structured(ctx, out, releases); ok {
+		return err
 	}
 
 	var rows [][]string
@@ -0,0 +1,204 @@
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// releaseChange is a setting that differs between two releases. From is
+// empty for settings that were added, and To for those that were removed.
+type releaseChange struct {
+	Setting string `json:"setting"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+type releaseDiff struct {
+	From    int             `json:"from"`
+	To      int             `json:"to"`
+	Changes []releaseChange `json:"changes"`
+}
+
+func parseReleaseVersion(s string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(s), "v"))
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid release version %q, must be like v3 or 3", s)
+	}
+
+	return version, nil
+}
+
+// runReleasesDiff shows what changed between two releases, or between a
+// release and the one before it when given a single version.
+func runReleasesDiff(ctx context.Context, appName string, versions []string) error {
+	// configs aren't recorded until the API takes them
+	if err := command.CheckAPI(ctx, "Release", "configs"); err != nil {
+		return fmt.Errorf("--diff isn't available: %w", err)
+	}
+
+	if len(versions) > 2 {
+		return fmt.Errorf("--diff takes one or two release versions, got %d", len(versions))
+	}
+
+	var parsed []int
+	for _, v := range versions {
+		version, err := parseReleaseVersion(v)
+		if err != nil {
+			return err
+		}
+
+		parsed = append(parsed, version)
+	}
+
+	if len(parsed) == 1 {
+		if parsed[0] == 1 {
+			return fmt.Errorf("v1 is the first release of %s, so there's nothing to compare it to", appName)
+		}
+
+		parsed = []int{parsed[0] - 1, parsed[0]}
+	}
+
+	apiClient := client.FromContext(ctx).API()
+
+	var releases []*api.Release
+	for _, version := range parsed {
+		release, err := apiClient.GetAppReleaseByVersion(ctx, appName, version)
+		if err != nil {
+			return fmt.Errorf("failed retrieving release v%d of %s: %w", version, appName, err)
+		}
+
+		if len(release.Configs) == 0 {
+			return fmt.Errorf("release v%d of %s has no recorded config; configs are recorded for releases of apps running on machines", version, appName)
+		}
+
+		releases = append(releases, release)
+	}
+
+	diff := releaseDiff{
+		From:    releases[0].Version,
+		To:      releases[1].Version,
+		Changes: diffReleaseSettings(releaseSettings(releases[0]), releaseSettings(releases[1])),
+	}
+
+	out := iostreams.FromContext(ctx).Out
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, diff)
+	}
+
+	if len(diff.Changes) == 0 {
+		fmt.Fprintf(out, "No changes from v%d to v%d\n", diff.From, diff.To)
+		return nil
+	}
+
+	colorize := iostreams.FromContext(ctx).ColorScheme()
+
+	fmt.Fprintf(out, "Changes from v%d to v%d:\n\n", diff.From, diff.To)
+	for _, change := range diff.Changes {
+		switch {
+		case change.From == "":
+			fmt.Fprintln(out, colorize.Green(fmt.Sprintf("+ %s: %s", change.Setting, change.To)))
+		case change.To == "":
+			fmt.Fprintln(out, colorize.Red(fmt.Sprintf("- %s: %s", change.Setting, change.From)))
+		default:
+			fmt.Fprintln(out, colorize.Yellow(fmt.Sprintf("~ %s: %s -> %s", change.Setting, change.From, change.To)))
+		}
+	}
+
+	return nil
+}
+
+// releaseSettings flattens the image digest and the machine config of each
+// process group of release into the settings releases are compared by. The
+// settings of configs are named after their group, like app/env.PORT.
+func releaseSettings(release *api.Release) map[string]string {
+	settings := map[string]string{
+		"image.digest": release.ImageDigest,
+	}
+
+	for group, cfg := range release.Configs {
+		for name, value := range configSettings(cfg) {
+			settings[group+"/"+name] = value
+		}
+	}
+
+	return settings
+}
+
+// configSettings flattens cfg into the settings releases are compared by.
+func configSettings(cfg *api.MachineConfig) map[string]string {
+	settings := map[string]string{
+		"image": cfg.Image,
+	}
+
+	for name, value := range cfg.Env {
+		settings["env."+name] = value
+	}
+
+	if guest := cfg.Guest; guest != nil {
+		settings["guest"] = fmt.Sprintf("%d %s cpu(s), %dMB of memory", guest.CPUs, guest.CPUKind, guest.MemoryMB)
+	} else if cfg.VMSize != "" {
+		settings["guest"] = cfg.VMSize
+	}
+
+	for _, service := range cfg.Services {
+		settings[fmt.Sprintf("services.%s/%d", service.Protocol, service.InternalPort)] = compactJSON(service)
+	}
+
+	for name, check := range cfg.Checks {
+		settings["checks."+name] = compactJSON(check)
+	}
+
+	for _, mount := range cfg.Mounts {
+		settings["mounts."+mount.Path] = compactJSON(mount)
+	}
+
+	if cmd := cfg.Init.Cmd; len(cmd) > 0 {
+		settings["init.cmd"] = strings.Join(cmd, " ")
+	}
+
+	return settings
+}
+
+func diffReleaseSettings(from, to map[string]string) (changes []releaseChange) {
+	keys := map[string]bool{}
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		if from[key] != to[key] {
+			changes = append(changes, releaseChange{Setting: key, From: from[key], To: to[key]})
+		}
+	}
+
+	return
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,69 @@
+package apps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestReleaseSettings(t *testing.T) {
+	release := &api.Release{
+		ImageDigest: "sha256:abc",
+		Configs: map[string]*api.MachineConfig{
+			"web": {
+				Image: "registry.fly.io/app:v1",
+				Env:   map[string]string{"PORT": "8080"},
+				Guest: &api.MachineGuest{CPUKind: "shared", CPUs: 1, MemoryMB: 256},
+			},
+			"worker": {
+				Image: "registry.fly.io/app:v1",
+				Init:  api.MachineInit{Cmd: []string{"bin/worker", "--queue", "default"}},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"image.digest":    "sha256:abc",
+		"web/image":       "registry.fly.io/app:v1",
+		"web/env.PORT":    "8080",
+		"web/guest":       "1 shared cpu(s), 256MB of memory",
+		"worker/image":    "registry.fly.io/app:v1",
+		"worker/init.cmd": "bin/worker --queue default",
+	}, releaseSettings(release))
+}
+
+func TestDiffReleaseSettings(t *testing.T) {
+	from := map[string]string{
+		"web/env.PORT":  "8080",
+		"web/env.DEBUG": "1",
+		"web/guest":     "1 shared cpu(s), 256MB of memory",
+	}
+	to := map[string]string{
+		"web/env.PORT":    "8080",
+		"web/guest":       "2 shared cpu(s), 512MB of memory",
+		"worker/init.cmd": "bin/worker",
+	}
+
+	assert.Equal(t, []releaseChange{
+		{Setting: "web/env.DEBUG", From: "1"},
+		{Setting: "web/guest", From: "1 shared cpu(s), 256MB of memory", To: "2 shared cpu(s), 512MB of memory"},
+		{Setting: "worker/init.cmd", To: "bin/worker"},
+	}, diffReleaseSettings(from, to))
+
+	assert.Empty(t, diffReleaseSettings(from, from))
+}
+
+func TestParseReleaseVersion(t *testing.T) {
+	for _, s := range []string{"v3", "3", " v3 "} {
+		version, err := parseReleaseVersion(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, 3, version, s)
+	}
+
+	for _, s := range []string{"", "v0", "three", "v-1"} {
+		_, err := parseReleaseVersion(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -66,23 +66,76 @@ func runWebLogin(ctx context.Context, signup bool) error {
 	colorize := io.ColorScheme()
 	fmt.Fprintf(io.Out, "Opening %s ...\n\n", colorize.Bold(auth.AuthURL))
 
-	token, err := waitForCLISession(ctx, logger, io.ErrOut, auth.ID)
+	session, err := waitForCLISession(ctx, logger, io.ErrOut, auth.ID)
 	switch {
 	case err == nil:
 		break
 	case errors.Is(err, context.DeadlineExceeded):
 		return errors.New("Login expired, please try again")
-	case token == "":
+	case session.AccessToken == "":
 		return errors.New("failed to log in, please try again")
 	default:
 		return err
 	}
 
-	if err := persistAccessToken(ctx, token); err != nil {
+	return finishLogin(ctx, session)
+}
+
+// runSSOLogin logs in through the SSO provider of the organization with the
+// given slug, by opening its login page or, with device, by having the user
+// enter a code on another device.
+func runSSOLogin(ctx context.Context, orgSlug string, device bool) error {
+	auth, err := api.StartCLISessionSSO(state.Hostname(ctx), orgSlug, device)
+	if err != nil {
+		return fmt.Errorf("failed starting SSO login for %s: %w", orgSlug, err)
+	}
+
+	io := iostreams.FromContext(ctx)
+	colorize := io.ColorScheme()
+
+	if device {
+		fmt.Fprintf(io.Out, "Open %s on another device and enter the code %s\n\n",
+			colorize.Bold(auth.VerificationURL), colorize.Bold(auth.UserCode))
+	} else {
+		if err := open.Run(auth.AuthURL); err != nil {
+			fmt.Fprintf(io.ErrOut,
+				"failed opening browser. Copy the url (%s) into a browser and continue, or log in with --device\n",
+				auth.AuthURL,
+			)
+		}
+
+		fmt.Fprintf(io.Out, "Opening %s ...\n\n", colorize.Bold(auth.AuthURL))
+	}
+
+	session, err := waitForCLISession(ctx, logger.FromContext(ctx), io.ErrOut, auth.ID)
+	switch {
+	case err == nil:
+		break
+	case errors.Is(err, context.DeadlineExceeded):
+		return errors.New("Login expired, please try again")
+	case session.AccessToken == "":
+		return errors.New("failed to log in, please try again")
+	default:
 		return err
 	}
 
-	client := client.FromToken(token).API()
+	return finishLogin(ctx, session)
+}
+
+// finishLogin persists the tokens of session and greets its user.
+func finishLogin(ctx context.Context, session api.CLISessionAuth) (err error) {
+	if session.RefreshToken != "" && session.ExpiresAt != nil {
+		if err = persistSessionTokens(ctx, session.AccessToken, session.RefreshToken, *session.ExpiresAt); err != nil {
+			return err
+		}
+	} else if err = persistAccessToken(ctx, session.AccessToken); err != nil {
+		return err
+	}
+
+	io := iostreams.FromContext(ctx)
+	colorize := io.ColorScheme()
+
+	client := client.FromToken(session.AccessToken).API()
 
 	user, err := client.GetCurrentUser(ctx)
 	if err != nil {
@@ -95,7 +148,7 @@ func runWebLogin(ctx context.Context, signup bool) error {
 }
 
 // TODO: this does NOT break on interrupts
-func waitForCLISession(parent context.Context, logger *logger.Logger, w io.Writer, id string) (token string, err error) {
+func waitForCLISession(parent context.Context, logger *logger.Logger, w io.Writer, id string) (session api.CLISessionAuth, err error) {
 	ctx, cancel := context.WithTimeout(parent, 15*time.Minute)
 	defer cancel()
 
@@ -105,7 +158,7 @@ func waitForCLISession(parent context.Context, logger *logger.Logger, w io.Write
 	s.Start()
 
 	for ctx.Err() == nil {
-		if token, err = api.GetAccessTokenForCLISession(ctx, id); err != nil {
+		if session, err = api.GetCLISession(ctx, id); err != nil {
 			logger.Debugf("failed retrieving token: %v", err)
 
 			pause.For(ctx, time.Second)
@@ -124,13 +177,39 @@ func waitForCLISession(parent context.Context, logger *logger.Logger, w io.Write
 	return
 }
 
+// persistAccessToken persists token as the access token of the context in
+// use, or else of the config file.
 func persistAccessToken(ctx context.Context, token string) (err error) {
 	path := state.ConfigFile(ctx)
 
-	if err = config.SetAccessToken(path, token); err != nil {
+	if name := config.FromContext(ctx).Context; name != "" {
+		err = config.SetContextSessionTokens(path, name, token, "", time.Time{})
+	} else {
+		err = config.SetAccessToken(path, token)
+	}
+
+	if err != nil {
 		err = fmt.Errorf("failed persisting %s in %s: %w\n",
 			config.AccessTokenFileKey, path, err)
 	}
 
 	return
 }
+
+// persistSessionTokens persists the tokens of an SSO session for the context
+// in use, or else for the config file.
+func persistSessionTokens(ctx context.Context, accessToken, refreshToken string, expiresAt time.Time) (err error) {
+	path := state.ConfigFile(ctx)
+
+	if name := config.FromContext(ctx).Context; name != "" {
+		err = config.SetContextSessionTokens(path, name, accessToken, refreshToken, expiresAt)
+	} else {
+		err = config.SetSessionTokens(path, accessToken, refreshToken, expiresAt)
+	}
+
+	if err != nil {
+		err = fmt.Errorf("failed persisting session tokens in %s: %w", path, err)
+	}
+
+	return
+}
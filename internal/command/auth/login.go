@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -17,6 +18,20 @@ func newLogin() *cobra.Command {
 		long = `Logs a user into the Fly platform. Supports browser-based,
 email/password and one-time-password authentication. Defaults to using
 browser-based authentication.
+
+With --sso, the user logs in through the SAML or OIDC identity provider of the
+organization with the given slug, so that its policies apply to the CLI too.
+The short-lived tokens such logins issue are refreshed automatically. On
+machines without a browser, --device prints a code to enter on another device
+instead of opening the login page.
+
+With --from-oidc, CI jobs log in by exchanging their OIDC identity token for a
+short-lived token, scoped by the trust rule of the organization the identity
+token matches, so that no long-lived deploy token needs to be kept in CI
+secrets. In GitHub Actions, the identity token is requested for the job, which
+needs the id-token: write permission. Elsewhere, like in GitLab CI, it's read
+from $FLY_OIDC_TOKEN. --org and --app narrow the token down when the trust
+rules the identity token matches allow several organizations or apps.
 `
 		short = "Log in a user"
 	)
@@ -41,6 +56,20 @@ browser-based authentication.
 			Name:        "otp",
 			Description: "One time password",
 		},
+		flag.String{
+			Name:        "sso",
+			Description: "Log in through the SSO provider of the organization with this slug",
+		},
+		flag.Bool{
+			Name:        "device",
+			Description: "Log in with a code entered on another device, for machines without a browser",
+		},
+		flag.Bool{
+			Name:        "from-oidc",
+			Description: "Log in by exchanging the OIDC identity token of the CI job for a short-lived token",
+		},
+		flag.Org(),
+		flag.App(),
 	)
 
 	return cmd
@@ -52,9 +81,28 @@ func runLogin(ctx context.Context) error {
 		email       = flag.GetString(ctx, "email")
 		password    = flag.GetString(ctx, "password")
 		otp         = flag.GetString(ctx, "otp")
+		sso         = flag.GetString(ctx, "sso")
+		device      = flag.GetBool(ctx, "device")
+		fromOIDC    = flag.GetBool(ctx, "from-oidc")
 	)
 
 	switch {
+	case fromOIDC:
+		if interactive || email != "" || password != "" || otp != "" || sso != "" || device {
+			return errors.New("--from-oidc can't be used with other login methods")
+		}
+
+		return runOIDCLogin(ctx)
+	case flag.GetOrg(ctx) != "" || flag.GetApp(ctx) != "":
+		return errors.New("--org and --app require --from-oidc")
+	case sso != "":
+		if interactive || email != "" || password != "" || otp != "" {
+			return errors.New("--sso can't be used with --interactive, --email, --password or --otp")
+		}
+
+		return runSSOLogin(ctx, sso, device)
+	case device:
+		return errors.New("--device requires --sso")
 	case interactive, email != "", password != "", otp != "":
 		return runShellLogin(ctx, email, password, otp)
 	default:
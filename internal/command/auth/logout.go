@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -38,7 +39,14 @@ func runLogout(ctx context.Context) (err error) {
 	}
 
 	path := state.ConfigFile(ctx)
-	if err = config.Clear(path); err != nil {
+	if name := config.FromContext(ctx).Context; name != "" {
+		// logging out of a context only drops its credentials
+		if err = config.SetContextSessionTokens(path, name, "", "", time.Time{}); err != nil {
+			err = fmt.Errorf("failed clearing the credentials of context %s at %s: %w\n", name, path, err)
+
+			return
+		}
+	} else if err = config.Clear(path); err != nil {
 		err = fmt.Errorf("failed clearing config file at %s: %w\n", path, err)
 
 		return
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// oidcTokenEnvKey is the environment variable an OIDC identity token may be
+// given with, like GitLab CI id_tokens provide them.
+const oidcTokenEnvKey = "FLY_OIDC_TOKEN"
+
+// runOIDCLogin exchanges the OIDC identity token of the CI job running flyctl
+// for a short-lived scoped token, and persists it.
+func runOIDCLogin(ctx context.Context) error {
+	token, provider, err := oidcIdentityToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := api.ExchangeOIDCToken(ctx, api.OIDCExchange{
+		Token:        token,
+		Organization: flag.GetOrg(ctx),
+		App:          flag.GetApp(ctx),
+	})
+	switch {
+	case err == nil:
+		break
+	case api.IsNotAuthenticatedError(err), isForbidden(err):
+		return fmt.Errorf("the %s identity token matches no trust rule of the organization: %w", provider, err)
+	default:
+		return fmt.Errorf("failed exchanging the %s identity token: %w", provider, err)
+	}
+
+	if err := persistSessionTokens(ctx, result.AccessToken, "", result.ExpiresAt); err != nil {
+		return err
+	}
+
+	target := "organization " + result.Organization
+	if result.App != "" {
+		target = "app " + result.App
+	}
+
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "successfully logged in with a %s token for %s through trust rule %s, expiring %s\n",
+		strings.ToLower(strings.ReplaceAll(result.Scope, "_", "")),
+		target,
+		result.Rule,
+		format.RelativeTime(result.ExpiresAt),
+	)
+
+	return nil
+}
+
+// oidcIdentityToken returns the OIDC identity token to exchange, from
+// $FLY_OIDC_TOKEN or else requested from GitHub Actions, along with the name
+// of its provider.
+func oidcIdentityToken(ctx context.Context) (token, provider string, err error) {
+	if token = strings.TrimSpace(os.Getenv(oidcTokenEnvKey)); token != "" {
+		provider = "$" + oidcTokenEnvKey
+		if os.Getenv("GITLAB_CI") != "" {
+			provider = "GitLab CI"
+		}
+
+		return
+	}
+
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	requestToken := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN")
+
+	if requestURL == "" || requestToken == "" {
+		if os.Getenv("GITHUB_ACTIONS") != "" {
+			err = errors.New("GitHub Actions didn't provide an identity token; grant the job the id-token: write permission")
+		} else {
+			err = fmt.Errorf("no identity token found; set $%s to the OIDC identity token of the CI job, or run in GitHub Actions", oidcTokenEnvKey)
+		}
+
+		return
+	}
+
+	if token, err = githubActionsIdentityToken(ctx, requestURL, requestToken, config.FromContext(ctx).APIBaseURL); err != nil {
+		err = fmt.Errorf("failed requesting the GitHub Actions identity token: %w", err)
+	}
+
+	return token, "GitHub Actions", err
+}
+
+// githubActionsIdentityToken requests an identity token for audience from
+// GitHub Actions.
+func githubActionsIdentityToken(ctx context.Context, requestURL, requestToken, audience string) (string, error) {
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", err
+	}
+
+	q := u.Query()
+	q.Set("audience", audience)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", errors.New(res.Status)
+	}
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Value == "" {
+		return "", errors.New("the response contains no token")
+	}
+
+	return body.Value, nil
+}
+
+func isForbidden(err error) bool {
+	var apiErr *api.ApiError
+
+	return errors.As(err, &apiErr) && apiErr.Status == http.StatusForbidden
+}
@@ -3,27 +3,72 @@ package auth
 import (
 	"context"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/iostreams"
 
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/state"
 )
 
+// scopePermissions maps the scopes of access tokens to the permissions
+// --check takes which they grant.
+var scopePermissions = map[string][]string{
+	"DEPLOY":    {"read", "deploy", "machines"},
+	"MACHINES":  {"read", "machines"},
+	"READ_ONLY": {"read"},
+}
+
 func newWhoAmI() *cobra.Command {
 	const (
 		long = `Displays the users email address/service identity currently
 authenticated and in use.
+
+With --verbose, also shows which token is in use and where it comes from, its
+scope and expiry, and the organizations and apps it can access.
+
+--check answers whether the token in use can perform an action on an app,
+like --check my-app:deploy, and fails when it can't, which helps debugging the
+credentials of CI pipelines. Actions are read, deploy and machines.
 `
 		short = "Show the currently authenticated user"
 	)
 
-	return command.New("whoami", long, short, runWhoAmI,
+	cmd := command.New("whoami", long, short, runWhoAmI,
 		command.RequireSession)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "check",
+			Description: "Check the token in use can perform an action on an app, like app:deploy",
+		},
+	)
+
+	return cmd
+}
+
+// identity describes the token in use, as shown with --verbose.
+type identity struct {
+	Email         string
+	Kind          string
+	Source        string
+	Scope         string
+	App           string   `json:",omitempty"`
+	AllowedIPs    []string `json:",omitempty"`
+	ExpiresAt     *time.Time
+	Organizations []string
+	Apps          []string
 }
 
 func runWhoAmI(ctx context.Context) error {
@@ -37,6 +82,14 @@ func runWhoAmI(ctx context.Context) error {
 	io := iostreams.FromContext(ctx)
 	cfg := config.FromContext(ctx)
 
+	if check := flag.GetString(ctx, "check"); check != "" {
+		return runCheck(ctx, check)
+	}
+
+	if cfg.VerboseOutput {
+		return runVerboseWhoAmI(ctx, user)
+	}
+
 	if cfg.JSONOutput {
 		_ = render.JSON(io.Out, map[string]string{"email": user.Email})
 	} else {
@@ -45,3 +98,229 @@ func runWhoAmI(ctx context.Context) error {
 
 	return nil
 }
+
+func runVerboseWhoAmI(ctx context.Context, user *api.User) error {
+	var (
+		client = client.FromContext(ctx).API()
+		cfg    = config.FromContext(ctx)
+		out    = iostreams.FromContext(ctx).Out
+	)
+
+	token, err := currentToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	id := identity{
+		Email:  user.Email,
+		Kind:   tokenKind(cfg.AccessToken, token),
+		Source: tokenSource(ctx),
+		Scope:  "full access",
+	}
+
+	if token != nil {
+		id.Scope = strings.ToLower(strings.ReplaceAll(token.Scope, "_", ""))
+		id.AllowedIPs = token.AllowedIPs
+		id.ExpiresAt = token.ExpiresAt
+
+		if token.App != nil {
+			id.App = token.App.Name
+		}
+	} else if !cfg.AccessTokenExpiresAt.IsZero() {
+		expiresAt := cfg.AccessTokenExpiresAt
+		id.ExpiresAt = &expiresAt
+	}
+
+	orgs, err := client.GetOrganizations(ctx)
+	if err != nil {
+		return fmt.Errorf("failed retrieving organizations: %w", err)
+	}
+
+	for _, org := range orgs {
+		if token == nil || token.Organization == nil || token.Organization.Slug == org.Slug {
+			id.Organizations = append(id.Organizations, org.Slug)
+		}
+	}
+
+	apps, err := client.GetApps(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed retrieving apps: %w", err)
+	}
+
+	for _, app := range apps {
+		if token != nil && token.App != nil && token.App.Name != app.Name {
+			continue
+		}
+
+		if token != nil && token.Organization != nil && app.Organization.Slug != token.Organization.Slug {
+			continue
+		}
+
+		id.Apps = append(id.Apps, app.Name)
+	}
+
+	sort.Strings(id.Organizations)
+	sort.Strings(id.Apps)
+
+	if cfg.JSONOutput {
+		return render.JSON(out, id)
+	}
+
+	scope := id.Scope
+	if id.App != "" {
+		scope += " on app " + id.App
+	} else if token != nil && token.Organization != nil {
+		scope += " on organization " + token.Organization.Slug
+	}
+
+	expires := "never"
+	if id.ExpiresAt != nil {
+		expires = format.RelativeTime(*id.ExpiresAt)
+	}
+
+	cols := []string{"User", "Token", "Source", "Scope", "Expires"}
+	obj := []string{id.Email, id.Kind, id.Source, scope, expires}
+
+	if len(id.AllowedIPs) > 0 {
+		cols = append(cols, "Allowed IPs")
+		obj = append(obj, strings.Join(id.AllowedIPs, ", "))
+	}
+
+	cols = append(cols, "Organizations")
+	obj = append(obj, strings.Join(id.Organizations, ", "))
+
+	if err := render.VerticalTable(out, "Identity", [][]string{obj}, cols...); err != nil {
+		return err
+	}
+
+	appRows := make([][]string, 0, len(id.Apps))
+	for _, app := range id.Apps {
+		appRows = append(appRows, []string{app})
+	}
+
+	return render.Table(out, "Apps", appRows, "Name")
+}
+
+// runCheck fails unless the token in use can perform the action of check,
+// given as app:action, on its app.
+func runCheck(ctx context.Context, check string) error {
+	var (
+		client = client.FromContext(ctx).API()
+		out    = iostreams.FromContext(ctx).Out
+	)
+
+	appName, action, ok := strings.Cut(check, ":")
+	if !ok || appName == "" {
+		return fmt.Errorf("invalid --check %q, must be like app:deploy", check)
+	}
+
+	switch action {
+	case "read", "deploy", "machines":
+	default:
+		return fmt.Errorf("unknown action %q, must be one of read, deploy or machines", action)
+	}
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("no: the token in use can't access app %s: %w", appName, err)
+	}
+
+	token, err := currentToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	if reason := denyReason(token, app, action); reason != "" {
+		return fmt.Errorf("no: the token in use can't %s app %s, %s", action, appName, reason)
+	}
+
+	fmt.Fprintf(out, "yes: the token in use can %s app %s\n", action, appName)
+
+	return nil
+}
+
+// currentToken returns the scoped token in use, or nil when it's the token of
+// a user, which it always is when the API doesn't serve scoped tokens.
+func currentToken(ctx context.Context) (*api.AccessToken, error) {
+	client := client.FromContext(ctx).API()
+
+	switch served, err := client.SchemaHas(ctx, "Queries", "currentAccessToken"); {
+	case err != nil:
+		return nil, fmt.Errorf("failed checking the API serves scoped tokens: %w", err)
+	case !served:
+		return nil, nil
+	}
+
+	token, err := client.GetCurrentAccessToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving current token: %w", err)
+	}
+
+	return token, nil
+}
+
+// denyReason returns why token, or the user's token when nil, can't perform
+// action on app, or an empty string when it can.
+func denyReason(token *api.AccessToken, app *api.AppCompact, action string) string {
+	if token == nil {
+		return ""
+	}
+
+	if token.ExpiresAt != nil && token.ExpiresAt.Before(time.Now()) {
+		return "since it expired " + format.RelativeTime(*token.ExpiresAt)
+	}
+
+	if token.App != nil && token.App.Name != app.Name {
+		return "since it's limited to app " + token.App.Name
+	}
+
+	if token.Organization != nil && app.Organization != nil && token.Organization.Slug != app.Organization.Slug {
+		return "since it's limited to organization " + token.Organization.Slug
+	}
+
+	if len(token.MachineIDs) > 0 {
+		return "since it's limited to machines " + strings.Join(token.MachineIDs, ", ")
+	}
+
+	for _, permission := range scopePermissions[token.Scope] {
+		if permission == action {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("since its scope is %s", strings.ToLower(strings.ReplaceAll(token.Scope, "_", "")))
+}
+
+// tokenKind describes the kind of the token in use.
+func tokenKind(accessToken string, token *api.AccessToken) string {
+	switch {
+	case strings.HasPrefix(accessToken, "FlyV1 "), strings.HasPrefix(accessToken, "fm1"), strings.HasPrefix(accessToken, "fm2_"):
+		return "macaroon"
+	case token != nil:
+		return "scoped access token"
+	default:
+		return "user access token"
+	}
+}
+
+// tokenSource describes where the token in use comes from, following the
+// precedence config applies them in.
+func tokenSource(ctx context.Context) string {
+	cfg := config.FromContext(ctx)
+
+	if flag.FromContext(ctx).Changed(flag.AccessTokenName) {
+		return "the --" + flag.AccessTokenName + " flag"
+	}
+
+	for _, key := range []string{config.AccessTokenEnvKey, config.APITokenEnvKey} {
+		if os.Getenv(key) != "" {
+			return "the $" + key + " environment variable"
+		}
+	}
+
+	if cfg.Context != "" {
+		return fmt.Sprintf("context %s of %s", cfg.Context, state.ConfigFile(ctx))
+	}
+
+	return state.ConfigFile(ctx)
+}
@@ -0,0 +1,74 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDelete() *cobra.Command {
+	const (
+		long = `Delete the autoscaling policy of a process group, which defaults to app. Its
+machines are kept at the count they're at.`
+		short = "Delete the autoscaling policy of a process group"
+		usage = "delete [group]"
+	)
+
+	cmd := command.New(usage, short, long, runDelete,
+		requirePolicies,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"rm"}
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runDelete(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	appCompact, err := machinesApp(ctx)
+	if err != nil {
+		return err
+	}
+
+	group := "app"
+	if arg := flag.FirstArg(ctx); arg != "" {
+		group = arg
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Delete the autoscaling policy of %s?", group); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := client.FromContext(ctx).API().DeleteAutoscalePolicy(ctx, appCompact.Name, group); err != nil {
+		return fmt.Errorf("failed deleting the autoscaling policy of %s: %w", group, err)
+	}
+
+	fmt.Fprintf(io.Out, "Deleted the autoscaling policy of %s; its machines stay at the count they're at\n", group)
+
+	return nil
+}
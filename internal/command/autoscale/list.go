@@ -0,0 +1,101 @@
+package autoscale
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `List the autoscaling policies of the process groups of an app, along with
+the count of machines they run, the last value of their metric the platform
+saw, and when they last scaled.`
+		short = "List the autoscaling policies of an app"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		requirePolicies,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls", "show"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Format(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	io := iostreams.FromContext(ctx)
+
+	appCompact, err := machinesApp(ctx)
+	if err != nil {
+		return err
+	}
+
+	policies, err := client.FromContext(ctx).API().GetAutoscalePolicies(ctx, appCompact.Name)
+	if err != nil {
+		return fmt.Errorf("failed retrieving autoscaling policies: %w", err)
+	}
+
+	if ok, err := render.Structured(ctx, io.Out, policies); ok {
+		return err
+	}
+
+	if len(policies) == 0 {
+		fmt.Fprintf(io.Out, "%s has no autoscaling policies; set one with fly autoscale policy set\n", appCompact.Name)
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(policies))
+	for _, policy := range policies {
+		metric := policy.Metric
+		if policy.Query != "" {
+			metric += " " + policy.Query
+		}
+
+		var lastValue, lastScaled string
+		if policy.LastValue != nil {
+			lastValue = formatValue(*policy.LastValue)
+		}
+		if policy.LastScaledAt != nil {
+			lastScaled = format.RelativeTime(*policy.LastScaledAt)
+		}
+
+		rows = append(rows, []string{
+			policy.ProcessGroup,
+			fmt.Sprintf("%d-%d", policy.MinCount, policy.MaxCount),
+			strconv.Itoa(policy.CurrentCount),
+			metric,
+			formatValue(policy.Target),
+			lastValue,
+			lastScaled,
+		})
+	}
+
+	return render.Table(io.Out, "", rows, "Group", "Range", "Machines", "Metric", "Target", "Last Value", "Last Scaled")
+}
+
+// formatValue formats the value of a metric without trailing zeros.
+func formatValue(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
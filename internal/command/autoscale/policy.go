@@ -0,0 +1,67 @@
+// Package autoscale implements the autoscale policy command chain.
+package autoscale
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// The metrics policies scale on.
+const (
+	metricConcurrency = "concurrency"
+	metricQueue       = "queue"
+	metricCPU         = "cpu"
+)
+
+// NewPolicy returns the policy command, which the autoscale command of the
+// legacy command tree carries.
+func NewPolicy() *cobra.Command {
+	const (
+		long = `Commands for managing the autoscaling policies of apps running on machines.
+
+A policy scales the machines of a process group between a minimum and a maximum
+count, keeping a metric near a target: the HTTP concurrency of its machines,
+their CPU utilization, or the depth of a queue, given as a PromQL expression
+over the app's metrics. The platform evaluates policies; the last value of
+their metric it saw is shown by fly autoscale policy list.
+
+Policies need the API to serve them, which it doesn't everywhere yet.`
+		short = "Manage metrics-based autoscaling policies"
+	)
+
+	cmd := command.New("policy", short, long, nil)
+
+	cmd.AddCommand(
+		newSet(),
+		newList(),
+		newDelete(),
+	)
+
+	return cmd
+}
+
+// requirePolicies makes sure the API serves autoscaling policies.
+var requirePolicies = command.RequireAPI("App", "autoscalePolicies")
+
+// machinesApp returns the app of ctx, which must run on machines.
+func machinesApp(ctx context.Context) (*api.AppCompact, error) {
+	appName := app.NameFromContext(ctx)
+
+	appCompact, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	if appCompact.PlatformVersion != app.MachinesPlatform {
+		return nil, fmt.Errorf("app %s doesn't run on machines; use fly autoscale balanced or standard instead", appName)
+	}
+
+	return appCompact, nil
+}
@@ -0,0 +1,162 @@
+package autoscale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSet() *cobra.Command {
+	const (
+		long = `Set the autoscaling policy of a process group, which defaults to app,
+replacing its previous one. Its machines are scaled between --min and --max
+to keep --metric near --target:
+
+  concurrency  the average number of HTTP requests each machine handles
+  cpu          the average CPU utilization of the machines, in percent
+  queue        the value of the PromQL expression of --query per machine,
+               like the depth of a queue the machines work off
+
+The query is evaluated once before the policy is set, to catch mistakes.`
+		short = "Set the autoscaling policy of a process group"
+		usage = "set [group]"
+	)
+
+	cmd := command.New(usage, short, long, runSet,
+		requirePolicies,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Int{
+			Name:        "min",
+			Description: "Minimum count of machines",
+			Default:     1,
+		},
+		flag.Int{
+			Name:        "max",
+			Description: "Maximum count of machines",
+		},
+		flag.String{
+			Name:        "metric",
+			Description: "Metric to scale on: concurrency, cpu or queue",
+			Default:     metricConcurrency,
+		},
+		flag.String{
+			Name:        "target",
+			Description: "Value of the metric to keep machines near",
+		},
+		flag.String{
+			Name:        "query",
+			Description: "PromQL expression of the depth of the queue, with --metric queue",
+		},
+	)
+
+	return cmd
+}
+
+func runSet(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	appCompact, err := machinesApp(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := api.SetAutoscalePolicyInput{
+		AppID:        appCompact.Name,
+		ProcessGroup: "app",
+		MinCount:     flag.GetInt(ctx, "min"),
+		MaxCount:     flag.GetInt(ctx, "max"),
+		Metric:       flag.GetString(ctx, "metric"),
+		Query:        flag.GetString(ctx, "query"),
+	}
+
+	if group := flag.FirstArg(ctx); group != "" {
+		input.ProcessGroup = group
+	}
+
+	if _, err := fmt.Sscan(flag.GetString(ctx, "target"), &input.Target); err != nil {
+		return errors.New("--target must be given a number")
+	}
+
+	if err := validatePolicy(input); err != nil {
+		return err
+	}
+
+	if input.Metric == metricQueue {
+		if _, err := apiClient.QueryMetrics(ctx, appCompact.Organization.Slug, input.Query, time.Now()); err != nil {
+			return fmt.Errorf("failed evaluating --query: %w", err)
+		}
+	}
+
+	policy, err := apiClient.SetAutoscalePolicy(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed setting the autoscaling policy of %s: %w", input.ProcessGroup, err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, policy)
+	}
+
+	fmt.Fprintf(io.Out, "Machines of %s now scale between %d and %d, keeping %s near %s\n",
+		colorize.Bold(policy.ProcessGroup), policy.MinCount, policy.MaxCount, policy.Metric, formatValue(policy.Target))
+
+	return nil
+}
+
+// validatePolicy returns an error when input isn't a policy the platform can
+// evaluate.
+func validatePolicy(input api.SetAutoscalePolicyInput) error {
+	switch {
+	case input.MinCount < 0:
+		return errors.New("--min can't be negative")
+	case input.MaxCount < 1:
+		return errors.New("--max must be given a count of at least 1")
+	case input.MaxCount < input.MinCount:
+		return fmt.Errorf("--max %d is below --min %d", input.MaxCount, input.MinCount)
+	case input.Target <= 0:
+		return errors.New("--target must be positive")
+	}
+
+	switch input.Metric {
+	case metricConcurrency:
+	case metricCPU:
+		if input.Target > 100 {
+			return errors.New("--target of cpu is a percentage, so can't exceed 100")
+		}
+	case metricQueue:
+		if input.Query == "" {
+			return errors.New("--query must be given with --metric queue")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown metric %q, must be one of concurrency, cpu or queue", input.Metric)
+	}
+
+	if input.Query != "" {
+		return errors.New("--query may only be given with --metric queue")
+	}
+
+	return nil
+}
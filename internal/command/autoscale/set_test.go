@@ -0,0 +1,39 @@
+package autoscale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestValidatePolicy(t *testing.T) {
+	policy := func(metric string, min, max int, target float64, query string) api.SetAutoscalePolicyInput {
+		return api.SetAutoscalePolicyInput{MinCount: min, MaxCount: max, Metric: metric, Target: target, Query: query}
+	}
+
+	valid := []api.SetAutoscalePolicyInput{
+		policy(metricConcurrency, 1, 10, 25, ""),
+		policy(metricConcurrency, 0, 1, 0.5, ""),
+		policy(metricCPU, 2, 2, 80, ""),
+		policy(metricQueue, 0, 5, 100, `sum(jobs_pending{app="worker"})`),
+	}
+	for _, input := range valid {
+		assert.NoError(t, validatePolicy(input), "%+v", input)
+	}
+
+	invalid := []api.SetAutoscalePolicyInput{
+		policy(metricConcurrency, -1, 10, 25, ""),
+		policy(metricConcurrency, 0, 0, 25, ""),
+		policy(metricConcurrency, 5, 2, 25, ""),
+		policy(metricConcurrency, 1, 10, 0, ""),
+		policy(metricConcurrency, 1, 10, 25, "up"),
+		policy(metricCPU, 1, 10, 120, ""),
+		policy(metricQueue, 1, 10, 100, ""),
+		policy("memory", 1, 10, 80, ""),
+	}
+	for _, input := range invalid {
+		assert.Error(t, validatePolicy(input), "%+v", input)
+	}
+}
@@ -0,0 +1,23 @@
+// Package billing implements the billing command chain.
+package billing
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+func New() *cobra.Command {
+	const (
+		long  = `Commands for reporting the usage of an organization and what it costs.`
+		short = "Report usage and costs"
+	)
+
+	cmd := command.New("billing", short, long, nil)
+
+	cmd.AddCommand(
+		newUsage(),
+	)
+
+	return cmd
+}
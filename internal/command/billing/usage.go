@@ -0,0 +1,343 @@
+package billing
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// monthLayout is the layout --month takes.
+const monthLayout = "2006-01"
+
+// categories are the columns usage is broken down into, in the order they're
+// shown.
+var categories = []string{"MACHINE", "VOLUME", "BANDWIDTH", "OTHER"}
+
+func newUsage() *cobra.Command {
+	const (
+		long = `Reports the usage of an organization over a month, the current one unless
+--month is given, and what it costs, broken down by app into machines,
+volumes, bandwidth and other resources. --resources lists the usage of each
+machine, volume and app's bandwidth instead.
+
+--forecast projects the cost of the current month from its run rate so far.
+--format csv writes the usage of each resource as CSV, for spreadsheets.
+
+Usage reports need the API to serve them, which it doesn't everywhere yet.
+`
+		short = "Report the usage and costs of an organization"
+	)
+
+	cmd := command.New("usage", short, long, runUsage,
+		command.RequireAPI("Organization", "usage"),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.String{
+			Name:        "month",
+			Description: "Month to report usage for, like 2022-09",
+		},
+		flag.Bool{
+			Name:        "resources",
+			Description: "List the usage of each resource rather than of each app",
+		},
+		flag.Bool{
+			Name:        "forecast",
+			Description: "Project the cost of the current month from its run rate",
+		},
+		flag.String{
+			Name:        "format",
+			Description: "Output format: table or csv",
+			Default:     "table",
+		},
+	)
+
+	return cmd
+}
+
+// appUsage is the usage of an app, in cents per category.
+type appUsage struct {
+	App           string
+	Cents         map[string]int
+	TotalCents    int
+	ForecastCents int `json:",omitempty"`
+}
+
+func runUsage(ctx context.Context) error {
+	var (
+		client   = client.FromContext(ctx).API()
+		out      = iostreams.FromContext(ctx).Out
+		format   = flag.GetString(ctx, "format")
+		forecast = flag.GetBool(ctx, "forecast")
+		now      = time.Now().UTC()
+	)
+
+	if format != "table" && format != "csv" {
+		return fmt.Errorf("unknown format %q, must be table or csv", format)
+	}
+
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if month := flag.GetString(ctx, "month"); month != "" {
+		var err error
+		if start, err = time.Parse(monthLayout, month); err != nil {
+			return fmt.Errorf("invalid --month %q, must be like 2022-09", month)
+		}
+	}
+
+	end := start.AddDate(0, 1, 0)
+	current := !now.Before(start) && now.Before(end)
+
+	switch {
+	case start.After(now):
+		return errors.New("--month can't be in the future")
+	case forecast && !current:
+		return errors.New("--forecast only applies to the current month")
+	case current:
+		end = now
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	usage, err := client.GetOrganizationUsage(ctx, org.Slug, start, end)
+	if err != nil {
+		return fmt.Errorf("failed retrieving usage of %s: %w", org.Slug, err)
+	}
+
+	// the ratio of the month to the part of it that elapsed, by which costs
+	// so far are projected
+	ratio := 0.0
+	if forecast {
+		ratio = float64(start.AddDate(0, 1, 0).Sub(start)) / float64(now.Sub(start))
+	}
+
+	if format == "csv" {
+		return writeCSV(ctx, usage, ratio)
+	}
+
+	apps := usageByApp(usage, ratio)
+
+	if config.FromContext(ctx).JSONOutput {
+		report := struct {
+			Organization  string
+			Month         string
+			Currency      string
+			TotalCents    int
+			ForecastCents int `json:",omitempty"`
+			Apps          []*appUsage
+			Items         []api.UsageItem
+		}{
+			Organization: org.Slug,
+			Month:        start.Format(monthLayout),
+			Currency:     usage.Currency,
+			TotalCents:   usage.TotalCents,
+			Apps:         apps,
+			Items:        usage.Items,
+		}
+
+		if forecast {
+			report.ForecastCents = project(usage.TotalCents, ratio)
+		}
+
+		return render.JSON(out, report)
+	}
+
+	title := fmt.Sprintf("Usage of %s in %s", org.Slug, start.Format("January 2006"))
+	if current {
+		title += " so far"
+	}
+
+	if flag.GetBool(ctx, "resources") {
+		if err := renderResources(ctx, title, usage); err != nil {
+			return err
+		}
+	} else if err := renderApps(ctx, title, usage.Currency, apps, forecast); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Total: %s\n", formatAmount(usage.TotalCents, usage.Currency))
+	if forecast {
+		fmt.Fprintf(out, "Forecast for %s: %s\n", start.Format("January"), formatAmount(project(usage.TotalCents, ratio), usage.Currency))
+	}
+
+	return nil
+}
+
+// usageByApp sums up the usage of each app, costliest first, projecting it
+// by ratio unless it's 0.
+func usageByApp(usage *api.OrganizationUsage, ratio float64) []*appUsage {
+	byName := map[string]*appUsage{}
+
+	var apps []*appUsage
+	for _, item := range usage.Items {
+		a, ok := byName[item.AppName]
+		if !ok {
+			a = &appUsage{
+				App:   item.AppName,
+				Cents: map[string]int{},
+			}
+			byName[item.AppName] = a
+			apps = append(apps, a)
+		}
+
+		a.Cents[category(item)] += item.AmountCents
+		a.TotalCents += item.AmountCents
+	}
+
+	for _, a := range apps {
+		if ratio > 0 {
+			a.ForecastCents = project(a.TotalCents, ratio)
+		}
+	}
+
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].TotalCents > apps[j].TotalCents
+	})
+
+	return apps
+}
+
+func renderApps(ctx context.Context, title, currency string, apps []*appUsage, forecast bool) error {
+	out := iostreams.FromContext(ctx).Out
+
+	cols := []string{"App", "Machines", "Volumes", "Bandwidth", "Other", "Total"}
+	if forecast {
+		cols = append(cols, "Forecast")
+	}
+
+	rows := make([][]string, 0, len(apps))
+	for _, a := range apps {
+		name := a.App
+		if name == "" {
+			name = "(organization)"
+		}
+
+		row := []string{name}
+		for _, c := range categories {
+			row = append(row, formatAmount(a.Cents[c], currency))
+		}
+		row = append(row, formatAmount(a.TotalCents, currency))
+
+		if forecast {
+			row = append(row, formatAmount(a.ForecastCents, currency))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return render.Table(out, title, rows, cols...)
+}
+
+func renderResources(ctx context.Context, title string, usage *api.OrganizationUsage) error {
+	out := iostreams.FromContext(ctx).Out
+
+	items := append([]api.UsageItem(nil), usage.Items...)
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].AmountCents > items[j].AmountCents
+	})
+
+	rows := make([][]string, 0, len(items))
+	for _, item := range items {
+		rows = append(rows, []string{
+			item.AppName,
+			category(item),
+			item.ResourceID,
+			item.Description,
+			formatQuantity(item),
+			formatAmount(item.AmountCents, usage.Currency),
+		})
+	}
+
+	return render.Table(out, title, rows, "App", "Category", "Resource", "Description", "Usage", "Cost")
+}
+
+// writeCSV writes the usage of each resource as CSV, with the projected
+// cost of each as well unless ratio is 0.
+func writeCSV(ctx context.Context, usage *api.OrganizationUsage, ratio float64) error {
+	w := csv.NewWriter(iostreams.FromContext(ctx).Out)
+
+	header := []string{"app", "category", "resource", "description", "quantity", "unit", "cost", "currency"}
+	if ratio > 0 {
+		header = append(header, "forecast")
+	}
+
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range usage.Items {
+		record := []string{
+			item.AppName,
+			category(item),
+			item.ResourceID,
+			item.Description,
+			strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+			item.Unit,
+			formatCents(item.AmountCents),
+			usage.Currency,
+		}
+
+		if ratio > 0 {
+			record = append(record, formatCents(project(item.AmountCents, ratio)))
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
+
+// category returns the category of item, counting unknown ones as OTHER.
+func category(item api.UsageItem) string {
+	for _, c := range categories {
+		if item.Category == c {
+			return c
+		}
+	}
+
+	return "OTHER"
+}
+
+func project(cents int, ratio float64) int {
+	return int(float64(cents)*ratio + 0.5)
+}
+
+func formatCents(cents int) string {
+	return fmt.Sprintf("%.2f", float64(cents)/100)
+}
+
+func formatAmount(cents int, currency string) string {
+	if currency == "" || currency == "USD" {
+		return "$" + formatCents(cents)
+	}
+
+	return formatCents(cents) + " " + currency
+}
+
+func formatQuantity(item api.UsageItem) string {
+	return strconv.FormatFloat(item.Quantity, 'f', -1, 64) + " " + item.Unit
+}
@@ -1,6 +1,8 @@
 package checks
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
@@ -13,9 +15,27 @@ func New() *cobra.Command {
 	flag.Add(cmd, commonFlags)
 
 	// fly checks list
-	listCmd := command.New("list", "List health checks", "", runAppCheckList, command.RequireSession, command.RequireAppName)
+	const listLong = `List the health checks of the app and their latest results.
+
+The platform only reports the latest result of each check of a machine, so
+flyctl records the results it sees each time checks are listed, on this
+computer. --history shows those recorded results of each check, with the
+output of failing ones. They're a sample rather than a full history: results
+which came and went between listings, or were seen by other computers, are
+missing. With --watch, --history keeps polling for new results every
+--interval, so that flapping checks can be caught in the act. Without
+--history, --watch refreshes the list every --interval instead, highlighting
+what changed.
+`
+	listCmd := command.New("list", "List health checks", listLong, runAppCheckList, command.RequireSession, command.RequireAppName)
 	flag.Add(listCmd, commonFlags,
 		flag.String{Name: "check-name", Description: "Filter checks by name"},
+		flag.String{Name: "machine", Description: "Filter checks by machine ID"},
+		flag.Bool{Name: "history", Description: "Show the results of each check of machines recorded when checks were listed on this computer"},
+		flag.Int{Name: "limit", Description: "How many of the latest results of each check --history shows", Default: 10},
+		flag.Bool{Name: "watch", Description: "Refresh the list, or keep polling for new results with --history"},
+		flag.Duration{Name: "interval", Description: "How often --watch refreshes or polls", Default: 15 * time.Second},
+		flag.Format(),
 	)
 	cmd.AddCommand(listCmd)
 
@@ -0,0 +1,135 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// historyLimit is how many results are kept for each check of each machine.
+const historyLimit = 100
+
+// checkResult is a result of a machine check, as recorded in the history.
+type checkResult struct {
+	Machine string    `json:"machine"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	Output  string    `json:"output"`
+	Time    time.Time `json:"time"`
+}
+
+// checkHistory holds the results of the checks of the machines of an app
+// that flyctl has seen, since the platform only reports their latest ones.
+type checkHistory struct {
+	path    string
+	Results []checkResult `json:"results"`
+}
+
+func historyPath(ctx context.Context, appName string) string {
+	return filepath.Join(state.ConfigDirectory(ctx), "checks", appName+".json")
+}
+
+func loadCheckHistory(path string) (*checkHistory, error) {
+	h := &checkHistory{path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("failed parsing check history %s: %w", path, err)
+	}
+
+	return h, nil
+}
+
+// record adds the results of the checks of machines that aren't in the
+// history yet, and returns them.
+func (h *checkHistory) record(machines []*api.Machine) (added []checkResult) {
+	latest := map[string]time.Time{}
+	for _, r := range h.Results {
+		if key := r.Machine + "/" + r.Name; r.Time.After(latest[key]) {
+			latest[key] = r.Time
+		}
+	}
+
+	for _, machine := range machines {
+		for _, check := range machine.Checks {
+			if check.UpdatedAt == nil {
+				continue
+			}
+
+			if !check.UpdatedAt.After(latest[machine.ID+"/"+check.Name]) {
+				continue
+			}
+
+			added = append(added, checkResult{
+				Machine: machine.ID,
+				Name:    check.Name,
+				Status:  check.Status,
+				Output:  check.Output,
+				Time:    *check.UpdatedAt,
+			})
+		}
+	}
+
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].Time.Before(added[j].Time)
+	})
+
+	h.Results = append(h.Results, added...)
+	h.trim()
+
+	return added
+}
+
+// trim drops all but the latest historyLimit results of each check.
+func (h *checkHistory) trim() {
+	sort.SliceStable(h.Results, func(i, j int) bool {
+		return h.Results[i].Time.Before(h.Results[j].Time)
+	})
+
+	counts := map[string]int{}
+	kept := make([]checkResult, 0, len(h.Results))
+
+	for i := len(h.Results) - 1; i >= 0; i-- {
+		r := h.Results[i]
+
+		key := r.Machine + "/" + r.Name
+		if counts[key]++; counts[key] <= historyLimit {
+			kept = append(kept, r)
+		}
+	}
+
+	// kept was filled from the latest result back
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+
+	h.Results = kept
+}
+
+func (h *checkHistory) save() error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(h.path, data, 0o600)
+}
@@ -2,9 +2,16 @@ package checks
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"sort"
 	"strings"
+	"time"
+
+	"github.com/azazeal/pause"
+	"github.com/samber/lo"
 
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
@@ -24,35 +31,93 @@ func runAppCheckList(ctx context.Context) error {
 
 	app, err := web.GetAppCompact(ctx, appName)
 	if err != nil {
-		return fmt.Errorf("failed to get app: %s", err)
+		return fmt.Errorf("failed to get app: %w", err)
 	}
 
 	if app.PlatformVersion == "machines" {
 		return runMachinesAppCheckList(ctx, app)
 	}
-	return runNomadAppCheckList(ctx)
+
+	if flag.GetBool(ctx, "history") {
+		return errors.New("--history is only supported for apps running on machines")
+	}
+
+	return watchOrList(ctx, appName, listNomadAppChecks)
 }
 
-func runMachinesAppCheckList(ctx context.Context, app *api.AppCompact) error {
-	out := iostreams.FromContext(ctx).Out
-	nameFilter := flag.GetString(ctx, "check-name")
+// watchOrList lists checks with list, every --interval with --watch.
+func watchOrList(ctx context.Context, appName string, list func(context.Context, io.Writer) error) error {
+	if flag.GetBool(ctx, "watch") {
+		return render.Watch(ctx, flag.GetDuration(ctx, "interval"), "Health checks of "+appName, list)
+	}
+
+	return list(ctx, iostreams.FromContext(ctx).Out)
+}
 
+func runMachinesAppCheckList(ctx context.Context, app *api.AppCompact) error {
 	flapsClient, err := flaps.New(ctx, app)
 	if err != nil {
 		return err
 	}
 
-	machines, err := flapsClient.ListActive(ctx)
+	history, err := loadCheckHistory(historyPath(ctx, app.Name))
 	if err != nil {
 		return err
 	}
-	sort.Slice(machines, func(i, j int) bool {
-		return machines[i].ID < machines[j].ID
+
+	// listMachines lists the machines checks are filtered to, recording the
+	// latest results of their checks
+	listMachines := func(ctx context.Context) ([]*api.Machine, error) {
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(machines, func(i, j int) bool {
+			return machines[i].ID < machines[j].ID
+		})
+
+		history.record(machines)
+		if err := history.save(); err != nil {
+			fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "failed recording check results: %v\n", err)
+		}
+
+		if machineFilter := flag.GetString(ctx, "machine"); machineFilter != "" {
+			machines = lo.Filter(machines, func(m *api.Machine, _ int) bool {
+				return m.ID == machineFilter
+			})
+		}
+
+		return machines, nil
+	}
+
+	if flag.GetBool(ctx, "history") {
+		if _, err := listMachines(ctx); err != nil {
+			return err
+		}
+
+		return runCheckHistory(ctx, flapsClient, history)
+	}
+
+	return watchOrList(ctx, app.Name, func(ctx context.Context, out io.Writer) error {
+		machines, err := listMachines(ctx)
+		if err != nil {
+			return err
+		}
+
+		return renderMachineChecks(ctx, out, app.Name, machines)
 	})
+}
 
-	fmt.Fprintf(out, "Health Checks for %s\n", app.Name)
-	table := helpers.MakeSimpleTable(out, []string{"Name", "Status", "Machine", "Last Updated", "Output"})
-	table.SetRowLine(true)
+// machineCheck is the status of a check of a machine.
+type machineCheck struct {
+	Machine string `json:"machine"`
+	api.MachineCheckStatus
+}
+
+func renderMachineChecks(ctx context.Context, out io.Writer, appName string, machines []*api.Machine) error {
+	nameFilter := flag.GetString(ctx, "check-name")
+
+	var checks []machineCheck
 	for _, machine := range machines {
 		sort.Slice(machine.Checks, func(i, j int) bool {
 			return machine.Checks[i].Name < machine.Checks[j].Name
@@ -62,17 +127,31 @@ func runMachinesAppCheckList(ctx context.Context, app *api.AppCompact) error {
 			if nameFilter != "" && nameFilter != check.Name {
 				continue
 			}
-			table.Append([]string{check.Name, check.Status, machine.ID, format.RelativeTime(*check.UpdatedAt), check.Output})
+			checks = append(checks, machineCheck{Machine: machine.ID, MachineCheckStatus: *check})
+		}
+	}
+
+	if ok, err := render.Structured(ctx, out, checks); ok {
+		return err
+	}
+
+	fmt.Fprintf(out, "Health Checks for %s\n", appName)
+	table := helpers.MakeSimpleTable(out, []string{"Name", "Status", "Machine", "Last Updated", "Output"})
+	table.SetRowLine(true)
+	for _, check := range checks {
+		updated := "-"
+		if check.UpdatedAt != nil {
+			updated = format.RelativeTime(*check.UpdatedAt)
 		}
+		table.Append([]string{check.Name, check.Status, check.Machine, updated, check.Output})
 	}
 	table.Render()
 
 	return nil
 }
 
-func runNomadAppCheckList(ctx context.Context) error {
+func listNomadAppChecks(ctx context.Context, out io.Writer) error {
 	appName := app.NameFromContext(ctx)
-	out := iostreams.FromContext(ctx).Out
 	web := client.FromContext(ctx).API()
 
 	var nameFilter *string
@@ -85,8 +164,8 @@ func runNomadAppCheckList(ctx context.Context) error {
 		return err
 	}
 
-	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, checks)
+	if ok, err := render.Structured(ctx, out, checks); ok {
+		return err
 	}
 
 	fmt.Fprintf(out, "Health Checks for %s\n", appName)
@@ -110,3 +189,107 @@ func formatOutput(output string) string {
 	}
 	return newstr
 }
+
+func runCheckHistory(ctx context.Context, flapsClient *flaps.Client, history *checkHistory) error {
+	var (
+		out     = iostreams.FromContext(ctx).Out
+		limit   = flag.GetInt(ctx, "limit")
+		results = filterResults(ctx, history.Results)
+	)
+
+	if limit < 1 {
+		return errors.New("--limit must be at least 1")
+	}
+
+	// keep the latest results of each check, newest first
+	counts := map[string]int{}
+	var shown []checkResult
+	for i := len(results) - 1; i >= 0; i-- {
+		r := results[i]
+
+		key := r.Machine + "/" + r.Name
+		if counts[key]++; counts[key] <= limit {
+			shown = append(shown, r)
+		}
+	}
+
+	sort.SliceStable(shown, func(i, j int) bool {
+		if shown[i].Machine != shown[j].Machine {
+			return shown[i].Machine < shown[j].Machine
+		}
+		return shown[i].Name < shown[j].Name
+	})
+
+	if ok, err := render.Structured(ctx, out, shown); ok {
+		if err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(shown))
+		for _, r := range shown {
+			rows = append(rows, []string{r.Machine, r.Name, r.Status, format.RelativeTime(r.Time), resultOutput(r)})
+		}
+
+		if err := render.Table(out, "Health Check Results Seen Locally", rows, "Machine", "Name", "Status", "When", "Output"); err != nil {
+			return err
+		}
+
+		fmt.Fprintln(iostreams.FromContext(ctx).ErrOut, "These are the results flyctl saw when checks were listed on this computer; results in between are missing.")
+	}
+
+	if !flag.GetBool(ctx, "watch") {
+		return nil
+	}
+
+	interval := flag.GetDuration(ctx, "interval")
+	if interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	for {
+		if pause.For(ctx, interval); ctx.Err() != nil {
+			return nil
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			return err
+		}
+
+		added := filterResults(ctx, history.record(machines))
+		if err := history.save(); err != nil {
+			return fmt.Errorf("failed recording check results: %w", err)
+		}
+
+		for _, r := range added {
+			if config.FromContext(ctx).JSONOutput {
+				if err := json.NewEncoder(out).Encode(r); err != nil {
+					return err
+				}
+				continue
+			}
+
+			fmt.Fprintf(out, "%s %s %s %s %s\n", r.Time.Format(time.RFC3339), r.Machine, r.Name, r.Status, resultOutput(r))
+		}
+	}
+}
+
+// filterResults narrows results down to the checks and machines filtered by.
+func filterResults(ctx context.Context, results []checkResult) []checkResult {
+	name := flag.GetString(ctx, "check-name")
+	machine := flag.GetString(ctx, "machine")
+
+	return lo.Filter(results, func(r checkResult, _ int) bool {
+		return (name == "" || r.Name == name) && (machine == "" || r.Machine == machine)
+	})
+}
+
+// resultOutput returns the output of failing results, which holds what the
+// check got back, such as the response body of HTTP checks.
+func resultOutput(r checkResult) string {
+	if r.Status == "passing" {
+		return ""
+	}
+
+	return strings.TrimSpace(r.Output)
+}
@@ -9,18 +9,22 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
+	"github.com/azazeal/pause"
 	"github.com/blang/semver"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/buildinfo"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/env"
+	"github.com/superfly/flyctl/internal/filemu"
 	"github.com/superfly/flyctl/internal/logger"
 	"github.com/superfly/flyctl/internal/update"
 
@@ -58,6 +62,7 @@ var commonPreparers = []Preparer{
 	ensureConfigDirPerms,
 	loadCache,
 	loadConfig,
+	disablePrompts,
 	initTaskManager,
 	startQueryingForNewRelease,
 	promptToUpdate,
@@ -65,6 +70,29 @@ var commonPreparers = []Preparer{
 	killOldAgent,
 }
 
+// completionPreparers are the preparers run before completing the arguments
+// and flags of commands. Unlike commonPreparers, they neither create the
+// config directory nor check for updates. The config file is only written to
+// when initClient refreshes an SSO session that's about to expire.
+var completionPreparers = []Preparer{
+	determineWorkingDir,
+	determineUserHomeDir,
+	determineConfigDir,
+	loadConfig,
+	initClient,
+	LoadAppConfigIfPresent,
+}
+
+// PrepareCompletion returns the context the arguments and flags of cmd are
+// completed with, carrying the config, the client and the app config.
+func PrepareCompletion(cmd *cobra.Command) (context.Context, error) {
+	ctx := cmd.Context()
+	ctx = NewContext(ctx, cmd)
+	ctx = flag.NewContext(ctx, cmd.Flags())
+
+	return prepare(ctx, completionPreparers...)
+}
+
 // TODO: remove after migration is complete
 func WrapRunE(fn func(*cobra.Command, []string) error) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, args []string) (err error) {
@@ -269,17 +297,41 @@ func loadConfig(ctx context.Context) (context.Context, error) {
 		return nil, err
 	}
 
+	// Apply the credentials of the context in use, if any
+	var explicit string
+	if fs := flag.FromContext(ctx); fs.Changed(flag.ContextName) {
+		explicit, _ = fs.GetString(flag.ContextName)
+	}
+	if err := cfg.ApplyContext(state.WorkingDirectory(ctx), explicit); err != nil {
+		return nil, err
+	}
+
 	// Apply config from the environment, overriding anything from the file
 	cfg.ApplyEnv()
 
 	// Finally, apply command line options, overriding any previous setting
 	cfg.ApplyFlags(flag.FromContext(ctx))
 
+	// commands that haven't been migrated yet read the token off viper
+	if cfg.Context != "" {
+		flyctl.UseContextToken(cfg.AccessToken)
+	}
+
 	logger.Debug("config initialized.")
 
 	return config.NewContext(ctx, cfg), nil
 }
 
+// disablePrompts makes prompts fail, rather than wait for input, with
+// --non-interactive.
+func disablePrompts(ctx context.Context) (context.Context, error) {
+	if config.FromContext(ctx).NonInteractive {
+		iostreams.FromContext(ctx).SetNeverPrompt(true)
+	}
+
+	return ctx, nil
+}
+
 func initClient(ctx context.Context) (context.Context, error) {
 	logger := logger.FromContext(ctx)
 	cfg := config.FromContext(ctx)
@@ -287,12 +339,119 @@ func initClient(ctx context.Context) (context.Context, error) {
 	// TODO: refactor so that api package does NOT depend on global state
 	api.SetBaseURL(cfg.APIBaseURL)
 	api.SetErrorLog(cfg.LogGQLErrors)
+	api.SetMaxRetries(cfg.MaxRetries)
+
+	if err := RefreshSession(ctx, cfg); err != nil {
+		logger.Warnf("failed refreshing your SSO session, run fly auth login --sso to log in again: %v", err)
+	}
+
 	c := client.FromToken(cfg.AccessToken)
 	logger.Debug("client initialized.")
 
 	return client.NewContext(ctx, c), nil
 }
 
+// refreshMargin is how long before it expires a short-lived access token is
+// refreshed, so that it doesn't expire while a command runs.
+const refreshMargin = 5 * time.Minute
+
+// sessionLockFile denotes the name of the file, within the config directory,
+// refreshes of SSO sessions lock, so that the commands and the agent
+// refreshing one at once don't each spend its refresh token.
+const sessionLockFile = "session.lock"
+
+var sessionMu sync.Mutex
+
+// RefreshSession refreshes the access token of cfg, and persists it, when it's
+// a short-lived one of an SSO session that's about to expire. When another
+// process refreshed it, or logged in again, first, cfg takes the access token
+// the configuration file now has instead.
+func RefreshSession(ctx context.Context, cfg *config.Config) error {
+	if cfg.RefreshToken == "" || cfg.AccessTokenExpiresAt.IsZero() {
+		return nil
+	}
+
+	if time.Until(cfg.AccessTokenExpiresAt) > refreshMargin {
+		return nil
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+
+	unlock, err := lockSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := filepath.Join(state.ConfigDirectory(ctx), config.FileName)
+
+	accessToken, refreshToken, expiresAt, err := config.SessionTokens(path, cfg.Context)
+	if err != nil {
+		return fmt.Errorf("failed reading the session: %w", err)
+	}
+
+	if accessToken != cfg.AccessToken && refreshToken != "" {
+		cfg.AccessToken = accessToken
+		cfg.RefreshToken = refreshToken
+		cfg.AccessTokenExpiresAt = expiresAt
+
+		logger.FromContext(ctx).Debug("reloaded SSO session.")
+
+		if time.Until(expiresAt) > refreshMargin {
+			return nil
+		}
+	}
+
+	auth, err := api.RefreshCLISession(ctx, cfg.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if auth.ExpiresAt == nil || auth.AccessToken == "" {
+		return errors.New("the refreshed session has no access token")
+	}
+
+	if auth.RefreshToken != "" {
+		cfg.RefreshToken = auth.RefreshToken
+	}
+	cfg.AccessToken = auth.AccessToken
+	cfg.AccessTokenExpiresAt = *auth.ExpiresAt
+
+	logger.FromContext(ctx).Debug("refreshed SSO session.")
+
+	if cfg.Context != "" {
+		flyctl.UseContextToken(cfg.AccessToken)
+
+		return config.SetContextSessionTokens(path, cfg.Context, cfg.AccessToken, cfg.RefreshToken, cfg.AccessTokenExpiresAt)
+	}
+
+	// commands that haven't been migrated yet read the token off viper
+	flyctl.SetSessionTokens(cfg.AccessToken, cfg.RefreshToken, cfg.AccessTokenExpiresAt)
+
+	return config.SetSessionTokens(path, cfg.AccessToken, cfg.RefreshToken, cfg.AccessTokenExpiresAt)
+}
+
+// lockSession waits for other processes to finish refreshing the session, for
+// up to as long as a refresh may take, and locks it.
+func lockSession(ctx context.Context) (filemu.UnlockFunc, error) {
+	path := filepath.Join(state.ConfigDirectory(ctx), sessionLockFile)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	for {
+		unlock, err := filemu.Lock(ctx, path)
+		if err == nil {
+			return unlock, nil
+		}
+
+		if pause.For(ctx, 100*time.Millisecond); ctx.Err() != nil {
+			return nil, fmt.Errorf("failed waiting for another refresh of the session: %w", err)
+		}
+	}
+}
+
 func initTaskManager(ctx context.Context) (context.Context, error) {
 	tm := task.New(ctx)
 
@@ -446,6 +605,38 @@ func RequireSession(ctx context.Context) (context.Context, error) {
 	return ctx, nil
 }
 
+// RequireAPI returns a Preparer which makes sure the API serves name, a field,
+// input field or enum value, of the type typeName, for commands relying on
+// parts of the API which aren't served everywhere yet. It embeds
+// RequireSession.
+func RequireAPI(typeName, name string) Preparer {
+	return func(ctx context.Context) (context.Context, error) {
+		if _, err := RequireSession(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := CheckAPI(ctx, typeName, name); err != nil {
+			return nil, err
+		}
+
+		return ctx, nil
+	}
+}
+
+// CheckAPI returns an error unless the API serves name of the type typeName.
+// See RequireAPI.
+func CheckAPI(ctx context.Context, typeName, name string) error {
+	ok, err := client.FromContext(ctx).API().SchemaHas(ctx, typeName, name)
+	switch {
+	case err != nil:
+		return fmt.Errorf("failed checking the API serves %s.%s: %w", typeName, name, err)
+	case !ok:
+		return fmt.Errorf("this needs %s.%s of the Fly API, which it doesn't serve yet", typeName, name)
+	default:
+		return nil
+	}
+}
+
 // LoadAppConfigIfPresent is a Preparer which loads the application's
 // configuration file from the path the user has selected via command line args
 // or the current working directory.
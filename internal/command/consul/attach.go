@@ -0,0 +1,138 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newAttach() *cobra.Command {
+	const (
+		long = `Attach an app to the Consul cluster Fly.io manages, provisioning a token for
+it and setting the secret holding the URL of the cluster, which deploys the
+app. Attaching an app again sets the secret again, which repairs it when it
+was unset or overwritten.`
+		short = "Attach an app to the managed Consul cluster"
+		usage = "attach"
+	)
+
+	cmd := command.New(usage, short, long, runAttach,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		variableNameFlag(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runAttach(ctx context.Context) error {
+	var (
+		out          = iostreams.FromContext(ctx).Out
+		client       = client.FromContext(ctx).API()
+		appName      = flag.GetApp(ctx)
+		variableName = flag.GetString(ctx, "variable-name")
+	)
+
+	consulURL, err := Attach(ctx, client, appName, variableName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s was attached to Consul at %s, and its %s secret set, which deploys it\n",
+		appName, redact(consulURL), variableName)
+
+	return nil
+}
+
+// Attach attaches the app to the managed Consul cluster, setting its
+// variableName secret to the URL of the cluster, which it returns.
+func Attach(ctx context.Context, client *api.Client, appName, variableName string) (string, error) {
+	data, err := client.EnablePostgresConsul(ctx, appName)
+	if err != nil {
+		return "", fmt.Errorf("failed attaching %s to Consul: %w", appName, err)
+	}
+
+	if _, err := client.SetSecrets(ctx, appName, map[string]string{variableName: data.ConsulURL}); err != nil {
+		return "", fmt.Errorf("failed setting %s of %s: %w", variableName, appName, err)
+	}
+
+	return data.ConsulURL, nil
+}
+
+func newRotate() *cobra.Command {
+	const (
+		long = `Replace the token an app uses to reach the managed Consul cluster, revoking
+the old one, and set the secret holding the URL of the cluster to embed the new
+one, which deploys the app.
+
+Rotating tokens needs the API to support it, which it doesn't everywhere yet.`
+		short = "Rotate the Consul token of an app"
+		usage = "rotate"
+	)
+
+	cmd := command.New(usage, short, long, runRotate,
+		command.RequireAPI("Mutations", "rotateConsulToken"),
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		variableNameFlag(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runRotate(ctx context.Context) error {
+	var (
+		out          = iostreams.FromContext(ctx).Out
+		client       = client.FromContext(ctx).API()
+		appName      = flag.GetApp(ctx)
+		variableName = flag.GetString(ctx, "variable-name")
+	)
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Rotate the Consul token of %s? Its machines can't reach Consul until deployed with the new one.", appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	data, err := client.RotateConsulToken(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed rotating the Consul token of %s: %w", appName, err)
+	}
+
+	if _, err := client.SetSecrets(ctx, appName, map[string]string{variableName: data.ConsulURL}); err != nil {
+		return fmt.Errorf("the token was rotated, but setting %s of %s failed; run fly consul attach to retry: %w", variableName, appName, err)
+	}
+
+	fmt.Fprintf(out, "The Consul token of %s was rotated, and its %s secret set, which deploys it\n", appName, variableName)
+
+	return nil
+}
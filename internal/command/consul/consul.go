@@ -0,0 +1,98 @@
+// Package consul implements the consul command chain.
+package consul
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+// DefaultVariableName is the secret holding the URL of the Consul cluster,
+// which Postgres and LiteFS apps also read.
+const DefaultVariableName = "FLY_CONSUL_URL"
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for attaching apps to the Consul cluster Fly.io manages, checking
+its health and rotating the tokens apps use to reach it.`
+		short = "Manage the Consul cluster of apps"
+	)
+
+	cmd := command.New("consul", short, long, nil)
+
+	cmd.AddCommand(
+		newAttach(),
+		newStatus(),
+		newRotate(),
+	)
+
+	return cmd
+}
+
+func variableNameFlag() flag.String {
+	return flag.String{
+		Name:        "variable-name",
+		Description: "Name of the secret holding the URL of the Consul cluster",
+		Default:     DefaultVariableName,
+	}
+}
+
+// checkHealth reports whether the Consul cluster at rawURL has a leader,
+// authenticating with the token the URL embeds.
+func checkHealth(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid Consul URL: %w", err)
+	}
+
+	token, _ := u.User.Password()
+
+	endpoint := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/v1/status/leader"}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Consul-Token", token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusForbidden:
+		return errors.New("the token was rejected, rotate it with fly consul rotate")
+	case res.StatusCode != http.StatusOK:
+		return fmt.Errorf("unexpected response: %s", res.Status)
+	case strings.Trim(strings.TrimSpace(string(body)), `"`) == "":
+		return errors.New("the cluster has no leader")
+	}
+
+	return nil
+}
+
+// redact hides the token the URL of a Consul cluster embeds.
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return u.Redacted()
+}
@@ -0,0 +1,110 @@
+package consul
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStatus() *cobra.Command {
+	const (
+		long = `Show the URL of the Consul cluster an app is attached to, whether the secret
+holding it is set, and whether the cluster is healthy.
+
+Looking up the cluster of an app needs the API to serve it, which it doesn't
+everywhere yet.`
+		short = "Show the Consul cluster of an app and its health"
+		usage = "status"
+	)
+
+	cmd := command.New(usage, short, long, runStatus,
+		command.RequireAPI("App", "consul"),
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		variableNameFlag(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+type status struct {
+	URL       string `json:"url"`
+	Region    string `json:"region"`
+	SecretSet bool   `json:"secret_set"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+}
+
+func runStatus(ctx context.Context) error {
+	var (
+		io           = iostreams.FromContext(ctx)
+		colorize     = io.ColorScheme()
+		client       = client.FromContext(ctx).API()
+		appName      = flag.GetApp(ctx)
+		variableName = flag.GetString(ctx, "variable-name")
+	)
+
+	cluster, err := client.GetAppConsul(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving the Consul cluster of %s: %w", appName, err)
+	}
+
+	if cluster == nil {
+		return fmt.Errorf("%s isn't attached to Consul; run fly consul attach to attach it", appName)
+	}
+
+	secrets, err := client.GetAppSecrets(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving secrets of %s: %w", appName, err)
+	}
+
+	s := status{
+		URL:    redact(cluster.URL),
+		Region: cluster.Region,
+	}
+
+	for _, secret := range secrets {
+		if secret.Name == variableName {
+			s.SecretSet = true
+			break
+		}
+	}
+
+	if err := checkHealth(ctx, cluster.URL); err != nil {
+		s.Error = err.Error()
+	} else {
+		s.Healthy = true
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, s)
+	}
+
+	secret := colorize.Green("set")
+	if !s.SecretSet {
+		secret = colorize.Red("not set, run fly consul attach to set it")
+	}
+
+	health := colorize.Green("healthy")
+	if !s.Healthy {
+		health = colorize.Red("unhealthy: " + s.Error)
+	}
+
+	obj := [][]string{{s.URL, s.Region, secret, health}}
+
+	return render.VerticalTable(io.Out, "Consul", obj, "URL", "Region", variableName, "Health")
+}
@@ -0,0 +1,336 @@
+// Package contexts implements the commands managing the named contexts of the
+// config file, which are attached to the config command.
+package contexts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/state"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// New returns the commands managing contexts. Contexts are named sets of
+// credentials, along with the organization commands default to with them,
+// picked with --context or $FLY_CONTEXT, by pinning them to directories, or
+// else by making them current.
+func New() []*cobra.Command {
+	return []*cobra.Command{
+		newSetContext(),
+		newUseContext(),
+		newCurrentContext(),
+		newGetContexts(),
+		newDeleteContext(),
+	}
+}
+
+func newSetContext() *cobra.Command {
+	const (
+		long = `Creates a context with the credentials in use, or updates the organization
+of an existing one. To create a context for another account, create it and
+then log it in with fly auth login --context <name>.
+`
+		short = "Create or update a context"
+		usage = "set-context <name>"
+	)
+
+	cmd := command.New(usage, short, long, runSetContext)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "default-org",
+			Description: "Organization commands default to in the context",
+		},
+	)
+
+	return cmd
+}
+
+func runSetContext(ctx context.Context) error {
+	var (
+		cfg  = config.FromContext(ctx)
+		out  = iostreams.FromContext(ctx).Out
+		name = flag.FirstArg(ctx)
+		path = state.ConfigFile(ctx)
+	)
+
+	if strings.TrimSpace(name) == "" {
+		return errors.New("context name can't be empty")
+	}
+
+	c, exists := cfg.Contexts[name]
+	if !exists {
+		c = config.Context{
+			AccessToken:  cfg.AccessToken,
+			RefreshToken: cfg.RefreshToken,
+			ExpiresAt:    cfg.AccessTokenExpiresAt,
+		}
+	}
+
+	if org := flag.GetString(ctx, "default-org"); org != "" {
+		c.Organization = org
+	}
+
+	if err := config.SetContext(path, name, c); err != nil {
+		return fmt.Errorf("failed saving context %s in %s: %w", name, path, err)
+	}
+
+	if exists {
+		fmt.Fprintf(out, "Updated context %s\n", name)
+	} else {
+		fmt.Fprintf(out, "Created context %s; use it with fly config use-context %s\n", name, name)
+	}
+
+	return nil
+}
+
+func newUseContext() *cobra.Command {
+	const (
+		long = `Makes the context with the given name the one used by default. With --dir,
+the context is pinned to the working directory instead, and is used by the
+commands run in it and in the directories it contains, which is how deploying
+a client's app with the wrong account is avoided.
+
+--unset goes back to the credentials outside of contexts, or unpins the
+working directory with --dir.
+`
+		short = "Use a context by default, or in the working directory"
+		usage = "use-context [name]"
+	)
+
+	cmd := command.New(usage, short, long, runUseContext)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "dir",
+			Description: "Pin the context to the working directory rather than using it everywhere",
+		},
+		flag.Bool{
+			Name:        "unset",
+			Description: "Stop using a context by default, or in the working directory with --dir",
+		},
+	)
+
+	return cmd
+}
+
+func runUseContext(ctx context.Context) error {
+	var (
+		cfg   = config.FromContext(ctx)
+		out   = iostreams.FromContext(ctx).Out
+		name  = flag.FirstArg(ctx)
+		path  = state.ConfigFile(ctx)
+		dir   = state.WorkingDirectory(ctx)
+		unset = flag.GetBool(ctx, "unset")
+		pin   = flag.GetBool(ctx, "dir")
+	)
+
+	switch {
+	case unset && name != "":
+		return errors.New("a context name can't be given with --unset")
+	case !unset && name == "":
+		return errors.New("a context name must be given, or --unset")
+	case !unset:
+		if _, ok := cfg.Contexts[name]; !ok {
+			return fmt.Errorf("unknown context %q; create it with fly config set-context %s", name, name)
+		}
+	}
+
+	if pin {
+		if err := config.PinContext(path, dir, name); err != nil {
+			return fmt.Errorf("failed pinning context in %s: %w", path, err)
+		}
+
+		if unset {
+			fmt.Fprintf(out, "Unpinned %s\n", dir)
+		} else {
+			fmt.Fprintf(out, "Pinned context %s to %s\n", name, dir)
+		}
+
+		return nil
+	}
+
+	if err := config.UseContext(path, name); err != nil {
+		return fmt.Errorf("failed setting current context in %s: %w", path, err)
+	}
+
+	if unset {
+		fmt.Fprintln(out, "No context is used by default anymore")
+	} else {
+		fmt.Fprintf(out, "Using context %s by default\n", name)
+	}
+
+	return nil
+}
+
+func newCurrentContext() *cobra.Command {
+	const (
+		long = `Shows the name of the context commands run in the working directory use.
+`
+		short = "Show the context in use"
+	)
+
+	cmd := command.New("current-context", short, long, runCurrentContext)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runCurrentContext(ctx context.Context) error {
+	var (
+		cfg = config.FromContext(ctx)
+		io  = iostreams.FromContext(ctx)
+	)
+
+	if cfg.JSONOutput {
+		return render.JSON(io.Out, map[string]string{"context": cfg.Context})
+	}
+
+	if cfg.Context == "" {
+		fmt.Fprintln(io.ErrOut, "No context is in use")
+
+		return nil
+	}
+
+	fmt.Fprintln(io.Out, cfg.Context)
+
+	return nil
+}
+
+func newGetContexts() *cobra.Command {
+	const (
+		long = `Lists the contexts of the config file, marking the one in use, along with
+the organizations they default to and the directories they're pinned to.
+`
+		short = "List contexts"
+	)
+
+	cmd := command.New("get-contexts", short, long, runGetContexts)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runGetContexts(ctx context.Context) error {
+	var (
+		cfg = config.FromContext(ctx)
+		out = iostreams.FromContext(ctx).Out
+	)
+
+	names := make([]string, 0, len(cfg.Contexts))
+	for name := range cfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pinned := map[string][]string{}
+	for dir, name := range cfg.PinnedContexts {
+		pinned[name] = append(pinned[name], dir)
+	}
+
+	type listed struct {
+		Name         string
+		Current      bool
+		Default      bool
+		Organization string
+		LoggedIn     bool
+		Pinned       []string
+	}
+
+	contexts := make([]listed, 0, len(names))
+	for _, name := range names {
+		c := cfg.Contexts[name]
+		sort.Strings(pinned[name])
+
+		contexts = append(contexts, listed{
+			Name:         name,
+			Current:      name == cfg.Context,
+			Default:      name == cfg.CurrentContext,
+			Organization: c.Organization,
+			LoggedIn:     c.AccessToken != "",
+			Pinned:       pinned[name],
+		})
+	}
+
+	if cfg.JSONOutput {
+		return render.JSON(out, contexts)
+	}
+
+	rows := make([][]string, 0, len(contexts))
+	for _, c := range contexts {
+		marker := ""
+		if c.Current {
+			marker = "*"
+		}
+
+		session := "logged out"
+		if c.LoggedIn {
+			session = "logged in"
+			if expiresAt := cfg.Contexts[c.Name].ExpiresAt; !expiresAt.IsZero() {
+				session += ", expires " + format.RelativeTime(expiresAt)
+			}
+		}
+
+		org := c.Organization
+		if c.Default {
+			org += " (default)"
+		}
+
+		rows = append(rows, []string{marker, c.Name, strings.TrimSpace(org), session, strings.Join(c.Pinned, ", ")})
+	}
+
+	return render.Table(out, "", rows, "Current", "Name", "Org", "Session", "Pinned To")
+}
+
+func newDeleteContext() *cobra.Command {
+	const (
+		long = `Deletes the context with the given name, along with its credentials, and
+unpins it from the directories it's pinned to.
+`
+		short = "Delete a context"
+		usage = "delete-context <name>"
+	)
+
+	cmd := command.New(usage, short, long, runDeleteContext)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runDeleteContext(ctx context.Context) error {
+	var (
+		cfg  = config.FromContext(ctx)
+		out  = iostreams.FromContext(ctx).Out
+		name = flag.FirstArg(ctx)
+		path = state.ConfigFile(ctx)
+	)
+
+	if _, ok := cfg.Contexts[name]; !ok {
+		return fmt.Errorf("unknown context %q", name)
+	}
+
+	if err := config.DeleteContext(path, name); err != nil {
+		return fmt.Errorf("failed deleting context %s in %s: %w", name, path, err)
+	}
+
+	fmt.Fprintf(out, "Deleted context %s\n", name)
+
+	return nil
+}
@@ -0,0 +1,113 @@
+// Package cron implements the cron command chain.
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// metadataKey is the metadata of the machines running cron jobs, set to the
+// name of their job.
+const metadataKey = "fly_cron_job"
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing the cron jobs of an app: commands run on a cron
+schedule by scheduled machines, which flyctl keeps track of so that a job whose
+machine went missing is reported rather than silently not run.
+
+Cron jobs need the API to keep track of them, which it doesn't everywhere yet.`
+		short = "Manage the cron jobs of an app"
+	)
+
+	cmd := command.New("cron", short, long, nil)
+
+	cmd.AddCommand(
+		newSchedule(),
+		newList(),
+		newRunNow(),
+		newLogs(),
+		newDestroy(),
+	)
+
+	return cmd
+}
+
+// requireCron makes sure the API keeps track of cron jobs.
+var requireCron = command.RequireAPI("App", "cronJobs")
+
+// jobs are the cron jobs of an app, along with their machines.
+type jobs struct {
+	app         *api.AppCompact
+	flapsClient *flaps.Client
+	list        []api.CronJob
+	machines    map[string]*api.Machine
+}
+
+func loadJobs(ctx context.Context) (*jobs, error) {
+	var (
+		apiClient = client.FromContext(ctx).API()
+		appName   = app.NameFromContext(ctx)
+	)
+
+	appCompact, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	if appCompact.PlatformVersion != "machines" {
+		return nil, fmt.Errorf("app %s doesn't run on machines, which cron jobs require", appName)
+	}
+
+	list, err := apiClient.GetCronJobs(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving cron jobs: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, appCompact)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	machines := make(map[string]*api.Machine, len(all))
+	for _, machine := range all {
+		machines[machine.ID] = machine
+	}
+
+	return &jobs{
+		app:         appCompact,
+		flapsClient: flapsClient,
+		list:        list,
+		machines:    machines,
+	}, nil
+}
+
+// get returns the job called name, and its machine, which is nil when it's
+// gone missing.
+func (j *jobs) get(name string) (*api.CronJob, *api.Machine, error) {
+	for i := range j.list {
+		if job := &j.list[i]; job.Name == name {
+			return job, j.machines[job.MachineID], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("app %s has no cron job called %s", j.app.Name, name)
+}
+
+// missingError returns the error of a job whose machine went missing.
+func missingError(job *api.CronJob) error {
+	return fmt.Errorf("the machine of cron job %s is missing; recreate it with fly cron schedule %s", job.Name, job.Name)
+}
@@ -0,0 +1,84 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDestroy() *cobra.Command {
+	const (
+		long  = `Destroy a cron job of an app, along with its machine.`
+		short = "Destroy a cron job"
+		usage = "destroy <name>"
+	)
+
+	cmd := command.New(usage, short, long, runDestroy,
+		requireCron,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"delete", "remove", "rm"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runDestroy(ctx context.Context) error {
+	var (
+		io   = iostreams.FromContext(ctx)
+		name = flag.FirstArg(ctx)
+	)
+
+	j, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, machine, err := j.get(name)
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy cron job %s of %s?", name, j.app.Name); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if machine != nil {
+		if err := j.flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: j.app.Name, ID: machine.ID, Kill: true}); err != nil {
+			return fmt.Errorf("failed destroying machine %s: %w", machine.ID, err)
+		}
+	}
+
+	if err := client.FromContext(ctx).API().DeleteCronJob(ctx, j.app.Name, name); err != nil {
+		return fmt.Errorf("failed deleting cron job %s: %w", name, err)
+	}
+
+	fmt.Fprintf(io.Out, "Destroyed cron job %s\n", name)
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// macros are the shorthands cron expressions may be given as.
+var macros = map[string]bool{
+	"@hourly":  true,
+	"@daily":   true,
+	"@weekly":  true,
+	"@monthly": true,
+	"@yearly":  true,
+}
+
+// fields are the fields of cron expressions, in order, along with the values
+// they range over. Both 0 and 7 stand for Sunday.
+var fields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// validateExpression returns an error when expr isn't a cron expression of
+// five fields, like 0 3 * * 1-5, or one of the macros, like @daily.
+func validateExpression(expr string) error {
+	expr = strings.TrimSpace(expr)
+
+	if strings.HasPrefix(expr, "@") {
+		if !macros[expr] {
+			return fmt.Errorf("unknown schedule %s, must be one of @hourly, @daily, @weekly, @monthly or @yearly", expr)
+		}
+
+		return nil
+	}
+
+	values := strings.Fields(expr)
+	if len(values) != len(fields) {
+		return fmt.Errorf("schedule %q must have 5 fields: minute, hour, day of month, month and day of week", expr)
+	}
+
+	for i, value := range values {
+		f := fields[i]
+
+		for _, item := range strings.Split(value, ",") {
+			if err := validateItem(item, f.min, f.max); err != nil {
+				return fmt.Errorf("invalid %s %q: %w", f.name, value, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateItem validates an item of a field, like *, */15, 5 or 1-5/2.
+func validateItem(item string, min, max int) error {
+	rng, step, hasStep := strings.Cut(item, "/")
+
+	if hasStep {
+		n, err := strconv.Atoi(step)
+		if err != nil || n < 1 {
+			return fmt.Errorf("step %q must be a positive number", step)
+		}
+	}
+
+	if rng == "*" {
+		return nil
+	}
+
+	from, to, isRange := strings.Cut(rng, "-")
+
+	low, err := parseValue(from, min, max)
+	if err != nil {
+		return err
+	}
+
+	if !isRange {
+		if hasStep {
+			return fmt.Errorf("steps apply to * or ranges, not to %s", rng)
+		}
+
+		return nil
+	}
+
+	high, err := parseValue(to, min, max)
+	if err != nil {
+		return err
+	}
+
+	if low > high {
+		return fmt.Errorf("range %s ends before it starts", rng)
+	}
+
+	return nil
+}
+
+func parseValue(s string, min, max int) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q isn't a number", s)
+	}
+
+	if n < min || n > max {
+		return 0, fmt.Errorf("%d isn't between %d and %d", n, min, max)
+	}
+
+	return n, nil
+}
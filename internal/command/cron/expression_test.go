@@ -0,0 +1,41 @@
+package cron
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExpression(t *testing.T) {
+	valid := []string{
+		"* * * * *",
+		"0 3 * * *",
+		"*/15 * * * *",
+		"0 9-17/2 * * 1-5",
+		"30 4 1,15 * 0",
+		"0 0 * 12 7",
+		"@daily",
+	}
+	for _, expr := range valid {
+		assert.NoError(t, validateExpression(expr), expr)
+	}
+
+	invalid := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"5/2 * * * *",
+		"a * * * *",
+		"@minutely",
+	}
+	for _, expr := range invalid {
+		assert.Error(t, validateExpression(expr), expr)
+	}
+}
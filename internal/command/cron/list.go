@@ -0,0 +1,151 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `List the cron jobs of an app, along with the state of their machines and
+when they last ran. Jobs whose machine went missing are reported, and can be
+recreated with fly cron schedule.`
+		short = "List the cron jobs of an app"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		requireCron,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Format(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+// jobStatus is a cron job along with the state of its machine.
+type jobStatus struct {
+	api.CronJob
+	State    string     `json:"state"`
+	LastRun  *time.Time `json:"lastRun,omitempty"`
+	ExitCode *int       `json:"exitCode,omitempty"`
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+	)
+
+	j, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		statuses = make([]jobStatus, 0, len(j.list))
+		missing  int
+	)
+
+	for _, job := range j.list {
+		status := jobStatus{CronJob: job, State: "missing"}
+
+		if machine := j.machines[job.MachineID]; machine != nil {
+			status.State = machine.State
+			status.LastRun, status.ExitCode = lastRun(machine)
+		} else {
+			missing++
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	if ok, err := render.Structured(ctx, io.Out, statuses); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		state := status.State
+		if state == "missing" {
+			state = colorize.Red(state)
+		}
+
+		var lastRun, exitCode string
+		if status.LastRun != nil {
+			lastRun = format.RelativeTime(*status.LastRun)
+		}
+		if status.ExitCode != nil {
+			exitCode = strconv.Itoa(*status.ExitCode)
+		}
+
+		rows = append(rows, []string{
+			status.Name,
+			status.Schedule,
+			status.Command,
+			status.Size,
+			status.MachineID,
+			state,
+			lastRun,
+			exitCode,
+		})
+	}
+
+	if err := render.Table(io.Out, "", rows, "Name", "Schedule", "Command", "Size", "Machine", "State", "Last Run", "Exit Code"); err != nil {
+		return err
+	}
+
+	if missing > 0 {
+		fmt.Fprintf(io.ErrOut, "%s %d jobs won't run since their machine is missing; recreate them with fly cron schedule <name>\n", colorize.Yellow("Warning:"), missing)
+	}
+
+	return nil
+}
+
+// lastRun returns when machine last exited, and with which exit code, from
+// its events.
+func lastRun(machine *api.Machine) (*time.Time, *int) {
+	var latest *api.MachineEvent
+	for _, event := range machine.Events {
+		if event.Type != "exit" {
+			continue
+		}
+
+		if latest == nil || event.Timestamp > latest.Timestamp {
+			latest = event
+		}
+	}
+
+	if latest == nil {
+		return nil, nil
+	}
+
+	at := time.UnixMilli(latest.Timestamp)
+
+	if latest.Request == nil || latest.Request.ExitEvent == nil {
+		return &at, nil
+	}
+
+	code := int(latest.Request.ExitEvent.ExitCode)
+
+	return &at, &code
+}
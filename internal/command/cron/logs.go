@@ -0,0 +1,97 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/logs"
+)
+
+func newLogs() *cobra.Command {
+	const (
+		long  = `Tail the logs of a cron job, as written by its machine, until interrupted.`
+		short = "Tail the logs of a cron job"
+		usage = "logs <name>"
+	)
+
+	cmd := command.New(usage, short, long, runLogs,
+		requireCron,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runLogs(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		jsonOut   = config.FromContext(ctx).JSONOutput
+	)
+
+	j, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	job, machine, err := j.get(flag.FirstArg(ctx))
+	switch {
+	case err != nil:
+		return err
+	case machine == nil:
+		return missingError(job)
+	}
+
+	opts := &logs.LogOptions{
+		AppName: j.app.Name,
+		VMID:    machine.ID,
+	}
+
+	entries := make(chan logs.LogEntry)
+
+	eg, ctx := errgroup.WithContext(ctx)
+
+	eg.Go(func() error {
+		defer close(entries)
+
+		return logs.Poll(ctx, entries, apiClient, opts)
+	})
+
+	eg.Go(func() error {
+		for entry := range entries {
+			if jsonOut {
+				if err := render.JSON(io.Out, entry); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			fmt.Fprintf(io.Out, "%s [%s] %s\n", entry.Timestamp, entry.Level, entry.Message)
+		}
+
+		return nil
+	})
+
+	if err := eg.Wait(); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRunNow() *cobra.Command {
+	const (
+		long = `Run a cron job now, out of its schedule, by starting its machine. Follow its
+output with fly cron logs.`
+		short = "Run a cron job now"
+		usage = "run-now <name>"
+	)
+
+	cmd := command.New(usage, short, long, runRunNow,
+		requireCron,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runRunNow(ctx context.Context) error {
+	var (
+		io   = iostreams.FromContext(ctx)
+		name = flag.FirstArg(ctx)
+	)
+
+	j, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	job, machine, err := j.get(name)
+	switch {
+	case err != nil:
+		return err
+	case machine == nil:
+		return missingError(job)
+	case machine.State == "started":
+		return fmt.Errorf("cron job %s is already running on machine %s", name, machine.ID)
+	}
+
+	if _, err := j.flapsClient.Start(ctx, machine.ID); err != nil {
+		return fmt.Errorf("failed starting machine %s: %w", machine.ID, err)
+	}
+
+	fmt.Fprintf(io.Out, "Cron job %s is running on machine %s; follow it with fly cron logs %s\n", name, machine.ID, name)
+
+	return nil
+}
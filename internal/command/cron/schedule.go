@@ -0,0 +1,208 @@
+package cron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/shlex"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+const defaultSize = "shared-cpu-1x"
+
+func newSchedule() *cobra.Command {
+	const (
+		long = `Schedule a job of an app to run --command on the cron schedule of --schedule,
+like "0 3 * * *" for every day at 03:00 UTC, on a scheduled machine of --size.
+
+The machine runs the image of --image, which defaults to the image the app's
+machines run, with the app's secrets. Scheduling a job that already exists
+replaces its machine; flags that aren't given keep their values, so running
+fly cron schedule <name> alone recreates a job whose machine went missing.`
+		short = "Schedule a cron job"
+		usage = "schedule <name>"
+	)
+
+	cmd := command.New(usage, short, long, runSchedule,
+		requireCron,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Region(),
+		flag.String{
+			Name:        "schedule",
+			Description: `Cron expression of the schedule, like "0 3 * * *" or @hourly`,
+		},
+		flag.String{
+			Name:        "command",
+			Description: "Command the job runs",
+		},
+		flag.String{
+			Name:        "size",
+			Description: "Size of the machine running the job, like shared-cpu-1x",
+		},
+		flag.String{
+			Name:        "image",
+			Description: "Image the job runs, defaults to the image of the app's machines",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runSchedule(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		name      = flag.FirstArg(ctx)
+	)
+
+	j, err := loadJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	input := api.SetCronJobInput{
+		AppID: j.app.Name,
+		Name:  name,
+		Size:  defaultSize,
+	}
+
+	existing, oldMachine, _ := j.get(name)
+	if existing != nil {
+		input.Schedule = existing.Schedule
+		input.Command = existing.Command
+		input.Size = existing.Size
+		input.Image = existing.Image
+		input.Region = existing.Region
+	}
+
+	for flagName, value := range map[string]*string{
+		"schedule": &input.Schedule,
+		"command":  &input.Command,
+		"size":     &input.Size,
+		"image":    &input.Image,
+	} {
+		if v := flag.GetString(ctx, flagName); v != "" {
+			*value = v
+		}
+	}
+	if region := config.FromContext(ctx).Region; region != "" {
+		input.Region = region
+	}
+
+	switch {
+	case input.Schedule == "":
+		return errors.New("--schedule must be given for new jobs")
+	case input.Command == "":
+		return errors.New("--command must be given for new jobs")
+	}
+
+	if err := validateExpression(input.Schedule); err != nil {
+		return err
+	}
+
+	cmd, err := shlex.Split(input.Command)
+	if err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	guest, ok := api.MachinePresets[input.Size]
+	if !ok {
+		return fmt.Errorf("unknown machine size %s, must be one of %s", input.Size, strings.Join(sizes(), ", "))
+	}
+
+	if input.Image == "" {
+		if input.Image = j.appImage(); input.Image == "" {
+			return errors.New("the app has no machines to take the image of, so --image must be given")
+		}
+	}
+
+	launchInput := api.LaunchMachineInput{
+		AppID:  j.app.Name,
+		Region: input.Region,
+		Config: &api.MachineConfig{
+			Image:    input.Image,
+			Guest:    guest,
+			Schedule: input.Schedule,
+			Init:     api.MachineInit{Cmd: cmd},
+			Restart:  api.MachineRestart{Policy: api.MachineRestartPolicyNo},
+			Metadata: map[string]string{metadataKey: name},
+		},
+	}
+
+	machine, err := j.flapsClient.Launch(ctx, launchInput)
+	if err != nil {
+		return fmt.Errorf("failed launching the machine of %s: %w", name, err)
+	}
+	input.MachineID = machine.ID
+	input.Region = machine.Region
+
+	job, err := apiClient.SetCronJob(ctx, input)
+	if err != nil {
+		// the job isn't recorded, so its machine would run untracked
+		_ = j.flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: j.app.Name, ID: machine.ID, Kill: true})
+
+		return fmt.Errorf("failed recording cron job %s: %w", name, err)
+	}
+
+	if oldMachine != nil {
+		if err := j.flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: j.app.Name, ID: oldMachine.ID, Kill: true}); err != nil {
+			fmt.Fprintf(io.ErrOut, "failed destroying machine %s, which ran %s before: %v\n", oldMachine.ID, name, err)
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, job)
+	}
+
+	fmt.Fprintf(io.Out, "Cron job %s runs %q on %s, on machine %s\n", colorize.Green(name), job.Command, job.Schedule, job.MachineID)
+
+	return nil
+}
+
+// appImage returns the image of the most recently updated machine of the app
+// that doesn't run a cron job, or an empty string when it has none.
+func (j *jobs) appImage() (image string) {
+	var updatedAt string
+	for _, machine := range j.machines {
+		if machine.Config == nil || machine.Config.Metadata[metadataKey] != "" {
+			continue
+		}
+
+		if machine.Config.Image != "" && (image == "" || machine.UpdatedAt > updatedAt) {
+			image, updatedAt = machine.Config.Image, machine.UpdatedAt
+		}
+	}
+
+	return
+}
+
+func sizes() []string {
+	var names []string
+	for name := range api.MachinePresets {
+		if strings.HasPrefix(name, "shared") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
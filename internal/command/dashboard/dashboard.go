@@ -0,0 +1,99 @@
+// Package dashboard implements the dashboard command.
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/skratchdot/open-golang/open"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Open web browser on Fly Web UI for this application.
+
+With --tui, show a full-screen terminal dashboard of the apps of an
+organization instead. Select an app to see its machines, their checks and the
+app's recent releases, and use the keys listed at the bottom of the screen to
+restart machines and tail logs.
+`
+		short = "Open web browser on Fly Web UI for this app"
+	)
+
+	cmd := command.New("dashboard", short, long, run,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Aliases = []string{"dash"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.Bool{
+			Name:        "tui",
+			Description: "Show a terminal dashboard of the apps of an organization",
+		},
+	)
+
+	cmd.AddCommand(newMetrics())
+
+	return cmd
+}
+
+func newMetrics() *cobra.Command {
+	const (
+		long  = `Open web browser on Fly Web UI for this application's metrics`
+		short = "Open web browser on Fly Web UI for this app's metrics"
+	)
+
+	cmd := command.New("metrics", short, long, runMetrics,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	return cmd
+}
+
+func run(ctx context.Context) error {
+	if flag.GetBool(ctx, "tui") {
+		if !iostreams.FromContext(ctx).IsInteractive() {
+			return errors.New("--tui requires a terminal")
+		}
+
+		return runTUI(ctx)
+	}
+
+	ctx, err := command.RequireAppName(ctx)
+	if err != nil {
+		return err
+	}
+
+	return openURL(ctx, "https://fly.io/apps/"+app.NameFromContext(ctx))
+}
+
+func runMetrics(ctx context.Context) error {
+	return openURL(ctx, "https://fly.io/apps/"+app.NameFromContext(ctx)+"/metrics")
+}
+
+func openURL(ctx context.Context, url string) error {
+	fmt.Fprintln(iostreams.FromContext(ctx).Out, "Opening", url)
+
+	return open.Run(url)
+}
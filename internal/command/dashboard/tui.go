@@ -0,0 +1,605 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/logs"
+)
+
+// refreshInterval is how often the selected app is refreshed.
+const refreshInterval = 5 * time.Second
+
+// releaseCount is how many of the latest releases of the selected app are
+// shown.
+const releaseCount = 10
+
+// logLines is how many lines of logs are kept.
+const logLines = 1000
+
+const keys = "[yellow]enter[-] select app  [yellow]tab[-] next pane  [yellow]r[-] restart machine  " +
+	"[yellow]l[-] tail logs  [yellow]R[-] refresh  [yellow]q[-] quit"
+
+// tui is the terminal dashboard of the apps of an organization.
+//
+// Its fields past flaps are only touched by the UI goroutine. Everything
+// which calls the API runs in goroutines of its own, and applies its results
+// via QueueUpdateDraw, after checking they're still wanted.
+type tui struct {
+	ctx    context.Context
+	client *api.Client
+	org    *api.Organization
+
+	mu    sync.Mutex
+	flaps map[string]*flaps.Client
+
+	app      *tview.Application
+	pages    *tview.Pages
+	apps     *tview.Table
+	machines *tview.Table
+	checks   *tview.Table
+	releases *tview.Table
+	logs     *tview.TextView
+	footer   *tview.TextView
+	panes    []tview.Primitive
+
+	// selected is the name of the app whose details are shown.
+	selected string
+	// tailing names what logs are tailed, and stopTailing stops tailing them.
+	tailing     string
+	stopTailing context.CancelFunc
+}
+
+func runTUI(ctx context.Context) error {
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	t := newTUI(ctx, client.FromContext(ctx).API(), org)
+
+	go t.loadApps()
+	go t.refresh()
+
+	return t.app.Run()
+}
+
+func newTUI(ctx context.Context, client *api.Client, org *api.Organization) *tui {
+	t := &tui{
+		ctx:      ctx,
+		client:   client,
+		org:      org,
+		flaps:    map[string]*flaps.Client{},
+		app:      tview.NewApplication(),
+		pages:    tview.NewPages(),
+		apps:     newTable("Apps of " + org.Slug),
+		machines: newTable("Machines"),
+		checks:   newTable("Checks"),
+		releases: newTable("Releases"),
+		logs:     tview.NewTextView(),
+		footer:   tview.NewTextView(),
+	}
+
+	t.logs.SetDynamicColors(true).
+		SetMaxLines(logLines).
+		SetChangedFunc(func() { t.logs.ScrollToEnd() }).
+		SetBorder(true).
+		SetTitle(" Logs ")
+
+	t.footer.SetDynamicColors(true).SetText(keys)
+
+	t.apps.SetSelectedFunc(func(row, _ int) {
+		if name, ok := t.apps.GetCell(row, 0).GetReference().(string); ok {
+			t.selectApp(name)
+		}
+	})
+
+	t.machines.SetSelectionChangedFunc(func(int, int) {
+		t.showChecks()
+	})
+
+	details := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(t.machines, 0, 2, false).
+		AddItem(tview.NewFlex().
+			AddItem(t.checks, 0, 1, false).
+			AddItem(t.releases, 0, 1, false), 0, 2, false).
+		AddItem(t.logs, 0, 3, false)
+
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tview.NewFlex().
+			AddItem(t.apps, 0, 1, true).
+			AddItem(details, 0, 3, false), 0, 1, true).
+		AddItem(t.footer, 1, 0, false)
+
+	t.panes = []tview.Primitive{t.apps, t.machines, t.checks, t.releases, t.logs}
+
+	t.pages.AddPage("main", layout, true, true)
+
+	t.app.SetRoot(t.pages, true).SetInputCapture(t.handleKey)
+
+	return t
+}
+
+func newTable(title string) *tview.Table {
+	table := tview.NewTable().
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	table.SetBorder(true).SetTitle(" " + title + " ")
+
+	return table
+}
+
+// setRows replaces the rows of table with header and rows, referencing the
+// values of refs by row, and keeps the selection on the same reference.
+func setRows(table *tview.Table, header []string, rows [][]string, refs []interface{}) {
+	row, _ := table.GetSelection()
+
+	var selected interface{}
+	if cell := table.GetCell(row, 0); row > 0 && cell != nil {
+		selected = cell.GetReference()
+	}
+
+	table.Clear()
+
+	for col, name := range header {
+		table.SetCell(0, col, tview.NewTableCell(name).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	newRow := 1
+	for i, r := range rows {
+		for col, value := range r {
+			cell := tview.NewTableCell(tview.Escape(value)).SetExpansion(1)
+			if refs != nil {
+				cell.SetReference(refs[i])
+			}
+
+			table.SetCell(i+1, col, cell)
+		}
+
+		if refs != nil && selected != nil && sameReference(refs[i], selected) {
+			newRow = i + 1
+		}
+	}
+
+	table.Select(newRow, 0)
+}
+
+func sameReference(a, b interface{}) bool {
+	if m, ok := a.(*api.Machine); ok {
+		other, ok := b.(*api.Machine)
+
+		return ok && m.ID == other.ID
+	}
+
+	return a == b
+}
+
+func (t *tui) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	// leave the keys of the confirmation dialog alone
+	if name, _ := t.pages.GetFrontPage(); name != "main" {
+		return event
+	}
+
+	switch event.Key() {
+	case tcell.KeyTab:
+		t.cycleFocus(1)
+
+		return nil
+	case tcell.KeyBacktab:
+		t.cycleFocus(-1)
+
+		return nil
+	case tcell.KeyRune:
+		break
+	default:
+		return event
+	}
+
+	switch event.Rune() {
+	case 'q':
+		t.app.Stop()
+	case 'r':
+		t.confirmRestart()
+	case 'l':
+		t.toggleLogs()
+	case 'R':
+		go t.loadApps()
+
+		if t.selected != "" {
+			go t.loadApp(t.selected)
+		}
+	default:
+		return event
+	}
+
+	return nil
+}
+
+func (t *tui) cycleFocus(step int) {
+	focused := t.app.GetFocus()
+
+	for i, pane := range t.panes {
+		if pane == focused {
+			next := (i + step + len(t.panes)) % len(t.panes)
+			t.app.SetFocus(t.panes[next])
+
+			return
+		}
+	}
+
+	t.app.SetFocus(t.panes[0])
+}
+
+// flash shows msg in the footer for a few seconds.
+func (t *tui) flash(msg string) {
+	t.footer.SetText(tview.Escape(msg))
+
+	time.AfterFunc(5*time.Second, func() {
+		t.app.QueueUpdateDraw(func() {
+			t.footer.SetText(keys)
+		})
+	})
+}
+
+// fail flashes err from a goroutine other than the UI's.
+func (t *tui) fail(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		t.flash("[red]" + err.Error())
+	})
+}
+
+// refresh reloads the selected app every refreshInterval.
+func (t *tui) refresh() {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-t.ctx.Done():
+			return
+		case <-ticker.C:
+			t.app.QueueUpdate(func() {
+				if t.selected != "" {
+					go t.loadApp(t.selected)
+				}
+			})
+		}
+	}
+}
+
+func (t *tui) loadApps() {
+	apps, err := t.client.GetApps(t.ctx, nil)
+	if err != nil {
+		t.fail(fmt.Errorf("failed listing apps: %w", err))
+
+		return
+	}
+
+	var (
+		rows [][]string
+		refs []interface{}
+	)
+
+	sort.Slice(apps, func(i, j int) bool { return apps[i].Name < apps[j].Name })
+
+	for _, app := range apps {
+		if app.Organization.Slug != t.org.Slug {
+			continue
+		}
+
+		rows = append(rows, []string{app.Name, app.Status, app.PlatformVersion})
+		refs = append(refs, app.Name)
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		setRows(t.apps, []string{"Name", "Status", "Platform"}, rows, refs)
+	})
+}
+
+func (t *tui) selectApp(name string) {
+	t.selected = name
+
+	t.machines.Clear().SetTitle(" Machines of " + name + " ")
+	t.checks.Clear()
+	t.releases.Clear().SetTitle(" Releases of " + name + " ")
+
+	t.app.SetFocus(t.machines)
+
+	go t.loadApp(name)
+}
+
+// loadApp fetches the machines and releases of the app called name.
+func (t *tui) loadApp(name string) {
+	app, err := t.client.GetAppCompact(t.ctx, name)
+	if err != nil {
+		t.fail(fmt.Errorf("failed retrieving app %s: %w", name, err))
+
+		return
+	}
+
+	releases, err := t.client.GetAppReleases(t.ctx, name, releaseCount)
+	if err != nil {
+		t.fail(fmt.Errorf("failed retrieving releases of %s: %w", name, err))
+
+		return
+	}
+
+	var machines []*api.Machine
+	if app.PlatformVersion == "machines" {
+		flapsClient, err := t.flapsClient(app)
+		if err != nil {
+			t.fail(err)
+
+			return
+		}
+
+		if machines, err = flapsClient.ListActive(t.ctx); err != nil {
+			t.fail(fmt.Errorf("failed listing machines of %s: %w", name, err))
+
+			return
+		}
+
+		sort.Slice(machines, func(i, j int) bool { return machines[i].ID < machines[j].ID })
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		if t.selected != name {
+			return
+		}
+
+		t.showMachines(app, machines)
+		t.showReleases(releases)
+		t.showChecks()
+	})
+}
+
+func (t *tui) flapsClient(app *api.AppCompact) (*flaps.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if c := t.flaps[app.Name]; c != nil {
+		return c, nil
+	}
+
+	c, err := flaps.New(t.ctx, app)
+	if err != nil {
+		return nil, fmt.Errorf("failed creating flaps client for %s: %w", app.Name, err)
+	}
+
+	t.flaps[app.Name] = c
+
+	return c, nil
+}
+
+func (t *tui) showMachines(app *api.AppCompact, machines []*api.Machine) {
+	if app.PlatformVersion != "machines" {
+		t.machines.Clear().SetCell(0, 0, tview.NewTableCell("Only apps on machines have their machines listed").
+			SetSelectable(false))
+
+		return
+	}
+
+	rows := make([][]string, 0, len(machines))
+	refs := make([]interface{}, 0, len(machines))
+
+	for _, m := range machines {
+		passing := 0
+		for _, c := range m.Checks {
+			if c.Status == "passing" {
+				passing++
+			}
+		}
+
+		rows = append(rows, []string{
+			m.ID,
+			m.Name,
+			m.State,
+			m.Region,
+			fmt.Sprintf("%d/%d", passing, len(m.Checks)),
+			m.FullImageRef(),
+			m.UpdatedAt,
+		})
+		refs = append(refs, m)
+	}
+
+	setRows(t.machines, []string{"ID", "Name", "State", "Region", "Checks", "Image", "Updated"}, rows, refs)
+}
+
+// selectedMachine returns the machine selected in the machines pane, if any.
+func (t *tui) selectedMachine() *api.Machine {
+	row, _ := t.machines.GetSelection()
+	if cell := t.machines.GetCell(row, 0); cell != nil {
+		if m, ok := cell.GetReference().(*api.Machine); ok {
+			return m
+		}
+	}
+
+	return nil
+}
+
+func (t *tui) showChecks() {
+	m := t.selectedMachine()
+	if m == nil {
+		t.checks.Clear().SetTitle(" Checks ")
+
+		return
+	}
+
+	t.checks.SetTitle(" Checks of " + m.ID + " ")
+
+	rows := make([][]string, 0, len(m.Checks))
+	for _, c := range m.Checks {
+		updated := ""
+		if c.UpdatedAt != nil {
+			updated = format.RelativeTime(*c.UpdatedAt)
+		}
+
+		rows = append(rows, []string{c.Name, c.Status, updated, c.Output})
+	}
+
+	setRows(t.checks, []string{"Name", "Status", "Updated", "Output"}, rows, nil)
+}
+
+func (t *tui) showReleases(releases []api.Release) {
+	rows := make([][]string, 0, len(releases))
+	for _, r := range releases {
+		rows = append(rows, []string{
+			fmt.Sprintf("v%d", r.Version),
+			r.Status,
+			r.Description,
+			r.User.Email,
+			format.RelativeTime(r.CreatedAt),
+		})
+	}
+
+	setRows(t.releases, []string{"Version", "Status", "Description", "User", "Date"}, rows, nil)
+}
+
+// confirmRestart asks whether to restart the selected machine, and restarts
+// it when confirmed.
+func (t *tui) confirmRestart() {
+	m := t.selectedMachine()
+	if m == nil || t.selected == "" {
+		t.flash("Select a machine to restart first")
+
+		return
+	}
+
+	appName := t.selected
+	focused := t.app.GetFocus()
+
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Restart machine %s of %s?", m.ID, appName)).
+		AddButtons([]string{"Restart", "Cancel"}).
+		SetDoneFunc(func(_ int, label string) {
+			t.pages.RemovePage("confirm")
+			t.app.SetFocus(focused)
+
+			if label != "Restart" {
+				return
+			}
+
+			t.flash("Restarting machine " + m.ID)
+
+			go t.restart(appName, m.ID)
+		})
+
+	t.pages.AddPage("confirm", modal, false, true)
+	t.app.SetFocus(modal)
+}
+
+func (t *tui) restart(appName, machineID string) {
+	t.mu.Lock()
+	flapsClient := t.flaps[appName]
+	t.mu.Unlock()
+
+	if flapsClient == nil {
+		t.fail(fmt.Errorf("app %s has no flaps client", appName))
+
+		return
+	}
+
+	if err := flapsClient.Restart(t.ctx, api.RestartMachineInput{ID: machineID}); err != nil {
+		t.fail(fmt.Errorf("failed restarting machine %s: %w", machineID, err))
+
+		return
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		t.flash("Restarted machine " + machineID)
+	})
+
+	t.loadApp(appName)
+}
+
+// toggleLogs tails the logs of the machine selected in the machines pane when
+// it's focused, or of the selected app otherwise. Tailing the same logs again
+// stops tailing them.
+func (t *tui) toggleLogs() {
+	if t.selected == "" {
+		t.flash("Select an app to tail the logs of first")
+
+		return
+	}
+
+	opts := &logs.LogOptions{AppName: t.selected}
+	target := "app " + t.selected
+
+	if t.app.GetFocus() == t.machines {
+		if m := t.selectedMachine(); m != nil {
+			opts.VMID = m.ID
+			target = "machine " + m.ID
+		}
+	}
+
+	if t.stopTailing != nil {
+		t.stopTailing()
+		t.stopTailing = nil
+
+		if t.tailing == target {
+			t.logs.SetTitle(" Logs ")
+			t.tailing = ""
+
+			return
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.ctx)
+	t.stopTailing = cancel
+	t.tailing = target
+
+	t.logs.Clear().SetTitle(" Logs of " + target + " ")
+
+	go t.tail(ctx, opts)
+}
+
+func (t *tui) tail(ctx context.Context, opts *logs.LogOptions) {
+	entries := make(chan logs.LogEntry)
+
+	go func() {
+		defer close(entries)
+
+		if err := logs.Poll(ctx, entries, t.client, opts); err != nil && ctx.Err() == nil {
+			t.fail(fmt.Errorf("failed tailing logs: %w", err))
+		}
+	}()
+
+	w := tview.ANSIWriter(t.logs)
+
+	for entry := range entries {
+		entry := entry
+
+		t.app.QueueUpdateDraw(func() {
+			if ctx.Err() != nil {
+				return
+			}
+
+			_ = render.LogEntry(w, entry, render.HideAllocID(), render.RemoveNewlines())
+		})
+	}
+}
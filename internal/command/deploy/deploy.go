@@ -37,7 +37,7 @@ func New() (cmd *cobra.Command) {
 		short = "Deploy Fly applications"
 	)
 
-	cmd = command.New("deploy [WORKING_DIRECTORY]", short, long, run,
+	cmd = command.New("deploy [WORKING_DIRECTORY]", short, long, Run,
 		command.RequireSession,
 		command.ChangeWorkingDirectoryToFirstArgIfPresent,
 		command.RequireAppName,
@@ -48,37 +48,45 @@ func New() (cmd *cobra.Command) {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
-		flag.Region(),
 		flag.Image(),
 		flag.Now(),
-		flag.RemoteOnly(false),
-		flag.LocalOnly(),
-		flag.Nixpacks(),
-		flag.BuildOnly(),
-		flag.Push(),
-		flag.Detach(),
-		flag.Strategy(),
-		flag.Dockerfile(),
-		flag.StringSlice{
-			Name:        "env",
-			Shorthand:   "e",
-			Description: "Set of environment variables in the form of NAME=VALUE pairs. Can be specified multiple times.",
-		},
-		flag.ImageLabel(),
-		flag.BuildArg(),
-		flag.BuildSecret(),
-		flag.BuildTarget(),
-		flag.NoCache(),
-		flag.Bool{
-			Name:        "auto-confirm",
-			Description: "Will automatically confirm changes without an interactive prompt.",
-		},
+		CommonFlags,
 	)
 
 	return
 }
 
-func run(ctx context.Context) error {
+// CommonFlags are the flags DeployWithConfig reads, which commands calling it
+// must register.
+var CommonFlags = flag.Set{
+	flag.Region(),
+	flag.RemoteOnly(false),
+	flag.LocalOnly(),
+	flag.Nixpacks(),
+	flag.BuildOnly(),
+	flag.Push(),
+	flag.Detach(),
+	flag.Strategy(),
+	flag.Dockerfile(),
+	flag.StringSlice{
+		Name:        "env",
+		Shorthand:   "e",
+		Description: "Set of environment variables in the form of NAME=VALUE pairs. Can be specified multiple times.",
+	},
+	flag.ImageLabel(),
+	flag.BuildArg(),
+	flag.BuildSecret(),
+	flag.BuildTarget(),
+	flag.NoCache(),
+	flag.Bool{
+		Name:        "auto-confirm",
+		Description: "Will automatically confirm changes without an interactive prompt.",
+	},
+}
+
+// Run deploys the app ctx names using the config ctx carries or, in its
+// absence, the config of the app's current release.
+func Run(ctx context.Context) error {
 	appConfig, err := determineAppConfig(ctx)
 	if err != nil {
 		return err
@@ -90,6 +98,15 @@ func run(ctx context.Context) error {
 func DeployWithConfig(ctx context.Context, appConfig *app.Config) (err error) {
 	apiClient := client.FromContext(ctx).API()
 
+	appName := app.NameFromContext(ctx)
+	if appName == "" {
+		appName = appConfig.AppName
+	}
+
+	if err := CheckRequiredSecrets(ctx, appName, appConfig); err != nil {
+		return err
+	}
+
 	// Fetch an image ref or build from source to get the final image reference to deploy
 	img, err := determineImage(ctx, appConfig)
 	if err != nil {
@@ -126,7 +143,13 @@ func DeployWithConfig(ctx context.Context, appConfig *app.Config) (err error) {
 			}
 		}
 
-		return createMachinesRelease(ctx, appConfig, img, flag.GetString(ctx, "strategy"))
+		if err := createMachinesRelease(ctx, appConfig, img, flag.GetString(ctx, "strategy")); err != nil {
+			return err
+		}
+
+		trackRelease(ctx, appName, img)
+
+		return nil
 	}
 
 	release, releaseCommand, err = createRelease(ctx, appConfig, img)
@@ -134,6 +157,8 @@ func DeployWithConfig(ctx context.Context, appConfig *app.Config) (err error) {
 		return err
 	}
 
+	trackRelease(ctx, appName, img)
+
 	if flag.GetDetach(ctx) {
 		return nil
 	}
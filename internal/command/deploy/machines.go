@@ -7,12 +7,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/samber/lo"
+
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/build/imgsrc"
 	"github.com/superfly/flyctl/internal/spinner"
+	"github.com/superfly/flyctl/internal/stagedsecrets"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -47,9 +50,12 @@ func createMachinesRelease(ctx context.Context, config *app.Config, img *imgsrc.
 		}
 
 		httpService := api.MachineService{
-			Protocol:     "tcp",
-			InternalPort: config.HttpService.InternalPort,
-			Concurrency:  concurrency,
+			Protocol:           "tcp",
+			InternalPort:       config.HttpService.InternalPort,
+			Concurrency:        concurrency,
+			Autostop:           config.HttpService.AutoStopMachines,
+			Autostart:          config.HttpService.AutoStartMachines,
+			MinMachinesRunning: config.HttpService.MinMachinesRunning,
 			Ports: []api.MachinePort{
 				{
 					Port:       80,
@@ -83,6 +89,10 @@ func createMachinesRelease(ctx context.Context, config *app.Config, img *imgsrc.
 		machineConfig.Checks = config.Checks
 	}
 
+	// machines the deployment launches are given the guest of the [vm] table,
+	// while existing ones keep theirs
+	machineConfig.Guest = config.Guest()
+
 	// Run validations against struct types and their JSON tags
 	err = config.Validate()
 
@@ -90,11 +100,15 @@ func createMachinesRelease(ctx context.Context, config *app.Config, img *imgsrc.
 		return err
 	}
 
+	if err := preflightMachinesDeploy(ctx, app, config); err != nil {
+		return err
+	}
+
 	if err := RunReleaseCommand(ctx, app, config, machineConfig); err != nil {
 		return fmt.Errorf("release command failed - aborting deployment. %w", err)
 	}
 
-	return DeployMachinesApp(ctx, app, strategy, machineConfig, config)
+	return DeployMachinesApp(ctx, app, strategy, machineConfig, config, "")
 }
 
 func RunReleaseCommand(ctx context.Context, app *api.AppCompact, appConfig *app.Config, machineConfig api.MachineConfig) (err error) {
@@ -113,7 +127,7 @@ func RunReleaseCommand(ctx context.Context, app *api.AppCompact, appConfig *app.
 	machineConf := machineConfig
 
 	machineConf.Metadata = map[string]string{
-		"process_group": "release_command",
+		api.MachineProcessGroupKey: "release_command",
 	}
 	// Override the machine default command to run the release command
 	machineConf.Init.Cmd = strings.Split(appConfig.Deploy.ReleaseCommand, " ")
@@ -198,7 +212,9 @@ func RunReleaseCommand(ctx context.Context, app *api.AppCompact, appConfig *app.
 	return
 }
 
-func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string, machineConfig api.MachineConfig, appConfig *app.Config) (err error) {
+// DeployMachinesApp applies machineConfig to the active machines of app. When processGroup is
+// set, only the machines belonging to that process group are updated.
+func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string, machineConfig api.MachineConfig, appConfig *app.Config, processGroup string) (err error) {
 	io := iostreams.FromContext(ctx)
 	flapsClient, err := flaps.New(ctx, app)
 	if err != nil {
@@ -219,7 +235,7 @@ func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string
 	spin := spinner.Run(io, msg)
 	defer spin.StopWithSuccess()
 
-	machineConfig.Metadata = map[string]string{"process_group": "app"}
+	machineConfig.Metadata = map[string]string{api.MachineProcessGroupKey: "app"}
 	machineConfig.Init.Cmd = nil
 
 	launchInput := api.LaunchMachineInput{
@@ -234,6 +250,16 @@ func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string
 		return
 	}
 
+	if processGroup != "" {
+		machines = lo.Filter(machines, func(m *api.Machine, _ int) bool {
+			return m.Config.Metadata[api.MachineProcessGroupKey] == processGroup
+		})
+
+		if len(machines) == 0 {
+			return fmt.Errorf("no machines found in process group %s", processGroup)
+		}
+	}
+
 	if len(machines) > 0 {
 
 		for _, machine := range machines {
@@ -258,6 +284,10 @@ func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string
 
 			launchInput.Region = machine.Region
 
+			if launchInput.Config.Env == nil {
+				launchInput.Config.Env = map[string]string{}
+			}
+
 			if launchInput.Config.Env["PRIMARY_REGION"] == "" {
 				launchInput.Config.Env["PRIMARY_REGION"] = machine.Config.Env["PRIMARY_REGION"]
 			}
@@ -274,13 +304,17 @@ func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string
 				launchInput.Config.Mounts = machine.Config.Mounts
 			}
 
+			// Likewise keep the files secrets set with fly secrets set --file
+			// are exposed as
+			launchInput.Config.Files = machine.Config.Files
+
 			updateResult, err := flapsClient.Update(ctx, launchInput, machine.LeaseNonce)
 			if err != nil {
 				if strategy != "immediate" {
 					return err
 
 				} else {
-					fmt.Printf("Continuing after error: %s\n", err)
+					fmt.Fprintf(io.ErrOut, "Continuing after error: %s\n", err)
 				}
 			}
 
@@ -300,6 +334,14 @@ func DeployMachinesApp(ctx context.Context, app *api.AppCompact, strategy string
 		}
 	}
 
+	// every machine now has the secrets staged for the app, which only a
+	// deploy of some process group leaves staged for the others
+	if processGroup == "" {
+		if err := stagedsecrets.Clear(ctx, app.Name); err != nil {
+			fmt.Fprintf(io.ErrOut, "Failed clearing the staged secrets of %s: %v\n", app.Name, err)
+		}
+	}
+
 	return
 }
 
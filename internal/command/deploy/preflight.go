@@ -0,0 +1,144 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/statuspage"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// preflightMachinesDeploy verifies, before anything is mutated, that the
+// organization has quota left for the machines the deployment launches and
+// that the target regions have capacity for the requested machine sizes.
+// All problems found are reported together. Checks the API doesn't serve yet
+// are skipped, and those which fail to run are only warned about, so that
+// they never stop a deployment on their own.
+func preflightMachinesDeploy(ctx context.Context, app *api.AppCompact, appConfig *app.Config) error {
+	tb := render.NewTextBlock(ctx, "Running preflight checks")
+	client := client.FromContext(ctx).API()
+	errOut := iostreams.FromContext(ctx).ErrOut
+
+	warn := func(format string, args ...interface{}) {
+		fmt.Fprintf(errOut, "WARNING: "+format+", skipping it\n", args...)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		warn("failed running preflight checks: %v", err)
+		return nil
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		warn("failed running preflight checks: %v", err)
+		return nil
+	}
+
+	// count the machines this deployment launches: the first machine of the
+	// app and the temporary release command one. Deployments update machines
+	// along with their volumes, and allocate no IPs, so they need no more of
+	// either.
+	var requested int
+	if len(machines) == 0 {
+		requested++
+	}
+	if appConfig.Deploy != nil && appConfig.Deploy.ReleaseCommand != "" {
+		requested++
+	}
+
+	// the machine sizes which must fit in each target region
+	guests := map[string]*api.MachineGuest{}
+	for _, machine := range machines {
+		guests[machine.Region] = guestFor(machine.Config)
+	}
+	if len(machines) == 0 && appConfig.PrimaryRegion != "" {
+		guests[appConfig.PrimaryRegion] = api.MachinePresets["shared-cpu-1x"]
+		if guest := appConfig.Guest(); guest != nil {
+			guests[appConfig.PrimaryRegion] = guest
+		}
+	}
+
+	var problems []string
+
+	if requested > 0 && served(ctx, "Organization", "limits") {
+		if limits, err := client.GetOrganizationLimits(ctx, app.Organization.Slug); err != nil {
+			warn("failed checking the machine quota: %v", err)
+		} else {
+			problems = append(problems, quotaProblems(limits, requested)...)
+		}
+	}
+
+	if served(ctx, "FlyPlatform", "regionCapacity") {
+		for region, guest := range guests {
+			available, err := client.PlatformRegionCapacity(ctx, region, guest)
+			if err != nil {
+				warn("failed checking capacity of region %s: %v", region, err)
+				continue
+			}
+
+			if !available {
+				problem := fmt.Sprintf("region %s is out of capacity for %d %s cpu(s) with %dMB of memory",
+					region, guest.CPUs, guest.CPUKind, guest.MemoryMB)
+				if guest.GPUKind != "" {
+					problem += " and a " + guest.GPUKind + " GPU"
+				}
+				problems = append(problems, problem)
+			}
+		}
+	}
+
+	// incidents don't stop deployments, but explain failures they may cause
+	regions := make([]string, 0, len(guests))
+	for region := range guests {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	statuspage.WarnRegions(ctx, errOut, regions...)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("preflight checks failed, nothing was deployed:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	tb.Done("Preflight checks passed")
+
+	return nil
+}
+
+// served reports whether the API serves name of typeName, which preflight
+// checks need. Checks are skipped when that can't be told.
+func served(ctx context.Context, typeName, name string) bool {
+	ok, err := client.FromContext(ctx).API().SchemaHas(ctx, typeName, name)
+
+	return err == nil && ok
+}
+
+// quotaProblems describes how launching requested more machines exceeds the
+// machine quota of limits, if it does.
+func quotaProblems(limits *api.OrganizationLimits, requested int) []string {
+	if limits == nil || limits.MaxMachines == 0 || limits.Machines+requested <= limits.MaxMachines {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("machine quota exceeded: %d in use, %d requested, limit is %d",
+		limits.Machines, requested, limits.MaxMachines)}
+}
+
+func guestFor(config *api.MachineConfig) *api.MachineGuest {
+	if config.Guest != nil {
+		return config.Guest
+	}
+
+	if guest, ok := api.MachinePresets[config.VMSize]; ok {
+		return guest
+	}
+
+	return api.MachinePresets["shared-cpu-1x"]
+}
@@ -0,0 +1,24 @@
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestQuotaProblems(t *testing.T) {
+	limits := &api.OrganizationLimits{
+		MaxMachines: 10,
+		Machines:    9,
+	}
+
+	assert.Empty(t, quotaProblems(nil, 1))
+	assert.Empty(t, quotaProblems(limits, 1))
+
+	assert.Equal(t, []string{"machine quota exceeded: 9 in use, 2 requested, limit is 10"},
+		quotaProblems(limits, 2))
+
+	assert.Empty(t, quotaProblems(&api.OrganizationLimits{Machines: 50}, 3))
+}
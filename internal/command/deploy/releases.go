@@ -0,0 +1,80 @@
+package deploy
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/build/imgsrc"
+	"github.com/superfly/flyctl/internal/logger"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// trackRelease creates a release of the Sentry project of the app, when it
+// tracks releases, named after the commit being deployed or, outside of git
+// repositories, the image. Apps without a Sentry project, which sets their
+// SENTRY_DSN secret, are skipped, as are all apps while the API doesn't track
+// Sentry releases. Failing to doesn't fail the deploy.
+func trackRelease(ctx context.Context, appName string, img *imgsrc.DeploymentImage) {
+	logger := logger.FromContext(ctx)
+	apiClient := client.FromContext(ctx).API()
+
+	if ok, err := apiClient.SchemaHas(ctx, "Mutations", "createSentryRelease"); err != nil || !ok {
+		logger.Debugf("not tracking Sentry releases, which the API doesn't serve: %v", err)
+		return
+	}
+
+	secrets, err := apiClient.GetAppSecrets(ctx, appName)
+	if err != nil {
+		logger.Debugf("failed fetching secrets of %s: %v", appName, err)
+		return
+	}
+	if !hasSentry(secrets) {
+		return
+	}
+
+	commitSHA := headCommit(ctx, state.WorkingDirectory(ctx))
+
+	version := commitSHA
+	if version == "" {
+		version = img.Tag
+		if i := strings.LastIndex(version, ":"); i != -1 {
+			version = version[i+1:]
+		}
+	}
+
+	release, err := apiClient.CreateSentryRelease(ctx, appName, version, commitSHA)
+	switch {
+	case err != nil:
+		logger.Debugf("failed creating Sentry release: %v", err)
+	case release != nil:
+		tb := render.NewTextBlock(ctx)
+		tb.Donef("Sentry release %s created: %s", release.Version, release.URL)
+	}
+}
+
+func hasSentry(secrets []api.Secret) bool {
+	for _, secret := range secrets {
+		if secret.Name == "SENTRY_DSN" {
+			return true
+		}
+	}
+	return false
+}
+
+// headCommit returns the SHA of the commit checked out in dir, or an empty
+// string when dir isn't in a git repository.
+func headCommit(ctx context.Context, dir string) string {
+	cmd := exec.CommandContext(ctx, "git", "rev-parse", "HEAD")
+	cmd.Dir = dir
+
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,39 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+)
+
+// CheckRequiredSecrets verifies that every secret listed under
+// [env.required_secrets] in appConfig has been set on the named app.
+func CheckRequiredSecrets(ctx context.Context, appName string, appConfig *app.Config) error {
+	if len(appConfig.RequiredSecrets) == 0 {
+		return nil
+	}
+
+	secrets, err := client.FromContext(ctx).API().GetAppSecrets(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed fetching secrets: %w", err)
+	}
+
+	missing := appConfig.MissingSecrets(secrets)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	lines := make([]string, 0, len(missing))
+	for _, name := range missing {
+		if desc := appConfig.RequiredSecrets[name]; desc != "" {
+			name = fmt.Sprintf("%s (%s)", name, desc)
+		}
+		lines = append(lines, name)
+	}
+
+	return fmt.Errorf("app %s is missing required secrets:\n  %s\nset them with `fly secrets set NAME=VALUE`",
+		appName, strings.Join(lines, "\n  "))
+}
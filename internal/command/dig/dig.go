@@ -2,7 +2,6 @@
 package dig
 
 import (
-	"bytes"
 	"context"
 	"encoding/binary"
 	"errors"
@@ -10,8 +9,10 @@ import (
 	"net"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/miekg/dns"
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/agent"
@@ -20,7 +21,9 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
 )
 
 var nameErrorRx = regexp.MustCompile(`\[.*?\]:53`)
@@ -30,8 +33,10 @@ func New() *cobra.Command {
 		long = `Make DNS requests against Fly.io's internal DNS server. Valid types include
 AAAA and TXT (the two types our servers answer authoritatively), AAAA-NATIVE
 and TXT-NATIVE, which resolve with Go's resolver (they're slower,
-but may be useful if diagnosing a DNS bug) and A and CNAME
-(if you're using the server to test recursive lookups.)
+but may be useful if diagnosing a DNS bug) and any other type such as
+A, CNAME, MX or SRV (if you're using the server to test recursive lookups.)
+Queries go through the WireGuard tunnel, so .internal names resolve from
+anywhere. Use --watch to follow the propagation of a record.
 Note that this resolves names against the server for the current organization. You can
 set the organization with -o <org-slug>; otherwise, the command uses the organization
 attached to the current app (you can pass an app in with -a <appname>).`
@@ -44,7 +49,7 @@ attached to the current app (you can pass an app in with -a <appname>).`
 		command.LoadAppNameIfPresent,
 	)
 
-	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.Args = cobra.RangeArgs(1, 3)
 
 	flag.Add(cmd,
 		flag.App(),
@@ -54,7 +59,15 @@ attached to the current app (you can pass an app in with -a <appname>).`
 			Name:        "short",
 			Shorthand:   "s",
 			Default:     false,
-			Description: "Just print the answers, not DNS record details",
+			Description: "Just print the answers, not DNS record details. Same as +short",
+		},
+		flag.Bool{
+			Name:        "watch",
+			Description: "Repeat the query every 5 seconds, printing the answers when they change",
+		},
+		flag.String{
+			Name:        "expect",
+			Description: "With --watch, stop once an answer matches this value",
 		},
 	)
 
@@ -69,6 +82,30 @@ func run(ctx context.Context) error {
 		err error
 	)
 
+	var (
+		args  []string
+		short = flag.GetBool(ctx, "short")
+	)
+	for _, arg := range flag.Args(ctx) {
+		if arg == "+short" {
+			short = true
+		} else {
+			args = append(args, arg)
+		}
+	}
+
+	var dtype, name string
+	switch len(args) {
+	case 1:
+		dtype, name = "AAAA", args[0]
+	case 2:
+		dtype, name = strings.ToUpper(args[0]), args[1]
+	default:
+		return fmt.Errorf("expected [type] <name>, got %d arguments", len(args))
+	}
+	// add the trailing dot
+	name = dns.Fqdn(name)
+
 	orgSlug := flag.GetOrg(ctx)
 
 	if orgSlug == "" {
@@ -96,92 +133,176 @@ func run(ctx context.Context) error {
 		return err
 	}
 
-	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(ns, "53"))
-	if err != nil {
-		return err
+	switch dtype {
+	case "AAAA-NATIVE":
+		hosts, err := r.LookupHost(ctx, name)
+		if err != nil {
+			return fixNameError(err, ns)
+		}
+
+		for _, h := range hosts {
+			fmt.Fprintf(io.Out, "%s\n", h)
+		}
+
+		return nil
+
+	case "TXT-NATIVE":
+		txts, err := r.LookupTXT(ctx, name)
+		if err != nil {
+			return fixNameError(err, ns)
+		}
+
+		fmt.Fprintf(io.Out, "%s\n", strings.Join(txts, ""))
+
+		return nil
+	}
+
+	qtype, ok := dns.StringToType[dtype]
+	if !ok {
+		return fmt.Errorf("don't understand DNS type %s", dtype)
 	}
 
 	msg := &dns.Msg{}
+	msg.SetQuestion(name, qtype)
+	msg.RecursionDesired = !strings.HasSuffix(name, ".internal.")
 
-	dtype := "AAAA"
-	name := flag.FirstArg(ctx)
+	query := func() (*dns.Msg, error) {
+		return exchange(ctx, d, ns, msg)
+	}
 
-	if len(flag.Args(ctx)) > 1 {
-		dtype = strings.ToUpper(flag.FirstArg(ctx))
-		name = flag.Args(ctx)[1]
+	if flag.GetBool(ctx, "watch") {
+		return watch(ctx, query, flag.GetString(ctx, "expect"))
 	}
-	// add the trailing dot
-	name = dns.Fqdn(name)
 
-	if strings.HasSuffix(name, ".internal.") {
-		msg.RecursionDesired = false
-	} else {
-		msg.RecursionDesired = true
+	reply, err := query()
+	if err != nil {
+		return err
 	}
 
-	// put this switch block in its own function to reduce the footprint of the main function
-	// e.g: func resolve(ctx context.Context, r *net.Resolver, msg *dns.Msg, name string, dtype string)
-	switch dtype {
-	case "A", "CNAME", "TXT", "AAAA":
-		msg.SetQuestion(name, dns.StringToType[dtype])
+	switch {
+	case config.FromContext(ctx).JSONOutput:
+		return render.JSON(io.Out, answersOf(reply))
+	case short:
+		if reply.MsgHdr.Rcode != dns.RcodeSuccess {
+			return fmt.Errorf("lookup failed: %s", dns.RcodeToString[reply.MsgHdr.Rcode])
+		}
+
+		for _, answer := range answersOf(reply) {
+			fmt.Fprintln(io.Out, answer.Value)
+		}
+	default:
+		fmt.Fprintf(io.Out, "%+v\n", reply)
+	}
+
+	return nil
+}
+
+// Answer is an answer of a DNS reply.
+type Answer struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl"`
+	Value string `json:"value"`
+}
+
+// answersOf returns the answers of a reply, with their values in the
+// presentation format dig +short uses.
+func answersOf(reply *dns.Msg) []Answer {
+	answers := make([]Answer, 0, len(reply.Answer))
+
+	for _, rr := range reply.Answer {
+		hdr := rr.Header()
+
+		value := strings.TrimPrefix(rr.String(), hdr.String())
+		if txt, ok := rr.(*dns.TXT); ok {
+			value = strings.Join(txt.Txt, "")
+		}
+
+		answers = append(answers, Answer{
+			Name:  hdr.Name,
+			Type:  dns.TypeToString[hdr.Rrtype],
+			TTL:   hdr.Ttl,
+			Value: value,
+		})
+	}
 
-		reply, err := roundTrip(conn, msg)
+	return answers
+}
+
+// watch repeats the query, printing the answers whenever they change, until
+// one of them matches expect or the context is canceled.
+func watch(ctx context.Context, query func() (*dns.Msg, error), expect string) error {
+	out := iostreams.FromContext(ctx).Out
+
+	var (
+		last    string
+		printed bool
+	)
+	for {
+		reply, err := query()
 		if err != nil {
 			return err
 		}
 
-		if flag.GetBool(ctx, "short") {
-			if reply.MsgHdr.Rcode != dns.RcodeSuccess {
-				return fmt.Errorf("lookup failed: %s", dns.RcodeToString[reply.MsgHdr.Rcode])
-			}
+		var values []string
+		for _, answer := range answersOf(reply) {
+			values = append(values, answer.Value)
+		}
 
-			switch dtype {
-			case "AAAA":
-				for _, rr := range reply.Answer {
-					if aaaa, ok := rr.(*dns.AAAA); ok {
-						fmt.Fprintf(io.Out, "%s\n", aaaa.AAAA)
-					}
-				}
-			case "TXT":
-				buf := &bytes.Buffer{}
-
-				for _, rr := range reply.Answer {
-					if txt, ok := rr.(*dns.TXT); ok {
-						for _, s := range txt.Txt {
-							buf.WriteString(s)
-						}
-					}
-				}
-
-				fmt.Fprintf(io.Out, "%s\n", buf.String())
+		if current := strings.Join(values, ", "); !printed || current != last {
+			if current == "" {
+				fmt.Fprintf(out, "%s %s\n", time.Now().Format(time.Kitchen), dns.RcodeToString[reply.MsgHdr.Rcode])
+			} else {
+				fmt.Fprintf(out, "%s %s\n", time.Now().Format(time.Kitchen), current)
 			}
-		} else {
-			fmt.Fprintf(io.Out, "%+v\n", reply)
+			last, printed = current, true
 		}
 
-	case "AAAA-NATIVE":
-		hosts, err := r.LookupHost(ctx, name)
-		if err != nil {
-			return fixNameError(err, ns)
+		if expect != "" && lo.Contains(values, expect) {
+			return nil
 		}
 
-		for _, h := range hosts {
-			fmt.Fprintf(io.Out, "%s\n", h)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
 		}
+	}
+}
 
-	case "TXT-NATIVE":
-		txts, err := r.LookupTXT(ctx, name)
-		if err != nil {
-			return fixNameError(err, ns)
-		}
+// Lookup queries the nameserver ns, reached through d, for the records of name
+// of type qtype, like dns.TypeTXT, recursively unless name is a .internal one.
+// Names without records have no answers rather than an error.
+func Lookup(ctx context.Context, d agent.Dialer, ns, name string, qtype uint16) ([]Answer, error) {
+	name = dns.Fqdn(name)
 
-		fmt.Fprintf(io.Out, "%s\n", strings.Join(txts, ""))
+	msg := &dns.Msg{}
+	msg.SetQuestion(name, qtype)
+	msg.RecursionDesired = !strings.HasSuffix(name, ".internal.")
 
+	reply, err := exchange(ctx, d, ns, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch reply.MsgHdr.Rcode {
+	case dns.RcodeSuccess, dns.RcodeNameError:
+		return answersOf(reply), nil
 	default:
-		return fmt.Errorf("don't understand DNS type %s", dtype)
+		return nil, fmt.Errorf("lookup of %s failed: %s", name, dns.RcodeToString[reply.MsgHdr.Rcode])
+	}
+}
+
+// exchange sends msg to the nameserver ns, reached through d, and returns its
+// reply.
+func exchange(ctx context.Context, d agent.Dialer, ns string, msg *dns.Msg) (*dns.Msg, error) {
+	conn, err := d.DialContext(ctx, "tcp", net.JoinHostPort(ns, "53"))
+	if err != nil {
+		return nil, err
 	}
+	defer conn.Close()
 
-	return nil
+	return roundTrip(conn, msg)
 }
 
 // roundTrip a DNS request across a "TCP" socket; we'd just use miekg/dns's Client, but I don't think it promises to
@@ -0,0 +1,63 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDestroy() *cobra.Command {
+	const (
+		long = `Destroy an extension, along with its data. Apps it's attached to keep their
+secrets, which no longer work.`
+		short = "Destroy an extension"
+		usage = "destroy <name>"
+	)
+
+	cmd := command.New(usage, short, long, runDestroy, command.RequireSession)
+
+	flag.Add(cmd,
+		flag.Yes(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runDestroy(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API().GenqClient
+		name   = flag.FirstArg(ctx)
+	)
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy extension %s and its data? This can't be undone.", name); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if _, err := gql.DeleteAddOn(ctx, client, name); err != nil {
+		return fmt.Errorf("failed destroying extension %s: %w", name, err)
+	}
+
+	fmt.Fprintf(out, "Extension %s was destroyed\n", name)
+
+	return nil
+}
@@ -0,0 +1,301 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/dig"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newEmail() *cobra.Command {
+	const (
+		long = `Commands for setting up transactional email for apps with Resend, over its
+API or SMTP. They need the API to provision email integrations, which it
+doesn't everywhere yet.`
+		short = "Set up transactional email with Resend"
+	)
+
+	cmd := command.New("email", short, long, nil)
+
+	cmd.AddCommand(
+		newEmailCreate(),
+		newEmailVerify(),
+	)
+
+	return cmd
+}
+
+func newEmailCreate() *cobra.Command {
+	const (
+		long = `Provision a Resend email integration sending from --domain for an app, and
+set its RESEND_API_KEY and SMTP_HOST, SMTP_PORT, SMTP_USERNAME and
+SMTP_PASSWORD secrets, which deploys the app.
+
+Email is only delivered once the domain has the DNS records proving it's
+allowed to send from it. These records are listed, and checked until they're
+found with --wait; fly extensions email verify checks them again later.`
+		short = "Provision transactional email for an app"
+		usage = "create"
+	)
+
+	cmd := command.New(usage, short, long, runEmailCreate,
+		command.RequireAPI("AddOnType", "resend"),
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "domain",
+			Description: "Domain to send email from, like example.com",
+		},
+		flag.Bool{
+			Name:        "wait",
+			Description: "Check the DNS records of the domain until they're all found",
+		},
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runEmailCreate(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = flag.GetApp(ctx)
+		domain    = strings.TrimSuffix(flag.GetString(ctx, "domain"), ".")
+	)
+
+	if domain == "" {
+		return errors.New("--domain must be given")
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	options := map[string]interface{}{
+		"domain": domain,
+	}
+
+	integration, err := provision(ctx, "resend", "", app.Organization.ID, app.ID, options)
+	if err != nil {
+		return fmt.Errorf("failed provisioning email for %s: %w", appName, err)
+	}
+
+	secrets := environment(integration.Environment)
+	if len(secrets) == 0 {
+		return errors.New("the email integration was provisioned without credentials")
+	}
+
+	if _, err := apiClient.SetSecrets(ctx, appName, secrets); err != nil {
+		return fmt.Errorf("failed setting the email secrets of %s: %w", appName, err)
+	}
+
+	fmt.Fprintf(io.Out, "Email integration %s was provisioned, and %s of %s set, which deploys it\n\n",
+		colorize.Green(integration.Name), strings.Join(sortedKeys(secrets), ", "), appName)
+
+	verified, err := verifyDomain(ctx, integration.Name, app.Organization.Slug, flag.GetBool(ctx, "wait"))
+	if err == nil && !verified {
+		fmt.Fprintf(io.Out, "Add the missing DNS records at the DNS provider of %s, then run fly extensions email verify %s\n", domain, integration.Name)
+	}
+
+	return err
+}
+
+func newEmailVerify() *cobra.Command {
+	const (
+		long = `Check that the sending domain of an email integration has the DNS records
+proving it's allowed to send from it, listing those that are missing. With
+--wait, the records are checked until they're all found.`
+		short = "Check the DNS records of the sending domain"
+		usage = "verify <name>"
+	)
+
+	cmd := command.New(usage, short, long, runEmailVerify, command.RequireAPI("AddOn", "dnsRecords"))
+
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "wait",
+			Description: "Check the DNS records of the domain until they're all found",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runEmailVerify(ctx context.Context) error {
+	name := flag.FirstArg(ctx)
+
+	integration, err := getEmail(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	verified, err := verifyDomain(ctx, name, integration.Organization.Slug, flag.GetBool(ctx, "wait"))
+	if err == nil && !verified {
+		err = errors.New("DNS records of the sending domain are missing; add them at the DNS provider of the domain")
+	}
+
+	return err
+}
+
+func getEmail(ctx context.Context, name string) (*api.AddOn, error) {
+	integration, err := client.FromContext(ctx).API().GetEmailExtension(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving email integration %s: %w", name, err)
+	}
+
+	return integration, nil
+}
+
+// dnsCheck is the result of the check of a DNS record.
+type dnsCheck struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Found bool   `json:"found"`
+}
+
+// verifyDomain checks the DNS records of the sending domain of the email
+// integration called name, through the nameserver of org, rendering which
+// are found, and repeating until they all are when wait is set. It reports
+// whether they all were.
+func verifyDomain(ctx context.Context, name, org string, wait bool) (bool, error) {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	integration, err := getEmail(ctx, name)
+	if err != nil {
+		return false, err
+	}
+
+	if len(integration.DNSRecords) == 0 {
+		fmt.Fprintln(io.Out, "The sending domain needs no DNS records")
+
+		return true, nil
+	}
+
+	agentclient, err := agent.Establish(ctx, apiClient)
+	if err != nil {
+		return false, err
+	}
+
+	_, ns, err := dig.ResolverForOrg(ctx, agentclient, org)
+	if err != nil {
+		return false, err
+	}
+
+	d, err := agentclient.Dialer(ctx, org)
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		checks := make([]dnsCheck, 0, len(integration.DNSRecords))
+		missing := 0
+
+		for _, record := range integration.DNSRecords {
+			found, err := hasRecord(ctx, d, ns, record.Name, record.Type, record.Value)
+			if err != nil {
+				return false, err
+			}
+
+			if !found {
+				missing++
+			}
+
+			checks = append(checks, dnsCheck{
+				Name:  record.Name,
+				Type:  record.Type,
+				Value: record.Value,
+				Found: found,
+			})
+		}
+
+		if config.FromContext(ctx).JSONOutput {
+			if err := render.JSON(io.Out, checks); err != nil {
+				return false, err
+			}
+		} else {
+			rows := make([][]string, 0, len(checks))
+			for _, check := range checks {
+				status := colorize.Green("✓ found")
+				if !check.Found {
+					status = colorize.Red("✗ missing")
+				}
+
+				rows = append(rows, []string{check.Type, check.Name, check.Value, status})
+			}
+
+			if err := render.Table(io.Out, "DNS records of the sending domain", rows, "Type", "Name", "Value", "Status"); err != nil {
+				return false, err
+			}
+		}
+
+		switch {
+		case missing == 0:
+			fmt.Fprintln(io.Out, "The sending domain has all its DNS records; email is delivered once the provider sees them too")
+
+			return true, nil
+		case !wait:
+			return false, nil
+		}
+
+		fmt.Fprintf(io.ErrOut, "%d DNS records are missing, checking again in 30 seconds...\n", missing)
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(30 * time.Second):
+		}
+	}
+}
+
+// hasRecord reports whether name has a record of type rtype with value.
+func hasRecord(ctx context.Context, d agent.Dialer, ns, name, rtype, value string) (bool, error) {
+	qtype, ok := dns.StringToType[strings.ToUpper(rtype)]
+	if !ok {
+		return false, fmt.Errorf("unsupported DNS record type %s", rtype)
+	}
+
+	answers, err := dig.Lookup(ctx, d, ns, name, qtype)
+	if err != nil {
+		return false, err
+	}
+
+	want := strings.TrimSuffix(value, ".")
+	for _, answer := range answers {
+		// answers of types like MX lead with their priority, and end with
+		// the root
+		if strings.HasSuffix(strings.TrimSuffix(answer.Value, "."), want) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,90 @@
+// Package extensions implements the extensions command chain.
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for provisioning services from Fly.io partners for apps, like
+error tracking, queues, search or email, listing them and destroying them. The
+providers they can be provisioned from are listed by fly extensions providers.
+Extensions need the API to serve the catalog of providers, which it doesn't
+everywhere yet.`
+		short = "Provision partner services for apps"
+	)
+
+	cmd := command.New("extensions", short, long, nil)
+
+	cmd.Aliases = []string{"ext"}
+
+	cmd.AddCommand(
+		newProviders(),
+		newList(),
+		newProvision(),
+		newDestroy(),
+		newSentry(),
+		newEmail(),
+	)
+
+	return cmd
+}
+
+// catalog returns the providers extensions can be provisioned from, sorted by
+// name.
+func catalog(ctx context.Context) ([]api.AddOnProvider, error) {
+	providers, err := client.FromContext(ctx).API().GetAddOnProviders(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving the providers of extensions: %w", err)
+	}
+
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+
+	return providers, nil
+}
+
+// provision provisions an extension of the given type, attached to the app
+// of appID unless it's empty.
+func provision(ctx context.Context, addOnType, name, orgID, appID string, options map[string]interface{}) (*api.AddOn, error) {
+	return client.FromContext(ctx).API().ProvisionExtension(ctx, api.CreateAddOnInput{
+		Type:           addOnType,
+		OrganizationID: orgID,
+		AppID:          appID,
+		Name:           name,
+		Options:        options,
+	})
+}
+
+// environment converts the environment of an extension, which the API returns
+// as a JSON object, to secrets.
+func environment(m map[string]interface{}) map[string]string {
+	env := make(map[string]string, len(m))
+	for name, value := range m {
+		if s, ok := value.(string); ok {
+			env[name] = s
+		}
+	}
+
+	return env
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
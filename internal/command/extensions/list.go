@@ -0,0 +1,108 @@
+package extensions
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newProviders() *cobra.Command {
+	const (
+		long  = `List the providers extensions can be provisioned from`
+		short = "List the providers of extensions"
+		usage = "providers"
+	)
+
+	cmd := command.New(usage, short, long, runProviders, command.RequireAPI("Queries", "addOnProviders"))
+
+	flag.Add(cmd,
+		flag.Format(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runProviders(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	providers, err := catalog(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ok, err := render.Structured(ctx, out, providers); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(providers))
+	for _, p := range providers {
+		rows = append(rows, []string{p.Name, p.DisplayName, p.Description})
+	}
+
+	return render.Table(out, "", rows, "Name", "Provider", "Description")
+}
+
+func newList() *cobra.Command {
+	const (
+		long  = `List the extensions of your organizations, along with their status`
+		short = "List extensions"
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runList, command.RequireAPI("AddOn", "addOnProvider"))
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Format(),
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+		org    = flag.GetOrg(ctx)
+	)
+
+	all, err := client.GetExtensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	var extensions []api.AddOn
+	for _, e := range all {
+		if org == "" || e.Organization.Slug == org {
+			extensions = append(extensions, e)
+		}
+	}
+
+	if ok, err := render.Structured(ctx, out, extensions); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(extensions))
+	for _, e := range extensions {
+		var provider string
+		if e.AddOnProvider != nil {
+			provider = e.AddOnProvider.Name
+		}
+
+		rows = append(rows, []string{e.Name, provider, e.Organization.Slug, e.Status})
+	}
+
+	return render.Table(out, "", rows, "Name", "Provider", "Org", "Status")
+}
@@ -0,0 +1,170 @@
+package extensions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/cmdutil"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newProvision() *cobra.Command {
+	const (
+		long = `Provision an extension from one of the providers fly extensions providers
+lists, chosen from a list when not given.
+
+With --app, the extension is attached to the app, whose secrets are set to the
+credentials of the extension, which deploys it. Otherwise, the credentials are
+shown. Options of the provider are given with --option.`
+		short = "Provision an extension"
+		usage = "provision [provider]"
+	)
+
+	cmd := command.New(usage, short, long, runProvision, command.RequireAPI("Queries", "addOnProviders"))
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.String{
+			Name:        "app",
+			Shorthand:   "a",
+			Description: "App to attach the extension to",
+		},
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "Name of the extension, generated when not given",
+		},
+		flag.StringSlice{
+			Name:        "option",
+			Description: "Option of the provider, in the form of NAME=VALUE, comma separated or repeated",
+		},
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	return cmd
+}
+
+func runProvision(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = flag.GetString(ctx, "app")
+	)
+
+	p, err := selectProvider(ctx, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	options, err := cmdutil.ParseKVStringsToMap(flag.GetStringSlice(ctx, "option"))
+	if err != nil {
+		return fmt.Errorf("invalid options: %w", err)
+	}
+
+	var (
+		orgID string
+		appID string
+	)
+
+	if appName != "" {
+		app, err := apiClient.GetAppCompact(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		}
+
+		orgID, appID = app.Organization.ID, app.ID
+	} else {
+		var org *api.Organization
+		if org, err = prompt.Org(ctx); err != nil {
+			return err
+		}
+
+		orgID = org.ID
+	}
+
+	values := make(map[string]interface{}, len(options))
+	for name, value := range options {
+		values[name] = value
+	}
+
+	e, err := provision(ctx, p.Type, flag.GetString(ctx, "name"), orgID, appID, values)
+	if err != nil {
+		return fmt.Errorf("failed provisioning %s: %w", p.Name, err)
+	}
+
+	secrets := environment(e.Environment)
+
+	if appName != "" && len(secrets) > 0 {
+		if _, err := apiClient.SetSecrets(ctx, appName, secrets); err != nil {
+			return fmt.Errorf("%s was provisioned, but setting the secrets of %s failed: %w", e.Name, appName, err)
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, e)
+	}
+
+	fmt.Fprintf(io.Out, "%s extension %s was provisioned, and is %s\n", p.DisplayName, colorize.Green(e.Name), e.Status)
+
+	switch {
+	case len(secrets) == 0:
+		break
+	case appName != "":
+		fmt.Fprintf(io.Out, "Secrets %s of %s were set, which deploys it\n", strings.Join(sortedKeys(secrets), ", "), appName)
+	default:
+		fmt.Fprintln(io.Out, "Set these secrets on the apps using it:")
+		for _, name := range sortedKeys(secrets) {
+			fmt.Fprintf(io.Out, "  %s: %s\n", name, secrets[name])
+		}
+	}
+
+	return nil
+}
+
+// selectProvider returns the provider of the catalog named name, or the one
+// the user chooses when name is empty.
+func selectProvider(ctx context.Context, name string) (*api.AddOnProvider, error) {
+	providers, err := catalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != "" {
+		names := make([]string, 0, len(providers))
+		for i, p := range providers {
+			if p.Name == name {
+				return &providers[i], nil
+			}
+			names = append(names, p.Name)
+		}
+
+		return nil, fmt.Errorf("unknown provider %s, must be one of %s", name, strings.Join(names, ", "))
+	}
+
+	options := make([]string, 0, len(providers))
+	for _, p := range providers {
+		options = append(options, fmt.Sprintf("%s: %s", p.DisplayName, p.Description))
+	}
+
+	var index int
+	switch err := prompt.Select(ctx, &index, "Select a provider:", "", options...); {
+	case prompt.IsNonInteractive(err):
+		return nil, prompt.NonInteractiveError("provider argument must be specified when not running interactively")
+	case err != nil:
+		return nil, err
+	}
+
+	return &providers[index], nil
+}
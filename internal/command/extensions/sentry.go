@@ -0,0 +1,103 @@
+package extensions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSentry() *cobra.Command {
+	const (
+		long = `Commands for setting up error tracking of apps with Sentry. They need the API
+to provision Sentry projects, which it doesn't everywhere yet.`
+		short = "Set up error tracking with Sentry"
+	)
+
+	cmd := command.New("sentry", short, long, nil)
+
+	cmd.AddCommand(
+		newSentryCreate(),
+	)
+
+	return cmd
+}
+
+func newSentryCreate() *cobra.Command {
+	const (
+		long = `Provision a Sentry project for an app, and set its SENTRY_DSN secret, which
+Sentry SDKs read to report errors, which deploys the app.
+
+With --release-tracking, each fly deploy of the app also creates a Sentry
+release, along with the commit SHA of the working directory, so errors are
+tied to the deploy which introduced them.`
+		short = "Provision a Sentry project for an app"
+		usage = "create"
+	)
+
+	cmd := command.New(usage, short, long, runSentryCreate,
+		command.RequireAPI("AddOnType", "sentry"),
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Bool{
+			Name:        "release-tracking",
+			Description: "Create a Sentry release on each deploy of the app",
+		},
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runSentryCreate(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = flag.GetApp(ctx)
+		tracking  = flag.GetBool(ctx, "release-tracking")
+	)
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	options := map[string]interface{}{
+		"release_tracking": tracking,
+	}
+
+	project, err := provision(ctx, "sentry", appName, app.Organization.ID, app.ID, options)
+	if err != nil {
+		return fmt.Errorf("failed provisioning a Sentry project for %s: %w", appName, err)
+	}
+
+	dsn := environment(project.Environment)["SENTRY_DSN"]
+	if dsn == "" {
+		return errors.New("the Sentry project was provisioned without a DSN")
+	}
+
+	if _, err := apiClient.SetSecrets(ctx, appName, map[string]string{"SENTRY_DSN": dsn}); err != nil {
+		return fmt.Errorf("failed setting SENTRY_DSN of %s: %w", appName, err)
+	}
+
+	fmt.Fprintf(io.Out, "Sentry project %s was provisioned, and SENTRY_DSN of %s set, which deploys it\n",
+		colorize.Green(project.Name), appName)
+
+	if tracking {
+		fmt.Fprintln(io.Out, "Each fly deploy now creates a Sentry release, along with the commit SHA being deployed")
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package externaldb implements the external-db command chain.
+package externaldb
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for attaching apps to databases hosted outside of Fly.io, like
+PlanetScale, Neon or Amazon RDS.`
+		short = "Attach apps to databases hosted outside of Fly.io"
+	)
+
+	cmd := command.New("external-db", short, long, nil)
+
+	cmd.AddCommand(
+		newAttach(),
+	)
+
+	return cmd
+}
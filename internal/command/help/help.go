@@ -16,7 +16,6 @@ var deprecatedCommands = map[string]bool{
 	"completion":  true,
 	"curl":        true,
 	"dns-records": true,
-	"domains":     true,
 }
 
 func New(root *cobra.Command) *cobra.Command {
@@ -184,7 +184,7 @@ func updateImageForMachines(ctx context.Context, app *api.AppCompact) (err error
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("can't build tunnel for %s: %s", app.Organization.Slug, err)
+		return fmt.Errorf("can't build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
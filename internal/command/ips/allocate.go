@@ -78,6 +78,5 @@ func runAllocateIPAddress(ctx context.Context, addrType string) error {
 	}
 
 	ipAddresses := []api.IPAddress{*ipAddress}
-	renderListTable(ctx, ipAddresses)
-	return nil
+	return renderListTable(ctx, ipAddresses)
 }
@@ -0,0 +1,235 @@
+package ips
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/cmd/presenters"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// requireEgress makes sure the API serves static egress addresses.
+var requireEgress = command.RequireAPI("App", "egressIpAddresses")
+
+func newAllocateEgress() *cobra.Command {
+	const (
+		long = `Allocates static IPv4 and IPv6 egress addresses to the machines of the
+application, so that their outbound traffic comes from fixed addresses. All machines
+without egress addresses get them, unless specific machines are given with --machine.
+
+Static egress addresses need the API to serve them, which it doesn't everywhere yet.`
+		short = `Allocate static egress IP addresses`
+	)
+
+	cmd := command.New("allocate-egress", short, long, runAllocateEgress,
+		requireEgress,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.StringSlice{
+			Name:        "machine",
+			Description: "ID of a machine to allocate egress addresses to. Can be specified multiple times",
+		},
+	)
+
+	return cmd
+}
+
+func newListEgress() *cobra.Command {
+	const (
+		long = `Lists the static egress IP addresses of the application and the machines using them.
+
+Static egress addresses need the API to serve them, which it doesn't everywhere yet.`
+		short = `List static egress IP addresses`
+	)
+
+	cmd := command.New("list-egress", short, long, runListEgress,
+		requireEgress,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	return cmd
+}
+
+func newReleaseEgress() *cobra.Command {
+	const (
+		long = `Releases the static egress IP addresses of the machines of the application.
+All egress addresses are released, unless specific machines are given with --machine.
+
+Static egress addresses need the API to serve them, which it doesn't everywhere yet.`
+		short = `Release static egress IP addresses`
+	)
+
+	cmd := command.New("release-egress", short, long, runReleaseEgress,
+		requireEgress,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.StringSlice{
+			Name:        "machine",
+			Description: "ID of a machine to release egress addresses from. Can be specified multiple times",
+		},
+	)
+
+	return cmd
+}
+
+func runAllocateEgress(ctx context.Context) error {
+	client := client.FromContext(ctx).API()
+	appName := app.NameFromContext(ctx)
+
+	existing, err := client.GetEgressIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	machineIDs := flag.GetStringSlice(ctx, "machine")
+	if len(machineIDs) == 0 {
+		appCompact, err := client.GetAppCompact(ctx, appName)
+		if err != nil {
+			return err
+		}
+
+		flapsClient, err := flaps.New(ctx, appCompact)
+		if err != nil {
+			return fmt.Errorf("could not make flaps client: %w", err)
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, machine := range machines {
+			if !lo.ContainsBy(existing, func(ip api.EgressIPAddress) bool { return ip.MachineID == machine.ID }) {
+				machineIDs = append(machineIDs, machine.ID)
+			}
+		}
+
+		if len(machineIDs) == 0 {
+			return fmt.Errorf("all machines of %s already have egress addresses", appName)
+		}
+	}
+
+	var allocated []api.EgressIPAddress
+	for _, machineID := range machineIDs {
+		ips, err := client.AllocateEgressIPAddress(ctx, appName, machineID)
+		if err != nil {
+			return fmt.Errorf("failed allocating egress addresses to machine %s: %w", machineID, err)
+		}
+
+		allocated = append(allocated, ips...)
+	}
+
+	return renderEgressTable(ctx, allocated)
+}
+
+func runListEgress(ctx context.Context) error {
+	client := client.FromContext(ctx).API()
+	appName := app.NameFromContext(ctx)
+
+	ips, err := client.GetEgressIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	return renderEgressTable(ctx, ips)
+}
+
+func runReleaseEgress(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+	)
+
+	machineIDs := flag.GetStringSlice(ctx, "machine")
+	if len(machineIDs) == 0 {
+		ips, err := client.GetEgressIPAddresses(ctx, appName)
+		if err != nil {
+			return err
+		}
+
+		machineIDs = lo.Uniq(lo.Map(ips, func(ip api.EgressIPAddress, _ int) string { return ip.MachineID }))
+		if len(machineIDs) == 0 {
+			return fmt.Errorf("%s has no egress addresses", appName)
+		}
+
+		if !flag.GetYes(ctx) {
+			msg := fmt.Sprintf("Release the egress addresses of all %d machines of %s?", len(machineIDs), appName)
+
+			switch confirmed, err := prompt.Confirm(ctx, msg); {
+			case err == nil:
+				if !confirmed {
+					return nil
+				}
+			case prompt.IsNonInteractive(err):
+				return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+			default:
+				return err
+			}
+		}
+	}
+
+	for _, machineID := range machineIDs {
+		if err := client.ReleaseEgressIPAddress(ctx, appName, machineID); err != nil {
+			return fmt.Errorf("failed releasing egress addresses of machine %s: %w", machineID, err)
+		}
+
+		fmt.Fprintf(io.Out, "Released egress addresses of machine %s\n", machineID)
+	}
+
+	return nil
+}
+
+func renderEgressTable(ctx context.Context, ips []api.EgressIPAddress) error {
+	out := iostreams.FromContext(ctx).Out
+
+	if config.FromContext(ctx).JSONOutput {
+		docs := make([]schema.EgressIPAddress, 0, len(ips))
+		for _, ip := range ips {
+			docs = append(docs, schema.EgressIPAddress{
+				ID:        ip.ID,
+				Address:   ip.IP,
+				Version:   ip.Version,
+				Region:    ip.Region,
+				MachineID: ip.MachineID,
+				CreatedAt: ip.CreatedAt,
+			})
+		}
+
+		return render.JSON(out, docs)
+	}
+
+	rows := make([][]string, 0, len(ips))
+	for _, ip := range ips {
+		rows = append(rows, []string{ip.MachineID, fmt.Sprintf("v%d", ip.Version), ip.IP, ip.Region, presenters.FormatRelativeTime(ip.CreatedAt)})
+	}
+
+	return render.Table(out, "", rows, "Machine", "Version", "IP", "Region", "Created At")
+}
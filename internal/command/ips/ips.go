@@ -19,6 +19,11 @@ func New() *cobra.Command {
 		newAllocatev6(),
 		newPrivate(),
 		newRelease(),
+		newAllocateEgress(),
+		newListEgress(),
+		newReleaseEgress(),
+		newUpgrade(),
+		newMakeV6Only(),
 	)
 	return cmd
 }
@@ -24,6 +24,7 @@ func newList() *cobra.Command {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Format(),
 	)
 	return cmd
 }
@@ -37,6 +38,5 @@ func runIPAddressesList(ctx context.Context) error {
 		return err
 	}
 
-	renderListTable(ctx, ipAddresses)
-	return nil
+	return renderListTable(ctx, ipAddresses)
 }
@@ -2,23 +2,33 @@ package ips
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"strings"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
 )
 
 func newRelease() *cobra.Command {
 	const (
-		long  = `Releases an IP address from the application`
-		short = `Release an IP address`
+		long = `Releases one or more IP addresses from the application. With --all, every
+address of the application is released, or every address of a type with --type.
+Releasing more than one address lists the hostnames resolving to each of them
+for confirmation first.`
+		short = `Release IP addresses`
 	)
 
-	cmd := command.New("release [ADDRESS]", short, long, runReleaseIPAddress,
+	cmd := command.New("release [ADDRESS...]", short, long, runReleaseIPAddress,
 		command.RequireSession,
 		command.RequireAppName,
 	)
@@ -26,32 +36,151 @@ func newRelease() *cobra.Command {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Yes(),
+		flag.Bool{
+			Name:        "all",
+			Description: "Release all addresses of the app, or all of the type given with --type",
+		},
+		flag.String{
+			Name:        "type",
+			Description: "With --all, the type of the addresses to release: v4, v6, shared-v4 or private-v6",
+		},
 	)
 
-	cmd.Args = cobra.ExactArgs(1)
 	return cmd
 }
 
+// releasableTypes are the types of addresses --type selects.
+var releasableTypes = []string{"v4", "v6", "shared_v4", "private_v6"}
+
 func runReleaseIPAddress(ctx context.Context) error {
-	client := client.FromContext(ctx).API()
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+		args    = flag.Args(ctx)
+		all     = flag.GetBool(ctx, "all")
+		ipType  = strings.ReplaceAll(flag.GetString(ctx, "type"), "-", "_")
+	)
+
+	switch {
+	case all && len(args) > 0:
+		return errors.New("specify either addresses or --all, not both")
+	case !all && len(args) == 0:
+		return errors.New("specify the addresses to release, or --all")
+	case !all && ipType != "":
+		return errors.New("--type only applies with --all")
+	case ipType != "" && !lo.Contains(releasableTypes, ipType):
+		return fmt.Errorf("invalid --type %q: it must be one of v4, v6, shared-v4 or private-v6", flag.GetString(ctx, "type"))
+	}
+
+	var toRelease []api.IPAddress
+
+	if all {
+		ipAddresses, err := client.GetIPAddresses(ctx, appName)
+		if err != nil {
+			return err
+		}
+
+		for _, ipAddress := range ipAddresses {
+			if ipType == "" || ipAddress.Type == ipType {
+				toRelease = append(toRelease, ipAddress)
+			}
+		}
+
+		if len(toRelease) == 0 {
+			return fmt.Errorf("%s has no addresses to release", appName)
+		}
+	} else {
+		for _, address := range args {
+			if ip := net.ParseIP(address); ip == nil {
+				return fmt.Errorf("Invalid IP address: '%s'", address)
+			}
+
+			ipAddress, err := client.FindIPAddress(ctx, appName, address)
+			if err != nil {
+				return err
+			}
+			if ipAddress == nil {
+				return fmt.Errorf("%s isn't allocated to %s", address, appName)
+			}
+
+			toRelease = append(toRelease, *ipAddress)
+		}
+	}
+
+	// releasing a single, named address is confirmed by naming it
+	if (all || len(args) > 1) && !flag.GetYes(ctx) {
+		hostnames, err := hostnamesResolvingTo(ctx, appName, toRelease)
+		if err != nil {
+			return err
+		}
 
-	appName := app.NameFromContext(ctx)
-	address := flag.Args(ctx)[0]
+		rows := make([][]string, 0, len(toRelease))
+		for _, ipAddress := range toRelease {
+			resolving := strings.Join(hostnames[ipAddress.Address], ", ")
+			if resolving == "" {
+				resolving = "-"
+			}
 
-	if ip := net.ParseIP(address); ip == nil {
-		return fmt.Errorf("Invalid IP address: '%s'", address)
+			rows = append(rows, []string{ipAddress.Address, ipAddress.Type, resolving})
+		}
+
+		if err := render.Table(io.Out, "", rows, "IP", "Type", "Hostnames Resolving To It"); err != nil {
+			return err
+		}
+
+		msg := fmt.Sprintf("Release %d addresses from %s? Traffic to the hostnames above will stop reaching it", len(toRelease), appName)
+
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	for _, ipAddress := range toRelease {
+		if err := client.ReleaseIPAddress(ctx, ipAddress.ID); err != nil {
+			return fmt.Errorf("failed releasing %s: %w", ipAddress.Address, err)
+		}
+
+		fmt.Fprintf(io.Out, "Released %s from %s\n", ipAddress.Address, appName)
 	}
 
-	ipAddress, err := client.FindIPAddress(ctx, appName, address)
+	return nil
+}
+
+// hostnamesResolvingTo maps each of the addresses to the hostnames of the app,
+// its certificates' and its fly.dev one, which currently resolve to it.
+func hostnamesResolvingTo(ctx context.Context, appName string, ipAddresses []api.IPAddress) (map[string][]string, error) {
+	certs, err := client.FromContext(ctx).API().GetAppCertificates(ctx, appName)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := client.ReleaseIPAddress(ctx, ipAddress.ID); err != nil {
-		return err
+	hostnames := []string{appName + ".fly.dev"}
+	for _, cert := range certs {
+		hostnames = append(hostnames, cert.Hostname)
 	}
 
-	fmt.Printf("Released %s from %s\n", ipAddress.Address, appName)
+	resolving := map[string][]string{}
+	for _, hostname := range hostnames {
+		addrs, err := net.DefaultResolver.LookupIP(ctx, "ip", hostname)
+		if err != nil {
+			continue
+		}
 
-	return nil
+		for _, ipAddress := range ipAddresses {
+			if containsIP(addrs, ipAddress.Address) {
+				resolving[ipAddress.Address] = append(resolving[ipAddress.Address], hostname)
+			}
+		}
+	}
+
+	return resolving, nil
 }
@@ -7,25 +7,43 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/cmd/presenters"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
 	"github.com/superfly/flyctl/iostreams"
 )
 
-func renderListTable(ctx context.Context, ipAddresses []api.IPAddress) {
-	rows := make([][]string, 0, len(ipAddresses))
+func renderListTable(ctx context.Context, ipAddresses []api.IPAddress) error {
+	out := iostreams.FromContext(ctx).Out
 
-	var ipType string
-	for _, ipAddr := range ipAddresses {
+	scope := func(ipAddr api.IPAddress) string {
 		if strings.HasPrefix(ipAddr.Address, "fdaa") {
-			ipType = "private"
-		} else {
-			ipType = "public"
+			return "private"
 		}
 
-		rows = append(rows, []string{ipAddr.Type, ipAddr.Address, ipType, ipAddr.Region, presenters.FormatRelativeTime(ipAddr.CreatedAt)})
+		return "public"
 	}
 
-	out := iostreams.FromContext(ctx).Out
-	render.Table(out, "", rows, "Version", "IP", "Type", "Region", "Created At")
+	docs := make([]schema.IPAddress, 0, len(ipAddresses))
+	for _, ipAddr := range ipAddresses {
+		docs = append(docs, schema.IPAddress{
+			ID:        ipAddr.ID,
+			Address:   ipAddr.Address,
+			Type:      ipAddr.Type,
+			Scope:     scope(ipAddr),
+			Region:    ipAddr.Region,
+			CreatedAt: ipAddr.CreatedAt,
+		})
+	}
+
+	if ok, err := render.Structured(ctx, out, docs); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(ipAddresses))
+	for _, ipAddr := range ipAddresses {
+		rows = append(rows, []string{ipAddr.Type, ipAddr.Address, scope(ipAddr), ipAddr.Region, presenters.FormatRelativeTime(ipAddr.CreatedAt)})
+	}
+
+	return render.Table(out, "", rows, "Version", "IP", "Type", "Region", "Created At")
 }
 
 func renderPrivateTable(ctx context.Context, allocations []*api.AllocationStatus, backupRegions []api.Region) {
@@ -0,0 +1,183 @@
+package ips
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newUpgrade() *cobra.Command {
+	const (
+		long = `Upgrades the shared IPv4 address of the application to a dedicated one. A
+dedicated IPv4 address is allocated and checked to serve the application's
+certificates before the shared address is released. DNS records that still point
+at the shared address are listed afterwards.`
+		short = `Upgrade a shared IPv4 address to a dedicated one`
+	)
+
+	cmd := command.New("upgrade", short, long, runUpgrade,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Bool{
+			Name:        "dedicated-ipv4",
+			Description: "Replace the shared IPv4 address with a dedicated one",
+		},
+	)
+
+	return cmd
+}
+
+func runUpgrade(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+	)
+
+	if !flag.GetBool(ctx, "dedicated-ipv4") {
+		return errors.New("specify the upgrade to perform; only --dedicated-ipv4 is supported")
+	}
+
+	ipAddresses, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var shared *api.IPAddress
+	for i, ipAddr := range ipAddresses {
+		switch ipAddr.Type {
+		case "v4":
+			return fmt.Errorf("%s already has the dedicated IPv4 address %s", appName, ipAddr.Address)
+		case "shared_v4":
+			shared = &ipAddresses[i]
+		}
+	}
+
+	if shared == nil {
+		return fmt.Errorf("%s has no shared IPv4 address; allocate a dedicated one with flyctl ips allocate-v4", appName)
+	}
+
+	if !flag.GetYes(ctx) {
+		msg := fmt.Sprintf("Dedicated IPv4 addresses are billed monthly. Replace the shared address %s of %s with a dedicated one?", shared.Address, appName)
+
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	certs, err := client.GetAppCertificates(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	hostnames := []string{appName + ".fly.dev"}
+	for _, cert := range certs {
+		if cert.ClientStatus == "Ready" {
+			hostnames = append(hostnames, cert.Hostname)
+		}
+	}
+
+	tb := render.NewTextBlock(ctx, "Allocating a dedicated IPv4 address")
+
+	dedicated, err := client.AllocateIPAddress(ctx, appName, "v4", "")
+	if err != nil {
+		return err
+	}
+
+	tb.Detailf("Allocated %s", dedicated.Address)
+
+	for _, hostname := range hostnames {
+		tb.Detailf("Checking %s is served on %s", hostname, dedicated.Address)
+
+		if err := checkServed(ctx, dedicated.Address, hostname); err != nil {
+			// the dedicated address is billed, so it's not kept around unused
+			if releaseErr := client.ReleaseIPAddress(context.Background(), dedicated.ID); releaseErr != nil {
+				fmt.Fprintf(io.ErrOut, "failed releasing the dedicated address %s: %v\n", dedicated.Address, releaseErr)
+			}
+
+			return fmt.Errorf("%s isn't served on %s yet, so the shared address %s was kept: %w", hostname, dedicated.Address, shared.Address, err)
+		}
+	}
+
+	if err := client.ReleaseIPAddress(ctx, shared.ID); err != nil {
+		return fmt.Errorf("failed releasing the shared address %s: %w", shared.Address, err)
+	}
+
+	tb.Donef("Replaced the shared address %s with the dedicated address %s", shared.Address, dedicated.Address)
+
+	var stale []string
+	for _, hostname := range hostnames[1:] {
+		addrs, err := net.DefaultResolver.LookupHost(ctx, hostname)
+		if err != nil {
+			continue
+		}
+
+		for _, addr := range addrs {
+			if addr == shared.Address {
+				stale = append(stale, hostname)
+				break
+			}
+		}
+	}
+
+	if len(stale) > 0 {
+		fmt.Fprintf(io.Out, "\nThese hostnames resolve to the shared address. Update their A records to %s:\n\n", dedicated.Address)
+		for _, hostname := range stale {
+			fmt.Fprintf(io.Out, "    A %s %s\n", hostname, dedicated.Address)
+		}
+	}
+
+	return nil
+}
+
+// checkServed checks that a TLS connection to address for hostname presents a
+// valid certificate for it, retrying while the new address propagates.
+func checkServed(ctx context.Context, address, hostname string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 10 * time.Second},
+		Config:    &tls.Config{ServerName: hostname},
+	}
+
+	for {
+		var conn net.Conn
+		if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, "443")); err == nil {
+			return conn.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package ips
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newMakeV6Only() *cobra.Command {
+	const (
+		long = `Makes the application reachable over IPv6 only by releasing its IPv4
+addresses. The application's TCP services are checked to accept connections on
+its IPv6 address first, which needs IPv6 connectivity from here, and the DNS
+changes its hostnames need are listed afterwards. UDP services aren't checked,
+and are only reachable over IPv4. Clients without IPv6 connectivity can't reach
+the application anymore.
+
+Run with --revert to allocate a shared IPv4 address again, or a dedicated one
+with --revert --dedicated.`
+		short = `Release the IPv4 addresses of an app`
+	)
+
+	cmd := command.New("make-v6-only", short, long, runMakeV6Only,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Bool{
+			Name:        "revert",
+			Description: "Allocate an IPv4 address to the app again",
+		},
+		flag.Bool{
+			Name:        "dedicated",
+			Description: "With --revert, allocate a dedicated rather than a shared IPv4 address",
+		},
+	)
+
+	return cmd
+}
+
+func runMakeV6Only(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+	)
+
+	if flag.GetBool(ctx, "revert") {
+		return revertV6Only(ctx)
+	}
+
+	ipAddresses, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var (
+		v4s []api.IPAddress
+		v6  string
+	)
+	for _, ipAddr := range ipAddresses {
+		switch ipAddr.Type {
+		case "v4", "shared_v4":
+			v4s = append(v4s, ipAddr)
+		case "v6":
+			v6 = ipAddr.Address
+		}
+	}
+
+	if len(v4s) == 0 {
+		return fmt.Errorf("%s has no IPv4 addresses", appName)
+	}
+
+	if v6 == "" {
+		return fmt.Errorf("%s has no public IPv6 address; allocate one with flyctl ips allocate-v6 first", appName)
+	}
+
+	cfg, err := client.GetConfig(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	for _, port := range tcpPorts(cfg.Definition["services"]) {
+		if err := checkReachable(ctx, v6, port); err != nil {
+			return fmt.Errorf("%s isn't reachable on port %s of its IPv6 address %s, so its IPv4 addresses were kept: %w", appName, port, v6, err)
+		}
+	}
+
+	if !flag.GetYes(ctx) {
+		addresses := make([]string, 0, len(v4s))
+		for _, v4 := range v4s {
+			addresses = append(addresses, v4.Address)
+		}
+
+		msg := fmt.Sprintf("Release %s? Clients without IPv6 connectivity won't be able to reach %s", strings.Join(addresses, ", "), appName)
+
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	// collect the hostnames pointing at the addresses before they're released
+	certs, err := client.GetAppCertificates(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var stale []string
+	for _, cert := range certs {
+		addrs, err := net.DefaultResolver.LookupIP(ctx, "ip4", cert.Hostname)
+		if err != nil {
+			continue
+		}
+
+		for _, v4 := range v4s {
+			if containsIP(addrs, v4.Address) {
+				stale = append(stale, cert.Hostname)
+				break
+			}
+		}
+	}
+
+	for _, v4 := range v4s {
+		if err := client.ReleaseIPAddress(ctx, v4.ID); err != nil {
+			return fmt.Errorf("failed releasing %s: %w", v4.Address, err)
+		}
+
+		fmt.Fprintf(io.Out, "Released %s from %s\n", v4.Address, appName)
+	}
+
+	if len(stale) > 0 {
+		fmt.Fprintf(io.Out, "\nThese hostnames have A records pointing at released addresses. Remove them and make sure they have an AAAA record instead:\n\n")
+		for _, hostname := range stale {
+			fmt.Fprintf(io.Out, "    AAAA %s %s\n", hostname, v6)
+		}
+	}
+
+	fmt.Fprintf(io.Out, "\nTo make %s reachable over IPv4 again, run: flyctl ips make-v6-only --revert -a %s\n", appName, appName)
+
+	return nil
+}
+
+func revertV6Only(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+	)
+
+	addrType := "shared_v4"
+	if flag.GetBool(ctx, "dedicated") {
+		addrType = "v4"
+	}
+
+	ipAddress, err := client.AllocateIPAddress(ctx, appName, addrType, "")
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Allocated %s to %s\n", ipAddress.Address, appName)
+	fmt.Fprintf(io.Out, "\nHostnames with their own A records need them pointed at it:\n\n    A <hostname> %s\n", ipAddress.Address)
+
+	return nil
+}
+
+// tcpPorts returns the public ports of the TCP services in the raw services
+// section of an app's configuration.
+func tcpPorts(raw interface{}) (ports []string) {
+	services, _ := raw.([]interface{})
+
+	for _, service := range services {
+		service, ok := service.(map[string]interface{})
+		if !ok || (service["protocol"] != nil && service["protocol"] != "tcp") {
+			continue
+		}
+
+		servicePorts, _ := service["ports"].([]interface{})
+		for _, port := range servicePorts {
+			if port, ok := port.(map[string]interface{}); ok && port["port"] != nil {
+				ports = append(ports, fmt.Sprint(port["port"]))
+			}
+		}
+	}
+
+	return
+}
+
+// checkReachable checks that a TCP connection to port of address, an IPv6
+// address, can be established.
+func checkReachable(ctx context.Context, address, port string) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+
+	conn, err := dialer.DialContext(ctx, "tcp6", net.JoinHostPort(address, port))
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func containsIP(addrs []net.IP, address string) bool {
+	for _, addr := range addrs {
+		if addr.Equal(net.ParseIP(address)) {
+			return true
+		}
+	}
+
+	return false
+}
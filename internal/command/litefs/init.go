@@ -0,0 +1,100 @@
+package litefs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/consul"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/scanner"
+)
+
+func newInit() *cobra.Command {
+	const (
+		long = `Initialize LiteFS for an app: write ` + ConfigFileName + ` next to fly.toml, add the
+volume LiteFS keeps its data on to the mounts of fly.toml, and attach the app
+to Consul, which elects the primary.`
+		short = "Initialize LiteFS for an app"
+		usage = "init"
+	)
+
+	cmd := command.New(usage, short, long, runInit,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runInit(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = app.NameFromContext(ctx)
+		cfg     = app.ConfigFromContext(ctx)
+	)
+
+	if cfg == nil {
+		return fmt.Errorf("no fly.toml found; run fly launch first")
+	}
+
+	path := filepath.Join(filepath.Dir(cfg.Path), ConfigFileName)
+
+	write := true
+	if helpers.FileExists(path) && !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Overwrite %s?", path); {
+		case err == nil:
+			write = confirmed
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if write {
+		if err := os.WriteFile(path, DefaultConfig(), 0o644); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.Out, "Wrote %s\n", path)
+	}
+
+	if _, ok := cfg.Definition["mounts"]; ok {
+		fmt.Fprintf(io.ErrOut, "fly.toml already mounts a volume, which was left as is; LiteFS keeps its data in %s, which must be on it\n", Volume.Destination)
+	} else {
+		cfg.SetVolumes([]scanner.Volume{Volume})
+
+		if err := cfg.WriteToFile(cfg.Path); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.Out, "Added the %s volume, mounted at %s, to %s\n", Volume.Source, Volume.Destination, cfg.Path)
+	}
+
+	if _, err := consul.Attach(ctx, client.FromContext(ctx).API(), appName, consul.DefaultVariableName); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Attached %s to Consul, setting its %s secret\n", appName, consul.DefaultVariableName)
+	fmt.Fprint(io.Out, Notice)
+
+	return nil
+}
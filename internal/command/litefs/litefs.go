@@ -0,0 +1,187 @@
+// Package litefs implements the litefs command chain.
+package litefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/scanner"
+)
+
+const (
+	// ConfigFileName is the name of the LiteFS configuration file, next to
+	// fly.toml.
+	ConfigFileName = "litefs.yml"
+
+	// apiPort is the port the HTTP API of LiteFS listens on.
+	apiPort = "20202"
+
+	defaultMountDir = "/litefs"
+)
+
+// Volume is the volume LiteFS keeps its data on.
+var Volume = scanner.Volume{
+	Source:      "litefs",
+	Destination: "/var/lib/litefs",
+}
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for running SQLite apps replicated with LiteFS: initializing their
+configuration, inspecting replication and the current primary, handing the
+primary off to another machine, and exporting and importing databases.`
+		short = "Manage SQLite databases replicated with LiteFS"
+	)
+
+	cmd := command.New("litefs", short, long, nil)
+
+	cmd.AddCommand(
+		newInit(),
+		newStatus(),
+		newHandoff(),
+		newExport(),
+		newImport(),
+	)
+
+	return cmd
+}
+
+// DefaultConfig returns the LiteFS configuration of apps on Fly.io, which
+// mounts databases at /litefs, keeps its data on Volume, and elects a primary
+// among the machines of the primary region through Consul.
+func DefaultConfig() []byte {
+	return []byte(`# LiteFS replicates the SQLite databases of this app. Learn more at
+# https://fly.io/docs/litefs/
+fuse:
+  dir: "` + defaultMountDir + `"
+
+data:
+  dir: "` + Volume.Destination + `"
+
+exit-on-error: false
+
+lease:
+  type: "consul"
+  advertise-url: "http://${HOSTNAME}.vm.${FLY_APP_NAME}.internal:` + apiPort + `"
+  candidate: ${FLY_REGION == PRIMARY_REGION}
+  promote: true
+
+  consul:
+    url: "${FLY_CONSUL_URL}"
+    key: "litefs/${FLY_APP_NAME}"
+`)
+}
+
+// Notice tells what apps must do, besides the configuration DefaultConfig
+// returns, to run LiteFS.
+const Notice = `
+LiteFS must run in the image of the app, mounting the databases before the app
+starts. Add this to your Dockerfile, and open databases under /litefs:
+
+  COPY --from=flyio/litefs:0.5 /usr/local/bin/litefs /usr/local/bin/litefs
+  RUN apt-get update -y && apt-get install -y ca-certificates fuse3 sqlite3
+  ENTRYPOINT litefs mount -- <command starting your app>
+`
+
+func mountDirFlag() flag.String {
+	return flag.String{
+		Name:        "mount-dir",
+		Description: "Directory LiteFS mounts databases at, as configured in " + ConfigFileName,
+		Default:     defaultMountDir,
+	}
+}
+
+// startedMachines returns the started machines of the app, sorted by ID.
+func startedMachines(ctx context.Context, flapsClient *flaps.Client) ([]*api.Machine, error) {
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var started []*api.Machine
+	for _, m := range machines {
+		if m.State == "started" {
+			started = append(started, m)
+		}
+	}
+
+	sort.Slice(started, func(i, j int) bool {
+		return started[i].ID < started[j].ID
+	})
+
+	if len(started) == 0 {
+		return nil, errors.New("no started machines")
+	}
+
+	return started, nil
+}
+
+// primaryHost returns the hostname of the primary a machine replicates from,
+// or an empty string when the machine is the primary.
+func primaryHost(ctx context.Context, flapsClient *flaps.Client, machineID, mountDir string) (string, error) {
+	res, err := flapsClient.Exec(ctx, machineID, &api.MachineExecRequest{
+		Cmd: "cat " + mountDir + "/.primary",
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if res.ExitCode != 0 {
+		return "", nil
+	}
+
+	return strings.TrimSpace(res.StdOut), nil
+}
+
+// findPrimary returns the machine which is the primary.
+func findPrimary(ctx context.Context, flapsClient *flaps.Client, machines []*api.Machine, mountDir string) (*api.Machine, error) {
+	for _, m := range machines {
+		host, err := primaryHost(ctx, flapsClient, m.ID, mountDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading the primary of machine %s: %w", m.ID, err)
+		}
+
+		if host == "" {
+			return m, nil
+		}
+	}
+
+	return nil, errors.New("none of the started machines is the primary")
+}
+
+// apiClient returns a client of the HTTP API of LiteFS on the machines of the
+// organization, reached through the WireGuard tunnel of the agent.
+func apiClient(ctx context.Context, client *api.Client, orgSlug string) (*http.Client, error) {
+	agentclient, err := agent.Establish(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer, err := agentclient.ConnectToTunnel(ctx, orgSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: dialer.DialContext,
+		},
+	}, nil
+}
+
+// apiURL returns the URL of path on the HTTP API of LiteFS on the machine.
+func apiURL(machine *api.Machine, path string) string {
+	return "http://" + net.JoinHostPort(machine.PrivateIP, apiPort) + path
+}
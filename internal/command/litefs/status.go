@@ -0,0 +1,260 @@
+package litefs
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStatus() *cobra.Command {
+	const (
+		long = `Show the role of each started machine of an app, the primary replicas
+replicate from, and the replication position of each database, as a
+transaction ID and checksum. Replicas whose positions lag behind the primary's
+haven't caught up with its last writes.`
+		short = "Show LiteFS replication status"
+		usage = "status"
+	)
+
+	cmd := command.New(usage, short, long, runStatus,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		mountDirFlag(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+// node is the replication status of a machine.
+type node struct {
+	Machine   string            `json:"machine"`
+	Region    string            `json:"region"`
+	Role      string            `json:"role"`
+	Primary   string            `json:"primary,omitempty"`
+	Positions map[string]string `json:"positions"`
+	Error     string            `json:"error,omitempty"`
+}
+
+func runStatus(ctx context.Context) error {
+	var (
+		out      = iostreams.FromContext(ctx).Out
+		appName  = app.NameFromContext(ctx)
+		mountDir = flag.GetString(ctx, "mount-dir")
+	)
+
+	_, flapsClient, machines, err := appMachines(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	nodes := make([]node, 0, len(machines))
+	for _, m := range machines {
+		n := node{Machine: m.ID, Region: m.Region}
+
+		if err := inspect(ctx, flapsClient, m.ID, mountDir, &n); err != nil {
+			n.Role, n.Error = "unknown", err.Error()
+		}
+
+		nodes = append(nodes, n)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, nodes)
+	}
+
+	rows := make([][]string, 0, len(nodes))
+	for _, n := range nodes {
+		positions := make([]string, 0, len(n.Positions))
+		for _, db := range sortedKeys(n.Positions) {
+			positions = append(positions, db+"@"+n.Positions[db])
+		}
+
+		details := strings.Join(positions, ", ")
+		if n.Error != "" {
+			details = n.Error
+		}
+
+		rows = append(rows, []string{n.Machine, n.Region, n.Role, n.Primary, details})
+	}
+
+	return render.Table(out, "", rows, "Machine", "Region", "Role", "Primary", "Positions")
+}
+
+// inspect reads the role of the machine, and the positions of its databases,
+// from the files LiteFS exposes in the mount directory.
+func inspect(ctx context.Context, flapsClient *flaps.Client, machineID, mountDir string, n *node) error {
+	res, err := flapsClient.Exec(ctx, machineID, &api.MachineExecRequest{Cmd: "ls -a " + mountDir})
+	if err != nil {
+		return err
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("LiteFS isn't mounted at %s", mountDir)
+	}
+
+	n.Positions = map[string]string{}
+	for _, name := range strings.Fields(res.StdOut) {
+		db := strings.TrimSuffix(name, "-pos")
+		if db == name {
+			continue
+		}
+
+		res, err := flapsClient.Exec(ctx, machineID, &api.MachineExecRequest{Cmd: "cat " + mountDir + "/" + name})
+		if err != nil {
+			return err
+		}
+
+		n.Positions[db] = strings.TrimSpace(res.StdOut)
+	}
+
+	if n.Primary, err = primaryHost(ctx, flapsClient, machineID, mountDir); err != nil {
+		return err
+	}
+
+	n.Role = "replica"
+	if n.Primary == "" {
+		n.Role = "primary"
+	}
+
+	return nil
+}
+
+func newHandoff() *cobra.Command {
+	const (
+		long = `Hand the primary off to a machine, which must be a candidate, from the
+current primary, which becomes a replica. Writes fail while the handoff is
+underway.`
+		short = "Hand the LiteFS primary off to a machine"
+		usage = "handoff <machine-id>"
+	)
+
+	cmd := command.New(usage, short, long, runHandoff,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		mountDirFlag(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runHandoff(ctx context.Context) error {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		appName   = app.NameFromContext(ctx)
+		machineID = flag.FirstArg(ctx)
+		mountDir  = flag.GetString(ctx, "mount-dir")
+	)
+
+	_, flapsClient, machines, err := appMachines(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var target *api.Machine
+	for _, m := range machines {
+		if m.ID == machineID {
+			target = m
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("machine %s isn't a started machine of %s", machineID, appName)
+	}
+
+	primary, err := findPrimary(ctx, flapsClient, machines, mountDir)
+	if err != nil {
+		return err
+	}
+	if primary.ID == target.ID {
+		return fmt.Errorf("machine %s already is the primary", machineID)
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Hand the primary off from %s to %s? Writes fail until it completes.", primary.ID, target.ID); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	// litefs run -promote asks the primary to hand off to the local node
+	// before running its command.
+	res, err := flapsClient.Exec(ctx, target.ID, &api.MachineExecRequest{
+		Cmd:     "litefs run -promote -- true",
+		Timeout: 30,
+	})
+	if err != nil {
+		return fmt.Errorf("failed handing the primary off to %s: %w", target.ID, err)
+	}
+	if res.ExitCode != 0 {
+		return fmt.Errorf("failed handing the primary off to %s: %s", target.ID, strings.TrimSpace(res.StdOut+res.StdErr))
+	}
+
+	fmt.Fprintf(out, "Machine %s in %s is now the primary\n", target.ID, target.Region)
+
+	return nil
+}
+
+// appMachines returns the app along with a client of the machines API for it
+// and its started machines.
+func appMachines(ctx context.Context, appName string) (*api.AppCompact, *flaps.Client, []*api.Machine, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	machines, err := startedMachines(ctx, flapsClient)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("%s has %w", appName, err)
+	}
+
+	return app, flapsClient, machines, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
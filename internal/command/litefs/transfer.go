@@ -0,0 +1,224 @@
+package litefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newExport() *cobra.Command {
+	const (
+		long = `Export a database from the primary to a local file, consistent as of its last
+transaction, without stopping the app.`
+		short = "Export a LiteFS database"
+		usage = "export <database>"
+	)
+
+	cmd := command.New(usage, short, long, runExport,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		mountDirFlag(),
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "File to export the database to, named after it when not given",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runExport(ctx context.Context) (err error) {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		name   = flag.FirstArg(ctx)
+		output = flag.GetString(ctx, "output")
+	)
+
+	if output == "" {
+		output = name
+	}
+
+	primary, httpClient, err := connectPrimary(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL(primary, "/export?name="+url.QueryEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed exporting %s: %w", name, err)
+	}
+	defer res.Body.Close()
+
+	if err := checkResponse(res); err != nil {
+		return fmt.Errorf("failed exporting %s: %w", name, err)
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	n, err := io.Copy(f, res.Body)
+	if err != nil {
+		return fmt.Errorf("failed writing %s: %w", output, err)
+	}
+
+	fmt.Fprintf(out, "Exported %s of %s to %s\n", humanize.IBytes(uint64(n)), name, output)
+
+	return nil
+}
+
+func newImport() *cobra.Command {
+	const (
+		long = `Import a local SQLite database file as a database of the primary, replacing
+it when it exists. Replicas pick it up like any other write.`
+		short = "Import a LiteFS database"
+		usage = "import <database> <file>"
+	)
+
+	cmd := command.New(usage, short, long, runImport,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		mountDirFlag(),
+	)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	return cmd
+}
+
+func runImport(ctx context.Context) error {
+	var (
+		out  = iostreams.FromContext(ctx).Out
+		args = flag.Args(ctx)
+		name = args[0]
+		path = args[1]
+	)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Replace %s of %s with %s?", name, app.NameFromContext(ctx), path); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	primary, httpClient, err := connectPrimary(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL(primary, "/import?name="+url.QueryEscape(name)), f)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed importing %s: %w", name, err)
+	}
+	defer res.Body.Close()
+
+	if err := checkResponse(res); err != nil {
+		return fmt.Errorf("failed importing %s: %w", name, err)
+	}
+
+	fmt.Fprintf(out, "Imported %s of %s as %s on %s\n", humanize.IBytes(uint64(info.Size())), path, name, primary.ID)
+
+	return nil
+}
+
+// connectPrimary returns the primary of the app, along with a client of the
+// HTTP API of LiteFS on it.
+func connectPrimary(ctx context.Context) (*api.Machine, *http.Client, error) {
+	var (
+		appName  = app.NameFromContext(ctx)
+		mountDir = flag.GetString(ctx, "mount-dir")
+	)
+
+	app, flapsClient, machines, err := appMachines(ctx, appName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	primary, err := findPrimary(ctx, flapsClient, machines, mountDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	httpClient, err := apiClient(ctx, client.FromContext(ctx).API(), app.Organization.Slug)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return primary, httpClient, nil
+}
+
+func checkResponse(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	if len(body) == 0 {
+		return errors.New(res.Status)
+	}
+
+	return fmt.Errorf("%s: %s", res.Status, body)
+}
@@ -4,7 +4,9 @@ package logs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"regexp"
 	"time"
 
 	"github.com/azazeal/pause"
@@ -12,6 +14,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/logs"
 
@@ -31,6 +34,26 @@ the Fly platform.
 
 Logs can be filtered to a specific instance using the --instance/-i flag or
 to all instances running in a specific region using the --region/-r flag.
+These filters are applied by the Fly platform.
+
+The logs of the machines of a process group are shown with --group, and
+entries can be narrowed down to those at --level or above, to those whose
+message matches the regular expression of --grep, and to those logged between
+--since and --until, which take either a time like 2022-09-01T15:04:05Z or a
+duration ago like 1h. With --until, flyctl exits once it's shown all logs up to
+then. These filters are applied by flyctl, so all the logs of the app, or of
+its region or instance given, are still fetched and filtered as they arrive;
+only a group of a single machine, or of machines in a single region, is
+narrowed down by the Fly platform.
+
+With --format json or logfmt, each entry is written on a line of its own with
+its timestamp, region, machine, instance and level, for piping into tools like
+jq; json also includes the metadata of entries. --format plain writes entries
+like the default format, without colors. --json alone writes entries as JSON
+as they're received.
+
+Logs older than the few minutes kept for tailing can be searched with
+fly logs search, when log retention is configured.
 `
 		short = "View app logs"
 	)
@@ -51,6 +74,39 @@ to all instances running in a specific region using the --region/-r flag.
 			Shorthand:   "i",
 			Description: "Filter by instance ID",
 		},
+		flag.String{
+			Name:        "machine",
+			Description: "Filter by machine ID",
+		},
+		flag.String{
+			Name:        "group",
+			Description: "Filter by the process group of machines; applied by flyctl unless it has a single machine or region",
+		},
+		flag.String{
+			Name:        "level",
+			Description: "Only show entries at this level or above, such as warn; applied by flyctl",
+		},
+		flag.String{
+			Name:        "grep",
+			Description: "Only show entries whose message matches this regular expression; applied by flyctl",
+		},
+		flag.String{
+			Name:        "since",
+			Description: "Only show entries logged since this time or duration ago",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "Only show entries logged until this time or duration ago",
+		},
+		flag.String{
+			Name:        "format",
+			Description: "Output format: json, logfmt or plain",
+		},
+	)
+
+	cmd.AddCommand(
+		newShip(),
+		newSearch(),
 	)
 
 	return
@@ -65,20 +121,196 @@ func run(ctx context.Context) error {
 		VMID:       flag.GetString(ctx, "instance"),
 	}
 
+	if machine := flag.GetString(ctx, "machine"); machine != "" {
+		if opts.VMID != "" {
+			return errors.New("--instance and --machine can't be used together")
+		}
+
+		opts.VMID = machine
+	}
+
+	filter, err := newFilter(ctx, opts)
+	if err != nil {
+		return err
+	}
+	opts.Filter = filter
+
+	renderEntry, err := newRenderer(ctx, opts.AppName)
+	if err != nil {
+		return err
+	}
+
 	var eg *errgroup.Group
 	eg, ctx = errgroup.WithContext(ctx)
 
+	// logs up to a time that's passed are all polled
+	if !filter.Until.IsZero() && filter.Until.Before(time.Now()) {
+		entries := poll(ctx, eg, client, opts)
+
+		eg.Go(func() error {
+			return printStreams(ctx, renderEntry, entries)
+		})
+
+		return eg.Wait()
+	}
+
 	pollingCtx, cancelPolling := context.WithCancel(ctx)
 	pollEntries := poll(pollingCtx, eg, client, opts)
 	liveEntries := nats(ctx, eg, client, opts, cancelPolling)
 
 	eg.Go(func() error {
-		return printStreams(ctx, pollEntries, liveEntries)
+		return printStreams(ctx, renderEntry, pollEntries, liveEntries)
 	})
 
 	return eg.Wait()
 }
 
+// newFilter returns the filter of the --group, --level, --grep, --since and
+// --until flags. When a group has a single machine, opts is narrowed to it
+// instead, so that the platform filters its logs, and to the region of its
+// machines when they share one.
+func newFilter(ctx context.Context, opts *logs.LogOptions) (filter *logs.Filter, err error) {
+	filter = &logs.Filter{}
+
+	if group := flag.GetString(ctx, "group"); group != "" {
+		if opts.VMID != "" {
+			return nil, errors.New("--group can't be used with --instance or --machine")
+		}
+
+		machines, err := groupMachines(ctx, opts.AppName, group)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(machines) == 1 {
+			opts.VMID = machines[0].ID
+		} else {
+			filter.Instances = map[string]bool{}
+			regions := map[string]bool{}
+			for _, machine := range machines {
+				filter.Instances[machine.ID] = true
+				regions[machine.Region] = true
+			}
+
+			if len(regions) == 1 && opts.RegionCode == "" {
+				opts.RegionCode = machines[0].Region
+			}
+		}
+	}
+
+	if level := flag.GetString(ctx, "level"); level != "" {
+		if !logs.ValidLevel(level) {
+			return nil, fmt.Errorf("unknown log level %q, must be one of debug, info, warn or error", level)
+		}
+
+		filter.MinLevel = level
+	}
+
+	if expr := flag.GetString(ctx, "grep"); expr != "" {
+		if filter.Pattern, err = regexp.Compile(expr); err != nil {
+			return nil, fmt.Errorf("invalid --grep expression: %w", err)
+		}
+	}
+
+	if filter.Since, err = flag.GetTime(ctx, "since"); err != nil {
+		return nil, err
+	}
+
+	if filter.Until, err = flag.GetTime(ctx, "until"); err != nil {
+		return nil, err
+	}
+
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Since.Before(filter.Until) {
+		return nil, errors.New("--since must be before --until")
+	}
+
+	return filter, nil
+}
+
+// groupMachines returns the machines of the app in group.
+func groupMachines(ctx context.Context, appName, group string) ([]*api.Machine, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	if app.PlatformVersion != "machines" {
+		return nil, fmt.Errorf("app %s doesn't run on machines, so its logs can't be filtered by --group", appName)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	machines, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	var inGroup []*api.Machine
+	for _, machine := range machines {
+		if machine.Config != nil && machine.Config.Metadata[api.MachineProcessGroupKey] == group {
+			inGroup = append(inGroup, machine)
+		}
+	}
+
+	if len(inGroup) == 0 {
+		return nil, fmt.Errorf("app %s has no machines in process group %s", appName, group)
+	}
+
+	return inGroup, nil
+}
+
+// renderer writes log entries to w.
+type renderer func(w io.Writer, entry logs.LogEntry) error
+
+// newRenderer returns the renderer of --format. Without it, --json renders
+// entries as they're received, as it always has.
+func newRenderer(ctx context.Context, appName string) (renderer, error) {
+	format := flag.GetString(ctx, "format")
+	if format == "" && config.FromContext(ctx).JSONOutput {
+		return func(w io.Writer, entry logs.LogEntry) error {
+			return render.JSON(w, entry)
+		}, nil
+	}
+
+	pretty := func(opts ...render.LogOption) renderer {
+		opts = append(opts, render.HideAllocID(), render.RemoveNewlines(), render.HideRegion())
+
+		return func(w io.Writer, entry logs.LogEntry) error {
+			return render.LogEntry(w, entry, opts...)
+		}
+	}
+
+	switch format {
+	case "":
+		return pretty(), nil
+	case "plain":
+		return pretty(render.NoColor()), nil
+	case "json", "logfmt":
+		break
+	default:
+		return nil, fmt.Errorf("unknown format %q, must be one of json, logfmt or plain", format)
+	}
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed fetching app: %w", err)
+	}
+	machines := app.PlatformVersion == "machines"
+
+	if format == "json" {
+		return func(w io.Writer, entry logs.LogEntry) error {
+			return render.LogEntryJSON(w, entry, machines)
+		}, nil
+	}
+
+	return func(w io.Writer, entry logs.LogEntry) error {
+		return render.LogEntryLogfmt(w, entry, machines)
+	}, nil
+}
+
 func poll(ctx context.Context, eg *errgroup.Group, client *api.Client, opts *logs.LogOptions) <-chan logs.LogEntry {
 	c := make(chan logs.LogEntry)
 
@@ -128,25 +360,24 @@ func nats(ctx context.Context, eg *errgroup.Group, client *api.Client, opts *log
 	return c
 }
 
-func printStreams(ctx context.Context, streams ...<-chan logs.LogEntry) error {
+func printStreams(ctx context.Context, renderEntry renderer, streams ...<-chan logs.LogEntry) error {
 	var eg *errgroup.Group
 	eg, ctx = errgroup.WithContext(ctx)
 
 	out := iostreams.FromContext(ctx).Out
-	json := config.FromContext(ctx).JSONOutput
 
 	for _, stream := range streams {
 		stream := stream
 
 		eg.Go(func() error {
-			return printStream(ctx, out, stream, json)
+			return printStream(ctx, out, stream, renderEntry)
 		})
 	}
 
 	return eg.Wait()
 }
 
-func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry, json bool) error {
+func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry, renderEntry renderer) error {
 	for {
 		select {
 		case <-ctx.Done():
@@ -156,18 +387,7 @@ func printStream(ctx context.Context, w io.Writer, stream <-chan logs.LogEntry,
 				return nil
 			}
 
-			var err error
-			if json {
-				err = render.JSON(w, entry)
-			} else {
-				err = render.LogEntry(w, entry,
-					render.HideAllocID(),
-					render.RemoveNewlines(),
-					render.HideRegion(),
-				)
-			}
-
-			if err != nil {
+			if err := renderEntry(w, entry); err != nil {
 				return err
 			}
 		}
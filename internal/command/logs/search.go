@@ -0,0 +1,200 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+	"github.com/superfly/flyctl/logs"
+)
+
+func newSearch() *cobra.Command {
+	const (
+		long = `Search the stored logs of an application, rather than only the recent and
+live ones fly logs shows, so that what happened during an incident can be
+looked into after the fact. Searching requires the organization of the app to
+have log retention configured.
+
+Entries are matched against the full text of the query, when given, and
+narrowed down to those logged between --since and --until, which take either a
+time like 2022-09-01T15:04:05Z or a duration ago like 1h. --field matches the
+value of a field of entries, like --field http.response.status_code=500, and
+may be repeated.
+
+Matching entries are shown oldest first, in the formats of fly logs.
+`
+		short = "Search the stored logs of an app"
+		usage = "search [query]"
+	)
+
+	cmd := command.New(usage, short, long, runSearch,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Region(),
+		flag.String{
+			Name:        "instance",
+			Shorthand:   "i",
+			Description: "Filter by instance ID",
+		},
+		flag.String{
+			Name:        "machine",
+			Description: "Filter by machine ID",
+		},
+		flag.String{
+			Name:        "level",
+			Description: "Only show entries at this level or above, such as warn",
+		},
+		flag.StringSlice{
+			Name:        "field",
+			Description: "Only show entries whose field has this value, like key=value",
+		},
+		flag.String{
+			Name:        "since",
+			Description: "Only show entries logged since this time or duration ago",
+			Default:     "1h",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "Only show entries logged until this time or duration ago",
+		},
+		flag.Int{
+			Name:        "limit",
+			Description: "The most entries to show",
+			Default:     500,
+		},
+		flag.String{
+			Name:        "format",
+			Description: "Output format: json, logfmt or plain",
+		},
+	)
+
+	return cmd
+}
+
+func runSearch(ctx context.Context) error {
+	var (
+		appName = app.NameFromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		out     = iostreams.FromContext(ctx).Out
+		limit   = flag.GetInt(ctx, "limit")
+	)
+
+	if limit < 1 {
+		return errors.New("--limit must be at least 1")
+	}
+
+	search := api.LogSearch{
+		Query:    flag.FirstArg(ctx),
+		Region:   config.FromContext(ctx).Region,
+		Instance: flag.GetString(ctx, "instance"),
+		Level:    flag.GetString(ctx, "level"),
+		Limit:    limit,
+	}
+
+	if machine := flag.GetString(ctx, "machine"); machine != "" {
+		if search.Instance != "" {
+			return errors.New("--instance and --machine can't be used together")
+		}
+
+		search.Instance = machine
+	}
+
+	if search.Level != "" && !logs.ValidLevel(search.Level) {
+		return fmt.Errorf("unknown log level %q, must be one of debug, info, warn or error", search.Level)
+	}
+
+	for _, field := range flag.GetStringSlice(ctx, "field") {
+		name, value, ok := strings.Cut(field, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid --field %q, must be like key=value", field)
+		}
+
+		if search.Fields == nil {
+			search.Fields = map[string]string{}
+		}
+		search.Fields[name] = value
+	}
+
+	var err error
+	if search.Since, err = flag.GetTime(ctx, "since"); err != nil {
+		return err
+	}
+
+	if search.Until, err = flag.GetTime(ctx, "until"); err != nil {
+		return err
+	}
+
+	if !search.Until.IsZero() && !search.Since.Before(search.Until) {
+		return errors.New("--since must be before --until")
+	}
+
+	renderEntry, err := newRenderer(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	var (
+		shown int
+		token string
+	)
+
+	for shown < limit {
+		search.Limit = limit - shown
+
+		entries, next, err := client.SearchAppLogs(ctx, appName, search, token)
+		switch {
+		case api.IsNotFoundError(err):
+			return fmt.Errorf("the logs of %s can't be searched, since its organization has no log retention configured", appName)
+		case err != nil:
+			return fmt.Errorf("failed searching logs: %w", err)
+		}
+
+		for _, e := range entries {
+			if shown == limit {
+				break
+			}
+
+			entry := logs.LogEntry{
+				Instance:  e.Instance,
+				Level:     e.Level,
+				Message:   e.Message,
+				Region:    e.Region,
+				Timestamp: e.Timestamp,
+				Meta:      e.Meta,
+			}
+
+			if err := renderEntry(out, entry); err != nil {
+				return err
+			}
+			shown++
+		}
+
+		if next == "" || len(entries) == 0 {
+			break
+		}
+		token = next
+	}
+
+	if shown == 0 {
+		fmt.Fprintln(iostreams.FromContext(ctx).ErrOut, "No matching log entries")
+	}
+
+	return nil
+}
@@ -0,0 +1,527 @@
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/cmdutil"
+	"github.com/superfly/flyctl/internal/command"
+	machines "github.com/superfly/flyctl/internal/command/machine"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// shipperImage is the image of the log shipper, which reads the logs of an
+// organization from NATS and ships them with Vector.
+const shipperImage = "flyio/log-shipper:latest"
+
+// sink describes the settings of a destination the log shipper supports,
+// which are stored as secrets of the shipper app.
+type sink struct {
+	name     string
+	required []string
+	optional []string
+}
+
+var sinks = []sink{
+	{name: "datadog", required: []string{"DATADOG_API_KEY"}, optional: []string{"DATADOG_SITE"}},
+	{name: "http", required: []string{"HTTP_URL", "HTTP_TOKEN"}},
+	{name: "loki", required: []string{"LOKI_URL", "LOKI_USERNAME", "LOKI_PASSWORD"}},
+	{name: "s3", required: []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_BUCKET", "AWS_REGION"}, optional: []string{"S3_ENDPOINT"}},
+}
+
+func sinkNames() []string {
+	names := make([]string, 0, len(sinks))
+	for _, s := range sinks {
+		names = append(names, s.name)
+	}
+
+	return names
+}
+
+func newShip() *cobra.Command {
+	const (
+		long = `Ship the logs of an organization, or of one of its apps, to Datadog, Loki,
+S3 or an HTTP endpoint. Logs are shipped by a log shipper app, named
+<org>-log-shipper by default, which runs the flyio/log-shipper image.
+`
+		short = "Ship logs to external services"
+	)
+
+	cmd := command.New("ship", short, long, nil)
+
+	cmd.AddCommand(
+		newShipSetup(),
+		newShipStatus(),
+		newShipDestroy(),
+	)
+
+	return cmd
+}
+
+var shipperFlags = flag.Set{
+	flag.Org(),
+	flag.String{
+		Name:        "name",
+		Description: "Name of the log shipper app, defaults to <org>-log-shipper",
+	},
+}
+
+func newShipSetup() *cobra.Command {
+	const (
+		long = `Set up shipping logs to a sink, creating the log shipper app of the
+organization if it doesn't exist yet. The settings of the sink are given as
+NAME=VALUE arguments, and prompted for when missing:
+
+  datadog  DATADOG_API_KEY, and optionally DATADOG_SITE
+  http     HTTP_URL and HTTP_TOKEN
+  loki     LOKI_URL, LOKI_USERNAME and LOKI_PASSWORD
+  s3       AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_BUCKET, AWS_REGION,
+           and optionally S3_ENDPOINT
+
+Settings are stored as secrets of the log shipper app, along with the access
+token it reads logs with. Unless --shipper-token is set, a read-only token is
+created for it, limited to the app of --source-app when given, which can be
+revoked with fly tokens revoke. Creating it needs the API to serve scoped
+tokens, which it doesn't everywhere yet; otherwise, pass --shipper-token.
+Running setup again with another sink ships logs to both.
+`
+		short = "Set up shipping logs to a sink"
+		usage = "setup <datadog|http|loki|s3> [NAME=VALUE...]"
+	)
+
+	cmd := command.New(usage, short, long, runShipSetup, command.RequireSession)
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	flag.Add(cmd,
+		shipperFlags,
+		flag.Region(),
+		flag.String{
+			Name:        "source-app",
+			Description: "Only ship the logs of this app, rather than of the whole organization",
+		},
+		flag.String{
+			Name:        "shipper-token",
+			Description: "Access token the log shipper reads logs with, defaults to a new read-only token",
+		},
+	)
+
+	return cmd
+}
+
+// createShipperToken creates the read-only token the log shipper of org reads
+// logs with, limited to the app of --source-app when given, rather than
+// handing it the token of the user.
+func createShipperToken(ctx context.Context, apiClient *api.Client, org *api.Organization) (string, error) {
+	if err := command.CheckAPI(ctx, "CreateAccessTokenInput", "scope"); err != nil {
+		return "", fmt.Errorf("can't create a read-only token for the log shipper, pass one with --shipper-token: %w", err)
+	}
+
+	input := api.CreateAccessTokenInput{
+		OrganizationID: org.ID,
+		Name:           "log shipper of " + org.Slug,
+		Scope:          "READ_ONLY",
+	}
+
+	if sourceApp := flag.GetString(ctx, "source-app"); sourceApp != "" {
+		app, err := apiClient.GetAppCompact(ctx, sourceApp)
+		if err != nil {
+			return "", fmt.Errorf("failed retrieving app %s: %w", sourceApp, err)
+		}
+
+		input.AppID = app.ID
+		input.Name = "log shipper of " + sourceApp
+	}
+
+	token, secret, err := apiClient.CreateAccessToken(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed creating a read-only token for the log shipper: %w", err)
+	}
+
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "Created read-only token %s for the log shipper\n", token.ID)
+
+	return secret, nil
+}
+
+func runShipSetup(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		args      = flag.Args(ctx)
+	)
+
+	var target *sink
+	for i := range sinks {
+		if sinks[i].name == args[0] {
+			target = &sinks[i]
+		}
+	}
+
+	if target == nil {
+		return fmt.Errorf("unknown sink %s, must be one of %s", args[0], strings.Join(sinkNames(), ", "))
+	}
+
+	secrets, err := cmdutil.ParseKVStringsToMap(args[1:])
+	if err != nil {
+		return fmt.Errorf("could not parse sink settings: %w", err)
+	}
+
+	known := append(append([]string{}, target.required...), target.optional...)
+	for name := range secrets {
+		if !lo.Contains(known, name) {
+			return fmt.Errorf("unknown %s setting %s, must be one of %s", target.name, name, strings.Join(known, ", "))
+		}
+	}
+
+	for _, name := range target.required {
+		if secrets[name] != "" {
+			continue
+		}
+
+		var value string
+		switch err := prompt.Password(ctx, &value, name+":", true); {
+		case prompt.IsNonInteractive(err):
+			return fmt.Errorf("%s must be given as %s=VALUE when not running interactively", name, name)
+		case err != nil:
+			return err
+		}
+
+		secrets[name] = value
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	secrets["ORG"] = org.Slug
+	if secrets["ACCESS_TOKEN"] = flag.GetString(ctx, "shipper-token"); secrets["ACCESS_TOKEN"] == "" {
+		if secrets["ACCESS_TOKEN"], err = createShipperToken(ctx, apiClient, org); err != nil {
+			return err
+		}
+	}
+
+	if sourceApp := flag.GetString(ctx, "source-app"); sourceApp != "" {
+		secrets["SUBJECT"] = fmt.Sprintf("logs.%s.>", sourceApp)
+	}
+
+	name := shipperName(ctx, org.Slug)
+
+	app, err := apiClient.GetAppCompact(ctx, name)
+	switch {
+	case err != nil && strings.Contains(err.Error(), "Could not find App"):
+		if app, err = createShipperApp(ctx, apiClient, org, name); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case app.PlatformVersion != "machines":
+		return fmt.Errorf("app %s doesn't run on machines, so it can't be used as a log shipper", name)
+	}
+
+	fmt.Fprintf(io.Out, "Setting the %s settings as secrets of %s\n", target.name, name)
+
+	if _, err := apiClient.SetSecrets(ctx, name, secrets); err != nil {
+		return fmt.Errorf("failed setting secrets: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	if err := launchOrUpdateShipper(ctx, flapsClient, app); err != nil {
+		return err
+	}
+
+	source := "organization " + org.Slug
+	if sourceApp := flag.GetString(ctx, "source-app"); sourceApp != "" {
+		source = "app " + sourceApp
+	}
+
+	fmt.Fprintf(io.Out, "Shipping the logs of %s to %s\n", source, target.name)
+	fmt.Fprintf(io.Out, "Check on the log shipper with fly logs ship status --name %s\n", name)
+
+	return nil
+}
+
+func createShipperApp(ctx context.Context, apiClient *api.Client, org *api.Organization, name string) (*api.AppCompact, error) {
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "Creating log shipper app %s\n", name)
+
+	app, err := apiClient.CreateApp(ctx, api.CreateAppInput{
+		Name:           name,
+		OrganizationID: org.ID,
+		Machines:       true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating app %s: %w", name, err)
+	}
+
+	return &api.AppCompact{
+		ID:              app.ID,
+		Name:            app.Name,
+		PlatformVersion: "machines",
+		Organization: &api.OrganizationBasic{
+			ID:   app.Organization.ID,
+			Slug: app.Organization.Slug,
+		},
+	}, nil
+}
+
+// launchOrUpdateShipper launches the machine of the log shipper, or updates
+// the existing ones so that they pick up the secrets set.
+func launchOrUpdateShipper(ctx context.Context, flapsClient *flaps.Client, app *api.AppCompact) error {
+	out := iostreams.FromContext(ctx).Out
+
+	existing, err := flapsClient.List(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	if len(existing) == 0 {
+		machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+			AppID:  app.Name,
+			Region: flag.GetRegion(ctx),
+			Config: &api.MachineConfig{
+				Image: shipperImage,
+				Guest: &api.MachineGuest{
+					CPUKind:  "shared",
+					CPUs:     1,
+					MemoryMB: 256,
+				},
+				Metadata: map[string]string{
+					"fly_log_shipper": "true",
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed launching log shipper: %w", err)
+		}
+
+		fmt.Fprintf(out, "Launched log shipper machine %s in %s\n", machine.ID, machine.Region)
+
+		return machines.WaitForStartOrStop(ctx, machine, "start", 5*time.Minute)
+	}
+
+	for _, machine := range existing {
+		machine.Config.Image = shipperImage
+
+		updated, err := flapsClient.Update(ctx, api.LaunchMachineInput{
+			ID:     machine.ID,
+			AppID:  app.Name,
+			Region: machine.Region,
+			Config: machine.Config,
+		}, "")
+		if err != nil {
+			return fmt.Errorf("failed updating log shipper: %w", err)
+		}
+
+		fmt.Fprintf(out, "Updated log shipper machine %s\n", updated.ID)
+
+		if err := machines.WaitForStartOrStop(ctx, updated, "start", 5*time.Minute); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newShipStatus() *cobra.Command {
+	const (
+		long = `Show the sinks the log shipper ships logs to, the state of its machines, and
+the warnings and errors it logged recently.
+`
+		short = "Show the health of the log shipper"
+		usage = "status"
+	)
+
+	cmd := command.New(usage, short, long, runShipStatus, command.RequireSession)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, shipperFlags)
+
+	return cmd
+}
+
+type shipperStatus struct {
+	App      string
+	Sinks    []string
+	Machines []*api.Machine
+	Problems []string
+}
+
+func runShipStatus(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := shipperName(ctx, org.Slug)
+
+	app, err := apiClient.GetAppCompact(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed fetching log shipper app %s: %w", name, err)
+	}
+
+	secrets, err := apiClient.GetAppSecrets(ctx, name)
+	if err != nil {
+		return fmt.Errorf("failed fetching secrets: %w", err)
+	}
+
+	status := shipperStatus{App: name}
+
+	set := map[string]bool{}
+	for _, secret := range secrets {
+		set[secret.Name] = true
+	}
+
+	for _, s := range sinks {
+		configured := true
+		for _, name := range s.required {
+			configured = configured && set[name]
+		}
+
+		if configured {
+			status.Sinks = append(status.Sinks, s.name)
+		}
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	if status.Machines, err = flapsClient.List(ctx, ""); err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	// the latest page of logs of the shipper holds its recent problems, such
+	// as a sink rejecting its credentials
+	entries, _, err := apiClient.GetAppLogs(ctx, name, "", "", "")
+	if err != nil {
+		return fmt.Errorf("failed fetching logs of the log shipper: %w", err)
+	}
+
+	for _, entry := range entries {
+		if level := strings.ToLower(entry.Level); level == "warn" || level == "warning" || level == "error" {
+			status.Problems = append(status.Problems, fmt.Sprintf("%s [%s] %s", entry.Timestamp, entry.Level, entry.Message))
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, status)
+	}
+
+	sinks := "none, run fly logs ship setup"
+	if len(status.Sinks) > 0 {
+		sort.Strings(status.Sinks)
+		sinks = strings.Join(status.Sinks, ", ")
+	}
+
+	fmt.Fprintf(io.Out, "%-6s: %s\n", "App", status.App)
+	fmt.Fprintf(io.Out, "%-6s: %s\n\n", "Sinks", sinks)
+
+	rows := make([][]string, 0, len(status.Machines))
+	for _, machine := range status.Machines {
+		rows = append(rows, []string{machine.ID, machine.State, machine.Region, machine.UpdatedAt})
+	}
+
+	if err := render.Table(io.Out, "Machines", rows, "ID", "State", "Region", "Updated"); err != nil {
+		return err
+	}
+
+	if len(status.Problems) == 0 {
+		fmt.Fprintln(io.Out, "No recent warnings or errors")
+
+		return nil
+	}
+
+	fmt.Fprintln(io.Out, "Recent warnings and errors:")
+	for _, problem := range status.Problems {
+		fmt.Fprintf(io.Out, "  %s\n", problem)
+	}
+
+	return nil
+}
+
+func newShipDestroy() *cobra.Command {
+	const (
+		long = `Stop shipping logs by destroying the log shipper app, along with the sink
+settings stored as its secrets.
+`
+		short = "Destroy the log shipper"
+		usage = "destroy"
+	)
+
+	cmd := command.New(usage, short, long, runShipDestroy, command.RequireSession)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, shipperFlags, flag.Yes())
+
+	return cmd
+}
+
+func runShipDestroy(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	name := shipperName(ctx, org.Slug)
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy log shipper app %s and stop shipping logs?", name); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return errors.New("--yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := apiClient.DeleteApp(ctx, name); err != nil {
+		return fmt.Errorf("failed destroying %s: %w", name, err)
+	}
+
+	fmt.Fprintf(io.Out, "Destroyed log shipper app %s\n", name)
+
+	return nil
+}
+
+func shipperName(ctx context.Context, org string) string {
+	if name := flag.GetString(ctx, "name"); name != "" {
+		return name
+	}
+
+	return org + "-log-shipper"
+}
@@ -0,0 +1,130 @@
+package machine
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// consoleProblems match the console lines that explain why a machine didn't
+// reach its process, along with the hint shown once they've been seen.
+var consoleProblems = []struct {
+	pattern *regexp.Regexp
+	hint    string
+}{
+	{
+		pattern: regexp.MustCompile(`(?i)out of memory|oom[-_ ]kill`),
+		hint:    "the machine ran out of memory; give it more with fly machine update --memory",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)kernel panic|init.*panic|panicked at|^panic:`),
+		hint:    "the machine's init or kernel panicked before the process started",
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)no such file or directory|exec format error|permission denied`),
+		hint:    "the machine's command couldn't be run; check the image's entrypoint and cmd",
+	},
+}
+
+func newConsole() *cobra.Command {
+	const (
+		short = "Show the serial console output of a machine"
+		long  = short + `
+
+Shows what the machine wrote to its serial console since it last booted: the
+kernel and init messages, OOM killer messages and panics, and the output of
+its process from before its logs start shipping. This is where to look when a
+machine never reaches its process, which fly logs can't show.
+
+Lines reporting problems are highlighted.
+`
+		usage = "console <id>"
+	)
+
+	cmd := command.New(usage, short, long, runMachineConsole,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Bool{
+			Name:        "follow",
+			Shorthand:   "f",
+			Description: "Keep streaming the console output until the machine stops",
+		},
+	)
+
+	return cmd
+}
+
+func runMachineConsole(ctx context.Context) error {
+	var (
+		appName   = app.NameFromContext(ctx)
+		machineID = flag.FirstArg(ctx)
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+	)
+
+	app, err := appFromMachineOrName(ctx, machineID, appName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	console, err := flapsClient.Console(ctx, machineID, flag.GetBool(ctx, "follow"))
+	if err != nil {
+		return err
+	}
+	defer console.Close()
+
+	seen := make([]bool, len(consoleProblems))
+
+	scanner := bufio.NewScanner(console)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		problem := false
+		for i, p := range consoleProblems {
+			if p.pattern.MatchString(line) {
+				seen[i], problem = true, true
+			}
+		}
+
+		if problem {
+			line = colorize.Red(line)
+		}
+
+		fmt.Fprintln(io.Out, line)
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed reading the console of machine %s: %w", machineID, err)
+	}
+
+	for i, p := range consoleProblems {
+		if seen[i] {
+			fmt.Fprintf(io.ErrOut, "%s %s\n", colorize.Yellow("Hint:"), p.hint)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package machine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		short = "Create, but don't start, a machine"
+		long  = `Create a machine without starting it.
+
+With --standby-for, the machine stands by for the machines given: it's kept
+stopped, and the platform starts it when any of them becomes unhealthy, which
+makes for cheap failover of single machine apps. Unless an image is given, the
+config of the first of them is copied, without its volumes, into the region it
+runs in.`
+
+		usage = "create [image] [command]"
+	)
+
+	cmd := command.New(usage, short, long, runCreate,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	flag.Add(
+		cmd,
+		flag.Region(),
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "Machine name, will be generated if missing",
+		},
+		flag.StringSlice{
+			Name:        "standby-for",
+			Description: "IDs of the machines the machine stands by for, started when any of them becomes unhealthy",
+		},
+		sharedFlags,
+	)
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		io         = iostreams.FromContext(ctx)
+		colorize   = io.ColorScheme()
+		appName    = app.NameFromContext(ctx)
+		standbyFor = flag.GetStringSlice(ctx, "standby-for")
+		region     = flag.GetString(ctx, "region")
+	)
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make API client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	var primaries []*api.Machine
+	for _, id := range standbyFor {
+		primary, err := flapsClient.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed retrieving machine %s to stand by for: %w", id, err)
+		}
+
+		if len(primary.Config.Standbys) > 0 {
+			return fmt.Errorf("machine %s is a standby itself, so can't be stood by for", id)
+		}
+
+		primaries = append(primaries, primary)
+	}
+
+	var machineConf api.MachineConfig
+
+	if len(flag.Args(ctx)) == 0 {
+		if len(primaries) == 0 {
+			return errors.New("an image must be given, unless the machine stands by for others with --standby-for")
+		}
+
+		primary := primaries[0]
+
+		machineConf = *primary.Config
+		machineConf.Mounts = nil
+		if region == "" {
+			region = primary.Region
+		}
+	} else {
+		machineConf = api.MachineConfig{
+			Guest: &api.MachineGuest{
+				CPUKind:    "shared",
+				CPUs:       1,
+				MemoryMB:   256,
+				KernelArgs: flag.GetStringSlice(ctx, "kernel-arg"),
+			},
+		}
+
+		if machineConf, err = determineMachineConfig(ctx, machineConf, app, flag.FirstArg(ctx)); err != nil {
+			return err
+		}
+
+		if flag.GetBool(ctx, "build-only") {
+			return nil
+		}
+	}
+
+	machineConf.Standbys = standbyFor
+
+	if err := checkGPU(ctx, &machineConf, region); err != nil {
+		return err
+	}
+
+	machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:      app.Name,
+		Name:       flag.GetString(ctx, "name"),
+		Region:     region,
+		Config:     &machineConf,
+		SkipLaunch: true,
+	})
+	if err != nil {
+		return fmt.Errorf("could not create machine: %w", err)
+	}
+
+	fmt.Fprintf(io.Out, "Machine %s was created in %s, and isn't started\n", colorize.Bold(machine.ID), machine.Region)
+	if len(standbyFor) > 0 {
+		fmt.Fprintf(io.Out, "It stands by for %v, and is started when any of them becomes unhealthy\n", standbyFor)
+	}
+
+	return nil
+}
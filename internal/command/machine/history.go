@@ -0,0 +1,171 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// historyMetrics maps metric names to the PromQL query returning them for a
+// machine, and a formatter for their values.
+var historyMetrics = map[string]struct {
+	query  string
+	format func(float64) string
+}{
+	"memory": {
+		query: `sum(fly_instance_memory_mem_total{app=%[1]q,instance=%[2]q} - fly_instance_memory_mem_available{app=%[1]q,instance=%[2]q})`,
+		format: func(v float64) string {
+			return humanize.IBytes(uint64(v))
+		},
+	},
+	"cpu": {
+		// fly_instance_cpu counts centiseconds so its rate is a percentage
+		query: `sum(rate(fly_instance_cpu{app=%[1]q,instance=%[2]q,mode!="idle"}[5m]))`,
+		format: func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v)
+		},
+	},
+}
+
+func newHistory() *cobra.Command {
+	const (
+		short = "Show the resource usage history of a machine"
+		long  = short + `
+
+Renders the CPU or memory usage of the machine over the given period as a
+sparkline, followed by a table of hourly averages.
+`
+		usage = "history <id>"
+	)
+
+	cmd := command.New(usage, short, long, runMachineHistory,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "metric",
+			Description: "Metric to show. Options are memory or cpu",
+			Default:     "memory",
+		},
+		flag.Duration{
+			Name:        "since",
+			Description: "How far back to show usage for",
+			Default:     24 * time.Hour,
+		},
+	)
+
+	return cmd
+}
+
+func runMachineHistory(ctx context.Context) (err error) {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		client    = client.FromContext(ctx).API()
+		machineID = flag.FirstArg(ctx)
+		appName   = app.NameFromContext(ctx)
+		name      = flag.GetString(ctx, "metric")
+		since     = flag.GetDuration(ctx, "since")
+	)
+
+	metric, ok := historyMetrics[name]
+	if !ok {
+		return fmt.Errorf("unsupported metric %q; use memory or cpu", name)
+	}
+
+	if since < time.Hour {
+		return fmt.Errorf("--since must be at least 1h, got %s", since)
+	}
+
+	app, err := appFromMachineOrName(ctx, machineID, appName)
+	if err != nil {
+		return fmt.Errorf("could not get app: %w", err)
+	}
+
+	end := time.Now()
+	start := end.Add(-since)
+	step := since / 120
+
+	samples, err := client.QueryMetricsRange(ctx, app.Organization.Slug, fmt.Sprintf(metric.query, app.Name, machineID), start, end, step)
+	if err != nil {
+		return fmt.Errorf("failed querying metrics: %w", err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, samples)
+	}
+
+	if len(samples) == 0 {
+		fmt.Fprintf(out, "No %s metrics found for machine %s over the last %s\n", name, machineID, since)
+		return nil
+	}
+
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = sample.Value
+	}
+
+	low, high, sum := math.Inf(1), math.Inf(-1), 0.0
+	for _, v := range values {
+		low, high, sum = math.Min(low, v), math.Max(high, v), sum+v
+	}
+
+	fmt.Fprintf(out, "%s usage of machine %s over the last %s\n\n", name, machineID, since)
+	fmt.Fprintf(out, "  %s\n\n", render.Sparkline(values))
+	fmt.Fprintf(out, "  min %s  avg %s  max %s  latest %s\n\n",
+		metric.format(low), metric.format(sum/float64(len(values))), metric.format(high), metric.format(values[len(values)-1]))
+
+	var rows [][]string
+	for _, bucket := range hourlyAverages(samples) {
+		rows = append(rows, []string{
+			bucket.Time.Local().Format("Jan 02 15:04"),
+			metric.format(bucket.Value),
+		})
+	}
+
+	return render.Table(out, "", rows, "Hour", "Average")
+}
+
+// hourlyAverages averages samples by the hour they fall in.
+func hourlyAverages(samples []api.MetricSample) (buckets []api.MetricSample) {
+	var count int
+
+	for _, sample := range samples {
+		hour := sample.Time.Truncate(time.Hour)
+
+		if n := len(buckets); n == 0 || !buckets[n-1].Time.Equal(hour) {
+			if n > 0 {
+				buckets[n-1].Value /= float64(count)
+			}
+			buckets = append(buckets, api.MetricSample{Time: hour})
+			count = 0
+		}
+
+		buckets[len(buckets)-1].Value += sample.Value
+		count++
+	}
+
+	if n := len(buckets); n > 0 {
+		buckets[n-1].Value /= float64(count)
+	}
+
+	return
+}
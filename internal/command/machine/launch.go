@@ -310,10 +310,12 @@ func setScannerPrefs(ctx context.Context, appConfig *app.Config, srcInfo *scanne
 			} else if secret.Value != "" {
 				val = secret.Value
 			} else {
-				prompt := fmt.Sprintf("Set secret %s:", secret.Key)
+				if !iostreams.FromContext(ctx).CanPrompt() {
+					return prompt.NonInteractiveError(fmt.Sprintf("secret %s must be set when not running interactively", secret.Key))
+				}
 
 				surveyInput := &survey.Input{
-					Message: prompt,
+					Message: fmt.Sprintf("Set secret %s:", secret.Key),
 					Help:    secret.Help,
 				}
 
@@ -395,13 +397,15 @@ func installFiles(ctx context.Context, dir string, srcInfo *scanner.SourceInfo)
 	for _, f := range srcInfo.Files {
 		path := filepath.Join(dir, f.Path)
 
-		overwrite, err := prompt.ConfirmOverwrite(ctx, path)
-		if err != nil {
-			return err
-		}
+		if helpers.FileExists(path) {
+			overwrite, err := prompt.ConfirmOverwrite(ctx, path)
+			if err != nil {
+				return err
+			}
 
-		if helpers.FileExists(path) && !overwrite {
-			continue
+			if !overwrite {
+				continue
+			}
 		}
 
 		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
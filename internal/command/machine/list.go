@@ -3,14 +3,18 @@ package machine
 import (
 	"context"
 	"fmt"
+	"io"
+	"sort"
 
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/parallel"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
@@ -18,7 +22,15 @@ import (
 func newList() *cobra.Command {
 	const (
 		short = "List Fly machines"
-		long  = short + "\n"
+		long  = short + `.
+
+Without an app, --org lists the machines of all the apps of an organization
+instead, fetching those of several apps at once and showing those of each app
+as soon as they're fetched.
+
+With --watch, the list is refreshed every --interval, highlighting what
+changed.
+`
 
 		usage = "list"
 	)
@@ -35,6 +47,9 @@ func newList() *cobra.Command {
 		cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Org(),
+		flag.Watch(),
+		flag.Format(),
 		flag.Bool{
 			Name:        "quiet",
 			Shorthand:   "q",
@@ -49,41 +64,67 @@ func runMachineList(ctx context.Context) (err error) {
 	var (
 		appName = app.NameFromContext(ctx)
 		client  = client.FromContext(ctx).API()
-		io      = iostreams.FromContext(ctx)
-		silence = flag.GetBool(ctx, "quiet")
-		cfg     = config.FromContext(ctx)
+		title   string
+		list    func(context.Context, io.Writer) error
 	)
 
 	if appName == "" {
-		return fmt.Errorf("app is not found")
-	}
-	app, err := client.GetAppCompact(ctx, appName)
-	if err != nil {
-		return err
+		org := flag.GetString(ctx, flag.OrgName)
+		if org == "" {
+			return fmt.Errorf("app is not found")
+		}
+
+		title = "Machines of " + org
+		list = func(ctx context.Context, w io.Writer) error {
+			return listOrgMachines(ctx, w, org)
+		}
+	} else {
+		app, err := client.GetAppCompact(ctx, appName)
+		if err != nil {
+			return err
+		}
+		flapsClient, err := flaps.New(ctx, app)
+		if err != nil {
+			return fmt.Errorf("list of machines could not be retrieved: %w", err)
+		}
+
+		title = "Machines of " + appName
+		list = func(ctx context.Context, w io.Writer) error {
+			return listAppMachines(ctx, w, appName, flapsClient)
+		}
 	}
-	flapsClient, err := flaps.New(ctx, app)
-	if err != nil {
-		return fmt.Errorf("list of machines could not be retrieved: %w", err)
+
+	if flag.GetBool(ctx, "watch") {
+		return render.Watch(ctx, flag.GetDuration(ctx, "interval"), title, list)
 	}
 
+	return list(ctx, iostreams.FromContext(ctx).Out)
+}
+
+func listAppMachines(ctx context.Context, w io.Writer, appName string, flapsClient *flaps.Client) error {
+	var (
+		streams = iostreams.FromContext(ctx)
+		silence = flag.GetBool(ctx, "quiet")
+	)
+
 	machines, err := flapsClient.List(ctx, "")
 	if err != nil {
 		return fmt.Errorf("machines could not be retrieved")
 	}
 
-	if cfg.JSONOutput {
-		return render.JSON(io.Out, machines)
+	if ok, err := render.Structured(ctx, w, machines); ok {
+		return err
 	}
 
 	rows := [][]string{}
 
-	listOfMachinesLink := io.CreateLink("View them in the UI here", fmt.Sprintf("https://fly.io/apps/%s/machines/", appName))
-	fmt.Fprintf(io.Out, "%d machines have been retrieved.\n%s\n\n", len(machines), listOfMachinesLink)
+	listOfMachinesLink := streams.CreateLink("View them in the UI here", fmt.Sprintf("https://fly.io/apps/%s/machines/", appName))
+	fmt.Fprintf(w, "%d machines have been retrieved.\n%s\n\n", len(machines), listOfMachinesLink)
 	if silence {
 		for _, machine := range machines {
 			rows = append(rows, []string{machine.ID})
 		}
-		_ = render.Table(io.Out, appName, rows, "ID")
+		_ = render.Table(w, appName, rows, "ID")
 	} else {
 		for _, machine := range machines {
 			var volName string
@@ -104,7 +145,153 @@ func runMachineList(ctx context.Context) (err error) {
 			})
 		}
 
-		_ = render.Table(io.Out, appName, rows, "ID", "Name", "State", "Region", "Image", "IP Address", "Volume", "Created", "Last Updated")
+		_ = render.Table(w, appName, rows, "ID", "Name", "State", "Region", "Image", "IP Address", "Volume", "Created", "Last Updated")
 	}
 	return nil
 }
+
+// orgMachines are the machines of an app of an organization.
+type orgMachines struct {
+	App      string
+	Machines []*api.Machine
+	Error    string `json:",omitempty"`
+}
+
+// listOrgMachines lists the machines of the machines apps of org, fetching
+// those of parallel.Limit apps at once. Apps whose machines can't be fetched
+// are reported without failing the others.
+func listOrgMachines(ctx context.Context, w io.Writer, org string) error {
+	client := client.FromContext(ctx).API()
+
+	apps, err := client.GetApps(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed listing apps: %w", err)
+	}
+
+	var results []*orgMachines
+	for _, app := range apps {
+		if app.Organization.Slug == org && app.PlatformVersion == "machines" {
+			results = append(results, &orgMachines{App: app.Name})
+		}
+	}
+
+	fetch := func(r *orgMachines) {
+		flapsClient, err := flaps.New(ctx, &api.AppCompact{
+			Name:            r.App,
+			PlatformVersion: "machines",
+			Organization:    &api.OrganizationBasic{Slug: org},
+		})
+		if err == nil {
+			r.Machines, err = flapsClient.List(ctx, "")
+		}
+		if err != nil {
+			r.Error = err.Error()
+		}
+	}
+
+	// without --watch, or a structured format, the machines of each app are
+	// shown as soon as they're fetched, so that large organizations don't
+	// wait on their slowest app
+	structured := config.FromContext(ctx).JSONOutput || flag.GetString(ctx, flag.FormatName) != ""
+	if !structured && !flag.GetBool(ctx, "watch") {
+		return streamOrgMachines(ctx, w, results, fetch)
+	}
+
+	parallel.Each(len(results), parallel.Limit, func(i int) {
+		fetch(results[i])
+	})
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].App < results[j].App
+	})
+
+	if ok, err := render.Structured(ctx, w, results); ok {
+		return err
+	}
+
+	var rows [][]string
+	for _, r := range results {
+		reportOrgMachinesError(ctx, r)
+		rows = append(rows, orgMachineRows(ctx, r, true)...)
+	}
+
+	return renderOrgMachines(ctx, w, org, rows, true)
+}
+
+// streamOrgMachines fetches the machines of results, rendering those of each
+// app in a table of its own as soon as they're fetched.
+func streamOrgMachines(ctx context.Context, w io.Writer, results []*orgMachines, fetch func(*orgMachines)) error {
+	fetched := make(chan *orgMachines)
+	go func() {
+		defer close(fetched)
+
+		parallel.Each(len(results), parallel.Limit, func(i int) {
+			fetch(results[i])
+			fetched <- results[i]
+		})
+	}()
+
+	var err error
+	for r := range fetched {
+		if reportOrgMachinesError(ctx, r) || len(r.Machines) == 0 || err != nil {
+			continue
+		}
+
+		err = renderOrgMachines(ctx, w, r.App, orgMachineRows(ctx, r, false), false)
+	}
+
+	return err
+}
+
+// reportOrgMachinesError reports whether the machines of r couldn't be
+// fetched, telling why when they couldn't.
+func reportOrgMachinesError(ctx context.Context, r *orgMachines) bool {
+	if r.Error == "" {
+		return false
+	}
+
+	fmt.Fprintf(iostreams.FromContext(ctx).ErrOut, "failed listing the machines of %s: %s\n", r.App, r.Error)
+
+	return true
+}
+
+// orgMachineRows returns the table rows of the machines of r, starting with
+// the name of their app when withApp is set.
+func orgMachineRows(ctx context.Context, r *orgMachines, withApp bool) (rows [][]string) {
+	silence := flag.GetBool(ctx, "quiet")
+
+	for _, machine := range r.Machines {
+		if silence {
+			rows = append(rows, []string{machine.ID})
+
+			continue
+		}
+
+		row := []string{
+			machine.ID,
+			machine.Name,
+			machine.State,
+			machine.Region,
+			machine.ImageRefWithVersion(),
+			machine.UpdatedAt,
+		}
+		if withApp {
+			row = append([]string{r.App}, row...)
+		}
+
+		rows = append(rows, row)
+	}
+
+	return
+}
+
+func renderOrgMachines(ctx context.Context, w io.Writer, title string, rows [][]string, withApp bool) error {
+	switch {
+	case flag.GetBool(ctx, "quiet"):
+		return render.Table(w, title, rows, "ID")
+	case withApp:
+		return render.Table(w, title, rows, "App", "ID", "Name", "State", "Region", "Image", "Last Updated")
+	default:
+		return render.Table(w, title, rows, "ID", "Name", "State", "Region", "Image", "Last Updated")
+	}
+}
@@ -35,6 +35,12 @@ func New() *cobra.Command {
 		newClone(),
 		newUpdate(),
 		newRestart(),
+		newSnapshot(),
+		newRestore(),
+		newHistory(),
+		newConsole(),
+		newCreate(),
+		newStandby(),
 	)
 
 	return cmd
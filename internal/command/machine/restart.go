@@ -95,7 +95,7 @@ func Restart(ctx context.Context, machineID, sig string, timeOut int, forceStop
 
 		s, err := strconv.Atoi(sig)
 		if err != nil {
-			return fmt.Errorf("could not get signal %s", err)
+			return fmt.Errorf("could not get signal %w", err)
 		}
 		signal.Signal = syscall.Signal(s)
 		input.Signal = signal
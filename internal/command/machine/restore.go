@@ -0,0 +1,139 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRestore() *cobra.Command {
+	const (
+		short = "Recreate a machine and its volumes from a snapshot set"
+		long  = short + `
+
+The snapshot set is the file written by the snapshot command. A new volume is
+created from each of its snapshots, and a new machine using the recorded
+config is launched with those volumes attached.
+`
+		usage = "restore <snapshot-set>"
+	)
+
+	cmd := command.New(usage, short, long, runMachineRestore,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(
+		cmd,
+		flag.String{
+			Name:        "region",
+			Description: "Target region for the restored machine. Defaults to the region of the snapshotted machine",
+		},
+		flag.String{
+			Name:        "name",
+			Description: "Optional name for the restored machine",
+		},
+	)
+
+	return cmd
+}
+
+func runMachineRestore(ctx context.Context) (err error) {
+	var (
+		io       = iostreams.FromContext(ctx)
+		out      = io.Out
+		colorize = io.ColorScheme()
+		client   = client.FromContext(ctx).API()
+	)
+
+	data, err := os.ReadFile(flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	var set snapshotSet
+	if err = json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("failed parsing snapshot set: %w", err)
+	}
+
+	app, err := client.GetAppCompact(ctx, set.AppName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	region := flag.GetString(ctx, "region")
+	if region == "" {
+		region = set.Region
+	}
+
+	if set.Config == nil {
+		return fmt.Errorf("snapshot set %s has no machine config", flag.FirstArg(ctx))
+	}
+
+	config := *set.Config
+	config.Mounts = nil
+
+	for _, mount := range set.Volumes {
+		snapshotID := mount.SnapshotID
+
+		volume, err := client.CreateVolume(ctx, api.CreateVolumeInput{
+			AppID:      app.ID,
+			Name:       mount.VolumeName,
+			Region:     region,
+			SizeGb:     mount.SizeGb,
+			Encrypted:  mount.Encrypted,
+			SnapshotID: &snapshotID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed restoring snapshot %s: %w", snapshotID, err)
+		}
+
+		fmt.Fprintf(out, "Restored snapshot %s into volume %s\n", snapshotID, colorize.Bold(volume.ID))
+
+		config.Mounts = append(config.Mounts, api.MachineMount{
+			Volume:    volume.ID,
+			Path:      mount.Path,
+			SizeGb:    mount.SizeGb,
+			Encrypted: mount.Encrypted,
+		})
+	}
+
+	input := api.LaunchMachineInput{
+		AppID:  app.Name,
+		Name:   flag.GetString(ctx, "name"),
+		Region: region,
+		Config: &config,
+	}
+
+	machine, err := flapsClient.Launch(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Machine %s has been created from the snapshot of %s...\n", colorize.Bold(machine.ID), set.MachineID)
+
+	if err = WaitForStartOrStop(ctx, machine, "start", time.Minute*5); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Machine has been successfully restored!\n")
+
+	return
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/env"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/gpu"
 	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/state"
 )
@@ -52,6 +53,10 @@ var sharedFlags = flag.Set{
 		Name:        "memory",
 		Description: "Memory (in megabytes) to attribute to the machine",
 	},
+	flag.String{
+		Name:        "vm-gpu-kind",
+		Description: "Kind of GPU to give the machine, like a100-40gb",
+	},
 	flag.StringSlice{
 		Name:        "env",
 		Shorthand:   "e",
@@ -225,6 +230,10 @@ func runMachineRun(ctx context.Context) error {
 		return nil
 	}
 
+	if err := checkGPU(ctx, &machineConf, input.Region); err != nil {
+		return err
+	}
+
 	input.Config = &machineConf
 
 	machine, err := flapsClient.Launch(ctx, input)
@@ -250,6 +259,24 @@ func runMachineRun(ctx context.Context) error {
 	return nil
 }
 
+// checkGPU checks the GPU the --vm-gpu-kind flag gives the machine of conf
+// is available in region, and guides users towards an image able to use it.
+func checkGPU(ctx context.Context, conf *api.MachineConfig, region string) error {
+	if flag.GetString(ctx, "vm-gpu-kind") == "" {
+		return nil
+	}
+
+	if err := gpu.Check(ctx, client.FromContext(ctx).API(), conf.Guest, region); err != nil {
+		return err
+	}
+
+	if notice := gpu.Notice(conf.Image); notice != "" {
+		fmt.Fprintln(iostreams.FromContext(ctx).ErrOut, notice)
+	}
+
+	return nil
+}
+
 func createApp(ctx context.Context, message, name string, client *api.Client) (*api.AppCompact, error) {
 	confirm, err := prompt.Confirm(ctx, message)
 	if err != nil {
@@ -524,6 +551,16 @@ func determineMachineConfig(ctx context.Context, initialMachineConf api.MachineC
 		}
 	}
 
+	if gpuKind := flag.GetString(ctx, "vm-gpu-kind"); gpuKind != "" {
+		// presets are shared, so the guest is copied before it's changed
+		guest := *api.MachinePresets["shared-cpu-1x"]
+		if machineConf.Guest != nil {
+			guest = *machineConf.Guest
+		}
+		guest.GPUKind = gpuKind
+		machineConf.Guest = &guest
+	}
+
 	machineConf.Env, err = parseKVFlag(ctx, "env", machineConf.Env)
 
 	if err != nil {
@@ -0,0 +1,219 @@
+package machine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/volumes/snapshots"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// snapshotSet is a point-in-time recovery unit for a single machine: its
+// config along with a snapshot of each of its attached volumes.
+type snapshotSet struct {
+	MachineID   string             `json:"machine_id"`
+	MachineName string             `json:"machine_name"`
+	AppName     string             `json:"app_name"`
+	Region      string             `json:"region"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Config      *api.MachineConfig `json:"config"`
+	Volumes     []snapshotSetMount `json:"volumes"`
+}
+
+type snapshotSetMount struct {
+	Path       string `json:"path"`
+	VolumeID   string `json:"volume_id"`
+	VolumeName string `json:"volume_name"`
+	SizeGb     int    `json:"size_gb"`
+	Encrypted  bool   `json:"encrypted"`
+	SnapshotID string `json:"snapshot_id"`
+}
+
+func newSnapshot() *cobra.Command {
+	const (
+		short = "Snapshot a machine together with its attached volumes"
+		long  = short + `
+
+The machine is quiesced, either by running the given pre-snapshot command on
+it or by stopping it, while all of its attached volumes are snapshotted. The
+machine config and volume snapshots are written to a snapshot set file which
+can be passed to the restore command.
+
+Volumes are snapshotted one after another rather than atomically, so unless
+the machine is stopped with --stop, writes landing between the snapshots of
+its volumes can leave them inconsistent with each other.
+`
+		usage = "snapshot <id>"
+	)
+
+	cmd := command.New(usage, short, long, runMachineSnapshot,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(
+		cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "pre-snapshot-command",
+			Description: "Command to run on the machine before snapshotting, such as one flushing writes to disk",
+		},
+		flag.Bool{
+			Name:        "stop",
+			Description: "Stop the machine while its volumes are snapshotted",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Path to write the snapshot set to. Defaults to <machine-id>-<timestamp>.snapshot.json",
+		},
+	)
+
+	return cmd
+}
+
+func runMachineSnapshot(ctx context.Context) (err error) {
+	var (
+		out       = iostreams.FromContext(ctx).Out
+		client    = client.FromContext(ctx).API()
+		machineID = flag.FirstArg(ctx)
+		appName   = app.NameFromContext(ctx)
+	)
+
+	app, err := appFromMachineOrName(ctx, machineID, appName)
+	if err != nil {
+		return fmt.Errorf("could not get app: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+	ctx = flaps.NewContext(ctx, flapsClient)
+
+	machine, err := flapsClient.Get(ctx, machineID)
+	if err != nil {
+		return err
+	}
+
+	if len(machine.Config.Mounts) == 0 {
+		return fmt.Errorf("machine %s has no volumes attached", machineID)
+	}
+
+	if len(machine.Config.Mounts) > 1 && !flag.GetBool(ctx, "stop") {
+		fmt.Fprintf(iostreams.FromContext(ctx).ErrOut,
+			"Warning: the volumes of machine %s are snapshotted one after another, so they may be inconsistent with each other unless it's stopped with --stop\n", machine.ID)
+	}
+
+	if cmd := flag.GetString(ctx, "pre-snapshot-command"); cmd != "" {
+		fmt.Fprintf(out, "Running pre-snapshot command: %s\n", cmd)
+
+		res, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{Cmd: cmd})
+		if err != nil {
+			return err
+		}
+
+		if res.ExitCode != 0 {
+			return fmt.Errorf("pre-snapshot command exited with non-zero status of %d: %s", res.ExitCode, res.StdErr)
+		}
+	}
+
+	if flag.GetBool(ctx, "stop") && machine.State == "started" {
+		fmt.Fprintf(out, "Stopping machine %s...\n", machine.ID)
+
+		if err = flapsClient.Stop(ctx, api.StopMachineInput{ID: machine.ID, Filters: &api.Filters{}}); err != nil {
+			return err
+		}
+
+		if err = WaitForStartOrStop(ctx, machine, "stop", time.Minute*5); err != nil {
+			return err
+		}
+
+		defer func() {
+			fmt.Fprintf(out, "Starting machine %s...\n", machine.ID)
+
+			if _, e := flapsClient.Start(ctx, machine.ID); err == nil {
+				err = e
+			}
+		}()
+	}
+
+	set := &snapshotSet{
+		MachineID:   machine.ID,
+		MachineName: machine.Name,
+		AppName:     app.Name,
+		Region:      machine.Region,
+		CreatedAt:   time.Now(),
+		Config:      machine.Config,
+	}
+
+	// the snapshots each volume already has tell the new ones apart
+	existing := map[string][]api.Snapshot{}
+	for _, mount := range machine.Config.Mounts {
+		if existing[mount.Volume], err = client.GetVolumeSnapshots(ctx, mount.Volume); err != nil {
+			return fmt.Errorf("failed retrieving snapshots of volume %s: %w", mount.Volume, err)
+		}
+	}
+
+	// request every snapshot before waiting on any of them so that the
+	// volumes are captured as close together as possible
+	for _, mount := range machine.Config.Mounts {
+		fmt.Fprintf(out, "Snapshotting volume %s mounted at %s\n", mount.Volume, mount.Path)
+
+		if err = client.CreateVolumeSnapshot(ctx, mount.Volume); err != nil {
+			return fmt.Errorf("failed snapshotting volume %s: %w", mount.Volume, err)
+		}
+	}
+
+	for _, mount := range machine.Config.Mounts {
+		volume, err := client.GetVolume(ctx, mount.Volume)
+		if err != nil {
+			return err
+		}
+
+		snapshot, err := snapshots.WaitFor(ctx, mount.Volume, existing[mount.Volume])
+		if err != nil {
+			return err
+		}
+
+		set.Volumes = append(set.Volumes, snapshotSetMount{
+			Path:       mount.Path,
+			VolumeID:   mount.Volume,
+			VolumeName: volume.Name,
+			SizeGb:     volume.SizeGb,
+			Encrypted:  volume.Encrypted,
+			SnapshotID: snapshot.ID,
+		})
+	}
+
+	path := flag.GetString(ctx, "output")
+	if path == "" {
+		path = fmt.Sprintf("%s-%s.snapshot.json", machine.ID, set.CreatedAt.Format("20060102150405"))
+	}
+
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Wrote snapshot set for machine %s to %s\n", machine.ID, path)
+
+	return
+}
@@ -0,0 +1,251 @@
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStandby() *cobra.Command {
+	const (
+		short = "Manage standby machines"
+		long  = `Commands for managing standby machines, which are kept stopped and started
+when a machine they stand by for becomes unhealthy. They're created with
+fly machine create --standby-for.`
+	)
+
+	cmd := command.New("standby", short, long, nil)
+
+	cmd.AddCommand(
+		newStandbyList(),
+		newStandbyBreak(),
+	)
+
+	return cmd
+}
+
+func newStandbyList() *cobra.Command {
+	const (
+		short = "List standby machines and the machines they stand by for"
+		long  = short + "\n"
+	)
+
+	cmd := command.New("list", short, long, runStandbyList,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Format(),
+	)
+
+	return cmd
+}
+
+// standby is a standby machine along with a machine it stands by for.
+type standby struct {
+	Standby       string
+	StandbyState  string
+	Region        string
+	Primary       string
+	PrimaryState  string
+	PrimaryHealth string
+}
+
+func runStandbyList(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	flapsClient, err := newFlapsClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	byID := make(map[string]*api.Machine, len(machines))
+	for _, machine := range machines {
+		byID[machine.ID] = machine
+	}
+
+	var standbys []standby
+	for _, machine := range machines {
+		for _, id := range machine.Config.Standbys {
+			s := standby{
+				Standby:      machine.ID,
+				StandbyState: machine.State,
+				Region:       machine.Region,
+				Primary:      id,
+				PrimaryState: "destroyed",
+			}
+
+			if primary, ok := byID[id]; ok {
+				s.PrimaryState = primary.State
+				s.PrimaryHealth = health(primary)
+			}
+
+			standbys = append(standbys, s)
+		}
+	}
+
+	if ok, err := render.Structured(ctx, out, standbys); ok {
+		return err
+	}
+
+	if len(standbys) == 0 {
+		fmt.Fprintln(out, "No machines are standbys; create one with fly machine create --standby-for")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(standbys))
+	for _, s := range standbys {
+		rows = append(rows, []string{s.Standby, s.StandbyState, s.Region, s.Primary, s.PrimaryState, s.PrimaryHealth})
+	}
+
+	return render.Table(out, "", rows, "Standby", "State", "Region", "Stands By For", "Its State", "Its Checks")
+}
+
+// health summarizes the checks of machine, like 2/3 passing.
+func health(machine *api.Machine) string {
+	if len(machine.Checks) == 0 {
+		return ""
+	}
+
+	var passing int
+	for _, check := range machine.Checks {
+		if check.Status == "passing" {
+			passing++
+		}
+	}
+
+	return fmt.Sprintf("%d/%d passing", passing, len(machine.Checks))
+}
+
+func newStandbyBreak() *cobra.Command {
+	const (
+		short = "Stop a machine from standing by for others"
+		long  = `Stop a machine from standing by for the machines it does, leaving it as a
+regular stopped machine, or destroying it with --destroy.`
+
+		usage = "break <standby-id>"
+	)
+
+	cmd := command.New(usage, short, long, runStandbyBreak,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Bool{
+			Name:        "destroy",
+			Description: "Destroy the standby machine",
+		},
+	)
+
+	return cmd
+}
+
+func runStandbyBreak(ctx context.Context) error {
+	var (
+		out     = iostreams.FromContext(ctx).Out
+		appName = app.NameFromContext(ctx)
+		id      = flag.FirstArg(ctx)
+	)
+
+	flapsClient, err := newFlapsClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	machine, err := flapsClient.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if len(machine.Config.Standbys) == 0 {
+		return fmt.Errorf("machine %s isn't a standby", id)
+	}
+
+	primaries := strings.Join(machine.Config.Standbys, ", ")
+
+	if flag.GetBool(ctx, "destroy") {
+		if !flag.GetYes(ctx) {
+			switch confirmed, err := prompt.Confirmf(ctx, "Destroy standby machine %s of %s?", id, primaries); {
+			case err == nil:
+				if !confirmed {
+					return nil
+				}
+			case prompt.IsNonInteractive(err):
+				return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+			default:
+				return err
+			}
+		}
+
+		if err := flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: appName, ID: id, Kill: true}); err != nil {
+			return fmt.Errorf("failed destroying machine %s: %w", id, err)
+		}
+
+		fmt.Fprintf(out, "Standby machine %s of %s was destroyed\n", id, primaries)
+
+		return nil
+	}
+
+	machineConf := *machine.Config
+	machineConf.Standbys = nil
+
+	_, err = flapsClient.Update(ctx, api.LaunchMachineInput{
+		ID:         machine.ID,
+		AppID:      appName,
+		Name:       machine.Name,
+		Region:     machine.Region,
+		Config:     &machineConf,
+		SkipLaunch: machine.State != "started",
+	}, "")
+	if err != nil {
+		return fmt.Errorf("failed updating machine %s: %w", id, err)
+	}
+
+	fmt.Fprintf(out, "Machine %s no longer stands by for %s\n", id, primaries)
+
+	return nil
+}
+
+func newFlapsClient(ctx context.Context) (*flaps.Client, error) {
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, app.NameFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return nil, fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	return flapsClient, nil
+}
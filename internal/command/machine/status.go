@@ -9,9 +9,11 @@ import (
 
 	"github.com/alecthomas/chroma/quick"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -68,12 +70,17 @@ func runMachineStatus(ctx context.Context) (err error) {
 	if err != nil {
 		switch {
 		case strings.Contains(err.Error(), "status"):
-			return fmt.Errorf("retrieve machine failed %s", err)
+			return fmt.Errorf("retrieve machine failed %w", err)
 		default:
 			return fmt.Errorf("machine %s could not be retrieved", machineID)
 		}
 	}
 
+	// the machine is rendered as machine list renders it
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, machine)
+	}
+
 	fmt.Fprintf(io.Out, "Machine ID: %s\n", machine.ID)
 	fmt.Fprintf(io.Out, "Instance ID: %s\n", machine.InstanceID)
 	fmt.Fprintf(io.Out, "State: %s\n\n", machine.State)
@@ -87,7 +94,7 @@ func runMachineStatus(ctx context.Context) (err error) {
 			machine.Name,
 			machine.PrivateIP,
 			machine.Region,
-			machine.Config.Metadata["process_group"],
+			machine.Config.Metadata[api.MachineProcessGroupKey],
 			fmt.Sprint(machine.Config.Guest.MemoryMB),
 			fmt.Sprint(machine.Config.Guest.CPUs),
 			machine.CreatedAt,
@@ -84,6 +84,10 @@ func runUpdate(ctx context.Context) (err error) {
 		return
 	}
 
+	if err := checkGPU(ctx, &machineConf, machine.Region); err != nil {
+		return err
+	}
+
 	input := api.LaunchMachineInput{
 		ID:     machine.ID,
 		AppID:  app.Name,
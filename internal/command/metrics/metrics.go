@@ -0,0 +1,26 @@
+// Package metrics implements the metrics command chain.
+package metrics
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// New initializes and returns a new metrics Command.
+func New() *cobra.Command {
+	const (
+		short = "Query the metrics of an organization"
+		long  = short + ` from the Prometheus managed by Fly, which scrapes
+the metrics of every app of the organization.
+`
+	)
+
+	cmd := command.New("metrics", short, long, nil)
+
+	cmd.AddCommand(
+		newQuery(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,286 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newQuery() *cobra.Command {
+	const (
+		short = "Run a PromQL query"
+		long  = short + ` against the metrics of an organization, such as
+
+  fly metrics query 'sum(rate(fly_app_http_responses_count[5m])) by (status)'
+
+The query is evaluated at the current time, and each resulting series is shown
+with its labels and value. With --range, it's evaluated over that long up to
+now instead, and each series is summarized by its minimum, average, maximum
+and latest values. --format sparkline draws each series over --range, which
+defaults to 1h for it, and --format json writes the series with all of their
+samples. --watch runs the query again every --interval.
+
+The organization is the one of the app in the working directory, or of --app,
+unless --org is given.
+`
+		usage = "query <promql>"
+	)
+
+	cmd := command.New(usage, short, long, runQuery,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.String{
+			Name:        "format",
+			Description: "Output format: table, json or sparkline",
+			Default:     "table",
+		},
+		flag.Duration{
+			Name:        "range",
+			Description: "Evaluate the query over this long up to now, like 1h",
+		},
+		flag.Duration{
+			Name:        "step",
+			Description: "Resolution of --range queries, defaults to a 60th of the range",
+		},
+		flag.Bool{
+			Name:        "watch",
+			Description: "Run the query again every --interval",
+		},
+		flag.Duration{
+			Name:        "interval",
+			Description: "How often --watch runs the query",
+			Default:     10 * time.Second,
+		},
+	)
+
+	return cmd
+}
+
+// query holds what's needed to run a query, which --watch runs repeatedly.
+type query struct {
+	client   *api.Client
+	org      string
+	promql   string
+	format   string
+	span     time.Duration
+	step     time.Duration
+	interval time.Duration
+}
+
+func runQuery(ctx context.Context) error {
+	format := flag.GetString(ctx, "format")
+	if config.FromContext(ctx).JSONOutput {
+		format = "json"
+	}
+
+	switch format {
+	case "table", "json", "sparkline":
+	default:
+		return fmt.Errorf("unsupported format %q; use table, json or sparkline", format)
+	}
+
+	q := &query{
+		client:   client.FromContext(ctx).API(),
+		promql:   flag.FirstArg(ctx),
+		format:   format,
+		span:     flag.GetDuration(ctx, "range"),
+		step:     flag.GetDuration(ctx, "step"),
+		interval: flag.GetDuration(ctx, "interval"),
+	}
+
+	if q.span < 0 || q.step < 0 {
+		return errors.New("--range and --step must be positive")
+	}
+
+	if q.span == 0 && format == "sparkline" {
+		q.span = time.Hour
+	}
+
+	if q.span > 0 && q.step == 0 {
+		q.step = q.span / 60
+	}
+
+	// Prometheus steps are whole seconds
+	if q.step > 0 && q.step < time.Second {
+		q.step = time.Second
+	}
+
+	org, err := orgSlug(ctx, q.client)
+	if err != nil {
+		return err
+	}
+	q.org = org
+
+	if !flag.GetBool(ctx, "watch") {
+		return q.run(ctx, iostreams.FromContext(ctx).Out)
+	}
+
+	return q.watch(ctx)
+}
+
+// orgSlug returns the slug of the organization given with --org, or of the
+// app being worked with, or of the one the user picks.
+func orgSlug(ctx context.Context, apiClient *api.Client) (string, error) {
+	if slug := flag.GetOrg(ctx); slug != "" {
+		return slug, nil
+	}
+
+	if appName := app.NameFromContext(ctx); appName != "" {
+		app, err := apiClient.GetAppCompact(ctx, appName)
+		if err != nil {
+			return "", fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		}
+
+		return app.Organization.Slug, nil
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return org.Slug, nil
+}
+
+func (q *query) run(ctx context.Context, out io.Writer) error {
+	var (
+		series []api.MetricSeries
+		err    error
+		now    = time.Now()
+	)
+
+	if q.span > 0 {
+		series, err = q.client.QueryMetricsSeries(ctx, q.org, q.promql, now.Add(-q.span), now, q.step)
+	} else {
+		series, err = q.client.QueryMetrics(ctx, q.org, q.promql, now)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed querying metrics: %w", err)
+	}
+
+	if q.format == "json" {
+		return render.JSON(out, series)
+	}
+
+	if len(series) == 0 {
+		_, err := fmt.Fprintln(out, "No series matched the query")
+		return err
+	}
+
+	labels := labelNames(series)
+
+	var cols []string
+	for _, label := range labels {
+		if label == "__name__" {
+			label = "metric"
+		}
+		cols = append(cols, label)
+	}
+
+	switch {
+	case q.format == "sparkline":
+		cols = append(cols, "Last "+q.span.String(), "Latest")
+	case q.span > 0:
+		cols = append(cols, "Min", "Avg", "Max", "Latest")
+	default:
+		cols = append(cols, "Value")
+	}
+
+	rows := make([][]string, 0, len(series))
+	for _, s := range series {
+		row := make([]string, 0, len(cols))
+		for _, label := range labels {
+			row = append(row, s.Labels[label])
+		}
+
+		values := make([]float64, len(s.Samples))
+		for i, sample := range s.Samples {
+			values[i] = sample.Value
+		}
+
+		switch {
+		case len(values) == 0:
+			for len(row) < len(cols) {
+				row = append(row, "-")
+			}
+		case q.format == "sparkline":
+			row = append(row, render.Sparkline(values), formatValue(values[len(values)-1]))
+		case q.span > 0:
+			low, high, sum := math.Inf(1), math.Inf(-1), 0.0
+			for _, v := range values {
+				low, high, sum = math.Min(low, v), math.Max(high, v), sum+v
+			}
+
+			row = append(row, formatValue(low), formatValue(sum/float64(len(values))), formatValue(high), formatValue(values[len(values)-1]))
+		default:
+			row = append(row, formatValue(values[len(values)-1]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return render.Table(out, "", rows, cols...)
+}
+
+func (q *query) watch(ctx context.Context) error {
+	if q.format == "json" {
+		return errors.New("--watch and json output are not supported together")
+	}
+
+	return render.Watch(ctx, q.interval, q.promql, q.run)
+}
+
+// labelNames returns the names of the labels of series, with the metric name
+// first.
+func labelNames(series []api.MetricSeries) []string {
+	set := map[string]bool{}
+	for _, s := range series {
+		for name := range s.Labels {
+			set[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if set["__name__"] {
+		names = append([]string{"__name__"}, names...)
+	}
+
+	return names
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', 6, 64)
+}
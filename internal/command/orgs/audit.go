@@ -0,0 +1,236 @@
+package orgs
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// auditPageSize is how many audit events are retrieved per page.
+const auditPageSize = 500
+
+func newAudit() *cobra.Command {
+	const (
+		long = `Commands for archiving the audit log of an organization. fly activity
+shows it interactively.
+`
+		short = "Archive the audit log of an organization"
+	)
+
+	cmd := command.New("audit", short, long, nil)
+
+	cmd.AddCommand(
+		newAuditExport(),
+	)
+
+	return cmd
+}
+
+func newAuditExport() *cobra.Command {
+	const (
+		long = `Exports the complete audit log of an organization between --since and
+--until, oldest event first, for compliance archiving. Both take a time like
+2022-09-01T15:04:05Z or a duration ago like 30d or 12h.
+
+The export is written to stdout, or to the file given with --output. With
+--upload, it's uploaded instead: to an S3 URL like s3://bucket/audit.csv, with
+the credentials and region of the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+AWS_SESSION_TOKEN and AWS_REGION environment variables, or to a presigned
+https URL.
+
+The audit log needs the API to serve it, which it doesn't everywhere yet.
+`
+		short = "Export the audit log of an organization"
+		usage = "export [slug]"
+	)
+
+	cmd := command.New(usage, short, long, runAuditExport,
+		command.RequireAPI("Organization", "auditEvents"),
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "since",
+			Description: "Export events since this time or duration ago",
+			Default:     "30d",
+		},
+		flag.String{
+			Name:        "until",
+			Description: "Export events until this time or duration ago",
+		},
+		flag.String{
+			Name:        "format",
+			Description: "Export format: json or csv",
+			Default:     "json",
+		},
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "File to write the export to, rather than stdout",
+		},
+		flag.String{
+			Name:        "upload",
+			Description: "S3 URL, like s3://bucket/key, or presigned https URL to upload the export to",
+		},
+	)
+
+	return cmd
+}
+
+func runAuditExport(ctx context.Context) error {
+	var (
+		client = client.FromContext(ctx).API()
+		io     = iostreams.FromContext(ctx)
+		format = flag.GetString(ctx, "format")
+		output = flag.GetString(ctx, "output")
+		upload = flag.GetString(ctx, "upload")
+	)
+
+	if format != "json" && format != "csv" {
+		return fmt.Errorf("unknown format %q, must be json or csv", format)
+	}
+
+	if output != "" && upload != "" {
+		return errors.New("--output and --upload can't be used together")
+	}
+
+	var filter api.AuditEventsFilter
+
+	since, err := flag.GetTime(ctx, "since")
+	if err != nil {
+		return err
+	}
+	if !since.IsZero() {
+		filter.Since = &since
+	}
+
+	until, err := flag.GetTime(ctx, "until")
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		filter.Until = &until
+	}
+
+	org, err := OrgFromFirstArgOrSelect(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		events []api.AuditEvent
+		cursor string
+	)
+
+	for {
+		page, info, err := client.GetAuditEventsPage(ctx, org.Slug, filter, auditPageSize, cursor)
+		if err != nil {
+			return fmt.Errorf("failed retrieving audit events of %s: %w", org.Slug, err)
+		}
+
+		events = append(events, page...)
+
+		if io.IsStderrTTY() {
+			fmt.Fprintf(io.ErrOut, "\rretrieved %d events", len(events))
+		}
+
+		if !info.HasNextPage || info.EndCursor == "" {
+			break
+		}
+		cursor = info.EndCursor
+	}
+
+	if io.IsStderrTTY() {
+		fmt.Fprintln(io.ErrOut)
+	}
+
+	// events come newest first, while archives read oldest first
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	var buf bytes.Buffer
+	if format == "csv" {
+		err = writeAuditCSV(&buf, events)
+	} else {
+		err = json.NewEncoder(&buf).Encode(events)
+	}
+	if err != nil {
+		return err
+	}
+
+	contentType := "application/json"
+	if format == "csv" {
+		contentType = "text/csv"
+	}
+
+	switch {
+	case upload != "":
+		if err := uploadExport(ctx, upload, buf.Bytes(), contentType); err != nil {
+			return fmt.Errorf("failed uploading export to %s: %w", upload, err)
+		}
+
+		fmt.Fprintf(io.ErrOut, "Uploaded %d events of %s to %s\n", len(events), org.Slug, upload)
+	case output != "":
+		if err := os.WriteFile(output, buf.Bytes(), 0o600); err != nil {
+			return fmt.Errorf("failed writing export: %w", err)
+		}
+
+		fmt.Fprintf(io.ErrOut, "Exported %d events of %s to %s\n", len(events), org.Slug, output)
+	default:
+		_, err = io.Out.Write(buf.Bytes())
+	}
+
+	return err
+}
+
+func writeAuditCSV(buf *bytes.Buffer, events []api.AuditEvent) error {
+	w := csv.NewWriter(buf)
+
+	if err := w.Write([]string{"id", "time", "type", "app", "user", "ip_address", "description"}); err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		var appName, email string
+		if event.App != nil {
+			appName = event.App.Name
+		}
+		if event.User != nil {
+			email = event.User.Email
+		}
+
+		record := []string{
+			event.ID,
+			event.CreatedAt.UTC().Format(time.RFC3339),
+			event.Type,
+			appName,
+			email,
+			event.IPAddress,
+			event.Description,
+		}
+
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	w.Flush()
+
+	return w.Error()
+}
@@ -60,7 +60,7 @@ func runDelete(ctx context.Context) error {
 
 	client := client.FromContext(ctx).API()
 	if _, err := client.DeleteOrganization(ctx, org.ID); err != nil {
-		return fmt.Errorf("failed deleting organization %s", err)
+		return fmt.Errorf("failed deleting organization %w", err)
 	}
 
 	return nil
@@ -46,7 +46,7 @@ func runInvite(ctx context.Context) error {
 		return nil
 	}
 
-	inv, err := client.CreateOrganizationInvite(ctx, org.ID, email)
+	inv, err := client.CreateOrganizationInvite(ctx, org.ID, email, "")
 	if err != nil {
 		return fmt.Errorf("failed inviting %s to %s: %w", email, org.Name, err)
 	}
@@ -22,7 +22,8 @@ func New() *cobra.Command {
 	const (
 		long = `Commands for managing Fly organizations. list, create, show and
 destroy organizations.
-Organization admins can also invite or remove users from Organizations.
+Organization admins can also invite or remove users from Organizations, and
+manage their roles with the members commands.
 `
 		short = "Commands for managing Fly organizations"
 	)
@@ -36,6 +37,9 @@ Organization admins can also invite or remove users from Organizations.
 		newShow(),
 		newInvite(),
 		newRemove(),
+		newMembers(),
+		newAudit(),
+		newTransferApps(),
 		newCreate(),
 		newDelete(),
 	)
@@ -67,7 +71,7 @@ func slugFromFirstArgOrSelect(ctx context.Context) (slug string, err error) {
 	}
 
 	io := iostreams.FromContext(ctx)
-	if !io.IsInteractive() {
+	if !io.CanPrompt() {
 		err = errSlugArgMustBeSpecified
 
 		return
@@ -0,0 +1,304 @@
+package orgs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newTransferApps() *cobra.Command {
+	const (
+		long = `Moves the apps of an organization matching --match, or all of them with
+--all, to another one, along with their volumes, IP addresses and
+certificates, in one run.
+
+A plan of the apps to move, and of what moves with them, is shown first, along
+with warnings about what changes, like private IPv6 addresses. With --dry-run,
+nothing else happens. Otherwise, once confirmed, apps are moved one after the
+other, which restarts them, and a report of the apps that moved and of those
+that failed to, which are left in place, ends the run.
+`
+		short = "Move many apps from an organization to another"
+	)
+
+	cmd := command.New("transfer-apps", short, long, runTransferApps,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "from",
+			Description: "Slug of the organization to move apps from",
+		},
+		flag.String{
+			Name:        "to",
+			Description: "Slug of the organization to move apps to",
+		},
+		flag.StringSlice{
+			Name:        "match",
+			Description: "Only move apps whose names match this pattern, like staging-*, comma separated or repeated",
+		},
+		flag.Bool{
+			Name:        "all",
+			Description: "Move all the apps of the organization, rather than those matching --match",
+		},
+		flag.Bool{
+			Name:        "dry-run",
+			Description: "Only show the plan, without moving apps",
+		},
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+// transferPlan is what moves along with an app.
+type transferPlan struct {
+	App          string
+	Volumes      int
+	IPAddresses  int
+	Certificates int
+	Warnings     []string
+}
+
+// transferResult is the outcome of moving an app.
+type transferResult struct {
+	App   string
+	Moved bool
+	Error string `json:",omitempty"`
+}
+
+func runTransferApps(ctx context.Context) error {
+	var (
+		client   = client.FromContext(ctx).API()
+		io       = iostreams.FromContext(ctx)
+		from     = flag.GetString(ctx, "from")
+		to       = flag.GetString(ctx, "to")
+		patterns = flag.GetStringSlice(ctx, "match")
+		all      = flag.GetBool(ctx, "all")
+		jsonOut  = config.FromContext(ctx).JSONOutput
+	)
+
+	switch {
+	case from == "" || to == "":
+		return errors.New("--from and --to must be specified")
+	case from == to:
+		return errors.New("--from and --to must be different organizations")
+	case len(patterns) == 0 && !all:
+		return errors.New("--match or --all must be specified")
+	case len(patterns) > 0 && all:
+		return errors.New("--match and --all can't be used together")
+	}
+
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --match %q: %w", pattern, err)
+		}
+	}
+
+	if _, err := OrgFromSlug(ctx, from); err != nil {
+		return err
+	}
+
+	target, err := OrgFromSlug(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	apps, err := client.GetApps(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed listing apps: %w", err)
+	}
+
+	var names []string
+	for _, app := range apps {
+		if app.Organization.Slug == from && matchesAny(app.Name, patterns) {
+			names = append(names, app.Name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return fmt.Errorf("no apps of %s match", from)
+	}
+
+	plans := make([]transferPlan, 0, len(names))
+	for _, name := range names {
+		plan, err := planTransfer(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		plans = append(plans, plan)
+	}
+
+	if !jsonOut {
+		if err := renderTransferPlan(ctx, from, to, plans); err != nil {
+			return err
+		}
+	}
+
+	if flag.GetBool(ctx, "dry-run") {
+		if jsonOut {
+			return render.JSON(io.Out, plans)
+		}
+
+		return nil
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Move %d apps from %s to %s, restarting them?", len(plans), from, to); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	results := make([]transferResult, 0, len(plans))
+	failed := 0
+
+	for _, plan := range plans {
+		if !jsonOut {
+			fmt.Fprintf(io.ErrOut, "Moving %s to %s...\n", plan.App, to)
+		}
+
+		result := transferResult{App: plan.App, Moved: true}
+		if _, err := client.MoveApp(ctx, plan.App, target.ID); err != nil {
+			result.Moved = false
+			result.Error = err.Error()
+			failed++
+		}
+
+		results = append(results, result)
+	}
+
+	if jsonOut {
+		if err := render.JSON(io.Out, struct {
+			Plan    []transferPlan
+			Results []transferResult
+		}{plans, results}); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(results))
+		for _, result := range results {
+			status := io.ColorScheme().Green("moved")
+			if !result.Moved {
+				status = io.ColorScheme().Red("failed")
+			}
+
+			rows = append(rows, []string{result.App, status, result.Error})
+		}
+
+		if err := render.Table(io.Out, "Report", rows, "App", "Status", "Error"); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d apps failed to move, and were left in %s", failed, len(results), from)
+	}
+
+	return nil
+}
+
+// planTransfer lists what moves along with an app, and what to watch out
+// for.
+func planTransfer(ctx context.Context, appName string) (plan transferPlan, err error) {
+	client := client.FromContext(ctx).API()
+
+	plan.App = appName
+
+	volumes, err := client.GetVolumes(ctx, appName)
+	if err != nil {
+		return plan, fmt.Errorf("failed retrieving volumes of %s: %w", appName, err)
+	}
+	plan.Volumes = len(volumes)
+
+	ips, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return plan, fmt.Errorf("failed retrieving IP addresses of %s: %w", appName, err)
+	}
+	plan.IPAddresses = len(ips)
+
+	certs, err := client.GetAppCertificates(ctx, appName)
+	if err != nil {
+		return plan, fmt.Errorf("failed retrieving certificates of %s: %w", appName, err)
+	}
+	plan.Certificates = len(certs)
+
+	for _, ip := range ips {
+		if ip.Type == "private_v6" {
+			plan.Warnings = append(plan.Warnings, "its private IPv6 address changes")
+			break
+		}
+	}
+
+	if plan.Volumes > 0 {
+		plan.Warnings = append(plan.Warnings, "machines with volumes restart with them")
+	}
+
+	return plan, nil
+}
+
+func renderTransferPlan(ctx context.Context, from, to string, plans []transferPlan) error {
+	out := iostreams.FromContext(ctx).Out
+
+	rows := make([][]string, 0, len(plans))
+	for _, plan := range plans {
+		rows = append(rows, []string{
+			plan.App,
+			strconv.Itoa(plan.Volumes),
+			strconv.Itoa(plan.IPAddresses),
+			strconv.Itoa(plan.Certificates),
+			strings.Join(plan.Warnings, "; "),
+		})
+	}
+
+	title := fmt.Sprintf("Plan: move %d apps from %s to %s", len(plans), from, to)
+
+	if err := render.Table(out, title, rows, "App", "Volumes", "IP Addresses", "Certificates", "Warnings"); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Apps left in %s won't reach the moved apps over the private network anymore.\n", from)
+
+	return nil
+}
+
+// matchesAny reports whether name matches any of patterns, or whether there
+// are none.
+func matchesAny(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+package orgs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+// uploadExport uploads data to dest, either an S3 URL like s3://bucket/key,
+// signed with the AWS credentials of the environment, or a presigned https
+// URL.
+func uploadExport(ctx context.Context, dest string, data []byte, contentType string) error {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return err
+	}
+
+	switch u.Scheme {
+	case "https":
+		break
+	case "s3":
+		return uploadS3(ctx, u.Host, strings.TrimPrefix(u.Path, "/"), data, contentType)
+	default:
+		return fmt.Errorf("unsupported URL %q, must be like s3://bucket/key or https://", dest)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, dest, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errors.New(res.Status)
+	}
+
+	return nil
+}
+
+// uploadS3 puts data, of the given content type, at key of bucket, on AWS S3.
+func uploadS3(ctx context.Context, bucket, key string, data []byte, contentType string) error {
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to upload to S3")
+	}
+
+	if bucket == "" || key == "" {
+		return errors.New("S3 URLs must name a bucket and a key, like s3://bucket/key")
+	}
+
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	client := &s3.Client{
+		Endpoint:     fmt.Sprintf("https://s3.%s.amazonaws.com", region),
+		Region:       region,
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+
+	return client.PutObject(ctx, bucket, key, data, contentType)
+}
@@ -0,0 +1,199 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCapacity() *cobra.Command {
+	const (
+		long = `Commands for checking the capacity of regions, before deploying or scaling
+out to them.
+`
+		short = "Check the capacity of regions"
+	)
+
+	cmd := command.New("capacity", short, long, nil)
+
+	cmd.AddCommand(
+		newCapacityCheck(),
+	)
+
+	return cmd
+}
+
+func newCapacityCheck() *cobra.Command {
+	const (
+		long = `Check whether regions can place a count of machines of a VM size now, along
+with a volume for each when --volume-size is given, and report on each region.
+Capacity changes as others launch and destroy machines, so this doesn't
+reserve it, but catches regions a deploy or scale out would fail halfway
+through in.
+
+Checking capacity needs the API to serve it, which it doesn't everywhere yet.
+`
+		short = "Check whether regions can place machines"
+	)
+
+	cmd := command.New("check", short, long, runCapacityCheck,
+		command.RequireAPI("FlyPlatform", "regionCapacity"),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "region",
+			Shorthand:   "r",
+			Description: "Regions to check, like fra,ams",
+		},
+		flag.String{
+			Name:        "vm-size",
+			Description: "VM size of the machines",
+			Default:     "shared-cpu-1x",
+		},
+		flag.Int{
+			Name:        "vm-memory",
+			Description: "Memory of the machines in MB, overriding that of the VM size",
+		},
+		flag.String{
+			Name:        "vm-gpu-kind",
+			Description: "Kind of GPU of the machines, like a100-40gb",
+		},
+		flag.Int{
+			Name:        "count",
+			Description: "Count of machines to place in each region",
+			Default:     1,
+		},
+		flag.Int{
+			Name:        "volume-size",
+			Description: "Size in GB of a volume for each machine",
+		},
+	)
+
+	return cmd
+}
+
+// placement is the result of checking a region can place machines.
+type placement struct {
+	Region            string
+	Machines          int
+	PlaceableMachines int
+	VolumeGb          int
+	AvailableGb       int
+	Problems          []string
+}
+
+func runCapacityCheck(ctx context.Context) error {
+	var (
+		out        = iostreams.FromContext(ctx).Out
+		client     = client.FromContext(ctx).API()
+		regions    = flag.GetStringSlice(ctx, "region")
+		count      = flag.GetInt(ctx, "count")
+		volumeSize = flag.GetInt(ctx, "volume-size")
+	)
+
+	if len(regions) == 0 {
+		return errors.New("regions to check must be given with --region")
+	}
+
+	if count < 1 {
+		return errors.New("--count must be at least 1")
+	}
+
+	size := flag.GetString(ctx, "vm-size")
+	preset, ok := api.MachinePresets[size]
+	if !ok {
+		return fmt.Errorf("unknown VM size %s; fly platform vm-sizes lists them", size)
+	}
+
+	guest := *preset
+	if memory := flag.GetInt(ctx, "vm-memory"); memory > 0 {
+		guest.MemoryMB = memory
+	}
+	guest.GPUKind = flag.GetString(ctx, "vm-gpu-kind")
+
+	sort.Strings(regions)
+
+	var (
+		placements []placement
+		failed     []string
+	)
+
+	for _, region := range regions {
+		capacity, err := client.PlatformRegionPlacement(ctx, region, &guest)
+		if err != nil {
+			return fmt.Errorf("failed checking capacity of region %s: %w", region, err)
+		}
+
+		p := placement{
+			Region:            region,
+			Machines:          count,
+			PlaceableMachines: capacity.Machines,
+			VolumeGb:          count * volumeSize,
+			AvailableGb:       capacity.VolumeGb,
+		}
+
+		if !capacity.Available || capacity.Machines < count {
+			p.Problems = append(p.Problems, fmt.Sprintf("only %d machines fit", capacity.Machines))
+		}
+		if p.VolumeGb > capacity.VolumeGb {
+			p.Problems = append(p.Problems, fmt.Sprintf("only %dGB of volumes fit", capacity.VolumeGb))
+		}
+
+		if len(p.Problems) > 0 {
+			failed = append(failed, region)
+		}
+
+		placements = append(placements, p)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		if err := render.JSON(out, placements); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(placements))
+		for _, p := range placements {
+			result := "ok"
+			if len(p.Problems) > 0 {
+				result = strings.Join(p.Problems, ", ")
+			}
+
+			volumes := ""
+			if volumeSize > 0 {
+				volumes = fmt.Sprintf("%dGB of %dGB", p.VolumeGb, p.AvailableGb)
+			}
+
+			rows = append(rows, []string{
+				p.Region,
+				fmt.Sprintf("%d of %d", p.Machines, p.PlaceableMachines),
+				volumes,
+				result,
+			})
+		}
+
+		if err := render.Table(out, "", rows, "Region", "Machines", "Volumes", "Result"); err != nil {
+			return err
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%s can't place %d %s machines now", strings.Join(failed, ", "), count, size)
+	}
+
+	return nil
+}
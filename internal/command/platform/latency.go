@@ -0,0 +1,218 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/helpers"
+)
+
+// Latencies are measured with requests to probeURL, which the Fly proxy
+// replays to the region named by their fly-prefer-region header, or the
+// nearest one serving it when it doesn't run there. The first
+// request to each region warms up the connection, and the median of the
+// latencySamples following it is kept.
+const (
+	probeURL       = "https://debug.fly.dev/"
+	probeImage     = "curlimages/curl:latest"
+	latencySamples = 3
+)
+
+// measureLatencies returns the round-trip latency from here to each of
+// regions. Regions which couldn't be reached are left out.
+func measureLatencies(ctx context.Context, regions []string) map[string]time.Duration {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		latencies = make(map[string]time.Duration, len(regions))
+		sem       = make(chan struct{}, 8)
+	)
+
+	for _, region := range regions {
+		region := region
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			latency, err := probe(ctx, region)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			latencies[region] = latency
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	return latencies
+}
+
+func probe(ctx context.Context, region string) (time.Duration, error) {
+	samples := make([]time.Duration, 0, latencySamples)
+
+	for i := 0; i <= latencySamples; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, probeURL, nil)
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("fly-prefer-region", region)
+
+		start := time.Now()
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		res.Body.Close()
+
+		if i > 0 {
+			samples = append(samples, time.Since(start))
+		}
+	}
+
+	return median(samples), nil
+}
+
+// createProbeApp creates the throwaway app the ephemeral machines measuring
+// latencies are launched in, so the app measured for is left untouched. It's
+// up to the caller to delete it.
+func createProbeApp(ctx context.Context, apiClient *api.Client, org *api.OrganizationBasic) (*api.AppCompact, error) {
+	suffix, err := helpers.RandString(8)
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := apiClient.CreateApp(ctx, api.CreateAppInput{
+		Name:           "latency-probe-" + strings.ToLower(suffix),
+		OrganizationID: org.ID,
+		Machines:       true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed creating throwaway app: %w", err)
+	}
+
+	return &api.AppCompact{
+		ID:              app.ID,
+		Name:            app.Name,
+		PlatformVersion: "machines",
+		Organization: &api.OrganizationBasic{
+			ID:   app.Organization.ID,
+			Slug: app.Organization.Slug,
+		},
+	}, nil
+}
+
+// measureLatenciesFrom returns the round-trip latency from from to each of
+// regions, measured by an ephemeral machine of app launched there.
+func measureLatenciesFrom(ctx context.Context, flapsClient *flaps.Client, appName, from string, regions []string) (map[string]time.Duration, error) {
+	machine, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:  appName,
+		Region: from,
+		Config: &api.MachineConfig{
+			Image: probeImage,
+			Init: api.MachineInit{
+				Exec: []string{"sleep", "infinity"},
+			},
+			Metadata: map[string]string{api.MachineProcessGroupKey: "latency_probe"},
+			Restart:  api.MachineRestart{Policy: api.MachineRestartPolicyNo},
+			Guest:    api.MachinePresets["shared-cpu-1x"],
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed launching ephemeral machine in %s: %w", from, err)
+	}
+
+	defer func() {
+		input := api.RemoveMachineInput{AppID: appName, ID: machine.ID, Kill: true}
+		_ = flapsClient.Destroy(context.Background(), input)
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	if err := flapsClient.Wait(waitCtx, machine, "started"); err != nil {
+		return nil, fmt.Errorf("ephemeral machine %s in %s failed to start: %w", machine.ID, from, err)
+	}
+
+	res, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{
+		Cmd:     probeScript(regions),
+		Timeout: 5 * len(regions),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed measuring latencies from %s: %w", from, err)
+	}
+
+	return parseProbeOutput(res.StdOut), nil
+}
+
+// probeScript returns the command measuring latencies from a machine. It
+// prints a line per region, with the code of the region followed by the
+// durations of its requests in seconds.
+func probeScript(regions []string) string {
+	outputs := strings.Repeat("-o /dev/null ", latencySamples+1)
+	urls := strings.TrimSpace(strings.Repeat(probeURL+" ", latencySamples+1))
+
+	return fmt.Sprintf(`sh -c 'for r in %s; do printf "%%s" "$r"; curl -sI %s-w " %%{time_total}" -H "fly-prefer-region: $r" %s; echo; done'`,
+		strings.Join(regions, " "), outputs, urls)
+}
+
+// parseProbeOutput parses the output of probeScript into the latency to each
+// region, skipping the warm-up request.
+func parseProbeOutput(out string) map[string]time.Duration {
+	latencies := map[string]time.Duration{}
+
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		var samples []time.Duration
+		for _, field := range fields[2:] {
+			seconds, err := strconv.ParseFloat(field, 64)
+			if err != nil || seconds <= 0 {
+				continue
+			}
+			samples = append(samples, time.Duration(math.Round(seconds*1e6))*time.Microsecond)
+		}
+
+		if len(samples) > 0 {
+			latencies[fields[0]] = median(samples)
+		}
+	}
+
+	return latencies
+}
+
+func median(samples []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted[len(sorted)/2]
+}
+
+func formatLatency(d time.Duration, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
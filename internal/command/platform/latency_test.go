@@ -0,0 +1,21 @@
+package platform
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProbeOutput(t *testing.T) {
+	out := "fra 0.231 0.021 0.019 0.025\n" +
+		"ams 0.301 0.035 0.041 0.033\n" +
+		"syd\n" +
+		"nrt 0.9 garbage 0.250\n"
+
+	assert.Equal(t, map[string]time.Duration{
+		"fra": 21 * time.Millisecond,
+		"ams": 35 * time.Millisecond,
+		"nrt": 250 * time.Millisecond,
+	}, parseProbeOutput(out))
+}
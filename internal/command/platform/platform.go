@@ -22,6 +22,7 @@ about the Fly platform.
 		newRegions(),
 		newStatus(),
 		newVMSizes(),
+		newCapacity(),
 	)
 
 	return
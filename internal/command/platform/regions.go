@@ -2,32 +2,59 @@ package platform
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 )
 
 func newRegions() (cmd *cobra.Command) {
 	const (
 		long = `View a list of regions where Fly has edges and/or datacenters
+
+With --latency, the round-trip latency from here to each region is measured,
+and regions are listed from the nearest. With --from-app-regions, it's also
+measured from each region the machines of an app run in, by an ephemeral
+machine launched there in a throwaway app, to weigh where to scale out to.
+Requests are routed to each region by the Fly proxy, which falls back to the
+nearest region when the probe doesn't run in one.
 `
 		short = "List regions"
 	)
 
 	cmd = command.New("regions", short, long, runRegions,
 		command.RequireSession,
+		command.LoadAppNameIfPresent,
 	)
 
 	cmd.Args = cobra.NoArgs
 
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Bool{
+			Name:        "latency",
+			Description: "Measure the latency to each region and rank them by it",
+		},
+		flag.Bool{
+			Name:        "from-app-regions",
+			Description: "Also measure latencies from the regions of the app, with ephemeral machines",
+		},
+	)
+
 	return
 }
 
@@ -42,6 +69,10 @@ func runRegions(ctx context.Context) error {
 		return regions[i].Code < regions[j].Code
 	})
 
+	if flag.GetBool(ctx, "latency") || flag.GetBool(ctx, "from-app-regions") {
+		return runRegionLatencies(ctx, regions)
+	}
+
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
 		return render.JSON(out, regions)
@@ -63,3 +94,156 @@ func runRegions(ctx context.Context) error {
 
 	return render.Table(out, "", rows, "Code", "Name", "Gateway")
 }
+
+// regionLatency is a region along with the latencies to it in milliseconds,
+// keyed by the region they're measured from, or local.
+type regionLatency struct {
+	api.Region
+	LatencyMs map[string]int64
+}
+
+func runRegionLatencies(ctx context.Context, regions []api.Region) error {
+	io := iostreams.FromContext(ctx)
+
+	codes := make([]string, 0, len(regions))
+	for _, region := range regions {
+		codes = append(codes, region.Code)
+	}
+
+	fmt.Fprintf(io.ErrOut, "Measuring latencies to %d regions from here\n", len(codes))
+
+	local := measureLatencies(ctx, codes)
+
+	var (
+		from        []string
+		fromLatency = map[string]map[string]time.Duration{}
+	)
+
+	if flag.GetBool(ctx, "from-app-regions") {
+		appName := app.NameFromContext(ctx)
+		if appName == "" {
+			return errors.New("--from-app-regions requires an app, given with --app or a fly.toml")
+		}
+
+		apiClient := client.FromContext(ctx).API()
+
+		appCompact, err := apiClient.GetAppCompact(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("failed fetching app: %w", err)
+		}
+
+		if appCompact.PlatformVersion != app.MachinesPlatform {
+			return fmt.Errorf("--from-app-regions requires an app running on machines, and %s doesn't", appName)
+		}
+
+		flapsClient, err := flaps.New(ctx, appCompact)
+		if err != nil {
+			return fmt.Errorf("could not make flaps client: %w", err)
+		}
+
+		machines, err := flapsClient.ListActive(ctx)
+		if err != nil {
+			return fmt.Errorf("failed listing machines: %w", err)
+		}
+
+		for _, machine := range machines {
+			if _, ok := fromLatency[machine.Region]; !ok {
+				fromLatency[machine.Region] = nil
+				from = append(from, machine.Region)
+			}
+		}
+		sort.Strings(from)
+
+		if len(from) == 0 {
+			return fmt.Errorf("%s has no machines, so no regions to measure latencies from", appName)
+		}
+
+		probeApp, err := createProbeApp(ctx, apiClient, appCompact.Organization)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := apiClient.DeleteApp(context.Background(), probeApp.Name); err != nil {
+				fmt.Fprintf(io.ErrOut, "failed deleting throwaway app %s: %v\n", probeApp.Name, err)
+			}
+		}()
+
+		probeFlaps, err := flaps.New(ctx, probeApp)
+		if err != nil {
+			return fmt.Errorf("could not make flaps client: %w", err)
+		}
+
+		for _, region := range from {
+			fmt.Fprintf(io.ErrOut, "Measuring latencies from %s with an ephemeral machine of %s\n", region, probeApp.Name)
+
+			latencies, err := measureLatenciesFrom(ctx, probeFlaps, probeApp.Name, region, codes)
+			if err != nil {
+				return err
+			}
+			fromLatency[region] = latencies
+		}
+	}
+
+	// rank regions by their latency from here, then from the app's regions
+	rank := func(code string) time.Duration {
+		if d, ok := local[code]; ok {
+			return d
+		}
+		for _, region := range from {
+			if d, ok := fromLatency[region][code]; ok {
+				return time.Hour + d
+			}
+		}
+
+		return 2 * time.Hour
+	}
+
+	sort.SliceStable(regions, func(i, j int) bool {
+		return rank(regions[i].Code) < rank(regions[j].Code)
+	})
+
+	if config.FromContext(ctx).JSONOutput {
+		results := make([]regionLatency, 0, len(regions))
+		for _, region := range regions {
+			latencies := map[string]int64{}
+			if d, ok := local[region.Code]; ok {
+				latencies["local"] = d.Milliseconds()
+			}
+			for _, source := range from {
+				if d, ok := fromLatency[source][region.Code]; ok {
+					latencies[source] = d.Milliseconds()
+				}
+			}
+
+			results = append(results, regionLatency{Region: region, LatencyMs: latencies})
+		}
+
+		return render.JSON(io.Out, results)
+	}
+
+	cols := []string{"Code", "Name", "Gateway", "Latency"}
+	for _, region := range from {
+		cols = append(cols, "From "+region)
+	}
+
+	rows := make([][]string, 0, len(regions))
+	for _, region := range regions {
+		gateway := ""
+		if region.GatewayAvailable {
+			gateway = "✓"
+		}
+
+		d, ok := local[region.Code]
+		row := []string{region.Code, region.Name, gateway, formatLatency(d, ok)}
+
+		for _, source := range from {
+			d, ok := fromLatency[source][region.Code]
+			row = append(row, formatLatency(d, ok))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return render.Table(io.Out, "", rows, cols...)
+}
@@ -3,17 +3,27 @@ package platform
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/statuspage"
 	"github.com/superfly/flyctl/iostreams"
 )
 
 func newStatus() (cmd *cobra.Command) {
 	const (
-		long = `Show current Fly platform status in a browser
+		long = `Show the current status of the Fly platform, as reported on status.fly.io:
+its ongoing incidents with their latest updates, upcoming maintenances, and the
+regions that aren't fully operational. --region narrows these down to the
+incidents and health of the regions given.
 `
 		short = "Show current platform status"
 	)
@@ -22,18 +32,129 @@ func newStatus() (cmd *cobra.Command) {
 
 	cmd.Args = cobra.NoArgs
 
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "region",
+			Shorthand:   "r",
+			Description: "Only show the incidents and health of these regions, comma separated or repeated",
+		},
+		flag.Bool{
+			Name:        "web",
+			Description: "Open status.fly.io in a browser instead",
+		},
+	)
+
 	return
 }
 
 func runStatus(ctx context.Context) error {
-	const url = "https://status.fly.io/"
+	io := iostreams.FromContext(ctx)
+
+	if flag.GetBool(ctx, "web") {
+		fmt.Fprintf(io.ErrOut, "opening %s ...\n", statuspage.URL)
+
+		if err := open.Run(statuspage.URL); err != nil {
+			return fmt.Errorf("failed opening %s: %w", statuspage.URL, err)
+		}
+
+		return nil
+	}
+
+	summary, err := statuspage.Fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed fetching platform status: %w", err)
+	}
+
+	if regions := flag.GetStringSlice(ctx, "region"); len(regions) > 0 {
+		summary.Incidents = summary.RegionIncidents(regions...)
+
+		wanted := map[string]bool{}
+		for _, region := range regions {
+			wanted[region] = true
+		}
+
+		var components []statuspage.Component
+		for _, c := range summary.Components {
+			if wanted[c.Region()] {
+				components = append(components, c)
+			}
+		}
+		summary.Components = components
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, summary)
+	}
+
+	colorize := io.ColorScheme()
+
+	description := summary.Status.Description
+	switch summary.Status.Indicator {
+	case "none":
+		description = colorize.Green(description)
+	case "minor":
+		description = colorize.Yellow(description)
+	default:
+		description = colorize.Red(description)
+	}
+
+	fmt.Fprintf(io.Out, "%s %s\n\n", colorize.Bold("Platform status:"), description)
+
+	if len(summary.Incidents) == 0 {
+		fmt.Fprintln(io.Out, "No ongoing incidents")
+	}
 
-	w := iostreams.FromContext(ctx).ErrOut
-	fmt.Fprintf(w, "opening %s ...\n", url)
+	for _, incident := range summary.Incidents {
+		renderIncident(ctx, incident)
+	}
+
+	for _, maintenance := range summary.ScheduledMaintenances {
+		renderIncident(ctx, maintenance)
+	}
+
+	var rows [][]string
+	for _, c := range summary.Components {
+		if region := c.Region(); region != "" && !c.Operational() {
+			rows = append(rows, []string{region, c.Name, strings.ReplaceAll(c.Status, "_", " ")})
+		}
+	}
+
+	if len(rows) == 0 {
+		fmt.Fprintln(io.Out, "\nAll regions are operational")
+
+		return nil
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	fmt.Fprintln(io.Out)
+
+	return render.Table(io.Out, "Degraded regions", rows, "Region", "Name", "Status")
+}
+
+func renderIncident(ctx context.Context, incident statuspage.Incident) {
+	io := iostreams.FromContext(ctx)
+	colorize := io.ColorScheme()
+
+	fmt.Fprintf(io.Out, "%s %s (%s, %s impact)\n", colorize.Yellow("●"), colorize.Bold(incident.Name), incident.Status, incident.Impact)
+
+	var affected []string
+	for _, c := range incident.Components {
+		affected = append(affected, c.Name)
+	}
+	if len(affected) > 0 {
+		fmt.Fprintf(io.Out, "  Affects: %s\n", strings.Join(affected, ", "))
+	}
+
+	if update := incident.Latest(); update != nil {
+		fmt.Fprintf(io.Out, "  %s, %s: %s\n", format.RelativeTime(update.CreatedAt), update.Status, strings.TrimSpace(update.Body))
+	}
 
-	if err := open.Run(url); err != nil {
-		return fmt.Errorf("failed opening %s: %w", url, err)
+	if incident.Shortlink != "" {
+		fmt.Fprintf(io.Out, "  %s\n", incident.Shortlink)
 	}
 
-	return nil
+	fmt.Fprintln(io.Out)
 }
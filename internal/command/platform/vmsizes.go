@@ -3,6 +3,7 @@ package platform
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -10,13 +11,16 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
 )
 
 func newVMSizes() (cmd *cobra.Command) {
 	const (
-		long = `View a list of VM sizes which can be used with the FLYCTL SCALE VM command
+		long = `View a list of VM sizes which can be used with the FLYCTL SCALE VM command,
+along with their CPU type. With --pricing, their hourly and monthly prices are
+shown too.
 `
 		short = "List VM Sizes"
 	)
@@ -27,9 +31,22 @@ func newVMSizes() (cmd *cobra.Command) {
 
 	cmd.Args = cobra.NoArgs
 
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "pricing",
+			Description: "Show the hourly and monthly price of each size",
+		},
+	)
+
 	return
 }
 
+// pricedVMSize is a VM size along with its hourly price.
+type pricedVMSize struct {
+	api.VMSize
+	PriceHour float32
+}
+
 func runVMSizes(ctx context.Context) error {
 	client := client.FromContext(ctx).API()
 
@@ -38,21 +55,44 @@ func runVMSizes(ctx context.Context) error {
 		return fmt.Errorf("failed retrieving sizes: %w", err)
 	}
 
+	pricing := flag.GetBool(ctx, "pricing")
+
 	out := iostreams.FromContext(ctx).Out
 	if config.FromContext(ctx).JSONOutput {
-		return render.JSON(out, sizes)
+		if !pricing {
+			return render.JSON(out, sizes)
+		}
+
+		priced := make([]pricedVMSize, 0, len(sizes))
+		for _, size := range sizes {
+			priced = append(priced, pricedVMSize{VMSize: size, PriceHour: size.PriceHour()})
+		}
+
+		return render.JSON(out, priced)
+	}
+
+	cols := []string{"Name", "CPU Cores", "CPU Type", "Memory"}
+	if pricing {
+		cols = append(cols, "Price/Hour", "Price/Month")
 	}
 
 	var rows [][]string
 	for _, size := range sizes {
-		rows = append(rows, []string{
+		row := []string{
 			size.Name,
 			cores(size),
+			cpuType(size),
 			memory(size),
-		})
+		}
+
+		if pricing {
+			row = append(row, fmt.Sprintf("$%.4f", size.PriceHour()), fmt.Sprintf("$%.2f", size.PriceMonth))
+		}
+
+		rows = append(rows, row)
 	}
 
-	return render.Table(out, "", rows, "Name", "CPU Cores", "Memory")
+	return render.Table(out, "", rows, cols...)
 }
 
 func cores(size api.VMSize) string {
@@ -68,3 +108,15 @@ func memory(size api.VMSize) string {
 	}
 	return fmt.Sprintf("%d GB", int(size.MemoryGB))
 }
+
+// cpuType returns the type of CPUs of size, which its name starts with.
+func cpuType(size api.VMSize) string {
+	switch {
+	case strings.HasPrefix(size.Name, "shared"):
+		return "shared"
+	case strings.HasPrefix(size.Name, "performance"):
+		return "performance"
+	default:
+		return "dedicated"
+	}
+}
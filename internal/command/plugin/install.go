@@ -0,0 +1,284 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// defaultIndexURL is the URL of the index plugins are installed from, unless
+// --index or FLY_PLUGIN_INDEX gives another.
+const defaultIndexURL = "https://raw.githubusercontent.com/superfly/flyctl-plugins/main/index.json"
+
+// indexKey is the base64 encoded Ed25519 public key indexes are signed with.
+// Signatures are served along with indexes, at their URL suffixed with .sig,
+// so the checksums of an index can only be trusted once it's verified.
+var indexKey = "pejF0hfr0y+ei+w3Xq5mBdI27qbe1OCq3Vx8tztrelI="
+
+// index lists the plugins which can be installed, along with where to
+// download their builds from.
+type index struct {
+	Plugins []indexPlugin `json:"plugins"`
+}
+
+type indexPlugin struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Version     string  `json:"version"`
+	Builds      []build `json:"builds"`
+}
+
+// build is a build of a plugin for an OS and architecture: either the
+// executable itself, or a .tar.gz archive holding it.
+type build struct {
+	OS     string `json:"os"`
+	Arch   string `json:"arch"`
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// find returns the plugin of idx called name, and its build for goos and
+// goarch.
+func (idx *index) find(name, goos, goarch string) (*indexPlugin, *build, error) {
+	for i := range idx.Plugins {
+		p := &idx.Plugins[i]
+		if p.Name != name {
+			continue
+		}
+
+		for j := range p.Builds {
+			if b := &p.Builds[j]; b.OS == goos && b.Arch == goarch {
+				return p, b, nil
+			}
+		}
+
+		return nil, nil, fmt.Errorf("plugin %s has no build for %s/%s", name, goos, goarch)
+	}
+
+	return nil, nil, fmt.Errorf("plugin %s isn't in the index", name)
+}
+
+func newInstall() *cobra.Command {
+	const (
+		long = `Install a plugin from the plugin index into the plugins directory, replacing
+any version of it installed before. Downloads are checked against the checksums
+of the index, which must be signed with the key of the Fly plugin index, so
+--index only takes mirrors of it.`
+		short = "Install a plugin"
+		usage = "install <name>"
+	)
+
+	cmd := command.New(usage, short, long, runInstall)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "index",
+			Description: "URL of the plugin index",
+		},
+	)
+
+	return cmd
+}
+
+func runInstall(ctx context.Context) error {
+	var (
+		out  = iostreams.FromContext(ctx).Out
+		name = flag.FirstArg(ctx)
+	)
+
+	if strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("invalid plugin name %q", name)
+	}
+
+	indexURL := flag.GetString(ctx, "index")
+	if indexURL == "" {
+		indexURL = os.Getenv("FLY_PLUGIN_INDEX")
+	}
+	if indexURL == "" {
+		indexURL = defaultIndexURL
+	}
+
+	var idx index
+	data, err := download(ctx, indexURL)
+	if err != nil {
+		return fmt.Errorf("failed fetching plugin index: %w", err)
+	}
+	sig, err := download(ctx, indexURL+".sig")
+	if err != nil {
+		return fmt.Errorf("failed fetching plugin index signature: %w", err)
+	}
+	if err := verifyIndex(data, sig, indexKey); err != nil {
+		return fmt.Errorf("failed verifying plugin index: %w", err)
+	}
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return fmt.Errorf("failed parsing plugin index: %w", err)
+	}
+
+	p, b, err := idx.find(name, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+
+	data, err = download(ctx, b.URL)
+	if err != nil {
+		return fmt.Errorf("failed downloading %s: %w", name, err)
+	}
+
+	if err := verify(data, b.SHA256); err != nil {
+		return fmt.Errorf("failed verifying %s: %w", name, err)
+	}
+
+	if strings.HasSuffix(path.Base(b.URL), ".tar.gz") {
+		if data, err = extract(data, executableName(name)); err != nil {
+			return fmt.Errorf("failed extracting %s: %w", name, err)
+		}
+	}
+
+	d, err := dir()
+	if err != nil {
+		return err
+	}
+
+	dest, err := writeExecutable(d, executableName(name), data)
+	if err != nil {
+		return fmt.Errorf("failed installing %s: %w", name, err)
+	}
+
+	fmt.Fprintf(out, "Installed %s %s to %s; run it as fly %s\n", name, p.Version, dest, name)
+
+	return nil
+}
+
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, res.Status)
+	}
+
+	return io.ReadAll(res.Body)
+}
+
+// verifyIndex checks that sig, base64 encoded, is the Ed25519 signature of
+// data by the base64 encoded public key.
+func verifyIndex(data, sig []byte, key string) error {
+	pub, err := base64.StdEncoding.DecodeString(key)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return errors.New("invalid public key")
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(pub), data, decoded) {
+		return errors.New("signature mismatch")
+	}
+
+	return nil
+}
+
+// verify checks that the SHA-256 of data is the hex encoded sum.
+func verify(data []byte, sum string) error {
+	if sum == "" {
+		return errors.New("the index has no checksum for it")
+	}
+
+	actual := sha256.Sum256(data)
+	if hex.EncodeToString(actual[:]) != strings.ToLower(sum) {
+		return errors.New("checksum mismatch")
+	}
+
+	return nil
+}
+
+// extract returns the content of the file called name in the .tar.gz archive
+// data, in any directory of it.
+func extract(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the archive has no %s", name)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag == tar.TypeReg && path.Base(header.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// writeExecutable writes data to the executable name in dir, replacing any
+// existing one at once.
+func writeExecutable(dir, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	f, err := os.CreateTemp(dir, name+".*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.Chmod(f.Name(), 0o755); err != nil {
+		return "", err
+	}
+
+	dest := filepath.Join(dir, name)
+	if err := os.Rename(f.Name(), dest); err != nil {
+		return "", err
+	}
+
+	return dest, nil
+}
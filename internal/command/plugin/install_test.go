@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexFind(t *testing.T) {
+	idx := index{Plugins: []indexPlugin{
+		{Name: "foo", Version: "1.0.0", Builds: []build{
+			{OS: "linux", Arch: "amd64", URL: "https://example.com/foo-linux-amd64"},
+			{OS: "darwin", Arch: "arm64", URL: "https://example.com/foo-darwin-arm64"},
+		}},
+	}}
+
+	p, b, err := idx.find("foo", "darwin", "arm64")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", p.Version)
+	assert.Equal(t, "https://example.com/foo-darwin-arm64", b.URL)
+
+	_, _, err = idx.find("foo", "windows", "amd64")
+	assert.Error(t, err)
+
+	_, _, err = idx.find("bar", "linux", "amd64")
+	assert.Error(t, err)
+}
+
+func TestVerifyAndExtract(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	content := []byte("#!/bin/sh\necho foo\n")
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "foo-1.0.0/flyctl-foo", Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	sum := sha256.Sum256(buf.Bytes())
+	assert.NoError(t, verify(buf.Bytes(), hex.EncodeToString(sum[:])))
+	assert.Error(t, verify(buf.Bytes(), hex.EncodeToString(make([]byte, 32))))
+	assert.Error(t, verify(buf.Bytes(), ""))
+
+	extracted, err := extract(buf.Bytes(), "flyctl-foo")
+	require.NoError(t, err)
+	assert.Equal(t, content, extracted)
+
+	_, err = extract(buf.Bytes(), "flyctl-bar")
+	assert.Error(t, err)
+}
+
+func TestVerifyIndex(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	key := base64.StdEncoding.EncodeToString(pub)
+	data := []byte(`{"plugins":[]}`)
+	sig := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data)) + "\n")
+
+	assert.NoError(t, verifyIndex(data, sig, key))
+	assert.Error(t, verifyIndex([]byte(`{"plugins":null}`), sig, key))
+	assert.Error(t, verifyIndex(data, []byte("not base64!"), key))
+	assert.Error(t, verifyIndex(data, sig, indexKey))
+	assert.Error(t, verifyIndex(data, sig, "c2hvcnQ="))
+}
@@ -0,0 +1,174 @@
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `List the plugins installed in the plugins directory and found on PATH. Plugins
+named like a command of flyctl are shadowed by it, and can't run.`
+		short = "List plugins"
+	)
+
+	cmd := command.New("list", short, long, runList)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, flag.Format())
+
+	return cmd
+}
+
+// found is a plugin found on the system.
+type found struct {
+	Name     string
+	Path     string
+	Shadowed bool
+}
+
+func runList(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	plugins := discover()
+
+	root := command.FromContext(ctx).Root()
+	for i := range plugins {
+		for _, cmd := range root.Commands() {
+			if cmd.Name() == plugins[i].Name || cmd.HasAlias(plugins[i].Name) {
+				plugins[i].Shadowed = true
+			}
+		}
+	}
+
+	if ok, err := render.Structured(ctx, out, plugins); ok {
+		return err
+	}
+
+	if len(plugins) == 0 {
+		fmt.Fprintln(out, "No plugins found. Install one with fly plugin install <name>, or put an executable named flyctl-<name> on PATH.")
+
+		return nil
+	}
+
+	rows := make([][]string, 0, len(plugins))
+	for _, p := range plugins {
+		status := ""
+		if p.Shadowed {
+			status = "shadowed by a command"
+		}
+
+		rows = append(rows, []string{p.Name, p.Path, status})
+	}
+
+	return render.Table(out, "", rows, "Name", "Path", "Status")
+}
+
+// discover returns the plugins in the plugins directory and on PATH. Of
+// plugins found more than once, the one which runs is returned.
+func discover() []found {
+	var dirs []string
+	if d, err := dir(); err == nil {
+		dirs = append(dirs, d)
+	}
+	dirs = append(dirs, filepath.SplitList(os.Getenv("PATH"))...)
+
+	var (
+		plugins []found
+		seen    = map[string]bool{}
+	)
+
+	for _, d := range dirs {
+		entries, err := os.ReadDir(d)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			name, ok := pluginName(entry)
+			if !ok || seen[name] {
+				continue
+			}
+
+			seen[name] = true
+			plugins = append(plugins, found{Name: name, Path: filepath.Join(d, entry.Name())})
+		}
+	}
+
+	return plugins
+}
+
+// pluginName returns the name of the plugin entry is the executable of, or
+// false when it isn't one.
+func pluginName(entry fs.DirEntry) (string, bool) {
+	name := entry.Name()
+	if !strings.HasPrefix(name, prefix) || entry.IsDir() {
+		return "", false
+	}
+
+	if runtime.GOOS == "windows" {
+		if !strings.HasSuffix(strings.ToLower(name), ".exe") {
+			return "", false
+		}
+
+		name = name[:len(name)-len(".exe")]
+	} else if info, err := entry.Info(); err != nil || info.Mode()&0o111 == 0 {
+		return "", false
+	}
+
+	name = strings.TrimPrefix(name, prefix)
+
+	return name, name != ""
+}
+
+func newRemove() *cobra.Command {
+	const (
+		long = `Remove a plugin from the plugins directory. Plugins found on PATH aren't
+removed.`
+		short = "Remove an installed plugin"
+		usage = "remove <name>"
+	)
+
+	cmd := command.New(usage, short, long, runRemove)
+
+	cmd.Aliases = []string{"rm", "uninstall"}
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runRemove(ctx context.Context) error {
+	name := flag.FirstArg(ctx)
+
+	d, err := dir()
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(d, executableName(name))
+	switch err := os.Remove(path); {
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("plugin %s isn't installed", name)
+	case err != nil:
+		return err
+	}
+
+	fmt.Fprintf(iostreams.FromContext(ctx).Out, "Removed plugin %s\n", name)
+
+	return nil
+}
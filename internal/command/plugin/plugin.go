@@ -0,0 +1,194 @@
+// Package plugin implements the plugin command chain, and runs plugins as
+// commands.
+package plugin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/cli/safeexec"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/agent"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flyerr"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// prefix is the prefix of the names of plugin executables: flyctl-foo is
+// run as fly foo.
+const prefix = "flyctl-"
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing plugins. Plugins are executables named flyctl-<name>,
+found in the plugins directory of the flyctl configuration directory or on
+PATH, which run as fly <name>. They get the access token, the API URL and
+the path of the agent's socket through the FLY_ACCESS_TOKEN, FLY_API_BASE_URL
+and FLY_AGENT_SOCKET environment variables, and the path of flyctl itself
+through FLYCTL_BIN.`
+		short = "Manage plugins"
+	)
+
+	cmd := command.New("plugin", short, long, nil)
+
+	cmd.Aliases = []string{"plugins"}
+
+	cmd.AddCommand(
+		newInstall(),
+		newList(),
+		newRemove(),
+	)
+
+	return cmd
+}
+
+// Attach adds a command running the plugin args name to root, when they
+// name a plugin rather than a command of root. Plugins can't shadow commands.
+// The global flags of root preceding the name of the plugin apply to it.
+func Attach(root *cobra.Command, args []string) {
+	i := nameIndex(root.PersistentFlags(), args)
+	if i == -1 {
+		return
+	}
+
+	name := args[i]
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return
+		}
+	}
+
+	path, err := lookup(name)
+	if err != nil {
+		return
+	}
+
+	// the plugin command doesn't parse flags, so the global ones are parsed
+	// here; they're shared with the flags of the plugin command
+	if err := root.PersistentFlags().Parse(args[:i]); err != nil {
+		return
+	}
+
+	root.AddCommand(newPluginCommand(name, path, args[i+1:]))
+}
+
+// nameIndex returns the index of the first argument of args which is neither
+// a flag of flags nor the value of one, or -1 when there's none.
+func nameIndex(flags *pflag.FlagSet, args []string) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			return -1
+		case !strings.HasPrefix(arg, "-"):
+			return i
+		case strings.Contains(arg, "="):
+			continue
+		}
+
+		var f *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			f = flags.Lookup(arg[2:])
+		} else {
+			// the last of combined shorthands, like -vt, takes the value
+			f = flags.ShorthandLookup(arg[len(arg)-1:])
+		}
+
+		if f == nil {
+			return -1
+		}
+		if f.NoOptDefVal == "" {
+			i++ // skip the value
+		}
+	}
+
+	return -1
+}
+
+// dir returns the directory plugins are installed into.
+func dir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".fly", "plugins"), nil
+}
+
+// executableName returns the name of the executable of the plugin name.
+func executableName(name string) string {
+	if runtime.GOOS == "windows" {
+		return prefix + name + ".exe"
+	}
+
+	return prefix + name
+}
+
+// lookup returns the path of the executable of the plugin name, preferring
+// installed plugins over those on PATH.
+func lookup(name string) (string, error) {
+	if d, err := dir(); err == nil {
+		path := filepath.Join(d, executableName(name))
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return safeexec.LookPath(prefix + name)
+}
+
+func newPluginCommand(name, path string, args []string) *cobra.Command {
+	short := fmt.Sprintf("Run the %s plugin", name)
+
+	cmd := command.New(name, short, short+", "+path, func(ctx context.Context) error {
+		return run(ctx, path, args)
+	})
+
+	// flags are the plugin's to parse
+	cmd.DisableFlagParsing = true
+
+	return cmd
+}
+
+func run(ctx context.Context, path string, args []string) error {
+	var (
+		io  = iostreams.FromContext(ctx)
+		cfg = config.FromContext(ctx)
+	)
+
+	env := append(os.Environ(),
+		"FLY_ACCESS_TOKEN="+cfg.AccessToken,
+		"FLY_API_BASE_URL="+cfg.APIBaseURL,
+		"FLY_AGENT_SOCKET="+agent.SocketPath(ctx),
+	)
+	if self, err := os.Executable(); err == nil {
+		env = append(env, "FLYCTL_BIN="+self)
+	}
+
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Env = env
+	cmd.Stdin = io.In
+	cmd.Stdout = io.Out
+	cmd.Stderr = io.ErrOut
+
+	var exitErr *exec.ExitError
+	switch err := cmd.Run(); {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr):
+		// the plugin's own output explains the failure
+		return flyerr.ExitCodeError(exitErr.ExitCode())
+	default:
+		return fmt.Errorf("failed running plugin %s: %w", path, err)
+	}
+}
@@ -0,0 +1,31 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameIndex(t *testing.T) {
+	flags := pflag.NewFlagSet("root", pflag.ContinueOnError)
+	flags.StringP("access-token", "t", "", "")
+	flags.BoolP("verbose", "", false, "")
+
+	cases := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"foo", "-t", "x"}, 0},
+		{[]string{"-t", "x", "foo"}, 2},
+		{[]string{"--access-token=x", "foo", "bar"}, 1},
+		{[]string{"--verbose", "--access-token", "x", "foo"}, 3},
+		{[]string{"--verbose"}, -1},
+		{[]string{"--unknown", "foo"}, -1},
+		{[]string{"--", "foo"}, -1},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, nameIndex(flags, c.args), "%v", c.args)
+	}
+}
@@ -105,7 +105,7 @@ func runAttach(ctx context.Context) error {
 
 	dialer, err := agentclient.Dialer(ctx, pgApp.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("ssh: can't build tunnel for %s: %s", pgApp.Organization.Slug, err)
+		return fmt.Errorf("ssh: can't build tunnel for %s: %w", pgApp.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
@@ -117,7 +117,7 @@ func runAttach(ctx context.Context) error {
 		}
 		pgInstances, err := agentclient.Instances(ctx, pgApp.Organization.Slug, pgApp.Name)
 		if err != nil {
-			return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", pgAppName, err)
+			return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", pgAppName, err)
 		}
 		if len(pgInstances.Addresses) == 0 {
 			return fmt.Errorf("no 6pn ips found for %s app", pgAppName)
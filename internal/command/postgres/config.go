@@ -17,9 +17,11 @@ import (
 	"github.com/superfly/flyctl/flypg"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -99,7 +101,7 @@ func runConfigView(ctx context.Context) (err error) {
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("ssh: can't build tunnel for %s: %s", app.Organization.Slug, err)
+		return fmt.Errorf("ssh: can't build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
@@ -111,7 +113,7 @@ func runConfigView(ctx context.Context) (err error) {
 		}
 		pgInstances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 		if err != nil {
-			return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", app.Name, err)
+			return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", app.Name, err)
 		}
 		if len(pgInstances.Addresses) == 0 {
 			return fmt.Errorf("no 6pn ips found for %s app", app.Name)
@@ -146,6 +148,26 @@ func runConfigView(ctx context.Context) (err error) {
 		return err
 	}
 
+	if config.FromContext(ctx).JSONOutput {
+		docs := make([]schema.PostgresSetting, 0, len(res.Settings))
+		for _, setting := range res.Settings {
+			docs = append(docs, schema.PostgresSetting{
+				Name:           strings.Replace(setting.Name, "_", "-", -1),
+				Value:          setting.Setting,
+				Unit:           setting.Unit,
+				Description:    setting.Desc,
+				Type:           setting.VarType,
+				Min:            setting.MinVal,
+				Max:            setting.MaxVal,
+				EnumValues:     setting.EnumVals,
+				PendingChange:  setting.PendingChange,
+				PendingRestart: setting.PendingRestart,
+			})
+		}
+
+		return render.JSON(io.Out, docs)
+	}
+
 	pendingRestart := false
 
 	rows := make([][]string, 0, len(res.Settings))
@@ -296,14 +318,14 @@ func runConfigUpdate(ctx context.Context) (err error) {
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("ssh: can't build tunnel for %s: %s", app.Organization.Slug, err)
+		return fmt.Errorf("ssh: can't build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 
 	ctx = agent.DialerWithContext(ctx, dialer)
 
 	pgInstances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 	if err != nil {
-		return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", app.Name, err)
+		return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", app.Name, err)
 	}
 	if len(pgInstances.Addresses) == 0 {
 		return fmt.Errorf("no 6pn ips found for %s app", app.Name)
@@ -484,7 +506,7 @@ func updateNomadConfig(ctx context.Context, app *api.AppCompact, changes map[str
 
 	pgInstances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 	if err != nil {
-		return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", app.Name, err)
+		return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", app.Name, err)
 	}
 	if len(pgInstances.Addresses) == 0 {
 		return fmt.Errorf("no 6pn ips found for %s app", app.Name)
@@ -13,6 +13,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/ssh"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/proxy"
 )
 
 func newConnect() *cobra.Command {
@@ -48,6 +49,19 @@ func newConnect() *cobra.Command {
 			Shorthand:   "p",
 			Description: "The postgres user password",
 		},
+		flag.Bool{
+			Name:        "proxy-only",
+			Description: "Proxy a local port to the leader instead of opening a console",
+		},
+		flag.String{
+			Name:        "local-port",
+			Description: "The local port to proxy from with --proxy-only",
+			Default:     "5432",
+		},
+		flag.Duration{
+			Name:        "idle-timeout",
+			Description: "Close the proxy after this long without traffic, e.g. 30m. Disabled by default",
+		},
 	)
 
 	return cmd
@@ -79,7 +93,7 @@ func runConnect(ctx context.Context) error {
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("failed to build tunnel for %s: %v", app.Organization.Slug, err)
+		return fmt.Errorf("failed to build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
@@ -91,7 +105,7 @@ func runConnect(ctx context.Context) error {
 		}
 		pgInstances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 		if err != nil {
-			return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", app.Name, err)
+			return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", app.Name, err)
 		}
 		if len(pgInstances.Addresses) == 0 {
 			return fmt.Errorf("no 6pn ips found for %s app", app.Name)
@@ -119,6 +133,17 @@ func runConnect(ctx context.Context) error {
 		return fmt.Errorf("platform %s is not supported", app.PlatformVersion)
 	}
 
+	if flag.GetBool(ctx, "proxy-only") {
+		return proxy.Connect(ctx, &proxy.ConnectParams{
+			Ports:            []string{flag.GetString(ctx, "local-port"), "5432"},
+			AppName:          app.Name,
+			OrganizationSlug: app.Organization.Slug,
+			Dialer:           dialer,
+			RemoteHost:       leaderIp,
+			IdleTimeout:      flag.GetDuration(ctx, "idle-timeout"),
+		})
+	}
+
 	database := flag.GetString(ctx, "database")
 	user := flag.GetString(ctx, "user")
 	password := flag.GetString(ctx, "password")
@@ -12,6 +12,7 @@ import (
 	"github.com/superfly/flyctl/internal/command/apps"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/statuspage"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -117,6 +118,8 @@ func runCreate(ctx context.Context) (err error) {
 		return
 	}
 
+	statuspage.WarnRegions(ctx, io.ErrOut, region.Code)
+
 	input := &flypg.CreateClusterInput{
 		AppName:      appName,
 		Organization: org,
@@ -64,7 +64,7 @@ func runDetach(ctx context.Context) error {
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("can't build tunnel for %s: %s", app.Organization.Slug, err)
+		return fmt.Errorf("can't build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
@@ -76,7 +76,7 @@ func runDetach(ctx context.Context) error {
 		}
 		pgInstances, err := agentclient.Instances(ctx, pgApp.Organization.Slug, pgApp.Name)
 		if err != nil {
-			return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", pgAppName, err)
+			return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", pgAppName, err)
 		}
 		if len(pgInstances.Addresses) == 0 {
 			return fmt.Errorf("no 6pn ips found for %s app", pgAppName)
@@ -8,7 +8,7 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
-	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/format"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -24,6 +24,10 @@ func newList() *cobra.Command {
 
 	cmd := command.New(usage, short, long, runList)
 
+	flag.Add(cmd,
+		flag.Format(),
+	)
+
 	return cmd
 }
 
@@ -31,7 +35,6 @@ func runList(ctx context.Context) (err error) {
 	var (
 		client = client.FromContext(ctx).API()
 		io     = iostreams.FromContext(ctx)
-		cfg    = config.FromContext(ctx)
 	)
 
 	apps, err := client.GetApps(ctx, api.StringPointer("postgres_cluster"))
@@ -45,8 +48,8 @@ func runList(ctx context.Context) (err error) {
 	}
 
 	// if --json
-	if cfg.JSONOutput {
-		return render.JSON(io.Out, apps)
+	if ok, err := render.Structured(ctx, io.Out, apps); ok {
+		return err
 	}
 
 	rows := make([][]string, 0, len(apps))
@@ -82,7 +82,7 @@ func runListUsers(ctx context.Context) (err error) {
 
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
-		return fmt.Errorf("ssh: can't build tunnel for %s: %s", app.Organization.Slug, err)
+		return fmt.Errorf("ssh: can't build tunnel for %s: %w", app.Organization.Slug, err)
 	}
 	ctx = agent.DialerWithContext(ctx, dialer)
 
@@ -94,7 +94,7 @@ func runListUsers(ctx context.Context) (err error) {
 		}
 		pgInstances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 		if err != nil {
-			return fmt.Errorf("failed to lookup 6pn ip for %s app: %v", app.Name, err)
+			return fmt.Errorf("failed to lookup 6pn ip for %s app: %w", app.Name, err)
 		}
 		if len(pgInstances.Addresses) == 0 {
 			return fmt.Errorf("no 6pn ips found for %s app", app.Name)
@@ -4,9 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/sync/errgroup"
+
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
@@ -18,14 +25,31 @@ import (
 
 func New() *cobra.Command {
 	var (
-		long  = strings.Trim(`Proxies connections to a fly VM through a Wireguard tunnel The current application DNS is the default remote host`, "\n")
+		long = strings.Trim(`Proxies connections to a fly VM through a Wireguard tunnel The current application DNS is the default remote host.
+
+Several ports can be proxied at once over the same tunnel, each given as
+local[:remote_host]:remote, e.g. 5432:mydb.internal:5432 6379:myredis.internal:6379,
+or listed one per line in a file passed with --mappings. Append /udp to a
+mapping to proxy UDP rather than TCP, e.g. 5353:53/udp.
+
+To proxy to several apps of the same organization, repeat --app once per
+mapping: the nth mapping defaults to the nth app, e.g.
+fly proxy 5432:5432 6379:6379 -a mydb -a myredis.
+
+The remote host of the legacy form, fly proxy 5432 mydb.internal, may be an
+IPv6 address, e.g. fly proxy 5432 fdaa:0:1::3 or [2001:db8::1].
+
+With --unix-socket, the local end of a single mapping is a Unix domain socket
+at the given path rather than a TCP port, e.g.
+fly proxy 5432 mydb.internal --unix-socket /tmp/mydb.sock. The socket is
+removed when the proxy exits.`, "\n")
 		short = `Proxies connections to a fly VM`
 	)
 
-	cmd := command.New("proxy <local:remote> [remote_host]", short, long, run,
+	cmd := command.New("proxy <local:remote>... [remote_host]", short, long, run,
 		command.RequireSession, command.LoadAppNameIfPresent)
 
-	cmd.Args = cobra.RangeArgs(1, 2)
+	cmd.Args = cobra.ArbitraryArgs
 
 	flag.Add(cmd,
 		flag.App(),
@@ -42,11 +66,53 @@ func New() *cobra.Command {
 			Shorthand:   "q",
 			Description: "Don't print progress indicators for WireGuard",
 		},
+		flag.Duration{
+			Name:        "idle-timeout",
+			Description: "Close the proxy after this long without traffic, e.g. 30m. Disabled by default",
+		},
+		flag.String{
+			Name:        "mappings",
+			Description: "Path to a file of port mappings to proxy, one local[:remote_host]:remote[/udp] per line",
+		},
+		flag.String{
+			Name:        "unix-socket",
+			Description: "Listen on a Unix domain socket at this path instead of a local port",
+		},
 	)
 
+	app := cmd.Flags().Lookup(flag.AppName)
+	app.Value = &appsValue{Value: app.Value}
+
 	return cmd
 }
 
+// appsValue is the value of --app, which may be repeated to pair the nth port
+// mapping with the nth app. The app of the command is the first one given.
+type appsValue struct {
+	pflag.Value
+	apps []string
+}
+
+func (v *appsValue) Set(val string) error {
+	if len(v.apps) == 0 {
+		if err := v.Value.Set(val); err != nil {
+			return err
+		}
+	}
+	v.apps = append(v.apps, val)
+
+	return nil
+}
+
+// appsFromFlag returns the apps given with --app, in order.
+func appsFromFlag(ctx context.Context) []string {
+	if v, ok := flag.FromContext(ctx).Lookup(flag.AppName).Value.(*appsValue); ok {
+		return v.apps
+	}
+
+	return nil
+}
+
 func run(ctx context.Context) (err error) {
 	client := client.FromContext(ctx).API()
 	appName := app.NameFromContext(ctx)
@@ -82,6 +148,22 @@ func run(ctx context.Context) (err error) {
 		orgSlug = app.Organization.Slug
 	}
 
+	// the apps share the tunnel, so they must belong to the same organization
+	apps := appsFromFlag(ctx)
+	if len(apps) > 1 {
+		for _, name := range apps[1:] {
+			app, err := client.GetAppBasic(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			if app.Organization.Slug != orgSlug {
+				return fmt.Errorf("%s belongs to organization %s rather than %s; only apps of the same organization can be proxied to at once",
+					name, app.Organization.Slug, orgSlug)
+			}
+		}
+	}
+
 	agentclient, err := agent.Establish(ctx, client)
 	if err != nil {
 		return err
@@ -98,21 +180,149 @@ func run(ctx context.Context) (err error) {
 		return err
 	}
 
-	ports := strings.Split(args[0], ":")
+	defaultHost := fmt.Sprintf("%s.internal", appName)
+
+	// the legacy form takes the remote host as a separate argument
+	if len(args) == 2 && isRemoteHost(args[1]) {
+		defaultHost = strings.Trim(args[1], "[]")
+		args = args[:1]
+	}
+
+	specs := args
+	if path := flag.GetString(ctx, "mappings"); path != "" {
+		fromFile, err := readMappings(path)
+		if err != nil {
+			return err
+		}
+		specs = append(specs, fromFile...)
+	}
+
+	if len(specs) == 0 {
+		return errors.New("specify at least one port mapping, or a file of them with --mappings")
+	}
+
+	if len(apps) > 1 && len(apps) != len(specs) {
+		return fmt.Errorf("%d apps were given for %d port mappings; give one --app per mapping, in the same order", len(apps), len(specs))
+	}
+
+	socketPath := flag.GetString(ctx, "unix-socket")
+	if socketPath != "" && len(specs) > 1 {
+		return errors.New("--unix-socket takes a single port mapping")
+	}
+
+	var servers []*proxy.Server
+	for i, spec := range specs {
+		mappingApp, mappingHost := appName, defaultHost
+		if len(apps) > 1 {
+			mappingApp, mappingHost = apps[i], fmt.Sprintf("%s.internal", apps[i])
+		}
+
+		m, err := parseMapping(spec, mappingHost)
+		if err != nil {
+			return err
+		}
+
+		if socketPath != "" {
+			if m.network == "udp" {
+				return errors.New("UDP can't be proxied from a Unix domain socket")
+			}
+
+			// the socket replaces the local port, which defaults to the remote one
+			m.ports = []string{socketPath, m.ports[len(m.ports)-1]}
+		}
+
+		server, err := proxy.NewServer(ctx, &proxy.ConnectParams{
+			Ports:            m.ports,
+			AppName:          mappingApp,
+			OrganizationSlug: orgSlug,
+			Dialer:           dialer,
+			PromptInstance:   promptInstance,
+			IdleTimeout:      flag.GetDuration(ctx, "idle-timeout"),
+			RemoteHost:       m.host,
+			Network:          m.network,
+		})
+		if err != nil {
+			return err
+		}
+
+		servers = append(servers, server)
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, server := range servers {
+		server := server
+		eg.Go(func() error {
+			return server.ProxyServer(ctx)
+		})
+	}
+
+	return eg.Wait()
+}
+
+// mappingRx matches local[:remote_host][:remote][/protocol] port mappings.
+// IPv6 remote hosts are enclosed in brackets.
+var mappingRx = regexp.MustCompile(`^([^:\[\]]+?)(?::(\[[0-9a-fA-F:]+\]|[^:\[\]/]+))?(?::([0-9]+))?(?:/(tcp|udp))?$`)
+
+type mapping struct {
+	ports   []string
+	host    string
+	network string
+}
+
+func parseMapping(spec, defaultHost string) (*mapping, error) {
+	match := mappingRx.FindStringSubmatch(spec)
+	if match == nil {
+		return nil, fmt.Errorf("invalid port mapping %q; expected local[:remote_host][:remote][/udp]", spec)
+	}
+
+	local, middle, remote, network := match[1], match[2], match[3], match[4]
+
+	m := &mapping{ports: []string{local}, host: defaultHost, network: network}
 
-	params := &proxy.ConnectParams{
-		Ports:            ports,
-		AppName:          appName,
-		OrganizationSlug: orgSlug,
-		Dialer:           dialer,
-		PromptInstance:   promptInstance,
+	switch {
+	case remote != "":
+		m.host = strings.Trim(middle, "[]")
+		m.ports = append(m.ports, remote)
+	case middle == "":
+	case isNumeric(middle):
+		m.ports = append(m.ports, middle)
+	default:
+		m.host = strings.Trim(middle, "[]")
 	}
 
-	if len(args) > 1 {
-		params.RemoteHost = args[1]
-	} else {
-		params.RemoteHost = fmt.Sprintf("%s.internal", appName)
+	return m, nil
+}
+
+// readMappings reads port mappings from a file, one per line. Blank lines and
+// lines starting with # are skipped.
+func readMappings(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading port mappings: %w", err)
 	}
 
-	return proxy.Connect(ctx, params)
+	var mappings []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			mappings = append(mappings, line)
+		}
+	}
+
+	return mappings, nil
+}
+
+// isRemoteHost reports whether arg is the remote host of the legacy form
+// rather than a port mapping: an IPv6 address, bare or in brackets, or a host
+// starting with neither a port nor a socket path.
+func isRemoteHost(arg string) bool {
+	if ip := net.ParseIP(strings.Trim(arg, "[]")); ip != nil {
+		return true
+	}
+
+	return !isNumeric(strings.SplitN(arg, ":", 2)[0]) && !strings.Contains(arg, "/")
+}
+
+func isNumeric(s string) bool {
+	_, err := strconv.Atoi(s)
+	return err == nil
 }
@@ -21,10 +21,12 @@ import (
 
 func newConnect() (cmd *cobra.Command) {
 	const (
-		long = `Connect to a Redis database using redis-cli`
+		long = `Connect to a Redis database using redis-cli, through a proxy on a local
+port. Without redis-cli, the URL to point other clients at is shown instead,
+and the proxy runs until interrupted.`
 
-		short = long
-		usage = "connect"
+		short = "Connect to a Redis database using redis-cli"
+		usage = "connect [name]"
 	)
 
 	cmd = command.New(usage, short, long, runConnect, command.RequireSession)
@@ -32,8 +34,16 @@ func newConnect() (cmd *cobra.Command) {
 	flag.Add(cmd,
 		flag.Org(),
 		flag.Region(),
+		flag.String{
+			Name:        "port",
+			Shorthand:   "p",
+			Description: "Local port to proxy the database on",
+			Default:     "16379",
+		},
 	)
 
+	cmd.Args = cobra.MaximumNArgs(1)
+
 	return cmd
 }
 
@@ -43,30 +53,32 @@ func runConnect(ctx context.Context) (err error) {
 		io     = iostreams.FromContext(ctx)
 	)
 
-	if err != nil {
-		return err
-	}
+	name := flag.FirstArg(ctx)
 
-	var index int
-	var options []string
+	if name == "" {
+		var index int
+		var options []string
 
-	result, err := gql.ListAddOns(ctx, client.GenqClient, "redis")
-	if err != nil {
-		return
-	}
+		result, err := gql.ListAddOns(ctx, client.GenqClient, "redis")
+		if err != nil {
+			return err
+		}
 
-	databases := result.AddOns.Nodes
+		databases := result.AddOns.Nodes
 
-	for _, database := range databases {
-		options = append(options, fmt.Sprintf("%s (%s) %s", database.Name, database.PrimaryRegion, database.Organization.Slug))
-	}
+		for _, database := range databases {
+			options = append(options, fmt.Sprintf("%s (%s) %s", database.Name, database.PrimaryRegion, database.Organization.Slug))
+		}
 
-	err = prompt.Select(ctx, &index, "Select a database to connect to", "", options...)
-	if err != nil {
-		return
+		err = prompt.Select(ctx, &index, "Select a database to connect to", "", options...)
+		if err != nil {
+			return err
+		}
+
+		name = databases[index].Name
 	}
 
-	response, err := gql.GetAddOn(ctx, client.GenqClient, databases[index].Name)
+	response, err := gql.GetAddOn(ctx, client.GenqClient, name)
 	if err != nil {
 		return err
 	}
@@ -83,7 +95,7 @@ func runConnect(ctx context.Context) (err error) {
 		return err
 	}
 
-	localProxyPort := "16379"
+	localProxyPort := flag.GetString(ctx, "port")
 
 	params := &proxy.ConnectParams{
 		Ports:            []string{localProxyPort, "6379"},
@@ -92,23 +104,25 @@ func runConnect(ctx context.Context) (err error) {
 		RemoteHost:       database.PrivateIp,
 	}
 
-	go proxy.Connect(ctx, params)
-
 	redisCliPath, err := exec.LookPath("redis-cli")
 	if err != nil {
-		fmt.Fprintf(io.Out, "Could not find redis-cli in your $PATH. Install it or point your redis-cli at: %s", "someurl")
-	} else {
-		// TODO: let proxy.Connect inform us about readiness
-		time.Sleep(3 * time.Second)
-		cmd := exec.CommandContext(ctx, redisCliPath, "-p", localProxyPort)
-		cmd.Env = append(cmd.Env, fmt.Sprintf("REDISCLI_AUTH=%s", database.Password))
-		cmd.Stdout = io.Out
-		cmd.Stderr = io.ErrOut
-		cmd.Stdin = io.In
-
-		cmd.Start()
-		cmd.Wait()
+		fmt.Fprintf(io.Out, "Could not find redis-cli in your $PATH. Install it or point your client at: redis://default:%s@localhost:%s\n", database.Password, localProxyPort)
+
+		return proxy.Connect(ctx, params)
 	}
 
+	go proxy.Connect(ctx, params)
+
+	// TODO: let proxy.Connect inform us about readiness
+	time.Sleep(3 * time.Second)
+	cmd := exec.CommandContext(ctx, redisCliPath, "-p", localProxyPort)
+	cmd.Env = append(cmd.Env, fmt.Sprintf("REDISCLI_AUTH=%s", database.Password))
+	cmd.Stdout = io.Out
+	cmd.Stderr = io.ErrOut
+	cmd.Stdin = io.In
+
+	cmd.Start()
+	cmd.Wait()
+
 	return
 }
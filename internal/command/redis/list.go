@@ -13,6 +13,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
 )
 
 func newList() (cmd *cobra.Command) {
@@ -26,6 +27,7 @@ func newList() (cmd *cobra.Command) {
 
 	flag.Add(cmd,
 		flag.Org(),
+		flag.Format(),
 	)
 
 	return cmd
@@ -58,6 +60,25 @@ func runList(ctx context.Context) (err error) {
 		}
 	`
 	response, err := gql.ListAddOns(ctx, client, "redis")
+	if err != nil {
+		return err
+	}
+
+	databases := make([]schema.RedisDatabase, 0, len(response.AddOns.Nodes))
+	for _, addon := range response.AddOns.Nodes {
+		databases = append(databases, schema.RedisDatabase{
+			ID:            addon.Id,
+			Name:          addon.Name,
+			Organization:  addon.Organization.Slug,
+			Plan:          addon.AddOnPlan.DisplayName,
+			PrimaryRegion: addon.PrimaryRegion,
+			ReadRegions:   addon.ReadRegions,
+		})
+	}
+
+	if ok, err := render.Structured(ctx, out, databases); ok {
+		return err
+	}
 
 	var rows [][]string
 
@@ -9,8 +9,10 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/gql"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -49,6 +51,23 @@ func runPlans(ctx context.Context) (err error) {
 	`
 
 	result, err := gql.ListAddOnPlans(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		plans := make([]schema.RedisPlan, 0, len(result.AddOnPlans.Nodes))
+		for _, plan := range result.AddOnPlans.Nodes {
+			plans = append(plans, schema.RedisPlan{
+				ID:            plan.Id,
+				Name:          plan.DisplayName,
+				MaxDataSize:   plan.MaxDataSize,
+				PricePerMonth: plan.PricePerMonth,
+			})
+		}
+
+		return render.JSON(out, plans)
+	}
 
 	var rows [][]string
 
@@ -0,0 +1,168 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/parallel"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newResetPassword() (cmd *cobra.Command) {
+	const (
+		long = `Reset the password of an Upstash Redis database, set the secret of the apps
+attached to it to its new URL, and deploy them for it to take effect. Apps are
+attached when they have the secret, in the organization of the database,
+unless --app names them. Clients still using the old password can't connect
+anymore. This needs the API to reset passwords, which it doesn't everywhere
+yet.`
+
+		short = "Reset the password of a Redis database"
+		usage = "reset-password <name>"
+	)
+
+	cmd = command.New(usage, short, long, runResetPassword, command.RequireAPI("Mutations", "resetAddOnPassword"))
+
+	flag.Add(cmd,
+		flag.Yes(),
+		flag.StringSlice{
+			Name:        "app",
+			Shorthand:   "a",
+			Description: "App whose secret to set to the new URL, rather than those found to have it, comma separated or repeated",
+		},
+		flag.String{
+			Name:        "secret",
+			Description: "Name of the secret holding the URL of the database in apps",
+			Default:     "REDIS_URL",
+		},
+	)
+	cmd.Args = cobra.ExactArgs(1)
+	return cmd
+}
+
+func runResetPassword(ctx context.Context) (err error) {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		name      = flag.FirstArg(ctx)
+		apps      = flag.GetStringSlice(ctx, "app")
+		secret    = flag.GetString(ctx, "secret")
+	)
+
+	response, err := gql.GetAddOn(ctx, apiClient.GenqClient, name)
+	if err != nil {
+		return
+	}
+
+	if len(apps) == 0 {
+		if apps, err = attachedApps(ctx, response.AddOn.Organization.Slug, secret); err != nil {
+			return
+		}
+	}
+
+	if !flag.GetYes(ctx) {
+		msg := fmt.Sprintf("Reset the password of %s? Clients using the old one won't be able to connect", name)
+		if len(apps) == 0 {
+			msg += fmt.Sprintf(", and no app has %s to update", secret)
+		} else {
+			msg += fmt.Sprintf(", and %s will be updated and deployed", strings.Join(apps, ", "))
+		}
+
+		switch confirmed, err := prompt.Confirm(ctx, msg+"."); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	result, err := apiClient.ResetAddOnPassword(ctx, response.AddOn.Id)
+	if err != nil {
+		return fmt.Errorf("failed resetting the password of %s: %w", name, err)
+	}
+
+	url := result.PublicUrl
+
+	fmt.Fprintf(io.Out, "The password of %s was reset.\n", name)
+
+	var failed []string
+	for _, app := range apps {
+		if err := secrets.SetAndDeploy(ctx, app, map[string]string{secret: url}); err != nil {
+			fmt.Fprintf(io.ErrOut, "%s failed updating %s of %s: %v\n", colorize.Red("✗"), secret, app, err)
+			failed = append(failed, app)
+
+			continue
+		}
+
+		fmt.Fprintf(io.Out, "%s set %s of %s, and deployed it\n", colorize.Green("✓"), secret, app)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed updating %d apps; set %s to the URL fly redis status %s shows", len(failed), secret, name)
+	}
+
+	if len(apps) == 0 {
+		fmt.Fprintf(io.Out, "Apps connect to it at %s\n", colorize.Green(url))
+	}
+
+	return
+}
+
+// attachedApps returns the names of the apps of org which have the secret.
+func attachedApps(ctx context.Context, org, secret string) ([]string, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	all, err := apiClient.GetOrganizationApps(ctx, org)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing apps: %w", err)
+	}
+
+	candidates := make([]string, 0, len(all))
+	for _, app := range all {
+		candidates = append(candidates, app.Name)
+	}
+
+	var (
+		has  = make([]bool, len(candidates))
+		errs = make([]error, len(candidates))
+	)
+	parallel.Each(len(candidates), parallel.Limit, func(i int) {
+		var appSecrets []api.Secret
+		if appSecrets, errs[i] = apiClient.GetAppSecrets(ctx, candidates[i]); errs[i] != nil {
+			return
+		}
+
+		for _, s := range appSecrets {
+			if s.Name == secret {
+				has[i] = true
+			}
+		}
+	})
+
+	var apps []string
+	for i, app := range candidates {
+		if errs[i] != nil {
+			return nil, fmt.Errorf("failed listing secrets of %s: %w", app, errs[i])
+		}
+		if has[i] {
+			apps = append(apps, app)
+		}
+	}
+
+	return apps, nil
+}
@@ -2,12 +2,17 @@ package redis
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 	"strings"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/gql"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -16,7 +21,7 @@ import (
 func newStatus() *cobra.Command {
 	const (
 		short = "Show status of a Redis database"
-		long  = short + "\n"
+		long  = short + ", along with its memory usage and eviction metrics when the API reports them\n"
 
 		usage = "status <name>"
 	)
@@ -34,18 +39,33 @@ func newStatus() *cobra.Command {
 
 func runStatus(ctx context.Context) (err error) {
 	var (
-		io     = iostreams.FromContext(ctx)
-		name   = flag.FirstArg(ctx)
-		client = client.FromContext(ctx).API().GenqClient
+		io        = iostreams.FromContext(ctx)
+		name      = flag.FirstArg(ctx)
+		apiClient = client.FromContext(ctx).API()
 	)
 
-	response, err := gql.GetAddOn(ctx, client, name)
+	response, err := gql.GetAddOn(ctx, apiClient.GenqClient, name)
 	if err != nil {
 		return err
 	}
 
 	addOn := response.AddOn
 
+	// metrics are only shown by APIs reporting them
+	var metrics *api.AddOnMetrics
+	if ok, _ := apiClient.SchemaHas(ctx, "AddOn", "metrics"); ok {
+		if metrics, err = apiClient.GetAddOnMetrics(ctx, name); err != nil {
+			return
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, struct {
+			gql.GetAddOnAddOn
+			Metrics *api.AddOnMetrics `json:"metrics,omitempty"`
+		}{addOn, metrics})
+	}
+
 	obj := [][]string{
 		{
 			addOn.Id,
@@ -59,9 +79,37 @@ func runStatus(ctx context.Context) (err error) {
 
 	var cols []string = []string{"ID", "Name", "Plan", "Primary Region", "Read Regions", "Private URL"}
 
-	if err = render.VerticalTable(io.Out, "Redis", obj, cols...); err != nil {
+	if err = render.VerticalTable(io.Out, "Redis", obj, cols...); err != nil || metrics == nil {
 		return
 	}
 
-	return
+	memory := humanize.IBytes(uint64(metrics.UsedMemoryBytes))
+	if metrics.MaxMemoryBytes > 0 {
+		memory = fmt.Sprintf("%s of %s (%.0f%%)",
+			memory,
+			humanize.IBytes(uint64(metrics.MaxMemoryBytes)),
+			float64(metrics.UsedMemoryBytes)/float64(metrics.MaxMemoryBytes)*100,
+		)
+	}
+
+	eviction := "disabled, writes fail when memory is full"
+	if metrics.EvictionEnabled {
+		eviction = "enabled"
+	}
+
+	obj = [][]string{
+		{
+			memory,
+			eviction,
+			strconv.FormatInt(metrics.EvictedKeys, 10),
+			strconv.FormatInt(metrics.Keys, 10),
+			fmt.Sprintf("%.1f%%", metrics.HitRate*100),
+			strconv.Itoa(metrics.ConnectedClients),
+			fmt.Sprintf("%.1f", metrics.CommandsPerSecond),
+		},
+	}
+
+	cols = []string{"Memory", "Eviction", "Evicted Keys", "Keys", "Hit Rate", "Clients", "Commands/s"}
+
+	return render.VerticalTable(io.Out, "Metrics", obj, cols...)
 }
@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 
@@ -17,9 +19,12 @@ import (
 
 func newUpdate() (cmd *cobra.Command) {
 	const (
-		long = `Update an Upstash Redis database`
+		long = `Update the plan and the replica regions of an Upstash Redis database.
 
-		short = long
+--plan, --add-replica and --remove-replica change the database without
+prompting, leaving what they don't cover as it is.`
+
+		short = "Update an Upstash Redis database"
 		usage = "update <name>"
 	)
 
@@ -28,6 +33,18 @@ func newUpdate() (cmd *cobra.Command) {
 	flag.Add(cmd,
 		flag.Org(),
 		flag.Region(),
+		flag.String{
+			Name:        "plan",
+			Description: "Upstash Redis plan to switch to",
+		},
+		flag.StringSlice{
+			Name:        "add-replica",
+			Description: "Region to add a replica in, comma separated or repeated",
+		},
+		flag.StringSlice{
+			Name:        "remove-replica",
+			Description: "Region to remove the replica of, comma separated or repeated",
+		},
 	)
 	cmd.Args = cobra.ExactArgs(1)
 	return cmd
@@ -69,27 +86,66 @@ func runUpdate(ctx context.Context) (err error) {
 
 	addOn := response.AddOn
 
-	readRegions, err := prompt.MultiRegion(ctx, "Choose replica regions, or unselect to remove replica regions:", addOn.ReadRegions, []string{addOn.PrimaryRegion})
-	if err != nil {
-		return
-	}
+	var (
+		planFlag       = flag.GetString(ctx, "plan")
+		addReplicas    = flag.GetStringSlice(ctx, "add-replica")
+		removeReplicas = flag.GetStringSlice(ctx, "remove-replica")
+		unattended     = planFlag != "" || len(addReplicas) > 0 || len(removeReplicas) > 0
+	)
+
+	var readRegionCodes []string
+
+	if unattended {
+		readRegionCodes, err = changeReplicas(addOn.PrimaryRegion, addOn.ReadRegions, addReplicas, removeReplicas)
+		if err != nil {
+			return
+		}
+	} else {
+		readRegions, err := prompt.MultiRegion(ctx, "Choose replica regions, or unselect to remove replica regions:", addOn.ReadRegions, []string{addOn.PrimaryRegion})
+		if err != nil {
+			return err
+		}
 
-	var index int
-	var promptOptions []string
+		for _, region := range *readRegions {
+			readRegionCodes = append(readRegionCodes, region.Code)
+		}
+	}
 
 	result, err := gql.ListAddOnPlans(ctx, client)
 	if err != nil {
 		return
 	}
 
-	for _, plan := range result.AddOnPlans.Nodes {
-		promptOptions = append(promptOptions, fmt.Sprintf("%s: %s Max Data Size, $%d/month/region", plan.DisplayName, plan.MaxDataSize, plan.PricePerMonth))
-	}
+	planID := addOn.AddOnPlan.Id
 
-	err = prompt.Select(ctx, &index, "Select an Upstash Redis plan", "", promptOptions...)
+	switch {
+	case planFlag != "":
+		planID = ""
+		for _, plan := range result.AddOnPlans.Nodes {
+			if strings.EqualFold(plan.DisplayName, planFlag) {
+				planID = plan.Id
+				break
+			}
+		}
 
-	if err != nil {
-		return fmt.Errorf("failed to select a plan: %w", err)
+		if planID == "" {
+			return fmt.Errorf("invalid plan name: %s", planFlag)
+		}
+	case !unattended:
+		var index int
+		var promptOptions []string
+
+		for _, plan := range result.AddOnPlans.Nodes {
+			promptOptions = append(promptOptions, fmt.Sprintf("%s: %s Max Data Size, $%d/month/region", plan.DisplayName, plan.MaxDataSize, plan.PricePerMonth))
+		}
+
+		err = prompt.Select(ctx, &index, "Select an Upstash Redis plan", "", promptOptions...)
+
+		if err != nil {
+			return fmt.Errorf("failed to select a plan: %w", err)
+		}
+
+		planID = result.AddOnPlans.Nodes[index].Id
 	}
 
 	_ = `# @genqlient
@@ -102,13 +158,11 @@ func runUpdate(ctx context.Context) (err error) {
   }
 	`
 
-	readRegionCodes := []string{}
-
-	for _, region := range *readRegions {
-		readRegionCodes = append(readRegionCodes, region.Code)
+	if readRegionCodes == nil {
+		readRegionCodes = []string{}
 	}
 
-	_, err = gql.UpdateAddOn(ctx, client, addOn.Id, result.AddOnPlans.Nodes[index].Id, readRegionCodes)
+	_, err = gql.UpdateAddOn(ctx, client, addOn.Id, planID, readRegionCodes)
 
 	if err != nil {
 		return
@@ -118,3 +172,34 @@ func runUpdate(ctx context.Context) (err error) {
 
 	return
 }
+
+// changeReplicas returns the replica regions of a database once those of add
+// are added and those of remove are removed.
+func changeReplicas(primary string, current, add, remove []string) ([]string, error) {
+	regions := map[string]bool{}
+	for _, code := range current {
+		regions[code] = true
+	}
+
+	for _, code := range add {
+		if code == primary {
+			return nil, fmt.Errorf("%s is the primary region, so it can't have a replica", code)
+		}
+		regions[code] = true
+	}
+
+	for _, code := range remove {
+		if !regions[code] {
+			return nil, fmt.Errorf("there's no replica in %s to remove", code)
+		}
+		delete(regions, code)
+	}
+
+	codes := make([]string, 0, len(regions))
+	for code := range regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return codes, nil
+}
@@ -0,0 +1,319 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flypg"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/command/postgres"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		long = `Create the review app of pull request --pr of an app, or update it when it
+exists, by deploying the working directory, which should be the pull request's
+branch, with the app's config.
+
+The review app is named after the template stored for the app, which defaults
+to {app}-pr-{pr} and may also contain {branch}; --name-template stores another.
+Secrets of the app aren't copied. With --postgres, the review app gets a fork
+of that Postgres cluster, restored from its latest snapshot, as DATABASE_URL.
+
+The URL of the review app is shown, and with --comment, posted on the pull
+request when running in GitHub Actions, with GITHUB_TOKEN set.`
+		short = "Create or update the review app of a pull request"
+	)
+
+	cmd := command.New("create", short, long, runCreate,
+		requireReview,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Image(),
+		flag.Int{
+			Name:        "pr",
+			Description: "Number of the pull request",
+		},
+		flag.String{
+			Name:        "branch",
+			Description: "Branch of the pull request, defaults to the checked out one",
+		},
+		flag.String{
+			Name:        "name-template",
+			Description: "Template of the names of review apps to store, like {app}-pr-{pr}",
+		},
+		flag.String{
+			Name:        "postgres",
+			Description: "Postgres cluster app to fork from its latest snapshot for the review app",
+		},
+		flag.String{
+			Name:        "database-name",
+			Description: "Database of the Postgres fork DATABASE_URL points to, defaults to the app's",
+		},
+		flag.Bool{
+			Name:        "comment",
+			Description: "Post the URL of the review app on the pull request in GitHub Actions",
+		},
+		deploy.CommonFlags,
+	)
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		appName   = app.NameFromContext(ctx)
+		pr        = flag.GetInt(ctx, "pr")
+		template  = flag.GetString(ctx, "name-template")
+	)
+
+	if pr <= 0 {
+		return errNoPullRequest
+	}
+
+	base, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	reviews, err := apiClient.GetReviewApps(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving review apps of %s: %w", appName, err)
+	}
+
+	branch := flag.GetString(ctx, "branch")
+	if branch == "" {
+		branch = currentBranch(ctx)
+	}
+
+	input := api.RecordReviewAppInput{
+		AppID:        appName,
+		PullRequest:  pr,
+		Branch:       branch,
+		NameTemplate: template,
+	}
+
+	if existing := find(reviews, pr); existing != nil {
+		input.Name = existing.Name
+		input.PostgresApp = existing.PostgresApp
+	} else {
+		if template == "" {
+			template = reviews.NameTemplate
+		}
+		if template == "" {
+			template = defaultNameTemplate
+		}
+
+		if input.Name, err = reviewName(template, appName, pr, branch); err != nil {
+			return err
+		}
+
+		_, err := apiClient.CreateApp(ctx, api.CreateAppInput{
+			OrganizationID: base.Organization.ID,
+			Name:           input.Name,
+			Machines:       base.PlatformVersion == app.MachinesPlatform,
+		})
+		if err != nil {
+			return fmt.Errorf("failed creating review app %s: %w", input.Name, err)
+		}
+
+		fmt.Fprintf(io.Out, "Created review app %s for pull request #%d\n", colorize.Green(input.Name), pr)
+	}
+
+	// recorded before anything else can fail, so fly review gc finds it
+	if _, err := apiClient.RecordReviewApp(ctx, input); err != nil {
+		return fmt.Errorf("failed recording review app %s: %w", input.Name, err)
+	}
+
+	if pg := flag.GetString(ctx, "postgres"); pg != "" && input.PostgresApp == "" {
+		input.PostgresApp, err = forkPostgres(ctx, base, pg, input.Name)
+		if input.PostgresApp != "" {
+			if _, err := apiClient.RecordReviewApp(ctx, input); err != nil {
+				return fmt.Errorf("failed recording review app %s: %w", input.Name, err)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("failed forking Postgres cluster %s: %w", pg, err)
+		}
+	}
+
+	cfg, err := reviewConfig(ctx, base, input.Name)
+	if err != nil {
+		return err
+	}
+
+	ctx = app.WithName(ctx, input.Name)
+	ctx = app.WithConfig(ctx, cfg)
+
+	if err := deploy.DeployWithConfig(ctx, cfg); err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://%s.fly.dev", input.Name)
+
+	if flag.GetBool(ctx, "comment") {
+		body := fmt.Sprintf("Review app for this pull request deployed to %s", url)
+		if err := commentOnPullRequest(ctx, pr, body); err != nil {
+			fmt.Fprintf(io.ErrOut, "failed commenting on pull request #%d: %v\n", pr, err)
+		}
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, struct {
+			Name        string `json:"name"`
+			PullRequest int    `json:"pullRequest"`
+			URL         string `json:"url"`
+		}{input.Name, pr, url})
+	}
+
+	fmt.Fprintf(io.Out, "Review app of pull request #%d is deployed to %s\n", pr, colorize.Green(url))
+
+	return nil
+}
+
+// reviewConfig returns the config review app name deploys with: the local
+// config, or in its absence, the config of base.
+func reviewConfig(ctx context.Context, base *api.AppCompact, name string) (*app.Config, error) {
+	var cfg app.Config
+
+	if local := app.ConfigFromContext(ctx); local != nil {
+		cfg = *local
+	} else {
+		apiConfig, err := client.FromContext(ctx).API().GetConfig(ctx, base.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed fetching app config of %s: %w", base.Name, err)
+		}
+
+		cfg.Definition = apiConfig.Definition
+	}
+
+	cfg.AppName = name
+	cfg.SetPlatformVersion(base.PlatformVersion)
+
+	return &cfg, nil
+}
+
+// forkPostgres launches a fork of the Postgres cluster pgApp for review app
+// name, restored from the latest snapshot of its volumes, and sets the
+// DATABASE_URL of the review app to it. It returns the name of the fork,
+// which is set when it was created even if launching it failed.
+func forkPostgres(ctx context.Context, base *api.AppCompact, pgApp, name string) (string, error) {
+	apiClient := client.FromContext(ctx).API()
+
+	volumes, err := apiClient.GetVolumes(ctx, pgApp)
+	if err != nil {
+		return "", fmt.Errorf("failed retrieving volumes: %w", err)
+	}
+
+	var (
+		source *api.Snapshot
+		region string
+		sizeGb int
+	)
+	for _, volume := range volumes {
+		snapshots, err := apiClient.GetVolumeSnapshots(ctx, volume.ID)
+		if err != nil {
+			return "", fmt.Errorf("failed retrieving snapshots of volume %s: %w", volume.ID, err)
+		}
+
+		for i := range snapshots {
+			if source == nil || snapshots[i].CreatedAt.After(source.CreatedAt) {
+				source, region, sizeGb = &snapshots[i], volume.Region, volume.SizeGb
+			}
+		}
+	}
+
+	if source == nil {
+		return "", fmt.Errorf("%s has no snapshots to fork", pgApp)
+	}
+
+	org, err := apiClient.GetOrganizationBySlug(ctx, base.Organization.Slug)
+	if err != nil {
+		return "", err
+	}
+
+	password, err := helpers.RandString(24)
+	if err != nil {
+		return "", err
+	}
+
+	forkName := name
+	if len(forkName) > maxNameLength-3 {
+		forkName = strings.TrimRight(forkName[:maxNameLength-3], "-")
+	}
+	forkName += "-db"
+
+	vmSize := postgres.MachineVMSizes()[0]
+
+	input := &flypg.CreateClusterInput{
+		AppName:            forkName,
+		Organization:       org,
+		ImageRef:           "flyio/postgres",
+		Region:             region,
+		InitialClusterSize: 1,
+		Password:           password,
+		VolumeSize:         api.IntPointer(sizeGb),
+		VMSize:             &vmSize,
+		SnapshotID:         &source.ID,
+	}
+
+	if err := flypg.NewLauncher(apiClient).LaunchMachinesPostgres(ctx, input); err != nil {
+		return forkName, err
+	}
+
+	database := flag.GetString(ctx, "database-name")
+	if database == "" {
+		database = strings.ToLower(strings.ReplaceAll(base.Name, "-", "_"))
+	}
+
+	url := fmt.Sprintf("postgres://postgres:%s@%s.internal:5432/%s", password, forkName, database)
+
+	if _, err := apiClient.SetSecrets(ctx, name, map[string]string{"DATABASE_URL": url}); err != nil {
+		return forkName, fmt.Errorf("failed setting DATABASE_URL of %s: %w", name, err)
+	}
+
+	return forkName, nil
+}
+
+// currentBranch returns the head branch of the pull request GitHub Actions is
+// running for, or else the branch checked out in the working directory, or an
+// empty string when it can't be told.
+func currentBranch(ctx context.Context) string {
+	if branch := os.Getenv("GITHUB_HEAD_REF"); branch != "" {
+		return branch
+	}
+
+	out, err := exec.CommandContext(ctx, "git", "rev-parse", "--abbrev-ref", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+
+	if branch := strings.TrimSpace(string(out)); branch != "HEAD" {
+		return branch
+	}
+
+	return ""
+}
@@ -0,0 +1,193 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newDestroy() *cobra.Command {
+	const (
+		long = `Destroy the review app of pull request --pr of an app, along with its fork of
+Postgres, such as once the pull request is closed.`
+		short = "Destroy the review app of a pull request"
+	)
+
+	cmd := command.New("destroy", short, long, runDestroy,
+		requireReview,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Int{
+			Name:        "pr",
+			Description: "Number of the pull request",
+		},
+	)
+
+	return cmd
+}
+
+func runDestroy(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = app.NameFromContext(ctx)
+		pr      = flag.GetInt(ctx, "pr")
+	)
+
+	if pr <= 0 {
+		return errNoPullRequest
+	}
+
+	reviews, err := client.FromContext(ctx).API().GetReviewApps(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving review apps of %s: %w", appName, err)
+	}
+
+	r := find(reviews, pr)
+	if r == nil {
+		return fmt.Errorf("pull request #%d of %s has no review app", pr, appName)
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy review app %s of pull request #%d?", r.Name, pr); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := destroy(ctx, appName, r); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Destroyed review app %s of pull request #%d\n", r.Name, pr)
+
+	return nil
+}
+
+func newGC() *cobra.Command {
+	const (
+		long = `Destroy the stale review apps of an app: those not deployed for --older-than,
+and when --open is given, those whose pull request isn't one of them.`
+		short = "Destroy stale review apps"
+	)
+
+	cmd := command.New("gc", short, long, runGC,
+		requireReview,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Yes(),
+		flag.Duration{
+			Name:        "older-than",
+			Description: "Destroy review apps not deployed for this long",
+			Default:     7 * 24 * time.Hour,
+		},
+		flag.StringSlice{
+			Name:        "open",
+			Description: "Numbers of the open pull requests, whose review apps are kept, comma separated or repeated",
+		},
+	)
+
+	return cmd
+}
+
+func runGC(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		colorize = io.ColorScheme()
+		appName  = app.NameFromContext(ctx)
+		cutoff   = time.Now().Add(-flag.GetDuration(ctx, "older-than"))
+		openPRs  = flag.GetStringSlice(ctx, "open")
+	)
+
+	var open map[int]bool
+	if len(openPRs) > 0 {
+		open = make(map[int]bool, len(openPRs))
+		for _, s := range openPRs {
+			pr, err := strconv.Atoi(s)
+			if err != nil {
+				return fmt.Errorf("invalid pull request number %q", s)
+			}
+			open[pr] = true
+		}
+	}
+
+	reviews, err := client.FromContext(ctx).API().GetReviewApps(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving review apps of %s: %w", appName, err)
+	}
+
+	var stale []api.ReviewApp
+	for _, r := range reviews.Nodes {
+		if r.UpdatedAt.Before(cutoff) || (open != nil && !open[r.PullRequest]) {
+			stale = append(stale, r)
+		}
+	}
+
+	if len(stale) == 0 {
+		fmt.Fprintln(io.Out, "No review apps are stale")
+
+		return nil
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Destroy %d stale review apps of %s?", len(stale), appName); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	failed := 0
+	for i := range stale {
+		r := &stale[i]
+
+		if err := destroy(ctx, appName, r); err != nil {
+			fmt.Fprintf(io.ErrOut, "%s %v\n", colorize.Red("✗"), err)
+			failed++
+
+			continue
+		}
+
+		fmt.Fprintf(io.Out, "%s destroyed %s of pull request #%d\n", colorize.Green("✓"), r.Name, r.PullRequest)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed destroying %d review apps", failed)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// commentOnPullRequest posts body as a comment of pull request pr of the
+// repository GitHub Actions is running for.
+func commentOnPullRequest(ctx context.Context, pr int, body string) error {
+	var (
+		token = os.Getenv("GITHUB_TOKEN")
+		repo  = os.Getenv("GITHUB_REPOSITORY")
+	)
+
+	if token == "" || repo == "" {
+		return errors.New("GITHUB_TOKEN and GITHUB_REPOSITORY must be set")
+	}
+
+	data, err := json.Marshal(map[string]string{"body": body})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/issues/%d/comments", repo, pr)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		return errors.New(res.Status)
+	}
+
+	return nil
+}
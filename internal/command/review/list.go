@@ -0,0 +1,75 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long  = `List the review apps of an app, along with the template their names follow.`
+		short = "List the review apps of an app"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		requireReview,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"ls"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Format(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		appName = app.NameFromContext(ctx)
+	)
+
+	reviews, err := client.FromContext(ctx).API().GetReviewApps(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving review apps of %s: %w", appName, err)
+	}
+
+	if reviews.NameTemplate == "" {
+		reviews.NameTemplate = defaultNameTemplate
+	}
+
+	if ok, err := render.Structured(ctx, io.Out, reviews); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(reviews.Nodes))
+	for _, r := range reviews.Nodes {
+		rows = append(rows, []string{
+			strconv.Itoa(r.PullRequest),
+			r.Name,
+			r.Branch,
+			r.PostgresApp,
+			format.RelativeTime(r.UpdatedAt),
+		})
+	}
+
+	title := fmt.Sprintf("Review apps of %s, named like %s", appName, reviews.NameTemplate)
+
+	return render.Table(io.Out, title, rows, "PR", "Name", "Branch", "Postgres", "Deployed")
+}
@@ -0,0 +1,141 @@
+// Package review implements the review command chain.
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// defaultNameTemplate is the template the names of review apps follow unless
+// another is stored for the app.
+const defaultNameTemplate = "{app}-pr-{pr}"
+
+// maxNameLength is the length names of apps can't exceed.
+const maxNameLength = 63
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing review apps: ephemeral apps deploying the pull requests
+of an app, with the app's config and optionally a fork of its Postgres cluster,
+named after a template stored for the app, so CI can create them on each push
+and destroy them once their pull request is closed.
+
+Review apps need the API to keep track of them, which it doesn't everywhere yet.`
+		short = "Manage review apps of pull requests"
+	)
+
+	cmd := command.New("review", short, long, nil)
+
+	cmd.AddCommand(
+		newCreate(),
+		newList(),
+		newDestroy(),
+		newGC(),
+	)
+
+	return cmd
+}
+
+// requireReview makes sure the API keeps track of review apps.
+var requireReview = command.RequireAPI("App", "reviewApps")
+
+// reviewName renders template into the name of the review app of pull
+// request pr of app, opened from branch. Names too long for apps are
+// shortened by cutting the branch first.
+func reviewName(template, app string, pr int, branch string) (string, error) {
+	if !strings.Contains(template, "{pr}") {
+		return "", fmt.Errorf("name template %q must contain {pr}, so that pull requests get apps of their own", template)
+	}
+
+	render := func(branch string) string {
+		return sanitize(strings.NewReplacer(
+			"{app}", app,
+			"{pr}", strconv.Itoa(pr),
+			"{branch}", branch,
+		).Replace(template))
+	}
+
+	if strings.ContainsAny(strings.NewReplacer("{app}", "", "{pr}", "", "{branch}", "").Replace(template), "{}") {
+		return "", fmt.Errorf("name template %q may only contain {app}, {pr} and {branch}", template)
+	}
+
+	branch = sanitize(branch)
+
+	name := render(branch)
+	if excess := len(name) - maxNameLength; excess > 0 && excess < len(branch) {
+		name = render(strings.TrimRight(branch[:len(branch)-excess], "-"))
+	}
+
+	if len(name) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength], "-")
+	}
+
+	return name, nil
+}
+
+// sanitize lowercases s and replaces the runs of characters names of apps
+// can't contain with dashes.
+func sanitize(s string) string {
+	var b strings.Builder
+
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+
+			continue
+		}
+
+		dash = true
+	}
+
+	return b.String()
+}
+
+// find returns the review app of pull request pr, or nil when it has none.
+func find(apps *api.ReviewApps, pr int) *api.ReviewApp {
+	for i := range apps.Nodes {
+		if apps.Nodes[i].PullRequest == pr {
+			return &apps.Nodes[i]
+		}
+	}
+
+	return nil
+}
+
+// destroy destroys the review app r of app, along with its fork of Postgres,
+// and deletes its record. Apps already destroyed are skipped.
+func destroy(ctx context.Context, app string, r *api.ReviewApp) error {
+	apiClient := client.FromContext(ctx).API()
+
+	for _, name := range []string{r.Name, r.PostgresApp} {
+		if name == "" {
+			continue
+		}
+
+		if err := apiClient.DeleteApp(ctx, name); err != nil && !api.IsNotFoundError(err) {
+			return fmt.Errorf("failed destroying %s: %w", name, err)
+		}
+	}
+
+	if err := apiClient.DeleteReviewApp(ctx, app, r.PullRequest); err != nil {
+		return fmt.Errorf("failed deleting the record of %s: %w", r.Name, err)
+	}
+
+	return nil
+}
+
+var errNoPullRequest = errors.New("--pr must be given the number of the pull request")
@@ -0,0 +1,36 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewName(t *testing.T) {
+	cases := []struct {
+		template, branch, want string
+	}{
+		{defaultNameTemplate, "", "web-pr-42"},
+		{"{app}-{branch}-{pr}", "feature/Add_Login", "web-feature-add-login-42"},
+		{"review-{pr}", "", "review-42"},
+		{"{app}--{branch}-{pr}", "--", "web-42"},
+	}
+	for _, c := range cases {
+		got, err := reviewName(c.template, "web", 42, c.branch)
+		require.NoError(t, err, c.template)
+		assert.Equal(t, c.want, got, c.template)
+	}
+
+	long, err := reviewName("{app}-{branch}-{pr}", "web", 42, strings.Repeat("x", 80))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(long), maxNameLength)
+	assert.True(t, strings.HasSuffix(long, "-42"), long)
+
+	_, err = reviewName("{app}-{branch}", "web", 42, "main")
+	assert.Error(t, err)
+
+	_, err = reviewName("{app}-{sha}-{pr}", "web", 42, "main")
+	assert.Error(t, err)
+}
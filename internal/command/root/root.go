@@ -8,42 +8,63 @@ import (
 	"github.com/superfly/flyctl/cmd"
 	"github.com/superfly/flyctl/flyctl"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/activity"
 	"github.com/superfly/flyctl/internal/command/agent"
+	"github.com/superfly/flyctl/internal/command/alerts"
 	"github.com/superfly/flyctl/internal/command/apps"
 	"github.com/superfly/flyctl/internal/command/auth"
+	"github.com/superfly/flyctl/internal/command/autoscale"
+	"github.com/superfly/flyctl/internal/command/billing"
 	"github.com/superfly/flyctl/internal/command/checks"
+	"github.com/superfly/flyctl/internal/command/consul"
+	"github.com/superfly/flyctl/internal/command/contexts"
 	"github.com/superfly/flyctl/internal/command/create"
+	"github.com/superfly/flyctl/internal/command/cron"
 	"github.com/superfly/flyctl/internal/command/curl"
+	"github.com/superfly/flyctl/internal/command/dashboard"
 	"github.com/superfly/flyctl/internal/command/deploy"
 	"github.com/superfly/flyctl/internal/command/destroy"
 	"github.com/superfly/flyctl/internal/command/dig"
 	"github.com/superfly/flyctl/internal/command/docs"
 	"github.com/superfly/flyctl/internal/command/doctor"
+	"github.com/superfly/flyctl/internal/command/extensions"
+	"github.com/superfly/flyctl/internal/command/externaldb"
 	"github.com/superfly/flyctl/internal/command/help"
 	"github.com/superfly/flyctl/internal/command/history"
 	"github.com/superfly/flyctl/internal/command/image"
 	"github.com/superfly/flyctl/internal/command/ips"
+	"github.com/superfly/flyctl/internal/command/litefs"
 	"github.com/superfly/flyctl/internal/command/logs"
 	"github.com/superfly/flyctl/internal/command/machine"
+	"github.com/superfly/flyctl/internal/command/metrics"
 	"github.com/superfly/flyctl/internal/command/monitor"
 	"github.com/superfly/flyctl/internal/command/move"
 	"github.com/superfly/flyctl/internal/command/open"
 	"github.com/superfly/flyctl/internal/command/orgs"
 	"github.com/superfly/flyctl/internal/command/ping"
 	"github.com/superfly/flyctl/internal/command/platform"
+	"github.com/superfly/flyctl/internal/command/plugin"
 	"github.com/superfly/flyctl/internal/command/postgres"
 	"github.com/superfly/flyctl/internal/command/proxy"
 	"github.com/superfly/flyctl/internal/command/redis"
 	"github.com/superfly/flyctl/internal/command/releases"
 	"github.com/superfly/flyctl/internal/command/restart"
 	"github.com/superfly/flyctl/internal/command/resume"
+	"github.com/superfly/flyctl/internal/command/review"
+	"github.com/superfly/flyctl/internal/command/routing"
+	"github.com/superfly/flyctl/internal/command/scale"
 	"github.com/superfly/flyctl/internal/command/secrets"
 	"github.com/superfly/flyctl/internal/command/ssh"
 	"github.com/superfly/flyctl/internal/command/status"
+	"github.com/superfly/flyctl/internal/command/storage"
 	"github.com/superfly/flyctl/internal/command/suspend"
+	"github.com/superfly/flyctl/internal/command/tokens"
+	"github.com/superfly/flyctl/internal/command/top"
+	"github.com/superfly/flyctl/internal/command/trace"
 	"github.com/superfly/flyctl/internal/command/version"
 	"github.com/superfly/flyctl/internal/command/vm"
 	"github.com/superfly/flyctl/internal/command/volumes"
+	"github.com/superfly/flyctl/internal/completion"
 )
 
 // New initializes and returns a reference to a new root command.
@@ -141,11 +162,14 @@ func New() *cobra.Command {
 		doctor.New(),
 		dig.New(),
 		volumes.New(),
+		activity.New(),
 		agent.New(),
+		alerts.New(),
 		image.New(),
 		ping.New(),
 		proxy.New(),
 		machine.New(),
+		metrics.New(),
 		monitor.New(),
 		postgres.New(),
 		ips.New(),
@@ -155,6 +179,20 @@ func New() *cobra.Command {
 		redis.New(),
 		vm.New(),
 		checks.New(),
+		routing.New(),
+		top.New(),
+		tokens.New(),
+		trace.New(),
+		billing.New(),
+		storage.New(),
+		consul.New(),
+		extensions.New(),
+		externaldb.New(),
+		litefs.New(),
+		cron.New(),
+		review.New(),
+		plugin.New(),
+		dashboard.New(),
 	}
 
 	// if os.Getenv("DEV") != "" {
@@ -190,10 +228,25 @@ func New() *cobra.Command {
 	// and finally, add the new commands
 	root.AddCommand(newCommands...)
 
+	// the config, autoscale and scale commands are still old ones, so their
+	// new subcommands are attached to them
+	for _, cmd := range root.Commands() {
+		switch cmd.Name() {
+		case "config":
+			cmd.AddCommand(contexts.New()...)
+		case "autoscale":
+			cmd.AddCommand(autoscale.NewPolicy())
+		case "scale":
+			cmd.AddCommand(scale.NewZero(), scale.NewSuggest())
+		}
+	}
+
 	root.SetHelpCommand(help.New(root))
 
 	root.RunE = help.NewRootHelp().RunE
 
+	completion.Register(root)
+
 	return root
 }
 
@@ -0,0 +1,62 @@
+package routing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newReset() *cobra.Command {
+	const (
+		long = `Removes a routing policy, so that the traffic it applied to is routed to
+the nearest region again. The policy is picked with --ip and --hostname, like
+with the set command.`
+		short = "Remove a routing policy"
+	)
+
+	cmd := command.New("reset", short, long, runReset,
+		requireRouting,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		selectorFlags,
+	)
+
+	return cmd
+}
+
+func runReset(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		client   = client.FromContext(ctx).API()
+		appName  = app.NameFromContext(ctx)
+		address  = flag.GetString(ctx, "ip")
+		hostname = flag.GetString(ctx, "hostname")
+	)
+
+	policies, err := client.GetRoutingPolicies(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	policy := findPolicy(policies, address, hostname)
+	if policy == nil {
+		return fmt.Errorf("%s has no routing policy for %s", appName, describe(appName, address, hostname))
+	}
+
+	if err := client.DeleteRoutingPolicy(ctx, policy.ID); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Traffic of %s is routed to the nearest region\n", describe(appName, address, hostname))
+
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package routing implements the routing command chain.
+package routing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for configuring how the Fly proxy routes the traffic of an
+application. By default, traffic to any of the application's addresses goes to
+the nearest region with capacity. A routing policy restricts the traffic of an
+address, or of a hostname, to a set of regions, with an ordered list of regions
+to fall back to. Routing policies need the API to serve them, which it doesn't
+everywhere yet.`
+		short = "Configure region-aware routing"
+	)
+
+	cmd := command.New("routing", short, long, nil)
+
+	cmd.AddCommand(
+		newShow(),
+		newSet(),
+		newReset(),
+	)
+
+	return cmd
+}
+
+// requireRouting makes sure the API serves routing policies.
+var requireRouting = command.RequireAPI("App", "routingPolicies")
+
+// selectorFlags pick the traffic a policy applies to.
+var selectorFlags = flag.Set{
+	flag.App(),
+	flag.AppConfig(),
+	flag.String{
+		Name:        "ip",
+		Description: "Address of the app whose traffic the policy applies to",
+	},
+	flag.String{
+		Name:        "hostname",
+		Description: "Hostname whose traffic the policy applies to",
+	},
+}
+
+// findPolicy returns the policy for the address and hostname, which may be
+// empty, or nil when there's none.
+func findPolicy(policies []api.RoutingPolicy, address, hostname string) *api.RoutingPolicy {
+	for i, policy := range policies {
+		var policyAddress string
+		if policy.IPAddress != nil {
+			policyAddress = policy.IPAddress.Address
+		}
+
+		if policyAddress == address && strings.EqualFold(policy.Hostname, hostname) {
+			return &policies[i]
+		}
+	}
+
+	return nil
+}
+
+// findIPAddress returns the app's address, which must be allocated to it.
+func findIPAddress(ctx context.Context, appName, address string) (*api.IPAddress, error) {
+	ipAddress, err := client.FromContext(ctx).API().FindIPAddress(ctx, appName, address)
+	if err != nil {
+		return nil, err
+	}
+
+	if ipAddress == nil {
+		return nil, fmt.Errorf("%s isn't allocated to %s", address, appName)
+	}
+
+	return ipAddress, nil
+}
@@ -0,0 +1,123 @@
+package routing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newSet() *cobra.Command {
+	const (
+		long = `Sets the routing policy of the application's traffic. Traffic is routed to
+the nearest of --regions, and to --fallback-regions in the given order when
+none of them can take it. The policy applies to the traffic of an address with
+--ip, of a hostname with --hostname, or of the whole application with neither.`
+		short = "Set a routing policy"
+	)
+
+	cmd := command.New("set", short, long, runSet,
+		requireRouting,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		selectorFlags,
+		flag.StringSlice{
+			Name:        "regions",
+			Description: "Regions to route traffic to, nearest first",
+		},
+		flag.StringSlice{
+			Name:        "fallback-regions",
+			Description: "Regions to route traffic to, in order, when none of --regions can take it",
+		},
+	)
+
+	return cmd
+}
+
+func runSet(ctx context.Context) error {
+	var (
+		io       = iostreams.FromContext(ctx)
+		client   = client.FromContext(ctx).API()
+		appName  = app.NameFromContext(ctx)
+		regions  = flag.GetStringSlice(ctx, "regions")
+		fallback = flag.GetStringSlice(ctx, "fallback-regions")
+	)
+
+	if len(regions) == 0 {
+		return errors.New("at least one region must be specified with --regions")
+	}
+
+	if overlap := lo.Intersect(regions, fallback); len(overlap) > 0 {
+		return fmt.Errorf("regions %s can't also be fallback regions", strings.Join(overlap, ", "))
+	}
+
+	platformRegions, _, err := client.PlatformRegions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, region := range append(regions, fallback...) {
+		if !lo.ContainsBy(platformRegions, func(r api.Region) bool { return r.Code == region }) {
+			return fmt.Errorf("unknown region %s", region)
+		}
+	}
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	input := api.SetRoutingPolicyInput{
+		AppID:           app.ID,
+		Hostname:        flag.GetString(ctx, "hostname"),
+		Regions:         regions,
+		FallbackRegions: fallback,
+	}
+
+	if address := flag.GetString(ctx, "ip"); address != "" {
+		ipAddress, err := findIPAddress(ctx, appName, address)
+		if err != nil {
+			return err
+		}
+
+		input.IPAddressID = ipAddress.ID
+	}
+
+	if _, err := client.SetRoutingPolicy(ctx, input); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Traffic of %s is routed to %s", describe(appName, flag.GetString(ctx, "ip"), input.Hostname), strings.Join(regions, ", "))
+	if len(fallback) > 0 {
+		fmt.Fprintf(io.Out, ", falling back to %s", strings.Join(fallback, ", "))
+	}
+	fmt.Fprintln(io.Out)
+
+	return nil
+}
+
+// describe names the traffic a policy applies to.
+func describe(appName, address, hostname string) string {
+	switch {
+	case address != "" && hostname != "":
+		return fmt.Sprintf("%s on %s", hostname, address)
+	case address != "":
+		return address
+	case hostname != "":
+		return hostname
+	default:
+		return appName
+	}
+}
@@ -0,0 +1,95 @@
+package routing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newShow() *cobra.Command {
+	const (
+		long  = `Shows the routing policy of each address and hostname of the application`
+		short = "Show routing policies"
+	)
+
+	cmd := command.New("show", short, long, runShow,
+		requireRouting,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	return cmd
+}
+
+func runShow(ctx context.Context) error {
+	var (
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+	)
+
+	policies, err := client.GetRoutingPolicies(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, policies)
+	}
+
+	ipAddresses, err := client.GetIPAddresses(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	// every address is listed, with the app-wide policy or the default of
+	// routing to the nearest region when it has no policy of its own
+	defaultPolicy := findPolicy(policies, "", "")
+
+	var rows [][]string
+	for _, ipAddress := range ipAddresses {
+		if strings.HasPrefix(ipAddress.Type, "private") {
+			continue
+		}
+
+		policy := findPolicy(policies, ipAddress.Address, "")
+		if policy == nil {
+			policy = defaultPolicy
+		}
+
+		regions, fallback := "nearest", "-"
+		if policy != nil {
+			regions, fallback = strings.Join(policy.Regions, ", "), strings.Join(policy.FallbackRegions, ", ")
+		}
+
+		rows = append(rows, []string{ipAddress.Address, "*", regions, fallback})
+	}
+
+	for _, policy := range policies {
+		if policy.Hostname == "" {
+			continue
+		}
+
+		address := "*"
+		if policy.IPAddress != nil {
+			address = policy.IPAddress.Address
+		}
+
+		rows = append(rows, []string{address, policy.Hostname, strings.Join(policy.Regions, ", "), strings.Join(policy.FallbackRegions, ", ")})
+	}
+
+	return render.Table(io.Out, "", rows, "IP", "Hostname", "Regions", "Fallback Regions")
+}
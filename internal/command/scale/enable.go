@@ -0,0 +1,140 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newZeroEnable() *cobra.Command {
+	const (
+		long = `Let the proxy stop the machines of an app's services when they're idle and
+start them again on requests. --min-running keeps a minimum of machines of
+each region running; giving it along with --region sets a minimum for those
+regions only.`
+		short = "Enable scaling to zero"
+	)
+
+	cmd := command.New("enable", short, long, runZeroEnable,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		selectionFlags,
+		flag.Int{
+			Name:        "min-running",
+			Description: "Count of machines to keep running in each region",
+		},
+	)
+
+	return cmd
+}
+
+func runZeroEnable(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	var minRunning *int
+	if flag.FromContext(ctx).Changed("min-running") {
+		n := flag.GetInt(ctx, "min-running")
+		if n < 0 {
+			return errors.New("--min-running can't be negative")
+		}
+
+		minRunning = api.IntPointer(n)
+	}
+
+	app, flapsClient, machines, err := selectMachines(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated, err := updateServices(ctx, flapsClient, app.Name, machines, func(service *api.MachineService) {
+		service.Autostop = api.BoolPointer(true)
+		service.Autostart = api.BoolPointer(true)
+		if minRunning != nil {
+			service.MinMachinesRunning = minRunning
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Scaling to zero is enabled on %d machines. To keep it across deploys, set in the services of fly.toml:\n\n", updated)
+	fmt.Fprintln(out, "  auto_stop_machines = true")
+	fmt.Fprintln(out, "  auto_start_machines = true")
+	if minRunning != nil {
+		fmt.Fprintf(out, "  min_machines_running = %d\n", *minRunning)
+	}
+
+	return nil
+}
+
+func newZeroDisable() *cobra.Command {
+	const (
+		long = `Stop the proxy from stopping the machines of an app's services when they're
+idle, and start those which are stopped, so they all keep running.`
+		short = "Disable scaling to zero"
+	)
+
+	cmd := command.New("disable", short, long, runZeroDisable,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, selectionFlags)
+
+	return cmd
+}
+
+func runZeroDisable(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	app, flapsClient, machines, err := selectMachines(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated, err := updateServices(ctx, flapsClient, app.Name, machines, func(service *api.MachineService) {
+		service.Autostop = api.BoolPointer(false)
+		service.Autostart = api.BoolPointer(false)
+		service.MinMachinesRunning = nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if machine.State != "stopped" || len(machine.Config.Services) == 0 {
+			continue
+		}
+
+		// updating a machine may have started it already
+		if m, err := flapsClient.Get(ctx, machine.ID); err == nil && m.State != "stopped" {
+			continue
+		}
+
+		if _, err := flapsClient.Start(ctx, machine.ID); err != nil {
+			return fmt.Errorf("failed starting machine %s: %w", machine.ID, err)
+		}
+
+		fmt.Fprintf(out, "Started machine %s of %s in %s\n", machine.ID, processGroup(machine), machine.Region)
+	}
+
+	fmt.Fprintf(out, "Scaling to zero is disabled on %d machines. To keep it across deploys, set in the services of fly.toml:\n\n", updated)
+	fmt.Fprintln(out, "  auto_stop_machines = false")
+	fmt.Fprintln(out, "  auto_start_machines = false")
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package scale
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/superfly/flyctl/api"
+)
+
+const (
+	// targetCPUUtilization is the share of their CPUs machines are sized to
+	// keep busy at peak.
+	targetCPUUtilization = 0.7
+	// memoryHeadroom is the factor peak memory usage is multiplied by to size
+	// the memory of machines.
+	memoryHeadroom = 1.3
+	// targetConcurrency is the share of the soft limit of their services
+	// machines are counted to reach at peak.
+	targetConcurrency = 0.8
+	// extraMemoryPriceMonthGB is the monthly price of each GB of memory beyond
+	// the one VM sizes come with.
+	extraMemoryPriceMonthGB = 5.0
+)
+
+// maxMemoryMBPerCPU is the most memory machines get per CPU, by CPU kind.
+var maxMemoryMBPerCPU = map[string]int{
+	"shared":      2048,
+	"dedicated":   8192,
+	"performance": 8192,
+}
+
+// usage is the peak usage of the machines of a process group: the 95th
+// percentile over the analyzed period of each machine, and the highest
+// among them for CPU and memory.
+type usage struct {
+	// CPUs is the count of CPUs a machine kept busy.
+	CPUs float64
+	// MemoryMB is the memory a machine used.
+	MemoryMB float64
+	// Concurrency is the count of connections or requests the machines
+	// handled together, or nil when unknown.
+	Concurrency *float64
+}
+
+// groupState is the current state of the machines of a process group.
+type groupState struct {
+	Group    string
+	Guest    api.MachineGuest
+	Machines int
+	Regions  int
+	// SoftLimit is the concurrency soft limit of the group's services, or 0
+	// when it has none.
+	SoftLimit int
+}
+
+// groupPlan is the suggested size and count of the machines of a process
+// group, along with the estimated change of their monthly cost.
+type groupPlan struct {
+	Group       string
+	Size        string
+	MemoryMB    int
+	Machines    int
+	NewSize     string
+	NewMemoryMB int
+	NewMachines int
+	CostDelta   float64
+	Reasons     []string
+}
+
+// changed reports whether p suggests any change.
+func (p *groupPlan) changed() bool {
+	return p.resized() || p.NewMachines != p.Machines
+}
+
+// resized reports whether p suggests changing the size of machines.
+func (p *groupPlan) resized() bool {
+	return p.NewSize != p.Size || p.NewMemoryMB != p.MemoryMB
+}
+
+// guest returns the guest of the machines p suggests.
+func (p *groupPlan) guest(current api.MachineGuest) api.MachineGuest {
+	guest := current
+	if preset, ok := api.MachinePresets[p.NewSize]; ok {
+		guest.CPUKind = preset.CPUKind
+		guest.CPUs = preset.CPUs
+	}
+	guest.MemoryMB = p.NewMemoryMB
+
+	return guest
+}
+
+// presetName returns the name of the preset with the CPUs of guest, or an
+// empty string when none matches.
+func presetName(guest api.MachineGuest) string {
+	for name, preset := range api.MachinePresets {
+		if preset.CPUKind == guest.CPUKind && preset.CPUs == guest.CPUs {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// presetsOfKind returns the presets of a CPU kind, by ascending CPUs.
+func presetsOfKind(kind string) []string {
+	var names []string
+	for name, preset := range api.MachinePresets {
+		if preset.CPUKind == kind {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return api.MachinePresets[names[i]].CPUs < api.MachinePresets[names[j]].CPUs
+	})
+
+	return names
+}
+
+// planGroup suggests the size and count of the machines of state from their
+// peak usage u. Sizes keep their CPU kind; prices are the monthly prices of
+// VM sizes by name.
+func planGroup(state groupState, u usage, prices map[string]float64) groupPlan {
+	plan := groupPlan{
+		Group:       state.Group,
+		Size:        presetName(state.Guest),
+		MemoryMB:    state.Guest.MemoryMB,
+		Machines:    state.Machines,
+		NewMachines: state.Machines,
+	}
+	plan.NewSize, plan.NewMemoryMB = plan.Size, plan.MemoryMB
+
+	if plan.Size == "" {
+		plan.Reasons = append(plan.Reasons, "machines have no standard size")
+
+		return plan
+	}
+
+	var (
+		memoryMB = roundUpMemory(u.MemoryMB * memoryHeadroom)
+		presets  = presetsOfKind(state.Guest.CPUKind)
+	)
+
+	// the largest size is suggested when none fits, along with as much memory
+	// as it takes
+	plan.NewSize = presets[len(presets)-1]
+	for _, name := range presets {
+		preset := api.MachinePresets[name]
+		if u.CPUs <= float64(preset.CPUs)*targetCPUUtilization && memoryMB <= preset.CPUs*maxMemoryMBPerCPU[preset.CPUKind] {
+			plan.NewSize = name
+
+			break
+		}
+	}
+
+	preset := api.MachinePresets[plan.NewSize]
+	if max := preset.CPUs * maxMemoryMBPerCPU[preset.CPUKind]; memoryMB > max {
+		memoryMB = max
+	}
+	if memoryMB < preset.MemoryMB {
+		memoryMB = preset.MemoryMB
+	}
+	plan.NewMemoryMB = memoryMB
+
+	if preset.CPUs != state.Guest.CPUs {
+		plan.Reasons = append(plan.Reasons, fmt.Sprintf("CPU peaks at %.0f%% of %d CPUs", 100*u.CPUs/float64(state.Guest.CPUs), state.Guest.CPUs))
+	}
+	if plan.NewMemoryMB != plan.MemoryMB {
+		plan.Reasons = append(plan.Reasons, fmt.Sprintf("memory peaks at %.0f MB of %d MB", u.MemoryMB, plan.MemoryMB))
+	}
+
+	if u.Concurrency != nil && state.SoftLimit > 0 {
+		needed := int(math.Ceil(*u.Concurrency / (float64(state.SoftLimit) * targetConcurrency)))
+		if needed < state.Regions {
+			needed = state.Regions
+		}
+		if needed < 1 {
+			needed = 1
+		}
+
+		if needed != plan.Machines {
+			plan.NewMachines = needed
+			plan.Reasons = append(plan.Reasons, fmt.Sprintf("concurrency peaks at %.0f against a soft limit of %d per machine", *u.Concurrency, state.SoftLimit))
+		}
+	}
+
+	plan.CostDelta = float64(plan.NewMachines)*monthlyPrice(plan.NewSize, plan.NewMemoryMB, prices) -
+		float64(plan.Machines)*monthlyPrice(plan.Size, plan.MemoryMB, prices)
+
+	return plan
+}
+
+// roundUpMemory rounds mb up to a multiple of 256 MB.
+func roundUpMemory(mb float64) int {
+	return int(math.Ceil(mb/256)) * 256
+}
+
+// monthlyPrice returns the monthly price of a machine of size with memoryMB
+// of memory, charging memory beyond the size's own at extraMemoryPriceMonthGB.
+func monthlyPrice(size string, memoryMB int, prices map[string]float64) float64 {
+	price := prices[size]
+
+	if preset, ok := api.MachinePresets[size]; ok && memoryMB > preset.MemoryMB {
+		price += float64(memoryMB-preset.MemoryMB) / 1024 * extraMemoryPriceMonthGB
+	}
+
+	return price
+}
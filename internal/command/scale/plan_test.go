@@ -0,0 +1,55 @@
+package scale
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestPlanGroup(t *testing.T) {
+	prices := map[string]float64{
+		"shared-cpu-1x": 2,
+		"shared-cpu-2x": 4,
+		"shared-cpu-4x": 8,
+	}
+
+	state := groupState{
+		Group:     "app",
+		Guest:     api.MachineGuest{CPUKind: "shared", CPUs: 4, MemoryMB: 2048},
+		Machines:  2,
+		Regions:   1,
+		SoftLimit: 20,
+	}
+
+	concurrency := 40.0
+	plan := planGroup(state, usage{CPUs: 0.5, MemoryMB: 300, Concurrency: &concurrency}, prices)
+
+	assert.Equal(t, "shared-cpu-4x", plan.Size)
+	assert.Equal(t, "shared-cpu-1x", plan.NewSize)
+	assert.Equal(t, 512, plan.NewMemoryMB)
+	assert.Equal(t, 3, plan.NewMachines)
+	assert.InDelta(t, 3*(2+0.25*extraMemoryPriceMonthGB)-2*(8+1*extraMemoryPriceMonthGB), plan.CostDelta, 0.001)
+	assert.Len(t, plan.Reasons, 3)
+
+	plan = planGroup(state, usage{CPUs: 10, MemoryMB: 9000}, prices)
+
+	assert.Equal(t, "shared-cpu-8x", plan.NewSize)
+	assert.Equal(t, 11776, plan.NewMemoryMB)
+	assert.Equal(t, 2, plan.NewMachines)
+}
+
+func TestPlanGroupUnchanged(t *testing.T) {
+	state := groupState{
+		Group:    "worker",
+		Guest:    api.MachineGuest{CPUKind: "dedicated", CPUs: 1, MemoryMB: 2048},
+		Machines: 1,
+		Regions:  1,
+	}
+
+	plan := planGroup(state, usage{CPUs: 0.6, MemoryMB: 1200}, nil)
+
+	assert.False(t, plan.changed())
+	assert.Empty(t, plan.Reasons)
+}
@@ -0,0 +1,165 @@
+package scale
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newZeroShow() *cobra.Command {
+	const (
+		long = `Show the scaling to zero settings of the machines of an app by process group
+and region, along with how many are running, how often stopped ones were woken
+up per hour and how long their cold starts took, from their recent events.`
+		short = "Show scaling to zero settings and statistics"
+	)
+
+	cmd := command.New("show", short, long, runZeroShow,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Aliases = []string{"status"}
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd, selectionFlags)
+
+	return cmd
+}
+
+// zeroStatus is the scaling to zero status of the machines of a process
+// group in a region.
+type zeroStatus struct {
+	Group              string
+	Region             string
+	Machines           int
+	Started            int
+	Autostop           string
+	Autostart          string
+	MinMachinesRunning string
+	WakesPerHour       float64
+	ColdStartMs        *int64
+}
+
+func runZeroShow(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	_, _, machines, err := selectMachines(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		now      = time.Now()
+		statuses []zeroStatus
+	)
+
+	// machines are sorted by group and region, so each run of them is one
+	for start := 0; start < len(machines); {
+		end := start + 1
+		for end < len(machines) && processGroup(machines[end]) == processGroup(machines[start]) && machines[end].Region == machines[start].Region {
+			end++
+		}
+
+		statuses = append(statuses, newZeroStatus(machines[start:end], now))
+		start = end
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, statuses)
+	}
+
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		coldStart := ""
+		if status.ColdStartMs != nil {
+			coldStart = fmt.Sprintf("%dms", *status.ColdStartMs)
+		}
+
+		rows = append(rows, []string{
+			status.Group,
+			status.Region,
+			fmt.Sprintf("%d/%d", status.Started, status.Machines),
+			status.Autostop,
+			status.Autostart,
+			status.MinMachinesRunning,
+			strconv.FormatFloat(status.WakesPerHour, 'f', 1, 64),
+			coldStart,
+		})
+	}
+
+	return render.Table(out, "", rows, "Group", "Region", "Started", "Auto Stop", "Auto Start", "Min Running", "Wakes/Hour", "Cold Start")
+}
+
+func newZeroStatus(machines []*api.Machine, now time.Time) zeroStatus {
+	status := zeroStatus{
+		Group:    processGroup(machines[0]),
+		Region:   machines[0].Region,
+		Machines: len(machines),
+	}
+
+	var autostop, autostart, minRunning []string
+	for _, machine := range machines {
+		if machine.State == "started" {
+			status.Started++
+		}
+
+		for _, service := range machine.Config.Services {
+			autostop = append(autostop, formatBool(service.Autostop))
+			autostart = append(autostart, formatBool(service.Autostart))
+
+			min := "0"
+			if service.MinMachinesRunning != nil {
+				min = strconv.Itoa(*service.MinMachinesRunning)
+			}
+			minRunning = append(minRunning, min)
+		}
+	}
+
+	status.Autostop = summarize(autostop)
+	status.Autostart = summarize(autostart)
+	status.MinMachinesRunning = summarize(minRunning)
+
+	stats := computeWakeStats(machines, now)
+	status.WakesPerHour = stats.perHour()
+	if coldStart, ok := stats.medianColdStart(); ok {
+		ms := coldStart.Milliseconds()
+		status.ColdStartMs = &ms
+	}
+
+	return status
+}
+
+func formatBool(b *bool) string {
+	if b != nil && *b {
+		return "on"
+	}
+
+	return "off"
+}
+
+// summarize returns the value all of values share, mixed when they differ,
+// or none when there are no values, as machines have no services.
+func summarize(values []string) string {
+	if len(values) == 0 {
+		return "no services"
+	}
+
+	for _, value := range values[1:] {
+		if value != values[0] {
+			return "mixed"
+		}
+	}
+
+	return values[0]
+}
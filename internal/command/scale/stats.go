@@ -0,0 +1,87 @@
+package scale
+
+import (
+	"sort"
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// wakeStats are the start statistics of a set of machines, derived from the
+// events of their machines: a wake is a machine reaching started after it
+// stopped or exited, and its cold start lasts from the first start event
+// following the stop to that point.
+type wakeStats struct {
+	Wakes      int
+	Window     time.Duration
+	ColdStarts []time.Duration
+}
+
+// perHour returns the count of wakes per hour over the window of s.
+func (s wakeStats) perHour() float64 {
+	if s.Window <= 0 {
+		return 0
+	}
+
+	return float64(s.Wakes) / s.Window.Hours()
+}
+
+// medianColdStart returns the median cold start of s, or false when no cold
+// start was seen.
+func (s wakeStats) medianColdStart() (time.Duration, bool) {
+	if len(s.ColdStarts) == 0 {
+		return 0, false
+	}
+
+	sorted := append([]time.Duration(nil), s.ColdStarts...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+
+	return sorted[len(sorted)/2], true
+}
+
+// computeWakeStats returns the wake statistics of machines over the window
+// their events span, up to now.
+func computeWakeStats(machines []*api.Machine, now time.Time) wakeStats {
+	var (
+		stats  wakeStats
+		oldest = now
+	)
+
+	for _, machine := range machines {
+		events := append([]*api.MachineEvent(nil), machine.Events...)
+		sort.Slice(events, func(i, j int) bool {
+			return events[i].Timestamp < events[j].Timestamp
+		})
+
+		var (
+			stopped bool
+			start   int64
+		)
+
+		for _, event := range events {
+			if at := time.UnixMilli(event.Timestamp); at.Before(oldest) {
+				oldest = at
+			}
+
+			switch {
+			case event.Type == "stop" || event.Type == "exit":
+				stopped, start = true, 0
+			case event.Type == "start" && stopped && event.Status == "started":
+				stats.Wakes++
+				if start > 0 {
+					stats.ColdStarts = append(stats.ColdStarts, time.Duration(event.Timestamp-start)*time.Millisecond)
+				}
+
+				stopped, start = false, 0
+			case event.Type == "start" && stopped && start == 0:
+				start = event.Timestamp
+			}
+		}
+	}
+
+	stats.Window = now.Sub(oldest)
+
+	return stats
+}
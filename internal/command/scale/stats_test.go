@@ -0,0 +1,41 @@
+package scale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/superfly/flyctl/api"
+)
+
+func TestComputeWakeStats(t *testing.T) {
+	now := time.UnixMilli(10_000_000)
+	at := func(ago time.Duration) int64 {
+		return now.Add(-ago).UnixMilli()
+	}
+
+	machines := []*api.Machine{
+		{Events: []*api.MachineEvent{
+			{Type: "start", Status: "started", Timestamp: at(2 * time.Hour)},
+			{Type: "stop", Status: "stopped", Timestamp: at(90 * time.Minute)},
+			{Type: "start", Status: "requested", Timestamp: at(time.Hour)},
+			{Type: "start", Status: "started", Timestamp: at(time.Hour - 800*time.Millisecond)},
+			{Type: "exit", Status: "stopped", Timestamp: at(30 * time.Minute)},
+		}},
+		{Events: []*api.MachineEvent{
+			{Type: "stop", Status: "stopped", Timestamp: at(time.Hour)},
+			{Type: "start", Status: "started", Timestamp: at(10 * time.Minute)},
+		}},
+	}
+
+	stats := computeWakeStats(machines, now)
+
+	assert.Equal(t, 2, stats.Wakes)
+	assert.Equal(t, 2*time.Hour, stats.Window)
+	assert.Equal(t, 1.0, stats.perHour())
+
+	coldStart, ok := stats.medianColdStart()
+	assert.True(t, ok)
+	assert.Equal(t, 800*time.Millisecond, coldStart)
+}
@@ -0,0 +1,406 @@
+package scale
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// NewSuggest returns the suggest command, which the scale command of the
+// legacy command tree carries.
+func NewSuggest() *cobra.Command {
+	const (
+		long = `Suggest VM size and count changes for each process group of an app, from the
+95th percentile of the CPU, memory and concurrency of its machines over the
+last days, along with the estimated change of their monthly cost.
+
+Sizes are picked to keep CPUs 70% busy and 30% of memory free at peak, and
+counts to keep concurrency at 80% of the soft limit of services. --apply
+resizes the machines and adds or destroys machines to follow the plan;
+machines with volumes aren't added, use fly scale count for those.`
+		short = "Suggest VM size and count changes from metrics"
+	)
+
+	cmd := command.New("suggest", short, long, runSuggest,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		selectionFlags,
+		flag.Yes(),
+		flag.Int{
+			Name:        "days",
+			Description: "Count of days of metrics to analyze",
+			Default:     7,
+		},
+		flag.Bool{
+			Name:        "apply",
+			Description: "Apply the suggested changes",
+		},
+	)
+
+	return cmd
+}
+
+// usageQueries are the PromQL queries of the 95th percentile of the usage of
+// each machine of an app, formatted with the app name and the count of days.
+var usageQueries = struct {
+	cpu, memory, concurrency string
+}{
+	// fly_instance_cpu counts centiseconds so its rate is a percentage
+	cpu:         `quantile_over_time(0.95, sum by (instance) (rate(fly_instance_cpu{app=%[1]q,mode!="idle"}[5m]))[%[2]dd:5m])`,
+	memory:      `quantile_over_time(0.95, sum by (instance) (fly_instance_memory_mem_total{app=%[1]q} - fly_instance_memory_mem_available{app=%[1]q})[%[2]dd:5m])`,
+	concurrency: `quantile_over_time(0.95, sum by (instance) (fly_app_concurrency{app=%[1]q})[%[2]dd:5m])`,
+}
+
+func runSuggest(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+		days   = flag.GetInt(ctx, "days")
+	)
+
+	if days < 1 {
+		return errors.New("--days must be at least 1")
+	}
+
+	appCompact, flapsClient, machines, err := selectMachines(ctx)
+	if err != nil {
+		return err
+	}
+
+	perInstance := func(query string) (map[string]float64, error) {
+		series, err := client.QueryMetrics(ctx, appCompact.Organization.Slug, fmt.Sprintf(query, appCompact.Name, days), time.Now())
+		if err != nil {
+			return nil, fmt.Errorf("failed querying metrics: %w", err)
+		}
+
+		values := make(map[string]float64, len(series))
+		for _, s := range series {
+			if len(s.Samples) > 0 {
+				values[s.Labels["instance"]] = s.Samples[len(s.Samples)-1].Value
+			}
+		}
+
+		return values, nil
+	}
+
+	cpu, err := perInstance(usageQueries.cpu)
+	if err != nil {
+		return err
+	}
+	memory, err := perInstance(usageQueries.memory)
+	if err != nil {
+		return err
+	}
+	concurrency, err := perInstance(usageQueries.concurrency)
+	if err != nil {
+		return err
+	}
+
+	sizes, err := client.PlatformVMSizes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed fetching VM sizes: %w", err)
+	}
+
+	prices := make(map[string]float64, len(sizes))
+	for _, size := range sizes {
+		prices[size.Name] = float64(size.PriceMonth)
+	}
+
+	groups := lo.GroupBy(machines, processGroup)
+
+	var plans []groupPlan
+	for _, name := range sortedGroups(groups) {
+		state, u, ok := groupUsage(name, groups[name], cpu, memory, concurrency)
+		if !ok {
+			plans = append(plans, groupPlan{
+				Group:   name,
+				Reasons: []string{fmt.Sprintf("no metrics over the last %d days", days)},
+			})
+
+			continue
+		}
+
+		plans = append(plans, planGroup(state, u, prices))
+	}
+
+	if config.FromContext(ctx).JSONOutput && !flag.GetBool(ctx, "apply") {
+		return render.JSON(out, plans)
+	}
+
+	if err := renderPlans(ctx, plans); err != nil {
+		return err
+	}
+
+	if !flag.GetBool(ctx, "apply") {
+		return nil
+	}
+
+	changes := lo.Filter(plans, func(p groupPlan, _ int) bool {
+		return p.Size != "" && p.changed()
+	})
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "\nNo changes to apply.")
+
+		return nil
+	}
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirm(ctx, "Apply these changes?"); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	for _, plan := range changes {
+		if err := applyPlan(ctx, flapsClient, appCompact.Name, plan, groups[plan.Group]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func sortedGroups(groups map[string][]*api.Machine) []string {
+	names := lo.Keys(groups)
+	sort.Strings(names)
+
+	return names
+}
+
+// groupUsage returns the state and peak usage of the machines of a process
+// group from the usage of each machine, or false when no machine of it has
+// metrics.
+func groupUsage(group string, machines []*api.Machine, cpu, memory, concurrency map[string]float64) (groupState, usage, bool) {
+	state := groupState{
+		Group:    group,
+		Machines: len(machines),
+		Regions:  len(lo.Uniq(lo.Map(machines, func(m *api.Machine, _ int) string { return m.Region }))),
+	}
+
+	if guest := machines[0].Config.Guest; guest != nil {
+		state.Guest = *guest
+	}
+
+	for _, service := range machines[0].Config.Services {
+		if service.Concurrency != nil && service.Concurrency.SoftLimit > 0 {
+			state.SoftLimit = service.Concurrency.SoftLimit
+
+			break
+		}
+	}
+
+	var (
+		u     usage
+		found bool
+	)
+
+	for _, machine := range machines {
+		if v, ok := cpu[machine.ID]; ok {
+			found = true
+			if cpus := v / 100; cpus > u.CPUs {
+				u.CPUs = cpus
+			}
+		}
+
+		if v, ok := memory[machine.ID]; ok {
+			found = true
+			if mb := v / (1 << 20); mb > u.MemoryMB {
+				u.MemoryMB = mb
+			}
+		}
+
+		if v, ok := concurrency[machine.ID]; ok {
+			if u.Concurrency == nil {
+				u.Concurrency = new(float64)
+			}
+			*u.Concurrency += v
+		}
+	}
+
+	return state, u, found
+}
+
+func renderPlans(ctx context.Context, plans []groupPlan) error {
+	out := iostreams.FromContext(ctx).Out
+
+	rows := make([][]string, 0, len(plans))
+	for _, p := range plans {
+		if p.Size == "" {
+			rows = append(rows, []string{p.Group, "", "", "", strings.Join(p.Reasons, ", ")})
+
+			continue
+		}
+
+		var (
+			current   = fmt.Sprintf("%d x %s %s", p.Machines, p.Size, formatMemory(p.MemoryMB))
+			suggested = "no change"
+			cost      = ""
+		)
+
+		if p.changed() {
+			suggested = fmt.Sprintf("%d x %s %s", p.NewMachines, p.NewSize, formatMemory(p.NewMemoryMB))
+			cost = fmt.Sprintf("%+.2f", p.CostDelta)
+		}
+
+		rows = append(rows, []string{p.Group, current, suggested, cost, strings.Join(p.Reasons, ", ")})
+	}
+
+	return render.Table(out, "", rows, "Group", "Current", "Suggested", "Cost/month ($)", "Why")
+}
+
+func formatMemory(mb int) string {
+	if mb%1024 == 0 {
+		return strconv.Itoa(mb/1024) + "GB"
+	}
+
+	return strconv.Itoa(mb) + "MB"
+}
+
+// applyPlan resizes the machines of a process group and adds or destroys
+// machines of it to follow plan.
+func applyPlan(ctx context.Context, flapsClient *flaps.Client, appName string, plan groupPlan, machines []*api.Machine) error {
+	out := iostreams.FromContext(ctx).Out
+
+	if plan.resized() {
+		for _, machine := range machines {
+			if machine.Config.Guest == nil {
+				continue
+			}
+
+			config := *machine.Config
+			guest := plan.guest(*machine.Config.Guest)
+			config.Guest = &guest
+
+			if err := updateMachine(ctx, flapsClient, appName, machine, &config); err != nil {
+				return err
+			}
+
+			machine.Config = &config
+
+			fmt.Fprintf(out, "Resized machine %s of %s in %s to %s\n", machine.ID, plan.Group, machine.Region, plan.NewSize)
+		}
+	}
+
+	switch {
+	case plan.NewMachines > plan.Machines:
+		return addMachines(ctx, flapsClient, appName, plan, machines)
+	case plan.NewMachines < plan.Machines:
+		return removeMachines(ctx, flapsClient, appName, plan, machines)
+	default:
+		return nil
+	}
+}
+
+// addMachines adds machines to a process group, cloning the config of its
+// first machine into its regions with the fewest machines.
+func addMachines(ctx context.Context, flapsClient *flaps.Client, appName string, plan groupPlan, machines []*api.Machine) error {
+	out := iostreams.FromContext(ctx).Out
+
+	template := machines[0]
+	if len(template.Config.Mounts) > 0 {
+		fmt.Fprintf(out, "Skipped adding machines to %s, its machines have volumes; use fly scale count to add them\n", plan.Group)
+
+		return nil
+	}
+
+	perRegion := countByRegion(machines)
+
+	for i := plan.Machines; i < plan.NewMachines; i++ {
+		region := lo.MinBy(lo.Keys(perRegion), func(a, b string) bool {
+			return perRegion[a] < perRegion[b] || perRegion[a] == perRegion[b] && a < b
+		})
+
+		config := *template.Config
+		m, err := flapsClient.Launch(ctx, api.LaunchMachineInput{
+			AppID:  appName,
+			Region: region,
+			Config: &config,
+		})
+		if err != nil {
+			return fmt.Errorf("failed adding a machine to %s in %s: %w", plan.Group, region, err)
+		}
+
+		perRegion[region]++
+
+		fmt.Fprintf(out, "Added machine %s to %s in %s\n", m.ID, plan.Group, region)
+	}
+
+	return nil
+}
+
+// removeMachines destroys machines of a process group, stopped ones and then
+// the newest first, keeping one in each of its regions.
+func removeMachines(ctx context.Context, flapsClient *flaps.Client, appName string, plan groupPlan, machines []*api.Machine) error {
+	out := iostreams.FromContext(ctx).Out
+
+	candidates := append([]*api.Machine(nil), machines...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if si, sj := candidates[i].State != "started", candidates[j].State != "started"; si != sj {
+			return si
+		}
+
+		return candidates[i].CreatedAt > candidates[j].CreatedAt
+	})
+
+	var (
+		perRegion = countByRegion(machines)
+		surplus   = plan.Machines - plan.NewMachines
+	)
+
+	for _, machine := range candidates {
+		if surplus == 0 {
+			break
+		}
+		if perRegion[machine.Region] == 1 {
+			continue
+		}
+
+		if err := flapsClient.Destroy(ctx, api.RemoveMachineInput{AppID: appName, ID: machine.ID, Kill: true}); err != nil {
+			return fmt.Errorf("failed destroying machine %s: %w", machine.ID, err)
+		}
+
+		perRegion[machine.Region]--
+		surplus--
+
+		fmt.Fprintf(out, "Destroyed machine %s of %s in %s\n", machine.ID, plan.Group, machine.Region)
+	}
+
+	return nil
+}
+
+func countByRegion(machines []*api.Machine) map[string]int {
+	counts := make(map[string]int)
+	for _, machine := range machines {
+		counts[machine.Region]++
+	}
+
+	return counts
+}
@@ -0,0 +1,190 @@
+// Package scale implements the subcommands of the scale command which are
+// new style ones.
+package scale
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// NewZero returns the zero command, which the scale command of the legacy
+// command tree carries.
+func NewZero() *cobra.Command {
+	const (
+		long = `Commands for managing scaling to zero: the proxy stopping the machines of an
+app's services when they're idle, and starting them again on requests.
+
+Settings are applied to the services of the machines, optionally only of a
+process group and regions, so each region can keep its own minimum of machines
+running. Deploys apply the settings of fly.toml again, so mirror them there
+with auto_stop_machines, auto_start_machines and min_machines_running.`
+		short = "Manage scaling to zero of machines"
+	)
+
+	cmd := command.New("zero", short, long, nil)
+
+	cmd.AddCommand(
+		newZeroEnable(),
+		newZeroDisable(),
+		newZeroShow(),
+	)
+
+	return cmd
+}
+
+// selectionFlags are the flags of the zero commands selecting the machines
+// they apply to.
+var selectionFlags = flag.Set{
+	flag.App(),
+	flag.AppConfig(),
+	flag.String{
+		Name:        "group",
+		Description: "Only apply to the machines of this process group",
+	},
+	flag.StringSlice{
+		Name:        "region",
+		Shorthand:   "r",
+		Description: "Only apply to the machines in these regions",
+	},
+}
+
+// selectMachines returns the app of ctx and the machines of it the
+// selection flags match, sorted by process group and region.
+func selectMachines(ctx context.Context) (*api.AppCompact, *flaps.Client, []*api.Machine, error) {
+	appName := app.NameFromContext(ctx)
+
+	appCompact, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed fetching app: %w", err)
+	}
+
+	if appCompact.PlatformVersion != app.MachinesPlatform {
+		return nil, nil, nil, fmt.Errorf("app %s doesn't run on machines", appName)
+	}
+
+	flapsClient, err := flaps.New(ctx, appCompact)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	var (
+		group    = flag.GetString(ctx, "group")
+		regions  = flag.GetStringSlice(ctx, "region")
+		selected []*api.Machine
+	)
+
+	for _, machine := range machines {
+		if machine.Config == nil {
+			continue
+		}
+		if group != "" && processGroup(machine) != group {
+			continue
+		}
+		if len(regions) > 0 && !lo.Contains(regions, machine.Region) {
+			continue
+		}
+
+		selected = append(selected, machine)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool {
+		if gi, gj := processGroup(selected[i]), processGroup(selected[j]); gi != gj {
+			return gi < gj
+		}
+
+		return selected[i].Region < selected[j].Region
+	})
+
+	if len(selected) == 0 {
+		return nil, nil, nil, fmt.Errorf("no machines of %s match the process group and regions given", appName)
+	}
+
+	return appCompact, flapsClient, selected, nil
+}
+
+func processGroup(machine *api.Machine) string {
+	if group := machine.Config.Metadata[api.MachineProcessGroupKey]; group != "" {
+		return group
+	}
+
+	return "app"
+}
+
+// updateServices applies update to the services of machines, one machine at
+// a time, waiting for those which were started to start again. Machines
+// without services are skipped, as the proxy only stops and starts machines
+// for them; the count of those updated is returned.
+func updateServices(ctx context.Context, flapsClient *flaps.Client, appName string, machines []*api.Machine, update func(*api.MachineService)) (int, error) {
+	out := iostreams.FromContext(ctx).Out
+
+	var updated int
+	for _, machine := range machines {
+		if len(machine.Config.Services) == 0 {
+			fmt.Fprintf(out, "Skipped machine %s, it has no services\n", machine.ID)
+
+			continue
+		}
+
+		config := *machine.Config
+		config.Services = append([]api.MachineService(nil), machine.Config.Services...)
+		for i := range config.Services {
+			update(&config.Services[i])
+		}
+
+		if err := updateMachine(ctx, flapsClient, appName, machine, &config); err != nil {
+			return updated, err
+		}
+
+		updated++
+
+		fmt.Fprintf(out, "Updated machine %s of %s in %s\n", machine.ID, processGroup(machine), machine.Region)
+	}
+
+	return updated, nil
+}
+
+// updateMachine updates machine to config, waiting for it to start again
+// when it was started.
+func updateMachine(ctx context.Context, flapsClient *flaps.Client, appName string, machine *api.Machine, config *api.MachineConfig) error {
+	m, err := flapsClient.Update(ctx, api.LaunchMachineInput{
+		ID:     machine.ID,
+		AppID:  appName,
+		Name:   machine.Name,
+		Region: machine.Region,
+		Config: config,
+	}, "")
+	if err != nil {
+		return fmt.Errorf("failed updating machine %s: %w", machine.ID, err)
+	}
+
+	if machine.State != "started" {
+		return nil
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	if err := flapsClient.Wait(waitCtx, m, "started"); err != nil {
+		return fmt.Errorf("machine %s didn't start again: %w", machine.ID, err)
+	}
+
+	return nil
+}
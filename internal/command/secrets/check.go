@@ -0,0 +1,52 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCheck() (cmd *cobra.Command) {
+	const (
+		long = `Check that every secret listed in the [env.required_secrets] table of
+the app's fly.toml has been set. Exits with an error listing the missing
+secrets, if any.`
+		short = "Check that the secrets required by fly.toml are set"
+		usage = "check [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runCheck, command.RequireSession, command.RequireAppName)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runCheck(ctx context.Context) error {
+	appName := app.NameFromContext(ctx)
+	out := iostreams.FromContext(ctx).Out
+
+	cfg := app.ConfigFromContext(ctx)
+	if cfg == nil {
+		return fmt.Errorf("no fly.toml found; required secrets are declared in [env.required_secrets]")
+	}
+
+	if err := deploy.CheckRequiredSecrets(ctx, appName, cfg); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "All %d required secrets are set\n", len(cfg.RequiredSecrets))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/samber/lo"
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// fileMachines returns the active machines of app, limited to the machines
+// of group when it's set.
+func fileMachines(ctx context.Context, flapsClient *flaps.Client, group string) ([]*api.Machine, error) {
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if group != "" {
+		machines = lo.Filter(machines, func(m *api.Machine, _ int) bool {
+			return m.Config.Metadata[api.MachineProcessGroupKey] == group
+		})
+
+		if len(machines) == 0 {
+			return nil, fmt.Errorf("no machines found in process group %s", group)
+		}
+	}
+
+	return machines, nil
+}
+
+// updateSecretFiles rolls the machines of app, or those of group when it's
+// set, updating the files their configs expose secrets as with edit.
+// Since updating a machine restarts it, this also applies the app's secrets.
+func updateSecretFiles(ctx context.Context, app *api.AppCompact, group string, edit func([]api.MachineFile) []api.MachineFile) error {
+	out := iostreams.FromContext(ctx).Out
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := fileMachines(ctx, flapsClient, group)
+	if err != nil {
+		return err
+	}
+
+	for _, machine := range machines {
+		if err := updateMachineFiles(ctx, flapsClient, app, machine, edit); err != nil {
+			return fmt.Errorf("failed updating machine %s: %w", machine.ID, err)
+		}
+
+		fmt.Fprintf(out, "Machine %s updated\n", machine.ID)
+	}
+
+	return nil
+}
+
+func updateMachineFiles(ctx context.Context, flapsClient *flaps.Client, app *api.AppCompact, machine *api.Machine, edit func([]api.MachineFile) []api.MachineFile) error {
+	lease, err := flapsClient.GetLease(ctx, machine.ID, api.IntPointer(30))
+	if err != nil {
+		return err
+	}
+	defer flapsClient.ReleaseLease(ctx, machine.ID, lease.Data.Nonce)
+
+	config := *machine.Config
+	config.Files = edit(append([]api.MachineFile(nil), machine.Config.Files...))
+
+	updated, err := flapsClient.Update(ctx, api.LaunchMachineInput{
+		ID:      machine.ID,
+		AppID:   app.Name,
+		OrgSlug: app.Organization.ID,
+		Region:  machine.Region,
+		Config:  &config,
+	}, lease.Data.Nonce)
+	if err != nil {
+		return err
+	}
+
+	return flapsClient.Wait(ctx, updated, "started")
+}
+
+// withSecretFile returns files with the file at guestPath exposing the
+// secret name, replacing any other file at the same path.
+func withSecretFile(name, guestPath string) func([]api.MachineFile) []api.MachineFile {
+	return func(files []api.MachineFile) []api.MachineFile {
+		files = lo.Reject(files, func(f api.MachineFile, _ int) bool {
+			return f.GuestPath == guestPath
+		})
+
+		return append(files, api.MachineFile{GuestPath: guestPath, SecretName: name})
+	}
+}
+
+// withoutSecretFiles returns files without those exposing any of the named
+// secrets.
+func withoutSecretFiles(names []string) func([]api.MachineFile) []api.MachineFile {
+	return func(files []api.MachineFile) []api.MachineFile {
+		return lo.Reject(files, func(f api.MachineFile, _ int) bool {
+			return lo.Contains(names, f.SecretName)
+		})
+	}
+}
+
+// secretFilesIn reports whether any of machines expose any of the named
+// secrets as files.
+func secretFilesIn(machines []*api.Machine, names []string) bool {
+	return lo.SomeBy(machines, func(m *api.Machine) bool {
+		return lo.SomeBy(m.Config.Files, func(f api.MachineFile) bool {
+			return lo.Contains(names, f.SecretName)
+		})
+	})
+}
@@ -0,0 +1,100 @@
+package secrets
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/helpers"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newGenerate() (cmd *cobra.Command) {
+	const (
+		long = `Generate a cryptographically random value and set it as an encrypted
+secret for an application. The value is printed once and can't be retrieved
+afterwards.`
+		short = "Set a secret to a randomly generated value"
+		usage = "generate [flags] NAME"
+	)
+
+	cmd = command.New(usage, short, long, runGenerate, command.RequireSession, command.LoadAppNameIfPresent)
+
+	flag.Add(cmd,
+		sharedFlags,
+		flag.Int{
+			Name:        "length",
+			Description: "Number of random bytes, or characters for the alphanumeric format",
+			Default:     32,
+		},
+		flag.String{
+			Name:        "format",
+			Description: "Encoding of the generated value. Options are alphanumeric, hex or base64",
+			Default:     "alphanumeric",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runGenerate(ctx context.Context) (err error) {
+	client := client.FromContext(ctx).API()
+	appName := app.NameFromContext(ctx)
+	out := iostreams.FromContext(ctx).Out
+
+	name := flag.FirstArg(ctx)
+
+	value, err := generateValue(flag.GetString(ctx, "format"), flag.GetInt(ctx, "length"))
+	if err != nil {
+		return err
+	}
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	secrets := map[string]string{name: value}
+
+	release, err := setSecrets(ctx, appName, secrets)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s=%s\n", name, value)
+
+	return deployForSecrets(ctx, app, release, []string{name}, false)
+}
+
+func generateValue(format string, length int) (string, error) {
+	if length < 1 {
+		return "", fmt.Errorf("length must be positive, got %d", length)
+	}
+
+	if format == "alphanumeric" {
+		return helpers.RandString(length)
+	}
+
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	switch format {
+	case "hex":
+		return hex.EncodeToString(b), nil
+	case "base64":
+		return base64.StdEncoding.EncodeToString(b), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q; use alphanumeric, hex or base64", format)
+	}
+}
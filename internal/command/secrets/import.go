@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
@@ -94,10 +95,10 @@ func runImport(ctx context.Context) (err error) {
 		return errors.New("requires at least one SECRET=VALUE pair")
 	}
 
-	release, err := client.SetSecrets(ctx, appName, secrets)
+	release, err := setSecrets(ctx, appName, secrets)
 	if err != nil {
 		return err
 	}
 
-	return deployForSecrets(ctx, app, release)
+	return deployForSecrets(ctx, app, release, lo.Keys(secrets), false)
 }
@@ -7,7 +7,6 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
-	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/format"
 	"github.com/superfly/flyctl/internal/render"
@@ -28,6 +27,7 @@ func newList() (cmd *cobra.Command) {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Format(),
 	)
 
 	return cmd
@@ -38,7 +38,6 @@ func runList(ctx context.Context) (err error) {
 	appName := app.NameFromContext(ctx)
 	out := iostreams.FromContext(ctx).Out
 	secrets, err := client.GetAppSecrets(ctx, appName)
-	cfg := config.FromContext(ctx)
 
 	if err != nil {
 		return err
@@ -59,9 +58,9 @@ func runList(ctx context.Context) (err error) {
 		"Digest",
 		"Created At",
 	}
-	if cfg.JSONOutput {
-		return render.JSON(out, secrets)
-	} else {
-		return render.Table(out, "", rows, headers...)
+	if ok, err := render.Structured(ctx, out, secrets); ok {
+		return err
 	}
+
+	return render.Table(out, "", rows, headers...)
 }
@@ -6,9 +6,11 @@ import (
 	"fmt"
 
 	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/deploy"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/stagedsecrets"
 	"github.com/superfly/flyctl/internal/watch"
 	"github.com/superfly/flyctl/iostreams"
 
@@ -25,6 +27,12 @@ var sharedFlags = flag.Set{
 	},
 }
 
+// groupFlag is the --group flag of fly secrets set and apply.
+var groupFlag = flag.String{
+	Name:        "group",
+	Description: "Only expose the secrets to, and redeploy, machines in this process group",
+}
+
 func New() *cobra.Command {
 	const (
 		long = `Secrets are provided to applications at runtime as ENV variables. Names are
@@ -42,12 +50,46 @@ func New() *cobra.Command {
 		newSet(),
 		newUnset(),
 		newImport(),
+		newGenerate(),
+		newCheck(),
+		newStage(),
+		newStaged(),
+		newApply(),
 	)
 
 	return secrets
 }
 
-func deployForSecrets(ctx context.Context, app *api.AppCompact, release *api.Release) (err error) {
+// processGroup returns the process group passed via --group, which only
+// some of the secrets commands take.
+func processGroup(ctx context.Context) string {
+	if flag.FromContext(ctx).Lookup(groupFlag.Name) == nil {
+		return ""
+	}
+
+	return flag.GetString(ctx, groupFlag.Name)
+}
+
+// setSecrets sets secrets on the app, scoping them to the process group
+// passed via --group, if any. Scoping needs the API to take the process
+// groups of secrets, which it doesn't everywhere yet.
+func setSecrets(ctx context.Context, appName string, secrets map[string]string) (*api.Release, error) {
+	client := client.FromContext(ctx).API()
+
+	if group := processGroup(ctx); group != "" {
+		if err := command.CheckAPI(ctx, "SecretInput", "processGroups"); err != nil {
+			return nil, fmt.Errorf("--group isn't available: %w", err)
+		}
+
+		return client.SetProcessGroupSecrets(ctx, appName, []string{group}, secrets)
+	}
+
+	return client.SetSecrets(ctx, appName, secrets)
+}
+
+// deployForSecrets deploys the changes made to the named secrets, or stages
+// them for a later apply should --stage be set.
+func deployForSecrets(ctx context.Context, app *api.AppCompact, release *api.Release, names []string, unset bool) (err error) {
 	out := iostreams.FromContext(ctx).Out
 
 	if flag.GetBool(ctx, "stage") {
@@ -56,17 +98,29 @@ func deployForSecrets(ctx context.Context, app *api.AppCompact, release *api.Rel
 			return errors.New("--stage isn't available for Nomad apps")
 		}
 
-		fmt.Fprint(out, "Secrets have been staged, but not set on VMs. Deploy or update machines in this app for the secrets to take effect.\n")
+		if err = stagedsecrets.Record(ctx, app.Name, names, unset); err != nil {
+			return fmt.Errorf("failed recording staged secrets: %w", err)
+		}
+
+		fmt.Fprint(out, "Secrets have been staged, but not set on VMs. Run `fly secrets apply` or deploy this app for the secrets to take effect.\n")
 		return
 	}
 
+	return deployRelease(ctx, app, release, processGroup(ctx), flag.GetBool(ctx, "detach"))
+}
+
+// deployRelease deploys the release setting secrets created, redeploying the
+// machines of group, or all of them when group is empty, of machines apps.
+func deployRelease(ctx context.Context, app *api.AppCompact, release *api.Release, group string, detach bool) (err error) {
+	out := iostreams.FromContext(ctx).Out
+
 	if app.PlatformVersion == "machines" {
 
-		if flag.GetBool(ctx, "detach") {
+		if detach {
 			fmt.Fprint(out, "The --detach option isn't available for Machine apps")
 		}
 
-		return deploy.DeployMachinesApp(ctx, app, "rolling", api.MachineConfig{}, nil)
+		return deploy.DeployMachinesApp(ctx, app, "rolling", api.MachineConfig{}, nil, group)
 	}
 
 	if !app.Deployed {
@@ -76,7 +130,7 @@ func deployForSecrets(ctx context.Context, app *api.AppCompact, release *api.Rel
 
 	fmt.Fprintf(out, "Release v%d created\n", release.Version)
 
-	if flag.GetBool(ctx, "detach") {
+	if detach {
 		return
 	}
 
@@ -84,3 +138,40 @@ func deployForSecrets(ctx context.Context, app *api.AppCompact, release *api.Rel
 
 	return err
 }
+
+// SetAndDeploy sets the secrets of the app named appName to values, and
+// deploys it for them to take effect, like fly secrets set does. Commands
+// rotating credentials use it to update the apps using them.
+func SetAndDeploy(ctx context.Context, appName string, values map[string]string) error {
+	client := client.FromContext(ctx).API()
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	release, err := client.SetSecrets(ctx, appName, values)
+	if err != nil {
+		return err
+	}
+
+	return deployRelease(ctx, app, release, "", false)
+}
+
+// UnsetAndDeploy unsets the named secrets of the app named appName, and
+// deploys it for that to take effect, like fly secrets unset does.
+func UnsetAndDeploy(ctx context.Context, appName string, names []string) error {
+	client := client.FromContext(ctx).API()
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+	}
+
+	release, err := client.UnsetSecrets(ctx, appName, names)
+	if err != nil {
+		return err
+	}
+
+	return deployRelease(ctx, app, release, "", false)
+}
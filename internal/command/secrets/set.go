@@ -2,10 +2,16 @@ package secrets
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
+	"regexp"
+	"strings"
 
+	"github.com/samber/lo"
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/helpers"
 	"github.com/superfly/flyctl/internal/app"
@@ -16,8 +22,15 @@ import (
 
 func newSet() (cmd *cobra.Command) {
 	const (
-		long  = `Set one or more encrypted secrets for an application`
-		short = long
+		long = `Set one or more encrypted secrets for an application.
+
+With --file, a single secret is set from the contents of a local file given
+as NAME=@path (or just @path, in which case the name is derived from the
+guest path) and exposed to machines as a file at the given guest path rather
+than as an environment variable. The file is added to the config of each
+machine, which is restarted for it to take effect. File secrets are only
+available for machines apps.`
+		short = `Set one or more encrypted secrets for an application`
 		usage = "set [flags] NAME=VALUE NAME=VALUE ..."
 	)
 
@@ -25,6 +38,11 @@ func newSet() (cmd *cobra.Command) {
 
 	flag.Add(cmd,
 		sharedFlags,
+		groupFlag,
+		flag.String{
+			Name:        "file",
+			Description: "Guest path to expose the secret at as a file, instead of as an environment variable",
+		},
 	)
 
 	cmd.Args = cobra.MinimumNArgs(1)
@@ -40,6 +58,10 @@ func runSet(ctx context.Context) (err error) {
 		return err
 	}
 
+	if guestPath := flag.GetString(ctx, "file"); guestPath != "" {
+		return runSetFile(ctx, app, guestPath)
+	}
+
 	secrets, err := cmdutil.ParseKVStringsToMap(flag.Args(ctx))
 	if err != nil {
 		return fmt.Errorf("could not parse secrets: %w", err)
@@ -52,7 +74,7 @@ func runSet(ctx context.Context) (err error) {
 			}
 			inval, err := helpers.ReadStdin(64 * 1024)
 			if err != nil {
-				return fmt.Errorf("error reading stdin for '%s': %s", k, err)
+				return fmt.Errorf("error reading stdin for '%s': %w", k, err)
 			}
 			secrets[k] = inval
 		}
@@ -62,10 +84,57 @@ func runSet(ctx context.Context) (err error) {
 		return errors.New("requires at least one SECRET=VALUE pair")
 	}
 
-	release, err := client.SetSecrets(ctx, appName, secrets)
+	release, err := setSecrets(ctx, appName, secrets)
 	if err != nil {
 		return err
 	}
 
-	return deployForSecrets(ctx, app, release)
+	return deployForSecrets(ctx, app, release, lo.Keys(secrets), false)
+}
+
+func runSetFile(ctx context.Context, app *api.AppCompact, guestPath string) error {
+	client := client.FromContext(ctx).API()
+
+	if app.PlatformVersion != "machines" {
+		return errors.New("--file is only available for machines apps")
+	}
+
+	if flag.GetBool(ctx, "stage") {
+		return errors.New("--file can't be combined with --stage, as file secrets are set on machines directly")
+	}
+
+	args := flag.Args(ctx)
+	if len(args) != 1 {
+		return errors.New("--file requires exactly one NAME=@path argument")
+	}
+
+	name, localPath, found := strings.Cut(args[0], "=")
+	if !found {
+		name, localPath = fileSecretName(guestPath), name
+	}
+
+	if !strings.HasPrefix(localPath, "@") {
+		return fmt.Errorf("file secrets must be given as NAME=@path (%s is invalid)", args[0])
+	}
+
+	data, err := os.ReadFile(strings.TrimPrefix(localPath, "@"))
+	if err != nil {
+		return fmt.Errorf("could not read secret file: %w", err)
+	}
+
+	value := base64.StdEncoding.EncodeToString(data)
+
+	if _, err := client.SetSecrets(ctx, app.Name, map[string]string{name: value}); err != nil {
+		return err
+	}
+
+	return updateSecretFiles(ctx, app, processGroup(ctx), withSecretFile(name, guestPath))
+}
+
+var nonEnvChars = regexp.MustCompile(`[^A-Z0-9]+`)
+
+// fileSecretName derives a secret name from the guest path of a file secret,
+// turning /etc/app/sa.json into ETC_APP_SA_JSON.
+func fileSecretName(guestPath string) string {
+	return strings.Trim(nonEnvChars.ReplaceAllString(strings.ToUpper(guestPath), "_"), "_")
 }
@@ -0,0 +1,158 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/deploy"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/stagedsecrets"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newStage() (cmd *cobra.Command) {
+	const (
+		long = `Stage one or more encrypted secrets for a machines application
+without deploying them. Staged changes accumulate until they are deployed
+together with the apply command, or by the next deploy.
+
+The secrets are set right away, but which of them are staged is tracked on this
+computer only, so the staged and apply commands only know of the changes staged
+here.`
+		short = "Stage secrets to be deployed later with the apply command"
+		usage = "stage [flags] NAME=VALUE NAME=VALUE ..."
+	)
+
+	cmd = command.New(usage, short, long, runStage, command.RequireSession, command.LoadAppNameIfPresent)
+
+	flag.Add(cmd,
+		sharedFlags,
+	)
+
+	cmd.Args = cobra.MinimumNArgs(1)
+
+	return cmd
+}
+
+func runStage(ctx context.Context) error {
+	if err := flag.FromContext(ctx).Set("stage", "true"); err != nil {
+		return err
+	}
+
+	return runSet(ctx)
+}
+
+func newStaged() (cmd *cobra.Command) {
+	const (
+		long  = `List the secret changes which have been staged but not yet applied`
+		short = long
+		usage = "staged [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runStaged, command.RequireSession, command.LoadAppNameIfPresent)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	return cmd
+}
+
+func runStaged(ctx context.Context) error {
+	appName := app.NameFromContext(ctx)
+	out := iostreams.FromContext(ctx).Out
+
+	changes, err := stagedsecrets.Load(ctx)
+	if err != nil {
+		return err
+	}
+	pending := changes[appName]
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, pending)
+	}
+
+	var rows [][]string
+	for _, change := range pending {
+		action := "set"
+		if change.Unset {
+			action = "unset"
+		}
+
+		rows = append(rows, []string{
+			change.Name,
+			action,
+			format.RelativeTime(change.StagedAt),
+		})
+	}
+
+	return render.Table(out, "", rows, "Name", "Action", "Staged At")
+}
+
+func newApply() (cmd *cobra.Command) {
+	const (
+		long = `Deploy all staged secret changes, restarting the application's machines
+exactly once. With --group, only the machines of that process group are
+restarted, and the changes stay staged until all machines are deployed.`
+		short = "Deploy staged secret changes"
+		usage = "apply [flags]"
+	)
+
+	cmd = command.New(usage, short, long, runApply, command.RequireSession, command.LoadAppNameIfPresent)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		groupFlag,
+	)
+
+	return cmd
+}
+
+func runApply(ctx context.Context) error {
+	client := client.FromContext(ctx).API()
+	appName := app.NameFromContext(ctx)
+	out := iostreams.FromContext(ctx).Out
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	if app.PlatformVersion != "machines" {
+		return errors.New("staged secrets are only available for machines apps")
+	}
+
+	changes, err := stagedsecrets.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(changes[appName]) == 0 {
+		fmt.Fprintf(out, "No staged secrets for %s\n", appName)
+		return nil
+	}
+
+	// deploying all the machines clears the staged changes
+	group := processGroup(ctx)
+	if err := deploy.DeployMachinesApp(ctx, app, "rolling", api.MachineConfig{}, nil, group); err != nil {
+		return err
+	}
+
+	if group != "" {
+		fmt.Fprintf(out, "Staged secrets were applied to the machines of process group %s, and stay staged until all machines are deployed\n", group)
+	}
+
+	return nil
+}
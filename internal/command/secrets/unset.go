@@ -2,9 +2,12 @@ package secrets
 
 import (
 	"context"
+	"errors"
 
 	"github.com/spf13/cobra"
+	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
@@ -36,10 +39,54 @@ func runUnset(ctx context.Context) (err error) {
 		return err
 	}
 
-	release, err := client.UnsetSecrets(ctx, appName, flag.Args(ctx))
+	names := flag.Args(ctx)
+
+	if app.PlatformVersion == "machines" {
+		return unsetMachines(ctx, app, names)
+	}
+
+	release, err := client.UnsetSecrets(ctx, appName, names)
+	if err != nil {
+		return err
+	}
+
+	return deployForSecrets(ctx, app, release, names, true)
+}
+
+// unsetMachines unsets the named secrets of a machines app. Secrets its
+// machines expose as files are dropped from their configs as well, since
+// machines referencing missing secrets fail to start.
+func unsetMachines(ctx context.Context, app *api.AppCompact, names []string) error {
+	client := client.FromContext(ctx).API()
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := fileMachines(ctx, flapsClient, "")
 	if err != nil {
 		return err
 	}
 
-	return deployForSecrets(ctx, app, release)
+	if !secretFilesIn(machines, names) {
+		release, err := client.UnsetSecrets(ctx, app.Name, names)
+		if err != nil {
+			return err
+		}
+
+		return deployForSecrets(ctx, app, release, names, true)
+	}
+
+	if flag.GetBool(ctx, "stage") {
+		return errors.New("secrets exposed as files can't be unset with --stage")
+	}
+
+	if err := updateSecretFiles(ctx, app, "", withoutSecretFiles(names)); err != nil {
+		return err
+	}
+
+	_, err = client.UnsetSecrets(ctx, app.Name, names)
+
+	return err
 }
@@ -0,0 +1,296 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/state"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newConfig() *cobra.Command {
+	const (
+		long = `Add entries for the VMs of the current app to your OpenSSH configuration, so
+that plain ssh, scp, rsync and remote development in IDEs work against them,
+e.g. ssh myapp-fra-1.fly.
+
+VMs are named after the app, their region and their number within it. Their
+connections are proxied through the WireGuard tunnel of the Fly agent, and a
+certificate is issued to log in with, which is stored in the flyctl
+configuration directory. Run this again when VMs change or the certificate
+expires.`
+		short = "Add entries for the app's VMs to your OpenSSH configuration"
+		usage = "config"
+	)
+
+	cmd := command.New(usage, short, long, runConfig, command.RequireSession, command.RequireAppName)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Int{
+			Name:        "hours",
+			Default:     24,
+			Description: "Expiration of the certificate, in hours (1-72)",
+		},
+		flag.String{
+			Name:        "path",
+			Description: "Path of the OpenSSH configuration to update (defaults to ~/.ssh/config)",
+		},
+		flag.Bool{
+			Name:        "print",
+			Description: "Print the entries rather than updating the OpenSSH configuration",
+		},
+	)
+
+	return cmd
+}
+
+func runConfig(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		appName   = app.NameFromContext(ctx)
+	)
+
+	hours := flag.GetInt(ctx, "hours")
+	if hours < 1 || hours > 72 {
+		return fmt.Errorf("invalid expiration time %d, must be between 1 and 72 hours", hours)
+	}
+
+	app, err := apiClient.GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("get app: %w", err)
+	}
+
+	if app.PlatformVersion != "machines" {
+		return fmt.Errorf("app %s doesn't run on machines; ssh config only supports VMs of machine apps", appName)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(machines) == 0 {
+		return fmt.Errorf("app %s has no started or stopped VMs", appName)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed locating flyctl: %w", err)
+	}
+
+	user, err := apiClient.GetCurrentUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	cert, err := apiClient.IssueSSHCertificate(ctx, app.Organization, user.Email, nil, &hours)
+	if err != nil {
+		return fmt.Errorf("create ssh certificate: %w (if you haven't created a key for your org yet, try `flyctl ssh establish`)", err)
+	}
+
+	keyPath, err := writeCertificate(ctx, app.Organization.Slug, cert)
+	if err != nil {
+		return err
+	}
+
+	block := sshConfigBlock(app, machines, exe, keyPath)
+
+	if flag.GetBool(ctx, "print") {
+		_, err = io.Out.Write(block)
+		return err
+	}
+
+	path := flag.GetString(ctx, "path")
+	if path == "" {
+		path = filepath.Join(state.UserHomeDirectory(ctx), ".ssh", "config")
+	}
+
+	if err := updateSSHConfig(path, appName, block); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(io.Out, "Added %d VMs of %s to %s, with a %d-hour certificate:\n", len(machines), appName, path, hours)
+	_, names := sshHosts(appName, machines)
+	for _, host := range names {
+		fmt.Fprintf(io.Out, "  ssh %s\n", host)
+	}
+
+	return nil
+}
+
+// writeCertificate stores the private key and certificate issued for org and
+// returns the path of the private key.
+func writeCertificate(ctx context.Context, org string, cert *api.IssuedCertificate) (string, error) {
+	pk, err := parsePrivateKey(cert.Key)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(state.ConfigDirectory(ctx), "ssh")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+
+	keyPath := filepath.Join(dir, org)
+	if err := os.WriteFile(keyPath, MarshalED25519PrivateKey(pk, "fly.io"), 0o600); err != nil {
+		return "", fmt.Errorf("failed writing private key: %w", err)
+	}
+
+	if err := os.WriteFile(keyPath+"-cert.pub", []byte(cert.Certificate), 0o600); err != nil {
+		return "", fmt.Errorf("failed writing certificate: %w", err)
+	}
+
+	return keyPath, nil
+}
+
+// sshHosts returns machines sorted by region, without reordering machines
+// itself, along with their names in the OpenSSH configuration.
+func sshHosts(appName string, machines []*api.Machine) ([]*api.Machine, []string) {
+	sorted := append([]*api.Machine(nil), machines...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Region != sorted[j].Region {
+			return sorted[i].Region < sorted[j].Region
+		}
+
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	names := make([]string, 0, len(sorted))
+	inRegion := map[string]int{}
+
+	for _, machine := range sorted {
+		inRegion[machine.Region]++
+		names = append(names, fmt.Sprintf("%s-%s-%d.fly", appName, machine.Region, inRegion[machine.Region]))
+	}
+
+	return sorted, names
+}
+
+func sshConfigBegin(appName string) string {
+	return "# BEGIN fly ssh config " + appName
+}
+
+func sshConfigEnd(appName string) string {
+	return "# END fly ssh config " + appName
+}
+
+// sshConfigBlock returns the OpenSSH configuration of the machines of app.
+func sshConfigBlock(app *api.AppCompact, machines []*api.Machine, exe, keyPath string) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, sshConfigBegin(app.Name))
+
+	machines, names := sshHosts(app.Name, machines)
+	for i, machine := range machines {
+		fmt.Fprintf(&b, "Host %s\n", names[i])
+		fmt.Fprintf(&b, "  HostName %s\n", machine.PrivateIP)
+	}
+
+	knownHosts := "/dev/null"
+	if runtime.GOOS == "windows" {
+		knownHosts = "NUL"
+	}
+
+	fmt.Fprintf(&b, "Host %s\n", strings.Join(names, " "))
+	fmt.Fprintln(&b, "  User root")
+	fmt.Fprintf(&b, "  IdentityFile \"%s\"\n", keyPath)
+	fmt.Fprintf(&b, "  CertificateFile \"%s\"\n", keyPath+"-cert.pub")
+	fmt.Fprintln(&b, "  IdentitiesOnly yes")
+	fmt.Fprintf(&b, "  ProxyCommand \"%s\" ssh proxy --org %s %%h %%p\n", exe, app.Organization.Slug)
+	fmt.Fprintln(&b, "  # VMs get new host keys when they're replaced")
+	fmt.Fprintln(&b, "  StrictHostKeyChecking no")
+	fmt.Fprintf(&b, "  UserKnownHostsFile %s\n", knownHosts)
+	fmt.Fprintln(&b, "  LogLevel ERROR")
+
+	fmt.Fprintln(&b, sshConfigEnd(app.Name))
+
+	return b.Bytes()
+}
+
+// updateSSHConfig replaces the entries of the app in the OpenSSH configuration
+// at path with block, or adds block when it has none yet: before the first
+// Host * entry, since OpenSSH uses the first value it finds for each option,
+// or else at the end.
+func updateSSHConfig(path, appName string, block []byte) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed reading %s: %w", path, err)
+	}
+
+	begin := bytes.Index(data, []byte(sshConfigBegin(appName)+"\n"))
+	end := bytes.Index(data, []byte(sshConfigEnd(appName)+"\n"))
+
+	switch {
+	case begin >= 0 && end > begin:
+		end += len(sshConfigEnd(appName)) + 1
+		data = append(data[:begin:begin], append(block, data[end:]...)...)
+	case len(data) == 0:
+		data = block
+	default:
+		if i := catchAllHost(data); i >= 0 {
+			data = append(data[:i:i], append(append(block, '\n'), data[i:]...)...)
+			break
+		}
+
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			data = append(data, '\n')
+		}
+
+		data = append(append(data, '\n'), block...)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed writing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// catchAllHost returns the offset of the first Host * line of the OpenSSH
+// configuration data, or -1 when it has none.
+func catchAllHost(data []byte) int {
+	var offset int
+
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		fields := strings.Fields(strings.ReplaceAll(string(line), "=", " "))
+		if len(fields) >= 2 && strings.EqualFold(fields[0], "Host") {
+			for _, pattern := range fields[1:] {
+				if pattern == "*" {
+					return offset
+				}
+			}
+		}
+
+		offset += len(line)
+	}
+
+	return -1
+}
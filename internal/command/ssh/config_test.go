@@ -0,0 +1,41 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateSSHConfig(t *testing.T) {
+	block := []byte(sshConfigBegin("myapp") + "\nHost myapp-fra-1.fly\n" + sshConfigEnd("myapp") + "\n")
+	updated := []byte(sshConfigBegin("myapp") + "\nHost myapp-ams-1.fly\n" + sshConfigEnd("myapp") + "\n")
+
+	cases := []struct {
+		name, config string
+		block        []byte
+		want         string
+	}{
+		{"empty", "", block, string(block)},
+		{"appended", "Host foo\n  User bar\n", block, "Host foo\n  User bar\n\n" + string(block)},
+		{"before catch-all", "Host foo\n  User bar\n\nHost *\n  User baz\n", block, "Host foo\n  User bar\n\n" + string(block) + "\nHost *\n  User baz\n"},
+		{"replaced", "Host foo\n\n" + string(block) + "\nHost *\n", updated, "Host foo\n\n" + string(updated) + "\nHost *\n"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if c.config != "" {
+				require.NoError(t, os.WriteFile(path, []byte(c.config), 0o600))
+			}
+
+			require.NoError(t, updateSSHConfig(path, "myapp", c.block))
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, c.want, string(data))
+		})
+	}
+}
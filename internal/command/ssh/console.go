@@ -3,12 +3,15 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"os"
+	"strings"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	gossh "golang.org/x/crypto/ssh"
 
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/api"
@@ -17,6 +20,8 @@ import (
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/flyerr"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/internal/sentry"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/ip"
@@ -44,12 +49,21 @@ func stdArgsSSH(cmd *cobra.Command) {
 		flag.String{
 			Name:        "region",
 			Shorthand:   "r",
-			Description: "Region to create WireGuard connection in",
+			Description: "Connect to a VM in this region",
+		},
+		flag.String{
+			Name:        "group",
+			Shorthand:   "g",
+			Description: "Connect to a VM of this process group",
+		},
+		flag.String{
+			Name:        "role",
+			Description: "Connect to a VM with this role, such as leader or replica for Postgres apps",
 		},
 		flag.Bool{
 			Name:        "quiet",
 			Shorthand:   "q",
-			Description: "Don't print progress indicators for WireGuard",
+			Description: "Don't print progress indicators for WireGuard and connecting",
 		},
 		flag.String{
 			Name:        "address",
@@ -87,8 +101,19 @@ func lookupAddress(ctx context.Context, cli *agent.Client, dialer agent.Dialer,
 
 func newConsole() *cobra.Command {
 	const (
-		long  = `Connect to a running instance of the current app.`
-		short = long
+		long = `Connect to a running instance of the current app.
+
+When the app runs on several machines, you're prompted to pick one, unless
+--group, --region or --role narrow them down to a single one.
+
+With --command, the command's input can be piped in and flyctl exits with the
+command's exit code, e.g. cat dump.sql | fly ssh console -q -C "psql".
+
+Ports are forwarded like with OpenSSH: -L 8080:localhost:80 forwards port 8080
+of this machine to port 80 of the VM, and -R 9000:localhost:9000 forwards port
+9000 of the VM to port 9000 of this machine. With -N, only ports are forwarded,
+until flyctl is interrupted.`
+		short = `Connect to a running instance of the current app.`
 		usage = "console"
 	)
 
@@ -98,6 +123,28 @@ func newConsole() *cobra.Command {
 
 	stdArgsSSH(cmd)
 
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "local-forward",
+			Shorthand:   "L",
+			Description: "Forward a local port to the VM, as [bind_address:]port:host:hostport",
+		},
+		flag.StringSlice{
+			Name:        "remote-forward",
+			Shorthand:   "R",
+			Description: "Forward a port of the VM to this machine, as [bind_address:]port:host:hostport",
+		},
+		flag.Bool{
+			Name:        "no-command",
+			Shorthand:   "N",
+			Description: "Only forward ports, without running a shell or command",
+		},
+		flag.Duration{
+			Name:        "keep-alive",
+			Description: "Interval of keep-alive messages sent to the VM, e.g. 30s",
+		},
+	)
+
 	return cmd
 }
 
@@ -132,7 +179,7 @@ func bringUp(ctx context.Context, client *api.Client, app *api.AppCompact) (*age
 	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
 	if err != nil {
 		captureError(err, app)
-		return nil, nil, fmt.Errorf("ssh: can't build tunnel for %s: %s\n", app.Organization.Slug, err)
+		return nil, nil, fmt.Errorf("ssh: can't build tunnel for %s: %w\n", app.Organization.Slug, err)
 	}
 
 	if !quiet(ctx) {
@@ -188,12 +235,48 @@ func runConsole(ctx context.Context) error {
 		params.DisableSpinner = true
 	}
 
+	localForwards, remoteForwards, err := parseForwards(ctx)
+	if err != nil {
+		return err
+	}
+
 	sshc, err := sshConnect(params, addr)
 	if err != nil {
 		captureError(err, app)
 		return err
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if interval := flag.GetDuration(ctx, "keep-alive"); interval > 0 {
+		go sshc.KeepAlive(ctx, interval)
+	}
+
+	listeners, err := forwardPorts(ctx, sshc, localForwards, remoteForwards)
+	defer func() {
+		for _, l := range listeners {
+			l.Close()
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	if flag.GetBool(ctx, "no-command") {
+		closed := make(chan error, 1)
+		go func() {
+			closed <- sshc.Client.Wait()
+		}()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-closed:
+			return fmt.Errorf("connection to %s closed: %w", addr, err)
+		}
+	}
+
 	term := &ssh.Terminal{
 		Stdin:  params.Stdin,
 		Stdout: params.Stdout,
@@ -202,6 +285,12 @@ func runConsole(ctx context.Context) error {
 	}
 
 	if err := sshc.Shell(params.Ctx, term, params.Cmd); err != nil {
+		// pass on the exit code of the remote command
+		var exitErr *gossh.ExitError
+		if errors.As(err, &exitErr) {
+			return flyerr.ExitCodeError(exitErr.ExitStatus())
+		}
+
 		captureError(err, app)
 		return errors.Wrap(err, "ssh shell")
 	}
@@ -209,6 +298,64 @@ func runConsole(ctx context.Context) error {
 	return err
 }
 
+// parseForwards parses the ports forwarded with --local-forward and
+// --remote-forward.
+func parseForwards(ctx context.Context) (local, remote []ssh.Forward, err error) {
+	for _, spec := range flag.GetStringSlice(ctx, "local-forward") {
+		f, err := ssh.ParseForward(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		local = append(local, f)
+	}
+
+	for _, spec := range flag.GetStringSlice(ctx, "remote-forward") {
+		f, err := ssh.ParseForward(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		remote = append(remote, f)
+	}
+
+	return
+}
+
+// forwardPorts starts forwarding ports over sshc, returning the listeners to
+// close once done.
+func forwardPorts(ctx context.Context, sshc *ssh.Client, local, remote []ssh.Forward) (listeners []io.Closer, err error) {
+	out := iostreams.FromContext(ctx).ErrOut
+
+	for _, f := range local {
+		l, err := sshc.ForwardLocal(f)
+		if err != nil {
+			return listeners, fmt.Errorf("failed forwarding %s: %w", f, err)
+		}
+
+		listeners = append(listeners, l)
+
+		if !quiet(ctx) {
+			fmt.Fprintf(out, "Forwarding %s on this machine to %s on the VM\n", f.Listen, f.Target)
+		}
+	}
+
+	for _, f := range remote {
+		l, err := sshc.ForwardRemote(f)
+		if err != nil {
+			return listeners, fmt.Errorf("failed forwarding %s: %w", f, err)
+		}
+
+		listeners = append(listeners, l)
+
+		if !quiet(ctx) {
+			fmt.Fprintf(out, "Forwarding %s on the VM to %s on this machine\n", f.Listen, f.Target)
+		}
+	}
+
+	return listeners, nil
+}
+
 func sshConnect(p *SSHParams, addr string) (*ssh.Client, error) {
 	terminal.Debugf("Fetching certificate for %s\n", addr)
 
@@ -257,7 +404,17 @@ func sshConnect(p *SSHParams, addr string) (*ssh.Client, error) {
 }
 
 func addrForMachines(ctx context.Context, app *api.AppCompact, console bool) (addr string, err error) {
-	out := iostreams.FromContext(ctx).Out
+	if addr = flag.GetString(ctx, "address"); addr != "" {
+		return addr, nil
+	}
+
+	if console {
+		if len(flag.Args(ctx)) != 0 {
+			return flag.Args(ctx)[0], nil
+		}
+	}
+
+	io := iostreams.FromContext(ctx)
 	flapsClient, err := flaps.New(ctx, app)
 	if err != nil {
 		return "", err
@@ -272,24 +429,31 @@ func addrForMachines(ctx context.Context, app *api.AppCompact, console bool) (ad
 		return "", fmt.Errorf("app %s has no started or stopped VMs", app.Name)
 	}
 
+	machines, err = filterMachines(ctx, machines)
 	if err != nil {
 		return "", err
 	}
 
-	var namesWithRegion []string
-	var selectedMachine *api.Machine
+	// No VM was selected, so just pick the first one unless there's a choice
+	// to be made interactively. Later, we might want to use 'nearest.of' but
+	// also resolve the machine IP to be able to start it
+	selectedMachine := machines[0]
 
-	for _, machine := range machines {
-		namesWithRegion = append(namesWithRegion, fmt.Sprintf("%s: %s %s %s", machine.Region, machine.ID, machine.PrivateIP, machine.Name))
-	}
+	if flag.GetBool(ctx, "select") || (len(machines) > 1 && io.CanPrompt()) {
+		if !io.CanPrompt() {
+			return "", prompt.NonInteractiveError("select flag can't be used when not running interactively")
+		}
 
-	if flag.GetBool(ctx, "select") {
+		var options []string
+		for _, machine := range machines {
+			options = append(options, fmt.Sprintf("%s: %s %s %s (%s)", machine.Region, machine.ID, machine.PrivateIP, machine.Name, describeMachine(machine)))
+		}
 
 		selected := 0
 
 		prompt := &survey.Select{
 			Message:  "Select VM:",
-			Options:  namesWithRegion,
+			Options:  options,
 			PageSize: 15,
 		}
 
@@ -298,42 +462,112 @@ func addrForMachines(ctx context.Context, app *api.AppCompact, console bool) (ad
 		}
 
 		selectedMachine = machines[selected]
+	}
 
-		if selectedMachine.State != "started" {
-			fmt.Fprintf(out, "Starting machine %s..", selectedMachine.ID)
-			_, err := flapsClient.Start(ctx, selectedMachine.ID)
-			if err != nil {
-				return "", err
-			}
+	if selectedMachine.State != "started" {
+		if !quiet(ctx) {
+			fmt.Fprintf(io.ErrOut, "Starting machine %s..\n", selectedMachine.ID)
+		}
+		_, err := flapsClient.Start(ctx, selectedMachine.ID)
+		if err != nil {
+			return "", err
+		}
 
-			err = flapsClient.Wait(ctx, selectedMachine, "started")
+		err = flapsClient.Wait(ctx, selectedMachine, "started")
 
-			if err != nil {
-				return "", err
-			}
+		if err != nil {
+			return "", err
 		}
 	}
 
-	if addr = flag.GetString(ctx, "address"); addr != "" {
-		return addr, nil
+	return selectedMachine.PrivateIP, nil
+}
+
+// filterMachines narrows machines down to those matching the --group, --region
+// and --role flags.
+func filterMachines(ctx context.Context, machines []*api.Machine) ([]*api.Machine, error) {
+	var (
+		group  = flag.GetString(ctx, "group")
+		region = flag.GetString(ctx, "region")
+		role   = flag.GetString(ctx, "role")
+	)
+
+	if group == "" && region == "" && role == "" {
+		return machines, nil
 	}
 
-	if console {
-		if len(flag.Args(ctx)) != 0 {
-			return flag.Args(ctx)[0], nil
+	var matching []*api.Machine
+	for _, machine := range machines {
+		switch {
+		case group != "" && machineGroup(machine) != group:
+		case region != "" && machine.Region != region:
+		case role != "" && machineRole(machine) != role:
+		default:
+			matching = append(matching, machine)
 		}
 	}
 
-	if selectedMachine == nil {
-		selectedMachine = machines[0]
+	if len(matching) == 0 {
+		var filters []string
+		if group != "" {
+			filters = append(filters, "group "+group)
+		}
+		if region != "" {
+			filters = append(filters, "region "+region)
+		}
+		if role != "" {
+			filters = append(filters, "role "+role)
+		}
+
+		return nil, fmt.Errorf("no VMs found in %s", strings.Join(filters, ", "))
 	}
-	// No VM was selected or passed as an argument, so just pick the first one for now
-	// Later, we might want to use 'nearest.of' but also resolve the machine IP to be able to start it
-	return selectedMachine.PrivateIP, nil
+
+	return matching, nil
+}
+
+func machineGroup(machine *api.Machine) string {
+	if machine.Config == nil {
+		return ""
+	}
+
+	return machine.Config.Metadata[api.MachineProcessGroupKey]
+}
+
+// machineRole returns the output of the role check of Postgres machines, such as
+// leader or replica.
+func machineRole(machine *api.Machine) string {
+	for _, check := range machine.Checks {
+		if check.Name == "role" && check.Status == "passing" {
+			return check.Output
+		}
+	}
+
+	return ""
+}
+
+func describeMachine(machine *api.Machine) string {
+	details := []string{machine.State}
+
+	if group := machineGroup(machine); group != "" {
+		details = append(details, "group "+group)
+	}
+
+	if role := machineRole(machine); role != "" {
+		details = append(details, "role "+role)
+	}
+
+	return strings.Join(details, ", ")
 }
 
 func addrForNomad(ctx context.Context, agentclient *agent.Client, app *api.AppCompact, console bool) (addr string, err error) {
+	if flag.GetString(ctx, "group") != "" || flag.GetString(ctx, "region") != "" || flag.GetString(ctx, "role") != "" {
+		return "", errors.New("--group, --region and --role only apply to apps running on machines")
+	}
+
 	if flag.GetBool(ctx, "select") {
+		if !iostreams.FromContext(ctx).CanPrompt() {
+			return "", prompt.NonInteractiveError("select flag can't be used when not running interactively")
+		}
 
 		instances, err := agentclient.Instances(ctx, app.Organization.Slug, app.Name)
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/ed25519"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -11,9 +12,10 @@ import (
 	"net"
 	"net/mail"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
-	"github.com/AlecAivazis/survey/v2"
 	"github.com/ejcx/sshcert"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh"
@@ -24,6 +26,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/command/orgs"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -31,7 +34,15 @@ func newIssue() *cobra.Command {
 	const (
 		long = `Issue a new SSH credential. With -agent, populate credential
 into SSH agent. With -hour, set the number of hours (1-72) for credential
-validity.`
+validity, or set any validity with --valid-for, which the organization may cap.
+
+The credential can be restricted to the Unix usernames of --username and to
+connections from the addresses of --source-address. --source-address and
+validities other than whole hours need the API to support them, which it
+doesn't everywhere yet.
+
+Credentials can't be revoked before they expire, as there's no revocation
+list machines check them against, so keep their validity short.`
 		short = `Issue a new SSH credential`
 		usage = "issue [org] [email] [path]"
 	)
@@ -42,16 +53,24 @@ validity.`
 
 	flag.Add(cmd,
 		flag.Org(),
-		flag.String{
+		flag.StringSlice{
 			Name:        "username",
 			Shorthand:   "u",
-			Description: "Unix username for SSH cert",
+			Description: "Unix usernames for SSH cert, comma separated or repeated",
 		},
 		flag.Int{
 			Name:        "hours",
 			Default:     24,
 			Description: "Expiration, in hours (<72)",
 		},
+		flag.Duration{
+			Name:        "valid-for",
+			Description: "Expiration, as a duration such as 15m or 168h, instead of --hours",
+		},
+		flag.StringSlice{
+			Name:        "source-address",
+			Description: "Addresses or CIDR ranges the SSH cert may be used from, comma separated or repeated",
+		},
 
 		flag.Bool{
 			Name:        "agent",
@@ -103,22 +122,69 @@ func runSSHIssue(ctx context.Context) (err error) {
 		}
 	}
 
-	var username *string
+	input := api.IssueCertificateInput{
+		OrganizationID:  org.ID,
+		Email:           email.Address,
+		SourceAddresses: flag.GetStringSlice(ctx, "source-address"),
+	}
+
+	switch usernames := flag.GetStringSlice(ctx, "username"); len(usernames) {
+	case 0:
+	case 1:
+		input.Username = &usernames[0]
+	default:
+		principals := strings.Join(usernames, ",")
+		input.Principals = &principals
+	}
+
+	for _, addr := range input.SourceAddresses {
+		if net.ParseIP(addr) == nil {
+			if _, _, err := net.ParseCIDR(addr); err != nil {
+				return fmt.Errorf("invalid source address %q, must be an IP address or CIDR range", addr)
+			}
+		}
+	}
 
-	if vals := flag.GetString(ctx, "username"); vals != "" {
-		username = &vals
+	if len(input.SourceAddresses) > 0 {
+		if err := command.CheckAPI(ctx, "IssueCertificateInput", "sourceAddresses"); err != nil {
+			return fmt.Errorf("--source-address isn't available: %w", err)
+		}
 	}
 
 	hours := flag.GetInt(ctx, "hours")
-	if hours < 1 || hours > 72 {
+	validFor := flag.GetDuration(ctx, "valid-for")
+
+	switch {
+	case validFor != 0 && validFor%time.Hour == 0 && validFor <= 72*time.Hour:
+		validHours := int(validFor / time.Hour)
+		input.ValidHours = &validHours
+	case validFor != 0:
+		if validFor < time.Minute || validFor%time.Minute != 0 {
+			return fmt.Errorf("invalid expiration time %s, must be a whole number of minutes", validFor)
+		}
+
+		if err := command.CheckAPI(ctx, "IssueCertificateInput", "validMinutes"); err != nil {
+			return fmt.Errorf("--valid-for %s isn't available, use whole hours up to 72h: %w", validFor, err)
+		}
+
+		minutes := int(validFor / time.Minute)
+		input.ValidMinutes = &minutes
+	case hours < 1 || hours > 72:
 		return fmt.Errorf("Invalid expiration time (1-72 hours)\n")
+	default:
+		input.ValidHours = &hours
+		validFor = time.Duration(hours) * time.Hour
 	}
 
-	icert, err := client.IssueSSHCertificate(ctx, org, email.Address, username, &hours)
+	icert, err := client.IssueRestrictedSSHCertificate(ctx, input)
 	if err != nil {
 		return err
 	}
 
+	if err := checkRestrictions(icert, input, validFor); err != nil {
+		return err
+	}
+
 	doAgent := flag.GetBool(ctx, "agent")
 	if doAgent {
 		if err = populateAgent(icert); err != nil {
@@ -192,20 +258,70 @@ func runSSHIssue(ctx context.Context) (err error) {
 	pf.Write(buf)
 	pf.Close()
 
-	fmt.Printf("Wrote %d-hour SSH credential to %s, %s-cert.pub\n", hours, rootname, rootname)
+	fmt.Printf("Wrote %s SSH credential to %s, %s-cert.pub\n", validFor, rootname, rootname)
 
 	return nil
 }
 
-func argOrPromptImpl(ctx context.Context, nth int, prompt string, first bool) (string, error) {
+// checkRestrictions makes sure the certificate issued is restricted as input
+// requested, rather than trusting the API applied every restriction.
+func checkRestrictions(icert *api.IssuedCertificate, input api.IssueCertificateInput, validFor time.Duration) error {
+	key, err := sshcert.ParsePublicKey(icert.Certificate)
+	if err != nil {
+		return fmt.Errorf("API error: can't parse API-provided SSH certificate: %w", err)
+	}
+
+	cert, ok := key.(*ssh.Certificate)
+	if !ok {
+		return errors.New("API error: the API didn't provide an SSH certificate")
+	}
+
+	var principals []string
+	switch {
+	case input.Username != nil:
+		principals = []string{*input.Username}
+	case input.Principals != nil:
+		principals = strings.Split(*input.Principals, ",")
+	}
+
+	if len(principals) > 0 {
+		want := append([]string(nil), principals...)
+		got := append([]string(nil), cert.ValidPrincipals...)
+		sort.Strings(want)
+		sort.Strings(got)
+
+		if strings.Join(want, ",") != strings.Join(got, ",") {
+			return fmt.Errorf("the certificate issued is valid for usernames %s rather than %s",
+				strings.Join(got, ", "), strings.Join(want, ", "))
+		}
+	}
+
+	if len(input.SourceAddresses) > 0 {
+		want := strings.Join(input.SourceAddresses, ",")
+		if got := cert.CriticalOptions["source-address"]; got != want {
+			return fmt.Errorf("the certificate issued isn't restricted to source addresses %s", want)
+		}
+	}
+
+	// allow for the clocks of flyctl and the API differing
+	const skew = 5 * time.Minute
+
+	validBefore := time.Unix(int64(cert.ValidBefore), 0)
+	if cert.ValidBefore != ssh.CertTimeInfinity && validBefore.Before(time.Now().Add(validFor+skew)) {
+		return nil
+	}
+
+	return fmt.Errorf("the certificate issued is valid until %s, past the %s requested",
+		validBefore.Format(time.RFC3339), validFor)
+}
+
+func argOrPromptImpl(ctx context.Context, nth int, msg string, first bool) (string, error) {
 	if len(flag.Args(ctx)) >= (nth + 1) {
 		return flag.Args(ctx)[nth], nil
 	}
 
 	val := ""
-	err := survey.AskOne(&survey.Input{
-		Message: prompt,
-	}, &val)
+	err := prompt.String(ctx, &val, msg, "", false)
 
 	return val, err
 }
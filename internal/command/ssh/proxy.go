@@ -0,0 +1,78 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newProxy() *cobra.Command {
+	const (
+		long = `Connect stdin and stdout to a port of a VM over the WireGuard tunnel of the
+organization. It's the ProxyCommand of the entries written by ssh config.`
+		short = "Connect stdin and stdout to a port of a VM"
+		usage = "proxy <address> <port>"
+	)
+
+	cmd := command.New(usage, short, long, runProxy, command.RequireSession)
+
+	cmd.Args = cobra.ExactArgs(2)
+	cmd.Hidden = true
+
+	flag.Add(cmd,
+		flag.Org(),
+	)
+
+	return cmd
+}
+
+func runProxy(ctx context.Context) error {
+	var (
+		streams = iostreams.FromContext(ctx)
+		args    = flag.Args(ctx)
+		org     = flag.GetOrg(ctx)
+	)
+
+	if org == "" {
+		return fmt.Errorf("--org is required")
+	}
+
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return fmt.Errorf("can't establish agent: %w", err)
+	}
+
+	dialer, err := agentclient.Dialer(ctx, org)
+	if err != nil {
+		return fmt.Errorf("can't build tunnel for %s: %w", org, err)
+	}
+
+	if err := agentclient.WaitForTunnel(ctx, org); err != nil {
+		return fmt.Errorf("tunnel unavailable: %w", err)
+	}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(args[0], args[1]))
+	if err != nil {
+		return fmt.Errorf("failed connecting to %s: %w", args[0], err)
+	}
+	defer conn.Close()
+
+	// ssh closes its end once the session is over, so the copy from the VM
+	// is what ends the proxy
+	go func() {
+		_, _ = io.Copy(conn, streams.In)
+	}()
+
+	_, err = io.Copy(streams.Out, conn)
+
+	return err
+}
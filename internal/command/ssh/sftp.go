@@ -18,6 +18,7 @@ import (
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/chzyer/readline"
 	"github.com/google/shlex"
@@ -36,6 +37,7 @@ func NewSFTP() *cobra.Command {
 		newFind(),
 		newSFTPShell(),
 		newGet(),
+		newPut(),
 	)
 
 	return cmd
@@ -71,19 +73,84 @@ func newFind() *cobra.Command {
 
 func newGet() *cobra.Command {
 	const (
-		long  = `The SFTP GET retrieves a file from a remote VM.`
-		short = long
-		usage = "get <path>"
+		long = `The SFTP GET retrieves files from a remote VM. The remote path may be a glob
+pattern, in which case the local path must be a directory. Directories are
+copied with --recursive, and transfers that were interrupted are completed
+with --resume.`
+		short = `The SFTP GET retrieves files from a remote VM.`
+		usage = "get <path> [local-path]"
 	)
 
 	cmd := command.New(usage, short, long, runGet, command.RequireSession, command.LoadAppNameIfPresent)
 
-	cmd.Args = cobra.MaximumNArgs(2)
+	cmd.Args = cobra.RangeArgs(1, 2)
+
+	stdArgsSSH(cmd)
+	transferFlags(cmd)
+
+	return cmd
+}
+
+func newPut() *cobra.Command {
+	const (
+		long = `The SFTP PUT uploads files to a remote VM. The local path may be a glob
+pattern, in which case the remote path must be a directory. Directories are
+copied with --recursive, and transfers that were interrupted are completed
+with --resume.`
+		short = `The SFTP PUT uploads files to a remote VM.`
+		usage = "put <local-path> [path]"
+	)
+
+	cmd := command.New(usage, short, long, runPut, command.RequireSession, command.LoadAppNameIfPresent)
+
+	cmd.Args = cobra.RangeArgs(1, 2)
 
 	stdArgsSSH(cmd)
+	transferFlags(cmd)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "mode",
+			Shorthand:   "m",
+			Default:     "0644",
+			Description: "Numeric permissions of the files created",
+		},
+	)
 
 	return cmd
+}
 
+func transferFlags(cmd *cobra.Command) {
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "recursive",
+			Shorthand:   "R",
+			Description: "Copy directories and their contents",
+		},
+		flag.Bool{
+			Name:        "resume",
+			Description: "Resume interrupted transfers of files that already exist at the destination",
+		},
+	)
+}
+
+// newTransfer returns a transfer over ftp that reports to the streams of ctx.
+func newTransfer(ctx context.Context, ftp *sftp.Client, resume bool) *transfer {
+	io := iostreams.FromContext(ctx)
+
+	t := &transfer{
+		ftp:    ftp,
+		resume: resume,
+		out: func(format string, args ...interface{}) {
+			fmt.Fprintf(io.Out, format+"\n", args...)
+		},
+	}
+
+	if io.IsStderrTTY() {
+		t.progress = io.ErrOut
+	}
+
+	return t
 }
 
 func newSFTPConnection(ctx context.Context) (*sftp.Client, error) {
@@ -155,50 +222,44 @@ func runLs(ctx context.Context) error {
 func runGet(ctx context.Context) error {
 	args := flag.Args(ctx)
 
-	var remote, local string
-
-	switch len(args) {
-	case 0:
-		fmt.Printf("get <remote-path> [local-path]\n")
-		return nil
-
-	case 1:
-		remote = args[0]
-		local = remote
-
-	default:
-		remote = args[0]
+	var local string
+	if len(args) > 1 {
 		local = args[1]
 	}
 
-	if _, err := os.Stat(local); err == nil {
-		return fmt.Errorf("get: local file %s: already exists", remote)
-	}
-
 	ftp, err := newSFTPConnection(ctx)
 	if err != nil {
 		return err
 	}
+	defer ftp.Close()
 
-	rf, err := ftp.Open(remote)
-	if err != nil {
-		return fmt.Errorf("get: remote file %s: %w", remote, err)
+	t := newTransfer(ctx, ftp, flag.GetBool(ctx, "resume"))
+
+	return t.get(args[0], local, flag.GetBool(ctx, "recursive"))
+}
+
+func runPut(ctx context.Context) error {
+	args := flag.Args(ctx)
+
+	var remote string
+	if len(args) > 1 {
+		remote = args[1]
 	}
-	defer rf.Close()
 
-	f, err := os.OpenFile(local, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	perm, err := strconv.ParseUint(flag.GetString(ctx, "mode"), 8, 32)
 	if err != nil {
-		return fmt.Errorf("get: local file %s: %w", local, err)
+		return fmt.Errorf("invalid mode (only numeric allowed) %q: %w", flag.GetString(ctx, "mode"), err)
 	}
-	defer f.Close()
 
-	bytes, err := rf.WriteTo(f)
+	ftp, err := newSFTPConnection(ctx)
 	if err != nil {
-		return fmt.Errorf("get: copy file: %w (%d bytes written)", err, bytes)
+		return err
 	}
+	defer ftp.Close()
 
-	fmt.Printf("%d bytes written to %s\n", bytes, local)
-	return nil
+	t := newTransfer(ctx, ftp, flag.GetBool(ctx, "resume"))
+
+	return t.put(args[0], remote, flag.GetBool(ctx, "recursive"), fs.FileMode(perm).Perm())
 }
 
 var completer = readline.NewPrefixCompleter(
@@ -210,9 +271,28 @@ var completer = readline.NewPrefixCompleter(
 )
 
 type sftpContext struct {
-	ftp *sftp.Client
-	wd  string
-	out func(string, ...interface{})
+	ftp      *sftp.Client
+	wd       string
+	out      func(string, ...interface{})
+	progress io.Writer
+}
+
+// resolve returns the remote path of arg relative to the working directory.
+func (sc *sftpContext) resolve(arg string) string {
+	if arg[0] == '/' {
+		return arg
+	}
+
+	return sc.wd + arg
+}
+
+func (sc *sftpContext) transfer(resume bool) *transfer {
+	return &transfer{
+		ftp:      sc.ftp,
+		out:      sc.out,
+		resume:   resume,
+		progress: sc.progress,
+	}
 }
 
 func (sc *sftpContext) cd(args ...string) error {
@@ -297,15 +377,13 @@ func (sc *sftpContext) ls(args ...string) error {
 	return nil
 }
 
-func (sc *sftpContext) getDir(rpath string, args []string) {
-	lpath := path.Base(rpath) + ".zip"
-
-	if len(args) > 2 {
-		lpath = args[2]
+func (sc *sftpContext) getDir(rpath, lpath string) {
+	if lpath == "" {
+		lpath = path.Base(rpath)
+	}
 
-		if !strings.HasSuffix(lpath, ".zip") {
-			lpath += ".zip"
-		}
+	if !strings.HasSuffix(lpath, ".zip") {
+		lpath += ".zip"
 	}
 
 	if _, err := os.Stat(lpath); err == nil {
@@ -395,125 +473,63 @@ func (sc *sftpContext) chmod(args ...string) error {
 }
 
 func (sc *sftpContext) put(args ...string) error {
+	const usage = "put [-r] [-c] [-m mode] <local-filename> [filename]"
+
 	fgs := goflag.NewFlagSet("put", goflag.ContinueOnError)
 
 	perm := fgs.String("m", "0644", "file mode")
+	recursive := fgs.Bool("r", false, "copy directories recursively")
+	resume := fgs.Bool("c", false, "resume interrupted transfers")
 
-	permbits, err := strconv.ParseInt(*perm, 8, 16)
-	if err != nil {
-		sc.out("put: invalid permissions (only numeric allowed) '%s': %s", *perm, err)
+	if err := fgs.Parse(args[1:]); err != nil || fgs.NArg() == 0 {
+		sc.out(usage)
 		return nil
 	}
 
-	if err := fgs.Parse(args[1:]); err != nil {
-		sc.out("put [-m] <local-filename> [filename]")
-		return nil
-	}
-
-	lpath := fgs.Arg(0)
-	if lpath == "" {
-		sc.out("put [-m] <local-filename> [filename]")
-		return nil
-	}
-
-	rpath := sc.wd + path.Base(lpath)
-	if rarg := fgs.Arg(1); rarg != "" {
-		if rarg[0] == '/' {
-			rpath = rarg
-		} else {
-			rpath = sc.wd + rarg
-		}
-	}
-
-	if _, err = sc.ftp.Stat(rpath); err == nil {
-		sc.out("put %s -> %s: file exists on VM", lpath, rpath)
-		return nil
-	}
-
-	f, err := os.Open(lpath)
+	permbits, err := strconv.ParseUint(*perm, 8, 32)
 	if err != nil {
-		sc.out("put %s -> %s: open local file: %s", lpath, rpath, err)
-		return nil
-	}
-	defer f.Close()
-
-	rf, err := sc.ftp.OpenFile(rpath, os.O_WRONLY|os.O_CREATE|os.O_EXCL)
-	if err != nil {
-		sc.out("put %s -> %s: create remote file: %s", lpath, rpath, err)
+		sc.out("put: invalid permissions (only numeric allowed) '%s': %s", *perm, err)
 		return nil
 	}
-	defer rf.Close()
 
-	bytes, err := rf.ReadFrom(f)
-	if err != nil {
-		sc.out("put %s -> %s: copy file file: %s (%d bytes written)", lpath, rpath, err, bytes)
-		return nil
+	rpath := sc.wd
+	if rarg := fgs.Arg(1); rarg != "" {
+		rpath = sc.resolve(rarg)
 	}
 
-	sc.out("%d bytes written", bytes)
-
-	if err = sc.ftp.Chmod(rpath, fs.FileMode(permbits)); err != nil {
-		sc.out("put %s -> %s: set permissions: %s", lpath, rpath, err)
-		return nil
+	if err := sc.transfer(*resume).put(fgs.Arg(0), rpath, *recursive, fs.FileMode(permbits).Perm()); err != nil {
+		sc.out("%s", err)
 	}
 
 	return nil
 }
 
 func (sc *sftpContext) get(args ...string) error {
-	if len(args) < 2 {
-		sc.out("get <filename> [local-filename]")
-		return nil
-	}
+	const usage = "get [-r] [-c] <filename> [local-filename]"
 
-	rpath := sc.wd + args[1]
-
-	inf, err := sc.ftp.Stat(rpath)
-	if err != nil {
-		sc.out("get %s: %s", rpath, err)
-		return nil
-	}
-
-	if inf.IsDir() {
-		sc.getDir(rpath, args)
-		return nil
-	}
+	fgs := goflag.NewFlagSet("get", goflag.ContinueOnError)
 
-	localFile := path.Base(rpath)
-	if len(args) > 2 {
-		localFile = args[2]
-	}
+	recursive := fgs.Bool("r", false, "copy directories recursively")
+	resume := fgs.Bool("c", false, "resume interrupted transfers")
 
-	_, err = os.Stat(localFile)
-	if err == nil {
-		sc.out("get %s -> %s: file exists", rpath, localFile)
+	if err := fgs.Parse(args[1:]); err != nil || fgs.NArg() == 0 {
+		sc.out(usage)
 		return nil
 	}
 
-	func() {
-		rf, err := sc.ftp.Open(rpath)
-		if err != nil {
-			sc.out("get %s -> %s: %s", err)
-			return
-		}
-		defer rf.Close()
+	rpath := sc.resolve(fgs.Arg(0))
 
-		f, err := os.OpenFile(localFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
-		if err != nil {
-			sc.out("get %s -> %s: %s", rpath, localFile, err)
-			return
+	// without -r, directories are fetched as a zip file
+	if !*recursive && !hasGlob(rpath) {
+		if inf, err := sc.ftp.Stat(rpath); err == nil && inf.IsDir() {
+			sc.getDir(rpath, fgs.Arg(1))
+			return nil
 		}
-		defer f.Close()
-
-		sc.out("get %s -> %s", rpath, localFile)
+	}
 
-		bytes, err := rf.WriteTo(f)
-		if err != nil {
-			sc.out("get %s -> %s: %s (wrote %d bytes)", rpath, localFile, err, bytes)
-		} else {
-			sc.out("wrote %d bytes", bytes)
-		}
-	}()
+	if err := sc.transfer(*resume).get(rpath, fgs.Arg(1), *recursive); err != nil {
+		sc.out("%s", err)
+	}
 
 	return nil
 }
@@ -550,6 +566,10 @@ func runShell(ctx context.Context) error {
 		ftp: ftp,
 	}
 
+	if io := iostreams.FromContext(ctx); io.IsStderrTTY() {
+		sc.progress = io.ErrOut
+	}
+
 	for {
 		line, err := l.Readline()
 		if err == readline.ErrInterrupt {
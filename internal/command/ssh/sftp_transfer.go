@@ -0,0 +1,465 @@
+package ssh
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/pkg/sftp"
+)
+
+var errNoMatches = errors.New("no files match")
+
+// transfer copies files and directory trees between the local machine and a
+// VM. With resume, files partially copied by an interrupted transfer are
+// completed rather than refused. Progress bars are drawn to progress, when set.
+type transfer struct {
+	ftp      *sftp.Client
+	out      func(string, ...interface{})
+	resume   bool
+	progress io.Writer
+}
+
+// get copies the remote files matching pattern to local, which must be a
+// directory when several files match. Directories are only copied with
+// recursive.
+func (t *transfer) get(pattern, local string, recursive bool) error {
+	matches := []string{pattern}
+	if hasGlob(pattern) {
+		var err error
+		if matches, err = t.ftp.Glob(pattern); err != nil {
+			return fmt.Errorf("get %s: %w", pattern, err)
+		} else if len(matches) == 0 {
+			return fmt.Errorf("get %s: %w", pattern, errNoMatches)
+		}
+	}
+
+	many := len(matches) > 1
+	if many {
+		if local == "" {
+			local = "."
+		}
+
+		if info, err := os.Stat(local); err != nil || !info.IsDir() {
+			return fmt.Errorf("get %s: %s is not a directory", pattern, local)
+		}
+	}
+
+	for _, rpath := range matches {
+		info, err := t.ftp.Stat(rpath)
+		if err != nil {
+			return fmt.Errorf("get %s: %w", rpath, err)
+		}
+
+		lpath := path.Base(rpath)
+		if local != "" {
+			if dir, err := os.Stat(local); err == nil && dir.IsDir() {
+				lpath = filepath.Join(local, lpath)
+			} else {
+				lpath = local
+			}
+		}
+
+		switch {
+		case !info.IsDir():
+			err = t.download(rpath, lpath)
+		case recursive:
+			err = t.downloadTree(rpath, lpath)
+		default:
+			err = fmt.Errorf("get %s: is a directory; use -r to copy it", rpath)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// put copies the local files matching pattern to remote, which must be a
+// directory when several files match. Files are created with perm, and
+// directories are only copied with recursive.
+func (t *transfer) put(pattern, remote string, recursive bool, perm fs.FileMode) error {
+	matches := []string{pattern}
+	if hasGlob(pattern) {
+		var err error
+		if matches, err = filepath.Glob(pattern); err != nil {
+			return fmt.Errorf("put %s: %w", pattern, err)
+		} else if len(matches) == 0 {
+			return fmt.Errorf("put %s: %w", pattern, errNoMatches)
+		}
+	}
+
+	many := len(matches) > 1
+	if many && remote == "" {
+		remote = "."
+	}
+
+	if many && !t.isDir(remote) {
+		return fmt.Errorf("put %s: %s is not a directory on the VM", pattern, remote)
+	}
+
+	for _, lpath := range matches {
+		info, err := os.Stat(lpath)
+		if err != nil {
+			return fmt.Errorf("put %s: %w", lpath, err)
+		}
+
+		rpath := filepath.Base(lpath)
+		if remote != "" {
+			if t.isDir(remote) {
+				rpath = path.Join(remote, rpath)
+			} else {
+				rpath = remote
+			}
+		}
+
+		switch {
+		case !info.IsDir():
+			err = t.upload(lpath, rpath, perm)
+		case recursive:
+			err = t.uploadTree(lpath, rpath)
+		default:
+			err = fmt.Errorf("put %s: is a directory; use -r to copy it", lpath)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// download copies the remote file at rpath to lpath.
+func (t *transfer) download(rpath, lpath string) error {
+	rf, err := t.ftp.Open(rpath)
+	if err != nil {
+		return fmt.Errorf("get %s: %w", rpath, err)
+	}
+	defer rf.Close()
+
+	info, err := rf.Stat()
+	if err != nil {
+		return fmt.Errorf("get %s: %w", rpath, err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+
+	var offset int64
+	if local, err := os.Stat(lpath); err == nil {
+		lf, err := os.Open(lpath)
+		if err != nil {
+			return fmt.Errorf("get %s -> %s: %w", rpath, lpath, err)
+		}
+		offset, err = t.resumeAt(lpath, rf, lf, local.Size(), info.Size())
+		lf.Close()
+		if err != nil || offset == info.Size() {
+			return err
+		}
+
+		flags = os.O_WRONLY | os.O_TRUNC
+		if offset > 0 {
+			flags = os.O_WRONLY | os.O_APPEND
+		}
+	}
+
+	f, err := os.OpenFile(lpath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("get %s -> %s: %w", rpath, lpath, err)
+	}
+	defer f.Close()
+
+	if _, err := rf.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("get %s: %w", rpath, err)
+	}
+
+	p := t.newProgress(lpath, offset, info.Size())
+
+	n, err := rf.WriteTo(&progressWriter{w: f, p: p})
+	p.done()
+	if err != nil {
+		return fmt.Errorf("get %s -> %s: %w (wrote %d bytes)", rpath, lpath, err, n)
+	}
+
+	t.out("%s -> %s (%s)", rpath, lpath, humanize.Bytes(uint64(offset+n)))
+
+	return nil
+}
+
+// upload copies the local file at lpath to rpath, giving new files perm.
+func (t *transfer) upload(lpath, rpath string, perm fs.FileMode) error {
+	f, err := os.Open(lpath)
+	if err != nil {
+		return fmt.Errorf("put %s: %w", lpath, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("put %s: %w", lpath, err)
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+
+	var offset int64
+	if remote, err := t.ftp.Stat(rpath); err == nil {
+		prf, err := t.ftp.Open(rpath)
+		if err != nil {
+			return fmt.Errorf("put %s -> %s: %w", lpath, rpath, err)
+		}
+		offset, err = t.resumeAt(rpath, f, prf, remote.Size(), info.Size())
+		prf.Close()
+		if err != nil || offset == info.Size() {
+			return err
+		}
+
+		flags = os.O_WRONLY
+		if offset == 0 {
+			flags |= os.O_TRUNC
+		}
+	}
+
+	rf, err := t.ftp.OpenFile(rpath, flags)
+	if err != nil {
+		return fmt.Errorf("put %s -> %s: %w", lpath, rpath, err)
+	}
+	defer rf.Close()
+
+	if _, err := rf.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("put %s -> %s: %w", lpath, rpath, err)
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("put %s: %w", lpath, err)
+	}
+
+	p := t.newProgress(rpath, offset, info.Size())
+
+	n, err := rf.ReadFrom(&progressReader{r: f, p: p, remain: info.Size() - offset})
+	p.done()
+	if err != nil {
+		return fmt.Errorf("put %s -> %s: %w (wrote %d bytes)", lpath, rpath, err, n)
+	}
+
+	if flags&os.O_CREATE != 0 {
+		if err := t.ftp.Chmod(rpath, perm); err != nil {
+			return fmt.Errorf("put %s -> %s: set permissions: %w", lpath, rpath, err)
+		}
+	}
+
+	t.out("%s -> %s (%s)", lpath, rpath, humanize.Bytes(uint64(offset+n)))
+
+	return nil
+}
+
+// resumeAt returns the offset to resume copying src, a file of size total,
+// to dst, which already has size bytes, from. The last bytes dst has must match
+// those of src; when they don't, dst holds another file, and the copy
+// restarts from 0.
+func (t *transfer) resumeAt(dst string, src, partial io.ReaderAt, size, total int64) (int64, error) {
+	switch {
+	case !t.resume:
+		return 0, fmt.Errorf("%s: file exists, and resuming wasn't requested", dst)
+	case size > total:
+		return 0, fmt.Errorf("%s: larger than the file being copied, so it can't be resumed", dst)
+	}
+
+	switch same, err := sameTail(src, partial, size); {
+	case err != nil:
+		return 0, fmt.Errorf("%s: failed comparing with the file being copied: %w", dst, err)
+	case !same:
+		t.out("%s: differs from the file being copied, copying it again", dst)
+
+		return 0, nil
+	case size == total:
+		t.out("%s: already complete", dst)
+	}
+
+	return size, nil
+}
+
+// resumeCheckSize is how many of the last bytes of partially copied files
+// are compared with those of the file being copied before resuming.
+const resumeCheckSize = 1 << 20
+
+// sameTail reports whether the resumeCheckSize bytes before offset size of a
+// and b are the same.
+func sameTail(a, b io.ReaderAt, size int64) (bool, error) {
+	n := int64(resumeCheckSize)
+	if size < n {
+		n = size
+	}
+
+	bufA, bufB := make([]byte, n), make([]byte, n)
+	if _, err := a.ReadAt(bufA, size-n); err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+	if _, err := b.ReadAt(bufB, size-n); err != nil && !errors.Is(err, io.EOF) {
+		return false, err
+	}
+
+	return bytes.Equal(bufA, bufB), nil
+}
+
+// downloadTree copies the remote directory tree at rdir into ldir.
+func (t *transfer) downloadTree(rdir, ldir string) error {
+	walker := t.ftp.Walk(rdir)
+
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return fmt.Errorf("get %s: %w", walker.Path(), err)
+		}
+
+		rel := strings.TrimPrefix(strings.TrimPrefix(walker.Path(), rdir), "/")
+		lpath := filepath.Join(ldir, filepath.FromSlash(rel))
+
+		if walker.Stat().IsDir() {
+			if err := os.MkdirAll(lpath, 0o755); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := t.download(walker.Path(), lpath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// uploadTree copies the local directory tree at ldir into rdir.
+func (t *transfer) uploadTree(ldir, rdir string) error {
+	return filepath.WalkDir(ldir, func(lpath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(ldir, lpath)
+		if err != nil {
+			return err
+		}
+		rpath := path.Join(rdir, filepath.ToSlash(rel))
+
+		switch info, err := d.Info(); {
+		case err != nil:
+			return err
+		case d.IsDir():
+			if err := t.ftp.MkdirAll(rpath); err != nil {
+				return fmt.Errorf("put %s: %w", rpath, err)
+			}
+
+			return nil
+		case !info.Mode().IsRegular():
+			t.out("%s: skipped, not a regular file", lpath)
+
+			return nil
+		default:
+			return t.upload(lpath, rpath, info.Mode().Perm())
+		}
+	})
+}
+
+// isDir reports whether the remote path is a directory.
+func (t *transfer) isDir(rpath string) bool {
+	info, err := t.ftp.Stat(rpath)
+	return err == nil && info.IsDir()
+}
+
+// hasGlob reports whether pattern contains any of the characters of glob
+// patterns.
+func hasGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[`)
+}
+
+// progress draws the progress bar of a file, redrawing it at most every
+// 200ms.
+type progress struct {
+	w       io.Writer
+	name    string
+	total   int64
+	written int64
+	drawn   time.Time
+}
+
+func (t *transfer) newProgress(name string, offset, total int64) *progress {
+	return &progress{
+		w:       t.progress,
+		name:    name,
+		written: offset,
+		total:   total,
+	}
+}
+
+func (p *progress) add(n int) {
+	p.written += int64(n)
+
+	if p.w != nil && time.Since(p.drawn) > 200*time.Millisecond {
+		p.draw()
+	}
+}
+
+func (p *progress) draw() {
+	const width = 30
+
+	// files may grow while they're copied
+	filled := width
+	if p.total > 0 && p.written < p.total {
+		filled = int(p.written * width / p.total)
+	}
+
+	fmt.Fprintf(p.w, "\r%s [%s%s] %s/%s", p.name,
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled),
+		humanize.Bytes(uint64(p.written)), humanize.Bytes(uint64(p.total)))
+
+	p.drawn = time.Now()
+}
+
+func (p *progress) done() {
+	if p.w != nil && !p.drawn.IsZero() {
+		p.draw()
+		fmt.Fprintln(p.w)
+	}
+}
+
+type progressWriter struct {
+	w io.Writer
+	p *progress
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.p.add(n)
+
+	return n, err
+}
+
+// progressReader reports its remaining length, so that sftp uploads it with
+// concurrent writes.
+type progressReader struct {
+	r      io.Reader
+	p      *progress
+	remain int64
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.p.add(n)
+	pr.remain -= int64(n)
+
+	return n, err
+}
+
+func (pr *progressReader) Len() int {
+	return int(pr.remain)
+}
@@ -16,8 +16,10 @@ func New() *cobra.Command {
 
 	cmd.AddCommand(
 		newConsole(),
+		newConfig(),
 		newIssue(),
 		newLog(),
+		newProxy(),
 		NewSFTP(),
 	)
 
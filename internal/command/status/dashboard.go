@@ -0,0 +1,268 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/promql"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// dashboardEvents is how many of the latest machine events the dashboard
+// shows.
+const dashboardEvents = 8
+
+// dashboardSpan is how far back the metrics of the dashboard go, which the
+// header of their table spells out.
+const dashboardSpan = 30 * time.Minute
+
+// dashboardMetrics are the metrics the dashboard charts, as PromQL queries of
+// the app and formatters for their values.
+var dashboardMetrics = []struct {
+	name   string
+	query  string
+	format func(float64) string
+}{
+	{
+		name:  "Requests",
+		query: `sum(rate(fly_app_http_responses_count{app=%[1]q}[1m]))`,
+		format: func(v float64) string {
+			return fmt.Sprintf("%.1f/s", v)
+		},
+	},
+	{
+		name:  "5xx responses",
+		query: `sum(rate(fly_app_http_responses_count{app=%[1]q,status=~"5.."}[1m]))`,
+		format: func(v float64) string {
+			return fmt.Sprintf("%.2f/s", v)
+		},
+	},
+	{
+		name:  "CPU",
+		query: promql.CPU(`app=%[1]q`),
+		format: func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v)
+		},
+	},
+	{
+		name:  "Memory",
+		query: promql.Memory(`app=%[1]q`),
+		format: func(v float64) string {
+			return humanize.IBytes(uint64(v))
+		},
+	},
+}
+
+// dashboard renders the overview of a machines app that status --watch
+// refreshes: its current release, machines, failing checks, recent machine
+// events and key metrics.
+type dashboard struct {
+	app    *api.AppCompact
+	client *api.Client
+	flaps  *flaps.Client
+
+	// states are the states of the machines as of the previous refresh, so
+	// that changes can be highlighted like kubectl get pods -w does.
+	states map[string]string
+}
+
+func newDashboard(ctx context.Context, client *api.Client, app *api.AppCompact) (*dashboard, error) {
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dashboard{
+		app:    app,
+		client: client,
+		flaps:  flapsClient,
+	}, nil
+}
+
+func (d *dashboard) render(ctx context.Context, w io.Writer) error {
+	colorize := iostreams.FromContext(ctx).ColorScheme()
+
+	machines, err := d.flaps.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed listing machines: %w", err)
+	}
+
+	sort.Slice(machines, func(i, j int) bool {
+		return machines[i].ID < machines[j].ID
+	})
+
+	release := "none"
+	if releases, err := d.client.GetAppReleases(ctx, d.app.Name, 1); err != nil {
+		release = colorize.Red("unavailable: " + err.Error())
+	} else if len(releases) > 0 {
+		r := releases[0]
+		release = fmt.Sprintf("v%d %s, %s by %s", r.Version, r.Status, humanize.Time(r.CreatedAt), r.User.Email)
+	}
+
+	fmt.Fprintf(w, "%s %s (%s)\n", colorize.Bold("App:"), d.app.Name, d.app.Organization.Slug)
+	fmt.Fprintf(w, "%s %s\n\n", colorize.Bold("Release:"), release)
+
+	states := make(map[string]string, len(machines))
+
+	rows := make([][]string, 0, len(machines))
+	for _, machine := range machines {
+		states[machine.ID] = machine.State
+
+		state := machine.State
+		if prev, ok := d.states[machine.ID]; d.states != nil && (!ok || prev != machine.State) {
+			if !ok {
+				prev = "new"
+			}
+			state = colorize.Yellow(fmt.Sprintf("%s (was %s)", machine.State, prev))
+		}
+
+		rows = append(rows, []string{
+			machine.ID,
+			state,
+			machine.Region,
+			render.MachineHealthChecksSummary(machine),
+			machine.ImageRefWithVersion(),
+			machine.UpdatedAt,
+		})
+	}
+
+	var gone []string
+	for id := range d.states {
+		if _, ok := states[id]; !ok {
+			gone = append(gone, id)
+		}
+	}
+	sort.Strings(gone)
+
+	for _, id := range gone {
+		rows = append(rows, []string{id, colorize.Yellow("gone (was " + d.states[id] + ")"), "", "", "", ""})
+	}
+
+	d.states = states
+
+	if err := render.Table(w, "Machines", rows, "ID", "State", "Region", "Health checks", "Image", "Updated"); err != nil {
+		return err
+	}
+
+	if err := renderFailingChecks(w, machines); err != nil {
+		return err
+	}
+
+	if err := renderRecentEvents(w, machines); err != nil {
+		return err
+	}
+
+	return d.renderMetrics(ctx, w)
+}
+
+func renderFailingChecks(w io.Writer, machines []*api.Machine) error {
+	var rows [][]string
+
+	for _, machine := range machines {
+		for _, check := range machine.Checks {
+			if check.Status == "passing" {
+				continue
+			}
+
+			output, _, _ := strings.Cut(strings.TrimSpace(check.Output), "\n")
+			if len(output) > 60 {
+				output = output[:57] + "..."
+			}
+
+			rows = append(rows, []string{machine.ID, check.Name, check.Status, output})
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return render.Table(w, "Failing checks", rows, "Machine", "Check", "Status", "Output")
+}
+
+func renderRecentEvents(w io.Writer, machines []*api.Machine) error {
+	type event struct {
+		machine string
+		*api.MachineEvent
+	}
+
+	var events []event
+	for _, machine := range machines {
+		for _, e := range machine.Events {
+			events = append(events, event{machine.ID, e})
+		}
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Timestamp > events[j].Timestamp
+	})
+
+	if len(events) > dashboardEvents {
+		events = events[:dashboardEvents]
+	}
+
+	rows := make([][]string, 0, len(events))
+	for _, e := range events {
+		var detail string
+		if e.Request != nil && e.Request.ExitEvent != nil {
+			exit := e.Request.ExitEvent
+			detail = "exit code " + strconv.Itoa(int(exit.ExitCode))
+			if exit.OOMKilled {
+				detail += ", oom killed"
+			}
+		}
+
+		rows = append(rows, []string{
+			humanize.Time(time.UnixMilli(e.Timestamp)),
+			e.machine,
+			e.Type,
+			e.Status,
+			e.Source,
+			detail,
+		})
+	}
+
+	return render.Table(w, "Recent events", rows, "When", "Machine", "Type", "Status", "Source", "Detail")
+}
+
+func (d *dashboard) renderMetrics(ctx context.Context, w io.Writer) error {
+	end := time.Now()
+	start := end.Add(-dashboardSpan)
+	step := dashboardSpan / 60
+
+	rows := make([][]string, 0, len(dashboardMetrics))
+	for _, metric := range dashboardMetrics {
+		query := fmt.Sprintf(metric.query, d.app.Name)
+
+		// metrics are best effort, so that the rest of the dashboard keeps
+		// refreshing when they're unavailable
+		samples, err := d.client.QueryMetricsRange(ctx, d.app.Organization.Slug, query, start, end, step)
+		if err != nil || len(samples) == 0 {
+			rows = append(rows, []string{metric.name, "", "unavailable"})
+			continue
+		}
+
+		values := make([]float64, len(samples))
+		for i, sample := range samples {
+			values[i] = sample.Value
+		}
+
+		rows = append(rows, []string{metric.name, render.Sparkline(values), metric.format(values[len(values)-1])})
+	}
+
+	return render.Table(w, "Metrics", rows, "Metric", "Last 30 minutes", "Latest")
+}
@@ -0,0 +1,50 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// renderExternalDatabases renders the databases hosted outside of Fly.io the
+// app is attached to, if any, and if the API records them.
+func renderExternalDatabases(ctx context.Context, out io.Writer, appName string) error {
+	apiClient := client.FromContext(ctx).API()
+
+	if served, err := apiClient.SchemaHas(ctx, "App", "externalDatabases"); err != nil || !served {
+		return err
+	}
+
+	databases, err := apiClient.GetExternalDatabases(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving external databases of %s: %w", appName, err)
+	}
+
+	if len(databases) == 0 {
+		return nil
+	}
+
+	rows := make([][]string, 0, len(databases))
+	for _, db := range databases {
+		checked := "never"
+		if !db.CheckedAt.IsZero() {
+			checked = fmt.Sprintf("%s from %s", format.RelativeTime(db.CheckedAt), db.Region)
+		}
+
+		rows = append(rows, []string{
+			db.Name,
+			db.Provider,
+			db.Host + ":" + strconv.Itoa(db.Port),
+			db.SecretName,
+			db.Path,
+			checked,
+		})
+	}
+
+	return render.Table(out, "External Dependencies", rows, "Name", "Provider", "Address", "Secret", "Path", "Checked")
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/superfly/flyctl/api"
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/parallel"
 	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/render/schema"
 	"github.com/superfly/flyctl/iostreams"
 )
 
@@ -33,6 +36,16 @@ func renderMachineStatus(ctx context.Context, app *api.AppCompact) error {
 		return machines[i].ID < machines[j].ID
 	})
 
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, schema.MachinesAppStatus{
+			Name:     app.Name,
+			Owner:    app.Organization.Slug,
+			Hostname: app.Hostname,
+			Platform: app.PlatformVersion,
+			Machines: machines,
+		})
+	}
+
 	if app.IsPostgresApp() {
 		return renderPGStatus(ctx, app, machines)
 	}
@@ -42,17 +55,16 @@ func renderMachineStatus(ctx context.Context, app *api.AppCompact) error {
 
 	var updatable []*api.Machine
 
-	for _, machine := range machines {
-		image := fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
-
-		latestImage, err := client.GetLatestImageDetails(ctx, image)
+	latestImages, err := latestImageDetails(ctx, client, machines)
+	if err != nil {
+		return err
+	}
 
-		if err != nil && strings.Contains(err.Error(), "Unknown repository") {
+	for _, machine := range machines {
+		latestImage, ok := latestImages[imageOf(machine)]
+		if !ok {
 			continue
 		}
-		if err != nil {
-			return fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
-		}
 
 		if latest == nil {
 			latest = latestImage
@@ -95,7 +107,11 @@ func renderMachineStatus(ctx context.Context, app *api.AppCompact) error {
 			machine.UpdatedAt,
 		})
 	}
-	return render.Table(io.Out, "", rows, "ID", "State", "Region", "Health checks", "Image", "Created", "Updated")
+	if err := render.Table(io.Out, "", rows, "ID", "State", "Region", "Health checks", "Image", "Created", "Updated"); err != nil {
+		return err
+	}
+
+	return renderExternalDatabases(ctx, io.Out, app.Name)
 }
 
 func renderPGStatus(ctx context.Context, app *api.AppCompact, machines []*api.Machine) (err error) {
@@ -110,17 +126,16 @@ func renderPGStatus(ctx context.Context, app *api.AppCompact, machines []*api.Ma
 
 	var updatable []*api.Machine
 
-	for _, machine := range machines {
-		image := fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
-
-		latestImage, err := client.GetLatestImageDetails(ctx, image)
+	latestImages, err := latestImageDetails(ctx, client, machines)
+	if err != nil {
+		return err
+	}
 
-		if err != nil && strings.Contains(err.Error(), "Unknown repository") {
+	for _, machine := range machines {
+		latestImage, ok := latestImages[imageOf(machine)]
+		if !ok {
 			continue
 		}
-		if err != nil {
-			return fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
-		}
 
 		if latest == nil {
 			latest = latestImage
@@ -177,3 +192,47 @@ func renderPGStatus(ctx context.Context, app *api.AppCompact, machines []*api.Ma
 	}
 	return render.Table(io.Out, "", rows, "ID", "State", "Role", "Region", "Health checks", "Image", "Created", "Updated")
 }
+
+func imageOf(machine *api.Machine) string {
+	return fmt.Sprintf("%s:%s", machine.ImageRef.Repository, machine.ImageRef.Tag)
+}
+
+// latestImageDetails fetches the details of the latest versions of the images
+// machines run, by image, several at once. Images of repositories the
+// registry doesn't know are left out.
+func latestImageDetails(ctx context.Context, client *api.Client, machines []*api.Machine) (map[string]*api.ImageVersion, error) {
+	var (
+		images []string
+		seen   = map[string]bool{}
+	)
+
+	for _, machine := range machines {
+		if image := imageOf(machine); !seen[image] {
+			seen[image] = true
+			images = append(images, image)
+		}
+	}
+
+	var (
+		versions = make([]*api.ImageVersion, len(images))
+		errs     = make([]error, len(images))
+	)
+
+	parallel.Each(len(images), parallel.Limit, func(i int) {
+		versions[i], errs[i] = client.GetLatestImageDetails(ctx, images[i])
+	})
+
+	latest := make(map[string]*api.ImageVersion, len(images))
+	for i, image := range images {
+		switch err := errs[i]; {
+		case err != nil && strings.Contains(err.Error(), "Unknown repository"):
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("unable to fetch latest image details for %s: %w", image, err)
+		}
+
+		latest[image] = versions[i]
+	}
+
+	return latest, nil
+}
@@ -2,17 +2,13 @@
 package status
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"strconv"
-	"strings"
 	"time"
 
-	"github.com/azazeal/pause"
-	"github.com/inancgumus/screen"
 	"github.com/spf13/cobra"
 
 	"github.com/superfly/flyctl/iostreams"
@@ -32,6 +28,12 @@ func New() (cmd *cobra.Command) {
 		long = `Show the application's current status including application
 details, tasks, most recent deployment details and in which regions it is
 currently allocated.
+
+With --watch, the status is refreshed every --rate seconds, highlighting what
+changed. For apps running on machines, it's shown as a dashboard of the
+current release, the machines and changes to their states, failing health
+checks, recent machine events, and request, error, CPU and memory metrics of
+the last 30 minutes.
 `
 		short = "Show app status"
 	)
@@ -56,7 +58,7 @@ currently allocated.
 		},
 		flag.Bool{
 			Name:        "watch",
-			Description: "Refresh details",
+			Description: "Refresh details, as a dashboard for apps running on machines",
 		},
 		flag.Int{
 			Name:        "rate",
@@ -73,12 +75,7 @@ currently allocated.
 }
 
 func run(ctx context.Context) error {
-	watch := flag.GetBool(ctx, "watch")
-	if watch && config.FromContext(ctx).JSONOutput {
-		return errors.New("--watch and --json are not supported together")
-	}
-
-	if !watch {
+	if !flag.GetBool(ctx, "watch") {
 		return runOnce(ctx)
 	}
 
@@ -99,7 +96,7 @@ func once(ctx context.Context, out io.Writer) (err error) {
 
 	app, err := client.GetAppCompact(ctx, appName)
 	if err != nil {
-		return fmt.Errorf("failed to get app: %s", err)
+		return fmt.Errorf("failed to get app: %w", err)
 	}
 
 	platformVersion := app.PlatformVersion
@@ -157,7 +154,11 @@ func once(ctx context.Context, out io.Writer) (err error) {
 		}
 	}
 
-	err = render.AllocationStatuses(out, "Instances", backupRegions, status.Allocations...)
+	if err = render.AllocationStatuses(out, "Instances", backupRegions, status.Allocations...); err != nil {
+		return
+	}
+
+	err = renderExternalDatabases(ctx, out, appName)
 
 	return
 }
@@ -183,45 +184,31 @@ func renderDeploymentStatus(w io.Writer, ds *api.DeploymentStatus) error {
 	)
 }
 
-func runWatch(ctx context.Context) (err error) {
-	streams := iostreams.FromContext(ctx)
-	if !streams.IsInteractive() {
-		err = errors.New("--watch is not supported for non-interactive sessions")
-
-		return
-	}
-	colorize := streams.ColorScheme()
-
+func runWatch(ctx context.Context) error {
 	sleep := flag.GetInt(ctx, "rate")
 	if sleep < 1 || sleep > 3600 {
-		err = errors.New("--rate must be in the [1, 3600] range")
-
-		return
+		return errors.New("--rate must be in the [1, 3600] range")
 	}
 
 	appName := app.NameFromContext(ctx)
 
-	var buf bytes.Buffer
+	refresh := once
 
-	for err == nil {
-		buf.Reset()
+	// machines apps get a dashboard which also shows their checks, recent
+	// events and metrics
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed to get app: %w", err)
+	}
 
-		if err = once(ctx, &buf); err != nil {
-			break
+	if app.PlatformVersion == "machines" && !app.IsPostgresApp() {
+		d, err := newDashboard(ctx, client.FromContext(ctx).API(), app)
+		if err != nil {
+			return err
 		}
 
-		header := fmt.Sprintf("%s %s %s\n\n", colorize.Bold(appName), "at:", colorize.Bold(time.Now().UTC().Format("15:04:05")))
-
-		screen.Clear()
-		screen.MoveTopLeft()
-
-		io.Copy(streams.Out, io.MultiReader(
-			strings.NewReader(header),
-			&buf,
-		))
-
-		pause.For(ctx, time.Duration(sleep)*time.Second)
+		refresh = d.render
 	}
 
-	return
+	return render.Watch(ctx, time.Duration(sleep)*time.Second, appName, refresh)
 }
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newAttach() *cobra.Command {
+	const (
+		long = `Attach a Tigris bucket to an app, by setting the secrets S3 clients read to
+reach it, and deploy the app for them to take effect: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+AWS_ENDPOINT_URL_S3, AWS_REGION and BUCKET_NAME.`
+		short = "Attach a Tigris bucket to an app"
+		usage = "attach <name>"
+	)
+
+	cmd := command.New(usage, short, long, runAttach,
+		requireTigris,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runAttach(ctx context.Context) error {
+	var (
+		out     = iostreams.FromContext(ctx).Out
+		appName = flag.GetApp(ctx)
+	)
+
+	b, err := getBucket(ctx, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	if err := secrets.SetAndDeploy(ctx, appName, b.secrets()); err != nil {
+		return fmt.Errorf("failed setting secrets of %s: %w", appName, err)
+	}
+
+	fmt.Fprintf(out, "Bucket %s was attached to %s\n", b.Name, appName)
+
+	return nil
+}
+
+func newDetach() *cobra.Command {
+	const (
+		long = `Detach a Tigris bucket from an app, by unsetting the secrets attach set,
+and deploy the app. The bucket and its objects are left as they are.`
+		short = "Detach a Tigris bucket from an app"
+		usage = "detach <name>"
+	)
+
+	cmd := command.New(usage, short, long, runDetach,
+		requireTigris,
+		command.RequireAppName,
+	)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runDetach(ctx context.Context) error {
+	var (
+		out     = iostreams.FromContext(ctx).Out
+		appName = flag.GetApp(ctx)
+	)
+
+	b, err := getBucket(ctx, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	if err := secrets.UnsetAndDeploy(ctx, appName, secretNames); err != nil {
+		return fmt.Errorf("failed unsetting secrets of %s: %w", appName, err)
+	}
+
+	fmt.Fprintf(out, "Bucket %s was detached from %s\n", b.Name, appName)
+
+	return nil
+}
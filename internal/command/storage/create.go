@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		long = `Create a Tigris object storage bucket, and show the credentials to reach it
+with any S3 client. Use fly storage attach to set them as secrets of an app.`
+		short = "Create a Tigris bucket"
+		usage = "create"
+	)
+
+	cmd := command.New(usage, short, long, runCreate, requireTigris)
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "Name of the bucket, generated when not given",
+		},
+		flag.Bool{
+			Name:        "public",
+			Description: "Allow anyone to read the objects of the bucket",
+		},
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+	)
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	addOn, err := apiClient.ProvisionExtension(ctx, api.CreateAddOnInput{
+		Type:           "tigris",
+		OrganizationID: org.ID,
+		Name:           flag.GetString(ctx, "name"),
+		Options: map[string]interface{}{
+			"public": flag.GetBool(ctx, "public"),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed creating bucket: %w", err)
+	}
+
+	b := &bucket{
+		ID:           addOn.ID,
+		Name:         addOn.Name,
+		Organization: org.Slug,
+		Environment:  environment(addOn.Environment),
+	}
+
+	secrets := b.secrets()
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, secrets)
+	}
+
+	fmt.Fprintf(io.Out, "Your Tigris bucket %s is ready. Set these secrets on your apps, or run fly storage attach %s --app <app>:\n\n", colorize.Green(b.Name), b.Name)
+
+	for _, name := range sortedKeys(secrets) {
+		fmt.Fprintf(io.Out, "%s: %s\n", name, secrets[name])
+	}
+
+	fmt.Fprintln(io.Out, "\nThe secret access key can't be shown again, only rotated.")
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/gql"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long  = `List the Tigris object storage buckets of your organizations`
+		short = "List Tigris buckets"
+		usage = "list"
+	)
+
+	cmd := command.New(usage, short, long, runList, requireTigris)
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Format(),
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API().GenqClient
+		org    = flag.GetOrg(ctx)
+	)
+
+	response, err := gql.ListAddOns(ctx, client, gql.AddOnType("tigris"))
+	if err != nil {
+		return err
+	}
+
+	var buckets []gql.ListAddOnsAddOnsAddOnConnectionNodesAddOn
+	for _, addOn := range response.AddOns.Nodes {
+		if org == "" || addOn.Organization.Slug == org {
+			buckets = append(buckets, addOn)
+		}
+	}
+
+	if ok, err := render.Structured(ctx, out, buckets); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(buckets))
+	for _, addOn := range buckets {
+		rows = append(rows, []string{addOn.Name, addOn.Organization.Slug})
+	}
+
+	return render.Table(out, "", rows, "Name", "Org")
+}
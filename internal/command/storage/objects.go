@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/s3"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// Objects larger than partSize are uploaded in parts of that size.
+const partSize = 16 << 20
+
+func newLs() *cobra.Command {
+	const (
+		long = `List the objects of a Tigris bucket, optionally only those under a prefix,
+like s3://bucket/images/. Objects under deeper prefixes are listed as these
+prefixes, like directories, unless --recursive is given.`
+		short = "List the objects of a Tigris bucket"
+		usage = "ls s3://<bucket>[/<prefix>]"
+	)
+
+	cmd := command.New(usage, short, long, runLs, requireTigris)
+
+	flag.Add(cmd,
+		flag.Bool{
+			Name:        "recursive",
+			Shorthand:   "r",
+			Description: "List all objects under the prefix",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runLs(ctx context.Context) error {
+	out := iostreams.FromContext(ctx).Out
+
+	bucketName, prefix, ok := parseObjectURL(flag.FirstArg(ctx))
+	if !ok {
+		return fmt.Errorf("invalid location %q, must be like s3://bucket/prefix", flag.FirstArg(ctx))
+	}
+
+	b, err := getBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	delimiter := "/"
+	if flag.GetBool(ctx, "recursive") {
+		delimiter = ""
+	}
+
+	objects, prefixes, err := b.client().ListObjects(ctx, b.name(), prefix, delimiter)
+	if err != nil {
+		return fmt.Errorf("failed listing objects of %s: %w", b.Name, err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(out, struct {
+			Prefixes []string    `json:"prefixes"`
+			Objects  []s3.Object `json:"objects"`
+		}{prefixes, objects})
+	}
+
+	rows := make([][]string, 0, len(prefixes)+len(objects))
+	for _, p := range prefixes {
+		rows = append(rows, []string{p, "", ""})
+	}
+	for _, object := range objects {
+		rows = append(rows, []string{
+			object.Key,
+			humanize.IBytes(uint64(object.Size)),
+			format.RelativeTime(object.LastModified),
+		})
+	}
+
+	return render.Table(out, "", rows, "Key", "Size", "Modified")
+}
+
+func newCp() *cobra.Command {
+	const (
+		long = `Copy a local file to a Tigris bucket, or an object of a bucket to a local
+file. Objects are given like s3://bucket/key, and - stands for standard input
+or output. When the destination is a directory, or a prefix ending with /, the
+name of the source is kept.`
+		short = "Copy files to and from a Tigris bucket"
+		usage = "cp <source> <destination>"
+	)
+
+	cmd := command.New(usage, short, long, runCp, requireTigris)
+
+	flag.Add(cmd)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	return cmd
+}
+
+func runCp(ctx context.Context) error {
+	var (
+		args     = flag.Args(ctx)
+		src, dst = args[0], args[1]
+	)
+
+	srcBucket, srcKey, srcRemote := parseObjectURL(src)
+	dstBucket, dstKey, dstRemote := parseObjectURL(dst)
+
+	switch {
+	case srcRemote && dstRemote:
+		return errors.New("copying between buckets isn't supported, one of source and destination must be local")
+	case srcRemote:
+		return download(ctx, srcBucket, srcKey, dst)
+	case dstRemote:
+		return upload(ctx, src, dstBucket, dstKey)
+	default:
+		return errors.New("one of source and destination must be an object, like s3://bucket/key")
+	}
+}
+
+func upload(ctx context.Context, src, bucketName, key string) error {
+	streams := iostreams.FromContext(ctx)
+
+	var r io.Reader = streams.In
+	if src != "-" {
+		f, err := os.Open(src)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	if key == "" || strings.HasSuffix(key, "/") {
+		if src == "-" {
+			return errors.New("the key of the object must be given when copying standard input")
+		}
+
+		key += filepath.Base(src)
+	}
+
+	b, err := getBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	n, err := putObject(ctx, b.client(), b.name(), key, r)
+	if err != nil {
+		return fmt.Errorf("failed uploading to s3://%s/%s: %w", bucketName, key, err)
+	}
+
+	fmt.Fprintf(streams.ErrOut, "Copied %s to s3://%s/%s\n", humanize.IBytes(uint64(n)), bucketName, key)
+
+	return nil
+}
+
+// putObject uploads the content of r as an object, in parts when it's larger
+// than partSize.
+func putObject(ctx context.Context, client *s3.Client, bucket, key string, r io.Reader) (int64, error) {
+	buf := make([]byte, partSize)
+
+	n, err := io.ReadFull(r, buf)
+	switch {
+	case err == io.EOF || err == io.ErrUnexpectedEOF:
+		return int64(n), client.PutObject(ctx, bucket, key, buf[:n], "")
+	case err != nil:
+		return 0, err
+	}
+
+	uploadID, err := client.CreateMultipartUpload(ctx, bucket, key)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		parts []s3.Part
+		total int64
+	)
+
+	for n > 0 {
+		number := len(parts) + 1
+
+		etag, err := client.UploadPart(ctx, bucket, key, uploadID, number, buf[:n])
+		if err != nil {
+			_ = client.AbortMultipartUpload(ctx, bucket, key, uploadID)
+
+			return 0, err
+		}
+
+		parts = append(parts, s3.Part{PartNumber: number, ETag: etag})
+		total += int64(n)
+
+		n, err = io.ReadFull(r, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			_ = client.AbortMultipartUpload(ctx, bucket, key, uploadID)
+
+			return 0, err
+		}
+	}
+
+	return total, client.CompleteMultipartUpload(ctx, bucket, key, uploadID, parts)
+}
+
+func download(ctx context.Context, bucketName, key, dst string) error {
+	streams := iostreams.FromContext(ctx)
+
+	if key == "" || strings.HasSuffix(key, "/") {
+		return errors.New("the key of the object to copy must be given, like s3://bucket/key")
+	}
+
+	b, err := getBucket(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	body, _, err := b.client().GetObject(ctx, b.name(), key)
+	if err != nil {
+		return fmt.Errorf("failed downloading s3://%s/%s: %w", bucketName, key, err)
+	}
+	defer body.Close()
+
+	if dst == "-" {
+		_, err = io.Copy(streams.Out, body)
+
+		return err
+	}
+
+	if info, err := os.Stat(dst); err == nil && info.IsDir() {
+		dst = filepath.Join(dst, path.Base(key))
+	}
+
+	f, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed writing %s: %w", dst, err)
+	}
+
+	fmt.Fprintf(streams.ErrOut, "Copied %s to %s\n", humanize.IBytes(uint64(n)), dst)
+
+	return nil
+}
+
+// parseObjectURL splits a location like s3://bucket/key into the bucket and
+// key, reporting whether it is one.
+func parseObjectURL(s string) (bucket, key string, ok bool) {
+	const scheme = "s3://"
+
+	if !strings.HasPrefix(s, scheme) {
+		return "", "", false
+	}
+
+	bucket, key, _ = strings.Cut(strings.TrimPrefix(s, scheme), "/")
+
+	return bucket, key, bucket != ""
+}
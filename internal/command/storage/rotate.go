@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/secrets"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRotate() *cobra.Command {
+	const (
+		long = `Issue new credentials for a Tigris bucket, revoking the old ones, and set the
+secrets of the apps given with --app to them, deploying those apps for the new
+credentials to take effect. Clients still using the old credentials can't
+reach the bucket anymore.`
+		short = "Rotate the credentials of a Tigris bucket"
+		usage = "rotate <name>"
+	)
+
+	cmd := command.New(usage, short, long, runRotate, requireTigris)
+
+	flag.Add(cmd,
+		flag.Yes(),
+		flag.StringSlice{
+			Name:        "app",
+			Shorthand:   "a",
+			Description: "App attached to the bucket whose secrets to update, comma separated or repeated",
+		},
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	return cmd
+}
+
+func runRotate(ctx context.Context) error {
+	var (
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		apiClient = client.FromContext(ctx).API()
+		apps      = flag.GetStringSlice(ctx, "app")
+	)
+
+	b, err := getBucket(ctx, flag.FirstArg(ctx))
+	if err != nil {
+		return err
+	}
+
+	if !flag.GetYes(ctx) {
+		msg := fmt.Sprintf("Rotate the credentials of %s? Clients using the old ones won't be able to reach it", b.Name)
+		if len(apps) == 0 {
+			msg += ", and no --app is given to update"
+		}
+
+		switch confirmed, err := prompt.Confirm(ctx, msg+"."); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	addOn, err := apiClient.RotateAddOnCredentials(ctx, b.ID)
+	if err != nil {
+		return fmt.Errorf("failed rotating the credentials of %s: %w", b.Name, err)
+	}
+
+	b.Environment = environment(addOn.Environment)
+	values := b.secrets()
+
+	fmt.Fprintf(io.Out, "The credentials of %s were rotated.\n", b.Name)
+
+	var failed []string
+	for _, app := range apps {
+		if err := secrets.SetAndDeploy(ctx, app, values); err != nil {
+			fmt.Fprintf(io.ErrOut, "%s failed updating secrets of %s: %v\n", colorize.Red("✗"), app, err)
+			failed = append(failed, app)
+
+			continue
+		}
+
+		fmt.Fprintf(io.Out, "%s set secrets of %s, and deployed it\n", colorize.Green("✓"), app)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed updating %d apps; run fly storage attach %s --app <app> to retry", len(failed), b.Name)
+	}
+
+	if len(apps) == 0 {
+		fmt.Fprintf(io.Out, "AWS_ACCESS_KEY_ID: %s\nAWS_SECRET_ACCESS_KEY: %s\n", values["AWS_ACCESS_KEY_ID"], values["AWS_SECRET_ACCESS_KEY"])
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Package storage implements the storage command chain.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+// Tigris buckets are reached through this endpoint and region unless their
+// credentials name others.
+const (
+	defaultEndpoint = "https://fly.storage.tigris.dev"
+	defaultRegion   = "auto"
+)
+
+// requireTigris is the Preparer making sure the API provisions Tigris
+// buckets, which storage commands need.
+var requireTigris = command.RequireAPI("AddOnType", "tigris")
+
+// secretNames are the names of the secrets attach sets on apps.
+var secretNames = []string{
+	"AWS_ACCESS_KEY_ID",
+	"AWS_SECRET_ACCESS_KEY",
+	"AWS_ENDPOINT_URL_S3",
+	"AWS_REGION",
+	"BUCKET_NAME",
+}
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing Tigris object storage buckets, their credentials and
+the apps using them, and for browsing the objects they store. They need the
+API to provision Tigris buckets, which it doesn't everywhere yet.`
+		short = "Manage Tigris object storage buckets"
+	)
+
+	cmd := command.New("storage", short, long, nil)
+
+	cmd.AddCommand(
+		newCreate(),
+		newList(),
+		newDestroy(),
+		newAttach(),
+		newDetach(),
+		newRotate(),
+		newLs(),
+		newCp(),
+	)
+
+	return cmd
+}
+
+// bucket is a Tigris bucket along with its credentials.
+type bucket struct {
+	ID           string
+	Name         string
+	Organization string
+	Environment  map[string]string
+}
+
+func getBucket(ctx context.Context, name string) (*bucket, error) {
+	addOn, err := client.FromContext(ctx).API().GetStorageBucket(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving bucket %s: %w", name, err)
+	}
+
+	return &bucket{
+		ID:           addOn.ID,
+		Name:         addOn.Name,
+		Organization: addOn.Organization.Slug,
+		Environment:  environment(addOn.Environment),
+	}, nil
+}
+
+// environment converts the environment of an add-on, which the API returns
+// as a JSON object, to secrets.
+func environment(m map[string]interface{}) map[string]string {
+	env := make(map[string]string, len(m))
+	for name, value := range m {
+		if s, ok := value.(string); ok {
+			env[name] = s
+		}
+	}
+
+	return env
+}
+
+// secrets returns the secrets apps using b are set.
+func (b *bucket) secrets() map[string]string {
+	secrets := map[string]string{
+		"AWS_ENDPOINT_URL_S3": defaultEndpoint,
+		"AWS_REGION":          defaultRegion,
+		"BUCKET_NAME":         b.Name,
+	}
+
+	for _, name := range secretNames {
+		if value := b.Environment[name]; value != "" {
+			secrets[name] = value
+		}
+	}
+
+	return secrets
+}
+
+// client returns a client of the S3 API authenticated with the credentials
+// of b.
+func (b *bucket) client() *s3.Client {
+	secrets := b.secrets()
+
+	return &s3.Client{
+		Endpoint:  secrets["AWS_ENDPOINT_URL_S3"],
+		Region:    secrets["AWS_REGION"],
+		AccessKey: secrets["AWS_ACCESS_KEY_ID"],
+		SecretKey: secrets["AWS_SECRET_ACCESS_KEY"],
+	}
+}
+
+// name returns the name the S3 API knows b by.
+func (b *bucket) name() string {
+	return b.secrets()["BUCKET_NAME"]
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,196 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newCreate() *cobra.Command {
+	const (
+		long = `Creates an access token limited to a scope:
+
+  deploy        deploying apps, and managing their secrets, releases and scale
+  readonly      reading apps, their machines, logs and metrics
+  machines      creating, updating, starting, stopping and destroying machines
+  machine-exec  starting, stopping and running commands on the machines given
+                with --machine only
+
+Tokens apply to all the apps of an organization, or to the app given with
+--app, which machine-exec tokens require, so that a job runner can be given
+just enough power to manage its own workers. They expire after --expiry, and
+--allow-ip restricts them to addresses or networks like 203.0.113.7 or
+203.0.113.0/24.
+
+The token is written to stdout, and can't be retrieved again afterwards.`
+		short = "Create a scoped access token"
+		usage = "create <deploy|readonly|machines|machine-exec>"
+	)
+
+	cmd := command.New(usage, short, long, runCreate,
+		requireTokens,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		scopeFlags,
+		flag.String{
+			Name:        "name",
+			Shorthand:   "n",
+			Description: "Name of the token, to tell it apart when listing tokens",
+		},
+		flag.Duration{
+			Name:        "expiry",
+			Shorthand:   "x",
+			Description: "How long the token is valid for, or 0 for it to never expire",
+			Default:     365 * 24 * time.Hour,
+		},
+		flag.StringSlice{
+			Name:        "allow-ip",
+			Description: "Address or network the token may only be used from, comma separated or repeated",
+		},
+		flag.StringSlice{
+			Name:        "machine",
+			Description: "Machine a machine-exec token may act on, comma separated or repeated",
+		},
+	)
+
+	return cmd
+}
+
+func runCreate(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		name   = flag.GetString(ctx, "name")
+		expiry = flag.GetDuration(ctx, "expiry")
+	)
+
+	scopeName := flag.FirstArg(ctx)
+
+	apiScope, ok := scopes[scopeName]
+	if !ok {
+		names := make([]string, 0, len(scopes))
+		for name := range scopes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		return fmt.Errorf("unknown scope %q, must be one of %s", scopeName, strings.Join(names, ", "))
+	}
+
+	if expiry < 0 {
+		return fmt.Errorf("--expiry must be positive, or 0 for the token to never expire")
+	}
+
+	allowed := flag.GetStringSlice(ctx, "allow-ip")
+	for _, ip := range allowed {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return fmt.Errorf("invalid --allow-ip %q, must be an address like 203.0.113.7 or a network like 203.0.113.0/24", ip)
+		}
+	}
+
+	machineIDs := flag.GetStringSlice(ctx, "machine")
+	switch {
+	case apiScope == "MACHINE_EXEC" && (len(machineIDs) == 0 || flag.GetApp(ctx) == ""):
+		return errors.New("machine-exec tokens require --app and at least one --machine")
+	case apiScope != "MACHINE_EXEC" && len(machineIDs) > 0:
+		return errors.New("--machine only applies to machine-exec tokens")
+	}
+
+	org, app, err := scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := checkMachines(ctx, app, machineIDs); err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = scopeName + " token"
+		if app != nil {
+			name = fmt.Sprintf("%s token for %s", scopeName, app.Name)
+		}
+	}
+
+	input := api.CreateAccessTokenInput{
+		OrganizationID: org.ID,
+		Name:           name,
+		Scope:          apiScope,
+		AllowedIPs:     allowed,
+		MachineIDs:     machineIDs,
+	}
+
+	if app != nil {
+		input.AppID = app.ID
+	}
+
+	if expiry > 0 {
+		expiresAt := time.Now().Add(expiry).UTC()
+		input.ExpiresAt = &expiresAt
+	}
+
+	token, secret, err := client.CreateAccessToken(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed creating token: %w", err)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, struct {
+			*api.AccessToken
+			Token string
+		}{token, secret})
+	}
+
+	expires := "never expires"
+	if token.ExpiresAt != nil {
+		expires = "expires " + token.ExpiresAt.Format(time.RFC3339)
+	}
+
+	fmt.Fprintf(io.ErrOut, "Created token %s (%s), which %s. It won't be shown again:\n", token.ID, token.Name, expires)
+	fmt.Fprintln(io.Out, secret)
+
+	return nil
+}
+
+// checkMachines makes sure the machines a machine-exec token is created for
+// belong to its app.
+func checkMachines(ctx context.Context, app *api.AppCompact, machineIDs []string) error {
+	if len(machineIDs) == 0 {
+		return nil
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	for _, id := range machineIDs {
+		if _, err := flapsClient.Get(ctx, id); err != nil {
+			return fmt.Errorf("failed retrieving machine %s of %s: %w", id, app.Name, err)
+		}
+	}
+
+	return nil
+}
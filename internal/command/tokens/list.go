@@ -0,0 +1,115 @@
+package tokens
+
+import (
+	"context"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/format"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newList() *cobra.Command {
+	const (
+		long = `Lists the scoped access tokens of an organization, or those that apply to
+the app given with --app, which include the tokens of its whole organization,
+along with who created them and when they were last used.`
+		short = "List scoped access tokens"
+	)
+
+	cmd := command.New("list", short, long, runList,
+		requireTokens,
+	)
+
+	cmd.Args = cobra.NoArgs
+	cmd.Aliases = []string{"ls"}
+
+	flag.Add(cmd,
+		scopeFlags,
+		flag.Format(),
+	)
+
+	return cmd
+}
+
+func runList(ctx context.Context) error {
+	var (
+		out    = iostreams.FromContext(ctx).Out
+		client = client.FromContext(ctx).API()
+	)
+
+	org, app, err := scope(ctx)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := client.GetAccessTokens(ctx, org.Slug)
+	if err != nil {
+		return err
+	}
+
+	if app != nil {
+		var applicable []api.AccessToken
+		for _, token := range tokens {
+			if token.App == nil || token.App.ID == app.ID {
+				applicable = append(applicable, token)
+			}
+		}
+		tokens = applicable
+	}
+
+	if ok, err := render.Structured(ctx, out, tokens); ok {
+		return err
+	}
+
+	rows := make([][]string, 0, len(tokens))
+	for _, token := range tokens {
+		appName := "all apps"
+		if token.App != nil {
+			appName = token.App.Name
+		}
+		if len(token.MachineIDs) > 0 {
+			appName += " (machines " + strings.Join(token.MachineIDs, ", ") + ")"
+		}
+
+		allowed := "any"
+		if len(token.AllowedIPs) > 0 {
+			allowed = strings.Join(token.AllowedIPs, ", ")
+		}
+
+		createdBy := ""
+		if token.CreatedBy != nil {
+			createdBy = token.CreatedBy.Email
+		}
+
+		expires := "never"
+		if token.ExpiresAt != nil {
+			expires = format.RelativeTime(*token.ExpiresAt)
+		}
+
+		lastUsed := "never"
+		if token.LastUsedAt != nil {
+			lastUsed = format.RelativeTime(*token.LastUsedAt)
+		}
+
+		rows = append(rows, []string{
+			token.ID,
+			token.Name,
+			scopeName(token.Scope),
+			appName,
+			allowed,
+			createdBy,
+			format.RelativeTime(token.CreatedAt),
+			expires,
+			lastUsed,
+		})
+	}
+
+	return render.Table(out, "", rows, "ID", "Name", "Scope", "App", "Allowed IPs", "Created By", "Created", "Expires", "Last Used")
+}
@@ -0,0 +1,65 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+func newRevoke() *cobra.Command {
+	const (
+		long = `Revokes the access token with the given ID, as shown by the list command,
+so that it's refused from then on.`
+		short = "Revoke a scoped access token"
+		usage = "revoke <id>"
+	)
+
+	cmd := command.New(usage, short, long, runRevoke,
+		requireTokens,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.Yes(),
+	)
+
+	return cmd
+}
+
+func runRevoke(ctx context.Context) error {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		id     = flag.FirstArg(ctx)
+	)
+
+	if !flag.GetYes(ctx) {
+		switch confirmed, err := prompt.Confirmf(ctx, "Revoke token %s? Anything using it will stop working.", id); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return errors.New("--yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	if err := client.RevokeAccessToken(ctx, id); err != nil {
+		return fmt.Errorf("failed revoking token %s: %w", id, err)
+	}
+
+	fmt.Fprintf(io.Out, "Revoked token %s\n", id)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+// Package tokens implements the tokens command chain.
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+)
+
+func New() *cobra.Command {
+	const (
+		long = `Commands for managing scoped access tokens, which are limited to deploying,
+to reading, or to managing machines, for all the apps of an organization or
+for a single app given with --app. Tokens may expire, and be restricted to a
+list of IP addresses, so that CI and other automation don't need a personal
+token. Machine-exec tokens go further, and may only act on the machines they're
+created for.
+
+Scoped access tokens need the API to serve them, which it doesn't everywhere yet.`
+		short = "Manage scoped access tokens"
+	)
+
+	cmd := command.New("tokens", short, long, nil)
+
+	cmd.AddCommand(
+		newCreate(),
+		newList(),
+		newRevoke(),
+	)
+
+	return cmd
+}
+
+// requireTokens makes sure the API serves scoped access tokens.
+var requireTokens = command.RequireAPI("CreateAccessTokenInput", "scope")
+
+// scopes maps the scopes create takes to those of the API.
+var scopes = map[string]string{
+	"deploy":       "DEPLOY",
+	"readonly":     "READ_ONLY",
+	"machines":     "MACHINES",
+	"machine-exec": "MACHINE_EXEC",
+}
+
+// scopeName returns the name create takes for the API scope of a token.
+func scopeName(apiScope string) string {
+	for name, s := range scopes {
+		if s == apiScope {
+			return name
+		}
+	}
+
+	return strings.ToLower(apiScope)
+}
+
+// scopeFlags pick the organization, or the app, tokens are managed for.
+var scopeFlags = flag.Set{
+	flag.Org(),
+	flag.String{
+		Name:        flag.AppName,
+		Shorthand:   "a",
+		Description: "Application the tokens are restricted to, rather than all the apps of the organization",
+	},
+}
+
+// scope returns the organization tokens are managed for, and the app they're
+// restricted to with --app, if any.
+func scope(ctx context.Context) (*api.OrganizationBasic, *api.AppCompact, error) {
+	if appName := flag.GetApp(ctx); appName != "" {
+		app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		}
+
+		return app.Organization, app, nil
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &api.OrganizationBasic{ID: org.ID, Slug: org.Slug}, nil, nil
+}
@@ -0,0 +1,373 @@
+// Package top implements the top command.
+package top
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/parallel"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/promql"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// extraMemoryGBMonth is the monthly price of each GB of memory machines have
+// on top of that of their VM size.
+const extraMemoryGBMonth = 5.0
+
+func New() *cobra.Command {
+	const (
+		long = `Shows an overview of all the apps of an organization, refreshed every
+--interval: the states and regions of their machines, their CPU and memory
+usage, and an estimate of what their started machines cost a month, based on
+the prices of their VM sizes. Volumes, bandwidth and other resources aren't
+part of the estimate.
+
+The overview is shown once when not running interactively, or with --json.
+`
+		short = "Show a live overview of the apps of an organization"
+	)
+
+	cmd := command.New("top", short, long, run,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.Org(),
+		flag.Duration{
+			Name:        "interval",
+			Description: "How often the overview is refreshed",
+			Default:     5 * time.Second,
+		},
+		flag.String{
+			Name:        "sort",
+			Description: "Column to sort apps by: name, machines, cpu, memory or cost",
+			Default:     "cpu",
+		},
+	)
+
+	return cmd
+}
+
+// appUsage is the overview of an app.
+type appUsage struct {
+	Name     string
+	Platform string
+	Status   string
+	Machines map[string]int
+	Regions  []string
+	CPU      float64
+	MemoryMB float64
+	Cost     float64
+	Error    string `json:",omitempty"`
+}
+
+func (u *appUsage) machineCount() (n int) {
+	for _, count := range u.Machines {
+		n += count
+	}
+
+	return
+}
+
+var sorters = map[string]func(a, b *appUsage) bool{
+	"name":     func(a, b *appUsage) bool { return a.Name < b.Name },
+	"machines": func(a, b *appUsage) bool { return a.machineCount() > b.machineCount() },
+	"cpu":      func(a, b *appUsage) bool { return a.CPU > b.CPU },
+	"memory":   func(a, b *appUsage) bool { return a.MemoryMB > b.MemoryMB },
+	"cost":     func(a, b *appUsage) bool { return a.Cost > b.Cost },
+}
+
+// overview gathers the usage of the apps of an organization, keeping the
+// flaps clients of apps around between refreshes.
+type overview struct {
+	client *api.Client
+	org    *api.Organization
+	sizes  []api.VMSize
+	less   func(a, b *appUsage) bool
+
+	mu    sync.Mutex
+	flaps map[string]*flaps.Client
+}
+
+func run(ctx context.Context) error {
+	var (
+		streams   = iostreams.FromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		interval  = flag.GetDuration(ctx, "interval")
+		sortBy    = flag.GetString(ctx, "sort")
+	)
+
+	less, ok := sorters[sortBy]
+	if !ok {
+		return fmt.Errorf("unsupported sort column %q; use name, machines, cpu, memory or cost", sortBy)
+	}
+
+	org, err := prompt.Org(ctx)
+	if err != nil {
+		return err
+	}
+
+	sizes, err := apiClient.PlatformVMSizes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed retrieving VM sizes: %w", err)
+	}
+
+	o := &overview{
+		client: apiClient,
+		org:    org,
+		sizes:  sizes,
+		less:   less,
+		flaps:  map[string]*flaps.Client{},
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		usages, err := o.gather(ctx)
+		if err != nil {
+			return err
+		}
+
+		return render.JSON(streams.Out, usages)
+	}
+
+	if !streams.IsInteractive() {
+		return o.render(ctx, streams.Out)
+	}
+
+	return render.Watch(ctx, interval, "Organization "+org.Slug, o.render)
+}
+
+func (o *overview) render(ctx context.Context, w io.Writer) error {
+	colorize := iostreams.FromContext(ctx).ColorScheme()
+
+	usages, err := o.gather(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		states = map[string]int{}
+		cost   float64
+		rows   = make([][]string, 0, len(usages))
+	)
+
+	for _, u := range usages {
+		for state, count := range u.Machines {
+			states[state] += count
+		}
+		cost += u.Cost
+
+		machines := describeStates(u.Machines)
+		if u.Error != "" {
+			machines = colorize.Red(u.Error)
+		}
+
+		cpu, memory, monthly := "-", "-", "-"
+		if u.Platform == "machines" {
+			cpu = fmt.Sprintf("%.1f%%", u.CPU)
+			memory = humanize.IBytes(uint64(u.MemoryMB * 1024 * 1024))
+			monthly = fmt.Sprintf("$%.2f", u.Cost)
+		}
+
+		rows = append(rows, []string{
+			u.Name,
+			u.Status,
+			machines,
+			strings.Join(u.Regions, ","),
+			cpu,
+			memory,
+			monthly,
+		})
+	}
+
+	fmt.Fprintf(w, "%d apps, machines: %s, estimated $%.2f/month\n\n", len(usages), describeStates(states), cost)
+
+	return render.Table(w, "", rows, "App", "Status", "Machines", "Regions", "CPU", "Memory", "Est. Monthly")
+}
+
+func (o *overview) gather(ctx context.Context) ([]*appUsage, error) {
+	apps, err := o.client.GetApps(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed listing apps: %w", err)
+	}
+
+	var usages []*appUsage
+	for _, app := range apps {
+		if app.Organization.Slug != o.org.Slug {
+			continue
+		}
+
+		usages = append(usages, &appUsage{
+			Name:     app.Name,
+			Platform: app.PlatformVersion,
+			Status:   app.Status,
+			Machines: map[string]int{},
+		})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		parallel.Each(len(usages), parallel.Limit, func(i int) {
+			if u := usages[i]; u.Platform == "machines" {
+				if err := o.machines(ctx, u); err != nil {
+					u.Error = err.Error()
+				}
+			}
+		})
+	}()
+
+	o.usage(ctx, usages)
+
+	<-done
+
+	sort.SliceStable(usages, func(i, j int) bool {
+		return o.less(usages[i], usages[j])
+	})
+
+	return usages, nil
+}
+
+// machines counts the machines of u by state, and estimates what its started
+// ones cost.
+func (o *overview) machines(ctx context.Context, u *appUsage) error {
+	o.mu.Lock()
+	flapsClient := o.flaps[u.Name]
+	o.mu.Unlock()
+
+	if flapsClient == nil {
+		app := &api.AppCompact{
+			Name:            u.Name,
+			PlatformVersion: u.Platform,
+			Organization: &api.OrganizationBasic{
+				ID:   o.org.ID,
+				Slug: o.org.Slug,
+			},
+		}
+
+		var err error
+		if flapsClient, err = flaps.New(ctx, app); err != nil {
+			return err
+		}
+
+		o.mu.Lock()
+		o.flaps[u.Name] = flapsClient
+		o.mu.Unlock()
+	}
+
+	machines, err := flapsClient.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	regions := map[string]bool{}
+	for _, machine := range machines {
+		u.Machines[machine.State]++
+		regions[machine.Region] = true
+
+		if machine.State == "started" && machine.Config != nil {
+			u.Cost += monthlyCost(o.sizes, machine.Config.Guest)
+		}
+	}
+
+	for region := range regions {
+		u.Regions = append(u.Regions, region)
+	}
+	sort.Strings(u.Regions)
+
+	return nil
+}
+
+// usage sets the CPU and memory usage of the apps of usages, which is best
+// effort since metrics may not be available.
+func (o *overview) usage(ctx context.Context, usages []*appUsage) {
+	byName := make(map[string]*appUsage, len(usages))
+	for _, u := range usages {
+		byName[u.Name] = u
+	}
+
+	now := time.Now()
+
+	if series, err := o.client.QueryMetrics(ctx, o.org.Slug, promql.CPU("", "app"), now); err == nil {
+		for _, s := range series {
+			if u := byName[s.Labels["app"]]; u != nil && len(s.Samples) > 0 {
+				u.CPU = s.Samples[0].Value
+			}
+		}
+	}
+
+	if series, err := o.client.QueryMetrics(ctx, o.org.Slug, promql.Memory("", "app"), now); err == nil {
+		for _, s := range series {
+			if u := byName[s.Labels["app"]]; u != nil && len(s.Samples) > 0 {
+				u.MemoryMB = s.Samples[0].Value / 1024 / 1024
+			}
+		}
+	}
+}
+
+// monthlyCost estimates what a machine of guest costs a month, from the price
+// of the VM size with its CPUs and of the memory it has on top of the size's.
+func monthlyCost(sizes []api.VMSize, guest *api.MachineGuest) float64 {
+	if guest == nil {
+		return 0
+	}
+
+	name := fmt.Sprintf("shared-cpu-%dx", guest.CPUs)
+	if guest.CPUKind == "performance" {
+		name = fmt.Sprintf("performance-%dx", guest.CPUs)
+	}
+
+	for _, size := range sizes {
+		if size.Name != name {
+			continue
+		}
+
+		cost := float64(size.PriceMonth)
+		if extra := guest.MemoryMB - size.MemoryMB; extra > 0 {
+			cost += float64(extra) / 1024 * extraMemoryGBMonth
+		}
+
+		return cost
+	}
+
+	return 0
+}
+
+// describeStates spells out machine counts by state, like 3 started, 1
+// stopped.
+func describeStates(states map[string]int) string {
+	if len(states) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(states))
+	for state := range states {
+		names = append(names, state)
+	}
+	sort.Strings(names)
+
+	described := make([]string, 0, len(names))
+	for _, state := range names {
+		described = append(described, fmt.Sprintf("%d %s", states[state], state))
+	}
+
+	return strings.Join(described, ", ")
+}
@@ -0,0 +1,227 @@
+// Package trace implements the trace command.
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azazeal/pause"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// requestIDHeader is the response header the proxy identifies requests by.
+const requestIDHeader = "fly-request-id"
+
+// traceWait is how long traces are waited on, since the proxy takes a few
+// seconds to report them.
+const traceWait = 15 * time.Second
+
+// timelineWidth is the width of the bars charting when the steps of a
+// request happened.
+const timelineWidth = 30
+
+func New() *cobra.Command {
+	const (
+		long = `Show how the Fly proxy handled a request to an application: the edge
+region it arrived at, how a machine was picked to route it to and where that
+machine runs, how long each step took, and the response code.
+
+Requests are identified by the fly-request-id header of their response. Without
+one, a test request is sent to the application, to --path, and traced.
+--prefer-region asks the proxy to route the test request to a region, like the
+fly-prefer-region header does.
+
+Request traces need the API to serve them, which it doesn't everywhere yet.
+`
+		short = "Trace how the proxy handled a request"
+		usage = "trace [request-id]"
+	)
+
+	cmd := command.New(usage, short, long, run,
+		command.RequireAPI("App", "requestTrace"),
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.MaximumNArgs(1)
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.String{
+			Name:        "path",
+			Description: "Path the test request is sent to",
+			Default:     "/",
+		},
+		flag.String{
+			Name:        "prefer-region",
+			Description: "Region the proxy is asked to route the test request to",
+		},
+	)
+
+	return cmd
+}
+
+func run(ctx context.Context) error {
+	var (
+		appName   = app.NameFromContext(ctx)
+		apiClient = client.FromContext(ctx).API()
+		io        = iostreams.FromContext(ctx)
+		requestID = flag.FirstArg(ctx)
+	)
+
+	if requestID == "" {
+		appCompact, err := apiClient.GetAppCompact(ctx, appName)
+		if err != nil {
+			return fmt.Errorf("failed retrieving app %s: %w", appName, err)
+		}
+
+		if requestID, err = sendTestRequest(ctx, appCompact); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(io.ErrOut, "traced test request %s\n", requestID)
+	}
+
+	trace, err := waitForTrace(ctx, apiClient, appName, requestID)
+	if err != nil {
+		return err
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, trace)
+	}
+
+	colorize := io.ColorScheme()
+
+	status := strconv.Itoa(trace.Status)
+	switch {
+	case trace.Status >= 500:
+		status = colorize.Red(status)
+	case trace.Status >= 400:
+		status = colorize.Yellow(status)
+	default:
+		status = colorize.Green(status)
+	}
+
+	fmt.Fprintf(io.Out, "%s %s\n", colorize.Bold("Request:"), trace.RequestID)
+	fmt.Fprintf(io.Out, "%s %s %s\n", colorize.Bold("URL:"), trace.Method, trace.URL)
+	fmt.Fprintf(io.Out, "%s %s\n", colorize.Bold("Edge region:"), trace.EdgeRegion)
+	fmt.Fprintf(io.Out, "%s %s in %.1fms at %s\n\n", colorize.Bold("Response:"), status, trace.DurationMs, trace.StartedAt.UTC().Format(time.RFC3339))
+
+	rows := make([][]string, 0, len(trace.Spans))
+	for _, span := range trace.Spans {
+		rows = append(rows, []string{
+			fmt.Sprintf("+%.1fms", span.OffsetMs),
+			fmt.Sprintf("%.1fms", span.DurationMs),
+			timeline(span, trace.DurationMs),
+			span.Name,
+			span.Region,
+			span.MachineID,
+			span.Description,
+		})
+	}
+
+	return render.Table(io.Out, "Steps", rows, "Start", "Took", "Timeline", "Step", "Region", "Machine", "Detail")
+}
+
+// sendTestRequest sends a GET request to app and returns its request id.
+func sendTestRequest(ctx context.Context, app *api.AppCompact) (string, error) {
+	if app.Hostname == "" {
+		return "", fmt.Errorf("app %s has no hostname to send a test request to; pass the id of a request to trace instead", app.Name)
+	}
+
+	path := flag.GetString(ctx, "path")
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	url := "https://" + app.Hostname + path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	if region := flag.GetString(ctx, "prefer-region"); region != "" {
+		req.Header.Set("fly-prefer-region", region)
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed sending test request to %s: %w", url, err)
+	}
+	resp.Body.Close()
+
+	requestID := resp.Header.Get(requestIDHeader)
+	if requestID == "" {
+		return "", fmt.Errorf("the response of %s has no %s header, so it didn't go through the proxy", url, requestIDHeader)
+	}
+
+	return requestID, nil
+}
+
+// waitForTrace retrieves the trace of a request, waiting for up to traceWait
+// for the proxy to report it.
+func waitForTrace(ctx context.Context, apiClient *api.Client, appName, requestID string) (*api.RequestTrace, error) {
+	deadline := time.Now().Add(traceWait)
+
+	for {
+		trace, err := apiClient.GetRequestTrace(ctx, appName, requestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed retrieving trace of request %s: %w", requestID, err)
+		}
+
+		if trace != nil {
+			return trace, nil
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("no trace found for request " + requestID + "; requests are traced for a few hours after they're made")
+		}
+
+		pause.For(ctx, time.Second)
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// timeline charts when span happened within a request that took total
+// milliseconds, like   ████      .
+func timeline(span api.RequestTraceSpan, total float64) string {
+	if total <= 0 {
+		return ""
+	}
+
+	start := int(span.OffsetMs / total * timelineWidth)
+	if start >= timelineWidth {
+		start = timelineWidth - 1
+	}
+
+	width := int(span.DurationMs/total*timelineWidth + 0.5)
+	if width < 1 {
+		width = 1
+	}
+	if start+width > timelineWidth {
+		width = timelineWidth - start
+	}
+
+	return strings.Repeat(" ", start) + strings.Repeat("█", width) + strings.Repeat(" ", timelineWidth-start-width)
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render/schema"
 )
 
 const saveInstallName = "saveinstall"
@@ -65,7 +66,16 @@ func run(ctx context.Context) (err error) {
 	)
 
 	if cfg.JSONOutput {
-		err = json.NewEncoder(out).Encode(info)
+		err = json.NewEncoder(out).Encode(schema.BuildInfo{
+			Name:              info.Name,
+			Version:           info.Version.String(),
+			Commit:            info.Commit,
+			BuildDate:         info.BuildDate,
+			OS:                info.OS,
+			Architecture:      info.Architecture,
+			Environment:       info.Environment,
+			JSONSchemaVersion: schema.Version,
+		})
 	} else {
 		_, err = fmt.Fprintln(out, info)
 	}
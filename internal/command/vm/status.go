@@ -8,6 +8,7 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 	"github.com/superfly/flyctl/iostreams"
@@ -59,6 +60,10 @@ func runStatus(ctx context.Context) (err error) {
 		return fmt.Errorf("failed to fetch allocation status: %w", err)
 	}
 
+	if config.FromContext(ctx).JSONOutput {
+		return render.JSON(io.Out, alloc)
+	}
+
 	if err = render.AllocationStatus(io.Out, "Instance", alloc); err != nil {
 		return
 	}
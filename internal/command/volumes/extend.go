@@ -10,6 +10,7 @@ import (
 	"github.com/superfly/flyctl/iostreams"
 
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
 	"github.com/superfly/flyctl/internal/config"
@@ -20,9 +21,9 @@ import (
 
 func newExtend() *cobra.Command {
 	const (
-		long = `Extends a target volume to the size specified. Volumes with attached nomad allocations
-		will be restarted automatically. Machines will require a manual restart to increase the size
-		of the FS.`
+		long = `Extends a target volume to the size specified. Volumes can only grow. Volumes with
+		attached nomad allocations will be restarted automatically. The filesystem of a volume attached
+		to a running machine is grown in place, without restarting the machine.`
 
 		short = "Extend a target volume"
 
@@ -73,6 +74,15 @@ func runExtend(ctx context.Context) error {
 		return fmt.Errorf("Volume size must be specified")
 	}
 
+	current, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volume: %w", err)
+	}
+
+	if sizeGB <= current.SizeGb {
+		return fmt.Errorf("volume %s is already %dGB; volumes can only be extended to a larger size", volID, current.SizeGb)
+	}
+
 	if app.PlatformVersion == "nomad" {
 		if !flag.GetBool(ctx, "auto-confirm") {
 			switch confirmed, err := prompt.Confirm(ctx, "Extending this volume will result in a VM restart. Continue?"); {
@@ -90,7 +100,7 @@ func runExtend(ctx context.Context) error {
 
 	input := api.ExtendVolumeInput{
 		VolumeID: volID,
-		SizeGb:   flag.GetInt(ctx, "size"),
+		SizeGb:   sizeGB,
 	}
 
 	volume, err := client.ExtendVolume(ctx, input)
@@ -108,9 +118,64 @@ func runExtend(ctx context.Context) error {
 		return err
 	}
 
-	if app.PlatformVersion == "machines" {
-		fmt.Fprintln(out, colorize.Yellow("You will need to stop and start your machine to increase the size of the FS"))
+	if app.PlatformVersion == "machines" && current.AttachedMachine != nil {
+		if err := growFilesystem(ctx, appName, current.AttachedMachine.ID, volID); err != nil {
+			fmt.Fprintln(io.ErrOut, colorize.Yellow(fmt.Sprintf("Failed growing the filesystem online: %v", err)))
+			fmt.Fprintln(out, colorize.Yellow("You will need to stop and start your machine to increase the size of the FS"))
+		}
+	}
+
+	return nil
+}
+
+// growFilesystem grows the filesystem of the volume to the size of its
+// device on the running machine it's attached to. Stopped machines grow it
+// when they next start.
+func growFilesystem(ctx context.Context, appName, machineID, volID string) error {
+	out := iostreams.FromContext(ctx).Out
+
+	app, err := client.FromContext(ctx).API().GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return err
+	}
+
+	machine, err := flapsClient.Get(ctx, machineID)
+	if err != nil {
+		return err
+	}
+
+	if machine.State != "started" {
+		fmt.Fprintf(out, "Machine %s isn't running; its filesystem will be grown when it next starts\n", machine.ID)
+		return nil
+	}
+
+	var path string
+	for _, mount := range machine.Config.Mounts {
+		if mount.Volume == volID {
+			path = mount.Path
+		}
+	}
+	if path == "" {
+		return fmt.Errorf("volume %s isn't mounted on machine %s", volID, machine.ID)
+	}
+
+	res, err := flapsClient.Exec(ctx, machine.ID, &api.MachineExecRequest{
+		Cmd: fmt.Sprintf("sh -c \"resize2fs $(findmnt -n -o SOURCE %s)\"", path),
+	})
+	if err != nil {
+		return err
+	}
+
+	if res.ExitCode != 0 {
+		return fmt.Errorf("resize2fs exited with non-zero status of %d: %s", res.ExitCode, res.StdErr)
 	}
 
+	fmt.Fprintf(out, "Grew the filesystem mounted at %s on machine %s\n", path, machine.ID)
+
 	return nil
 }
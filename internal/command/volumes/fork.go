@@ -0,0 +1,99 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func newFork() *cobra.Command {
+	const (
+		long = `Fork the specified volume, creating a copy-on-write duplicate of it and its data
+in the same region. The fork may belong to the app of the volume or, with --target-app,
+to another app of the same organization, such as to seed staging with production data.
+
+Forking needs the API to support it, which it doesn't everywhere yet.`
+
+		short = "Fork the specified volume"
+
+		usage = "fork <id>"
+	)
+
+	cmd := command.New(usage, short, long, runFork,
+		command.RequireAPI("Mutations", "forkVolume"),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "name",
+			Description: "Name of the new volume. Defaults to the name of the forked volume",
+		},
+		flag.String{
+			Name:        "target-app",
+			Description: "App to create the new volume for. Defaults to the app of the forked volume",
+		},
+	)
+
+	return cmd
+}
+
+func runFork(ctx context.Context) error {
+	var (
+		cfg    = config.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		volID  = flag.FirstArg(ctx)
+	)
+
+	source, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volume: %w", err)
+	}
+
+	sourceApp, err := client.GetAppCompact(ctx, source.App.Name)
+	if err != nil {
+		return err
+	}
+
+	targetApp := sourceApp
+	if name := flag.GetString(ctx, "target-app"); name != "" && name != sourceApp.Name {
+		if targetApp, err = client.GetAppCompact(ctx, name); err != nil {
+			return err
+		}
+
+		if targetApp.Organization.Slug != sourceApp.Organization.Slug {
+			return fmt.Errorf("volumes can only be forked to apps of the same organization; %s belongs to %s, but %s belongs to %s",
+				sourceApp.Name, sourceApp.Organization.Slug, targetApp.Name, targetApp.Organization.Slug)
+		}
+	}
+
+	input := api.ForkVolumeInput{
+		AppID:       targetApp.ID,
+		SourceVolID: source.ID,
+		Name:        flag.GetString(ctx, "name"),
+	}
+
+	volume, err := client.ForkVolume(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to fork volume: %w", err)
+	}
+
+	out := iostreams.FromContext(ctx).Out
+
+	if cfg.JSONOutput {
+		return render.JSON(out, volume)
+	}
+
+	return printVolume(out, volume)
+}
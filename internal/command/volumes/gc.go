@@ -0,0 +1,199 @@
+package volumes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func newGC() *cobra.Command {
+	const (
+		long = `List the orphaned volumes of an app, or of every app of an organization with
+--org, and offer to delete them. Volumes are orphaned when they're neither attached
+to a VM nor named by a mount of the app's config.`
+
+		short = "Find and delete orphaned volumes"
+	)
+
+	cmd := command.New("gc", short, long, runGC,
+		command.RequireSession,
+		command.LoadAppNameIfPresent,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Org(),
+		flag.Bool{
+			Name:        "auto-confirm",
+			Description: "Will automatically confirm changes without an interactive prompt.",
+		},
+	)
+
+	return cmd
+}
+
+type orphanedVolume struct {
+	AppName string
+	api.Volume
+}
+
+func runGC(ctx context.Context) error {
+	var (
+		cfg     = config.FromContext(ctx)
+		io      = iostreams.FromContext(ctx)
+		client  = client.FromContext(ctx).API()
+		appName = app.NameFromContext(ctx)
+		orgSlug = flag.GetOrg(ctx)
+	)
+
+	var appNames []string
+	switch {
+	case orgSlug != "":
+		apps, err := client.GetOrganizationApps(ctx, orgSlug)
+		if err != nil {
+			return fmt.Errorf("failed retrieving apps: %w", err)
+		}
+
+		for _, app := range apps {
+			appNames = append(appNames, app.Name)
+		}
+	case appName != "":
+		appNames = []string{appName}
+	default:
+		return errors.New("an app or an organization must be specified")
+	}
+
+	var orphans []orphanedVolume
+	for _, name := range appNames {
+		found, err := findOrphanedVolumes(ctx, name)
+		if err != nil {
+			return err
+		}
+		orphans = append(orphans, found...)
+	}
+
+	if cfg.JSONOutput {
+		return render.JSON(io.Out, orphans)
+	}
+
+	if len(orphans) == 0 {
+		fmt.Fprintln(io.Out, "No orphaned volumes found")
+		return nil
+	}
+
+	var totalGb int
+	rows := make([][]string, 0, len(orphans))
+	for _, orphan := range orphans {
+		rows = append(rows, []string{
+			orphan.ID,
+			orphan.AppName,
+			orphan.Name,
+			fmt.Sprintf("%dGB", orphan.SizeGb),
+			orphan.Region,
+			humanize.Time(orphan.CreatedAt),
+		})
+
+		totalGb += orphan.SizeGb
+	}
+
+	if err := render.Table(io.Out, "Orphaned Volumes", rows, "ID", "App", "Name", "Size", "Region", "Created At"); err != nil {
+		return err
+	}
+
+	if !flag.GetBool(ctx, "auto-confirm") {
+		msg := fmt.Sprintf("Delete these %d volumes, totaling %dGB? This is not reversible.", len(orphans), totalGb)
+
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("auto-confirm flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	for _, orphan := range orphans {
+		if _, err := client.DeleteVolume(ctx, orphan.ID); err != nil {
+			return fmt.Errorf("failed deleting volume %s: %w", orphan.ID, err)
+		}
+
+		fmt.Fprintf(io.Out, "Deleted volume %s from %s\n", orphan.ID, orphan.AppName)
+	}
+
+	return nil
+}
+
+// findOrphanedVolumes returns the volumes of the app which are neither
+// attached nor named by any mount of its config.
+func findOrphanedVolumes(ctx context.Context, appName string) (orphans []orphanedVolume, err error) {
+	client := client.FromContext(ctx).API()
+
+	volumes, err := client.GetVolumes(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving volumes of %s: %w", appName, err)
+	}
+
+	if len(volumes) == 0 {
+		return
+	}
+
+	cfg, err := client.GetConfig(ctx, appName)
+	if err != nil {
+		return nil, fmt.Errorf("failed retrieving config of %s: %w", appName, err)
+	}
+	sources := mountSources(cfg.Definition["mounts"])
+
+	for _, volume := range volumes {
+		if volume.AttachedMachine != nil || volume.AttachedAllocation != nil || lo.Contains(sources, volume.Name) {
+			continue
+		}
+
+		orphans = append(orphans, orphanedVolume{AppName: appName, Volume: volume})
+	}
+
+	return
+}
+
+// mountSources returns the volume names the mounts section of an app config
+// definition refers to. It may be a single table or an array of them.
+func mountSources(raw interface{}) (sources []string) {
+	var tables []interface{}
+
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		tables = append(tables, raw)
+	case []interface{}:
+		tables = raw
+	}
+
+	for _, table := range tables {
+		if table, ok := table.(map[string]interface{}); ok {
+			if source, ok := table["source"].(string); ok && source != "" {
+				sources = append(sources, source)
+			}
+		}
+	}
+
+	return
+}
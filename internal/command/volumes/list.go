@@ -13,7 +13,6 @@ import (
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/app"
 	"github.com/superfly/flyctl/internal/command"
-	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 )
@@ -33,13 +32,13 @@ func newList() *cobra.Command {
 	flag.Add(cmd,
 		flag.App(),
 		flag.AppConfig(),
+		flag.Format(),
 	)
 
 	return cmd
 }
 
 func runList(ctx context.Context) error {
-	cfg := config.FromContext(ctx)
 	client := client.FromContext(ctx).API()
 
 	appName := app.NameFromContext(ctx)
@@ -51,8 +50,8 @@ func runList(ctx context.Context) error {
 
 	out := iostreams.FromContext(ctx).Out
 
-	if cfg.JSONOutput {
-		return render.JSON(out, volumes)
+	if ok, err := render.Structured(ctx, out, volumes); ok {
+		return err
 	}
 
 	rows := make([][]string, 0, len(volumes))
@@ -0,0 +1,171 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/volumes/snapshots"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/prompt"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func newRotateKey() *cobra.Command {
+	const (
+		long = `Re-encrypt a volume with a fresh encryption key. The volume is snapshotted and
+the snapshot restored into a new encrypted volume, which replaces the volume on the
+machine it's attached to. The machine must be stopped. The old volume is deleted
+once the new one is in place, unless --keep-old is set.`
+
+		short = "Re-encrypt a volume with a fresh key"
+
+		usage = "rotate-key <id>"
+	)
+
+	cmd := command.New(usage, short, long, runRotateKey,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.Yes(),
+		flag.Bool{
+			Name:        "keep-old",
+			Description: "Keep the old volume rather than deleting it",
+		},
+	)
+
+	return cmd
+}
+
+func runRotateKey(ctx context.Context) (err error) {
+	var (
+		io     = iostreams.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		volID  = flag.FirstArg(ctx)
+	)
+
+	volume, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volume: %w", err)
+	}
+
+	app, err := client.GetAppCompact(ctx, volume.App.Name)
+	if err != nil {
+		return err
+	}
+
+	var (
+		flapsClient *flaps.Client
+		machine     *api.Machine
+	)
+
+	if volume.AttachedMachine != nil {
+		if flapsClient, err = flaps.New(ctx, app); err != nil {
+			return fmt.Errorf("could not make flaps client: %w", err)
+		}
+
+		if machine, err = flapsClient.Get(ctx, volume.AttachedMachine.ID); err != nil {
+			return err
+		}
+
+		if machine.State != "stopped" {
+			return fmt.Errorf("machine %s must be stopped to rotate the key of its volume, but is %s", machine.ID, machine.State)
+		}
+	} else if app.PlatformVersion != "machines" {
+		return fmt.Errorf("key rotation is only available for volumes of machines apps")
+	}
+
+	if !flag.GetYes(ctx) {
+		msg := fmt.Sprintf("Volume %s will be replaced by a new volume with a new ID. Continue?", volID)
+
+		switch confirmed, err := prompt.Confirm(ctx, msg); {
+		case err == nil:
+			if !confirmed {
+				return nil
+			}
+		case prompt.IsNonInteractive(err):
+			return prompt.NonInteractiveError("yes flag must be specified when not running interactively")
+		default:
+			return err
+		}
+	}
+
+	tb := render.NewTextBlock(ctx, fmt.Sprintf("Rotating the encryption key of volume %s", volID))
+
+	tb.Detail("Snapshotting volume")
+
+	existing, err := client.GetVolumeSnapshots(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving snapshots of volume: %w", err)
+	}
+
+	if err = client.CreateVolumeSnapshot(ctx, volID); err != nil {
+		return fmt.Errorf("failed snapshotting volume: %w", err)
+	}
+
+	snapshot, err := snapshots.WaitFor(ctx, volID, existing)
+	if err != nil {
+		return err
+	}
+	snapshotID := snapshot.ID
+
+	tb.Detailf("Restoring snapshot %s into a new encrypted volume", snapshotID)
+
+	rotated, err := client.CreateVolume(ctx, api.CreateVolumeInput{
+		AppID:      app.ID,
+		Name:       volume.Name,
+		Region:     volume.Region,
+		SizeGb:     volume.SizeGb,
+		Encrypted:  true,
+		SnapshotID: api.StringPointer(snapshotID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed restoring snapshot: %w", err)
+	}
+
+	if machine != nil {
+		tb.Detailf("Attaching volume %s to machine %s", rotated.ID, machine.ID)
+
+		config := machine.Config
+		for i, mount := range config.Mounts {
+			if mount.Volume == volID {
+				config.Mounts[i].Volume = rotated.ID
+				config.Mounts[i].Encrypted = true
+			}
+		}
+
+		input := api.LaunchMachineInput{
+			ID:     machine.ID,
+			AppID:  app.Name,
+			Name:   machine.Name,
+			Region: machine.Region,
+			Config: config,
+		}
+
+		if _, err = flapsClient.Update(ctx, input, ""); err != nil {
+			return fmt.Errorf("failed attaching volume %s to machine %s; volume %s is left untouched: %w", rotated.ID, machine.ID, volID, err)
+		}
+	}
+
+	if !flag.GetBool(ctx, "keep-old") {
+		tb.Detailf("Deleting volume %s", volID)
+
+		if _, err = client.DeleteVolume(ctx, volID); err != nil {
+			return fmt.Errorf("failed deleting volume %s: %w", volID, err)
+		}
+	}
+
+	tb.Donef("Volume %s replaced by %s, encrypted with a new key", volID, rotated.ID)
+
+	return printVolume(io.Out, rotated)
+}
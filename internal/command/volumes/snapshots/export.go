@@ -0,0 +1,428 @@
+package snapshots
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/ioutils"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/agent"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/command/ssh"
+	"github.com/superfly/flyctl/internal/env"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+	"github.com/superfly/flyctl/internal/s3"
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// exportPartSize is the size of the parts snapshots are uploaded in.
+const exportPartSize = 16 << 20
+
+// exportState tracks the progress of a multipart upload so that an
+// interrupted export can be resumed.
+type exportState struct {
+	UploadID string    `json:"upload_id"`
+	Offset   int64     `json:"offset"`
+	Parts    []s3.Part `json:"parts"`
+	Hash     []byte    `json:"hash"`
+}
+
+func newExport() *cobra.Command {
+	const (
+		long = `Export the contents of a snapshot of the specified volume, either to a local
+file or to a bucket of S3-compatible storage. Uploads to S3 use the credentials in
+AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY and are made in parts; an interrupted
+export resumes where it left off when run again. The checksum of the exported
+contents is verified against the digest of the snapshot.
+
+With --tarball, the files of the snapshot are exported to --output as a gzipped
+tarball instead. The snapshot is restored into a throwaway volume mounted on an
+ephemeral machine, and the tarball is streamed from it over the agent tunnel.
+Tarball exports aren't resumed, and as their contents differ from the snapshot's,
+only their own checksum is reported.
+`
+		short = "Export a snapshot to a local file or S3"
+
+		usage = "export <volume-id> <snapshot-id>"
+	)
+
+	cmd := command.New(usage, short, long, runExport,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "output",
+			Shorthand:   "o",
+			Description: "Path of the local file to export to",
+		},
+		flag.Bool{
+			Name:        "tarball",
+			Description: "Export the files of the snapshot to --output as a gzipped tarball, over the agent tunnel",
+		},
+		flag.String{
+			Name:        "bucket",
+			Description: "S3 bucket to export to",
+		},
+		flag.String{
+			Name:        "key",
+			Description: "Key of the exported object. Defaults to <volume-name>/<snapshot-id>",
+		},
+		flag.String{
+			Name:        "endpoint",
+			Description: "URL of the S3-compatible service",
+			Default:     "https://s3.amazonaws.com",
+		},
+		flag.String{
+			Name:        "s3-region",
+			Description: "Region of the S3 bucket",
+			Default:     "us-east-1",
+		},
+	)
+
+	return cmd
+}
+
+func runExport(ctx context.Context) error {
+	var (
+		client     = client.FromContext(ctx).API()
+		volID      = flag.Args(ctx)[0]
+		snapshotID = flag.Args(ctx)[1]
+		output     = flag.GetString(ctx, "output")
+		bucket     = flag.GetString(ctx, "bucket")
+	)
+
+	if (output == "") == (bucket == "") {
+		return errors.New("either --output or --bucket must be specified")
+	}
+
+	if flag.GetBool(ctx, "tarball") {
+		if output == "" {
+			return errors.New("--tarball requires --output")
+		}
+
+		if err := findSnapshot(ctx, volID, snapshotID); err != nil {
+			return err
+		}
+
+		return exportTarball(ctx, volID, snapshotID, output)
+	}
+
+	snapshots, err := client.GetVolumeSnapshots(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving snapshots: %w", err)
+	}
+
+	var digest string
+	var found bool
+	for _, snapshot := range snapshots {
+		if snapshot.ID == snapshotID {
+			digest, found = strings.TrimPrefix(snapshot.Digest, "sha256:"), true
+		}
+	}
+	if !found {
+		return fmt.Errorf("snapshot %s not found for volume %s", snapshotID, volID)
+	}
+
+	if output != "" {
+		return exportToFile(ctx, snapshotID, digest, output)
+	}
+
+	key := flag.GetString(ctx, "key")
+	if key == "" {
+		volume, err := client.GetVolume(ctx, volID)
+		if err != nil {
+			return fmt.Errorf("failed retrieving volume: %w", err)
+		}
+		key = volume.Name + "/" + snapshotID
+	}
+
+	return exportToS3(ctx, snapshotID, digest, bucket, key)
+}
+
+// exportToFile downloads the snapshot to path, appending to what a previous
+// export already wrote there.
+func exportToFile(ctx context.Context, snapshotID, digest, path string) error {
+	out := iostreams.FromContext(ctx).Out
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// hash what's been exported already before resuming
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		fmt.Fprintf(out, "Resuming export from %s\n", humanize.IBytes(uint64(offset)))
+	}
+
+	body, size, err := client.FromContext(ctx).API().GetVolumeSnapshotContent(ctx, snapshotID, offset)
+	if err != nil {
+		return fmt.Errorf("failed downloading snapshot: %w", err)
+	}
+	defer body.Close()
+
+	if _, err = io.Copy(io.MultiWriter(f, h), body); err != nil {
+		return fmt.Errorf("failed downloading snapshot: %w", err)
+	}
+
+	if err = verifyChecksum(h, digest); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Exported %s of snapshot %s to %s\n", humanize.IBytes(uint64(size)), snapshotID, path)
+
+	return nil
+}
+
+// exportTarball restores the snapshot on an ephemeral machine and streams a
+// tarball of its files to path over SSH through the agent tunnel.
+func exportTarball(ctx context.Context, volID, snapshotID, path string) (err error) {
+	const mountPath = "/data"
+
+	tb := render.NewTextBlock(ctx, fmt.Sprintf("Restoring snapshot %s into a throwaway volume", snapshotID))
+
+	app, _, machine, cleanup, err := restoreOnEphemeralMachine(ctx, tb, volID, snapshotID, ephemeralMachine{
+		image:   "debian:bullseye-slim",
+		path:    mountPath,
+		group:   "snapshot_export",
+		timeout: 5 * time.Minute,
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	agentclient, err := agent.Establish(ctx, client.FromContext(ctx).API())
+	if err != nil {
+		return fmt.Errorf("failed establishing agent: %w", err)
+	}
+
+	dialer, err := agentclient.Dialer(ctx, app.Organization.Slug)
+	if err != nil {
+		return fmt.Errorf("failed establishing tunnel: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	tb.Detailf("Streaming a tarball of %s to %s", mountPath, path)
+
+	var (
+		h      = sha256.New()
+		stdout = &countingWriter{w: io.MultiWriter(f, h)}
+		stderr bytes.Buffer
+	)
+
+	err = ssh.SSHConnect(&ssh.SSHParams{
+		Ctx:            ctx,
+		Org:            app.Organization,
+		App:            app.Name,
+		Dialer:         dialer,
+		Cmd:            fmt.Sprintf("tar -czf - -C %s .", mountPath),
+		Stdin:          strings.NewReader(""),
+		Stdout:         ioutils.NewWriteCloserWrapper(stdout, func() error { return nil }),
+		Stderr:         ioutils.NewWriteCloserWrapper(&stderr, func() error { return nil }),
+		DisableSpinner: true,
+	}, machine.PrivateIP)
+	switch {
+	case err != nil:
+		return fmt.Errorf("failed exporting tarball: %w: %s", err, strings.TrimSpace(stderr.String()))
+	case stdout.err != nil:
+		return fmt.Errorf("failed writing %s: %w", path, stdout.err)
+	}
+
+	tb.Donef("Exported a %s tarball of snapshot %s to %s (sha256 %s)",
+		humanize.IBytes(uint64(stdout.n)), snapshotID, path, hex.EncodeToString(h.Sum(nil)))
+
+	return nil
+}
+
+// countingWriter counts the bytes written to w, and records the first error
+// writing them, which the SSH session would otherwise drop.
+type countingWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	if cw.err != nil {
+		return 0, cw.err
+	}
+
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	cw.err = err
+
+	return n, err
+}
+
+// exportToS3 uploads the snapshot to the bucket in parts, saving its progress
+// after each so that an interrupted export can be resumed.
+func exportToS3(ctx context.Context, snapshotID, digest, bucket, key string) (err error) {
+	s3Client := &s3.Client{
+		Endpoint:  flag.GetString(ctx, "endpoint"),
+		Region:    flag.GetString(ctx, "s3-region"),
+		AccessKey: env.First("AWS_ACCESS_KEY_ID"),
+		SecretKey: env.First("AWS_SECRET_ACCESS_KEY"),
+	}
+
+	if s3Client.AccessKey == "" || s3Client.SecretKey == "" {
+		return errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set to export to S3")
+	}
+
+	statePath := filepath.Join(state.ConfigDirectory(ctx), "snapshot_exports",
+		fmt.Sprintf("%s-%x.json", snapshotID, sha256.Sum256([]byte(bucket+"/"+key))))
+
+	st, h, err := loadExportState(statePath)
+	if err != nil {
+		return err
+	}
+
+	tb := render.NewTextBlock(ctx, fmt.Sprintf("Exporting snapshot %s to s3://%s/%s", snapshotID, bucket, key))
+
+	if st.UploadID == "" {
+		if st.UploadID, err = s3Client.CreateMultipartUpload(ctx, bucket, key); err != nil {
+			return fmt.Errorf("failed starting upload: %w", err)
+		}
+	} else {
+		tb.Detailf("Resuming export from %s", humanize.IBytes(uint64(st.Offset)))
+	}
+
+	body, size, err := client.FromContext(ctx).API().GetVolumeSnapshotContent(ctx, snapshotID, st.Offset)
+	if err != nil {
+		return fmt.Errorf("failed downloading snapshot: %w", err)
+	}
+	defer body.Close()
+
+	buf := make([]byte, exportPartSize)
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n > 0 {
+			number := len(st.Parts) + 1
+
+			etag, err := s3Client.UploadPart(ctx, bucket, key, st.UploadID, number, buf[:n])
+			if err != nil {
+				return fmt.Errorf("failed uploading part %d: %w", number, err)
+			}
+
+			h.Write(buf[:n])
+			st.Parts = append(st.Parts, s3.Part{PartNumber: number, ETag: etag})
+			st.Offset += int64(n)
+
+			if err := saveExportState(statePath, st, h); err != nil {
+				return err
+			}
+
+			tb.Detailf("Uploaded %s of %s", humanize.IBytes(uint64(st.Offset)), humanize.IBytes(uint64(size)))
+		}
+
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed downloading snapshot: %w", err)
+		}
+	}
+
+	if err = verifyChecksum(h, digest); err != nil {
+		_ = s3Client.AbortMultipartUpload(ctx, bucket, key, st.UploadID)
+		_ = os.Remove(statePath)
+
+		return err
+	}
+
+	if err = s3Client.CompleteMultipartUpload(ctx, bucket, key, st.UploadID, st.Parts); err != nil {
+		return fmt.Errorf("failed completing upload: %w", err)
+	}
+
+	_ = os.Remove(statePath)
+
+	tb.Donef("Exported %s of snapshot %s to s3://%s/%s", humanize.IBytes(uint64(st.Offset)), snapshotID, bucket, key)
+
+	return nil
+}
+
+func loadExportState(path string) (st exportState, h hash.Hash, err error) {
+	h = sha256.New()
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, h, nil
+	} else if err != nil {
+		return
+	}
+
+	if err = json.Unmarshal(data, &st); err != nil {
+		return st, h, fmt.Errorf("failed parsing export state %s: %w", path, err)
+	}
+
+	if err = h.(encoding.BinaryUnmarshaler).UnmarshalBinary(st.Hash); err != nil {
+		return st, h, fmt.Errorf("failed restoring export state %s: %w", path, err)
+	}
+
+	return
+}
+
+func saveExportState(path string, st exportState, h hash.Hash) (err error) {
+	if st.Hash, err = h.(encoding.BinaryMarshaler).MarshalBinary(); err != nil {
+		return
+	}
+
+	data, err := json.Marshal(st)
+	if err != nil {
+		return
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
+// verifyChecksum compares the checksum of the exported contents against the
+// digest of the snapshot, when it has one.
+func verifyChecksum(h hash.Hash, digest string) error {
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	if digest != "" && !strings.EqualFold(sum, digest) {
+		return fmt.Errorf("checksum mismatch: exported contents have sha256 %s, but the snapshot digest is %s", sum, digest)
+	}
+
+	return nil
+}
@@ -12,7 +12,6 @@ import (
 
 	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
-	"github.com/superfly/flyctl/internal/config"
 	"github.com/superfly/flyctl/internal/flag"
 	"github.com/superfly/flyctl/internal/render"
 )
@@ -31,13 +30,16 @@ func newList() *cobra.Command {
 
 	cmd.Args = cobra.ExactArgs(1)
 
+	flag.Add(cmd,
+		flag.Format(),
+	)
+
 	return cmd
 }
 
 func runList(ctx context.Context) error {
 	var (
 		io     = iostreams.FromContext(ctx)
-		cfg    = config.FromContext(ctx)
 		client = client.FromContext(ctx).API()
 	)
 
@@ -48,8 +50,8 @@ func runList(ctx context.Context) error {
 		return fmt.Errorf("failed retrieving snapshots: %w", err)
 	}
 
-	if cfg.JSONOutput {
-		return render.JSON(io.Out, snapshots)
+	if ok, err := render.Structured(ctx, io.Out, snapshots); ok {
+		return err
 	}
 
 	if len(snapshots) == 0 {
@@ -0,0 +1,211 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func newRestore() *cobra.Command {
+	const (
+		long = `Restore a snapshot of the specified volume into a new volume, optionally in
+a different region. The new volume can be attached straight away to an existing
+stopped machine with --attach, or to a new machine cloned from an existing one
+with --clone.
+`
+		short = "Restore a snapshot into a new volume"
+
+		usage = "restore <volume-id> <snapshot-id>"
+	)
+
+	cmd := command.New(usage, short, long, runRestore,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	flag.Add(cmd,
+		flag.Region(),
+		flag.String{
+			Name:        "name",
+			Description: "Name of the new volume. Defaults to the name of the snapshotted volume",
+		},
+		flag.Int{
+			Name:        "size",
+			Shorthand:   "s",
+			Description: "Size of the new volume in gigabytes. Defaults to the size of the snapshotted volume",
+		},
+		flag.String{
+			Name:        "attach",
+			Description: "ID of a stopped machine to attach the new volume to",
+		},
+		flag.String{
+			Name:        "clone",
+			Description: "ID of a machine to clone into a new machine with the new volume attached",
+		},
+		flag.String{
+			Name:        "mount-path",
+			Description: "Path to mount the new volume at. Defaults to the path the machine mounts a volume at",
+		},
+	)
+
+	return cmd
+}
+
+func runRestore(ctx context.Context) error {
+	var (
+		io         = iostreams.FromContext(ctx)
+		cfg        = config.FromContext(ctx)
+		client     = client.FromContext(ctx).API()
+		volID      = flag.Args(ctx)[0]
+		snapshotID = flag.Args(ctx)[1]
+		attachID   = flag.GetString(ctx, "attach")
+		cloneID    = flag.GetString(ctx, "clone")
+	)
+
+	if attachID != "" && cloneID != "" {
+		return fmt.Errorf("--attach and --clone are mutually exclusive")
+	}
+
+	source, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volume: %w", err)
+	}
+
+	if err := findSnapshot(ctx, volID, snapshotID); err != nil {
+		return err
+	}
+
+	app, err := client.GetAppCompact(ctx, source.App.Name)
+	if err != nil {
+		return err
+	}
+
+	input := api.CreateVolumeInput{
+		AppID:      app.ID,
+		Name:       source.Name,
+		Region:     source.Region,
+		SizeGb:     source.SizeGb,
+		Encrypted:  source.Encrypted,
+		SnapshotID: api.StringPointer(snapshotID),
+	}
+	if name := flag.GetString(ctx, "name"); name != "" {
+		input.Name = name
+	}
+	if region := flag.GetString(ctx, flag.RegionName); region != "" {
+		input.Region = region
+	}
+	if size := flag.GetInt(ctx, "size"); size != 0 {
+		if size < source.SizeGb {
+			return fmt.Errorf("size must be at least the %dGB of the snapshotted volume", source.SizeGb)
+		}
+		input.SizeGb = size
+	}
+
+	volume, err := client.CreateVolume(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed restoring snapshot: %w", err)
+	}
+
+	if attachID == "" && cloneID == "" {
+		if cfg.JSONOutput {
+			return render.JSON(io.Out, volume)
+		}
+
+		fmt.Fprintf(io.Out, "Restored snapshot %s into volume %s (%s) in region %s\n", snapshotID, volume.ID, volume.Name, volume.Region)
+
+		return nil
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	machineID := attachID
+	if machineID == "" {
+		machineID = cloneID
+	}
+
+	machine, err := flapsClient.Get(ctx, machineID)
+	if err != nil {
+		return err
+	}
+
+	if attachID != "" && machine.State != "stopped" {
+		return fmt.Errorf("machine %s must be stopped to attach a volume to it, but is %s", machine.ID, machine.State)
+	}
+
+	if attachID != "" && machine.Region != volume.Region {
+		return fmt.Errorf("machine %s is in region %s but the new volume is in %s", machine.ID, machine.Region, volume.Region)
+	}
+
+	path := flag.GetString(ctx, "mount-path")
+	if path == "" && len(machine.Config.Mounts) > 0 {
+		path = machine.Config.Mounts[0].Path
+	}
+	if path == "" {
+		return fmt.Errorf("machine %s doesn't mount a volume; specify where to mount it with --mount-path", machine.ID)
+	}
+
+	config := machine.Config
+	config.Mounts = []api.MachineMount{{
+		Volume:    volume.ID,
+		Path:      path,
+		SizeGb:    volume.SizeGb,
+		Encrypted: volume.Encrypted,
+	}}
+
+	launchInput := api.LaunchMachineInput{
+		AppID:  app.Name,
+		Region: volume.Region,
+		Config: config,
+	}
+
+	if attachID != "" {
+		launchInput.ID = machine.ID
+		launchInput.Name = machine.Name
+
+		if machine, err = flapsClient.Update(ctx, launchInput, ""); err != nil {
+			return fmt.Errorf("failed attaching volume %s to machine %s: %w", volume.ID, attachID, err)
+		}
+	} else if machine, err = flapsClient.Launch(ctx, launchInput); err != nil {
+		return fmt.Errorf("failed cloning machine %s: %w", cloneID, err)
+	}
+
+	if cfg.JSONOutput {
+		return render.JSON(io.Out, map[string]interface{}{"volume": volume, "machine": machine})
+	}
+
+	fmt.Fprintf(io.Out, "Restored snapshot %s into volume %s (%s) in region %s\n", snapshotID, volume.ID, volume.Name, volume.Region)
+	fmt.Fprintf(io.Out, "Volume is mounted at %s on machine %s\n", path, machine.ID)
+
+	return nil
+}
+
+// findSnapshot returns an error unless the given snapshot belongs to the volume.
+func findSnapshot(ctx context.Context, volID, snapshotID string) error {
+	snapshots, err := client.FromContext(ctx).API().GetVolumeSnapshots(ctx, volID)
+	if err != nil {
+		return fmt.Errorf("failed retrieving snapshots: %w", err)
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.ID == snapshotID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("snapshot %s not found for volume %s", snapshotID, volID)
+}
@@ -0,0 +1,171 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+// snapshotFrequencies maps the supported snapshot frequencies to the interval
+// between snapshots.
+var snapshotFrequencies = map[string]time.Duration{
+	"hourly": time.Hour,
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+}
+
+func newSchedule() *cobra.Command {
+	const (
+		long = `Show or configure how often snapshots of the specified volume are taken and
+how long they're retained. Each rule takes the form FREQUENCY:RETENTION, where
+FREQUENCY is hourly, daily or weekly and RETENTION is a number of hours (h),
+days (d) or weeks (w). For example, --rule hourly:24h --rule daily:30d.
+Without rules, the current schedule is shown.
+
+Snapshot schedules need the API to serve them, which it doesn't everywhere yet.
+`
+		short = "Show or configure the snapshot schedule of a volume"
+
+		usage = "schedule <volume-id>"
+	)
+
+	cmd := command.New(usage, short, long, runSchedule,
+		command.RequireAPI("Volume", "snapshotSchedule"),
+	)
+
+	cmd.Args = cobra.ExactArgs(1)
+
+	flag.Add(cmd,
+		flag.StringSlice{
+			Name:        "rule",
+			Description: "Snapshot rule in the form FREQUENCY:RETENTION. Can be specified multiple times.",
+		},
+		flag.Bool{
+			Name:        "reset",
+			Description: "Restore the default daily snapshot schedule",
+		},
+	)
+
+	return cmd
+}
+
+func runSchedule(ctx context.Context) (err error) {
+	var (
+		io     = iostreams.FromContext(ctx)
+		cfg    = config.FromContext(ctx)
+		client = client.FromContext(ctx).API()
+		volID  = flag.FirstArg(ctx)
+		rules  []api.SnapshotScheduleRule
+	)
+
+	specs := flag.GetStringSlice(ctx, "rule")
+
+	switch reset := flag.GetBool(ctx, "reset"); {
+	case reset && len(specs) > 0:
+		return fmt.Errorf("--reset and --rule are mutually exclusive")
+	case reset:
+		rules, err = client.SetVolumeSnapshotSchedule(ctx, volID, nil)
+	case len(specs) > 0:
+		var parsed []api.SnapshotScheduleRule
+		if parsed, err = parseScheduleRules(specs); err != nil {
+			return err
+		}
+		rules, err = client.SetVolumeSnapshotSchedule(ctx, volID, parsed)
+	default:
+		rules, err = client.GetVolumeSnapshotSchedule(ctx, volID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed updating snapshot schedule: %w", err)
+	}
+
+	if cfg.JSONOutput {
+		return render.JSON(io.Out, rules)
+	}
+
+	if len(rules) == 0 {
+		fmt.Fprintf(io.Out, "Volume %s uses the default schedule of daily snapshots\n", volID)
+		return nil
+	}
+
+	rows := make([][]string, 0, len(rules))
+	for _, rule := range rules {
+		rows = append(rows, []string{
+			rule.Frequency,
+			rule.Retention,
+		})
+	}
+
+	return render.Table(io.Out, "Snapshot Schedule", rows, "Frequency", "Retention")
+}
+
+func parseScheduleRules(specs []string) ([]api.SnapshotScheduleRule, error) {
+	rules := make([]api.SnapshotScheduleRule, 0, len(specs))
+	seen := map[string]bool{}
+
+	for _, spec := range specs {
+		frequency, retention, ok := strings.Cut(spec, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule %q; rules take the form FREQUENCY:RETENTION", spec)
+		}
+
+		interval, ok := snapshotFrequencies[frequency]
+		if !ok {
+			return nil, fmt.Errorf("invalid frequency %q; use hourly, daily or weekly", frequency)
+		}
+
+		if seen[frequency] {
+			return nil, fmt.Errorf("frequency %s is specified more than once", frequency)
+		}
+		seen[frequency] = true
+
+		keep, err := parseRetention(retention)
+		if err != nil {
+			return nil, err
+		}
+
+		if keep < interval {
+			return nil, fmt.Errorf("retention %s of %s snapshots is shorter than the time between them", retention, frequency)
+		}
+
+		rules = append(rules, api.SnapshotScheduleRule{
+			Frequency: frequency,
+			Retention: retention,
+		})
+	}
+
+	return rules, nil
+}
+
+// parseRetention parses retention periods such as 24h, 30d or 4w.
+func parseRetention(s string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid retention %q; use a number of hours (h), days (d) or weeks (w)", s)
+	}
+
+	unit, ok := units[s[len(s)-1]]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if !ok || err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid retention %q; use a number of hours (h), days (d) or weeks (w)", s)
+	}
+
+	return time.Duration(n) * unit, nil
+}
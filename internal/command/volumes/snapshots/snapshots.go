@@ -1,8 +1,14 @@
 package snapshots
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
 	"github.com/superfly/flyctl/internal/command"
 )
 
@@ -22,7 +28,46 @@ func New() *cobra.Command {
 
 	snapshots.AddCommand(
 		newList(),
+		newSchedule(),
+		newRestore(),
+		newExport(),
+		newVerify(),
 	)
 
 	return snapshots
 }
+
+// WaitFor polls the snapshots of the given volume until one not among
+// existing, the snapshots it had before one was requested, shows up, for up
+// to 5 minutes, and returns it. Snapshots are told apart by ID rather than by
+// creation time, since the clocks of flyctl and the API may differ.
+func WaitFor(ctx context.Context, volID string, existing []api.Snapshot) (*api.Snapshot, error) {
+	client := client.FromContext(ctx).API()
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	known := make(map[string]bool, len(existing))
+	for _, snapshot := range existing {
+		known[snapshot.ID] = true
+	}
+
+	for {
+		snapshots, err := client.GetVolumeSnapshots(ctx, volID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, snapshot := range snapshots {
+			if !known[snapshot.ID] {
+				return &snapshot, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("timed out waiting for snapshot of volume %s", volID)
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,272 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+func newVerify() *cobra.Command {
+	const (
+		long = `Verify that a snapshot of the specified volume can be restored. The snapshot is
+restored into a throwaway volume which is mounted on an ephemeral machine. The
+optional --command is run against the mounted volume, after which the filesystem
+is checked for errors. The machine and volume are destroyed afterwards.
+`
+		short = "Verify that a snapshot can be restored"
+
+		usage = "verify <volume-id> <snapshot-id>"
+	)
+
+	cmd := command.New(usage, short, long, runVerify,
+		command.RequireSession,
+	)
+
+	cmd.Args = cobra.ExactArgs(2)
+
+	flag.Add(cmd,
+		flag.String{
+			Name:        "command",
+			Description: "Command to run on the machine with the restored volume mounted. It passes if it exits with status 0",
+		},
+		flag.String{
+			Name:        "image",
+			Description: "Image of the ephemeral machine. It must provide sh, findmnt and e2fsck",
+			Default:     "debian:bullseye-slim",
+		},
+		flag.String{
+			Name:        "mount-path",
+			Description: "Path to mount the restored volume at",
+			Default:     "/data",
+		},
+		flag.Duration{
+			Name:        "timeout",
+			Description: "How long to wait for the ephemeral machine to start",
+			Default:     5 * time.Minute,
+		},
+	)
+
+	return cmd
+}
+
+type verification struct {
+	Check    string `json:"check"`
+	Passed   bool   `json:"passed"`
+	ExitCode int32  `json:"exit_code"`
+	Output   string `json:"output,omitempty"`
+}
+
+func runVerify(ctx context.Context) (err error) {
+	var (
+		io         = iostreams.FromContext(ctx)
+		cfg        = config.FromContext(ctx)
+		volID      = flag.Args(ctx)[0]
+		snapshotID = flag.Args(ctx)[1]
+		path       = flag.GetString(ctx, "mount-path")
+	)
+
+	if err := findSnapshot(ctx, volID, snapshotID); err != nil {
+		return err
+	}
+
+	tb := render.NewTextBlock(ctx, fmt.Sprintf("Restoring snapshot %s into a throwaway volume", snapshotID))
+
+	_, flapsClient, machine, cleanup, err := restoreOnEphemeralMachine(ctx, tb, volID, snapshotID, ephemeralMachine{
+		image:   flag.GetString(ctx, "image"),
+		path:    path,
+		group:   "snapshot_verify",
+		timeout: flag.GetDuration(ctx, "timeout"),
+	})
+	defer cleanup()
+	if err != nil {
+		return err
+	}
+
+	var checks []verification
+
+	if command := flag.GetString(ctx, "command"); command != "" {
+		tb.Detailf("Running %q", command)
+
+		check, err := verify(ctx, flapsClient, machine.ID, command, command)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, check)
+	}
+
+	tb.Detail("Checking the filesystem")
+
+	// the filesystem has to be unmounted for e2fsck to give reliable results
+	fsck := fmt.Sprintf("sh -c \"dev=$(findmnt -n -o SOURCE %s) && umount %s && e2fsck -fn $dev\"", path, path)
+
+	check, err := verify(ctx, flapsClient, machine.ID, "filesystem", fsck)
+	if err != nil {
+		return err
+	}
+	checks = append(checks, check)
+
+	passed := true
+	for _, check := range checks {
+		passed = passed && check.Passed
+	}
+
+	if cfg.JSONOutput {
+		if err := render.JSON(io.Out, map[string]interface{}{"snapshot": snapshotID, "passed": passed, "checks": checks}); err != nil {
+			return err
+		}
+	} else {
+		rows := make([][]string, 0, len(checks))
+		for _, check := range checks {
+			result := "pass"
+			if !check.Passed {
+				result = "fail"
+			}
+			rows = append(rows, []string{check.Check, result, fmt.Sprint(check.ExitCode)})
+		}
+
+		if err := render.Table(io.Out, "", rows, "Check", "Result", "Exit Code"); err != nil {
+			return err
+		}
+
+		for _, check := range checks {
+			if !check.Passed && check.Output != "" {
+				fmt.Fprintf(io.Out, "\nOutput of %s:\n%s\n", check.Check, check.Output)
+			}
+		}
+	}
+
+	if !passed {
+		return fmt.Errorf("snapshot %s failed verification", snapshotID)
+	}
+
+	tb.Donef("Snapshot %s passed verification", snapshotID)
+
+	return nil
+}
+
+func verify(ctx context.Context, flapsClient *flaps.Client, machineID, name, cmd string) (verification, error) {
+	res, err := flapsClient.Exec(ctx, machineID, &api.MachineExecRequest{Cmd: cmd})
+	if err != nil {
+		return verification{}, fmt.Errorf("failed running %s check: %w", name, err)
+	}
+
+	return verification{
+		Check:    name,
+		Passed:   res.ExitCode == 0,
+		ExitCode: res.ExitCode,
+		Output:   res.StdOut + res.StdErr,
+	}, nil
+}
+
+// ephemeralMachine describes the machine restoreOnEphemeralMachine launches.
+type ephemeralMachine struct {
+	image   string
+	path    string
+	group   string
+	timeout time.Duration
+}
+
+// restoreOnEphemeralMachine restores the snapshot into a throwaway volume and
+// launches a machine with it mounted, waiting for the machine to start. The
+// returned cleanup destroys both and must be called even on error.
+func restoreOnEphemeralMachine(ctx context.Context, tb *render.TextBlock, volID, snapshotID string, spec ephemeralMachine) (app *api.AppCompact, flapsClient *flaps.Client, machine *api.Machine, cleanup func(), err error) {
+	var (
+		io       = iostreams.FromContext(ctx)
+		client   = client.FromContext(ctx).API()
+		cleanups []func()
+	)
+
+	cleanup = func() {
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}
+
+	source, err := client.GetVolume(ctx, volID)
+	if err != nil {
+		err = fmt.Errorf("failed retrieving volume: %w", err)
+		return
+	}
+
+	if app, err = client.GetAppCompact(ctx, source.App.Name); err != nil {
+		return
+	}
+
+	if flapsClient, err = flaps.New(ctx, app); err != nil {
+		err = fmt.Errorf("could not make flaps client: %w", err)
+		return
+	}
+
+	volume, err := client.CreateVolume(ctx, api.CreateVolumeInput{
+		AppID:      app.ID,
+		Name:       source.Name,
+		Region:     source.Region,
+		SizeGb:     source.SizeGb,
+		Encrypted:  source.Encrypted,
+		SnapshotID: api.StringPointer(snapshotID),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed restoring snapshot: %w", err)
+		return
+	}
+
+	cleanups = append(cleanups, func() {
+		if _, delErr := client.DeleteVolume(context.Background(), volume.ID); delErr != nil {
+			fmt.Fprintf(io.ErrOut, "failed deleting throwaway volume %s: %v\n", volume.ID, delErr)
+		}
+	})
+
+	tb.Detailf("Launching an ephemeral machine with volume %s mounted at %s", volume.ID, spec.path)
+
+	machine, err = flapsClient.Launch(ctx, api.LaunchMachineInput{
+		AppID:  app.Name,
+		Region: volume.Region,
+		Config: &api.MachineConfig{
+			Image: spec.image,
+			Init: api.MachineInit{
+				Exec: []string{"sleep", "infinity"},
+			},
+			Metadata: map[string]string{api.MachineProcessGroupKey: spec.group},
+			Mounts: []api.MachineMount{{
+				Volume:    volume.ID,
+				Path:      spec.path,
+				SizeGb:    volume.SizeGb,
+				Encrypted: volume.Encrypted,
+			}},
+			Restart: api.MachineRestart{Policy: api.MachineRestartPolicyNo},
+			Guest:   api.MachinePresets["shared-cpu-1x"],
+		},
+	})
+	if err != nil {
+		err = fmt.Errorf("failed launching ephemeral machine: %w", err)
+		return
+	}
+
+	cleanups = append(cleanups, func() {
+		input := api.RemoveMachineInput{AppID: app.Name, ID: machine.ID, Kill: true}
+		if destroyErr := flapsClient.Destroy(context.Background(), input); destroyErr != nil {
+			fmt.Fprintf(io.ErrOut, "failed destroying ephemeral machine %s: %v\n", machine.ID, destroyErr)
+		}
+	})
+
+	waitCtx, cancel := context.WithTimeout(ctx, spec.timeout)
+	defer cancel()
+
+	if err = flapsClient.Wait(waitCtx, machine, "started"); err != nil {
+		err = fmt.Errorf("ephemeral machine %s failed to start: %w", machine.ID, err)
+	}
+
+	return
+}
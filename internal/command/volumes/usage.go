@@ -0,0 +1,236 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/iostreams"
+
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+	"github.com/superfly/flyctl/internal/render"
+)
+
+type volumeUsage struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	MachineID    string `json:"machine_id"`
+	Path         string `json:"path"`
+	SizeBytes    uint64 `json:"size_bytes"`
+	UsedBytes    uint64 `json:"used_bytes"`
+	Inodes       uint64 `json:"inodes"`
+	UsedInodes   uint64 `json:"used_inodes"`
+	UsedPercent  int    `json:"used_percent"`
+	InodePercent int    `json:"inode_percent"`
+	Warning      bool   `json:"warning"`
+	Error        string `json:"error,omitempty"`
+}
+
+func newUsage() *cobra.Command {
+	const (
+		long = `Report the used and provisioned space, as well as inode usage, of the volumes
+of the app by querying the running machines they're attached to. Volumes above the
+warning threshold are highlighted.`
+
+		short = "Show disk usage of the volumes for app"
+	)
+
+	cmd := command.New("usage", short, long, runUsage,
+		command.RequireSession,
+		command.RequireAppName,
+	)
+
+	cmd.Args = cobra.NoArgs
+
+	flag.Add(cmd,
+		flag.App(),
+		flag.AppConfig(),
+		flag.Int{
+			Name:        "threshold",
+			Description: "Percentage of space or inodes used above which to warn",
+			Default:     80,
+		},
+	)
+
+	return cmd
+}
+
+func runUsage(ctx context.Context) error {
+	var (
+		cfg       = config.FromContext(ctx)
+		io        = iostreams.FromContext(ctx)
+		colorize  = io.ColorScheme()
+		client    = client.FromContext(ctx).API()
+		appName   = app.NameFromContext(ctx)
+		threshold = flag.GetInt(ctx, "threshold")
+	)
+
+	app, err := client.GetAppCompact(ctx, appName)
+	if err != nil {
+		return err
+	}
+
+	if app.PlatformVersion != "machines" {
+		return fmt.Errorf("volume usage is only available for machines apps")
+	}
+
+	volumes, err := client.GetVolumes(ctx, appName)
+	if err != nil {
+		return fmt.Errorf("failed retrieving volumes: %w", err)
+	}
+
+	flapsClient, err := flaps.New(ctx, app)
+	if err != nil {
+		return fmt.Errorf("could not make flaps client: %w", err)
+	}
+
+	usages := make([]volumeUsage, 0, len(volumes))
+	for _, volume := range volumes {
+		usage := volumeUsage{
+			ID:        volume.ID,
+			Name:      volume.Name,
+			SizeBytes: uint64(volume.SizeGb) << 30,
+		}
+
+		if volume.AttachedMachine == nil {
+			usage.Error = "not attached"
+		} else {
+			usage.MachineID = volume.AttachedMachine.ID
+
+			if err := measureVolume(ctx, flapsClient, &usage); err != nil {
+				usage.Error = err.Error()
+			}
+		}
+
+		usage.Warning = usage.Error == "" && (usage.UsedPercent >= threshold || usage.InodePercent >= threshold)
+
+		usages = append(usages, usage)
+	}
+
+	if cfg.JSONOutput {
+		return render.JSON(io.Out, usages)
+	}
+
+	rows := make([][]string, 0, len(usages))
+	for _, usage := range usages {
+		if usage.Error != "" {
+			rows = append(rows, []string{usage.ID, usage.Name, usage.MachineID, "", "", "", "", usage.Error})
+			continue
+		}
+
+		used := fmt.Sprintf("%d%%", usage.UsedPercent)
+		inodes := fmt.Sprintf("%d%%", usage.InodePercent)
+		if usage.Warning {
+			used, inodes = colorize.Yellow(used), colorize.Yellow(inodes)
+		}
+
+		rows = append(rows, []string{
+			usage.ID,
+			usage.Name,
+			usage.MachineID,
+			humanize.IBytes(usage.UsedBytes),
+			humanize.IBytes(usage.SizeBytes),
+			used,
+			inodes,
+			"",
+		})
+	}
+
+	if err := render.Table(io.Out, "", rows, "ID", "Name", "Machine", "Used", "Size", "Use%", "Inode Use%", "Note"); err != nil {
+		return err
+	}
+
+	for _, usage := range usages {
+		if usage.Warning {
+			fmt.Fprintln(io.ErrOut, colorize.Yellow(fmt.Sprintf("Volume %s is above %d%% usage; consider extending it with `fly volumes extend`", usage.ID, threshold)))
+		}
+	}
+
+	return nil
+}
+
+// measureVolume runs df on the machine the volume is attached to.
+func measureVolume(ctx context.Context, flapsClient *flaps.Client, usage *volumeUsage) error {
+	machine, err := flapsClient.Get(ctx, usage.MachineID)
+	if err != nil {
+		return err
+	}
+
+	if machine.State != "started" {
+		return fmt.Errorf("machine %s", machine.State)
+	}
+
+	for _, mount := range machine.Config.Mounts {
+		if mount.Volume == usage.ID {
+			usage.Path = mount.Path
+		}
+	}
+	if usage.Path == "" {
+		return fmt.Errorf("not mounted")
+	}
+
+	blocks, err := df(ctx, flapsClient, machine.ID, "-Pk", usage.Path)
+	if err != nil {
+		return err
+	}
+	usage.SizeBytes, usage.UsedBytes = blocks[0]<<10, blocks[1]<<10
+	usage.UsedPercent = percent(blocks[1], blocks[0])
+
+	inodes, err := df(ctx, flapsClient, machine.ID, "-Pi", usage.Path)
+	if err != nil {
+		return err
+	}
+	usage.Inodes, usage.UsedInodes = inodes[0], inodes[1]
+	usage.InodePercent = percent(inodes[1], inodes[0])
+
+	return nil
+}
+
+// df runs df with the given flags for path on the machine, returning the
+// total and used columns of its output.
+func df(ctx context.Context, flapsClient *flaps.Client, machineID, flags, path string) ([2]uint64, error) {
+	var counts [2]uint64
+
+	res, err := flapsClient.Exec(ctx, machineID, &api.MachineExecRequest{
+		Cmd: fmt.Sprintf("df %s %s", flags, path),
+	})
+	if err != nil {
+		return counts, err
+	}
+
+	if res.ExitCode != 0 {
+		return counts, fmt.Errorf("df exited with non-zero status of %d: %s", res.ExitCode, res.StdErr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(res.StdOut), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) < 3 {
+		return counts, fmt.Errorf("unexpected df output: %q", res.StdOut)
+	}
+
+	for i := range counts {
+		if counts[i], err = strconv.ParseUint(fields[i+1], 10, 64); err != nil {
+			return counts, fmt.Errorf("unexpected df output: %q", res.StdOut)
+		}
+	}
+
+	return counts, nil
+}
+
+func percent(used, total uint64) int {
+	if total == 0 {
+		return 0
+	}
+
+	return int(used * 100 / total)
+}
@@ -32,6 +32,10 @@ func New() *cobra.Command {
 		newList(),
 		newDelete(),
 		newExtend(),
+		newFork(),
+		newUsage(),
+		newRotateKey(),
+		newGC(),
 		newShow(),
 		snapshots.New(),
 	)
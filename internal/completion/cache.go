@@ -0,0 +1,72 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// entry is a file of the completion cache.
+type entry struct {
+	At     time.Time `json:"at"`
+	Values []string  `json:"values"`
+}
+
+// cached returns the values of c, and of appName when c is per app, from
+// the completion cache when they're younger than the ttl of c, or from fetch,
+// caching them.
+func cached(ctx context.Context, c completer, appName string, fetch func() ([]string, error)) ([]string, error) {
+	name := c.key
+	if c.perApp {
+		name += "-" + appName
+	}
+
+	if strings.ContainsAny(name, `/\.`) {
+		return fetch()
+	}
+
+	path := filepath.Join(state.ConfigDirectory(ctx), "completion", name+".json")
+
+	if e, ok := readEntry(path); ok && time.Since(e.At) < c.ttl {
+		return e.Values, nil
+	}
+
+	values, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	// failing to cache only makes the next completion slower
+	_ = writeEntry(path, entry{At: time.Now(), Values: values})
+
+	return values, nil
+}
+
+func readEntry(path string) (e entry, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	ok = json.Unmarshal(data, &e) == nil
+
+	return
+}
+
+func writeEntry(path string, e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,310 @@
+// Package completion implements the dynamic completion of the arguments and
+// flags of commands with the names of apps, machines, volumes, organizations
+// and regions, as the API knows them.
+package completion
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/superfly/flyctl/api"
+	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/flaps"
+	"github.com/superfly/flyctl/internal/app"
+	"github.com/superfly/flyctl/internal/command"
+)
+
+// completer returns the values an argument or flag may take, optionally
+// followed by a tab and a description of each.
+type completer struct {
+	// key names the cached values, which are those of the app when perApp is
+	// set.
+	key    string
+	perApp bool
+	ttl    time.Duration
+	fetch  func(ctx context.Context, client *api.Client, appName string) ([]string, error)
+}
+
+var (
+	apps = completer{
+		key: "apps",
+		ttl: 5 * time.Minute,
+		fetch: func(ctx context.Context, client *api.Client, _ string) ([]string, error) {
+			apps, err := client.GetApps(ctx, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]string, 0, len(apps))
+			for _, a := range apps {
+				values = append(values, a.Name+"\t"+a.Organization.Slug)
+			}
+
+			return values, nil
+		},
+	}
+
+	orgs = completer{
+		key: "orgs",
+		ttl: 5 * time.Minute,
+		fetch: func(ctx context.Context, client *api.Client, _ string) ([]string, error) {
+			orgs, err := client.GetOrganizations(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]string, 0, len(orgs))
+			for _, org := range orgs {
+				values = append(values, org.Slug+"\t"+org.Name)
+			}
+
+			return values, nil
+		},
+	}
+
+	regions = completer{
+		key: "regions",
+		ttl: 24 * time.Hour,
+		fetch: func(ctx context.Context, client *api.Client, _ string) ([]string, error) {
+			regions, _, err := client.PlatformRegions(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]string, 0, len(regions))
+			for _, region := range regions {
+				values = append(values, region.Code+"\t"+region.Name)
+			}
+
+			return values, nil
+		},
+	}
+
+	machines = completer{
+		key:    "machines",
+		perApp: true,
+		ttl:    30 * time.Second,
+		fetch: func(ctx context.Context, client *api.Client, appName string) ([]string, error) {
+			appCompact, err := client.GetAppCompact(ctx, appName)
+			if err != nil {
+				return nil, err
+			}
+
+			flapsClient, err := flaps.New(ctx, appCompact)
+			if err != nil {
+				return nil, err
+			}
+
+			machines, err := flapsClient.ListActive(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]string, 0, len(machines))
+			for _, m := range machines {
+				values = append(values, fmt.Sprintf("%s\t%s (%s, %s)", m.ID, m.Name, m.State, m.Region))
+			}
+
+			return values, nil
+		},
+	}
+
+	volumes = completer{
+		key:    "volumes",
+		perApp: true,
+		ttl:    30 * time.Second,
+		fetch: func(ctx context.Context, client *api.Client, appName string) ([]string, error) {
+			volumes, err := client.GetVolumes(ctx, appName)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]string, 0, len(volumes))
+			for _, v := range volumes {
+				values = append(values, fmt.Sprintf("%s\t%s (%s, %dGB)", v.ID, v.Name, v.Region, v.SizeGb))
+			}
+
+			return values, nil
+		},
+	}
+)
+
+// flagCompleters are the completers of flags, by name.
+var flagCompleters = map[string]completer{
+	"app":    apps,
+	"org":    orgs,
+	"region": regions,
+}
+
+// Register sets up the completion of the flags and arguments of root and
+// its descendants which take apps, machines, volumes, organizations or
+// regions. Arguments are recognized by the placeholders of command usages,
+// like <id> under machine and volumes, [APPNAME], [slug] or REGION.
+func Register(root *cobra.Command) {
+	walk(root, func(cmd *cobra.Command) {
+		for name, c := range flagCompleters {
+			if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+				_ = cmd.RegisterFlagCompletionFunc(name, c.complete)
+			}
+		}
+
+		if cmd.ValidArgsFunction != nil || cmd.HasSubCommands() {
+			return
+		}
+
+		completers, variadic := argCompleters(cmd)
+		if len(completers) == 0 {
+			return
+		}
+
+		cmd.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			i := len(args)
+			if i >= len(completers) {
+				if !variadic {
+					return nil, cobra.ShellCompDirectiveNoFileComp
+				}
+
+				i = len(completers) - 1
+			}
+
+			if completers[i] == nil {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+
+			return completers[i].complete(cmd, args, toComplete)
+		}
+	})
+}
+
+func walk(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+
+	for _, child := range cmd.Commands() {
+		walk(child, fn)
+	}
+}
+
+// argCompleters returns the completers of the arguments of cmd, by position,
+// and whether its last argument repeats.
+func argCompleters(cmd *cobra.Command) ([]*completer, bool) {
+	var (
+		completers []*completer
+		variadic   bool
+		found      bool
+	)
+
+	fields := strings.Fields(cmd.Use)
+	if len(fields) < 2 {
+		return nil, false
+	}
+
+	for _, p := range fields[1:] {
+		if strings.HasPrefix(p, "-") {
+			continue
+		}
+		if strings.Contains(p, "...") {
+			variadic = true
+			if p == "..." {
+				break
+			}
+		}
+
+		c := placeholderCompleter(cmd, strings.Trim(p, "[]<>."))
+		completers = append(completers, c)
+		found = found || c != nil
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	return completers, variadic
+}
+
+// placeholderCompleter returns the completer of the argument of cmd its usage
+// calls name, or nil when there's none.
+func placeholderCompleter(cmd *cobra.Command, name string) *completer {
+	switch name {
+	case "APPNAME", "app", "app-name":
+		return &apps
+	case "slug", "org":
+		return &orgs
+	case "REGION", "region":
+		return &regions
+	case "machine_id", "machine-id", "standby-id":
+		return &machines
+	case "volume-id", "volume_id":
+		return &volumes
+	case "id":
+		for parent := cmd.Parent(); parent != nil; parent = parent.Parent() {
+			switch parent.Name() {
+			case "machine", "machines":
+				return &machines
+			case "volumes", "volume":
+				return &volumes
+			}
+		}
+	}
+
+	return nil
+}
+
+// complete completes toComplete from the values of c, cached for its ttl.
+func (c completer) complete(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	const directive = cobra.ShellCompDirectiveNoFileComp
+
+	ctx, err := command.PrepareCompletion(cmd)
+	if err != nil {
+		return nil, directive
+	}
+
+	client := client.FromContext(ctx)
+	if !client.Authenticated() {
+		return nil, directive
+	}
+
+	var appName string
+	if c.perApp {
+		if appName = appNameOf(ctx, cmd); appName == "" {
+			return nil, directive
+		}
+	}
+
+	values, err := cached(ctx, c, appName, func() ([]string, error) {
+		return c.fetch(ctx, client.API(), appName)
+	})
+	if err != nil {
+		return nil, directive
+	}
+
+	var matches []string
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			matches = append(matches, value)
+		}
+	}
+
+	return matches, directive
+}
+
+// appNameOf returns the app cmd is given with --app, FLY_APP or fly.toml.
+func appNameOf(ctx context.Context, cmd *cobra.Command) string {
+	if f := cmd.Flags().Lookup("app"); f != nil && f.Value.String() != "" {
+		return f.Value.String()
+	}
+
+	if name := os.Getenv("FLY_APP"); name != "" {
+		return name
+	}
+
+	if cfg := app.ConfigFromContext(ctx); cfg != nil {
+		return cfg.AppName
+	}
+
+	return ""
+}
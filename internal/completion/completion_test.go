@@ -0,0 +1,28 @@
+package completion
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgCompleters(t *testing.T) {
+	machine := &cobra.Command{Use: "machine"}
+	status := &cobra.Command{Use: "status <id>"}
+	machine.AddCommand(status)
+
+	completers, variadic := argCompleters(status)
+	assert.Equal(t, []*completer{&machines}, completers)
+	assert.False(t, variadic)
+
+	completers, variadic = argCompleters(&cobra.Command{Use: "add REGION ..."})
+	assert.Equal(t, []*completer{&regions}, completers)
+	assert.True(t, variadic)
+
+	completers, _ = argCompleters(&cobra.Command{Use: "open [PATH]"})
+	assert.Nil(t, completers)
+
+	completers, _ = argCompleters(&cobra.Command{Use: "list"})
+	assert.Nil(t, completers)
+}
@@ -1,8 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/spf13/pflag"
 
@@ -18,19 +22,25 @@ const (
 	apiBaseURLEnvKey      = envKeyPrefix + "API_BASE_URL"
 	AccessTokenEnvKey     = envKeyPrefix + "ACCESS_TOKEN"
 	AccessTokenFileKey    = "access_token"
+	RefreshTokenFileKey   = "refresh_token"
+	ExpiresAtFileKey      = "access_token_expires_at"
 	WireGuardStateFileKey = "wire_guard_state"
 	APITokenEnvKey        = envKeyPrefix + "API_TOKEN"
 	orgEnvKey             = envKeyPrefix + "ORG"
 	registryHostEnvKey    = envKeyPrefix + "REGISTRY_HOST"
 	organizationEnvKey    = envKeyPrefix + "ORGANIZATION"
 	regionEnvKey          = envKeyPrefix + "REGION"
+	contextEnvKey         = envKeyPrefix + "CONTEXT"
 	verboseOutputEnvKey   = envKeyPrefix + "VERBOSE"
 	jsonOutputEnvKey      = envKeyPrefix + "JSON"
 	logGQLEnvKey          = envKeyPrefix + "LOG_GQL_ERRORS"
 	localOnlyEnvKey       = envKeyPrefix + "LOCAL_ONLY"
+	maxRetriesEnvKey      = envKeyPrefix + "MAX_RETRIES"
+	nonInteractiveEnvKey  = envKeyPrefix + "NON_INTERACTIVE"
 
 	defaultAPIBaseURL   = "https://api.fly.io"
 	defaultRegistryHost = "registry.fly.io"
+	defaultMaxRetries   = 3
 )
 
 // Config wraps the functionality of the configuration file.
@@ -63,8 +73,51 @@ type Config struct {
 	// LocalOnly denotes whether the user wants only local operations.
 	LocalOnly bool
 
+	// NonInteractive denotes whether commands should fail, rather than prompt,
+	// when they miss input.
+	NonInteractive bool
+
+	// MaxRetries denotes how many times API requests are retried after
+	// transient failures, like rate limiting.
+	MaxRetries int
+
 	// AccessToken denotes the user's access token.
 	AccessToken string
+
+	// RefreshToken denotes the token the access token is refreshed with, for
+	// short-lived access tokens issued by SSO logins.
+	RefreshToken string
+
+	// AccessTokenExpiresAt denotes when the access token expires, when it's
+	// short-lived.
+	AccessTokenExpiresAt time.Time
+
+	// Context denotes the name of the context the credentials above come from,
+	// if any.
+	Context string
+
+	// Contexts denotes the named contexts of the configuration file.
+	Contexts map[string]Context
+
+	// CurrentContext denotes the name of the context used by default.
+	CurrentContext string
+
+	// PinnedContexts maps directories to the names of the contexts used in
+	// them, and in the directories they contain.
+	PinnedContexts map[string]string
+
+	// orgFromContext denotes whether Organization is the one of Context,
+	// rather than one given by environment or flag.
+	orgFromContext bool
+}
+
+// Context is a named set of credentials, along with the organization commands
+// default to when using them.
+type Context struct {
+	AccessToken  string    `yaml:"access_token"`
+	RefreshToken string    `yaml:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `yaml:"access_token_expires_at,omitempty"`
+	Organization string    `yaml:"org,omitempty"`
 }
 
 // New returns a new instance of Config populated with default values.
@@ -72,6 +125,7 @@ func New() *Config {
 	return &Config{
 		APIBaseURL:   defaultAPIBaseURL,
 		RegistryHost: defaultRegistryHost,
+		MaxRetries:   defaultMaxRetries,
 	}
 }
 
@@ -83,22 +137,33 @@ func (cfg *Config) ApplyEnv() {
 	cfg.mu.Lock()
 	defer cfg.mu.Unlock()
 
+	fileToken := cfg.AccessToken
+
 	cfg.AccessToken = env.FirstOrDefault(cfg.AccessToken,
 		AccessTokenEnvKey, APITokenEnvKey)
 
 	// trim whitespace since it causes http errors when passsed to Docker auth
 	cfg.AccessToken = strings.TrimSpace(cfg.AccessToken)
 
+	cfg.dropRefreshUnless(fileToken)
+
 	cfg.VerboseOutput = env.IsTruthy(verboseOutputEnvKey) || cfg.VerboseOutput
 	cfg.JSONOutput = env.IsTruthy(jsonOutputEnvKey) || cfg.JSONOutput
 	cfg.LogGQLErrors = env.IsTruthy(logGQLEnvKey) || cfg.LogGQLErrors
 	cfg.LocalOnly = env.IsTruthy(localOnlyEnvKey) || cfg.LocalOnly
+	cfg.NonInteractive = env.IsTruthy(nonInteractiveEnvKey) || cfg.NonInteractive
 
-	cfg.Organization = env.FirstOrDefault(cfg.Organization,
-		orgEnvKey, organizationEnvKey)
+	if org := env.First(orgEnvKey, organizationEnvKey); org != "" {
+		cfg.Organization = org
+		cfg.orgFromContext = false
+	}
 	cfg.Region = env.FirstOrDefault(cfg.Region, regionEnvKey)
 	cfg.RegistryHost = env.FirstOrDefault(cfg.RegistryHost, registryHostEnvKey)
 	cfg.APIBaseURL = env.FirstOrDefault(cfg.APIBaseURL, apiBaseURLEnvKey)
+
+	if n, err := strconv.Atoi(env.First(maxRetriesEnvKey)); err == nil && n >= 0 {
+		cfg.MaxRetries = n
+	}
 }
 
 // ApplyFile sets the properties of cfg which may be set via configuration file
@@ -108,22 +173,114 @@ func (cfg *Config) ApplyFile(path string) (err error) {
 	defer cfg.mu.Unlock()
 
 	var w struct {
-		AccessToken string `yaml:"access_token"`
+		AccessToken    string             `yaml:"access_token"`
+		RefreshToken   string             `yaml:"refresh_token"`
+		ExpiresAt      time.Time          `yaml:"access_token_expires_at"`
+		Contexts       map[string]Context `yaml:"contexts"`
+		CurrentContext string             `yaml:"current_context"`
+		PinnedContexts map[string]string  `yaml:"pinned_contexts"`
 	}
 
 	if err = unmarshal(path, &w); err == nil {
 		cfg.AccessToken = w.AccessToken
+		cfg.RefreshToken = w.RefreshToken
+		cfg.AccessTokenExpiresAt = w.ExpiresAt
+		cfg.Contexts = w.Contexts
+		cfg.CurrentContext = w.CurrentContext
+		cfg.PinnedContexts = w.PinnedContexts
 	}
 
 	return
 }
 
+// ApplyContext sets the credentials and organization of cfg to those of the
+// context named explicitly, or by the environment, or pinned to the directory
+// dir is in, or else the current one, in that order. It must be called after
+// ApplyFile and before ApplyEnv, so that the latter still overrides them.
+func (cfg *Config) ApplyContext(dir, explicit string) error {
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+
+	name := explicit
+	if name == "" {
+		name = env.First(contextEnvKey)
+	}
+	if name == "" {
+		name = cfg.pinnedContext(dir)
+	}
+	if name == "" {
+		name = cfg.CurrentContext
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	c, ok := cfg.Contexts[name]
+	if !ok {
+		return fmt.Errorf("unknown context %q; list contexts with fly config get-contexts", name)
+	}
+
+	cfg.Context = name
+	cfg.AccessToken = c.AccessToken
+	cfg.RefreshToken = c.RefreshToken
+	cfg.AccessTokenExpiresAt = c.ExpiresAt
+	if c.Organization != "" {
+		cfg.Organization = c.Organization
+		cfg.orgFromContext = true
+	}
+
+	return nil
+}
+
+// pinnedContext returns the name of the context pinned to dir, or to the
+// closest directory containing it.
+func (cfg *Config) pinnedContext(dir string) string {
+	for dir != "" {
+		if name, ok := cfg.PinnedContexts[dir]; ok {
+			return name
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return ""
+}
+
+// dropRefreshUnless drops the refresh token, expiry, context and the
+// organization of that context of the access token read from the
+// configuration file when another one overrides it, since they belong to the
+// former.
+func (cfg *Config) dropRefreshUnless(fileToken string) {
+	if cfg.AccessToken != fileToken {
+		cfg.RefreshToken = ""
+		cfg.AccessTokenExpiresAt = time.Time{}
+		cfg.Context = ""
+
+		if cfg.orgFromContext {
+			cfg.Organization = ""
+			cfg.orgFromContext = false
+		}
+	}
+}
+
 // ApplyFlags sets the properties of cfg which may be set via command line flags
 // to the values the flags of the given FlagSet may contain.
 func (cfg *Config) ApplyFlags(fs *pflag.FlagSet) {
 	cfg.mu.Lock()
 	defer cfg.mu.Unlock()
 
+	fileToken := cfg.AccessToken
+	defer cfg.dropRefreshUnless(fileToken)
+
+	if fs.Changed(flag.OrgName) {
+		cfg.orgFromContext = false
+	}
+
 	applyStringFlags(fs, map[string]*string{
 		flag.AccessTokenName: &cfg.AccessToken,
 		flag.OrgName:         &cfg.Organization,
@@ -131,9 +288,14 @@ func (cfg *Config) ApplyFlags(fs *pflag.FlagSet) {
 	})
 
 	applyBoolFlags(fs, map[string]*bool{
-		flag.VerboseName:    &cfg.VerboseOutput,
-		flag.JSONOutputName: &cfg.JSONOutput,
-		flag.LocalOnlyName:  &cfg.LocalOnly,
+		flag.VerboseName:        &cfg.VerboseOutput,
+		flag.JSONOutputName:     &cfg.JSONOutput,
+		flag.LocalOnlyName:      &cfg.LocalOnly,
+		flag.NonInteractiveName: &cfg.NonInteractive,
+	})
+
+	applyIntFlags(fs, map[string]*int{
+		flag.MaxRetriesName: &cfg.MaxRetries,
 	})
 }
 
@@ -151,6 +313,20 @@ func applyStringFlags(fs *pflag.FlagSet, flags map[string]*string) {
 	}
 }
 
+func applyIntFlags(fs *pflag.FlagSet, flags map[string]*int) {
+	for name, dst := range flags {
+		if !fs.Changed(name) {
+			continue
+		}
+
+		if v, err := fs.GetInt(name); err != nil {
+			panic(err)
+		} else {
+			*dst = v
+		}
+	}
+}
+
 func applyBoolFlags(fs *pflag.FlagSet, flags map[string]*bool) {
 	for name, dst := range flags {
 		if !fs.Changed(name) {
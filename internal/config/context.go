@@ -14,3 +14,11 @@ func NewContext(ctx context.Context, cfg *Config) context.Context {
 func FromContext(ctx context.Context) *Config {
 	return ctx.Value(contextKey{}).(*Config)
 }
+
+// MaybeFromContext returns the Config ctx carries, or nil in case it carries
+// none.
+func MaybeFromContext(ctx context.Context) *Config {
+	cfg, _ := ctx.Value(contextKey{}).(*Config)
+
+	return cfg
+}
@@ -17,3 +17,12 @@ func TestNewContext(t *testing.T) {
 	ctx := NewContext(context.Background(), exp)
 	assert.Same(t, exp, FromContext(ctx))
 }
+
+func TestMaybeFromContext(t *testing.T) {
+	assert.Nil(t, MaybeFromContext(context.Background()))
+
+	exp := new(Config)
+
+	ctx := NewContext(context.Background(), exp)
+	assert.Same(t, exp, MaybeFromContext(ctx))
+}
@@ -3,8 +3,10 @@ package config
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -15,7 +17,142 @@ import (
 // found at path.
 func SetAccessToken(path, token string) error {
 	return set(path, map[string]interface{}{
-		AccessTokenFileKey: token,
+		AccessTokenFileKey:  token,
+		RefreshTokenFileKey: "",
+		ExpiresAtFileKey:    nil,
+	})
+}
+
+// SetSessionTokens sets the values of a short-lived access token, the time it
+// expires at and the token it's refreshed with at the configuration file found
+// at path.
+func SetSessionTokens(path, accessToken, refreshToken string, expiresAt time.Time) error {
+	return set(path, map[string]interface{}{
+		AccessTokenFileKey:  accessToken,
+		RefreshTokenFileKey: refreshToken,
+		ExpiresAtFileKey:    expiresAt.UTC(),
+	})
+}
+
+// SessionTokens returns the access token, the time it expires at and the token
+// it's refreshed with of the context with the given name, or of the file when
+// the name is empty, at the configuration file found at path.
+func SessionTokens(path, name string) (accessToken, refreshToken string, expiresAt time.Time, err error) {
+	var w struct {
+		AccessToken  string             `yaml:"access_token"`
+		RefreshToken string             `yaml:"refresh_token"`
+		ExpiresAt    time.Time          `yaml:"access_token_expires_at"`
+		Contexts     map[string]Context `yaml:"contexts"`
+	}
+
+	if err = unmarshal(path, &w); err != nil {
+		return
+	}
+
+	if name == "" {
+		return w.AccessToken, w.RefreshToken, w.ExpiresAt, nil
+	}
+
+	c, ok := w.Contexts[name]
+	if !ok {
+		err = fmt.Errorf("context %q no longer exists", name)
+
+		return
+	}
+
+	return c.AccessToken, c.RefreshToken, c.ExpiresAt, nil
+}
+
+// SetContext sets the context with the given name, replacing the one of that
+// name if any, at the configuration file found at path.
+func SetContext(path, name string, c Context) error {
+	return updateContexts(path, func(w *contextsFile) {
+		if w.Contexts == nil {
+			w.Contexts = map[string]Context{}
+		}
+		w.Contexts[name] = c
+	})
+}
+
+// SetContextSessionTokens sets the values of a short-lived access token, the
+// time it expires at and the token it's refreshed with for the context with
+// the given name at the configuration file found at path.
+func SetContextSessionTokens(path, name, accessToken, refreshToken string, expiresAt time.Time) error {
+	return updateContexts(path, func(w *contextsFile) {
+		if c, ok := w.Contexts[name]; ok {
+			c.AccessToken = accessToken
+			c.RefreshToken = refreshToken
+			c.ExpiresAt = expiresAt.UTC()
+			w.Contexts[name] = c
+		}
+	})
+}
+
+// DeleteContext deletes the context with the given name, and unpins it from
+// the directories it's pinned to, at the configuration file found at path.
+func DeleteContext(path, name string) error {
+	return updateContexts(path, func(w *contextsFile) {
+		delete(w.Contexts, name)
+
+		if w.CurrentContext == name {
+			w.CurrentContext = ""
+		}
+
+		for dir, pinned := range w.PinnedContexts {
+			if pinned == name {
+				delete(w.PinnedContexts, dir)
+			}
+		}
+	})
+}
+
+// UseContext sets the context used by default at the configuration file found
+// at path. An empty name uses the access token outside of contexts.
+func UseContext(path, name string) error {
+	return updateContexts(path, func(w *contextsFile) {
+		w.CurrentContext = name
+	})
+}
+
+// PinContext pins the context with the given name to dir at the configuration
+// file found at path. An empty name unpins dir.
+func PinContext(path, dir, name string) error {
+	return updateContexts(path, func(w *contextsFile) {
+		if name == "" {
+			delete(w.PinnedContexts, dir)
+
+			return
+		}
+
+		if w.PinnedContexts == nil {
+			w.PinnedContexts = map[string]string{}
+		}
+		w.PinnedContexts[dir] = name
+	})
+}
+
+type contextsFile struct {
+	Contexts       map[string]Context `yaml:"contexts"`
+	CurrentContext string             `yaml:"current_context"`
+	PinnedContexts map[string]string  `yaml:"pinned_contexts"`
+}
+
+func updateContexts(path string, fn func(*contextsFile)) error {
+	var w contextsFile
+
+	switch err := unmarshal(path, &w); {
+	case err == nil, os.IsNotExist(err):
+		break
+	default:
+		return err
+	}
+
+	fn(&w)
+
+	return set(path, map[string]interface{}{
+		"contexts":        w.Contexts,
+		"current_context": w.CurrentContext,
+		"pinned_contexts": w.PinnedContexts,
 	})
 }
 
@@ -24,6 +161,8 @@ func SetAccessToken(path, token string) error {
 func Clear(path string) (err error) {
 	return set(path, map[string]interface{}{
 		AccessTokenFileKey:    "",
+		RefreshTokenFileKey:   "",
+		ExpiresAtFileKey:      nil,
 		WireGuardStateFileKey: map[string]interface{}{},
 	})
 }
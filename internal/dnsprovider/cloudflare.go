@@ -0,0 +1,73 @@
+package dnsprovider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const cloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+type cloudflare struct {
+	token string
+}
+
+type cloudflareRecord struct {
+	ID      string `json:"id,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	TTL     int    `json:"ttl"`
+	Proxied bool   `json:"proxied"`
+}
+
+func (c *cloudflare) SetCNAME(ctx context.Context, name, target string) error {
+	zoneID, err := c.zoneID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var existing []cloudflareRecord
+	query := url.Values{"type": {"CNAME"}, "name": {name}}
+	if err := c.do(ctx, http.MethodGet, "/zones/"+zoneID+"/dns_records?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	record := cloudflareRecord{Type: "CNAME", Name: name, Content: target, TTL: 60}
+
+	if len(existing) > 0 {
+		return c.do(ctx, http.MethodPut, "/zones/"+zoneID+"/dns_records/"+existing[0].ID, record, nil)
+	}
+
+	return c.do(ctx, http.MethodPost, "/zones/"+zoneID+"/dns_records", record, nil)
+}
+
+func (c *cloudflare) zoneID(ctx context.Context, name string) (string, error) {
+	for _, zone := range zones(name) {
+		var found []struct {
+			ID string `json:"id"`
+		}
+
+		if err := c.do(ctx, http.MethodGet, "/zones?"+url.Values{"name": {zone}}.Encode(), nil, &found); err != nil {
+			return "", err
+		}
+
+		if len(found) > 0 {
+			return found[0].ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("no cloudflare zone found for %s", name)
+}
+
+func (c *cloudflare) do(ctx context.Context, method, path string, in, result interface{}) error {
+	var out struct {
+		Result interface{} `json:"result"`
+	}
+	out.Result = result
+
+	header := http.Header{"Authorization": {"Bearer " + c.token}}
+
+	return doJSON(ctx, method, cloudflareAPI+path, header, in, &out)
+}
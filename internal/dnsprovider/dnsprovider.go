@@ -0,0 +1,178 @@
+// Package dnsprovider manages DNS records through the APIs of DNS hosting
+// providers, so records needed for certificate validation don't have to be
+// added by hand.
+package dnsprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Provider creates records in the zones a DNS provider hosts.
+type Provider interface {
+	// SetCNAME points the CNAME record name at target, replacing any CNAME
+	// record of the same name.
+	SetCNAME(ctx context.Context, name, target string) error
+}
+
+// Names lists the supported providers.
+var Names = []string{"cloudflare", "route53", "google"}
+
+// New returns the named provider, authenticating with credentials. Empty
+// credentials are read from the environment variables the provider's own
+// tooling uses:
+//
+//   - cloudflare: an API token, or CLOUDFLARE_API_TOKEN
+//   - route53: ACCESS_KEY_ID:SECRET_ACCESS_KEY, or AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY
+//   - google: the path to a service account key file, or GOOGLE_APPLICATION_CREDENTIALS
+func New(name, credentials string) (Provider, error) {
+	switch name {
+	case "cloudflare":
+		if credentials == "" {
+			credentials = os.Getenv("CLOUDFLARE_API_TOKEN")
+		}
+		if credentials == "" {
+			return nil, errors.New("cloudflare requires an API token; pass --dns-credentials or set CLOUDFLARE_API_TOKEN")
+		}
+
+		return &cloudflare{token: credentials}, nil
+	case "route53":
+		accessKey, secretKey, _ := strings.Cut(credentials, ":")
+		if credentials == "" {
+			accessKey, secretKey = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+		}
+		if accessKey == "" || secretKey == "" {
+			return nil, errors.New("route53 requires an access key; pass --dns-credentials ACCESS_KEY_ID:SECRET_ACCESS_KEY or set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+
+		return &route53{accessKey: accessKey, secretKey: secretKey}, nil
+	case "google":
+		if credentials == "" {
+			credentials = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+		}
+		if credentials == "" {
+			return nil, errors.New("google requires a service account key file; pass --dns-credentials or set GOOGLE_APPLICATION_CREDENTIALS")
+		}
+
+		return newGoogle(credentials)
+	default:
+		return nil, fmt.Errorf("unsupported DNS provider %q; use one of %s", name, strings.Join(Names, ", "))
+	}
+}
+
+// resolvers are the public resolvers propagation is checked against.
+var resolvers = []string{"1.1.1.1:53", "8.8.8.8:53"}
+
+// WaitForCNAME waits until all the public resolvers answer queries for the
+// CNAME record name with target.
+func WaitForCNAME(ctx context.Context, name, target string) error {
+	client := &dns.Client{Timeout: 5 * time.Second}
+
+	msg := new(dns.Msg)
+	msg.SetQuestion(fqdn(name), dns.TypeCNAME)
+
+	propagated := func() bool {
+		for _, resolver := range resolvers {
+			res, _, err := client.ExchangeContext(ctx, msg, resolver)
+			if err != nil {
+				return false
+			}
+
+			var found bool
+			for _, rr := range res.Answer {
+				if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Target, fqdn(target)) {
+					found = true
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for !propagated() {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("CNAME record %s hasn't propagated: %w", name, ctx.Err())
+		case <-time.After(5 * time.Second):
+		}
+	}
+
+	return nil
+}
+
+// zones returns the zones name may belong to, from the most to the least
+// specific, leaving out the top-level domain.
+func zones(name string) []string {
+	labels := strings.Split(strings.TrimSuffix(name, "."), ".")
+
+	var candidates []string
+	for i := 0; i < len(labels)-1; i++ {
+		candidates = append(candidates, strings.Join(labels[i:], "."))
+	}
+
+	return candidates
+}
+
+func fqdn(name string) string {
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// doJSON sends in as the JSON body of the request and decodes the response
+// body into out, when either isn't nil.
+func doJSON(ctx context.Context, method, url string, header http.Header, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return responseError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func responseError(res *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+
+	return fmt.Errorf("%s %s: %s: %s", res.Request.Method, res.Request.URL.Host, res.Status, strings.TrimSpace(string(data)))
+}
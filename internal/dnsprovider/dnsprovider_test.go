@@ -0,0 +1,27 @@
+package dnsprovider
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZones(t *testing.T) {
+	assert.Equal(t, []string{"_acme-challenge.www.example.co.uk", "www.example.co.uk", "example.co.uk", "co.uk"},
+		zones("_acme-challenge.www.example.co.uk."))
+	assert.Empty(t, zones("localhost"))
+}
+
+func TestRoute53ChangeBatch(t *testing.T) {
+	data, err := xml.Marshal(route53ChangeBatch{
+		Changes: []route53Change{{Action: "UPSERT", Name: "a.example.com.", Type: "CNAME", TTL: 60, Values: []string{"b.example.net."}}},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, `<ChangeResourceRecordSetsRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">`+
+		`<ChangeBatch><Changes><Change><Action>UPSERT</Action>`+
+		`<ResourceRecordSet><Name>a.example.com.</Name><Type>CNAME</Type><TTL>60</TTL>`+
+		`<ResourceRecords><ResourceRecord><Value>b.example.net.</Value></ResourceRecord></ResourceRecords>`+
+		`</ResourceRecordSet></Change></Changes></ChangeBatch></ChangeResourceRecordSetsRequest>`, string(data))
+}
@@ -0,0 +1,198 @@
+package dnsprovider
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	googleAPI   = "https://dns.googleapis.com/dns/v1"
+	googleScope = "https://www.googleapis.com/auth/ndev.clouddns.readwrite"
+)
+
+type google struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+
+	token string
+}
+
+type googleRecordSet struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	RRDatas []string `json:"rrdatas"`
+}
+
+func newGoogle(keyFile string) (*google, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed reading service account key: %w", err)
+	}
+
+	g := &google{}
+	if err := json.Unmarshal(data, g); err != nil {
+		return nil, fmt.Errorf("failed parsing service account key %s: %w", keyFile, err)
+	}
+
+	if g.ProjectID == "" || g.ClientEmail == "" || g.PrivateKey == "" {
+		return nil, fmt.Errorf("%s isn't a service account key file", keyFile)
+	}
+
+	if g.TokenURI == "" {
+		g.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+
+	return g, nil
+}
+
+func (g *google) SetCNAME(ctx context.Context, name, target string) error {
+	if err := g.authenticate(ctx); err != nil {
+		return err
+	}
+
+	zone, err := g.managedZone(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	zonePath := "/projects/" + g.ProjectID + "/managedZones/" + zone
+
+	var existing struct {
+		RRSets []googleRecordSet `json:"rrsets"`
+	}
+	query := url.Values{"name": {fqdn(name)}, "type": {"CNAME"}}
+	if err := g.do(ctx, http.MethodGet, zonePath+"/rrsets?"+query.Encode(), nil, &existing); err != nil {
+		return err
+	}
+
+	change := map[string][]googleRecordSet{
+		"additions": {{Name: fqdn(name), Type: "CNAME", TTL: 60, RRDatas: []string{fqdn(target)}}},
+		"deletions": existing.RRSets,
+	}
+
+	return g.do(ctx, http.MethodPost, zonePath+"/changes", change, nil)
+}
+
+// managedZone returns the name of the most specific public managed zone name
+// belongs to.
+func (g *google) managedZone(ctx context.Context, name string) (string, error) {
+	for _, zone := range zones(name) {
+		var found struct {
+			ManagedZones []struct {
+				Name       string `json:"name"`
+				Visibility string `json:"visibility"`
+			} `json:"managedZones"`
+		}
+
+		query := url.Values{"dnsName": {fqdn(zone)}}
+		if err := g.do(ctx, http.MethodGet, "/projects/"+g.ProjectID+"/managedZones?"+query.Encode(), nil, &found); err != nil {
+			return "", err
+		}
+
+		for _, managed := range found.ManagedZones {
+			if managed.Visibility != "private" {
+				return managed.Name, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("no google cloud DNS managed zone found for %s", name)
+}
+
+// authenticate exchanges a JWT signed with the service account key for an
+// access token.
+func (g *google) authenticate(ctx context.Context) error {
+	block, _ := pem.Decode([]byte(g.PrivateKey))
+	if block == nil {
+		return errors.New("service account key holds no PEM encoded private key")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return fmt.Errorf("failed parsing service account private key: %w", err)
+		}
+	}
+
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return errors.New("service account private key isn't an RSA key")
+	}
+
+	now := time.Now()
+
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   g.ClientEmail,
+		"scope": googleScope,
+		"aud":   g.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`)) + "." + enc.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {unsigned + "." + enc.EncodeToString(signature)},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return responseError(res)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return err
+	}
+
+	g.token = token.AccessToken
+
+	return nil
+}
+
+func (g *google) do(ctx context.Context, method, path string, in, out interface{}) error {
+	header := http.Header{"Authorization": {"Bearer " + g.token}}
+
+	return doJSON(ctx, method, googleAPI+path, header, in, out)
+}
@@ -0,0 +1,145 @@
+package dnsprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/superfly/flyctl/internal/s3"
+)
+
+const route53API = "https://route53.amazonaws.com/2013-04-01"
+
+type route53 struct {
+	accessKey string
+	secretKey string
+}
+
+type route53HostedZones struct {
+	HostedZones []struct {
+		ID          string `xml:"Id"`
+		Name        string `xml:"Name"`
+		PrivateZone bool   `xml:"Config>PrivateZone"`
+	} `xml:"HostedZones>HostedZone"`
+	IsTruncated bool   `xml:"IsTruncated"`
+	NextMarker  string `xml:"NextMarker"`
+}
+
+type route53ChangeBatch struct {
+	XMLName xml.Name        `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53Change struct {
+	Action string   `xml:"Action"`
+	Name   string   `xml:"ResourceRecordSet>Name"`
+	Type   string   `xml:"ResourceRecordSet>Type"`
+	TTL    int      `xml:"ResourceRecordSet>TTL"`
+	Values []string `xml:"ResourceRecordSet>ResourceRecords>ResourceRecord>Value"`
+}
+
+func (r *route53) SetCNAME(ctx context.Context, name, target string) error {
+	zoneID, err := r.zoneID(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	batch := route53ChangeBatch{
+		Changes: []route53Change{{
+			Action: "UPSERT",
+			Name:   fqdn(name),
+			Type:   "CNAME",
+			TTL:    60,
+			Values: []string{fqdn(target)},
+		}},
+	}
+
+	body, err := xml.Marshal(batch)
+	if err != nil {
+		return err
+	}
+
+	return r.do(ctx, http.MethodPost, "/hostedzone/"+zoneID+"/rrset", nil, body, nil)
+}
+
+// zoneID returns the ID of the most specific public hosted zone name belongs to.
+func (r *route53) zoneID(ctx context.Context, name string) (string, error) {
+	var (
+		best   string
+		bestID string
+		marker string
+	)
+
+	for {
+		query := url.Values{}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+
+		var page route53HostedZones
+		if err := r.do(ctx, http.MethodGet, "/hostedzone", query, nil, &page); err != nil {
+			return "", err
+		}
+
+		for _, zone := range page.HostedZones {
+			if zone.PrivateZone || len(zone.Name) <= len(best) {
+				continue
+			}
+
+			if fqdn(name) == zone.Name || strings.HasSuffix(fqdn(name), "."+zone.Name) {
+				best, bestID = zone.Name, strings.TrimPrefix(zone.ID, "/hostedzone/")
+			}
+		}
+
+		if !page.IsTruncated {
+			break
+		}
+		marker = page.NextMarker
+	}
+
+	if bestID == "" {
+		return "", fmt.Errorf("no route53 hosted zone found for %s", name)
+	}
+
+	return bestID, nil
+}
+
+func (r *route53) do(ctx context.Context, method, path string, query url.Values, body []byte, out interface{}) error {
+	u := route53API + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	// Route 53 is a global service signed for us-east-1
+	s3.Sign(req, body, "us-east-1", "route53", r.accessKey, r.secretKey, time.Now().UTC())
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		return responseError(res)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return xml.NewDecoder(res.Body).Decode(out)
+}
@@ -2,6 +2,10 @@ package flag
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 )
@@ -64,6 +68,53 @@ func GetStringSlice(ctx context.Context, name string) []string {
 	}
 }
 
+// GetDuration returns the value of the named duration flag ctx carries. It
+// panics in case ctx carries no flags or in case the named flag isn't a
+// duration one.
+func GetDuration(ctx context.Context, name string) time.Duration {
+	if v, err := FromContext(ctx).GetDuration(name); err != nil {
+		panic(err)
+	} else {
+		return v
+	}
+}
+
+// GetTime returns the value of the named string flag ctx carries, parsed as a
+// time like 2022-09-01T15:04:05Z or as a duration before now, like 1h or 30d.
+// It returns the zero time for empty values. It panics in case ctx carries no
+// flags or in case the named flag isn't a string one.
+func GetTime(ctx context.Context, name string) (time.Time, error) {
+	t, err := parseTime(GetString(ctx, name), time.Now())
+	if err != nil {
+		return t, fmt.Errorf("invalid --%s: %w", name, err)
+	}
+
+	return t, nil
+}
+
+func parseTime(val string, now time.Time) (time.Time, error) {
+	if val == "" {
+		return time.Time{}, nil
+	}
+
+	if days := strings.TrimSuffix(val, "d"); days != val {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(val); err == nil {
+		return now.Add(-d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a time like 2022-09-01T15:04:05Z nor a duration like 30d or 12h", val)
+	}
+
+	return t, nil
+}
+
 // GetBool returns the value of the named boolean flag ctx carries. It panics
 // in case ctx carries no flags or in case the named flag isn't a boolean one.
 func GetBool(ctx context.Context, name string) bool {
@@ -0,0 +1,31 @@
+package flag
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2022, 9, 30, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]time.Time{
+		"":                     {},
+		"90m":                  now.Add(-90 * time.Minute),
+		"30d":                  time.Date(2022, 8, 31, 12, 0, 0, 0, time.UTC),
+		"2022-09-01T15:04:05Z": time.Date(2022, 9, 1, 15, 4, 5, 0, time.UTC),
+	}
+
+	for val, want := range cases {
+		got, err := parseTime(val, now)
+		if assert.NoError(t, err, val) {
+			assert.True(t, want.Equal(got), "%s: got %s, want %s", val, got, want)
+		}
+	}
+
+	for _, val := range []string{"d", "-3d", "yesterday", "2022-09-01"} {
+		_, err := parseTime(val, now)
+		assert.Error(t, err, val)
+	}
+}
@@ -3,6 +3,7 @@ package flag
 
 import (
 	"context"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -23,6 +24,21 @@ const (
 	// OrgName denotes the name of the org flag.
 	OrgName = "org"
 
+	// ContextName denotes the name of the context flag.
+	ContextName = "context"
+
+	// FormatName denotes the name of the format flag.
+	FormatName = "format"
+
+	// JSONErrorsName denotes the name of the json-errors flag.
+	JSONErrorsName = "json-errors"
+
+	// NonInteractiveName denotes the name of the non-interactive flag.
+	NonInteractiveName = "non-interactive"
+
+	// MaxRetriesName denotes the name of the max-retries flag.
+	MaxRetriesName = "max-retries"
+
 	// RegionName denotes the name of the region flag.
 	RegionName = "region"
 
@@ -139,6 +155,28 @@ func (i Int) addTo(cmd *cobra.Command) {
 	f.Hidden = i.Hidden
 }
 
+// Duration wraps the set of duration flags.
+type Duration struct {
+	Name        string
+	Shorthand   string
+	Description string
+	Default     time.Duration
+	Hidden      bool
+}
+
+func (d Duration) addTo(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	if d.Shorthand != "" {
+		_ = flags.DurationP(d.Name, d.Shorthand, d.Default, d.Description)
+	} else {
+		_ = flags.Duration(d.Name, d.Default, d.Description)
+	}
+
+	f := flags.Lookup(d.Name)
+	f.Hidden = d.Hidden
+}
+
 // StringSlice wraps the set of string slice flags.
 type StringSlice struct {
 	Name        string
@@ -172,6 +210,31 @@ func Org() String {
 	}
 }
 
+// Format returns the flag of list commands which renders each item they
+// list with a Go template, via render.Structured.
+func Format() String {
+	return String{
+		Name:        FormatName,
+		Description: "Render each item with a Go template of its Go field names, not its JSON keys, like '{{.ID}} {{.Region}}'",
+	}
+}
+
+// Watch returns the flags of read commands which re-render their output
+// every --interval with --watch.
+func Watch() Set {
+	return Set{
+		Bool{
+			Name:        "watch",
+			Description: "Refresh the output every --interval, highlighting changes",
+		},
+		Duration{
+			Name:        "interval",
+			Description: "How often --watch refreshes the output",
+			Default:     5 * time.Second,
+		},
+	}
+}
+
 // Region returns a region string flag.
 func Region() String {
 	return String{
@@ -0,0 +1,102 @@
+package flyerr
+
+import "errors"
+
+// Code identifies the kind of failure a command exits with, so that scripts
+// can tell failures apart without matching on their messages. Codes, and the
+// exit statuses they map to, are stable: add new ones rather than changing
+// existing ones.
+type Code string
+
+const (
+	// CodeUnknown is the code of failures no other code describes.
+	CodeUnknown Code = "error"
+
+	// CodeAuth is the code of failures to authenticate, or to be authorized.
+	CodeAuth Code = "auth"
+
+	// CodeNotFound is the code of failures to find an app, machine, volume or
+	// any other resource.
+	CodeNotFound Code = "not_found"
+
+	// CodeCapacity is the code of failures to place machines for lack of
+	// capacity in their region.
+	CodeCapacity Code = "capacity"
+
+	// CodeCheckTimeout is the code of health checks which didn't pass in time.
+	CodeCheckTimeout Code = "check_timeout"
+
+	// CodeLeaseConflict is the code of failures to lease machines another
+	// deployment, or command, holds the lease of.
+	CodeLeaseConflict Code = "lease_conflict"
+
+	// CodeInputRequired is the code of commands which would have prompted for
+	// input they weren't given, when prompting isn't possible.
+	CodeInputRequired Code = "input_required"
+
+	// CodeTimeout is the code of commands which timed out.
+	CodeTimeout Code = "timeout"
+
+	// CodeCancelled is the code of commands which were interrupted, or aborted.
+	CodeCancelled Code = "cancelled"
+)
+
+var exitCodes = map[Code]int{
+	CodeUnknown:       1,
+	CodeAuth:          10,
+	CodeNotFound:      11,
+	CodeCapacity:      12,
+	CodeCheckTimeout:  13,
+	CodeLeaseConflict: 14,
+	CodeInputRequired: 15,
+	CodeTimeout:       126,
+	CodeCancelled:     127,
+}
+
+// ExitCode returns the exit status of failures of the code.
+func (c Code) ExitCode() int {
+	if code, ok := exitCodes[c]; ok {
+		return code
+	}
+
+	return exitCodes[CodeUnknown]
+}
+
+// CodedError is an error which knows its code.
+type CodedError interface {
+	error
+	ErrorCode() Code
+}
+
+type codedError struct {
+	error
+	code Code
+}
+
+func (e *codedError) ErrorCode() Code {
+	return e.code
+}
+
+func (e *codedError) Unwrap() error {
+	return e.error
+}
+
+// WithCode returns err with the given code. It returns nil for nil errors.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &codedError{error: err, code: code}
+}
+
+// GetErrorCode returns the code of the outermost CodedError err wraps, or
+// CodeUnknown when it wraps none.
+func GetErrorCode(err error) Code {
+	var cerr CodedError
+	if errors.As(err, &cerr) {
+		return cerr.ErrorCode()
+	}
+
+	return CodeUnknown
+}
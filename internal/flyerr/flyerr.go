@@ -39,6 +39,15 @@ func GetErrorSuggestion(err error) string {
 	return ""
 }
 
+// ExitCodeError is an error for commands which fail with a specific exit code,
+// such as that of a remote command. The command's own output explains the
+// failure, so the CLI exits with the code without printing anything.
+type ExitCodeError int
+
+func (e ExitCodeError) Error() string {
+	return fmt.Sprintf("exit code %d", int(e))
+}
+
 func PrintCLIOutput(err error) {
 	if err == nil {
 		return
@@ -0,0 +1,85 @@
+// Package gpu checks the GPUs machines are given against the kinds the
+// platform offers, and guides users towards images able to use them.
+package gpu
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/superfly/flyctl/api"
+
+	"github.com/superfly/flyctl/internal/flyerr"
+)
+
+// DriverImage is an image providing the CUDA libraries GPU workloads need,
+// which images of GPU machines may be based on.
+const DriverImage = "nvidia/cuda:12.2.0-runtime-ubuntu22.04"
+
+// Check returns an error when guest asks for a kind of GPU the platform
+// doesn't offer in region, or has no capacity left for there. Only the kind
+// is checked when region is empty. What the API doesn't serve isn't checked,
+// leaving it to the machines API to reject.
+func Check(ctx context.Context, client *api.Client, guest *api.MachineGuest, region string) error {
+	switch served, err := client.SchemaHas(ctx, "FlyPlatform", "gpuKinds"); {
+	case err != nil:
+		return fmt.Errorf("failed checking the API serves GPU kinds: %w", err)
+	case !served:
+		return nil
+	}
+
+	kinds, err := client.PlatformGPUKinds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed retrieving GPU kinds: %w", err)
+	}
+
+	kind, ok := lo.Find(kinds, func(k api.GPUKind) bool { return k.Name == guest.GPUKind })
+	if !ok {
+		names := lo.Map(kinds, func(k api.GPUKind, _ int) string { return k.Name })
+		sort.Strings(names)
+
+		return fmt.Errorf("unknown GPU kind %s, must be one of %s", guest.GPUKind, strings.Join(names, ", "))
+	}
+
+	if region == "" {
+		return nil
+	}
+
+	if !lo.Contains(kind.Regions, region) {
+		return fmt.Errorf("%s GPUs aren't available in %s, only in %s", kind.Name, region, strings.Join(kind.Regions, ", "))
+	}
+
+	switch served, err := client.SchemaHas(ctx, "FlyPlatform", "regionCapacity"); {
+	case err != nil:
+		return fmt.Errorf("failed checking the API serves region capacity: %w", err)
+	case !served:
+		return nil
+	}
+
+	available, err := client.PlatformRegionCapacity(ctx, region, guest)
+	if err != nil {
+		return fmt.Errorf("failed checking capacity of region %s: %w", region, err)
+	}
+
+	if !available {
+		err := fmt.Errorf("region %s is out of capacity for %s GPUs, try one of %s", region, kind.Name, strings.Join(lo.Without(kind.Regions, region), ", "))
+
+		return flyerr.WithCode(flyerr.CodeCapacity, err)
+	}
+
+	return nil
+}
+
+// Notice returns guidance on the image of GPU machines, when image doesn't
+// look like it provides the CUDA libraries, or an empty string.
+func Notice(image string) string {
+	lower := strings.ToLower(image)
+	if strings.Contains(lower, "cuda") || strings.Contains(lower, "nvidia") {
+		return ""
+	}
+
+	return fmt.Sprintf("GPU machines are given the NVIDIA kernel driver, but their image must provide the CUDA libraries; if %s doesn't, base it on an image like %s", image, DriverImage)
+}
@@ -0,0 +1,40 @@
+// Package parallel implements running functions concurrently, bounded so
+// that org-wide commands stay quick without tripping API rate limits.
+package parallel
+
+import "sync"
+
+// Limit is how many API calls commands fetching details of many resources
+// make at once.
+const Limit = 16
+
+// Each calls fn with every index below n, with at most limit calls running
+// at once, and returns once they all have. Calls start in index order but may
+// finish in any order, so callers wanting results as they arrive should send
+// them on a channel from fn.
+func Each(n, limit int, fn func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, limit)
+	)
+
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			fn(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,39 @@
+package parallel
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEach(t *testing.T) {
+	var (
+		running, peak int32
+		mu            sync.Mutex
+		seen          = map[int]bool{}
+	)
+
+	Each(20, 4, func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		mu.Lock()
+		seen[i] = true
+		mu.Unlock()
+	})
+
+	assert.Len(t, seen, 20)
+	assert.LessOrEqual(t, peak, int32(4))
+}
@@ -25,7 +25,7 @@ type RegionParams struct {
 }
 
 func String(ctx context.Context, dst *string, msg, def string, required bool) error {
-	opt, err := newSurveyIO(ctx)
+	opt, err := newSurveyIO(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -44,7 +44,7 @@ func String(ctx context.Context, dst *string, msg, def string, required bool) er
 }
 
 func Int(ctx context.Context, dst *int, msg string, def int, required bool) error {
-	opt, err := newSurveyIO(ctx)
+	opt, err := newSurveyIO(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -70,7 +70,7 @@ func Int(ctx context.Context, dst *int, msg string, def int, required bool) erro
 }
 
 func Password(ctx context.Context, dst *string, msg string, required bool) error {
-	opt, err := newSurveyIO(ctx)
+	opt, err := newSurveyIO(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -88,7 +88,7 @@ func Password(ctx context.Context, dst *string, msg string, required bool) error
 }
 
 func MultiSelect(ctx context.Context, indices *[]int, msg string, def []int, options ...string) error {
-	opt, err := newSurveyIO(ctx)
+	opt, err := newSurveyIO(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -104,7 +104,7 @@ func MultiSelect(ctx context.Context, indices *[]int, msg string, def []int, opt
 }
 
 func Select(ctx context.Context, index *int, msg, def string, options ...string) error {
-	opt, err := newSurveyIO(ctx)
+	opt, err := newSurveyIO(ctx, msg)
 	if err != nil {
 		return err
 	}
@@ -128,7 +128,7 @@ func Confirmf(ctx context.Context, format string, a ...interface{}) (bool, error
 
 func Confirm(ctx context.Context, message string) (confirm bool, err error) {
 	var opt survey.AskOpt
-	if opt, err = newSurveyIO(ctx); err != nil {
+	if opt, err = newSurveyIO(ctx, message); err != nil {
 		return
 	}
 
@@ -142,10 +142,17 @@ func Confirm(ctx context.Context, message string) (confirm bool, err error) {
 }
 
 func ConfirmOverwrite(ctx context.Context, filename string) (confirm bool, err error) {
+	msg := fmt.Sprintf(`Overwrite "%s"?`, filename)
+
+	var opt survey.AskOpt
+	if opt, err = newSurveyIO(ctx, msg); err != nil {
+		return
+	}
+
 	prompt := &survey.Confirm{
-		Message: fmt.Sprintf(`Overwrite "%s"?`, filename),
+		Message: msg,
 	}
-	err = survey.AskOne(prompt, &confirm)
+	err = survey.AskOne(prompt, &confirm, opt)
 
 	return
 }
@@ -162,10 +169,13 @@ func (e NonInteractiveError) Error() string { return string(e) }
 
 func (NonInteractiveError) Unwrap() error { return errNonInteractive }
 
-func newSurveyIO(ctx context.Context) (survey.AskOpt, error) {
+// newSurveyIO returns the streams to prompt msg on. It fails with an error
+// naming msg when prompts can't be answered, either since there's no terminal
+// to answer them on or since they were disabled with --non-interactive.
+func newSurveyIO(ctx context.Context, msg string) (survey.AskOpt, error) {
 	io := iostreams.FromContext(ctx)
-	if !io.IsInteractive() {
-		return nil, errNonInteractive
+	if !io.CanPrompt() {
+		return nil, NonInteractiveError(fmt.Sprintf("can't prompt %q when not running interactively", strings.TrimSpace(msg)))
 	}
 
 	in, ok := io.In.(terminal.FileReader)
@@ -181,7 +191,7 @@ func newSurveyIO(ctx context.Context) (survey.AskOpt, error) {
 	return survey.WithStdio(in, out, io.ErrOut), nil
 }
 
-var errOrgSlugRequired = NonInteractiveError("org slug must be specified when not running interactively")
+var errOrgSlugRequired = NonInteractiveError("--org flag must be specified when not running interactively")
 
 // Org returns the Organization the user has passed in via flag or prompts the
 // user for one.
@@ -242,7 +252,7 @@ func SelectOrg(ctx context.Context, orgs []api.Organization) (org *api.Organizat
 }
 
 var (
-	errRegionCodeRequired  = NonInteractiveError("region code must be specified when not running interactively")
+	errRegionCodeRequired  = NonInteractiveError("--region flag must be specified when not running interactively")
 	errRegionCodesRequired = NonInteractiveError("regions codes must be specified in a comma-separated when not running interactively")
 )
 
@@ -372,7 +382,7 @@ func MultiSelectRegion(ctx context.Context, msg string, regions []api.Region, cu
 	return
 }
 
-var errVMsizeRequired = NonInteractiveError("vm size must be specified when not running interactively")
+var errVMsizeRequired = NonInteractiveError("--vm-size flag must be specified when not running interactively")
 
 func VMSize(ctx context.Context, def string) (size *api.VMSize, err error) {
 	client := client.FromContext(ctx).API()
@@ -408,7 +418,7 @@ func SelectVMSize(ctx context.Context, vmSizes []api.VMSize) (vmSize *api.VMSize
 	options := []string{}
 
 	for _, vmSize := range vmSizes {
-		options = append(options, fmt.Sprintf("%s - %d", vmSize.Name, vmSize.MemoryMB))
+		options = append(options, fmt.Sprintf("%s - %d MB - $%.2f/month", vmSize.Name, vmSize.MemoryMB, vmSize.PriceMonth))
 	}
 
 	var index int
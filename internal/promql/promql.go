@@ -0,0 +1,49 @@
+// Package promql builds the PromQL queries of the instance metrics several
+// commands chart, so that they agree on what CPU and memory usage are.
+package promql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CPU returns the query of the CPU usage of the instances matched by
+// selector, like app="foo", in percent of a CPU and summed by the given
+// labels, if any.
+func CPU(selector string, by ...string) string {
+	// fly_instance_cpu counts centiseconds so its rate is a percentage
+	return sum(fmt.Sprintf(`rate(fly_instance_cpu%s[1m])`, matchers(selector, `mode!="idle"`)), by)
+}
+
+// Memory returns the query of the memory the instances matched by selector,
+// like app="foo", use in bytes, summed by the given labels, if any.
+func Memory(selector string, by ...string) string {
+	selector = matchers(selector)
+
+	return sum(fmt.Sprintf(`fly_instance_memory_mem_total%[1]s - fly_instance_memory_mem_available%[1]s`, selector), by)
+}
+
+func sum(expr string, by []string) string {
+	if len(by) == 0 {
+		return fmt.Sprintf("sum(%s)", expr)
+	}
+
+	return fmt.Sprintf("sum by (%s) (%s)", strings.Join(by, ", "), expr)
+}
+
+// matchers joins the non-empty label matchers given into the braces of a
+// selector, or returns an empty string when there are none.
+func matchers(matchers ...string) string {
+	var nonEmpty []string
+	for _, m := range matchers {
+		if m != "" {
+			nonEmpty = append(nonEmpty, m)
+		}
+	}
+
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(nonEmpty, ",") + "}"
+}
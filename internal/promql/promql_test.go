@@ -0,0 +1,15 @@
+package promql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueries(t *testing.T) {
+	assert.Equal(t, `sum(rate(fly_instance_cpu{app=%[1]q,mode!="idle"}[1m]))`, CPU(`app=%[1]q`))
+	assert.Equal(t, `sum by (app) (rate(fly_instance_cpu{mode!="idle"}[1m]))`, CPU("", "app"))
+
+	assert.Equal(t, `sum(fly_instance_memory_mem_total{app=%[1]q} - fly_instance_memory_mem_available{app=%[1]q})`, Memory(`app=%[1]q`))
+	assert.Equal(t, `sum by (app) (fly_instance_memory_mem_total - fly_instance_memory_mem_available)`, Memory("", "app"))
+}
@@ -0,0 +1,63 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReedSolomonRemainder(t *testing.T) {
+	data := []byte{0x20, 0x5b, 0x0b, 0x78, 0xd1, 0x72, 0xdc, 0x4d, 0x43, 0x40, 0xec, 0x11, 0xec, 0x11, 0xec, 0x11}
+
+	assert.Equal(t, []byte{0xc4, 0x23, 0x27, 0x77, 0xeb, 0xd7, 0xe7, 0xe2, 0x5d, 0x17},
+		reedSolomonRemainder(data, reedSolomonDivisor(10)))
+}
+
+func TestEncodeVersion(t *testing.T) {
+	for n, size := range map[int]int{17: 21, 18: 25, 2953: 177} {
+		c, err := Encode(bytes.Repeat([]byte("x"), n))
+		assert.NoError(t, err)
+		assert.Equal(t, size, c.Size)
+	}
+
+	_, err := Encode(bytes.Repeat([]byte("x"), 2954))
+	assert.ErrorIs(t, err, ErrTooLong)
+}
+
+// TestEncodeConformance compares codes with those Kazuhiko Arase's encoder, as
+// vendored by the qrcode-terminal npm package, generated for testdata. They're
+// masked the same way, since encoders pick masks differently.
+func TestEncodeConformance(t *testing.T) {
+	for name, mask := range map[string]int{"hello": 2, "alphabet": 5, "wireguard": 7} {
+		data, err := os.ReadFile(filepath.Join("testdata", name+".in"))
+		assert.NoError(t, err)
+
+		exp, err := os.ReadFile(filepath.Join("testdata", fmt.Sprintf("%s-mask%d.txt", name, mask)))
+		assert.NoError(t, err)
+
+		c, err := encode(data)
+		assert.NoError(t, err)
+
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+
+		var got strings.Builder
+		for y := 0; y < c.Size; y++ {
+			for x := 0; x < c.Size; x++ {
+				if c.Dark(x, y) {
+					got.WriteByte('#')
+				} else {
+					got.WriteByte('.')
+				}
+			}
+			got.WriteByte('\n')
+		}
+
+		assert.Equal(t, string(exp), got.String(), name)
+	}
+}
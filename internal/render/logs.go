@@ -2,9 +2,13 @@ package render
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/logrusorgru/aurora"
 
@@ -17,6 +21,7 @@ type LogOptions struct {
 	RemoveNewlines bool
 	HideRegion     bool
 	HideAllocID    bool
+	NoColor        bool
 }
 
 // LogOption is a func type that returns a LogOption.
@@ -43,6 +48,13 @@ func HideAllocID() LogOption {
 	}
 }
 
+// NoColor removes the ANSI colors from the log output.
+func NoColor() LogOption {
+	return func(o *LogOptions) {
+		o.NoColor = true
+	}
+}
+
 func LogEntry(w io.Writer, entry logs.LogEntry, opts ...LogOption) (err error) {
 	options := &LogOptions{}
 	for _, opt := range opts {
@@ -56,6 +68,8 @@ func LogEntry(w io.Writer, entry logs.LogEntry, opts ...LogOption) (err error) {
 		return
 	}
 
+	au := aurora.NewAurora(!options.NoColor)
+
 	if !options.HideAllocID {
 		if entry.Meta.Event.Provider != "" {
 			if entry.Instance != "" {
@@ -70,11 +84,11 @@ func LogEntry(w io.Writer, entry logs.LogEntry, opts ...LogOption) (err error) {
 	}
 
 	if !options.HideRegion {
-		fmt.Fprintf(w, "%s ", aurora.Green(entry.Region))
+		fmt.Fprintf(w, "%s ", au.Green(entry.Region))
 	}
 
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "%s ", aurora.Faint(format.Time(ts)))
+	fmt.Fprintf(&buf, "%s ", au.Faint(format.Time(ts)))
 
 	if entry.Meta.Event.Provider != "" {
 		if entry.Instance != "" {
@@ -86,14 +100,14 @@ func LogEntry(w io.Writer, entry logs.LogEntry, opts ...LogOption) (err error) {
 		fmt.Fprintf(&buf, "%s", entry.Instance)
 	}
 
-	fmt.Fprintf(&buf, " %s [%s]", aurora.Green(entry.Region), aurora.Colorize(entry.Level, levelColor(entry.Level)))
+	fmt.Fprintf(&buf, " %s [%s]", au.Green(entry.Region), au.Colorize(entry.Level, levelColor(entry.Level)))
 
-	printFieldIfPresent(&buf, "error.code", entry.Meta.Error.Code)
-	hadErrorMsg := printFieldIfPresent(w, "error.message", entry.Meta.Error.Message)
-	printFieldIfPresent(&buf, "request.method", entry.Meta.HTTP.Request.Method)
-	printFieldIfPresent(&buf, "request.url", entry.Meta.URL.Full)
-	printFieldIfPresent(&buf, "request.id", entry.Meta.HTTP.Request.ID)
-	printFieldIfPresent(&buf, "response.status", entry.Meta.HTTP.Response.StatusCode)
+	printFieldIfPresent(au, &buf, "error.code", entry.Meta.Error.Code)
+	hadErrorMsg := printFieldIfPresent(au, w, "error.message", entry.Meta.Error.Message)
+	printFieldIfPresent(au, &buf, "request.method", entry.Meta.HTTP.Request.Method)
+	printFieldIfPresent(au, &buf, "request.url", entry.Meta.URL.Full)
+	printFieldIfPresent(au, &buf, "request.id", entry.Meta.HTTP.Request.ID)
+	printFieldIfPresent(au, &buf, "response.status", entry.Meta.HTTP.Response.StatusCode)
 
 	if !hadErrorMsg {
 		buf.Write([]byte(entry.Message))
@@ -105,17 +119,17 @@ func LogEntry(w io.Writer, entry logs.LogEntry, opts ...LogOption) (err error) {
 	return err
 }
 
-func printFieldIfPresent(w io.Writer, name string, value interface{}) (present bool) {
+func printFieldIfPresent(au aurora.Aurora, w io.Writer, name string, value interface{}) (present bool) {
 	switch v := value.(type) {
 	case string:
 		if v != "" {
-			fmt.Fprintf(w, `%s"%s" `, aurora.Faint(name+"="), v)
+			fmt.Fprintf(w, `%s"%s" `, au.Faint(name+"="), v)
 
 			present = true
 		}
 	case int:
 		if v > 0 {
-			fmt.Fprintf(w, "%s%d ", aurora.Faint(name+"="), v)
+			fmt.Fprintf(w, "%s%d ", au.Faint(name+"="), v)
 
 			present = true
 		}
@@ -124,6 +138,87 @@ func printFieldIfPresent(w io.Writer, name string, value interface{}) (present b
 	return
 }
 
+// logRecord is the structured form of log entries, with the fields in the
+// order they're rendered in.
+type logRecord struct {
+	Timestamp string `json:"timestamp"`
+	Region    string `json:"region"`
+	Machine   string `json:"machine,omitempty"`
+	Instance  string `json:"instance"`
+	Level     string `json:"level"`
+	Provider  string `json:"provider,omitempty"`
+	Message   string `json:"message"`
+
+	// Meta is only rendered as JSON
+	Meta logs.Meta `json:"meta"`
+}
+
+func newLogRecord(entry logs.LogEntry, machine bool) logRecord {
+	r := logRecord{
+		Timestamp: entry.Timestamp,
+		Region:    entry.Region,
+		Instance:  entry.Instance,
+		Level:     entry.Level,
+		Provider:  entry.Meta.Event.Provider,
+		Message:   strings.TrimRight(entry.Message, "\n"),
+		Meta:      entry.Meta,
+	}
+
+	// the instances of machine apps are their machines
+	if machine {
+		r.Machine = entry.Instance
+	}
+
+	return r
+}
+
+// LogEntryJSON renders entry as a JSON object on a single line, along with its
+// metadata. With machine, the instance of entry is also rendered as its
+// machine.
+func LogEntryJSON(w io.Writer, entry logs.LogEntry, machine bool) error {
+	return json.NewEncoder(w).Encode(newLogRecord(entry, machine))
+}
+
+// LogEntryLogfmt renders entry as logfmt key=value pairs on a single line.
+// With machine, the instance of entry is also rendered as its machine.
+func LogEntryLogfmt(w io.Writer, entry logs.LogEntry, machine bool) error {
+	r := newLogRecord(entry, machine)
+
+	var buf bytes.Buffer
+
+	pair := func(key, value string, always bool) {
+		if value == "" && !always {
+			return
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+
+		buf.WriteString(key)
+		buf.WriteByte('=')
+
+		if value == "" || strings.ContainsAny(value, " =\"\\") || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			value = strconv.Quote(value)
+		}
+
+		buf.WriteString(value)
+	}
+
+	pair("timestamp", r.Timestamp, true)
+	pair("region", r.Region, true)
+	pair("machine", r.Machine, false)
+	pair("instance", r.Instance, true)
+	pair("level", r.Level, true)
+	pair("provider", r.Provider, false)
+	pair("message", r.Message, true)
+
+	buf.WriteByte('\n')
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
 func levelColor(level string) aurora.Color {
 	switch level {
 	default:
@@ -0,0 +1,97 @@
+// Package schema declares the documents commands output with --json, where
+// they aren't API types.
+//
+// The JSON output of all commands is versioned as a whole by Version. Within
+// a version, documents only ever gain fields: removing or renaming a field, or
+// changing its type or meaning, bumps Version, which fly version --json
+// reports so scripts can check the shapes they rely on. Fields are named by
+// their json tags, or by their Go names when they have none.
+package schema
+
+import (
+	"time"
+
+	"github.com/superfly/flyctl/api"
+)
+
+// Version is the version of the JSON output of commands.
+const Version = 1
+
+// BuildInfo is the output of version.
+type BuildInfo struct {
+	Name              string
+	Version           string
+	Commit            string
+	BuildDate         time.Time
+	OS                string
+	Architecture      string
+	Environment       string
+	JSONSchemaVersion int
+}
+
+// IPAddress is an element of the output of ips list and ips allocate-v4
+// and allocate-v6. Scope is public, or private for addresses only reachable
+// over the private network of the organization.
+type IPAddress struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Type      string    `json:"type"`
+	Scope     string    `json:"scope"`
+	Region    string    `json:"region"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// EgressIPAddress is an element of the output of ips list-egress and ips
+// allocate-egress.
+type EgressIPAddress struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Version   int       `json:"version"`
+	Region    string    `json:"region"`
+	MachineID string    `json:"machine_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RedisDatabase is an element of the output of redis list.
+type RedisDatabase struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Organization  string   `json:"organization"`
+	Plan          string   `json:"plan"`
+	PrimaryRegion string   `json:"primary_region"`
+	ReadRegions   []string `json:"read_regions"`
+}
+
+// RedisPlan is an element of the output of redis plans.
+type RedisPlan struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	MaxDataSize   string `json:"max_data_size"`
+	PricePerMonth int    `json:"price_per_month"`
+}
+
+// PostgresSetting is an element of the output of postgres config view.
+// PendingChange is the value the setting takes once Postgres restarts, if
+// any.
+type PostgresSetting struct {
+	Name           string   `json:"name"`
+	Value          string   `json:"value"`
+	Unit           string   `json:"unit"`
+	Description    string   `json:"description"`
+	Type           string   `json:"type"`
+	Min            string   `json:"min,omitempty"`
+	Max            string   `json:"max,omitempty"`
+	EnumValues     []string `json:"enum_values,omitempty"`
+	PendingChange  string   `json:"pending_change,omitempty"`
+	PendingRestart bool     `json:"pending_restart"`
+}
+
+// MachinesAppStatus is the output of status for apps running on machines.
+// Machines are those of machine list.
+type MachinesAppStatus struct {
+	Name     string         `json:"name"`
+	Owner    string         `json:"owner"`
+	Hostname string         `json:"hostname"`
+	Platform string         `json:"platform"`
+	Machines []*api.Machine `json:"machines"`
+}
@@ -0,0 +1,36 @@
+package render
+
+import (
+	"math"
+	"strings"
+)
+
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders values as a line of block characters scaled between
+// their minimum and maximum. Infinite values are clamped to the highest or
+// lowest block, and NaNs render as the lowest one.
+func Sparkline(values []float64) string {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			continue
+		}
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		switch {
+		case math.IsInf(v, 1):
+			i = len(sparks) - 1
+		case math.IsInf(v, -1), math.IsNaN(v):
+		case high > low:
+			i = int((v - low) / (high - low) * float64(len(sparks)-1))
+		}
+		b.WriteRune(sparks[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package render
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSparkline(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []float64
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"flat", []float64{3, 3, 3}, "▁▁▁"},
+		{"scaled", []float64{0, 7, 14}, "▁▄█"},
+		{"infinite", []float64{0, math.Inf(1), 7, math.Inf(-1)}, "▁██▁"},
+		{"nan", []float64{math.NaN(), 0, 14}, "▁▁█"},
+		{"only non-finite", []float64{math.NaN(), math.Inf(1)}, "▁█"},
+	}
+
+	for _, c := range cases {
+		if got := Sparkline(c.values); got != c.want {
+			t.Errorf("%s: Sparkline(%v) = %q, want %q", c.name, c.values, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/internal/flag"
+)
+
+// templateFuncs are the functions --format templates may call, on top of
+// those text/template provides.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+
+		return string(data), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Template renders v with the Go template text, like docker --format does:
+// each element of v on a line of its own when v is a slice, or v itself
+// otherwise. Templates refer to fields by their Go names, like {{.UpdatedAt}},
+// rather than by the keys --json renders them with.
+func Template(w io.Writer, text string, v interface{}) error {
+	tmpl, err := template.New("format").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed parsing --format: %w", err)
+	}
+
+	items := []interface{}{v}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Slice {
+		items = make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(w, item); err != nil {
+			return fmt.Errorf("failed rendering --format: %w", err)
+		}
+
+		fmt.Fprintln(w)
+	}
+
+	return nil
+}
+
+// Structured renders v for scripts: with the template of the --format flag
+// of the command when it has one set, or as JSON with --json. It reports
+// whether it did so that, otherwise, callers render v for humans.
+//
+// The template sees the same values as --json does, by the names of their Go
+// fields rather than by their JSON keys.
+func Structured(ctx context.Context, w io.Writer, v interface{}) (bool, error) {
+	if f := flag.FromContext(ctx).Lookup(flag.FormatName); f != nil && f.Value.String() != "" {
+		return true, Template(w, f.Value.String(), v)
+	}
+
+	if config.FromContext(ctx).JSONOutput {
+		return true, JSON(w, v)
+	}
+
+	return false, nil
+}
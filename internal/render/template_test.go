@@ -0,0 +1,46 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplate(t *testing.T) {
+	type item struct {
+		ID      string `json:"id"`
+		Regions []string
+	}
+
+	items := []item{{ID: "a1", Regions: []string{"fra", "ams"}}, {ID: "b2"}}
+
+	cases := []struct {
+		text string
+		v    interface{}
+		exp  string
+	}{
+		{"{{.ID}}", items, "a1\nb2\n"},
+		{"{{.ID}} {{join .Regions \",\"}}", items[0], "a1 fra,ams\n"},
+		{"{{upper .ID}} {{json .Regions}}", items[1], "B2 null\n"},
+		{"{{json .}}", items[0], `{"id":"a1","Regions":["fra","ams"]}` + "\n"},
+		{"{{.ID}}", []item{}, ""},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		assert.NoError(t, Template(&buf, c.text, c.v), c.text)
+		assert.Equal(t, c.exp, buf.String(), c.text)
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	assert.ErrorContains(t, Template(&buf, "{{.ID", struct{ ID string }{}), "failed parsing --format")
+
+	// templates see Go field names, not JSON keys
+	assert.ErrorContains(t, Template(&buf, "{{.id}}", struct {
+		ID string `json:"id"`
+	}{}), "failed rendering --format")
+}
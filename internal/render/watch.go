@@ -0,0 +1,111 @@
+package render
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/azazeal/pause"
+	"github.com/inancgumus/screen"
+
+	"github.com/superfly/flyctl/internal/config"
+	"github.com/superfly/flyctl/iostreams"
+)
+
+// Watch calls fn every interval until ctx is done, showing what it renders
+// full-screen in place of what it rendered before. Lines which changed since
+// the previous render are marked in the gutter; relative times like 5s ago,
+// colors and the alignment of columns don't count as changes.
+//
+// Watch backs the --watch flags of read commands which, unlike running them
+// under watch(1), keep their colors and the session of flyctl.
+func Watch(ctx context.Context, interval time.Duration, title string, fn func(context.Context, io.Writer) error) error {
+	streams := iostreams.FromContext(ctx)
+
+	switch {
+	case config.FromContext(ctx).JSONOutput:
+		return errors.New("--watch and --json are not supported together")
+	case !streams.IsInteractive():
+		return errors.New("--watch is not supported for non-interactive sessions")
+	case interval < time.Second:
+		return errors.New("--watch must refresh at most every second")
+	}
+
+	var (
+		colorize = streams.ColorScheme()
+		marker   = colorize.Yellow("▌") + " "
+		buf      bytes.Buffer
+		previous map[string]bool
+	)
+
+	for {
+		buf.Reset()
+
+		if err := fn(ctx, &buf); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		var out bytes.Buffer
+		fmt.Fprintf(&out, "%s at %s, every %s\n\n", colorize.Bold(title),
+			colorize.Bold(time.Now().UTC().Format("15:04:05")), interval)
+
+		previous = markChanges(&out, buf.String(), previous, marker)
+
+		screen.Clear()
+		screen.MoveTopLeft()
+
+		_, _ = io.Copy(streams.Out, &out)
+
+		if pause.For(ctx, interval); ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// markChanges writes the lines of rendered to w, marking those which weren't
+// part of the previous render, and returns the keys of its lines to compare
+// the next render with. Nothing is marked when previous is nil.
+func markChanges(w io.Writer, rendered string, previous map[string]bool, marker string) map[string]bool {
+	lines := strings.Split(strings.TrimRight(rendered, "\n"), "\n")
+	current := make(map[string]bool, len(lines))
+
+	for _, line := range lines {
+		key := watchKey(line)
+		current[key] = true
+
+		gutter := "  "
+		if previous != nil && !previous[key] && key != "" {
+			gutter = marker
+		}
+
+		fmt.Fprintf(w, "%s%s\n", gutter, line)
+	}
+
+	return current
+}
+
+var (
+	ansiEscapes   = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	relativeTimes = regexp.MustCompile(`\b(\d+[hms])+ ago\b|\b(\d+|an?) (second|minute|hour|day|week|month|year)s? ago\b|\bjust now\b`)
+	tableRules    = regexp.MustCompile(`[-─═]{2,}`)
+)
+
+// watchKey returns what's compared of line between renders: its text without
+// colors, relative times or the padding aligning its columns, which change as
+// time passes or other rows change width.
+func watchKey(line string) string {
+	line = ansiEscapes.ReplaceAllString(line, "")
+	line = relativeTimes.ReplaceAllString(line, "")
+	line = tableRules.ReplaceAllString(line, "-")
+
+	return strings.Join(strings.Fields(line), " ")
+}
@@ -0,0 +1,33 @@
+package render
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchKey(t *testing.T) {
+	for line, exp := range map[string]string{
+		"web   started   fra   5s ago":         "web started fra",
+		"web started fra 2m13s ago":            "web started fra",
+		"db    passing   3 minutes ago   ok":   "db passing ok",
+		"\x1b[32mpassing\x1b[0m just now":      "passing",
+		"------   -----------":                 "- -",
+		"   ":                                  "",
+		"machine 4d891 in ams with 5s timeout": "machine 4d891 in ams with 5s timeout",
+	} {
+		assert.Equal(t, exp, watchKey(line), line)
+	}
+}
+
+func TestMarkChanges(t *testing.T) {
+	var buf bytes.Buffer
+
+	previous := markChanges(&buf, "ID   STATE\nabc  started  5s ago\n", nil, "> ")
+	assert.Equal(t, "  ID   STATE\n  abc  started  5s ago\n", buf.String())
+
+	buf.Reset()
+	markChanges(&buf, "ID       STATE\n\nabc      started  6s ago\nabcdefg  stopped  just now\n\n", previous, "> ")
+	assert.Equal(t, "  ID       STATE\n  \n  abc      started  6s ago\n> abcdefg  stopped  just now\n", buf.String())
+}
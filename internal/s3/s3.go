@@ -0,0 +1,332 @@
+// Package s3 implements the subset of the S3 API flyctl needs to list, get and
+// put objects, including large ones in multiple parts, on S3-compatible
+// storage.
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client is an S3 client authenticating with AWS Signature Version 4.
+type Client struct {
+	// Endpoint is the base URL of the service, such as https://s3.amazonaws.com.
+	// Buckets are addressed by path.
+	Endpoint  string
+	Region    string
+	AccessKey string
+	SecretKey string
+
+	// SessionToken is set when authenticating with temporary credentials.
+	SessionToken string
+
+	HTTPClient *http.Client
+}
+
+// Object is an object listed by ListObjects.
+type Object struct {
+	Key          string    `xml:"Key" json:"key"`
+	Size         int64     `xml:"Size" json:"size"`
+	LastModified time.Time `xml:"LastModified" json:"last_modified"`
+	ETag         string    `xml:"ETag" json:"etag"`
+}
+
+// Part is an uploaded part of a multipart upload.
+type Part struct {
+	PartNumber int    `xml:"PartNumber" json:"part_number"`
+	ETag       string `xml:"ETag" json:"etag"`
+}
+
+// Error is an error returned by the service.
+type Error struct {
+	Status  int
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("s3: %s", http.StatusText(e.Status))
+	}
+
+	return fmt.Sprintf("s3: %s: %s", e.Code, e.Message)
+}
+
+// CreateMultipartUpload initiates a multipart upload of the object, returning
+// its upload ID.
+func (c *Client) CreateMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	var result struct {
+		UploadID string `xml:"UploadId"`
+	}
+
+	query := url.Values{"uploads": {""}}
+	if err := c.do(ctx, http.MethodPost, bucket, key, query, nil, nil, &result, nil); err != nil {
+		return "", err
+	}
+
+	return result.UploadID, nil
+}
+
+// UploadPart uploads a part of a multipart upload, returning its ETag. Parts
+// other than the last must be at least 5MiB.
+func (c *Client) UploadPart(ctx context.Context, bucket, key, uploadID string, number int, data []byte) (string, error) {
+	query := url.Values{
+		"partNumber": {strconv.Itoa(number)},
+		"uploadId":   {uploadID},
+	}
+
+	var etag string
+	if err := c.do(ctx, http.MethodPut, bucket, key, query, nil, data, nil, &etag); err != nil {
+		return "", err
+	}
+
+	return etag, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the object.
+func (c *Client) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []Part) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []Part   `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{"uploadId": {uploadID}}
+
+	return c.do(ctx, http.MethodPost, bucket, key, query, nil, body, nil, nil)
+}
+
+// AbortMultipartUpload discards a multipart upload and its uploaded parts.
+func (c *Client) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	query := url.Values{"uploadId": {uploadID}}
+
+	return c.do(ctx, http.MethodDelete, bucket, key, query, nil, nil, nil, nil)
+}
+
+// PutObject uploads data as the object in a single request. The object is
+// stored with the given content type, unless it's empty.
+func (c *Client) PutObject(ctx context.Context, bucket, key string, data []byte, contentType string) error {
+	var header http.Header
+	if contentType != "" {
+		header = http.Header{"Content-Type": {contentType}}
+	}
+
+	return c.do(ctx, http.MethodPut, bucket, key, nil, header, data, nil, nil)
+}
+
+// GetObject returns the content of the object, which callers must close, and
+// its size.
+func (c *Client) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, int64, error) {
+	res, err := c.send(ctx, http.MethodGet, bucket, key, nil, nil, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
+// ListObjects lists the objects of bucket whose keys start with prefix. With
+// a delimiter, keys containing it past the prefix are rolled up into the
+// common prefixes returned instead, like directories.
+func (c *Client) ListObjects(ctx context.Context, bucket, prefix, delimiter string) (objects []Object, prefixes []string, err error) {
+	var token string
+
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
+		if delimiter != "" {
+			query.Set("delimiter", delimiter)
+		}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		var result struct {
+			Contents       []Object `xml:"Contents"`
+			CommonPrefixes []struct {
+				Prefix string `xml:"Prefix"`
+			} `xml:"CommonPrefixes"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+
+		if err = c.do(ctx, http.MethodGet, bucket, "", query, nil, nil, &result, nil); err != nil {
+			return
+		}
+
+		objects = append(objects, result.Contents...)
+		for _, p := range result.CommonPrefixes {
+			prefixes = append(prefixes, p.Prefix)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+func (c *Client) do(ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body []byte, out interface{}, etag *string) error {
+	res, err := c.send(ctx, method, bucket, key, query, header, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if etag != nil {
+		*etag = res.Header.Get("ETag")
+	}
+
+	if out != nil {
+		return xml.NewDecoder(res.Body).Decode(out)
+	}
+
+	_, err = io.Copy(io.Discard, res.Body)
+
+	return err
+}
+
+// send sends a signed request, with the given headers besides those signing
+// it, returning the response unless the service returns an error.
+func (c *Client) send(ctx context.Context, method, bucket, key string, query url.Values, header http.Header, body []byte) (*http.Response, error) {
+	endpoint, err := url.Parse(c.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", c.Endpoint, err)
+	}
+
+	endpoint.Path = "/" + bucket + "/" + key
+	endpoint.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if c.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", c.SessionToken)
+	}
+
+	c.sign(req, body, time.Now().UTC())
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 300 {
+		defer res.Body.Close()
+
+		e := &Error{Status: res.StatusCode}
+		_ = xml.NewDecoder(res.Body).Decode(e)
+		return nil, e
+	}
+
+	return res, nil
+}
+
+// sign signs the request with AWS Signature Version 4.
+func (c *Client) sign(req *http.Request, body []byte, now time.Time) {
+	Sign(req, body, c.Region, "s3", c.AccessKey, c.SecretKey, now)
+}
+
+// Sign signs the request to the given AWS service with Signature Version 4.
+func Sign(req *http.Request, body []byte, region, service, accessKey, secretKey string, now time.Time) {
+	var (
+		date        = now.Format("20060102")
+		timestamp   = now.Format("20060102T150405Z")
+		payloadHash = hashHex(body)
+		scope       = strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	)
+
+	req.Header.Set("X-Amz-Date", timestamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           timestamp,
+	}
+	if token := req.Header.Get("X-Amz-Security-Token"); token != "" {
+		headers["x-amz-security-token"] = token
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		timestamp,
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query sorted by key, escaping spaces as %20 as
+// Signature Version 4 requires.
+func canonicalQuery(query url.Values) string {
+	return strings.ReplaceAll(query.Encode(), "+", "%20")
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,139 @@
+// Package stagedsecrets tracks the secret changes staged with fly secrets
+// stage, which deploys apply. Changes are tracked in a file of the config
+// directory, so they're local to the computer they were staged on.
+package stagedsecrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/superfly/flyctl/internal/filemu"
+	"github.com/superfly/flyctl/internal/state"
+)
+
+// fileName denotes the name of the file, within the config directory, which
+// tracks the secrets that have been staged but not yet applied.
+const fileName = "staged_secrets.yml"
+
+// Change is a staged change to a secret.
+type Change struct {
+	Name     string    `yaml:"name" json:"name"`
+	Unset    bool      `yaml:"unset,omitempty" json:"unset"`
+	StagedAt time.Time `yaml:"staged_at" json:"staged_at"`
+}
+
+// Changes maps app names to their pending changes. Only secret names are
+// tracked; values are held by the API.
+type Changes map[string][]Change
+
+func path(ctx context.Context) string {
+	return filepath.Join(state.ConfigDirectory(ctx), fileName)
+}
+
+// Load returns the staged changes of all apps.
+func Load(ctx context.Context) (changes Changes, err error) {
+	path := path(ctx)
+
+	var unlock filemu.UnlockFunc
+	if unlock, err = filemu.RLock(ctx, path+".lock"); err != nil {
+		return
+	}
+	defer func() {
+		if e := unlock(); err == nil {
+			err = e
+		}
+	}()
+
+	return read(path)
+}
+
+func read(path string) (Changes, error) {
+	changes := Changes{}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return changes, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err := yaml.Unmarshal(data, &changes); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %w", path, err)
+	}
+
+	return changes, nil
+}
+
+// update applies fn to the staged changes while holding an exclusive lock, so
+// that concurrent invocations don't drop each other's changes.
+func update(ctx context.Context, fn func(Changes)) (err error) {
+	path := path(ctx)
+
+	var unlock filemu.UnlockFunc
+	if unlock, err = filemu.Lock(ctx, path+".lock"); err != nil {
+		return
+	}
+	defer func() {
+		if e := unlock(); err == nil {
+			err = e
+		}
+	}()
+
+	changes, err := read(path)
+	if err != nil {
+		return
+	}
+
+	fn(changes)
+
+	var b bytes.Buffer
+	if err = yaml.NewEncoder(&b).Encode(changes); err != nil {
+		return
+	}
+
+	return os.WriteFile(path, b.Bytes(), 0o600)
+}
+
+// Record adds the named secrets to the changes of appName, replacing any
+// earlier change to the same secret.
+func Record(ctx context.Context, appName string, names []string, unset bool) error {
+	now := time.Now()
+
+	return update(ctx, func(changes Changes) {
+		pending := changes[appName]
+
+		for _, name := range names {
+			for i := 0; i < len(pending); i++ {
+				if pending[i].Name == name {
+					pending = append(pending[:i], pending[i+1:]...)
+					i--
+				}
+			}
+
+			pending = append(pending, Change{Name: name, Unset: unset, StagedAt: now})
+		}
+
+		changes[appName] = pending
+	})
+}
+
+// Clear drops the changes of appName, once a deploy has applied them to all
+// of its machines.
+func Clear(ctx context.Context, appName string) error {
+	changes, err := Load(ctx)
+	if err != nil || len(changes[appName]) == 0 {
+		return err
+	}
+
+	return update(ctx, func(changes Changes) {
+		delete(changes, appName)
+	})
+}
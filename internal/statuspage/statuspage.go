@@ -0,0 +1,185 @@
+// Package statuspage reads the status of the Fly platform, and its incidents,
+// from the Statuspage API of status.fly.io.
+package statuspage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/superfly/flyctl/client"
+)
+
+// URL is the address of the status page.
+const URL = "https://status.fly.io/"
+
+const summaryURL = URL + "api/v2/summary.json"
+
+// Summary is the status of the platform, with its unresolved incidents and
+// upcoming or ongoing maintenances.
+type Summary struct {
+	Status struct {
+		Indicator   string `json:"indicator"`
+		Description string `json:"description"`
+	} `json:"status"`
+	Components            []Component `json:"components"`
+	Incidents             []Incident  `json:"incidents"`
+	ScheduledMaintenances []Incident  `json:"scheduled_maintenances"`
+}
+
+// Component is a part of the platform whose status is reported, such as a
+// region.
+type Component struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Group  bool   `json:"group"`
+}
+
+// Operational reports whether c works normally.
+func (c Component) Operational() bool {
+	return c.Status == "operational"
+}
+
+// Region returns the code of the region c is, like ams for a component named
+// "Amsterdam, Netherlands (AMS)" or "AMS - Amsterdam, Netherlands", or an
+// empty string when c isn't one.
+func (c Component) Region() string {
+	var code string
+
+	if open := strings.LastIndex(c.Name, "("); open >= 0 && strings.HasSuffix(c.Name, ")") {
+		code = c.Name[open+1 : len(c.Name)-1]
+	} else if fields := strings.Fields(c.Name); len(fields) > 2 && fields[1] == "-" {
+		code = fields[0]
+	}
+
+	if len(code) != 3 || strings.IndexFunc(code, func(r rune) bool { return !unicode.IsUpper(r) }) >= 0 {
+		return ""
+	}
+
+	return strings.ToLower(code)
+}
+
+// Incident is an incident, or a maintenance.
+type Incident struct {
+	ID         string      `json:"id"`
+	Name       string      `json:"name"`
+	Status     string      `json:"status"`
+	Impact     string      `json:"impact"`
+	Shortlink  string      `json:"shortlink"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	Components []Component `json:"components"`
+	Updates    []Update    `json:"incident_updates"`
+}
+
+// Update is a status update of an incident.
+type Update struct {
+	Status    string    `json:"status"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Latest returns the latest update of i, if it has any.
+func (i Incident) Latest() *Update {
+	var latest *Update
+	for j := range i.Updates {
+		if latest == nil || i.Updates[j].CreatedAt.After(latest.CreatedAt) {
+			latest = &i.Updates[j]
+		}
+	}
+
+	return latest
+}
+
+// Affects reports whether i affects region.
+func (i Incident) Affects(region string) bool {
+	for _, c := range i.Components {
+		if c.Region() == region {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Fetch returns the current status of the platform. It's requested over the
+// HTTP client of the API client ctx carries.
+func Fetch(ctx context.Context) (*Summary, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, summaryURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Accept", "application/json")
+
+	res, err := client.FromContext(ctx).API().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", summaryURL, res.Status)
+	}
+
+	var summary Summary
+	if err := json.NewDecoder(res.Body).Decode(&summary); err != nil {
+		return nil, fmt.Errorf("failed decoding platform status: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// RegionIncidents returns the unresolved incidents affecting any of regions.
+func (s *Summary) RegionIncidents(regions ...string) []Incident {
+	var incidents []Incident
+
+	for _, incident := range s.Incidents {
+		for _, region := range regions {
+			if incident.Affects(region) {
+				incidents = append(incidents, incident)
+				break
+			}
+		}
+	}
+
+	return incidents
+}
+
+// WarnRegions writes a warning to w for each unresolved incident affecting
+// any of regions. It's meant for commands about to act on those regions, so
+// the status is only waited on for a few seconds, and failing to fetch it
+// isn't reported.
+func WarnRegions(ctx context.Context, w io.Writer, regions ...string) {
+	if len(regions) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	summary, err := Fetch(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, incident := range summary.RegionIncidents(regions...) {
+		fmt.Fprintf(w, "WARNING: ongoing platform incident affecting %s: %s (%s)\n",
+			strings.Join(affected(incident, regions), ", "), incident.Name, incident.Shortlink)
+	}
+}
+
+func affected(incident Incident, regions []string) (affected []string) {
+	for _, region := range regions {
+		if incident.Affects(region) {
+			affected = append(affected, region)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,35 @@
+package statuspage
+
+import "testing"
+
+func TestComponentRegion(t *testing.T) {
+	cases := map[string]string{
+		"Amsterdam, Netherlands (AMS)": "ams",
+		"SJC - San Jose, California":   "sjc",
+		"Machines API":                 "",
+		"Dashboard":                    "",
+	}
+
+	for name, want := range cases {
+		if got := (Component{Name: name}).Region(); got != want {
+			t.Errorf("Region() of %q = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestRegionIncidents(t *testing.T) {
+	summary := &Summary{
+		Incidents: []Incident{
+			{Name: "Network issues", Components: []Component{{Name: "Frankfurt, Germany (FRA)"}}},
+			{Name: "Slow API", Components: []Component{{Name: "GraphQL API"}}},
+		},
+	}
+
+	if got := summary.RegionIncidents("ams", "fra"); len(got) != 1 || got[0].Name != "Network issues" {
+		t.Errorf("RegionIncidents() = %+v, want only the Frankfurt incident", got)
+	}
+
+	if got := summary.RegionIncidents("ams"); len(got) != 0 {
+		t.Errorf("RegionIncidents() = %+v, want none", got)
+	}
+}
@@ -305,6 +305,7 @@ func MachinesChecks(ctx context.Context, machines []*api.Machine) error {
 	}
 
 	machineIDs := lo.Map(machines, func(m *api.Machine, _ int) string { return m.ID })
+	parent := ctx
 	ctx, cancel := context.WithTimeout(ctx, 300*time.Second)
 	defer cancel()
 	iteration := 0
@@ -344,7 +345,12 @@ func MachinesChecks(ctx context.Context, machines []*api.Machine) error {
 		return nil
 	}
 
-	return retry.Do(fn, retry.Delay(time.Second), retry.DelayType(retry.FixedDelay), retry.Attempts(0), retry.Context(ctx))
+	err := retry.Do(fn, retry.Delay(time.Second), retry.DelayType(retry.FixedDelay), retry.Attempts(0), retry.Context(ctx))
+	if err != nil && parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return flyerr.WithCode(flyerr.CodeCheckTimeout, fmt.Errorf("timed out waiting for health checks to pass: %w", err))
+	}
+
+	return err
 }
 
 // retryGetMachines calls flaps with exponential backoff 10s max interval and up to 6 times
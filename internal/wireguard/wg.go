@@ -206,3 +206,114 @@ func PruneInvalidPeers(ctx context.Context, apiClient *api.Client) error {
 
 	return setWireGuardState(state)
 }
+
+const teamPeerPrefix = "team-"
+
+// TeamPeerName returns the name of the peer owned by member in the given
+// region, following the naming convention used for team peers.
+func TeamPeerName(member, region string) string {
+	return fmt.Sprintf("%s%s-%s", teamPeerPrefix, teamMemberSlug(member), region)
+}
+
+// teamMemberSlug returns member as it's written in the names of team peers.
+func teamMemberSlug(member string) string {
+	return strings.ToLower(cleanDNSPattern.ReplaceAllString(member, "-"))
+}
+
+// TeamPeer records the member a team peer was created for. The API doesn't
+// track who peers belong to, so team peers are recorded in the config file
+// of the computer they were created on.
+type TeamPeer struct {
+	Name   string `json:"name"`
+	Pubkey string `json:"pubkey"`
+	Member string `json:"member"`
+}
+
+// TeamPeers maps organization slugs to their recorded team peers.
+type TeamPeers map[string][]TeamPeer
+
+// GetTeamPeers returns the team peers recorded for all organizations.
+func GetTeamPeers() (TeamPeers, error) {
+	peers := TeamPeers{}
+
+	if err := viper.UnmarshalKey(flyctl.ConfigWireGuardTeamPeers, &peers); err != nil {
+		return nil, errors.Wrap(err, "invalid wireguard team peers")
+	}
+
+	return peers, nil
+}
+
+func setTeamPeers(peers TeamPeers) error {
+	viper.Set(flyctl.ConfigWireGuardTeamPeers, peers)
+	if err := flyctl.SaveConfig(); err != nil {
+		return errors.Wrap(err, "error saving config file")
+	}
+
+	return nil
+}
+
+// RecordTeamPeer records that the peer of orgSlug named name, with the given
+// public key, was created for member.
+func RecordTeamPeer(orgSlug, name, pubkey, member string) error {
+	peers, err := GetTeamPeers()
+	if err != nil {
+		return err
+	}
+
+	peers[orgSlug] = append(forget(peers[orgSlug], name), TeamPeer{Name: name, Pubkey: pubkey, Member: member})
+
+	return setTeamPeers(peers)
+}
+
+// ForgetTeamPeer drops the record of the team peer of orgSlug named name.
+func ForgetTeamPeer(orgSlug, name string) error {
+	peers, err := GetTeamPeers()
+	if err != nil {
+		return err
+	}
+
+	peers[orgSlug] = forget(peers[orgSlug], name)
+	if len(peers[orgSlug]) == 0 {
+		delete(peers, orgSlug)
+	}
+
+	return setTeamPeers(peers)
+}
+
+func forget(peers []TeamPeer, name string) []TeamPeer {
+	kept := make([]TeamPeer, 0, len(peers))
+	for _, peer := range peers {
+		if peer.Name != name {
+			kept = append(kept, peer)
+		}
+	}
+
+	return kept
+}
+
+// matches reports whether tp is the record of peer.
+func (tp TeamPeer) matches(peer *api.WireGuardPeer) bool {
+	return tp.Name == peer.Name && tp.Pubkey == peer.Pubkey
+}
+
+// TeamPeerOwner returns the member owning peer, or an empty string should
+// peer not be one of the team peers recorded in peers. Peers match by name and
+// public key, so a peer recreated under the same name isn't taken for the
+// recorded one.
+func TeamPeerOwner(peers []TeamPeer, peer *api.WireGuardPeer) string {
+	for _, tp := range peers {
+		if tp.matches(peer) {
+			return tp.Member
+		}
+	}
+
+	return ""
+}
+
+// IsTeamPeerOf reports whether peer is one of the team peers recorded in peers
+// as created for member.
+func IsTeamPeerOf(peers []TeamPeer, peer *api.WireGuardPeer, member string) bool {
+	owner := TeamPeerOwner(peers, peer)
+
+	return owner != "" && strings.EqualFold(owner, member)
+}
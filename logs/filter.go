@@ -0,0 +1,91 @@
+package logs
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Filter narrows log entries down to those matching all of its conditions.
+// The zero value matches every entry.
+type Filter struct {
+	// Instances, when set, are the instances entries must come from.
+	Instances map[string]bool
+	// MinLevel is the lowest level of the entries that match.
+	MinLevel string
+	// Pattern is the regular expression messages must match.
+	Pattern *regexp.Regexp
+	// Since and Until bound the timestamps of the entries that match.
+	Since time.Time
+	Until time.Time
+}
+
+var levels = map[string]int{
+	"trace":   0,
+	"debug":   1,
+	"info":    2,
+	"notice":  2,
+	"warn":    3,
+	"warning": 3,
+	"error":   4,
+	"fatal":   5,
+}
+
+// ValidLevel reports whether level is one Filter knows how to rank.
+func ValidLevel(level string) bool {
+	_, ok := levels[strings.ToLower(level)]
+	return ok
+}
+
+// levelRank ranks level, treating levels it doesn't know as info.
+func levelRank(level string) int {
+	if rank, ok := levels[strings.ToLower(level)]; ok {
+		return rank
+	}
+
+	return levels["info"]
+}
+
+// Match reports whether entry matches f.
+func (f *Filter) Match(entry LogEntry) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Instances != nil && !f.Instances[entry.Instance] {
+		return false
+	}
+
+	if f.MinLevel != "" && levelRank(entry.Level) < levelRank(f.MinLevel) {
+		return false
+	}
+
+	if f.Pattern != nil && !f.Pattern.MatchString(entry.Message) {
+		return false
+	}
+
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		if err != nil {
+			return true
+		}
+
+		if ts.Before(f.Since) || (!f.Until.IsZero() && ts.After(f.Until)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Past reports whether entry was logged after f's Until, meaning no later
+// entries can match.
+func (f *Filter) Past(entry LogEntry) bool {
+	if f == nil || f.Until.IsZero() {
+		return false
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, entry.Timestamp)
+
+	return err == nil && ts.After(f.Until)
+}
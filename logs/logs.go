@@ -14,6 +14,9 @@ type LogOptions struct {
 	AppName    string
 	VMID       string
 	RegionCode string
+
+	// Filter, when set, drops the entries it doesn't match.
+	Filter *Filter
 }
 
 func (opts *LogOptions) toNatsSubject() (subject string) {
@@ -112,7 +112,7 @@ func fromNats(ctx context.Context, out chan<- LogEntry, nc *nats.Conn, opts *Log
 			break
 		}
 
-		out <- LogEntry{
+		entry := LogEntry{
 			Instance:  log.Fly.App.Instance,
 			Level:     log.Log.Level,
 			Message:   log.Message,
@@ -124,6 +124,14 @@ func fromNats(ctx context.Context, out chan<- LogEntry, nc *nats.Conn, opts *Log
 				Event:    struct{ Provider string }{log.Event.Provider},
 			},
 		}
+
+		if opts.Filter.Past(entry) {
+			break
+		}
+
+		if opts.Filter.Match(entry) {
+			out <- entry
+		}
 	}
 
 	return
@@ -70,6 +70,11 @@ func Poll(ctx context.Context, out chan<- LogEntry, client *api.Client, opts *Lo
 
 		errorCount = 0
 		if len(entries) == 0 {
+			// entries logged until then were all read
+			if f := opts.Filter; f != nil && !f.Until.IsZero() && time.Now().After(f.Until) {
+				return nil
+			}
+
 			waitFor = backoff(minWait, maxWait)
 
 			continue
@@ -81,14 +86,22 @@ func Poll(ctx context.Context, out chan<- LogEntry, client *api.Client, opts *Lo
 			nextToken = token
 		}
 
-		for _, entry := range entries {
-			out <- LogEntry{
-				Instance:  entry.Instance,
-				Level:     entry.Level,
-				Message:   entry.Message,
-				Region:    entry.Region,
-				Timestamp: entry.Timestamp,
-				Meta:      entry.Meta,
+		for _, e := range entries {
+			entry := LogEntry{
+				Instance:  e.Instance,
+				Level:     e.Level,
+				Message:   e.Message,
+				Region:    e.Region,
+				Timestamp: e.Timestamp,
+				Meta:      e.Meta,
+			}
+
+			if opts.Filter.Past(entry) {
+				return nil
+			}
+
+			if opts.Filter.Match(entry) {
+				out <- entry
 			}
 		}
 	}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"os"
 	"strconv"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/superfly/flyctl/agent"
 	"github.com/superfly/flyctl/client"
+	"github.com/superfly/flyctl/internal/prompt"
 	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/ip"
 )
@@ -21,6 +24,10 @@ type ConnectParams struct {
 	RemoteHost       string
 	PromptInstance   bool
 	DisableSpinner   bool
+	IdleTimeout      time.Duration
+
+	// Network is either tcp, the default, or udp.
+	Network string
 }
 
 func Connect(ctx context.Context, p *ConnectParams) (err error) {
@@ -74,6 +81,31 @@ func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
 		remoteAddr = fmt.Sprintf("[%s]:%s", p.RemoteHost, remotePort)
 	}
 
+	if p.Network == "udp" {
+		if _, err := strconv.Atoi(localPort); err != nil {
+			return nil, fmt.Errorf("UDP can only be proxied from a local port, not %s", localPort)
+		}
+
+		addr, err := net.ResolveUDPAddr("udp", fmt.Sprintf("127.0.0.1:%s", localPort))
+		if err != nil {
+			return nil, err
+		}
+
+		packetConn, err := net.ListenUDP("udp", addr)
+		if err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(io.Out, "Proxying local UDP port %s to remote %s\n", localPort, remoteAddr)
+
+		return &Server{
+			Addr:        remoteAddr,
+			PacketConn:  packetConn,
+			Dial:        p.Dialer.DialContext,
+			IdleTimeout: p.IdleTimeout,
+		}, nil
+	}
+
 	var listener net.Listener
 
 	if _, err := strconv.Atoi(localPort); err == nil {
@@ -94,18 +126,29 @@ func NewServer(ctx context.Context, p *ConnectParams) (*Server, error) {
 			return nil, err
 		}
 
+		if err := removeStaleSocket(localPort); err != nil {
+			return nil, err
+		}
+
 		listener, err = net.ListenUnix("unix", addr)
 		if err != nil {
 			return nil, err
 		}
+
+		// only the current user gets to use the tunnel through the socket
+		if err := os.Chmod(localPort, 0o600); err != nil {
+			listener.Close()
+			return nil, err
+		}
 	}
 
 	fmt.Fprintf(io.Out, "Proxying local port %s to remote %s\n", localPort, remoteAddr)
 
 	return &Server{
-		Addr:     remoteAddr,
-		Listener: listener,
-		Dial:     p.Dialer.DialContext,
+		Addr:        remoteAddr,
+		Listener:    listener,
+		Dial:        p.Dialer.DialContext,
+		IdleTimeout: p.IdleTimeout,
 	}, nil
 }
 
@@ -115,6 +158,10 @@ func selectInstance(ctx context.Context, org, app string, c *agent.Client) (inst
 		return "", fmt.Errorf("look up %s: %w", app, err)
 	}
 
+	if !iostreams.FromContext(ctx).CanPrompt() {
+		return "", prompt.NonInteractiveError("select flag can't be used when not running interactively")
+	}
+
 	selected := 0
 	prompt := &survey.Select{
 		Message:  "Select instance:",
@@ -128,3 +175,20 @@ func selectInstance(ctx context.Context, org, app string, c *agent.Client) (inst
 
 	return instances.Addresses[selected], nil
 }
+
+// removeStaleSocket removes a socket file left behind at path by a proxy that
+// didn't exit cleanly. Sockets still being listened on and other files are
+// left alone, for listening to fail on.
+func removeStaleSocket(path string) error {
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return nil
+	}
+
+	if conn, err := net.Dial("unix", path); err == nil {
+		conn.Close()
+		return fmt.Errorf("%s is in use by another process", path)
+	}
+
+	return os.Remove(path)
+}
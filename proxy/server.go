@@ -2,12 +2,17 @@ package proxy
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/dustin/go-humanize"
+
+	"github.com/superfly/flyctl/iostreams"
 	"github.com/superfly/flyctl/terminal"
 )
 
@@ -16,17 +21,40 @@ type Server struct {
 	Addr      string
 	Listener  net.Listener
 	Dial      func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// PacketConn, when set instead of Listener, makes the server relay UDP
+	// datagrams rather than TCP connections.
+	PacketConn net.PacketConn
+
+	// IdleTimeout, when set, shuts the server down once no data has been
+	// transferred for that long.
+	IdleTimeout time.Duration
+
+	bytesSent     int64
+	bytesReceived int64
+	lastActivity  int64
 }
 
 func (srv *Server) ProxyServer(ctx context.Context) error {
+	if srv.PacketConn != nil {
+		return srv.proxyPackets(ctx)
+	}
+
 	defer srv.Listener.Close()
 
+	srv.touch()
+
 	for {
 		select {
 
 		case <-ctx.Done():
 			return nil
 		default:
+			if srv.idle() {
+				srv.printIdleSummary(ctx)
+				return nil
+			}
+
 			if ls, ok := srv.Listener.(*net.TCPListener); ok {
 				if err := ls.SetDeadline(time.Now().Add(time.Second)); err != nil {
 					return err
@@ -60,9 +88,9 @@ func (srv *Server) ProxyServer(ctx context.Context) error {
 
 				wg.Add(2)
 
-				copyFunc := func(dst net.Conn, src net.Conn) {
+				copyFunc := func(dst net.Conn, src net.Conn, counter *int64) {
 					defer wg.Done()
-					io.Copy(dst, src)
+					io.Copy(&activityWriter{w: dst, srv: srv, counter: counter}, src)
 
 					// close the write half if it exports a CloseWrite() method
 					if conn, ok := dst.(ClosableWrite); ok {
@@ -70,8 +98,8 @@ func (srv *Server) ProxyServer(ctx context.Context) error {
 					}
 				}
 
-				go copyFunc(target, source)
-				go copyFunc(source, target)
+				go copyFunc(target, source, &srv.bytesSent)
+				go copyFunc(source, target, &srv.bytesReceived)
 
 				wg.Wait()
 
@@ -81,6 +109,152 @@ func (srv *Server) ProxyServer(ctx context.Context) error {
 	}
 }
 
+// udpSessionTimeout is how long the remote end of a UDP client is kept open
+// without receiving datagrams.
+const udpSessionTimeout = 2 * time.Minute
+
+// proxyPackets relays the datagrams of each local client over a connection of
+// its own to the remote address, and relays replies back to the client.
+func (srv *Server) proxyPackets(ctx context.Context) error {
+	defer srv.PacketConn.Close()
+
+	srv.touch()
+
+	var (
+		mu      sync.Mutex
+		targets = map[string]net.Conn{}
+	)
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		for _, target := range targets {
+			target.Close()
+		}
+	}()
+
+	buf := make([]byte, 0xffff)
+
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if srv.idle() {
+			srv.printIdleSummary(ctx)
+			return nil
+		}
+
+		if err := srv.PacketConn.SetReadDeadline(time.Now().Add(time.Second)); err != nil {
+			return err
+		}
+
+		n, source, err := srv.PacketConn.ReadFrom(buf)
+		if err != nil {
+			if os.IsTimeout(err) {
+				continue
+			}
+			return err
+		}
+
+		mu.Lock()
+		target, ok := targets[source.String()]
+		mu.Unlock()
+
+		if !ok {
+			if target, err = srv.Dial(ctx, "udp", srv.Addr); err != nil {
+				terminal.Debug("failed to connect to target: ", err)
+				continue
+			}
+
+			terminal.Debug("new UDP client: ", source)
+
+			mu.Lock()
+			targets[source.String()] = target
+			mu.Unlock()
+
+			go func(target net.Conn, source net.Addr) {
+				defer func() {
+					mu.Lock()
+					delete(targets, source.String())
+					mu.Unlock()
+
+					target.Close()
+				}()
+
+				buf := make([]byte, 0xffff)
+				for {
+					if err := target.SetReadDeadline(time.Now().Add(udpSessionTimeout)); err != nil {
+						return
+					}
+
+					n, err := target.Read(buf)
+					if err != nil {
+						return
+					}
+
+					if _, err := srv.PacketConn.WriteTo(buf[:n], source); err != nil {
+						return
+					}
+
+					atomic.AddInt64(&srv.bytesReceived, int64(n))
+					srv.touch()
+				}
+			}(target, source)
+		}
+
+		if _, err := target.Write(buf[:n]); err != nil {
+			terminal.Debug("failed relaying datagram: ", err)
+			continue
+		}
+
+		atomic.AddInt64(&srv.bytesSent, int64(n))
+		srv.touch()
+	}
+}
+
 type ClosableWrite interface {
 	CloseWrite() error
 }
+
+// touch records that data has just been transferred.
+func (srv *Server) touch() {
+	atomic.StoreInt64(&srv.lastActivity, time.Now().UnixNano())
+}
+
+func (srv *Server) idle() bool {
+	if srv.IdleTimeout <= 0 {
+		return false
+	}
+
+	last := time.Unix(0, atomic.LoadInt64(&srv.lastActivity))
+
+	return time.Since(last) > srv.IdleTimeout
+}
+
+func (srv *Server) printIdleSummary(ctx context.Context) {
+	io := iostreams.FromContext(ctx)
+
+	fmt.Fprintf(io.ErrOut, "Closing proxy after %s of inactivity. Sent %s, received %s\n",
+		srv.IdleTimeout,
+		humanize.Bytes(uint64(atomic.LoadInt64(&srv.bytesSent))),
+		humanize.Bytes(uint64(atomic.LoadInt64(&srv.bytesReceived))),
+	)
+}
+
+// activityWriter counts the bytes written through it and keeps the server
+// from going idle.
+type activityWriter struct {
+	w       io.Writer
+	srv     *Server
+	counter *int64
+}
+
+func (aw *activityWriter) Write(p []byte) (int, error) {
+	n, err := aw.w.Write(p)
+
+	atomic.AddInt64(aw.counter, int64(n))
+	aw.srv.touch()
+
+	return n, err
+}
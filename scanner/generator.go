@@ -0,0 +1,84 @@
+package scanner
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DockerfileOption is a setting of a generated Dockerfile which the user may
+// customize. Options with choices are picked from a list.
+type DockerfileOption struct {
+	Name    string
+	Prompt  string
+	Default string
+	Choices []string
+}
+
+// DockerfileGenerator generates a Dockerfile and .dockerignore for a runtime
+// which would otherwise be built with buildpacks.
+type DockerfileGenerator struct {
+	Template string
+	Options  []DockerfileOption
+}
+
+// Defaults returns the default value of each option.
+func (g *DockerfileGenerator) Defaults() map[string]string {
+	values := make(map[string]string, len(g.Options))
+	for _, opt := range g.Options {
+		values[opt.Name] = opt.Default
+	}
+
+	return values
+}
+
+// Generate renders the Dockerfile and .dockerignore with the given option
+// values, falling back to defaults for those missing.
+func (g *DockerfileGenerator) Generate(values map[string]string) []SourceFile {
+	vars := make(map[string]interface{}, len(g.Options))
+	for name, value := range g.Defaults() {
+		vars[name] = value
+	}
+	for name, value := range values {
+		vars[name] = value
+	}
+
+	return templatesExecute(g.Template, vars)
+}
+
+// goVersion returns the go directive of the module's go.mod.
+func goVersion(sourceDir string) string {
+	file, err := os.Open(filepath.Join(sourceDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	re := regexp.MustCompile(`^go\s+(\d+\.\d+)`)
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if m := re.FindStringSubmatch(scanner.Text()); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
+
+// versionFile returns the contents of a version file such as .ruby-version,
+// less the given prefix, or def if there's none.
+func versionFile(sourceDir, name, prefix, def string) string {
+	data, err := os.ReadFile(filepath.Join(sourceDir, name))
+	if err != nil {
+		return def
+	}
+
+	if v := strings.TrimPrefix(strings.TrimSpace(string(data)), prefix); v != "" {
+		return v
+	}
+
+	return def
+}
@@ -15,5 +15,18 @@ func configureGo(sourceDir string) (*SourceInfo, error) {
 		},
 	}
 
+	version := goVersion(sourceDir)
+	if version == "" {
+		version = "1.19"
+	}
+
+	s.DockerfileGenerator = &DockerfileGenerator{
+		Template: "templates/generated/go",
+		Options: []DockerfileOption{
+			{Name: "version", Prompt: "Go version:", Default: version},
+			{Name: "build", Prompt: "Build command:", Default: `CGO_ENABLED=0 go build -ldflags="-s -w" -o /app/server .`},
+		},
+	}
+
 	return s, nil
 }
@@ -34,8 +34,10 @@ func configureNode(sourceDir string) (*SourceInfo, error) {
 	scripts, ok := result["scripts"].(map[string]interface{})
 
 	if !ok || scripts["start"] == nil {
-		// cowardly fall back to heroku buildpacks
+		// cowardly fall back to heroku buildpacks, unless a Dockerfile is
+		// generated instead
 		s.Builder = "heroku/buildpacks:20"
+		s.DockerfileGenerator = nodeDockerfileGenerator(sourceDir, result, scripts)
 		return s, nil
 	}
 
@@ -91,3 +93,35 @@ Now: run 'fly deploy' to deploy your Rails app.
 
 	return s, nil
 }
+
+func nodeDockerfileGenerator(sourceDir string, pkg, scripts map[string]interface{}) *DockerfileGenerator {
+	version := "lts"
+	if out, err := exec.Command("node", "-v").Output(); err == nil {
+		version = strings.TrimPrefix(strings.TrimSpace(string(out)), "v")
+	}
+
+	packager := "npm"
+	if checksPass(sourceDir, fileExists("yarn.lock")) {
+		packager = "yarn"
+	}
+
+	var build string
+	if scripts["build"] != nil {
+		build = packager + " run build"
+	}
+
+	main, ok := pkg["main"].(string)
+	if !ok || main == "" {
+		main = "index.js"
+	}
+
+	return &DockerfileGenerator{
+		Template: "templates/generated/node",
+		Options: []DockerfileOption{
+			{Name: "version", Prompt: "Node version:", Default: version},
+			{Name: "packager", Prompt: "Package manager:", Default: packager, Choices: []string{"npm", "yarn"}},
+			{Name: "build", Prompt: "Build command (optional):", Default: build},
+			{Name: "start", Prompt: "Start command:", Default: "node " + main},
+		},
+	}
+}
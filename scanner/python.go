@@ -17,5 +17,23 @@ func configurePython(sourceDir string) (*SourceInfo, error) {
 		DeployDocs: `We have generated a simple Procfile for you. Modify it to fit your needs and run "fly deploy" to deploy your application.`,
 	}
 
+	packager := "pip"
+	switch {
+	case checksPass(sourceDir, fileExists("poetry.lock")):
+		packager = "poetry"
+	case checksPass(sourceDir, fileExists("Pipfile")):
+		packager = "pipenv"
+	}
+
+	s.DockerfileGenerator = &DockerfileGenerator{
+		Template: "templates/generated/python",
+		Options: []DockerfileOption{
+			{Name: "version", Prompt: "Python version:", Default: versionFile(sourceDir, "runtime.txt", "python-", "3.10")},
+			{Name: "packager", Prompt: "Package manager:", Default: packager, Choices: []string{"pip", "pipenv", "poetry"}},
+			{Name: "build", Prompt: "Build command (optional):"},
+			{Name: "start", Prompt: "Start command:", Default: "gunicorn server:app --bind 0.0.0.0:8080"},
+		},
+	}
+
 	return s, nil
 }
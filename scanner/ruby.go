@@ -14,5 +14,14 @@ func configureRuby(sourceDir string) (*SourceInfo, error) {
 		},
 	}
 
+	s.DockerfileGenerator = &DockerfileGenerator{
+		Template: "templates/generated/ruby",
+		Options: []DockerfileOption{
+			{Name: "version", Prompt: "Ruby version:", Default: versionFile(sourceDir, ".ruby-version", "ruby-", "3.1")},
+			{Name: "build", Prompt: "Build command (optional):"},
+			{Name: "start", Prompt: "Start command:", Default: "bundle exec rackup --host 0.0.0.0 --port 8080"},
+		},
+	}
+
 	return s, nil
 }
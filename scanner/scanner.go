@@ -53,6 +53,7 @@ type SourceInfo struct {
 	InitCommands                 []InitCommand
 	PostgresInitCommands         []InitCommand
 	PostgresInitCommandCondition bool
+	DockerfileGenerator          *DockerfileGenerator
 }
 
 type SourceFile struct {
@@ -0,0 +1,180 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Forward describes a port forwarded over an SSH connection: connections to
+// Listen are made to Target from the other end.
+type Forward struct {
+	Listen string
+	Target string
+}
+
+func (f Forward) String() string {
+	return f.Listen + " -> " + f.Target
+}
+
+// ParseForward parses forwards specified like OpenSSH's -L and -R, as
+// [bind_address:]port:host:hostport. IPv6 addresses are written in brackets,
+// and the bind address defaults to localhost.
+func ParseForward(spec string) (f Forward, err error) {
+	var fields []string
+	for rest := spec; rest != ""; {
+		var field string
+
+		if rest[0] == '[' {
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return f, fmt.Errorf("invalid forward %q: unclosed bracket", spec)
+			}
+
+			field, rest = rest[1:end], rest[end+1:]
+			if rest != "" && rest[0] != ':' {
+				return f, fmt.Errorf("invalid forward %q", spec)
+			}
+		} else if i := strings.IndexByte(rest, ':'); i >= 0 {
+			field, rest = rest[:i], rest[i:]
+		} else {
+			field, rest = rest, ""
+		}
+
+		fields = append(fields, field)
+		rest = strings.TrimPrefix(rest, ":")
+	}
+
+	switch len(fields) {
+	case 3:
+		fields = append([]string{"localhost"}, fields...)
+	case 4:
+	default:
+		return f, fmt.Errorf("invalid forward %q: expected [bind_address:]port:host:hostport", spec)
+	}
+
+	for _, i := range []int{1, 3} {
+		if port, err := strconv.Atoi(fields[i]); err != nil || port < 0 || port > 65535 {
+			return f, fmt.Errorf("invalid forward %q: bad port %q", spec, fields[i])
+		}
+	}
+
+	f.Listen = net.JoinHostPort(fields[0], fields[1])
+	f.Target = net.JoinHostPort(fields[2], fields[3])
+
+	return f, nil
+}
+
+// ForwardLocal listens on the local end of f and forwards the connections it
+// accepts to the target of f through the VM, until the returned listener is
+// closed.
+func (c *Client) ForwardLocal(f Forward) (io.Closer, error) {
+	l, err := net.Listen("tcp", f.Listen)
+	if err != nil {
+		return nil, err
+	}
+
+	go serveForward(l, func() (net.Conn, error) {
+		return c.Client.Dial("tcp", f.Target)
+	})
+
+	return l, nil
+}
+
+// ForwardRemote listens on the VM's end of f and forwards the connections it
+// accepts to the target of f from this machine, until the returned listener
+// is closed.
+func (c *Client) ForwardRemote(f Forward) (io.Closer, error) {
+	l, err := c.Client.Listen("tcp", f.Listen)
+	if err != nil {
+		return nil, err
+	}
+
+	go serveForward(l, func() (net.Conn, error) {
+		return net.Dial("tcp", f.Target)
+	})
+
+	return l, nil
+}
+
+func serveForward(l net.Listener, dial func() (net.Conn, error)) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+
+			target, err := dial()
+			if err != nil {
+				return
+			}
+			defer target.Close()
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(target, conn)
+				target.Close()
+			}()
+
+			go func() {
+				defer wg.Done()
+				_, _ = io.Copy(conn, target)
+				conn.Close()
+			}()
+
+			wg.Wait()
+		}()
+	}
+}
+
+// KeepAlive sends a keep-alive request to the server every interval until ctx
+// is done, closing the connection when 3 requests in a row go unanswered.
+func (c *Client) KeepAlive(ctx context.Context, interval time.Duration) {
+	const maxMissed = 3
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	missed := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		replied := make(chan error, 1)
+		go func() {
+			_, _, err := c.Client.SendRequest("keepalive@openssh.com", true, nil)
+			replied <- err
+		}()
+
+		select {
+		case <-ctx.Done():
+			return
+		case err := <-replied:
+			if err != nil {
+				return
+			}
+
+			missed = 0
+		case <-time.After(interval):
+			if missed++; missed >= maxMissed {
+				c.Close()
+				return
+			}
+		}
+	}
+}
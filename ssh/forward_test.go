@@ -0,0 +1,33 @@
+package ssh
+
+import "testing"
+
+func TestParseForward(t *testing.T) {
+	cases := []struct {
+		spec string
+		want Forward
+		err  bool
+	}{
+		{spec: "8080:localhost:80", want: Forward{Listen: "localhost:8080", Target: "localhost:80"}},
+		{spec: "0.0.0.0:8080:localhost:80", want: Forward{Listen: "0.0.0.0:8080", Target: "localhost:80"}},
+		{spec: "5432:[fdaa::3]:5432", want: Forward{Listen: "localhost:5432", Target: "[fdaa::3]:5432"}},
+		{spec: "[::1]:8080:localhost:80", want: Forward{Listen: "[::1]:8080", Target: "localhost:80"}},
+		{spec: "8080:80", err: true},
+		{spec: "8080:localhost:http", err: true},
+		{spec: "8080:[::1:80", err: true},
+		{spec: "a:b:c:d:e", err: true},
+	}
+
+	for _, c := range cases {
+		got, err := ParseForward(c.spec)
+
+		switch {
+		case c.err && err == nil:
+			t.Errorf("ParseForward(%q) = %v, want error", c.spec, got)
+		case !c.err && err != nil:
+			t.Errorf("ParseForward(%q) failed: %v", c.spec, err)
+		case got != c.want:
+			t.Errorf("ParseForward(%q) = %v, want %v", c.spec, got, c.want)
+		}
+	}
+}
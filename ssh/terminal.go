@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"runtime"
+	"sync"
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/term"
@@ -52,7 +53,8 @@ func getFd(reader io.Reader) (fd int, ok bool) {
 
 func (t *Terminal) attach(ctx context.Context, sess *ssh.Session, cmd string) error {
 	width, height := DefaultWidth, DefaultHeight
-	if fd, ok := getFd(t.Stdin); ok {
+	fd, isTerminal := getFd(t.Stdin)
+	if isTerminal {
 		state, err := term.MakeRaw(fd)
 		if err != nil {
 			return err
@@ -72,15 +74,18 @@ func (t *Terminal) attach(ctx context.Context, sess *ssh.Session, cmd string) er
 		}
 	}
 
-	if err := sess.RequestPty(t.Mode, height, width, modes); err != nil {
-		return err
+	// commands fed from a pipe or file run without a pty, so their input
+	// isn't echoed or mangled and their stdout and stderr stay apart
+	if isTerminal || cmd == "" {
+		if err := sess.RequestPty(t.Mode, height, width, modes); err != nil {
+			return err
+		}
 	}
 
 	stdin, err := sess.StdinPipe()
 	if err != nil {
 		return err
 	}
-	defer stdin.Close()
 
 	stdout, err := sess.StdoutPipe()
 	if err != nil {
@@ -92,15 +97,37 @@ func (t *Terminal) attach(ctx context.Context, sess *ssh.Session, cmd string) er
 		return err
 	}
 
-	go io.Copy(stdin, t.Stdin)
-	go io.Copy(t.Stdout, stdout)
-	go io.Copy(t.Stderr, stderr)
+	// the remote end sees EOF once local input runs out, so commands
+	// reading all of it terminate
+	go func() {
+		_, _ = io.Copy(stdin, t.Stdin)
+		_ = stdin.Close()
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(t.Stdout, stdout)
+	}()
+
+	go func() {
+		defer wg.Done()
+		_, _ = io.Copy(t.Stderr, stderr)
+	}()
 
 	if cmd == "" {
-		err = sess.Shell()
+		if err = sess.Shell(); err == nil {
+			err = sess.Wait()
+		}
 	} else {
 		err = sess.Run(cmd)
 	}
+
+	// flush the output the session left before returning
+	wg.Wait()
+
 	if err != nil && err != io.EOF {
 		return err
 	}
@@ -4,10 +4,14 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net"
 	"net/netip"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/miekg/dns"
 	"golang.zx2c4.com/wireguard/conn"
@@ -130,6 +134,58 @@ func (t *Tunnel) DialContext(ctx context.Context, network, addr string) (net.Con
 	return t.net.DialContext(ctx, network, addr)
 }
 
+// TunnelStats are the statistics of the peer of a tunnel.
+type TunnelStats struct {
+	Endpoint      string
+	LastHandshake time.Time
+	RxBytes       uint64
+	TxBytes       uint64
+}
+
+// Stats returns the statistics WireGuard keeps of the tunnel's peer.
+func (t *Tunnel) Stats() (*TunnelStats, error) {
+	if t.dev == nil {
+		return nil, errors.New("tunnel is closed")
+	}
+
+	ipc, err := t.dev.IpcGet()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		stats     TunnelStats
+		sec, nsec int64
+	)
+
+	for _, line := range strings.Split(ipc, "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "endpoint":
+			stats.Endpoint = value
+		case "last_handshake_time_sec":
+			sec, _ = strconv.ParseInt(value, 10, 64)
+		case "last_handshake_time_nsec":
+			nsec, _ = strconv.ParseInt(value, 10, 64)
+		case "rx_bytes":
+			stats.RxBytes, _ = strconv.ParseUint(value, 10, 64)
+		case "tx_bytes":
+			stats.TxBytes, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+
+	// a zero time means there hasn't been a handshake yet
+	if sec != 0 || nsec != 0 {
+		stats.LastHandshake = time.Unix(sec, nsec)
+	}
+
+	return &stats, nil
+}
+
 func (t *Tunnel) Resolver() *net.Resolver {
 	return t.resolv
 }